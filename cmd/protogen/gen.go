@@ -0,0 +1,649 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// primitives maps Kafka primitive type names to Go types
+var primitives = map[string]string{
+	"bool":    "bool",
+	"int8":    "int8",
+	"int16":   "int16",
+	"int32":   "int32",
+	"int64":   "int64",
+	"uint16":  "uint16",
+	"uint32":  "uint32",
+	"float64": "float64",
+	"string":  "string",
+	"bytes":   "[]byte",
+	"records": "[]byte",
+	"uuid":    "UUID",
+}
+
+// fieldType is the resolved type of a field
+type fieldType struct {
+	kind string // a primitive name, "array" or "struct"
+	elem *fieldType
+	name string // Go type name when kind is "struct"
+}
+
+// structDef is a Go struct emitted for a message or one of its nested structs
+type structDef struct {
+	name     string
+	doc      string
+	fields   []*fieldSpec
+	versions versionRange // versions in which the struct can appear
+}
+
+// generator emits the Go source for a single message spec
+type generator struct {
+	spec    *messageSpec
+	valid   versionRange
+	flex    versionRange
+	commons map[string]*fieldSpec
+	structs []*structDef
+	seen    map[string]bool
+	// needsIsDefault holds the structs used as non-nullable tagged fields,
+	// which are only written when they differ from their defaults
+	needsIsDefault map[string]bool
+	b              bytes.Buffer
+}
+
+// generateMessage returns the formatted Go source for spec
+func generateMessage(spec *messageSpec, pkg, source string) ([]byte, error) {
+	valid, err := parseVersions(spec.ValidVersions)
+	if err != nil {
+		return nil, err
+	}
+	flex, err := parseVersions(spec.FlexibleVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		spec:           spec,
+		valid:          valid,
+		flex:           flex,
+		commons:        make(map[string]*fieldSpec),
+		seen:           make(map[string]bool),
+		needsIsDefault: make(map[string]bool),
+	}
+	for _, c := range spec.CommonStructs {
+		g.commons[c.Name] = c
+	}
+
+	versions := fmt.Sprintf("versions %d-%d", valid.lo, valid.hi)
+	if valid.lo == valid.hi {
+		versions = fmt.Sprintf("version %d", valid.lo)
+	}
+	root := &structDef{name: spec.Name, fields: spec.Fields, versions: valid}
+	switch {
+	case spec.APIKey != nil && spec.Type == "metadata":
+		root.doc = fmt.Sprintf("%s is the metadata record of type %d, %s.", spec.Name, *spec.APIKey, versions)
+	case spec.APIKey != nil && strings.HasPrefix(spec.Type, "coordinator-"):
+		root.doc = fmt.Sprintf("%s is the %s of coordinator record type %d, %s.", spec.Name,
+			strings.TrimPrefix(spec.Type, "coordinator-"), *spec.APIKey, versions)
+	case spec.APIKey != nil:
+		root.doc = fmt.Sprintf("%s is the %s for API key %d, %s.", spec.Name, spec.Type, *spec.APIKey, versions)
+	default:
+		root.doc = fmt.Sprintf("%s is a %s, %s.", spec.Name, spec.Type, versions)
+	}
+	g.seen[root.name] = true
+	g.structs = append(g.structs, root)
+
+	// Resolving field types registers nested structs as a side effect, so
+	// walk the struct list while it grows.
+	for i := 0; i < len(g.structs); i++ {
+		for _, f := range g.structs[i].fields {
+			if _, err := g.resolve(g.structs[i], f); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	fmt.Fprintf(&g.b, "// Code generated by protogen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&g.b, "package %s\n\n", pkg)
+
+	for _, s := range g.structs {
+		if err := g.writeStruct(s); err != nil {
+			return nil, err
+		}
+		if s == root {
+			g.writeMessageMethods(root)
+		}
+		if err := g.writeMethods(s); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := format.Source(g.b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, g.b.String())
+	}
+	return src, nil
+}
+
+// structName returns the Go name of a nested struct type, prefixed with
+// the message name so that structs from different messages never collide
+func (g *generator) structName(typeName string) string {
+	if strings.HasPrefix(typeName, g.spec.Name) {
+		return typeName
+	}
+	return g.spec.Name + typeName
+}
+
+// resolve determines the type of f, registering any nested struct it declares
+func (g *generator) resolve(parent *structDef, f *fieldSpec) (*fieldType, error) {
+	typ := f.Type
+	array := strings.HasPrefix(typ, "[]")
+	typ = strings.TrimPrefix(typ, "[]")
+
+	var t *fieldType
+	if _, ok := primitives[typ]; ok {
+		t = &fieldType{kind: typ}
+	} else {
+		t = &fieldType{kind: "struct", name: g.structName(typ)}
+		if !g.seen[t.name] {
+			versions, err := parseVersions(f.Versions)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			fields := f.Fields
+			if fields == nil {
+				// Common structs may be shared by fields with different
+				// version ranges, so their conditions use the full range.
+				common, ok := g.commons[typ]
+				if !ok {
+					return nil, fmt.Errorf("field %s: unknown type %q", f.Name, f.Type)
+				}
+				fields = common.Fields
+				versions = g.valid
+			}
+			doc := fmt.Sprintf("%s is the type of %s.%s.", t.name, parent.name, f.Name)
+			if array {
+				doc = fmt.Sprintf("%s is an element of %s.%s.", t.name, parent.name, f.Name)
+			}
+			g.seen[t.name] = true
+			g.structs = append(g.structs, &structDef{
+				name:     t.name,
+				doc:      doc,
+				fields:   fields,
+				versions: versions.intersect(parent.versions),
+			})
+		}
+	}
+
+	if array {
+		return &fieldType{kind: "array", elem: t}, nil
+	}
+	return t, nil
+}
+
+// goType returns the Go type used to hold a field
+func (g *generator) goType(f *fieldSpec, t *fieldType) string {
+	nullable := f.NullableVersions != "" && f.NullableVersions != "none"
+	switch t.kind {
+	case "array":
+		return "[]" + g.goType(&fieldSpec{}, t.elem)
+	case "struct":
+		if nullable {
+			return "*" + t.name
+		}
+		return t.name
+	case "string":
+		if nullable {
+			return "*string"
+		}
+		return "string"
+	default:
+		return primitives[t.kind]
+	}
+}
+
+// writeStruct emits the type declaration for s
+func (g *generator) writeStruct(s *structDef) error {
+	fmt.Fprintf(&g.b, "// %s\n", s.doc)
+	fmt.Fprintf(&g.b, "type %s struct {\n", s.name)
+	for _, f := range s.fields {
+		t, err := g.resolve(s, f)
+		if err != nil {
+			return err
+		}
+		if f.About != "" {
+			fmt.Fprintf(&g.b, "\t// %s\n", f.About)
+		}
+		fmt.Fprintf(&g.b, "\t%s %s\n", f.Name, g.goType(f, t))
+	}
+	if !g.flex.empty() {
+		fmt.Fprintf(&g.b, "\t// Tagged fields not defined by the spec, preserved as raw bytes.\n")
+		fmt.Fprintf(&g.b, "\tUnknownTaggedFields []TaggedField\n")
+	}
+	fmt.Fprintf(&g.b, "}\n\n")
+	return nil
+}
+
+// writeMessageMethods emits the exported API of the top-level message
+func (g *generator) writeMessageMethods(s *structDef) {
+	name := s.name
+	if g.spec.APIKey != nil {
+		fmt.Fprintf(&g.b, "// APIKey returns the API key of %s\n", name)
+		fmt.Fprintf(&g.b, "func (*%s) APIKey() int16 { return %d }\n\n", name, *g.spec.APIKey)
+	}
+	fmt.Fprintf(&g.b, "// MinVersion returns the lowest supported version of %s\n", name)
+	fmt.Fprintf(&g.b, "func (*%s) MinVersion() int16 { return %d }\n\n", name, g.valid.lo)
+	fmt.Fprintf(&g.b, "// MaxVersion returns the highest supported version of %s\n", name)
+	fmt.Fprintf(&g.b, "func (*%s) MaxVersion() int16 { return %d }\n\n", name, g.valid.hi)
+	fmt.Fprintf(&g.b, "// IsFlexible reports whether the given version of %s uses the flexible encoding\n", name)
+	fmt.Fprintf(&g.b, "func (*%s) IsFlexible(version int16) bool { return %s }\n\n", name, g.flex.cond(g.valid))
+
+	fmt.Fprintf(&g.b, "// Encode writes %s in the given version\n", name)
+	fmt.Fprintf(&g.b, "func (m *%s) Encode(e *Encoder, version int16) {\n", name)
+	fmt.Fprintf(&g.b, "\tm.encode(e, version, m.IsFlexible(version))\n}\n\n")
+
+	fmt.Fprintf(&g.b, "// Decode reads %s in the given version\n", name)
+	fmt.Fprintf(&g.b, "func (m *%s) Decode(d *Decoder, version int16) error {\n", name)
+	fmt.Fprintf(&g.b, "\tm.decode(d, version, m.IsFlexible(version))\n\treturn d.Err()\n}\n\n")
+}
+
+// fieldInfo holds the version conditions derived for a field
+type fieldInfo struct {
+	f        *fieldSpec
+	t        *fieldType
+	present  string // versions in which the field is in the fixed section
+	tagged   string // versions in which the field is a tagged field
+	nullable string // versions in which the field may be null
+	flexible string // expression selecting the compact encoding
+}
+
+// info derives the version conditions of f
+func (g *generator) info(s *structDef, f *fieldSpec) (*fieldInfo, error) {
+	t, err := g.resolve(s, f)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := parseVersions(f.Versions)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", f.Name, err)
+	}
+	nullable, err := parseVersions(f.NullableVersions)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", f.Name, err)
+	}
+
+	fi := &fieldInfo{f: f, t: t, nullable: nullable.cond(versions.intersect(s.versions)), flexible: "flexible", tagged: "false"}
+
+	fixed := versions
+	if f.Tag != nil {
+		tagged, err := parseVersions(f.TaggedVersions)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		fi.tagged = versions.intersect(tagged).cond(s.versions.intersect(g.flex))
+		if !tagged.empty() && tagged.lo > versions.lo {
+			fixed = versions.intersect(versionRange{lo: versions.lo, hi: tagged.lo - 1})
+		} else {
+			fixed = noVersions
+		}
+	}
+	fi.present = fixed.cond(s.versions)
+
+	if f.FlexibleVersions != "" {
+		fv, err := parseVersions(f.FlexibleVersions)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		switch c := fv.cond(s.versions); c {
+		case "false":
+			fi.flexible = "false"
+		case "true":
+		default:
+			fi.flexible = "flexible && " + c
+		}
+	}
+	return fi, nil
+}
+
+// writeMethods emits Default, encode and decode for s
+func (g *generator) writeMethods(s *structDef) error {
+	infos := make([]*fieldInfo, 0, len(s.fields))
+	for _, f := range s.fields {
+		fi, err := g.info(s, f)
+		if err != nil {
+			return err
+		}
+		infos = append(infos, fi)
+	}
+
+	var tagged []*fieldInfo
+	for _, fi := range infos {
+		if fi.tagged != "false" {
+			tagged = append(tagged, fi)
+		}
+	}
+	sort.Slice(tagged, func(i, j int) bool { return *tagged[i].f.Tag < *tagged[j].f.Tag })
+
+	if err := g.writeDefault(s, infos); err != nil {
+		return err
+	}
+	if g.needsIsDefault[s.name] {
+		g.writeIsDefault(s, infos)
+	}
+
+	// encode
+	fmt.Fprintf(&g.b, "func (m *%s) encode(e *Encoder, version int16, flexible bool) {\n", s.name)
+	for _, fi := range infos {
+		if fi.present == "false" {
+			continue
+		}
+		g.openCond(fi.present)
+		g.writeEncode(fi, "m."+fi.f.Name, "e", fi.flexible)
+		g.closeCond(fi.present)
+	}
+	if !g.flex.empty() {
+		fmt.Fprintf(&g.b, "if flexible {\n")
+		if len(tagged) == 0 {
+			fmt.Fprintf(&g.b, "e.PutTaggedFields(m.UnknownTaggedFields)\n")
+		} else {
+			fmt.Fprintf(&g.b, "var tagged []TaggedField\n")
+			for _, fi := range tagged {
+				fmt.Fprintf(&g.b, "if %s {\n", g.taggedCond(fi))
+				fmt.Fprintf(&g.b, "te := NewEncoder(0)\n")
+				g.writeEncode(fi, "m."+fi.f.Name, "te", "true")
+				fmt.Fprintf(&g.b, "tagged = append(tagged, TaggedField{Tag: %d, Data: te.Bytes()})\n", *fi.f.Tag)
+				fmt.Fprintf(&g.b, "}\n")
+			}
+			fmt.Fprintf(&g.b, "e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))\n")
+		}
+		fmt.Fprintf(&g.b, "}\n")
+	}
+	fmt.Fprintf(&g.b, "}\n\n")
+
+	// decode
+	fmt.Fprintf(&g.b, "func (m *%s) decode(d *Decoder, version int16, flexible bool) {\n", s.name)
+	fmt.Fprintf(&g.b, "m.Default()\n")
+	for _, fi := range infos {
+		if fi.present == "false" {
+			continue
+		}
+		g.openCond(fi.present)
+		g.writeDecode(fi, "m."+fi.f.Name, "d", fi.flexible)
+		g.closeCond(fi.present)
+	}
+	if !g.flex.empty() {
+		fmt.Fprintf(&g.b, "if flexible {\n")
+		fmt.Fprintf(&g.b, "d.TaggedFields(func(tag uint64, fd *Decoder) {\n")
+		fmt.Fprintf(&g.b, "switch tag {\n")
+		for _, fi := range tagged {
+			fmt.Fprintf(&g.b, "case %d:\n", *fi.f.Tag)
+			if fi.tagged == "true" {
+				g.writeDecode(fi, "m."+fi.f.Name, "fd", "true")
+				continue
+			}
+			fmt.Fprintf(&g.b, "if !(%s) {\n", fi.tagged)
+			fmt.Fprintf(&g.b, "m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))\n")
+			fmt.Fprintf(&g.b, "return\n}\n")
+			g.writeDecode(fi, "m."+fi.f.Name, "fd", "true")
+		}
+		fmt.Fprintf(&g.b, "default:\n")
+		fmt.Fprintf(&g.b, "m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))\n")
+		fmt.Fprintf(&g.b, "}\n")
+		fmt.Fprintf(&g.b, "})\n")
+		fmt.Fprintf(&g.b, "}\n")
+	}
+	fmt.Fprintf(&g.b, "}\n\n")
+
+	return nil
+}
+
+// writeDefault emits the Default method, which resets s to the spec defaults
+func (g *generator) writeDefault(s *structDef, infos []*fieldInfo) error {
+	fmt.Fprintf(&g.b, "// Default resets %s to its default field values\n", s.name)
+	fmt.Fprintf(&g.b, "func (m *%s) Default() {\n", s.name)
+	fmt.Fprintf(&g.b, "*m = %s{}\n", s.name)
+	for _, fi := range infos {
+		def := fi.f.defaultValue()
+		switch fi.t.kind {
+		case "struct":
+			if !strings.HasPrefix(g.goType(fi.f, fi.t), "*") {
+				fmt.Fprintf(&g.b, "m.%s.Default()\n", fi.f.Name)
+			}
+		case "bool":
+			if def == "true" {
+				fmt.Fprintf(&g.b, "m.%s = true\n", fi.f.Name)
+			}
+		case "int8", "int16", "int32", "int64", "uint16", "uint32", "float64":
+			if def != "" && def != "0" {
+				fmt.Fprintf(&g.b, "m.%s = %s\n", fi.f.Name, def)
+			}
+		case "string":
+			if def == "" || def == "null" {
+				continue
+			}
+			if strings.HasPrefix(g.goType(fi.f, fi.t), "*") {
+				return fmt.Errorf("field %s: non-null defaults for nullable strings are not supported", fi.f.Name)
+			}
+			fmt.Fprintf(&g.b, "m.%s = %s\n", fi.f.Name, strconv.Quote(def))
+		}
+	}
+	fmt.Fprintf(&g.b, "}\n\n")
+	return nil
+}
+
+// taggedCond returns the condition under which a tagged field is written:
+// the version must carry it and the value must differ from its default
+func (g *generator) taggedCond(fi *fieldInfo) string {
+	nonDefault := g.nonDefault(fi, "m."+fi.f.Name)
+	if fi.tagged == "true" {
+		return nonDefault
+	}
+	return fmt.Sprintf("(%s) && %s", fi.tagged, nonDefault)
+}
+
+// nonDefault returns an expression that holds when x, the value of the
+// field described by fi, differs from the field's default
+func (g *generator) nonDefault(fi *fieldInfo, x string) string {
+	def := fi.f.defaultValue()
+	switch fi.t.kind {
+	case "bool":
+		if def == "true" {
+			return "!" + x
+		}
+		return x
+	case "int8", "int16", "int32", "int64", "uint16", "uint32", "float64":
+		if def == "" {
+			def = "0"
+		}
+		return fmt.Sprintf("%s != %s", x, def)
+	case "string":
+		if strings.HasPrefix(g.goType(fi.f, fi.t), "*") {
+			return x + " != nil"
+		}
+		return fmt.Sprintf("%s != %s", x, strconv.Quote(def))
+	case "uuid":
+		return x + " != ZeroUUID"
+	case "struct":
+		if strings.HasPrefix(g.goType(fi.f, fi.t), "*") {
+			return x + " != nil"
+		}
+		g.needsIsDefault[fi.t.name] = true
+		return fmt.Sprintf("!%s.isDefault()", x)
+	default: // bytes, records and arrays
+		if fi.nullable != "false" {
+			return x + " != nil"
+		}
+		return fmt.Sprintf("len(%s) > 0", x)
+	}
+}
+
+// writeIsDefault emits the isDefault method of a struct used as a tagged field
+func (g *generator) writeIsDefault(s *structDef, infos []*fieldInfo) {
+	fmt.Fprintf(&g.b, "// isDefault reports whether %s holds only default field values\n", s.name)
+	fmt.Fprintf(&g.b, "func (m *%s) isDefault() bool {\n", s.name)
+	for _, fi := range infos {
+		fmt.Fprintf(&g.b, "if %s {\nreturn false\n}\n", g.nonDefault(fi, "m."+fi.f.Name))
+	}
+	if g.flex.empty() {
+		fmt.Fprintf(&g.b, "return true\n}\n\n")
+	} else {
+		fmt.Fprintf(&g.b, "return len(m.UnknownTaggedFields) == 0\n}\n\n")
+	}
+}
+
+// openCond opens an if block for a version condition unless it always holds
+func (g *generator) openCond(cond string) {
+	if cond != "true" {
+		fmt.Fprintf(&g.b, "if %s {\n", cond)
+	}
+}
+
+// closeCond closes a block opened by openCond
+func (g *generator) closeCond(cond string) {
+	if cond != "true" {
+		fmt.Fprintf(&g.b, "}\n")
+	}
+}
+
+// writeEncode emits the statements encoding x with the encoder enc
+func (g *generator) writeEncode(fi *fieldInfo, x, enc, flex string) {
+	t := fi.t
+	switch t.kind {
+	case "array":
+		elem := &fieldInfo{f: &fieldSpec{}, t: t.elem, nullable: "false", flexible: flex}
+		length := func() {
+			fmt.Fprintf(&g.b, "%s.PutArrayLength(len(%s), %s)\n", enc, x, flex)
+			fmt.Fprintf(&g.b, "for i := range %s {\n", x)
+			g.writeEncode(elem, x+"[i]", enc, flex)
+			fmt.Fprintf(&g.b, "}\n")
+		}
+		switch fi.nullable {
+		case "false":
+			length()
+		case "true":
+			fmt.Fprintf(&g.b, "if %s == nil {\n", x)
+			fmt.Fprintf(&g.b, "%s.PutArrayLength(-1, %s)\n", enc, flex)
+			fmt.Fprintf(&g.b, "} else {\n")
+			length()
+			fmt.Fprintf(&g.b, "}\n")
+		default:
+			fmt.Fprintf(&g.b, "if %s == nil && (%s) {\n", x, fi.nullable)
+			fmt.Fprintf(&g.b, "%s.PutArrayLength(-1, %s)\n", enc, flex)
+			fmt.Fprintf(&g.b, "} else {\n")
+			length()
+			fmt.Fprintf(&g.b, "}\n")
+		}
+	case "struct":
+		if fi.nullable == "false" && !strings.HasPrefix(g.goType(fi.f, t), "*") {
+			fmt.Fprintf(&g.b, "%s.encode(%s, version, flexible)\n", x, enc)
+			return
+		}
+		fmt.Fprintf(&g.b, "if %s == nil {\n", x)
+		if fi.nullable == "true" {
+			fmt.Fprintf(&g.b, "%s.PutInt8(-1)\n", enc)
+		} else {
+			fmt.Fprintf(&g.b, "if %s {\n%s.PutInt8(-1)\n} else {\n", fi.nullable, enc)
+			fmt.Fprintf(&g.b, "(&%s{}).encode(%s, version, flexible)\n}\n", t.name, enc)
+		}
+		fmt.Fprintf(&g.b, "} else {\n")
+		switch fi.nullable {
+		case "true":
+			fmt.Fprintf(&g.b, "%s.PutInt8(1)\n", enc)
+		case "false":
+		default:
+			fmt.Fprintf(&g.b, "if %s {\n%s.PutInt8(1)\n}\n", fi.nullable, enc)
+		}
+		fmt.Fprintf(&g.b, "%s.encode(%s, version, flexible)\n", x, enc)
+		fmt.Fprintf(&g.b, "}\n")
+	case "string":
+		if !strings.HasPrefix(g.goType(fi.f, t), "*") {
+			fmt.Fprintf(&g.b, "%s.PutString(%s, %s)\n", enc, x, flex)
+			return
+		}
+		if fi.nullable == "true" {
+			fmt.Fprintf(&g.b, "%s.PutNullableString(%s, %s)\n", enc, x, flex)
+			return
+		}
+		fmt.Fprintf(&g.b, "if %s {\n%s.PutNullableString(%s, %s)\n", fi.nullable, enc, x, flex)
+		fmt.Fprintf(&g.b, "} else {\n%s.PutString(derefString(%s), %s)\n}\n", enc, x, flex)
+	case "bytes", "records":
+		switch fi.nullable {
+		case "false":
+			fmt.Fprintf(&g.b, "%s.PutBytes(%s, %s)\n", enc, x, flex)
+		case "true":
+			fmt.Fprintf(&g.b, "%s.PutNullableBytes(%s, %s)\n", enc, x, flex)
+		default:
+			fmt.Fprintf(&g.b, "if %s {\n%s.PutNullableBytes(%s, %s)\n", fi.nullable, enc, x, flex)
+			fmt.Fprintf(&g.b, "} else {\n%s.PutBytes(%s, %s)\n}\n", enc, x, flex)
+		}
+	case "bool":
+		fmt.Fprintf(&g.b, "%s.PutBool(%s)\n", enc, x)
+	case "uuid":
+		fmt.Fprintf(&g.b, "%s.PutUUID(%s)\n", enc, x)
+	default:
+		fmt.Fprintf(&g.b, "%s.Put%s(%s)\n", enc, exportName(t.kind), x)
+	}
+}
+
+// writeDecode emits the statements decoding into x with the decoder dec
+func (g *generator) writeDecode(fi *fieldInfo, x, dec, flex string) {
+	t := fi.t
+	switch t.kind {
+	case "array":
+		elem := &fieldInfo{f: &fieldSpec{}, t: t.elem, nullable: "false", flexible: flex}
+		fmt.Fprintf(&g.b, "if n := %s.ArrayLength(%s); n >= 0 {\n", dec, flex)
+		fmt.Fprintf(&g.b, "%s = make(%s, n)\n", x, g.goType(fi.f, t))
+		fmt.Fprintf(&g.b, "for i := range %s {\n", x)
+		g.writeDecode(elem, x+"[i]", dec, flex)
+		fmt.Fprintf(&g.b, "}\n")
+		fmt.Fprintf(&g.b, "} else {\n%s = nil\n}\n", x)
+	case "struct":
+		if !strings.HasPrefix(g.goType(fi.f, t), "*") {
+			fmt.Fprintf(&g.b, "%s.decode(%s, version, flexible)\n", x, dec)
+			return
+		}
+		if fi.nullable == "true" {
+			fmt.Fprintf(&g.b, "if %s.Int8() >= 0 {\n", dec)
+		} else {
+			fmt.Fprintf(&g.b, "if !(%s) || %s.Int8() >= 0 {\n", fi.nullable, dec)
+		}
+		fmt.Fprintf(&g.b, "%s = &%s{}\n", x, t.name)
+		fmt.Fprintf(&g.b, "%s.decode(%s, version, flexible)\n", x, dec)
+		fmt.Fprintf(&g.b, "} else {\n%s = nil\n}\n", x)
+	case "string":
+		if !strings.HasPrefix(g.goType(fi.f, t), "*") {
+			fmt.Fprintf(&g.b, "%s = %s.String(%s)\n", x, dec, flex)
+			return
+		}
+		if fi.nullable == "true" {
+			fmt.Fprintf(&g.b, "%s = %s.NullableString(%s)\n", x, dec, flex)
+			return
+		}
+		fmt.Fprintf(&g.b, "if %s {\n%s = %s.NullableString(%s)\n", fi.nullable, x, dec, flex)
+		fmt.Fprintf(&g.b, "} else {\ns := %s.String(%s)\n%s = &s\n}\n", dec, flex, x)
+	case "bytes", "records":
+		switch fi.nullable {
+		case "false":
+			fmt.Fprintf(&g.b, "%s = %s.Bytes(%s)\n", x, dec, flex)
+		case "true":
+			fmt.Fprintf(&g.b, "%s = %s.NullableBytes(%s)\n", x, dec, flex)
+		default:
+			fmt.Fprintf(&g.b, "if %s {\n%s = %s.NullableBytes(%s)\n", fi.nullable, x, dec, flex)
+			fmt.Fprintf(&g.b, "} else {\n%s = %s.Bytes(%s)\n}\n", x, dec, flex)
+		}
+	case "bool":
+		fmt.Fprintf(&g.b, "%s = %s.Bool()\n", x, dec)
+	case "uuid":
+		fmt.Fprintf(&g.b, "%s = %s.UUID()\n", x, dec)
+	default:
+		fmt.Fprintf(&g.b, "%s = %s.%s()\n", x, dec, exportName(t.kind))
+	}
+}
+
+// exportName capitalises a primitive type name to match the Encoder/Decoder methods
+func exportName(kind string) string {
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// generateIndex emits a table of the version ranges of every request spec,
+// which the protocol package uses to pick header versions by API key
+func generateIndex(specs []*messageSpec, pkg string) ([]byte, error) {
+	var requests []*messageSpec
+	for _, spec := range specs {
+		if spec.Type == "request" && spec.APIKey != nil {
+			requests = append(requests, spec)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool { return *requests[i].APIKey < *requests[j].APIKey })
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by protogen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "// apiSpecs holds the versions defined by each request spec, keyed by API key\n")
+	fmt.Fprintf(&b, "var apiSpecs = map[int16]apiSpec{\n")
+	for _, spec := range requests {
+		valid, err := parseVersions(spec.ValidVersions)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec.Name, err)
+		}
+		flex, err := parseVersions(spec.FlexibleVersions)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec.Name, err)
+		}
+		firstFlexible := int16(-1)
+		if !flex.empty() {
+			firstFlexible = flex.lo
+		}
+		fmt.Fprintf(&b, "\t%d: {name: %q, minVersion: %d, maxVersion: %d, firstFlexibleVersion: %d},\n",
+			*spec.APIKey, strings.TrimSuffix(spec.Name, "Request"), valid.lo, valid.hi, firstFlexible)
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	return format.Source(b.Bytes())
+}
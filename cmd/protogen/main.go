@@ -0,0 +1,112 @@
+// Package main implements protogen, which generates Go message types with
+// versioned Encode/Decode methods from Apache Kafka's JSON message specs.
+//
+// Usage:
+//
+//	protogen -dir internal/kafka/protocol/messages -out internal/kafka/protocol
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", "messages", "directory containing the *.json message specs")
+	out := flag.String("out", ".", "directory to write the generated Go files to")
+	pkg := flag.String("package", "protocol", "package name of the generated files")
+	flag.Parse()
+
+	if err := run(*dir, *out, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "protogen: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+// run generates one Go file per message spec found in dir
+func run(dir, out, pkg string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no message specs found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	specs := make([]*messageSpec, 0, len(paths))
+	for _, path := range paths {
+		spec, err := loadSpec(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		specs = append(specs, spec)
+
+		src, err := generateMessage(spec, pkg, filepath.ToSlash(filepath.Join(filepath.Base(dir), filepath.Base(path))))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		name := filepath.Join(out, snakeCase(spec.Name)+"_gen.go")
+		if err := os.WriteFile(name, src, 0o644); err != nil {
+			return err
+		}
+	}
+
+	src, err := generateIndex(specs, pkg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(out, "apis_gen.go"), src, 0o644)
+}
+
+// loadSpec reads a message spec, stripping the // comment lines that
+// Kafka's spec files use and plain JSON does not allow
+func loadSpec(path string) (*messageSpec, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var clean bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "//") {
+			continue
+		}
+		clean.WriteString(line)
+		clean.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	spec := &messageSpec{}
+	if err := json.Unmarshal(clean.Bytes(), spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// snakeCase converts a CamelCase message name into a file name
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// messageSpec is the top-level object of a Kafka JSON message spec
+type messageSpec struct {
+	APIKey           *int16       `json:"apiKey"`
+	Type             string       `json:"type"`
+	Name             string       `json:"name"`
+	ValidVersions    string       `json:"validVersions"`
+	FlexibleVersions string       `json:"flexibleVersions"`
+	Fields           []*fieldSpec `json:"fields"`
+	CommonStructs    []*fieldSpec `json:"commonStructs"`
+}
+
+// fieldSpec describes one field of a message or struct
+type fieldSpec struct {
+	Name             string          `json:"name"`
+	Type             string          `json:"type"`
+	Versions         string          `json:"versions"`
+	NullableVersions string          `json:"nullableVersions"`
+	TaggedVersions   string          `json:"taggedVersions"`
+	FlexibleVersions string          `json:"flexibleVersions"`
+	Tag              *int            `json:"tag"`
+	Default          json.RawMessage `json:"default"`
+	About            string          `json:"about"`
+	Fields           []*fieldSpec    `json:"fields"`
+}
+
+// defaultValue returns the field's default as a plain string, or "" when unset
+func (f *fieldSpec) defaultValue() string {
+	if len(f.Default) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(f.Default, &s); err == nil {
+		return s
+	}
+	return string(f.Default)
+}
+
+// versionRange is an inclusive range of message versions
+type versionRange struct {
+	lo, hi int16
+}
+
+// noVersions is the empty version range
+var noVersions = versionRange{lo: 1, hi: 0}
+
+// parseVersions parses a version range such as "0+", "1-4", "3" or "none"
+func parseVersions(s string) (versionRange, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || s == "none":
+		return noVersions, nil
+	case strings.HasSuffix(s, "+"):
+		lo, err := strconv.ParseInt(strings.TrimSuffix(s, "+"), 10, 16)
+		if err != nil {
+			return noVersions, fmt.Errorf("invalid version range %q", s)
+		}
+		return versionRange{lo: int16(lo), hi: math.MaxInt16}, nil
+	case strings.Contains(s, "-"):
+		parts := strings.SplitN(s, "-", 2)
+		lo, err1 := strconv.ParseInt(parts[0], 10, 16)
+		hi, err2 := strconv.ParseInt(parts[1], 10, 16)
+		if err1 != nil || err2 != nil {
+			return noVersions, fmt.Errorf("invalid version range %q", s)
+		}
+		return versionRange{lo: int16(lo), hi: int16(hi)}, nil
+	default:
+		v, err := strconv.ParseInt(s, 10, 16)
+		if err != nil {
+			return noVersions, fmt.Errorf("invalid version range %q", s)
+		}
+		return versionRange{lo: int16(v), hi: int16(v)}, nil
+	}
+}
+
+// empty reports whether the range contains no versions
+func (r versionRange) empty() bool {
+	return r.lo > r.hi
+}
+
+// intersect returns the versions contained in both ranges
+func (r versionRange) intersect(o versionRange) versionRange {
+	if r.empty() || o.empty() {
+		return noVersions
+	}
+	return versionRange{lo: max(r.lo, o.lo), hi: min(r.hi, o.hi)}
+}
+
+// cond returns a Go expression over the variable "version" that is true
+// exactly for the versions of r that lie inside valid
+func (r versionRange) cond(valid versionRange) string {
+	r = r.intersect(valid)
+	if r.empty() {
+		return "false"
+	}
+
+	var parts []string
+	if r.lo > valid.lo {
+		parts = append(parts, fmt.Sprintf("version >= %d", r.lo))
+	}
+	if r.hi < valid.hi {
+		parts = append(parts, fmt.Sprintf("version <= %d", r.hi))
+	}
+	if len(parts) == 0 {
+		return "true"
+	}
+	return strings.Join(parts, " && ")
+}
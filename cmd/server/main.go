@@ -14,13 +14,23 @@ func main() {
 	log := logger.New(logger.INFO)
 	log.Info("Kafka server starting...")
 
-	config := server.Config{
-		Host: "0.0.0.0",
-		Port: 9092,
+	// An optional server.properties path may be given as the first argument
+	config := server.DefaultConfig()
+	if len(os.Args) > 1 {
+		loaded, err := server.LoadConfig(os.Args[1])
+		if err != nil {
+			log.Error("Failed to load config: %s", err.Error())
+			os.Exit(1)
+		}
+		config = loaded
 	}
 
 	// Create and start the server
-	srv := server.New(config, log)
+	srv, err := server.New(config, log)
+	if err != nil {
+		log.Error("Failed to create server: %s", err.Error())
+		os.Exit(1)
+	}
 	if err := srv.Start(); err != nil {
 		log.Error("Failed to start server: %s", err.Error())
 		os.Exit(1)
@@ -0,0 +1,186 @@
+package group
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+// Group types as ListGroups reports and filters them
+const (
+	classicGroupType  = "classic"
+	consumerGroupType = "consumer"
+)
+
+// consumerProtocolType is the protocol type of consumer groups, which
+// classic consumers use as well
+const consumerProtocolType = "consumer"
+
+// ListGroups lists the groups whose state and type match the filters,
+// ordered by group ID. Filters are matched without regard to case, and an
+// empty filter matches every group.
+func (c *Coordinator) ListGroups(statesFilter, typesFilter []string) ([]protocol.ListGroupsResponseListedGroup, int16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, protocol.ErrorNotCoordinator
+	}
+	matches := func(filter []string, value string) bool {
+		return len(filter) == 0 || slices.ContainsFunc(filter, func(f string) bool { return strings.EqualFold(f, value) })
+	}
+
+	listed := []protocol.ListGroupsResponseListedGroup{}
+	for _, g := range c.groups {
+		if g.state == Dead || !matches(statesFilter, g.state.String()) || !matches(typesFilter, classicGroupType) {
+			continue
+		}
+		group := protocol.ListGroupsResponseListedGroup{GroupId: g.id, GroupState: g.state.String(), GroupType: classicGroupType}
+		if g.protocolType != nil {
+			group.ProtocolType = *g.protocolType
+		}
+		listed = append(listed, group)
+	}
+	for _, g := range c.consumerGroups {
+		state := g.state()
+		if state == consumerDead || !matches(statesFilter, state.String()) || !matches(typesFilter, consumerGroupType) {
+			continue
+		}
+		listed = append(listed, protocol.ListGroupsResponseListedGroup{
+			GroupId:      g.id,
+			ProtocolType: consumerProtocolType,
+			GroupState:   state.String(),
+			GroupType:    consumerGroupType,
+		})
+	}
+	sort.Slice(listed, func(i, j int) bool { return listed[i].GroupId < listed[j].GroupId })
+	return listed, protocol.ErrorNone
+}
+
+// DescribeGroups describes the classic groups with the given IDs. Members'
+// metadata and assignments, and the group's protocol, are only reported
+// while the group is Stable, as they are in flux during a rebalance.
+// Groups that do not exist or are not classic groups get
+// GROUP_ID_NOT_FOUND.
+func (c *Coordinator) DescribeGroups(groupIDs []string) []protocol.DescribeGroupsResponseDescribedGroup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	described := make([]protocol.DescribeGroupsResponseDescribedGroup, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		result := protocol.DescribeGroupsResponseDescribedGroup{}
+		result.Default()
+		result.GroupId = id
+		g := c.groups[id]
+		switch {
+		case c.closed:
+			result.ErrorCode = protocol.ErrorNotCoordinator
+		case id == "":
+			result.ErrorCode = protocol.ErrorInvalidGroupID
+		case g == nil && c.consumerGroups[id] != nil:
+			result.ErrorCode = protocol.ErrorGroupIDNotFound
+			message := fmt.Sprintf("Group %s is not a classic group.", id)
+			result.ErrorMessage = &message
+		case g == nil:
+			result.ErrorCode = protocol.ErrorGroupIDNotFound
+			message := fmt.Sprintf("Group %s not found.", id)
+			result.ErrorMessage = &message
+		default:
+			describeClassicGroup(g, &result)
+		}
+		if result.ErrorCode != protocol.ErrorNone {
+			result.GroupState = Dead.String()
+		}
+		described = append(described, result)
+	}
+	return described
+}
+
+// describeClassicGroup fills in the description of a classic group
+func describeClassicGroup(g *classicGroup, result *protocol.DescribeGroupsResponseDescribedGroup) {
+	result.ErrorCode = protocol.ErrorNone
+	result.GroupState = g.state.String()
+	if g.protocolType != nil {
+		result.ProtocolType = *g.protocolType
+	}
+	stable := g.state == Stable
+	if stable && g.protocolName != nil {
+		result.ProtocolData = *g.protocolName
+	}
+	result.Members = []protocol.DescribeGroupsResponseDescribedGroupMember{}
+	for _, m := range g.sortedMembers() {
+		member := protocol.DescribeGroupsResponseDescribedGroupMember{
+			MemberId:         m.id,
+			GroupInstanceId:  m.instanceID,
+			ClientId:         m.clientID,
+			ClientHost:       m.clientHost,
+			MemberMetadata:   []byte{},
+			MemberAssignment: []byte{},
+		}
+		if stable {
+			member.MemberMetadata = m.metadata(*g.protocolName)
+			member.MemberAssignment = m.assignment
+		}
+		result.Members = append(result.Members, member)
+	}
+}
+
+// DeleteGroups deletes empty groups along with their committed offsets,
+// whose tombstones are written to the offsets topic. Groups with members
+// get NON_EMPTY_GROUP.
+func (c *Coordinator) DeleteGroups(groupIDs []string) []protocol.DeleteGroupsResponseDeletableGroupResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	results := make([]protocol.DeleteGroupsResponseDeletableGroupResult, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		results = append(results, protocol.DeleteGroupsResponseDeletableGroupResult{
+			GroupId:   id,
+			ErrorCode: c.deleteGroup(id),
+		})
+	}
+	return results
+}
+
+// deleteGroup deletes one group for DeleteGroups
+func (c *Coordinator) deleteGroup(id string) int16 {
+	classic, consumer := c.groups[id], c.consumerGroups[id]
+	switch {
+	case c.closed:
+		return protocol.ErrorNotCoordinator
+	case id == "":
+		return protocol.ErrorInvalidGroupID
+	case classic == nil && consumer == nil,
+		classic != nil && classic.state == Dead:
+		return protocol.ErrorGroupIDNotFound
+	case classic != nil && (classic.state != Empty || len(classic.pendingMembers) > 0),
+		consumer != nil && len(consumer.members) > 0:
+		return protocol.ErrorNonEmptyGroup
+	}
+
+	if offsets := c.offsets[id]; len(offsets) > 0 {
+		records := make([]record.Record, 0, len(offsets))
+		for tp := range offsets {
+			records = append(records, offsetRecord(id, tp, nil))
+		}
+		if err := c.appendRecords(id, records); err != nil {
+			c.logger.Error("Failed to delete offsets of group %s: %s", id, err.Error())
+			return protocol.ErrorCoordinatorNotAvailable
+		}
+		delete(c.offsets, id)
+	}
+
+	if classic != nil {
+		c.transition(classic, Dead)
+		delete(c.groups, id)
+	} else {
+		consumer.dead = true
+		delete(c.consumerGroups, id)
+	}
+	c.logger.Info("Deleted group %s", id)
+	return protocol.ErrorNone
+}
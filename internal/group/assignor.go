@@ -0,0 +1,264 @@
+package group
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// assignment maps topic IDs to the partitions assigned from each topic
+type assignment map[protocol.UUID]map[int32]bool
+
+// add assigns a partition
+func (a assignment) add(topic protocol.UUID, partition int32) {
+	if a[topic] == nil {
+		a[topic] = make(map[int32]bool)
+	}
+	a[topic][partition] = true
+}
+
+// remove unassigns a partition
+func (a assignment) remove(topic protocol.UUID, partition int32) {
+	delete(a[topic], partition)
+	if len(a[topic]) == 0 {
+		delete(a, topic)
+	}
+}
+
+// contains reports whether a partition is assigned
+func (a assignment) contains(topic protocol.UUID, partition int32) bool {
+	return a[topic][partition]
+}
+
+// size returns the number of assigned partitions
+func (a assignment) size() int {
+	n := 0
+	for _, partitions := range a {
+		n += len(partitions)
+	}
+	return n
+}
+
+// equal reports whether both assignments hold the same partitions
+func (a assignment) equal(b assignment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for topic, partitions := range a {
+		if len(b[topic]) != len(partitions) {
+			return false
+		}
+		for p := range partitions {
+			if !b[topic][p] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// subsetOf reports whether every partition of a is assigned in b
+func (a assignment) subsetOf(b assignment) bool {
+	for topic, partitions := range a {
+		for p := range partitions {
+			if !b[topic][p] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// topics returns the assigned topics in a stable order
+func (a assignment) topics() []protocol.UUID {
+	topics := make([]protocol.UUID, 0, len(a))
+	for topic := range a {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool { return bytes.Compare(topics[i][:], topics[j][:]) < 0 })
+	return topics
+}
+
+// partitions returns the partitions assigned from a topic, sorted
+func (a assignment) partitions(topic protocol.UUID) []int32 {
+	partitions := make([]int32, 0, len(a[topic]))
+	for p := range a[topic] {
+		partitions = append(partitions, p)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	return partitions
+}
+
+// subscribedTopic is a topic some member of a group subscribes to
+type subscribedTopic struct {
+	id         protocol.UUID
+	name       string
+	partitions int32
+}
+
+// memberSpec is what an assignor knows about a member: the topics it
+// subscribes to and the partitions it is currently assigned
+type memberSpec struct {
+	topics  map[protocol.UUID]bool
+	current assignment
+}
+
+// assignor computes the target assignment of a consumer group on the
+// coordinator, as selected by group.consumer.assignors and the members'
+// ServerAssignor
+type assignor interface {
+	assign(members map[string]memberSpec, topics map[protocol.UUID]subscribedTopic) map[string]assignment
+}
+
+// assignors are the server-side assignors, by the name clients select
+// them with
+var assignors = map[string]assignor{
+	"uniform": uniformAssignor{},
+	"range":   rangeAssignor{},
+}
+
+// sortedMemberIDs returns the IDs of the members ordered by member ID
+func sortedMemberIDs(members map[string]memberSpec) []string {
+	ids := make([]string, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sortedTopics returns the subscribed topics ordered by name
+func sortedTopics(topics map[protocol.UUID]subscribedTopic) []subscribedTopic {
+	sorted := make([]subscribedTopic, 0, len(topics))
+	for _, t := range topics {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	return sorted
+}
+
+// uniformAssignor spreads the partitions as evenly as the subscriptions
+// allow while moving as few of them as possible: members keep the
+// partitions they still subscribe to, unassigned partitions go to the
+// least loaded subscriber, and partitions then move from a member to a
+// subscriber holding at least two fewer until no such move is left
+type uniformAssignor struct{}
+
+func (uniformAssignor) assign(members map[string]memberSpec, topics map[protocol.UUID]subscribedTopic) map[string]assignment {
+	ids := sortedMemberIDs(members)
+	result := make(map[string]assignment, len(members))
+	for _, id := range ids {
+		result[id] = assignment{}
+	}
+
+	// subscribers lists the members of each topic in member ID order
+	subscribers := make(map[protocol.UUID][]string)
+	for _, id := range ids {
+		for topic := range members[id].topics {
+			if _, ok := topics[topic]; ok {
+				subscribers[topic] = append(subscribers[topic], id)
+			}
+		}
+	}
+	leastLoaded := func(topic protocol.UUID) string {
+		var least string
+		for _, id := range subscribers[topic] {
+			if least == "" || result[id].size() < result[least].size() {
+				least = id
+			}
+		}
+		return least
+	}
+
+	owners := make(map[protocol.UUID]map[int32]string)
+	for _, t := range topics {
+		owners[t.id] = make(map[int32]string)
+	}
+	for _, id := range ids {
+		for topic, partitions := range members[id].current {
+			t, ok := topics[topic]
+			if !ok || !members[id].topics[topic] {
+				continue
+			}
+			for p := range partitions {
+				if p < t.partitions && owners[topic][p] == "" {
+					owners[topic][p] = id
+					result[id].add(topic, p)
+				}
+			}
+		}
+	}
+
+	sorted := sortedTopics(topics)
+	for _, t := range sorted {
+		for p := int32(0); p < t.partitions; p++ {
+			if owners[t.id][p] != "" || len(subscribers[t.id]) == 0 {
+				continue
+			}
+			id := leastLoaded(t.id)
+			owners[t.id][p] = id
+			result[id].add(t.id, p)
+		}
+	}
+
+	for moved := true; moved; {
+		moved = false
+		for _, t := range sorted {
+			for p := int32(0); p < t.partitions; p++ {
+				from := owners[t.id][p]
+				if from == "" {
+					continue
+				}
+				to := leastLoaded(t.id)
+				if result[to].size()+1 < result[from].size() {
+					result[from].remove(t.id, p)
+					result[to].add(t.id, p)
+					owners[t.id][p] = to
+					moved = true
+				}
+			}
+		}
+	}
+	return result
+}
+
+// rangeAssignor gives each subscriber of a topic a contiguous range of its
+// partitions, in member ID order, with the first members taking one extra
+// partition when they do not divide evenly. Members subscribed to the same
+// topics get the same partition numbers from each, which keeps
+// co-partitioned topics together.
+type rangeAssignor struct{}
+
+func (rangeAssignor) assign(members map[string]memberSpec, topics map[protocol.UUID]subscribedTopic) map[string]assignment {
+	ids := sortedMemberIDs(members)
+	result := make(map[string]assignment, len(members))
+	for _, id := range ids {
+		result[id] = assignment{}
+	}
+
+	for _, t := range sortedTopics(topics) {
+		var subscribers []string
+		for _, id := range ids {
+			if members[id].topics[t.id] {
+				subscribers = append(subscribers, id)
+			}
+		}
+		if len(subscribers) == 0 {
+			continue
+		}
+		quota, extra := t.partitions/int32(len(subscribers)), t.partitions%int32(len(subscribers))
+		next := int32(0)
+		for i, id := range subscribers {
+			n := quota
+			if int32(i) < extra {
+				n++
+			}
+			for p := next; p < next+n; p++ {
+				result[id].add(t.id, p)
+			}
+			next += n
+		}
+	}
+	return result
+}
@@ -0,0 +1,331 @@
+package group
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// Topics of the assignor tests
+var (
+	topicA = subscribedTopic{id: protocol.UUID{1}, name: "a", partitions: 5}
+	topicB = subscribedTopic{id: protocol.UUID{2}, name: "b", partitions: 4}
+	// topicC is co-partitioned with topicA
+	topicC = subscribedTopic{id: protocol.UUID{3}, name: "c", partitions: 5}
+)
+
+// topicsOf maps the given topics by ID
+func topicsOf(topics ...subscribedTopic) map[protocol.UUID]subscribedTopic {
+	byID := make(map[protocol.UUID]subscribedTopic, len(topics))
+	for _, t := range topics {
+		byID[t.id] = t
+	}
+	return byID
+}
+
+// subscribing returns the spec of a member subscribed to topics, with no
+// current assignment
+func subscribing(topics ...subscribedTopic) memberSpec {
+	spec := memberSpec{topics: make(map[protocol.UUID]bool), current: assignment{}}
+	for _, t := range topics {
+		spec.topics[t.id] = true
+	}
+	return spec
+}
+
+// withCurrent returns spec with current as its current assignment
+func withCurrent(spec memberSpec, current assignment) memberSpec {
+	spec.current = current
+	return spec
+}
+
+// partitionsOf builds the assignment of the given partitions of topic
+func partitionsOf(topic subscribedTopic, partitions ...int32) assignment {
+	a := assignment{}
+	for _, p := range partitions {
+		a.add(topic.id, p)
+	}
+	return a
+}
+
+// union merges assignments
+func union(assignments ...assignment) assignment {
+	merged := assignment{}
+	for _, a := range assignments {
+		for topic, partitions := range a {
+			for p := range partitions {
+				merged.add(topic, p)
+			}
+		}
+	}
+	return merged
+}
+
+// formatAssignment writes an assignment as topic:partitions for messages
+func formatAssignment(a assignment) string {
+	s := ""
+	for _, topic := range a.topics() {
+		s += fmt.Sprintf("%d:%v ", topic[0], a.partitions(topic))
+	}
+	return s
+}
+
+// checkComplete fails the test unless result assigns every partition of
+// topics exactly once, and only to members subscribed to its topic
+func checkComplete(t *testing.T, result map[string]assignment, members map[string]memberSpec, topics map[protocol.UUID]subscribedTopic) {
+	t.Helper()
+	owners := make(map[protocol.UUID]map[int32]string)
+	for id, a := range result {
+		for topic, partitions := range a {
+			if !members[id].topics[topic] {
+				t.Errorf("member %s assigned topic %d it does not subscribe to", id, topic[0])
+			}
+			for p := range partitions {
+				if owners[topic] == nil {
+					owners[topic] = make(map[int32]string)
+				}
+				if owner := owners[topic][p]; owner != "" {
+					t.Errorf("partition %d-%d assigned to both %s and %s", topic[0], p, owner, id)
+				}
+				owners[topic][p] = id
+			}
+		}
+	}
+	for _, topic := range topics {
+		for p := int32(0); p < topic.partitions; p++ {
+			if owners[topic.id][p] == "" {
+				t.Errorf("partition %d-%d not assigned", topic.id[0], p)
+			}
+		}
+	}
+}
+
+// checkBalanced fails the test unless the members of result hold numbers
+// of partitions at most one apart
+func checkBalanced(t *testing.T, result map[string]assignment) {
+	t.Helper()
+	least, most := -1, 0
+	for _, a := range result {
+		if least < 0 || a.size() < least {
+			least = a.size()
+		}
+		most = max(most, a.size())
+	}
+	if most-least > 1 {
+		for id, a := range result {
+			t.Logf("%s: %s", id, formatAssignment(a))
+		}
+		t.Errorf("members hold between %d and %d partitions, want at most one apart", least, most)
+	}
+}
+
+func TestUniformAssignorBalance(t *testing.T) {
+	topics := topicsOf(topicA, topicB)
+	tests := []struct {
+		name    string
+		members map[string]memberSpec
+	}{
+		{
+			name:    "one member",
+			members: map[string]memberSpec{"m1": subscribing(topicA, topicB)},
+		},
+		{
+			name: "three members",
+			members: map[string]memberSpec{
+				"m1": subscribing(topicA, topicB),
+				"m2": subscribing(topicA, topicB),
+				"m3": subscribing(topicA, topicB),
+			},
+		},
+		{
+			name: "more members than partitions",
+			members: map[string]memberSpec{
+				"m1": subscribing(topicA, topicB), "m2": subscribing(topicA, topicB),
+				"m3": subscribing(topicA, topicB), "m4": subscribing(topicA, topicB),
+				"m5": subscribing(topicA, topicB), "m6": subscribing(topicA, topicB),
+				"m7": subscribing(topicA, topicB), "m8": subscribing(topicA, topicB),
+				"m9": subscribing(topicA, topicB), "m10": subscribing(topicA, topicB),
+			},
+		},
+		{
+			// The stale assignment of m1 is rebalanced rather than kept
+			name: "unbalanced current assignment",
+			members: map[string]memberSpec{
+				"m1": withCurrent(subscribing(topicA, topicB), union(partitionsOf(topicA, 0, 1, 2, 3, 4), partitionsOf(topicB, 0, 1, 2, 3))),
+				"m2": subscribing(topicA, topicB),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := uniformAssignor{}.assign(tt.members, topics)
+			if len(result) != len(tt.members) {
+				t.Errorf("assigned %d members, want %d", len(result), len(tt.members))
+			}
+			checkComplete(t, result, tt.members, topics)
+			checkBalanced(t, result)
+		})
+	}
+}
+
+func TestUniformAssignorSubscriptions(t *testing.T) {
+	// m1 alone subscribes to topicA, so it gets all of it; topicB is
+	// spread so that the totals are as even as it allows
+	topics := topicsOf(topicA, topicB)
+	members := map[string]memberSpec{
+		"m1": subscribing(topicA, topicB),
+		"m2": subscribing(topicB),
+		"m3": subscribing(topicB),
+	}
+	result := uniformAssignor{}.assign(members, topics)
+	checkComplete(t, result, members, topics)
+	if got := len(result["m1"][topicA.id]); got != 5 {
+		t.Errorf("m1 holds %d partitions of topic a, want 5", got)
+	}
+	if got := result["m1"].size(); got != 5 {
+		t.Errorf("m1 holds %d partitions, want only those of topic a", got)
+	}
+	if result["m2"].size() != 2 || result["m3"].size() != 2 {
+		t.Errorf("m2 and m3 hold %d and %d partitions, want 2 each", result["m2"].size(), result["m3"].size())
+	}
+}
+
+func TestUniformAssignorStickiness(t *testing.T) {
+	topics := topicsOf(topicA, topicB)
+	initial := map[string]memberSpec{
+		"m1": subscribing(topicA, topicB),
+		"m2": subscribing(topicA, topicB),
+		"m3": subscribing(topicA, topicB),
+	}
+	before := uniformAssignor{}.assign(initial, topics)
+
+	t.Run("member joins", func(t *testing.T) {
+		members := map[string]memberSpec{"m4": subscribing(topicA, topicB)}
+		for id, spec := range initial {
+			members[id] = withCurrent(spec, before[id])
+		}
+		after := uniformAssignor{}.assign(members, topics)
+		checkComplete(t, after, members, topics)
+		checkBalanced(t, after)
+
+		// Members only give up partitions, which all go to the new member
+		moved := 0
+		for id := range initial {
+			if !after[id].subsetOf(before[id]) {
+				t.Errorf("%s went from %s to %s, gaining partitions", id, formatAssignment(before[id]), formatAssignment(after[id]))
+			}
+			moved += before[id].size() - after[id].size()
+		}
+		if moved != after["m4"].size() {
+			t.Errorf("%d partitions moved, want only the %d of the new member", moved, after["m4"].size())
+		}
+	})
+
+	t.Run("member leaves", func(t *testing.T) {
+		members := map[string]memberSpec{
+			"m1": withCurrent(initial["m1"], before["m1"]),
+			"m2": withCurrent(initial["m2"], before["m2"]),
+		}
+		after := uniformAssignor{}.assign(members, topics)
+		checkComplete(t, after, members, topics)
+		checkBalanced(t, after)
+
+		// The remaining members keep their partitions and share m3's
+		for id := range members {
+			if !before[id].subsetOf(after[id]) {
+				t.Errorf("%s went from %s to %s, losing partitions", id, formatAssignment(before[id]), formatAssignment(after[id]))
+			}
+		}
+	})
+
+	t.Run("same members", func(t *testing.T) {
+		members := make(map[string]memberSpec)
+		for id, spec := range initial {
+			members[id] = withCurrent(spec, before[id])
+		}
+		after := uniformAssignor{}.assign(members, topics)
+		for id := range initial {
+			if !after[id].equal(before[id]) {
+				t.Errorf("%s went from %s to %s", id, formatAssignment(before[id]), formatAssignment(after[id]))
+			}
+		}
+	})
+}
+
+func TestRangeAssignor(t *testing.T) {
+	tests := []struct {
+		name    string
+		topics  map[protocol.UUID]subscribedTopic
+		members map[string]memberSpec
+		want    map[string]assignment
+	}{
+		{
+			// The first members take the extra partitions
+			name:   "uneven division",
+			topics: topicsOf(topicA),
+			members: map[string]memberSpec{
+				"m1": subscribing(topicA),
+				"m2": subscribing(topicA),
+				"m3": subscribing(topicA),
+			},
+			want: map[string]assignment{
+				"m1": partitionsOf(topicA, 0, 1),
+				"m2": partitionsOf(topicA, 2, 3),
+				"m3": partitionsOf(topicA, 4),
+			},
+		},
+		{
+			// Members get the same partition numbers of co-partitioned
+			// topics
+			name:   "co-partitioned topics",
+			topics: topicsOf(topicA, topicC),
+			members: map[string]memberSpec{
+				"m1": subscribing(topicA, topicC),
+				"m2": subscribing(topicA, topicC),
+			},
+			want: map[string]assignment{
+				"m1": union(partitionsOf(topicA, 0, 1, 2), partitionsOf(topicC, 0, 1, 2)),
+				"m2": union(partitionsOf(topicA, 3, 4), partitionsOf(topicC, 3, 4)),
+			},
+		},
+		{
+			// Each topic is divided among its own subscribers
+			name:   "different subscriptions",
+			topics: topicsOf(topicA, topicB),
+			members: map[string]memberSpec{
+				"m1": subscribing(topicA, topicB),
+				"m2": subscribing(topicB),
+				"m3": subscribing(topicA),
+			},
+			want: map[string]assignment{
+				"m1": union(partitionsOf(topicA, 0, 1, 2), partitionsOf(topicB, 0, 1)),
+				"m2": partitionsOf(topicB, 2, 3),
+				"m3": partitionsOf(topicA, 3, 4),
+			},
+		},
+		{
+			name:   "more members than partitions",
+			topics: topicsOf(subscribedTopic{id: protocol.UUID{4}, name: "d", partitions: 1}),
+			members: map[string]memberSpec{
+				"m1": subscribing(subscribedTopic{id: protocol.UUID{4}}),
+				"m2": subscribing(subscribedTopic{id: protocol.UUID{4}}),
+			},
+			want: map[string]assignment{
+				"m1": {protocol.UUID{4}: {0: true}},
+				"m2": {},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := rangeAssignor{}.assign(tt.members, tt.topics)
+			checkComplete(t, result, tt.members, tt.topics)
+			for id, want := range tt.want {
+				if !result[id].equal(want) {
+					t.Errorf("%s assigned %s, want %s", id, formatAssignment(result[id]), formatAssignment(want))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package group
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// state is the state of a classic group in the rebalance protocol
+type state int
+
+const (
+	// Empty groups have no members, but may still hold committed offsets
+	Empty state = iota
+	// PreparingRebalance groups wait for their members to rejoin
+	PreparingRebalance
+	// CompletingRebalance groups wait for the leader's assignment
+	CompletingRebalance
+	// Stable groups have handed out the assignment of their generation
+	Stable
+	// Dead groups have been removed from the coordinator
+	Dead
+)
+
+// validPreviousStates are the states each state may be entered from
+var validPreviousStates = map[state][]state{
+	Empty:               {PreparingRebalance},
+	PreparingRebalance:  {Stable, CompletingRebalance, Empty},
+	CompletingRebalance: {PreparingRebalance},
+	Stable:              {CompletingRebalance},
+	Dead:                {Stable, PreparingRebalance, CompletingRebalance, Empty, Dead},
+}
+
+// String returns the state's name as Kafka reports it
+func (s state) String() string {
+	switch s {
+	case Empty:
+		return "Empty"
+	case PreparingRebalance:
+		return "PreparingRebalance"
+	case CompletingRebalance:
+		return "CompletingRebalance"
+	case Stable:
+		return "Stable"
+	case Dead:
+		return "Dead"
+	}
+	return fmt.Sprintf("state(%d)", int(s))
+}
+
+// classicGroup is a group managed with the classic rebalance protocol,
+// where members rejoin through JoinGroup and the leader computes the
+// assignment that SyncGroup hands out
+type classicGroup struct {
+	id           string
+	state        state
+	generationID int32
+	protocolType *string
+	// protocolName is the protocol selected for the current generation
+	protocolName *string
+	leaderID     string
+
+	members map[string]*member
+	// staticMembers maps the group.instance.id of each static member to
+	// its current member ID
+	staticMembers map[string]string
+	// pendingMembers are dynamic members that were handed a member ID by
+	// MEMBER_ID_REQUIRED and have not rejoined with it yet
+	pendingMembers map[string]*time.Timer
+	// pendingSync are the members of the current generation that have not
+	// sent SyncGroup yet
+	pendingSync map[string]bool
+
+	// rebalance fires when the current join phase or sync phase is over;
+	// epoch invalidates the callbacks of timers that were replaced
+	rebalance *time.Timer
+	epoch     int
+	// initialJoin is set while the first rebalance of an empty group waits
+	// group.initial.rebalance.delay.ms for more members, and newMemberAdded
+	// while a member has joined since the delay last started
+	initialJoin    bool
+	newMemberAdded bool
+}
+
+// newClassicGroup creates an empty group
+func newClassicGroup(id string) *classicGroup {
+	return &classicGroup{
+		id:             id,
+		state:          Empty,
+		members:        make(map[string]*member),
+		staticMembers:  make(map[string]string),
+		pendingMembers: make(map[string]*time.Timer),
+		pendingSync:    make(map[string]bool),
+	}
+}
+
+// transitionTo moves the group to s. Invalid transitions are bugs in the
+// coordinator; they leave the group in its state and return an error.
+func (g *classicGroup) transitionTo(s state) error {
+	if !slices.Contains(validPreviousStates[s], g.state) {
+		return fmt.Errorf("group %s cannot move from %s to %s, only from one of %v",
+			g.id, g.state, s, validPreviousStates[s])
+	}
+	g.state = s
+	return nil
+}
+
+// isLeader reports whether memberID leads the group
+func (g *classicGroup) isLeader(memberID string) bool {
+	return g.leaderID != "" && g.leaderID == memberID
+}
+
+// sortedMembers returns the members ordered by member ID
+func (g *classicGroup) sortedMembers() []*member {
+	members := make([]*member, 0, len(g.members))
+	for _, m := range g.members {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].id < members[j].id })
+	return members
+}
+
+// add adds a member; the first member of an empty group decides the
+// protocol type and leads the group
+func (g *classicGroup) add(m *member) {
+	if len(g.members) == 0 {
+		g.protocolType = &m.protocolType
+	}
+	if g.leaderID == "" {
+		g.leaderID = m.id
+	}
+	g.members[m.id] = m
+	if m.instanceID != nil {
+		g.staticMembers[*m.instanceID] = m.id
+	}
+}
+
+// remove removes a member, handing leadership to another member if it led
+// the group
+func (g *classicGroup) remove(m *member) {
+	m.stopTimer()
+	delete(g.members, m.id)
+	delete(g.pendingSync, m.id)
+	if m.instanceID != nil && g.staticMembers[*m.instanceID] == m.id {
+		delete(g.staticMembers, *m.instanceID)
+	}
+	if g.isLeader(m.id) {
+		g.leaderID = ""
+		if members := g.sortedMembers(); len(members) > 0 {
+			g.leaderID = members[0].id
+		}
+	}
+}
+
+// replaceStaticMember moves a static member to a new member ID. Requests
+// still parked under the old ID are fenced.
+func (g *classicGroup) replaceStaticMember(instanceID, oldID, newID string) *member {
+	old := g.members[oldID]
+	old.completeJoin(joinError(oldID, protocol.ErrorFencedInstanceID))
+	old.completeSync(syncError(protocol.ErrorFencedInstanceID))
+	old.stopTimer()
+
+	m := *old
+	m.id = newID
+	m.join, m.sync, m.timer = nil, nil, nil
+	delete(g.members, oldID)
+	g.members[newID] = &m
+	g.staticMembers[instanceID] = newID
+	if g.pendingSync[oldID] {
+		delete(g.pendingSync, oldID)
+		g.pendingSync[newID] = true
+	}
+	if g.isLeader(oldID) {
+		g.leaderID = newID
+	}
+	return &m
+}
+
+// isStaticMemberFenced reports whether instanceID belongs to a static
+// member that has since rejoined under a member ID other than memberID
+func (g *classicGroup) isStaticMemberFenced(memberID string, instanceID *string) bool {
+	if instanceID == nil {
+		return false
+	}
+	current, ok := g.staticMembers[*instanceID]
+	return ok && current != memberID
+}
+
+// validateMember checks that a request comes from a current member
+func (g *classicGroup) validateMember(memberID string, instanceID *string) int16 {
+	if g.isStaticMemberFenced(memberID, instanceID) {
+		return protocol.ErrorFencedInstanceID
+	}
+	if _, ok := g.members[memberID]; !ok {
+		return protocol.ErrorUnknownMemberID
+	}
+	return protocol.ErrorNone
+}
+
+// supportsProtocols reports whether a member with the given protocols may
+// join: an empty group accepts any, otherwise the protocol type must match
+// and one of the protocols must be supported by every member
+func (g *classicGroup) supportsProtocols(protocolType string, protocols []protocol.JoinGroupRequestProtocol) bool {
+	if len(g.members) == 0 {
+		return protocolType != "" && len(protocols) > 0
+	}
+	if g.protocolType == nil || *g.protocolType != protocolType {
+		return false
+	}
+	for _, p := range protocols {
+		if g.allSupport(p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// allSupport reports whether every member supports the named protocol
+func (g *classicGroup) allSupport(name string) bool {
+	for _, m := range g.members {
+		if !m.supports(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectProtocol picks the protocol for the next generation. Each member
+// votes for its most preferred protocol among those every member
+// supports, and the protocol with the most votes wins.
+func (g *classicGroup) selectProtocol() string {
+	members := g.sortedMembers()
+	votes := make(map[string]int)
+	var candidates []string
+	for _, m := range members {
+		for _, p := range m.protocols {
+			if g.allSupport(p.Name) {
+				if votes[p.Name] == 0 {
+					candidates = append(candidates, p.Name)
+				}
+				votes[p.Name]++
+				break
+			}
+		}
+	}
+
+	var selected string
+	for _, name := range candidates {
+		if selected == "" || votes[name] > votes[selected] {
+			selected = name
+		}
+	}
+	return selected
+}
+
+// rebalanceTimeout is the longest rebalance timeout of the members, which
+// bounds how long the group waits for them to rejoin
+func (g *classicGroup) rebalanceTimeout() time.Duration {
+	var timeout time.Duration
+	for _, m := range g.members {
+		timeout = max(timeout, m.rebalanceTimeout)
+	}
+	return timeout
+}
+
+// allMembersJoined reports whether every member has rejoined, so the join
+// phase of a rebalance can complete early
+func (g *classicGroup) allMembersJoined() bool {
+	if len(g.pendingMembers) > 0 {
+		return false
+	}
+	for _, m := range g.members {
+		if m.join == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// initNextGeneration starts a new generation with the members that
+// rejoined, or moves the group to Empty if none did
+func (g *classicGroup) initNextGeneration() error {
+	g.generationID++
+	clear(g.pendingSync)
+	if len(g.members) == 0 {
+		g.protocolName = nil
+		return g.transitionTo(Empty)
+	}
+	name := g.selectProtocol()
+	g.protocolName = &name
+	return g.transitionTo(CompletingRebalance)
+}
+
+// memberMetadata lists the members with their metadata for the selected
+// protocol, as handed to the leader
+func (g *classicGroup) memberMetadata() []protocol.JoinGroupResponseMember {
+	var members []protocol.JoinGroupResponseMember
+	for _, m := range g.sortedMembers() {
+		members = append(members, protocol.JoinGroupResponseMember{
+			MemberId:        m.id,
+			GroupInstanceId: m.instanceID,
+			Metadata:        m.metadata(*g.protocolName),
+		})
+	}
+	return members
+}
+
+// joinResponse builds the JoinGroup response of the current generation
+// for a member; only the leader is sent the members to assign
+func (g *classicGroup) joinResponse(memberID string) *protocol.JoinGroupResponse {
+	resp := &protocol.JoinGroupResponse{}
+	resp.Default()
+	resp.ErrorCode = protocol.ErrorNone
+	resp.GenerationId = g.generationID
+	resp.ProtocolType = g.protocolType
+	resp.ProtocolName = g.protocolName
+	resp.Leader = g.leaderID
+	resp.MemberId = memberID
+	resp.Members = []protocol.JoinGroupResponseMember{}
+	if g.isLeader(memberID) {
+		resp.Members = g.memberMetadata()
+	}
+	return resp
+}
+
+// stopRebalanceTimer cancels the pending join or sync deadline
+func (g *classicGroup) stopRebalanceTimer() {
+	g.epoch++
+	if g.rebalance != nil {
+		g.rebalance.Stop()
+		g.rebalance = nil
+	}
+}
+
+// joinError builds a failed JoinGroup response
+func joinError(memberID string, errorCode int16) *protocol.JoinGroupResponse {
+	resp := &protocol.JoinGroupResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	resp.MemberId = memberID
+	resp.Members = []protocol.JoinGroupResponseMember{}
+	return resp
+}
+
+// syncError builds a failed SyncGroup response
+func syncError(errorCode int16) *protocol.SyncGroupResponse {
+	resp := &protocol.SyncGroupResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	resp.Assignment = []byte{}
+	return resp
+}
@@ -0,0 +1,38 @@
+package group
+
+import "testing"
+
+func TestTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to state
+		valid    bool
+	}{
+		{Empty, PreparingRebalance, true},
+		{PreparingRebalance, CompletingRebalance, true},
+		{CompletingRebalance, Stable, true},
+		{Stable, PreparingRebalance, true},
+		{PreparingRebalance, Empty, true},
+		{Stable, Dead, true},
+		{Empty, Stable, false},
+		{Empty, CompletingRebalance, false},
+		{Stable, CompletingRebalance, false},
+		{CompletingRebalance, Empty, false},
+		{Dead, PreparingRebalance, false},
+	}
+	for _, tt := range tests {
+		g := newClassicGroup("group")
+		g.state = tt.from
+		err := g.transitionTo(tt.to)
+		switch {
+		case tt.valid && err != nil:
+			t.Errorf("%s to %s: %v", tt.from, tt.to, err)
+		case tt.valid && g.state != tt.to:
+			t.Errorf("%s to %s: group is %s", tt.from, tt.to, g.state)
+		case !tt.valid && err == nil:
+			t.Errorf("%s to %s: no error", tt.from, tt.to)
+		case !tt.valid && g.state != tt.from:
+			// An invalid transition leaves the group where it was
+			t.Errorf("%s to %s: group moved to %s", tt.from, tt.to, g.state)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package group
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// Member epochs with a special meaning in ConsumerGroupHeartbeat requests
+const (
+	joinGroupMemberEpoch        int32 = 0
+	leaveGroupMemberEpoch       int32 = -1
+	leaveGroupStaticMemberEpoch int32 = -2
+)
+
+// consumerState is the state of a consumer group in the consumer rebalance
+// protocol (KIP-848). It is derived from the group's epochs and members.
+type consumerState int
+
+const (
+	consumerEmpty consumerState = iota
+	consumerAssigning
+	consumerReconciling
+	consumerStable
+	consumerDead
+)
+
+// String returns the state's name as Kafka reports it
+func (s consumerState) String() string {
+	switch s {
+	case consumerEmpty:
+		return "Empty"
+	case consumerAssigning:
+		return "Assigning"
+	case consumerReconciling:
+		return "Reconciling"
+	case consumerStable:
+		return "Stable"
+	case consumerDead:
+		return "Dead"
+	}
+	return fmt.Sprintf("consumerState(%d)", int(s))
+}
+
+// memberState is how far a consumer group member has reconciled its
+// assignment with the group's target assignment
+type memberState int
+
+const (
+	// memberStable members own their target assignment
+	memberStable memberState = iota
+	// memberUnrevokedPartitions members must revoke partitions before they
+	// move to the target epoch
+	memberUnrevokedPartitions
+	// memberUnreleasedPartitions members are at the target epoch but wait
+	// for other members to release some of their target partitions
+	memberUnreleasedPartitions
+)
+
+// consumerMember is a member of a consumer group
+type consumerMember struct {
+	id         string
+	instanceID *string
+	rackID     *string
+	clientID   string
+	clientHost string
+
+	// epoch is the member epoch; previousEpoch is the epoch before the
+	// last bump, still accepted in case the bumped response was lost
+	epoch         int32
+	previousEpoch int32
+	state         memberState
+
+	rebalanceTimeout     time.Duration
+	subscribedTopicNames []string
+	subscribedTopicRegex *string
+	// subscribedRegexp is subscribedTopicRegex compiled to match whole names
+	subscribedRegexp *regexp.Regexp
+	serverAssignor   *string
+
+	// assigned are the partitions the member may use; pendingRevocation
+	// are partitions it still owns but must give up
+	assigned          assignment
+	pendingRevocation assignment
+
+	// sessionDeadline is when the member is removed unless it heartbeats
+	// again; revocationDeadline, when set, is when it is fenced unless it
+	// has revoked its partitions
+	sessionDeadline    time.Time
+	sessionTimer       *time.Timer
+	revocationDeadline time.Time
+	revocationTimer    *time.Timer
+}
+
+// stopTimers cancels the member's session and revocation deadlines
+func (m *consumerMember) stopTimers() {
+	if m.sessionTimer != nil {
+		m.sessionTimer.Stop()
+	}
+	if m.revocationTimer != nil {
+		m.revocationTimer.Stop()
+	}
+}
+
+// consumerGroup is a group managed with the consumer rebalance protocol,
+// where members only heartbeat and the coordinator computes the
+// assignment and hands it out incrementally
+type consumerGroup struct {
+	id string
+	// groupEpoch is bumped whenever the membership, the subscriptions or the
+	// subscribed topics change; assignmentEpoch is the group epoch the
+	// target assignment was computed for
+	groupEpoch      int32
+	assignmentEpoch int32
+	dead            bool
+
+	members map[string]*consumerMember
+	// staticMembers maps the instance ID of each static member to its
+	// member ID
+	staticMembers map[string]string
+
+	// targetAssignment is the assignment each member reconciles towards
+	targetAssignment map[string]assignment
+	// owners maps each partition that a member is assigned or still has to
+	// revoke to that member
+	owners map[protocol.UUID]map[int32]string
+	// subscribedTopics are the topics the members subscribe to, as of the
+	// last heartbeat
+	subscribedTopics map[protocol.UUID]subscribedTopic
+	// assignorName is the assignor that computed the target assignment
+	assignorName string
+}
+
+// newConsumerGroup creates an empty consumer group
+func newConsumerGroup(id string) *consumerGroup {
+	return &consumerGroup{
+		id:               id,
+		members:          make(map[string]*consumerMember),
+		staticMembers:    make(map[string]string),
+		targetAssignment: make(map[string]assignment),
+		owners:           make(map[protocol.UUID]map[int32]string),
+		subscribedTopics: make(map[protocol.UUID]subscribedTopic),
+	}
+}
+
+// state derives the group's state from its epochs and members
+func (g *consumerGroup) state() consumerState {
+	switch {
+	case g.dead:
+		return consumerDead
+	case len(g.members) == 0:
+		return consumerEmpty
+	case g.groupEpoch > g.assignmentEpoch:
+		return consumerAssigning
+	}
+	for _, m := range g.members {
+		if m.epoch != g.assignmentEpoch || m.state != memberStable {
+			return consumerReconciling
+		}
+	}
+	return consumerStable
+}
+
+// sortedMembers returns the members ordered by member ID
+func (g *consumerGroup) sortedMembers() []*consumerMember {
+	members := make([]*consumerMember, 0, len(g.members))
+	for _, m := range g.members {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].id < members[j].id })
+	return members
+}
+
+// setOwnership records m as the owner of its assigned partitions and of
+// those pending revocation, or releases them if owned is false
+func (g *consumerGroup) setOwnership(m *consumerMember, owned bool) {
+	for _, a := range []assignment{m.assigned, m.pendingRevocation} {
+		for topic, partitions := range a {
+			for p := range partitions {
+				switch {
+				case owned:
+					if g.owners[topic] == nil {
+						g.owners[topic] = make(map[int32]string)
+					}
+					g.owners[topic][p] = m.id
+				case g.owners[topic][p] == m.id:
+					delete(g.owners[topic], p)
+				}
+			}
+		}
+	}
+}
+
+// isFree reports whether a partition may be handed to member id: no other
+// member owns it, or still has to revoke it
+func (g *consumerGroup) isFree(topic protocol.UUID, partition int32, id string) bool {
+	owner := g.owners[topic][partition]
+	return owner == "" || owner == id
+}
+
+// update replaces a member's assignment state, keeping the partition
+// owners in step
+func (g *consumerGroup) update(m *consumerMember, next consumerMember) {
+	g.setOwnership(m, false)
+	*m = next
+	g.setOwnership(m, true)
+}
+
+// add adds a new member
+func (g *consumerGroup) add(m *consumerMember) {
+	g.members[m.id] = m
+	if m.instanceID != nil {
+		g.staticMembers[*m.instanceID] = m.id
+	}
+	g.setOwnership(m, true)
+}
+
+// remove removes a member, releasing its partitions
+func (g *consumerGroup) remove(m *consumerMember) {
+	m.stopTimers()
+	g.setOwnership(m, false)
+	delete(g.members, m.id)
+	delete(g.targetAssignment, m.id)
+	if m.instanceID != nil && g.staticMembers[*m.instanceID] == m.id {
+		delete(g.staticMembers, *m.instanceID)
+	}
+}
+
+// preferredAssignor picks the assignor for the next target assignment:
+// the one most members ask for, or the default if none asks for any
+func (g *consumerGroup) preferredAssignor(defaultName string) string {
+	votes := make(map[string]int)
+	for _, m := range g.members {
+		if m.serverAssignor != nil {
+			votes[*m.serverAssignor]++
+		}
+	}
+	names := make([]string, 0, len(votes))
+	for name := range votes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	preferred, most := defaultName, 0
+	for _, name := range names {
+		if votes[name] > most {
+			preferred, most = name, votes[name]
+		}
+	}
+	return preferred
+}
+
+// computeTargetAssignment runs the named assignor over the members, given
+// the topics each subscribes to, and moves the assignment epoch to the
+// group epoch
+func (g *consumerGroup) computeTargetAssignment(name string, subscriptions map[string]map[protocol.UUID]bool) {
+	members := make(map[string]memberSpec, len(g.members))
+	for id := range g.members {
+		members[id] = memberSpec{topics: subscriptions[id], current: g.targetAssignment[id]}
+	}
+	g.targetAssignment = assignors[name].assign(members, g.subscribedTopics)
+	g.assignmentEpoch = g.groupEpoch
+	g.assignorName = name
+}
+
+// reconcile moves a member towards its target assignment, given the
+// partitions the client reports owning (nil if it did not report them). It
+// returns the member's next state without applying it.
+func (g *consumerGroup) reconcile(m *consumerMember, owned assignment) consumerMember {
+	switch m.state {
+	case memberStable:
+		if m.epoch == g.assignmentEpoch {
+			return *m
+		}
+	case memberUnrevokedPartitions:
+		if ownsAny(m.pendingRevocation, owned) {
+			return *m
+		}
+	}
+	return g.nextAssignment(m, owned)
+}
+
+// nextAssignment computes the member's next assignment. Partitions that
+// left its target are revoked first, at its current epoch; once none is
+// left the member moves to the assignment epoch with the target partitions
+// that other members have released.
+func (g *consumerGroup) nextAssignment(m *consumerMember, owned assignment) consumerMember {
+	target := g.targetAssignment[m.id]
+	next := *m
+	next.assigned = assignment{}
+	next.pendingRevocation = assignment{}
+	pending := assignment{}
+	unreleased := false
+	for topic, partitions := range m.assigned {
+		for p := range partitions {
+			if target.contains(topic, p) {
+				next.assigned.add(topic, p)
+			} else {
+				next.pendingRevocation.add(topic, p)
+			}
+		}
+	}
+	for topic, partitions := range target {
+		for p := range partitions {
+			switch {
+			case m.assigned.contains(topic, p):
+			case g.isFree(topic, p, m.id):
+				pending.add(topic, p)
+			default:
+				unreleased = true
+			}
+		}
+	}
+
+	next.previousEpoch = m.epoch
+	if len(next.pendingRevocation) > 0 && ownsAny(next.pendingRevocation, owned) {
+		next.state = memberUnrevokedPartitions
+		return next
+	}
+	for topic, partitions := range pending {
+		for p := range partitions {
+			next.assigned.add(topic, p)
+		}
+	}
+	next.pendingRevocation = assignment{}
+	next.epoch = g.assignmentEpoch
+	next.state = memberStable
+	if unreleased {
+		next.state = memberUnreleasedPartitions
+	}
+	return next
+}
+
+// ownsAny reports whether a client that reported owning owned still owns
+// any of partitions. A client that did not report is assumed to own them.
+func ownsAny(partitions, owned assignment) bool {
+	if owned == nil {
+		return true
+	}
+	for topic, ps := range partitions {
+		for p := range ps {
+			if owned.contains(topic, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameSubscription reports whether a member's subscription is unchanged
+func sameSubscription(m *consumerMember, names []string, regex *string) bool {
+	return slices.Equal(m.subscribedTopicNames, names) &&
+		(m.subscribedTopicRegex == nil) == (regex == nil) &&
+		(regex == nil || *m.subscribedTopicRegex == *regex)
+}
@@ -0,0 +1,545 @@
+package group
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// ConsumerGroupHeartbeat handles a heartbeat of the consumer rebalance
+// protocol: it adds, updates or removes the member, recomputes the target
+// assignment when the group epoch moved, and reconciles the member's
+// assignment one step towards its target.
+//
+// Membership is not written to the offsets topic, so after a restart a
+// member heartbeating with an epoch the coordinator does not know gets
+// UNKNOWN_MEMBER_ID and rejoins, rather than GROUP_ID_NOT_FOUND.
+func (c *Coordinator) ConsumerGroupHeartbeat(ctx RequestContext, req *protocol.ConsumerGroupHeartbeatRequest) *protocol.ConsumerGroupHeartbeatResponse {
+	regex, errorCode, message := c.validateConsumerGroupHeartbeat(ctx.APIVersion, req)
+	if errorCode != protocol.ErrorNone {
+		return consumerHeartbeatError(errorCode, message)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return consumerHeartbeatError(protocol.ErrorNotCoordinator, "")
+	}
+	if req.MemberEpoch < joinGroupMemberEpoch {
+		return c.leaveConsumerGroup(req)
+	}
+
+	g, errorCode, message := c.consumerGroup(req.GroupId, req.MemberEpoch == joinGroupMemberEpoch)
+	if errorCode != protocol.ErrorNone {
+		return consumerHeartbeatError(errorCode, message)
+	}
+	owned := ownedAssignment(req.TopicPartitions)
+
+	var m *consumerMember
+	changed := false
+	if g != nil {
+		m = g.members[req.MemberId]
+	}
+	if req.MemberEpoch == joinGroupMemberEpoch {
+		if req.MemberId == "" {
+			// Version 0 lets the coordinator pick the member ID
+			req.MemberId = protocol.RandomUUID().Base64()
+		}
+		// A new member changes the group, while a rejoining member or a
+		// static member taking over its old partitions does not
+		changed = m == nil && (req.InstanceId == nil || g.staticMembers[*req.InstanceId] == "")
+		if m, errorCode, message = c.joinConsumerGroup(g, req); errorCode != protocol.ErrorNone {
+			return consumerHeartbeatError(errorCode, message)
+		}
+	} else {
+		if m == nil {
+			return consumerHeartbeatError(protocol.ErrorUnknownMemberID,
+				fmt.Sprintf("Member %s is not a member of group %s.", req.MemberId, req.GroupId))
+		}
+		if errorCode, message := validateMemberEpoch(m, req.MemberEpoch, owned); errorCode != protocol.ErrorNone {
+			return consumerHeartbeatError(errorCode, message)
+		}
+	}
+
+	m.clientID, m.clientHost = ctx.ClientID, ctx.ClientHost
+	if req.RackId != nil {
+		m.rackID = req.RackId
+	}
+	if req.RebalanceTimeoutMs >= 0 {
+		m.rebalanceTimeout = time.Duration(req.RebalanceTimeoutMs) * time.Millisecond
+	}
+	if updateSubscription(m, req, regex) {
+		changed = true
+	}
+	if req.ServerAssignor != nil && (m.serverAssignor == nil || *m.serverAssignor != *req.ServerAssignor) {
+		m.serverAssignor = req.ServerAssignor
+		changed = true
+	}
+
+	subscriptions, topicsChanged := c.resolveSubscriptions(g)
+	if changed || topicsChanged {
+		g.groupEpoch++
+	}
+	if g.groupEpoch > g.assignmentEpoch {
+		name := g.preferredAssignor(c.config.ConsumerAssignors[0])
+		g.computeTargetAssignment(name, subscriptions)
+		c.logger.Debug("Computed target assignment of group %s at epoch %d with the %s assignor", g.id, g.groupEpoch, name)
+	}
+
+	before, wasUnrevoked := m.assigned, m.state == memberUnrevokedPartitions
+	g.update(m, g.reconcile(m, owned))
+	c.scheduleConsumerSession(g, m)
+	c.scheduleRevocation(g, m, wasUnrevoked)
+
+	resp := &protocol.ConsumerGroupHeartbeatResponse{}
+	resp.Default()
+	resp.ErrorCode = protocol.ErrorNone
+	resp.MemberId = &m.id
+	resp.MemberEpoch = m.epoch
+	resp.HeartbeatIntervalMs = int32(c.config.ConsumerHeartbeatInterval.Milliseconds())
+	// A full request, sent on joining or after an error, always gets the
+	// assignment; otherwise it is only sent when it changed
+	full := req.MemberEpoch == joinGroupMemberEpoch ||
+		req.RebalanceTimeoutMs != -1 && (req.SubscribedTopicNames != nil || req.SubscribedTopicRegex != nil) && req.TopicPartitions != nil
+	if full || !before.equal(m.assigned) {
+		resp.Assignment = heartbeatAssignment(m.assigned)
+	}
+	return resp
+}
+
+// validateConsumerGroupHeartbeat checks the fields a heartbeat must or
+// must not carry for its member epoch, and compiles its topic regex
+func (c *Coordinator) validateConsumerGroupHeartbeat(version int16, req *protocol.ConsumerGroupHeartbeatRequest) (*regexp.Regexp, int16, string) {
+	invalid := func(message string) (*regexp.Regexp, int16, string) {
+		return nil, protocol.ErrorInvalidRequest, message
+	}
+	switch {
+	case req.GroupId == "":
+		return invalid("GroupId can't be empty.")
+	case req.InstanceId != nil && *req.InstanceId == "":
+		return invalid("InstanceId can't be empty.")
+	case req.RackId != nil && *req.RackId == "":
+		return invalid("RackId can't be empty.")
+	case req.MemberEpoch < leaveGroupStaticMemberEpoch:
+		return invalid("MemberEpoch is invalid.")
+	case req.MemberId == "" && (version >= 1 || req.MemberEpoch != joinGroupMemberEpoch):
+		return invalid("MemberId can't be empty.")
+	case req.MemberEpoch == leaveGroupStaticMemberEpoch && req.InstanceId == nil:
+		return invalid("InstanceId can't be null.")
+	}
+	if req.MemberEpoch == joinGroupMemberEpoch {
+		switch {
+		case req.RebalanceTimeoutMs == -1:
+			return invalid("RebalanceTimeoutMs must be provided in first request.")
+		case len(req.TopicPartitions) > 0 || req.TopicPartitions == nil:
+			return invalid("TopicPartitions must be empty when (re-)joining.")
+		case req.SubscribedTopicNames == nil && req.SubscribedTopicRegex == nil:
+			return invalid("SubscribedTopicNames or SubscribedTopicRegex must be set in first request.")
+		}
+	}
+
+	if req.ServerAssignor != nil && !slices.Contains(c.config.ConsumerAssignors, *req.ServerAssignor) {
+		return nil, protocol.ErrorUnsupportedAssignor, fmt.Sprintf("ServerAssignor %s is not supported. Supported assignors: %s.",
+			*req.ServerAssignor, strings.Join(c.config.ConsumerAssignors, ", "))
+	}
+
+	if req.SubscribedTopicRegex == nil || *req.SubscribedTopicRegex == "" {
+		return nil, protocol.ErrorNone, ""
+	}
+	// Kafka matches the whole topic name with RE2J, whose syntax is Go's
+	regex, err := regexp.Compile("^(?:" + *req.SubscribedTopicRegex + ")$")
+	if err != nil {
+		return nil, protocol.ErrorInvalidRegularExpression,
+			fmt.Sprintf("SubscribedTopicRegex `%s` is not a valid regular expression: %s.", *req.SubscribedTopicRegex, err.Error())
+	}
+	return regex, protocol.ErrorNone, ""
+}
+
+// consumerGroup returns the consumer group with the given ID. With create
+// set a missing group is created, taking over an empty classic group of
+// the same ID; otherwise a missing group is returned as nil.
+func (c *Coordinator) consumerGroup(groupID string, create bool) (*consumerGroup, int16, string) {
+	if g := c.consumerGroups[groupID]; g != nil {
+		return g, protocol.ErrorNone, ""
+	}
+	if classic := c.groups[groupID]; classic != nil {
+		if !create || classic.state != Empty || len(classic.pendingMembers) > 0 {
+			return nil, protocol.ErrorGroupIDNotFound, fmt.Sprintf("Group %s is not a consumer group.", groupID)
+		}
+		c.transition(classic, Dead)
+		delete(c.groups, groupID)
+		c.logger.Info("Converting empty classic group %s to a consumer group", groupID)
+	} else if !create {
+		return nil, protocol.ErrorNone, ""
+	}
+
+	g := newConsumerGroup(groupID)
+	c.consumerGroups[groupID] = g
+	return g, protocol.ErrorNone, ""
+}
+
+// joinConsumerGroup returns the member a heartbeat with epoch 0 joins as:
+// the member itself if it is rejoining, the static member it replaces, or
+// a new member
+func (c *Coordinator) joinConsumerGroup(g *consumerGroup, req *protocol.ConsumerGroupHeartbeatRequest) (*consumerMember, int16, string) {
+	if req.InstanceId != nil {
+		if oldID, ok := g.staticMembers[*req.InstanceId]; ok && oldID != req.MemberId {
+			old := g.members[oldID]
+			if old.epoch != leaveGroupStaticMemberEpoch {
+				return nil, protocol.ErrorUnreleasedInstanceID, fmt.Sprintf(
+					"Static member %s with instance id %s cannot join the group because the instance id is owned by member %s.",
+					req.MemberId, *req.InstanceId, oldID)
+			}
+			return c.replaceStaticConsumerMember(g, old, req.MemberId), protocol.ErrorNone, ""
+		}
+	}
+	if m := g.members[req.MemberId]; m != nil {
+		return m, protocol.ErrorNone, ""
+	}
+
+	if len(g.members) >= c.config.ConsumerMaxSize {
+		return nil, protocol.ErrorGroupMaxSizeReached,
+			fmt.Sprintf("The consumer group has reached its maximum capacity of %d members.", c.config.ConsumerMaxSize)
+	}
+	m := &consumerMember{
+		id:                req.MemberId,
+		instanceID:        req.InstanceId,
+		previousEpoch:     -1,
+		assigned:          assignment{},
+		pendingRevocation: assignment{},
+	}
+	g.add(m)
+	c.logger.Info("Member %s joined consumer group %s", m.id, g.id)
+	return m, protocol.ErrorNone, ""
+}
+
+// replaceStaticConsumerMember hands the partitions and target assignment
+// of a static member that left temporarily to the member rejoining under
+// its instance ID, so that the group does not rebalance
+func (c *Coordinator) replaceStaticConsumerMember(g *consumerGroup, old *consumerMember, newID string) *consumerMember {
+	target := g.targetAssignment[old.id]
+	g.remove(old)
+
+	m := *old
+	m.id = newID
+	m.epoch, m.previousEpoch = joinGroupMemberEpoch, -1
+	m.sessionTimer, m.revocationTimer = nil, nil
+	m.revocationDeadline = time.Time{}
+	g.add(&m)
+	if target != nil {
+		g.targetAssignment[newID] = target
+	}
+	c.logger.Info("Static member %s of consumer group %s rejoined as %s", *m.instanceID, g.id, newID)
+	return &m
+}
+
+// validateMemberEpoch checks the epoch a member heartbeats with. The
+// previous epoch is accepted as long as the member owns no partition
+// beyond its assignment, as the response that bumped it may have been lost.
+func validateMemberEpoch(m *consumerMember, epoch int32, owned assignment) (int16, string) {
+	if epoch > m.epoch {
+		return protocol.ErrorFencedMemberEpoch, fmt.Sprintf(
+			"The consumer group member has a greater member epoch (%d) than the one known by the group coordinator (%d). The member must abandon all its partitions and rejoin.",
+			epoch, m.epoch)
+	}
+	if epoch < m.epoch && (epoch != m.previousEpoch || owned == nil || !owned.subsetOf(m.assigned)) {
+		return protocol.ErrorFencedMemberEpoch, fmt.Sprintf(
+			"The consumer group member has a smaller member epoch (%d) than the one known by the group coordinator (%d). The member must abandon all its partitions and rejoin.",
+			epoch, m.epoch)
+	}
+	return protocol.ErrorNone, ""
+}
+
+// updateSubscription applies the subscription of a heartbeat to a member,
+// reporting whether it changed. Fields left null keep their value, and an
+// empty regex drops the regex subscription.
+func updateSubscription(m *consumerMember, req *protocol.ConsumerGroupHeartbeatRequest, regex *regexp.Regexp) bool {
+	names, pattern := m.subscribedTopicNames, m.subscribedTopicRegex
+	if req.SubscribedTopicNames != nil {
+		names = slices.Clone(req.SubscribedTopicNames)
+		sort.Strings(names)
+		names = slices.Compact(names)
+	}
+	if req.SubscribedTopicRegex != nil {
+		pattern = req.SubscribedTopicRegex
+		if *pattern == "" {
+			pattern = nil
+		}
+	}
+	if sameSubscription(m, names, pattern) {
+		return false
+	}
+	m.subscribedTopicNames, m.subscribedTopicRegex = names, pattern
+	if req.SubscribedTopicRegex != nil {
+		m.subscribedRegexp = regex
+	}
+	return true
+}
+
+// resolveSubscriptions resolves the subscription of each member to the
+// topics that exist, and reports whether the group's subscribed topics or
+// their partition counts changed since the last heartbeat
+func (c *Coordinator) resolveSubscriptions(g *consumerGroup) (map[string]map[protocol.UUID]bool, bool) {
+	subscriptions := make(map[string]map[protocol.UUID]bool, len(g.members))
+	topics := make(map[protocol.UUID]subscribedTopic)
+	subscribe := func(m *consumerMember, name string) {
+		t := c.metadata.TopicByName(name)
+		if t == nil {
+			return
+		}
+		subscriptions[m.id][t.ID] = true
+		topics[t.ID] = subscribedTopic{id: t.ID, name: t.Name, partitions: int32(len(t.Partitions))}
+	}
+
+	for _, m := range g.members {
+		subscriptions[m.id] = make(map[protocol.UUID]bool)
+		for _, name := range m.subscribedTopicNames {
+			subscribe(m, name)
+		}
+		if m.subscribedRegexp != nil {
+			for _, t := range c.metadata.Topics() {
+				if !t.IsInternal() && m.subscribedRegexp.MatchString(t.Name) {
+					subscribe(m, t.Name)
+				}
+			}
+		}
+	}
+
+	if maps.Equal(topics, g.subscribedTopics) {
+		return subscriptions, false
+	}
+	g.subscribedTopics = topics
+	return subscriptions, true
+}
+
+// leaveConsumerGroup handles a heartbeat with a negative member epoch. A
+// dynamic member leaves for good; a static member keeps its partitions
+// until it rejoins under the same instance ID or its session runs out.
+func (c *Coordinator) leaveConsumerGroup(req *protocol.ConsumerGroupHeartbeatRequest) *protocol.ConsumerGroupHeartbeatResponse {
+	var m *consumerMember
+	g := c.consumerGroups[req.GroupId]
+	if g != nil {
+		m = g.members[req.MemberId]
+		if m == nil && req.InstanceId != nil {
+			m = g.members[g.staticMembers[*req.InstanceId]]
+		}
+	}
+	if m == nil {
+		return consumerHeartbeatError(protocol.ErrorUnknownMemberID,
+			fmt.Sprintf("Member %s is not a member of group %s.", req.MemberId, req.GroupId))
+	}
+
+	if req.MemberEpoch == leaveGroupStaticMemberEpoch {
+		m.epoch = leaveGroupStaticMemberEpoch
+		c.logger.Info("Static member %s of consumer group %s left temporarily", m.id, g.id)
+	} else {
+		c.removeConsumerMember(g, m)
+		c.logger.Info("Member %s left consumer group %s", m.id, g.id)
+	}
+
+	resp := &protocol.ConsumerGroupHeartbeatResponse{}
+	resp.Default()
+	resp.ErrorCode = protocol.ErrorNone
+	resp.MemberId = &req.MemberId
+	resp.MemberEpoch = req.MemberEpoch
+	return resp
+}
+
+// removeConsumerMember removes a member and bumps the group epoch, so the
+// other members get its partitions
+func (c *Coordinator) removeConsumerMember(g *consumerGroup, m *consumerMember) {
+	g.remove(m)
+	g.groupEpoch++
+}
+
+// scheduleConsumerSession restarts a member's session. A member whose
+// session runs out without a heartbeat is removed from the group.
+func (c *Coordinator) scheduleConsumerSession(g *consumerGroup, m *consumerMember) {
+	timeout := c.config.ConsumerSessionTimeout
+	m.sessionDeadline = time.Now().Add(timeout)
+	if m.sessionTimer != nil {
+		m.sessionTimer.Reset(timeout)
+		return
+	}
+	m.sessionTimer = time.AfterFunc(timeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if g.members[m.id] != m || c.closed {
+			return
+		}
+		if remaining := time.Until(m.sessionDeadline); remaining > 0 {
+			m.sessionTimer.Reset(remaining)
+			return
+		}
+		c.logger.Info("Member %s in consumer group %s has failed, removing it from the group", m.id, g.id)
+		c.removeConsumerMember(g, m)
+	})
+}
+
+// scheduleRevocation bounds how long a member may take to revoke its
+// partitions by its rebalance timeout. A member that does not revoke them
+// in time is fenced, so that their new owners are not held up forever.
+func (c *Coordinator) scheduleRevocation(g *consumerGroup, m *consumerMember, wasUnrevoked bool) {
+	if m.state != memberUnrevokedPartitions {
+		m.revocationDeadline = time.Time{}
+		if m.revocationTimer != nil {
+			m.revocationTimer.Stop()
+		}
+		return
+	}
+	if wasUnrevoked {
+		return
+	}
+
+	m.revocationDeadline = time.Now().Add(m.rebalanceTimeout)
+	if m.revocationTimer != nil {
+		m.revocationTimer.Reset(m.rebalanceTimeout)
+		return
+	}
+	m.revocationTimer = time.AfterFunc(m.rebalanceTimeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if g.members[m.id] != m || c.closed || m.state != memberUnrevokedPartitions {
+			return
+		}
+		if remaining := time.Until(m.revocationDeadline); remaining > 0 {
+			m.revocationTimer.Reset(remaining)
+			return
+		}
+		c.logger.Info("Member %s in consumer group %s failed to revoke partitions within %s, removing it from the group",
+			m.id, g.id, m.rebalanceTimeout)
+		c.removeConsumerMember(g, m)
+	})
+}
+
+// DescribeConsumerGroups describes the consumer groups with the given IDs
+func (c *Coordinator) DescribeConsumerGroups(groupIDs []string) []protocol.ConsumerGroupDescribeResponseDescribedGroup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	described := make([]protocol.ConsumerGroupDescribeResponseDescribedGroup, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		result := protocol.ConsumerGroupDescribeResponseDescribedGroup{}
+		result.Default()
+		result.GroupId = id
+		g := c.consumerGroups[id]
+		switch {
+		case c.closed:
+			result.ErrorCode = protocol.ErrorNotCoordinator
+		case id == "":
+			result.ErrorCode = protocol.ErrorInvalidGroupID
+		case g == nil && c.groups[id] != nil:
+			result.ErrorCode = protocol.ErrorGroupIDNotFound
+			message := fmt.Sprintf("Group %s is not a consumer group.", id)
+			result.ErrorMessage = &message
+		case g == nil:
+			result.ErrorCode = protocol.ErrorGroupIDNotFound
+			message := fmt.Sprintf("Group %s not found.", id)
+			result.ErrorMessage = &message
+		default:
+			c.describeConsumerGroup(g, &result)
+		}
+		described = append(described, result)
+	}
+	return described
+}
+
+// describeConsumerGroup fills in the description of a consumer group
+func (c *Coordinator) describeConsumerGroup(g *consumerGroup, result *protocol.ConsumerGroupDescribeResponseDescribedGroup) {
+	result.ErrorCode = protocol.ErrorNone
+	result.GroupState = g.state().String()
+	result.GroupEpoch = g.groupEpoch
+	result.AssignmentEpoch = g.assignmentEpoch
+	result.AssignorName = g.assignorName
+	if result.AssignorName == "" {
+		result.AssignorName = g.preferredAssignor(c.config.ConsumerAssignors[0])
+	}
+	result.Members = []protocol.ConsumerGroupDescribeResponseMember{}
+	for _, m := range g.sortedMembers() {
+		member := protocol.ConsumerGroupDescribeResponseMember{}
+		member.Default()
+		member.MemberId = m.id
+		member.InstanceId = m.instanceID
+		member.RackId = m.rackID
+		member.MemberEpoch = m.epoch
+		member.ClientId = m.clientID
+		member.ClientHost = m.clientHost
+		member.SubscribedTopicNames = m.subscribedTopicNames
+		if member.SubscribedTopicNames == nil {
+			member.SubscribedTopicNames = []string{}
+		}
+		member.SubscribedTopicRegex = m.subscribedTopicRegex
+		member.Assignment = c.describedAssignment(g, m.assigned)
+		member.TargetAssignment = c.describedAssignment(g, g.targetAssignment[m.id])
+		member.MemberType = 1
+		result.Members = append(result.Members, member)
+	}
+}
+
+// describedAssignment lists an assignment with the names of its topics
+func (c *Coordinator) describedAssignment(g *consumerGroup, a assignment) protocol.ConsumerGroupDescribeResponseAssignment {
+	described := protocol.ConsumerGroupDescribeResponseAssignment{
+		TopicPartitions: []protocol.ConsumerGroupDescribeResponseTopicPartitions{},
+	}
+	for _, topic := range a.topics() {
+		name := g.subscribedTopics[topic].name
+		if t := c.metadata.TopicByID(topic); t != nil {
+			name = t.Name
+		}
+		described.TopicPartitions = append(described.TopicPartitions, protocol.ConsumerGroupDescribeResponseTopicPartitions{
+			TopicId:    topic,
+			TopicName:  name,
+			Partitions: a.partitions(topic),
+		})
+	}
+	return described
+}
+
+// ownedAssignment converts the partitions a heartbeat reports owning, or
+// returns nil if it did not report them
+func ownedAssignment(topics []protocol.ConsumerGroupHeartbeatRequestTopicPartitions) assignment {
+	if topics == nil {
+		return nil
+	}
+	owned := assignment{}
+	for _, t := range topics {
+		for _, p := range t.Partitions {
+			owned.add(t.TopicId, p)
+		}
+	}
+	return owned
+}
+
+// heartbeatAssignment converts an assignment for a heartbeat response
+func heartbeatAssignment(a assignment) *protocol.ConsumerGroupHeartbeatResponseAssignment {
+	resp := &protocol.ConsumerGroupHeartbeatResponseAssignment{
+		TopicPartitions: []protocol.ConsumerGroupHeartbeatResponseTopicPartitions{},
+	}
+	for _, topic := range a.topics() {
+		resp.TopicPartitions = append(resp.TopicPartitions, protocol.ConsumerGroupHeartbeatResponseTopicPartitions{
+			TopicId:    topic,
+			Partitions: a.partitions(topic),
+		})
+	}
+	return resp
+}
+
+// consumerHeartbeatError builds a failed ConsumerGroupHeartbeat response
+func consumerHeartbeatError(errorCode int16, message string) *protocol.ConsumerGroupHeartbeatResponse {
+	resp := &protocol.ConsumerGroupHeartbeatResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	if message != "" {
+		resp.ErrorMessage = &message
+	}
+	return resp
+}
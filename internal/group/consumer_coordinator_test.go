@@ -0,0 +1,240 @@
+package group
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// testTopic is the topic of the coordinator tests, with testPartitions
+// partitions
+const (
+	testTopic      = "events"
+	testPartitions = 4
+	testGroup      = "group"
+)
+
+// testContext is the client connection of the coordinator tests
+var testContext = RequestContext{ClientID: "client", ClientHost: "/127.0.0.1", APIVersion: 1}
+
+// newTestCoordinator returns a coordinator with config that assigns
+// testTopic, and the ID of the topic. It has no offsets topic.
+func newTestCoordinator(t *testing.T, config Config) (*Coordinator, protocol.UUID) {
+	t.Helper()
+	image := metadata.NewImage()
+	id := protocol.RandomUUID()
+	records := []protocol.Message{&protocol.TopicRecord{Name: testTopic, TopicId: id}}
+	for p := int32(0); p < testPartitions; p++ {
+		records = append(records, &protocol.PartitionRecord{TopicId: id, PartitionId: p, Replicas: []int32{1}, Isr: []int32{1}, Leader: 1})
+	}
+	if err := image.Publish(records...); err != nil {
+		t.Fatal(err)
+	}
+	c := New(config, image, nil, logger.New(logger.ERROR))
+	t.Cleanup(c.Close)
+	return c, id
+}
+
+// joinConsumer sends the first heartbeat of a member subscribing to
+// testTopic
+func joinConsumer(c *Coordinator, memberID string) *protocol.ConsumerGroupHeartbeatResponse {
+	req := &protocol.ConsumerGroupHeartbeatRequest{}
+	req.Default()
+	req.GroupId = testGroup
+	req.MemberId = memberID
+	req.RebalanceTimeoutMs = 60000
+	req.SubscribedTopicNames = []string{testTopic}
+	req.TopicPartitions = []protocol.ConsumerGroupHeartbeatRequestTopicPartitions{}
+	return c.ConsumerGroupHeartbeat(testContext, req)
+}
+
+// heartbeatConsumer sends a heartbeat of a member at epoch, reporting the
+// partitions it owns unless owned is nil
+func heartbeatConsumer(c *Coordinator, memberID string, epoch int32, owned assignment) *protocol.ConsumerGroupHeartbeatResponse {
+	req := &protocol.ConsumerGroupHeartbeatRequest{}
+	req.Default()
+	req.GroupId = testGroup
+	req.MemberId = memberID
+	req.MemberEpoch = epoch
+	if owned != nil {
+		req.TopicPartitions = []protocol.ConsumerGroupHeartbeatRequestTopicPartitions{}
+	}
+	for _, topic := range owned.topics() {
+		req.TopicPartitions = append(req.TopicPartitions, protocol.ConsumerGroupHeartbeatRequestTopicPartitions{
+			TopicId:    topic,
+			Partitions: owned.partitions(topic),
+		})
+	}
+	return c.ConsumerGroupHeartbeat(testContext, req)
+}
+
+// responseAssignment returns the assignment of a heartbeat response, or
+// nil if it has none
+func responseAssignment(resp *protocol.ConsumerGroupHeartbeatResponse) assignment {
+	if resp.Assignment == nil {
+		return nil
+	}
+	a := assignment{}
+	for _, t := range resp.Assignment.TopicPartitions {
+		for _, p := range t.Partitions {
+			a.add(t.TopicId, p)
+		}
+	}
+	return a
+}
+
+// allPartitions returns the assignment of every partition of topic
+func allPartitions(topic protocol.UUID) assignment {
+	a := assignment{}
+	for p := int32(0); p < testPartitions; p++ {
+		a.add(topic, p)
+	}
+	return a
+}
+
+// checkHeartbeat fails the test unless resp succeeded at epoch
+func checkHeartbeat(t *testing.T, resp *protocol.ConsumerGroupHeartbeatResponse, epoch int32) {
+	t.Helper()
+	if resp.ErrorCode != protocol.ErrorNone {
+		t.Fatalf("heartbeat failed with error %d", resp.ErrorCode)
+	}
+	if resp.MemberEpoch != epoch {
+		t.Errorf("member epoch = %d, want %d", resp.MemberEpoch, epoch)
+	}
+}
+
+func TestConsumerHeartbeatRevokesBeforeAssigning(t *testing.T) {
+	c, topic := newTestCoordinator(t, DefaultConfig())
+	all := allPartitions(topic)
+
+	resp := joinConsumer(c, "m1")
+	checkHeartbeat(t, resp, 1)
+	if got := responseAssignment(resp); !got.equal(all) {
+		t.Fatalf("m1 assigned %s, want every partition", formatAssignment(got))
+	}
+
+	// m2 joins, but the partitions its target takes from m1 are not free
+	// until m1 has revoked them
+	resp = joinConsumer(c, "m2")
+	checkHeartbeat(t, resp, 2)
+	if got := responseAssignment(resp); got == nil || got.size() != 0 {
+		t.Fatalf("m2 assigned %s on joining, want nothing yet", formatAssignment(got))
+	}
+
+	// m1 is told to revoke first, keeping its epoch until it has
+	resp = heartbeatConsumer(c, "m1", 1, all)
+	checkHeartbeat(t, resp, 1)
+	kept := responseAssignment(resp)
+	if kept == nil || kept.size() != testPartitions/2 || !kept.subsetOf(all) {
+		t.Fatalf("m1 assigned %s while revoking, want half its partitions", formatAssignment(kept))
+	}
+	revoked := assignment{}
+	for p := int32(0); p < testPartitions; p++ {
+		if !kept.contains(topic, p) {
+			revoked.add(topic, p)
+		}
+	}
+	resp = heartbeatConsumer(c, "m2", 2, assignment{})
+	checkHeartbeat(t, resp, 2)
+	if got := responseAssignment(resp); got != nil && got.size() != 0 {
+		t.Errorf("m2 assigned %s before m1 revoked it", formatAssignment(got))
+	}
+
+	// Once m1 reports the partitions gone it moves to the new epoch, and
+	// they are handed to m2
+	resp = heartbeatConsumer(c, "m1", 1, kept)
+	checkHeartbeat(t, resp, 2)
+	if got := responseAssignment(resp); got != nil && !got.equal(kept) {
+		t.Errorf("m1 assigned %s after revoking, want %s", formatAssignment(got), formatAssignment(kept))
+	}
+	resp = heartbeatConsumer(c, "m2", 2, assignment{})
+	checkHeartbeat(t, resp, 2)
+	if got := responseAssignment(resp); !got.equal(revoked) {
+		t.Errorf("m2 assigned %s after m1 revoked, want %s", formatAssignment(got), formatAssignment(revoked))
+	}
+}
+
+func TestConsumerHeartbeatFencing(t *testing.T) {
+	c, topic := newTestCoordinator(t, DefaultConfig())
+	all := allPartitions(topic)
+	checkHeartbeat(t, joinConsumer(c, "m1"), 1)
+
+	type heartbeatCase struct {
+		name      string
+		memberID  string
+		epoch     int32
+		owned     assignment
+		wantError int16
+	}
+	run := func(tests []heartbeatCase) {
+		t.Helper()
+		for _, tt := range tests {
+			resp := heartbeatConsumer(c, tt.memberID, tt.epoch, tt.owned)
+			if resp.ErrorCode != tt.wantError {
+				t.Errorf("%s: error %d, want %d", tt.name, resp.ErrorCode, tt.wantError)
+			}
+		}
+	}
+	run([]heartbeatCase{
+		{"unknown member", "unknown", 1, all, protocol.ErrorUnknownMemberID},
+		{"greater epoch", "m1", 2, all, protocol.ErrorFencedMemberEpoch},
+		{"current epoch", "m1", 1, all, protocol.ErrorNone},
+	})
+
+	// m1 moves from epoch 1 to 2 by revoking half its partitions to m2
+	checkHeartbeat(t, joinConsumer(c, "m2"), 2)
+	kept := responseAssignment(heartbeatConsumer(c, "m1", 1, all))
+	checkHeartbeat(t, heartbeatConsumer(c, "m1", 1, kept), 2)
+
+	run([]heartbeatCase{
+		// The response bumping m1 to epoch 2 may have been lost
+		{"previous epoch", "m1", 1, kept, protocol.ErrorNone},
+		{"previous epoch with revoked partitions", "m1", 1, all, protocol.ErrorFencedMemberEpoch},
+		{"previous epoch without owned partitions", "m1", 1, nil, protocol.ErrorFencedMemberEpoch},
+		{"greater epoch", "m1", 3, kept, protocol.ErrorFencedMemberEpoch},
+	})
+
+	// A member that left is unknown
+	leave := &protocol.ConsumerGroupHeartbeatRequest{}
+	leave.Default()
+	leave.GroupId, leave.MemberId, leave.MemberEpoch = testGroup, "m1", leaveGroupMemberEpoch
+	if resp := c.ConsumerGroupHeartbeat(testContext, leave); resp.ErrorCode != protocol.ErrorNone {
+		t.Fatalf("leave failed with error %d", resp.ErrorCode)
+	}
+	if resp := heartbeatConsumer(c, "m1", 2, kept); resp.ErrorCode != protocol.ErrorUnknownMemberID {
+		t.Errorf("heartbeat after leaving: error %d, want %d", resp.ErrorCode, protocol.ErrorUnknownMemberID)
+	}
+}
+
+func TestConsumerSessionExpiry(t *testing.T) {
+	config := DefaultConfig()
+	config.ConsumerSessionTimeout = 50 * time.Millisecond
+	c, topic := newTestCoordinator(t, config)
+	all := allPartitions(topic)
+	checkHeartbeat(t, joinConsumer(c, "m1"), 1)
+	checkHeartbeat(t, joinConsumer(c, "m2"), 2)
+
+	// m1 keeps heartbeating while m2's session runs out, which bumps the
+	// group to epoch 3 and gives m1 every partition again
+	epoch, owned := int32(1), all
+	for deadline := time.Now().Add(time.Second); epoch != 3 || !owned.equal(all); {
+		if time.Now().After(deadline) {
+			t.Fatalf("m1 owns %s at epoch %d, want every partition at epoch 3", formatAssignment(owned), epoch)
+		}
+		time.Sleep(10 * time.Millisecond)
+		resp := heartbeatConsumer(c, "m1", epoch, owned)
+		if resp.ErrorCode != protocol.ErrorNone {
+			t.Fatalf("m1 heartbeat failed with error %d", resp.ErrorCode)
+		}
+		epoch = resp.MemberEpoch
+		if a := responseAssignment(resp); a != nil {
+			owned = a
+		}
+	}
+	if resp := heartbeatConsumer(c, "m2", 2, assignment{}); resp.ErrorCode != protocol.ErrorUnknownMemberID {
+		t.Errorf("m2 heartbeat after its session expired: error %d, want %d", resp.ErrorCode, protocol.ErrorUnknownMemberID)
+	}
+}
@@ -0,0 +1,778 @@
+// Package group implements the group coordinator, which manages the
+// membership of consumer groups with the classic rebalance protocol or the
+// consumer rebalance protocol of KIP-848, and keeps their committed offsets
+// in the __consumer_offsets topic
+package group
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// Config holds the group coordinator settings
+type Config struct {
+	// MinSessionTimeout and MaxSessionTimeout bound the session timeouts
+	// members may ask for
+	MinSessionTimeout time.Duration
+	MaxSessionTimeout time.Duration
+
+	// InitialRebalanceDelay is how long the first rebalance of an empty
+	// group waits for more members to join
+	InitialRebalanceDelay time.Duration
+
+	// MaxSize caps the number of members of a group
+	MaxSize int
+
+	// OffsetsTopicPartitions is the number of partitions of the offsets
+	// topic (offsets.topic.num.partitions)
+	OffsetsTopicPartitions int32
+
+	// OffsetMetadataMaxBytes caps the metadata committed with an offset
+	// (offset.metadata.max.bytes)
+	OffsetMetadataMaxBytes int
+
+	// ConsumerSessionTimeout and ConsumerHeartbeatInterval are the session
+	// timeout and heartbeat interval of consumer groups
+	// (group.consumer.session.timeout.ms, group.consumer.heartbeat.interval.ms)
+	ConsumerSessionTimeout    time.Duration
+	ConsumerHeartbeatInterval time.Duration
+
+	// ConsumerMaxSize caps the number of members of a consumer group
+	// (group.consumer.max.size)
+	ConsumerMaxSize int
+
+	// ConsumerAssignors are the server-side assignors consumer groups may
+	// use, the first being the default (group.consumer.assignors)
+	ConsumerAssignors []string
+}
+
+// DefaultConfig returns Kafka's default group coordinator settings
+func DefaultConfig() Config {
+	return Config{
+		MinSessionTimeout:      6 * time.Second,
+		MaxSessionTimeout:      30 * time.Minute,
+		InitialRebalanceDelay:  3 * time.Second,
+		MaxSize:                math.MaxInt32,
+		OffsetsTopicPartitions: 50,
+		OffsetMetadataMaxBytes: 4096,
+
+		ConsumerSessionTimeout:    45 * time.Second,
+		ConsumerHeartbeatInterval: 5 * time.Second,
+		ConsumerMaxSize:           math.MaxInt32,
+		ConsumerAssignors:         []string{"uniform", "range"},
+	}
+}
+
+// RequestContext describes the client connection a request arrived on
+type RequestContext struct {
+	ClientID   string
+	ClientHost string
+	APIVersion int16
+}
+
+// Coordinator manages every group this broker coordinates. On a single
+// broker that is every group, so FindCoordinator always points here.
+type Coordinator struct {
+	config   Config
+	metadata *metadata.Image
+	logs     *storage.Manager
+	logger   *logger.Logger
+
+	// mu guards the groups, their members and offsets, and is held by timer
+	// callbacks as well as requests
+	mu     sync.Mutex
+	groups map[string]*classicGroup
+	// consumerGroups are the groups using the consumer rebalance protocol;
+	// a group ID is in at most one of groups and consumerGroups
+	consumerGroups map[string]*consumerGroup
+	// offsets caches the committed offsets of each group, as written to
+	// the offsets topic
+	offsets map[string]map[storage.TopicPartition]OffsetAndMetadata
+	// pendingOffsets holds the offsets committed in each producer's open
+	// transaction, by group, until the transaction completes
+	pendingOffsets map[int64]map[string]map[storage.TopicPartition]OffsetAndMetadata
+	closed         bool
+}
+
+// New creates a coordinator with no groups that assigns the topics in
+// image and writes offsets to the offsets topic in logs. Use Load to start
+// from the offsets already there.
+func New(config Config, image *metadata.Image, logs *storage.Manager, logger *logger.Logger) *Coordinator {
+	return &Coordinator{
+		config:         config,
+		metadata:       image,
+		logs:           logs,
+		logger:         logger,
+		groups:         make(map[string]*classicGroup),
+		consumerGroups: make(map[string]*consumerGroup),
+		offsets:        make(map[string]map[storage.TopicPartition]OffsetAndMetadata),
+		pendingOffsets: make(map[int64]map[string]map[storage.TopicPartition]OffsetAndMetadata),
+	}
+}
+
+// OffsetsTopicPartitions returns the number of partitions the offsets
+// topic is created with
+func (c *Coordinator) OffsetsTopicPartitions() int32 {
+	return c.config.OffsetsTopicPartitions
+}
+
+// OffsetsPartitionFor returns the partition of the offsets topic that
+// holds a group's offsets
+func (c *Coordinator) OffsetsPartitionFor(groupID string) int32 {
+	return PartitionFor(groupID, c.config.OffsetsTopicPartitions)
+}
+
+// Close unloads every group. Parked JoinGroup and SyncGroup requests are
+// answered with NOT_COORDINATOR, so their clients look for the
+// coordinator again, and no timer fires afterwards.
+func (c *Coordinator) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, g := range c.groups {
+		g.stopRebalanceTimer()
+		for id, timer := range g.pendingMembers {
+			timer.Stop()
+			delete(g.pendingMembers, id)
+		}
+		for _, m := range g.members {
+			m.stopTimer()
+			m.completeJoin(joinError(m.id, protocol.ErrorNotCoordinator))
+			m.completeSync(syncError(protocol.ErrorNotCoordinator))
+		}
+		c.transition(g, Dead)
+	}
+	for _, g := range c.consumerGroups {
+		for _, m := range g.members {
+			m.stopTimers()
+		}
+		g.dead = true
+	}
+}
+
+// JoinGroup adds a member to a group, or accepts a member rejoining it,
+// and triggers a rebalance when needed. The response is delivered on the
+// returned channel once the rebalance's join phase completes.
+func (c *Coordinator) JoinGroup(ctx RequestContext, req *protocol.JoinGroupRequest) <-chan *protocol.JoinGroupResponse {
+	ch := make(chan *protocol.JoinGroupResponse, 1)
+
+	switch {
+	case req.GroupId == "":
+		ch <- joinError(req.MemberId, protocol.ErrorInvalidGroupID)
+		return ch
+	case req.SessionTimeoutMs < int32(c.config.MinSessionTimeout.Milliseconds()) ||
+		req.SessionTimeoutMs > int32(c.config.MaxSessionTimeout.Milliseconds()):
+		ch <- joinError(req.MemberId, protocol.ErrorInvalidSessionTimeout)
+		return ch
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		ch <- joinError(req.MemberId, protocol.ErrorNotCoordinator)
+		return ch
+	}
+	if cg := c.consumerGroups[req.GroupId]; cg != nil {
+		// Classic members cannot join a consumer group, but an empty one
+		// is converted back to a classic group
+		if len(cg.members) > 0 {
+			ch <- joinError(req.MemberId, protocol.ErrorInconsistentGroupProtocol)
+			return ch
+		}
+		c.logger.Info("Converting empty consumer group %s to a classic group", req.GroupId)
+		cg.dead = true
+		delete(c.consumerGroups, req.GroupId)
+	}
+	g := c.groups[req.GroupId]
+	if g == nil {
+		// Only new members may create a group
+		if req.MemberId != "" {
+			ch <- joinError(req.MemberId, protocol.ErrorUnknownMemberID)
+			return ch
+		}
+		g = newClassicGroup(req.GroupId)
+		c.groups[req.GroupId] = g
+	}
+
+	switch {
+	case g.state == Dead:
+		// The group was unloaded or deleted while the request was in
+		// flight; the client should find the coordinator again
+		ch <- joinError(req.MemberId, protocol.ErrorCoordinatorNotAvailable)
+	case !g.supportsProtocols(req.ProtocolType, req.Protocols):
+		ch <- joinError(req.MemberId, protocol.ErrorInconsistentGroupProtocol)
+	case req.MemberId == "":
+		c.joinNewMember(g, ctx, req, ch)
+	default:
+		c.joinCurrentMember(g, ctx, req, ch)
+	}
+
+	if g.state == PreparingRebalance {
+		c.maybeCompleteJoin(g)
+	}
+	return ch
+}
+
+// joinNewMember handles a JoinGroup without a member ID
+func (c *Coordinator) joinNewMember(g *classicGroup, ctx RequestContext, req *protocol.JoinGroupRequest, ch chan *protocol.JoinGroupResponse) {
+	prefix := ctx.ClientID
+	if req.GroupInstanceId != nil {
+		prefix = *req.GroupInstanceId
+	}
+	memberID := prefix + "-" + protocol.RandomUUID().String()
+
+	// A static member that is already known takes over its old member ID
+	if req.GroupInstanceId != nil {
+		if oldID, ok := g.staticMembers[*req.GroupInstanceId]; ok {
+			c.replaceStaticMember(g, ctx, req, oldID, memberID, ch)
+			return
+		}
+	}
+
+	if len(g.members)+len(g.pendingMembers) >= c.config.MaxSize {
+		ch <- joinError(req.MemberId, protocol.ErrorGroupMaxSizeReached)
+		return
+	}
+
+	// From v4 dynamic members must rejoin with the ID they are given, so
+	// that a client whose first response got lost does not leave a stray
+	// member behind
+	if req.GroupInstanceId == nil && ctx.APIVersion >= 4 {
+		c.addPendingMember(g, memberID, time.Duration(req.SessionTimeoutMs)*time.Millisecond)
+		ch <- joinError(memberID, protocol.ErrorMemberIDRequired)
+		return
+	}
+
+	c.addMemberAndRebalance(g, ctx, req, memberID, ch)
+}
+
+// joinCurrentMember handles a JoinGroup from a member that has a member ID
+func (c *Coordinator) joinCurrentMember(g *classicGroup, ctx RequestContext, req *protocol.JoinGroupRequest, ch chan *protocol.JoinGroupResponse) {
+	if timer, ok := g.pendingMembers[req.MemberId]; ok {
+		timer.Stop()
+		delete(g.pendingMembers, req.MemberId)
+		c.addMemberAndRebalance(g, ctx, req, req.MemberId, ch)
+		return
+	}
+
+	if errorCode := g.validateMember(req.MemberId, req.GroupInstanceId); errorCode != protocol.ErrorNone {
+		ch <- joinError(req.MemberId, errorCode)
+		return
+	}
+
+	m := g.members[req.MemberId]
+	switch g.state {
+	case PreparingRebalance:
+		c.updateMemberAndRebalance(g, m, req, ch)
+	case CompletingRebalance:
+		if m.matches(req.Protocols) {
+			// The member probably missed its JoinGroup response; send it
+			// the current generation again
+			ch <- g.joinResponse(m.id)
+		} else {
+			c.updateMemberAndRebalance(g, m, req, ch)
+		}
+	case Stable:
+		if g.isLeader(m.id) || !m.matches(req.Protocols) {
+			// A rejoining leader forces a rebalance, which lets it react
+			// to changes that do not show in its metadata, such as new
+			// partitions of a subscribed topic
+			c.updateMemberAndRebalance(g, m, req, ch)
+		} else {
+			ch <- g.joinResponse(m.id)
+		}
+	default:
+		ch <- joinError(req.MemberId, protocol.ErrorUnknownMemberID)
+	}
+}
+
+// newMember builds a member from its JoinGroup request
+func newMember(ctx RequestContext, req *protocol.JoinGroupRequest, memberID string) *member {
+	m := &member{
+		id:           memberID,
+		instanceID:   req.GroupInstanceId,
+		clientID:     ctx.ClientID,
+		clientHost:   ctx.ClientHost,
+		protocolType: req.ProtocolType,
+	}
+	updateMember(m, req)
+	return m
+}
+
+// updateMember applies the timeouts and protocols of a JoinGroup request
+func updateMember(m *member, req *protocol.JoinGroupRequest) {
+	m.sessionTimeout = time.Duration(req.SessionTimeoutMs) * time.Millisecond
+	m.rebalanceTimeout = m.sessionTimeout
+	if req.RebalanceTimeoutMs >= 0 {
+		m.rebalanceTimeout = time.Duration(req.RebalanceTimeoutMs) * time.Millisecond
+	}
+	m.protocols = req.Protocols
+}
+
+// addMemberAndRebalance adds a member and parks its JoinGroup until the
+// rebalance it triggers has gathered the members
+func (c *Coordinator) addMemberAndRebalance(g *classicGroup, ctx RequestContext, req *protocol.JoinGroupRequest, memberID string, ch chan *protocol.JoinGroupResponse) {
+	m := newMember(ctx, req, memberID)
+	m.join = ch
+	g.add(m)
+	if g.initialJoin {
+		g.newMemberAdded = true
+	}
+	c.maybePrepareRebalance(g, "adding new member "+memberID)
+}
+
+// updateMemberAndRebalance records a member's rejoin and parks its
+// JoinGroup until the rebalance has gathered the members
+func (c *Coordinator) updateMemberAndRebalance(g *classicGroup, m *member, req *protocol.JoinGroupRequest, ch chan *protocol.JoinGroupResponse) {
+	// A client never has two JoinGroups in flight; if one is still parked
+	// its connection is gone
+	m.completeJoin(joinError(m.id, protocol.ErrorRebalanceInProgress))
+	updateMember(m, req)
+	m.join = ch
+	c.maybePrepareRebalance(g, "updating metadata for member "+m.id)
+}
+
+// replaceStaticMember handles a known static member joining without a
+// member ID, typically after a restart. It takes over the old member's
+// place and assignment; only a change to the group's protocol needs a
+// rebalance.
+func (c *Coordinator) replaceStaticMember(g *classicGroup, ctx RequestContext, req *protocol.JoinGroupRequest, oldID, newID string, ch chan *protocol.JoinGroupResponse) {
+	oldLeader := g.leaderID
+	m := g.replaceStaticMember(*req.GroupInstanceId, oldID, newID)
+	m.clientID, m.clientHost = ctx.ClientID, ctx.ClientHost
+	updateMember(m, req)
+	m.join = ch
+	c.scheduleHeartbeat(g, m)
+
+	switch g.state {
+	case Stable:
+		if g.protocolName != nil && g.selectProtocol() != *g.protocolName {
+			c.prepareRebalance(g, "static member "+newID+" with instance id "+*req.GroupInstanceId+" changed the group's protocol")
+			return
+		}
+		resp := g.joinResponse(newID)
+		if g.isLeader(newID) && ctx.APIVersion >= 9 {
+			// The leader learns the members but keeps the assignment
+			// that the group already runs with
+			resp.SkipAssignment = true
+		} else {
+			// Older leaders would compute an assignment that a stable
+			// group never hands out, so they are told the old leader
+			// still leads
+			resp.Leader = oldLeader
+			resp.Members = []protocol.JoinGroupResponseMember{}
+		}
+		m.completeJoin(resp)
+	case CompletingRebalance:
+		// The leader may already be assigning to the old member ID, so
+		// the assignment has to be computed again
+		c.prepareRebalance(g, "updating metadata for static member "+newID+" with instance id "+*req.GroupInstanceId)
+	}
+}
+
+// addPendingMember remembers a member ID handed out by MEMBER_ID_REQUIRED
+// until the member rejoins with it or its session timeout passes
+func (c *Coordinator) addPendingMember(g *classicGroup, memberID string, sessionTimeout time.Duration) {
+	g.pendingMembers[memberID] = time.AfterFunc(sessionTimeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if _, ok := g.pendingMembers[memberID]; !ok || c.closed {
+			return
+		}
+		delete(g.pendingMembers, memberID)
+		if g.state == PreparingRebalance {
+			c.maybeCompleteJoin(g)
+		}
+	})
+}
+
+// maybePrepareRebalance starts a rebalance unless one is already gathering
+// members
+func (c *Coordinator) maybePrepareRebalance(g *classicGroup, reason string) {
+	switch g.state {
+	case Stable, CompletingRebalance, Empty:
+		c.prepareRebalance(g, reason)
+	}
+}
+
+// prepareRebalance moves the group to PreparingRebalance and schedules the
+// end of the join phase. The first rebalance of an empty group waits the
+// initial rebalance delay, extended while members keep arriving; others
+// wait for the members' rebalance timeout unless everyone rejoins sooner.
+func (c *Coordinator) prepareRebalance(g *classicGroup, reason string) {
+	// Members waiting for an assignment that will not come must rejoin
+	if g.state == CompletingRebalance {
+		for _, m := range g.members {
+			m.completeSync(syncError(protocol.ErrorRebalanceInProgress))
+		}
+	}
+
+	c.logger.Info("Preparing to rebalance group %s in state %s with old generation %d (reason: %s)",
+		g.id, g.state, g.generationID, reason)
+	initial := g.state == Empty
+	g.stopRebalanceTimer()
+	if !c.transition(g, PreparingRebalance) {
+		return
+	}
+
+	if initial {
+		g.initialJoin = true
+		g.newMemberAdded = false
+		delay := c.config.InitialRebalanceDelay
+		c.scheduleInitialJoin(g, delay, max(g.rebalanceTimeout()-delay, 0))
+		return
+	}
+	epoch := g.epoch
+	g.rebalance = time.AfterFunc(g.rebalanceTimeout(), func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if g.epoch == epoch && !c.closed {
+			c.completeJoin(g)
+		}
+	})
+}
+
+// scheduleInitialJoin ends the join phase of an empty group's first
+// rebalance after delay, or waits again if new members joined meanwhile
+// and the group's rebalance timeout allows
+func (c *Coordinator) scheduleInitialJoin(g *classicGroup, delay, remaining time.Duration) {
+	epoch := g.epoch
+	g.rebalance = time.AfterFunc(delay, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if g.epoch != epoch || c.closed {
+			return
+		}
+		if g.newMemberAdded && remaining > 0 {
+			g.newMemberAdded = false
+			next := min(c.config.InitialRebalanceDelay, remaining)
+			c.scheduleInitialJoin(g, next, remaining-next)
+			return
+		}
+		c.completeJoin(g)
+	})
+}
+
+// maybeCompleteJoin ends the join phase early once every member has
+// rejoined. The initial delay of a new group always runs its course.
+func (c *Coordinator) maybeCompleteJoin(g *classicGroup) {
+	if g.state == PreparingRebalance && !g.initialJoin && g.allMembersJoined() {
+		c.completeJoin(g)
+	}
+}
+
+// completeJoin ends the join phase of a rebalance: members that did not
+// rejoin are removed, the next generation starts, and every parked
+// JoinGroup is answered
+func (c *Coordinator) completeJoin(g *classicGroup) {
+	g.stopRebalanceTimer()
+	g.initialJoin = false
+
+	for _, m := range g.members {
+		if m.join == nil {
+			c.logger.Info("Member %s in group %s has not rejoined in time, removing it from the group", m.id, g.id)
+			g.remove(m)
+		}
+	}
+
+	if err := g.initNextGeneration(); err != nil {
+		c.logger.Error("Failed to start the next generation of group %s: %s", g.id, err.Error())
+		return
+	}
+	if g.state == Empty {
+		c.logger.Info("Group %s with generation %d is now empty", g.id, g.generationID)
+		return
+	}
+
+	c.logger.Info("Stabilized group %s generation %d with %d members", g.id, g.generationID, len(g.members))
+	for _, m := range g.sortedMembers() {
+		m.completeJoin(g.joinResponse(m.id))
+		g.pendingSync[m.id] = true
+		c.scheduleHeartbeat(g, m)
+	}
+	c.schedulePendingSync(g)
+}
+
+// schedulePendingSync removes the members that have not sent SyncGroup by
+// the end of the group's rebalance timeout, which starts another rebalance
+func (c *Coordinator) schedulePendingSync(g *classicGroup) {
+	epoch, generation := g.epoch, g.generationID
+	g.rebalance = time.AfterFunc(g.rebalanceTimeout(), func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if g.epoch != epoch || g.generationID != generation || c.closed {
+			return
+		}
+		if g.state != CompletingRebalance && g.state != Stable {
+			return
+		}
+		if len(g.pendingSync) == 0 {
+			return
+		}
+		for id := range g.pendingSync {
+			m := g.members[id]
+			c.logger.Info("Member %s in group %s has not synced in time, removing it from the group", id, g.id)
+			m.completeSync(syncError(protocol.ErrorUnknownMemberID))
+			g.remove(m)
+		}
+		c.maybePrepareRebalance(g, "removing members that did not sync in time")
+	})
+}
+
+// scheduleHeartbeat restarts a member's session. A member whose session
+// runs out without a heartbeat is removed from the group, unless it is
+// parked in JoinGroup or SyncGroup and so cannot heartbeat.
+func (c *Coordinator) scheduleHeartbeat(g *classicGroup, m *member) {
+	m.heartbeatDeadline = time.Now().Add(m.sessionTimeout)
+	if m.timer != nil {
+		m.timer.Reset(m.sessionTimeout)
+		return
+	}
+	m.timer = time.AfterFunc(m.sessionTimeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if g.members[m.id] != m || c.closed {
+			return
+		}
+		if m.join != nil || m.sync != nil {
+			m.heartbeatDeadline = time.Now().Add(m.sessionTimeout)
+		}
+		if remaining := time.Until(m.heartbeatDeadline); remaining > 0 {
+			m.timer.Reset(remaining)
+			return
+		}
+		c.logger.Info("Member %s in group %s has failed, removing it from the group", m.id, g.id)
+		c.removeMemberAndUpdateGroup(g, m, "removing member "+m.id+" on heartbeat expiration")
+	})
+}
+
+// removeMemberAndUpdateGroup removes a member that left or failed, failing
+// any request it still has parked, and rebalances the rest of the group
+func (c *Coordinator) removeMemberAndUpdateGroup(g *classicGroup, m *member, reason string) {
+	m.completeJoin(joinError(m.id, protocol.ErrorUnknownMemberID))
+	m.completeSync(syncError(protocol.ErrorUnknownMemberID))
+	g.remove(m)
+
+	switch g.state {
+	case Stable, CompletingRebalance:
+		c.maybePrepareRebalance(g, reason)
+	case PreparingRebalance:
+		c.maybeCompleteJoin(g)
+	}
+}
+
+// SyncGroup hands a member its assignment for the current generation.
+// The leader's request carries the assignment of every member; members
+// that sync before it are parked until it arrives.
+func (c *Coordinator) SyncGroup(req *protocol.SyncGroupRequest) <-chan *protocol.SyncGroupResponse {
+	ch := make(chan *protocol.SyncGroupResponse, 1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g := c.groups[req.GroupId]
+	var errorCode int16
+	switch {
+	case c.closed:
+		errorCode = protocol.ErrorNotCoordinator
+	case g == nil:
+		errorCode = protocol.ErrorUnknownMemberID
+	case g.state == Dead:
+		errorCode = protocol.ErrorCoordinatorNotAvailable
+	default:
+		errorCode = g.validateMember(req.MemberId, req.GroupInstanceId)
+	}
+	if errorCode == protocol.ErrorNone {
+		switch {
+		case req.GenerationId != g.generationID:
+			errorCode = protocol.ErrorIllegalGeneration
+		case req.ProtocolType != nil && (g.protocolType == nil || *g.protocolType != *req.ProtocolType),
+			req.ProtocolName != nil && (g.protocolName == nil || *g.protocolName != *req.ProtocolName):
+			errorCode = protocol.ErrorInconsistentGroupProtocol
+		}
+	}
+	if errorCode != protocol.ErrorNone {
+		ch <- syncError(errorCode)
+		return ch
+	}
+
+	m := g.members[req.MemberId]
+	switch g.state {
+	case PreparingRebalance:
+		ch <- syncError(protocol.ErrorRebalanceInProgress)
+	case CompletingRebalance:
+		m.sync = ch
+		delete(g.pendingSync, m.id)
+		c.scheduleHeartbeat(g, m)
+		if g.isLeader(m.id) {
+			c.completeSync(g, req.Assignments)
+		}
+	case Stable:
+		delete(g.pendingSync, m.id)
+		c.scheduleHeartbeat(g, m)
+		ch <- g.syncResponse(m)
+	default:
+		ch <- syncError(protocol.ErrorUnknownMemberID)
+	}
+	return ch
+}
+
+// completeSync installs the leader's assignment, moves the group to
+// Stable and answers every parked SyncGroup. Members the leader left out
+// get an empty assignment.
+func (c *Coordinator) completeSync(g *classicGroup, assignments []protocol.SyncGroupRequestAssignment) {
+	byMember := make(map[string][]byte, len(assignments))
+	for _, a := range assignments {
+		byMember[a.MemberId] = a.Assignment
+	}
+	for _, m := range g.members {
+		m.assignment = byMember[m.id]
+		if m.assignment == nil {
+			m.assignment = []byte{}
+		}
+	}
+
+	if !c.transition(g, Stable) {
+		return
+	}
+	c.logger.Info("Assignment received from leader %s for group %s for generation %d", g.leaderID, g.id, g.generationID)
+	for _, m := range g.members {
+		m.completeSync(g.syncResponse(m))
+	}
+}
+
+// transition moves a classic group to s, logging an invalid transition
+// rather than failing the request that caused it. It reports whether the
+// group moved.
+func (c *Coordinator) transition(g *classicGroup, s state) bool {
+	if err := g.transitionTo(s); err != nil {
+		c.logger.Error("Invalid state transition: %s", err.Error())
+		return false
+	}
+	return true
+}
+
+// syncResponse builds the SyncGroup response carrying a member's assignment
+func (g *classicGroup) syncResponse(m *member) *protocol.SyncGroupResponse {
+	resp := &protocol.SyncGroupResponse{}
+	resp.Default()
+	resp.ErrorCode = protocol.ErrorNone
+	resp.ProtocolType = g.protocolType
+	resp.ProtocolName = g.protocolName
+	resp.Assignment = m.assignment
+	return resp
+}
+
+// Heartbeat keeps a member's session alive, and tells it to rejoin when
+// the group is rebalancing
+func (c *Coordinator) Heartbeat(req *protocol.HeartbeatRequest) int16 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g := c.groups[req.GroupId]
+	switch {
+	case c.closed:
+		return protocol.ErrorNotCoordinator
+	case g == nil:
+		return protocol.ErrorUnknownMemberID
+	case g.state == Dead:
+		return protocol.ErrorCoordinatorNotAvailable
+	}
+	if errorCode := g.validateMember(req.MemberId, req.GroupInstanceId); errorCode != protocol.ErrorNone {
+		return errorCode
+	}
+	if req.GenerationId != g.generationID {
+		return protocol.ErrorIllegalGeneration
+	}
+
+	m := g.members[req.MemberId]
+	switch g.state {
+	case PreparingRebalance:
+		c.scheduleHeartbeat(g, m)
+		return protocol.ErrorRebalanceInProgress
+	case CompletingRebalance, Stable:
+		// Members may heartbeat between their JoinGroup and SyncGroup
+		c.scheduleHeartbeat(g, m)
+		return protocol.ErrorNone
+	}
+	return protocol.ErrorUnknownMemberID
+}
+
+// LeaveGroup removes members from a group. Members are identified by
+// member ID, or by group.instance.id for static members. It returns the
+// group-level error and, if that is none, the result of each member.
+func (c *Coordinator) LeaveGroup(groupID string, members []protocol.LeaveGroupRequestMemberIdentity) (int16, []protocol.LeaveGroupResponseMemberResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g := c.groups[groupID]
+	switch {
+	case c.closed:
+		return protocol.ErrorNotCoordinator, nil
+	case g != nil && g.state == Dead:
+		return protocol.ErrorCoordinatorNotAvailable, nil
+	}
+
+	results := make([]protocol.LeaveGroupResponseMemberResponse, 0, len(members))
+	for _, leaving := range members {
+		result := protocol.LeaveGroupResponseMemberResponse{
+			MemberId:        leaving.MemberId,
+			GroupInstanceId: leaving.GroupInstanceId,
+			ErrorCode:       protocol.ErrorNone,
+		}
+		if g == nil {
+			result.ErrorCode = protocol.ErrorUnknownMemberID
+		} else {
+			result.ErrorCode = c.leave(g, leaving)
+		}
+		results = append(results, result)
+	}
+	return protocol.ErrorNone, results
+}
+
+// leave removes one member from a group
+func (c *Coordinator) leave(g *classicGroup, leaving protocol.LeaveGroupRequestMemberIdentity) int16 {
+	memberID := leaving.MemberId
+	if leaving.GroupInstanceId != nil {
+		current, ok := g.staticMembers[*leaving.GroupInstanceId]
+		switch {
+		case !ok:
+			return protocol.ErrorUnknownMemberID
+		case memberID != "" && memberID != current:
+			return protocol.ErrorFencedInstanceID
+		}
+		memberID = current
+	} else if timer, ok := g.pendingMembers[memberID]; ok {
+		timer.Stop()
+		delete(g.pendingMembers, memberID)
+		if g.state == PreparingRebalance {
+			c.maybeCompleteJoin(g)
+		}
+		return protocol.ErrorNone
+	}
+
+	m, ok := g.members[memberID]
+	if !ok {
+		return protocol.ErrorUnknownMemberID
+	}
+	reason := "removing member " + memberID + " on LeaveGroup"
+	if leaving.Reason != nil {
+		reason += "; client reason: " + *leaving.Reason
+	}
+	c.logger.Info("Member %s has left group %s through explicit LeaveGroup", memberID, g.id)
+	c.removeMemberAndUpdateGroup(g, m, reason)
+	return protocol.ErrorNone
+}
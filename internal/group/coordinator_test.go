@@ -0,0 +1,260 @@
+package group
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// classicConfig returns coordinator settings that let the classic group
+// tests use short timeouts and rebalance without the initial delay
+func classicConfig() Config {
+	config := DefaultConfig()
+	config.MinSessionTimeout = time.Millisecond
+	config.InitialRebalanceDelay = 0
+	return config
+}
+
+// joinClassic sends a JoinGroup to testGroup with the timeouts given
+func joinClassic(c *Coordinator, memberID string, instanceID *string, sessionTimeout, rebalanceTimeout time.Duration) <-chan *protocol.JoinGroupResponse {
+	req := &protocol.JoinGroupRequest{}
+	req.Default()
+	req.GroupId = testGroup
+	req.MemberId = memberID
+	req.GroupInstanceId = instanceID
+	req.SessionTimeoutMs = int32(sessionTimeout.Milliseconds())
+	req.RebalanceTimeoutMs = int32(rebalanceTimeout.Milliseconds())
+	req.ProtocolType = "consumer"
+	req.Protocols = []protocol.JoinGroupRequestProtocol{{Name: "range", Metadata: []byte{}}}
+	return c.JoinGroup(testContext, req)
+}
+
+// syncClassic sends a SyncGroup to testGroup, carrying the assignment of
+// each member ID if it comes from the leader
+func syncClassic(c *Coordinator, memberID string, instanceID *string, generation int32, assignments map[string]string) <-chan *protocol.SyncGroupResponse {
+	req := &protocol.SyncGroupRequest{}
+	req.Default()
+	req.GroupId = testGroup
+	req.MemberId = memberID
+	req.GroupInstanceId = instanceID
+	req.GenerationId = generation
+	req.Assignments = []protocol.SyncGroupRequestAssignment{}
+	for id, a := range assignments {
+		req.Assignments = append(req.Assignments, protocol.SyncGroupRequestAssignment{MemberId: id, Assignment: []byte(a)})
+	}
+	return c.SyncGroup(req)
+}
+
+// heartbeatClassic sends a Heartbeat to testGroup and returns its error
+func heartbeatClassic(c *Coordinator, memberID string, instanceID *string, generation int32) int16 {
+	req := &protocol.HeartbeatRequest{}
+	req.Default()
+	req.GroupId = testGroup
+	req.MemberId = memberID
+	req.GroupInstanceId = instanceID
+	req.GenerationId = generation
+	return c.Heartbeat(req)
+}
+
+// await returns the response sent on ch, failing the test if none comes
+func await[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case resp := <-ch:
+		return resp
+	case <-time.After(2 * time.Second):
+		t.Fatal("no response")
+	}
+	panic("unreachable")
+}
+
+// checkParked fails the test if a response was sent on ch
+func checkParked[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+	select {
+	case resp := <-ch:
+		t.Fatalf("request answered with %+v, want it parked", resp)
+	default:
+	}
+}
+
+// checkJoin fails the test unless resp succeeded
+func checkJoin(t *testing.T, resp *protocol.JoinGroupResponse) *protocol.JoinGroupResponse {
+	t.Helper()
+	if resp.ErrorCode != protocol.ErrorNone {
+		t.Fatalf("JoinGroup failed with error %d", resp.ErrorCode)
+	}
+	return resp
+}
+
+// formClassicGroup forms a stable group of one member, then has a second
+// member join it. It returns the JoinGroup responses of the second
+// generation, the leader's first.
+func formClassicGroup(t *testing.T, c *Coordinator, rebalanceTimeout time.Duration) (*protocol.JoinGroupResponse, *protocol.JoinGroupResponse) {
+	t.Helper()
+	first := checkJoin(t, await(t, joinClassic(c, "", nil, time.Minute, rebalanceTimeout)))
+	if resp := await(t, syncClassic(c, first.MemberId, nil, first.GenerationId, nil)); resp.ErrorCode != protocol.ErrorNone {
+		t.Fatalf("SyncGroup failed with error %d", resp.ErrorCode)
+	}
+
+	// The first member rejoins once the second has, which completes the
+	// join phase at once
+	second := joinClassic(c, "", nil, time.Minute, rebalanceTimeout)
+	leader := checkJoin(t, await(t, joinClassic(c, first.MemberId, nil, time.Minute, rebalanceTimeout)))
+	follower := checkJoin(t, await(t, second))
+	if leader.GenerationId != first.GenerationId+1 || follower.GenerationId != leader.GenerationId {
+		t.Fatalf("members joined generations %d and %d, want %d", leader.GenerationId, follower.GenerationId, first.GenerationId+1)
+	}
+	if leader.Leader != leader.MemberId || len(leader.Members) != 2 {
+		t.Fatalf("leader %s of %d members, want %s of 2", leader.Leader, len(leader.Members), leader.MemberId)
+	}
+	return leader, follower
+}
+
+func TestClassicHeartbeatRebalanceInProgress(t *testing.T) {
+	c, _ := newTestCoordinator(t, classicConfig())
+	first := checkJoin(t, await(t, joinClassic(c, "", nil, time.Minute, time.Minute)))
+	await(t, syncClassic(c, first.MemberId, nil, first.GenerationId, nil))
+	if got := heartbeatClassic(c, first.MemberId, nil, first.GenerationId); got != protocol.ErrorNone {
+		t.Fatalf("heartbeat of stable group: error %d", got)
+	}
+
+	// A new member starts a rebalance, which the first member learns of
+	// from its heartbeat
+	second := joinClassic(c, "", nil, time.Minute, time.Minute)
+	checkParked(t, second)
+	if got := heartbeatClassic(c, first.MemberId, nil, first.GenerationId); got != protocol.ErrorRebalanceInProgress {
+		t.Errorf("heartbeat during rebalance: error %d, want %d", got, protocol.ErrorRebalanceInProgress)
+	}
+	// and SyncGroup of the old generation is refused the same way
+	if resp := await(t, syncClassic(c, first.MemberId, nil, first.GenerationId, nil)); resp.ErrorCode != protocol.ErrorRebalanceInProgress {
+		t.Errorf("SyncGroup during rebalance: error %d, want %d", resp.ErrorCode, protocol.ErrorRebalanceInProgress)
+	}
+}
+
+func TestClassicRebalanceTimeout(t *testing.T) {
+	c, _ := newTestCoordinator(t, classicConfig())
+	first := checkJoin(t, await(t, joinClassic(c, "", nil, time.Minute, 50*time.Millisecond)))
+	await(t, syncClassic(c, first.MemberId, nil, first.GenerationId, nil))
+
+	// The first member never rejoins, so once the rebalance timeout has
+	// passed the second member forms the next generation alone
+	resp := checkJoin(t, await(t, joinClassic(c, "", nil, time.Minute, 50*time.Millisecond)))
+	if resp.GenerationId != first.GenerationId+1 {
+		t.Errorf("generation %d, want %d", resp.GenerationId, first.GenerationId+1)
+	}
+	if resp.Leader != resp.MemberId || len(resp.Members) != 1 || resp.Members[0].MemberId != resp.MemberId {
+		t.Errorf("leader %s of %+v, want %s alone", resp.Leader, resp.Members, resp.MemberId)
+	}
+	if got := heartbeatClassic(c, first.MemberId, nil, resp.GenerationId); got != protocol.ErrorUnknownMemberID {
+		t.Errorf("heartbeat of the removed member: error %d, want %d", got, protocol.ErrorUnknownMemberID)
+	}
+}
+
+func TestClassicSessionExpiry(t *testing.T) {
+	c, _ := newTestCoordinator(t, classicConfig())
+	resp := checkJoin(t, await(t, joinClassic(c, "", nil, 50*time.Millisecond, time.Minute)))
+	await(t, syncClassic(c, resp.MemberId, nil, resp.GenerationId, nil))
+
+	// Heartbeats keep the session alive past its timeout
+	for range 10 {
+		time.Sleep(10 * time.Millisecond)
+		if got := heartbeatClassic(c, resp.MemberId, nil, resp.GenerationId); got != protocol.ErrorNone {
+			t.Fatalf("heartbeat: error %d", got)
+		}
+	}
+
+	// Without them the member is removed and the group left empty
+	time.Sleep(150 * time.Millisecond)
+	if got := heartbeatClassic(c, resp.MemberId, nil, resp.GenerationId); got != protocol.ErrorUnknownMemberID {
+		t.Errorf("heartbeat after the session expired: error %d, want %d", got, protocol.ErrorUnknownMemberID)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if g := c.groups[testGroup]; g.state != Empty || len(g.members) != 0 {
+		t.Errorf("group is %s with %d members, want Empty", g.state, len(g.members))
+	}
+}
+
+func TestClassicSync(t *testing.T) {
+	c, _ := newTestCoordinator(t, classicConfig())
+	leader, follower := formClassicGroup(t, c, time.Minute)
+	generation := leader.GenerationId
+
+	// The follower waits for the leader's assignment, which leaves the
+	// leader itself out
+	parked := syncClassic(c, follower.MemberId, nil, generation, nil)
+	checkParked(t, parked)
+	leaderResp := await(t, syncClassic(c, leader.MemberId, nil, generation, map[string]string{follower.MemberId: "f"}))
+	followerResp := await(t, parked)
+	if followerResp.ErrorCode != protocol.ErrorNone || string(followerResp.Assignment) != "f" {
+		t.Errorf("follower got error %d with assignment %q, want %q", followerResp.ErrorCode, followerResp.Assignment, "f")
+	}
+	if leaderResp.ErrorCode != protocol.ErrorNone || leaderResp.Assignment == nil || len(leaderResp.Assignment) != 0 {
+		t.Errorf("leader got error %d with assignment %q, want an empty one", leaderResp.ErrorCode, leaderResp.Assignment)
+	}
+
+	// Once the group is stable SyncGroup is answered at once
+	resp := await(t, syncClassic(c, follower.MemberId, nil, generation, nil))
+	if resp.ErrorCode != protocol.ErrorNone || string(resp.Assignment) != "f" {
+		t.Errorf("follower resync got error %d with assignment %q, want %q", resp.ErrorCode, resp.Assignment, "f")
+	}
+	if resp := await(t, syncClassic(c, follower.MemberId, nil, generation-1, nil)); resp.ErrorCode != protocol.ErrorIllegalGeneration {
+		t.Errorf("SyncGroup of an old generation: error %d, want %d", resp.ErrorCode, protocol.ErrorIllegalGeneration)
+	}
+}
+
+func TestClassicSyncWithoutLeader(t *testing.T) {
+	c, _ := newTestCoordinator(t, classicConfig())
+	leader, follower := formClassicGroup(t, c, 50*time.Millisecond)
+
+	// The leader never sends its assignment, so once the rebalance timeout
+	// has passed it is removed and the follower must rejoin
+	parked := syncClassic(c, follower.MemberId, nil, follower.GenerationId, nil)
+	if resp := await(t, parked); resp.ErrorCode != protocol.ErrorRebalanceInProgress {
+		t.Errorf("follower SyncGroup: error %d, want %d", resp.ErrorCode, protocol.ErrorRebalanceInProgress)
+	}
+	resp := checkJoin(t, await(t, joinClassic(c, follower.MemberId, nil, time.Minute, 50*time.Millisecond)))
+	if resp.Leader != follower.MemberId || len(resp.Members) != 1 {
+		t.Errorf("leader %s of %d members, want %s alone", resp.Leader, len(resp.Members), follower.MemberId)
+	}
+	if got := heartbeatClassic(c, leader.MemberId, nil, resp.GenerationId); got != protocol.ErrorUnknownMemberID {
+		t.Errorf("heartbeat of the removed leader: error %d, want %d", got, protocol.ErrorUnknownMemberID)
+	}
+}
+
+func TestClassicStaticMemberReplacement(t *testing.T) {
+	c, _ := newTestCoordinator(t, classicConfig())
+	instanceID := "instance"
+	old := checkJoin(t, await(t, joinClassic(c, "", &instanceID, time.Minute, time.Minute)))
+	await(t, syncClassic(c, old.MemberId, &instanceID, old.GenerationId, map[string]string{old.MemberId: "a"}))
+
+	// The restarted member joins without its member ID and takes over its
+	// old place in the same generation, without a rebalance
+	resp := checkJoin(t, await(t, joinClassic(c, "", &instanceID, time.Minute, time.Minute)))
+	if resp.MemberId == old.MemberId {
+		t.Fatalf("rejoined as %s, want a new member ID", resp.MemberId)
+	}
+	if resp.GenerationId != old.GenerationId {
+		t.Errorf("generation %d, want %d", resp.GenerationId, old.GenerationId)
+	}
+	c.mu.Lock()
+	state := c.groups[testGroup].state
+	c.mu.Unlock()
+	if state != Stable {
+		t.Errorf("group is %s, want Stable", state)
+	}
+
+	// The old member ID is fenced, and the new one keeps the assignment
+	if got := heartbeatClassic(c, old.MemberId, &instanceID, old.GenerationId); got != protocol.ErrorFencedInstanceID {
+		t.Errorf("heartbeat with the old member ID: error %d, want %d", got, protocol.ErrorFencedInstanceID)
+	}
+	if got := heartbeatClassic(c, resp.MemberId, &instanceID, resp.GenerationId); got != protocol.ErrorNone {
+		t.Errorf("heartbeat with the new member ID: error %d", got)
+	}
+	sync := await(t, syncClassic(c, resp.MemberId, &instanceID, resp.GenerationId, nil))
+	if sync.ErrorCode != protocol.ErrorNone || string(sync.Assignment) != "a" {
+		t.Errorf("SyncGroup got error %d with assignment %q, want %q", sync.ErrorCode, sync.Assignment, "a")
+	}
+}
@@ -0,0 +1,100 @@
+package group
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// member is a member of a classic group
+type member struct {
+	id string
+	// instanceID is the group.instance.id of a static member, nil for a
+	// dynamic one
+	instanceID *string
+	clientID   string
+	clientHost string
+
+	sessionTimeout   time.Duration
+	rebalanceTimeout time.Duration
+
+	protocolType string
+	// protocols are the protocols the member supports, most preferred first
+	protocols []protocol.JoinGroupRequestProtocol
+	// assignment is the member's share of the current generation's
+	// assignment, handed out by SyncGroup
+	assignment []byte
+
+	// join and sync receive the responses of the member's parked JoinGroup
+	// and SyncGroup requests; they are nil when nothing is parked
+	join chan *protocol.JoinGroupResponse
+	sync chan *protocol.SyncGroupResponse
+
+	// heartbeatDeadline is when the session expires unless the member
+	// heartbeats again; timer fires at or after it
+	heartbeatDeadline time.Time
+	timer             *time.Timer
+}
+
+// isStatic reports whether the member was registered with a group.instance.id
+func (m *member) isStatic() bool {
+	return m.instanceID != nil
+}
+
+// supports reports whether the member supports the named protocol
+func (m *member) supports(name string) bool {
+	for _, p := range m.protocols {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// metadata returns the member's metadata for the named protocol
+func (m *member) metadata(name string) []byte {
+	for _, p := range m.protocols {
+		if p.Name == name {
+			return p.Metadata
+		}
+	}
+	return nil
+}
+
+// matches reports whether protocols are the ones the member already has,
+// in the same order and with the same metadata
+func (m *member) matches(protocols []protocol.JoinGroupRequestProtocol) bool {
+	if len(protocols) != len(m.protocols) {
+		return false
+	}
+	for i, p := range protocols {
+		if p.Name != m.protocols[i].Name || !bytes.Equal(p.Metadata, m.protocols[i].Metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+// completeJoin answers the member's parked JoinGroup, if any
+func (m *member) completeJoin(resp *protocol.JoinGroupResponse) {
+	if m.join != nil {
+		m.join <- resp
+		m.join = nil
+	}
+}
+
+// completeSync answers the member's parked SyncGroup, if any
+func (m *member) completeSync(resp *protocol.SyncGroupResponse) {
+	if m.sync != nil {
+		m.sync <- resp
+		m.sync = nil
+	}
+}
+
+// stopTimer cancels the member's session expiration
+func (m *member) stopTimer() {
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+}
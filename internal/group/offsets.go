@@ -0,0 +1,476 @@
+package group
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"unicode/utf16"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// OffsetsTopic is the internal topic that committed offsets are written
+// to. It is compacted, so the compactor keeps only the last commit of each
+// group's partition, and the tombstones of deleted offsets until
+// delete.retention.ms has passed.
+const OffsetsTopic = "__consumer_offsets"
+
+// Record types that prefix the keys of the offsets topic's records
+const (
+	legacyOffsetCommitRecord int16 = 0
+	offsetCommitRecord       int16 = 1
+	groupMetadataRecord      int16 = 2
+)
+
+// offsetCommitValueVersion is the version offsets are written with, the
+// first one holding the leader epoch
+const offsetCommitValueVersion int16 = 3
+
+// replayReadBytes is how much of the offsets topic is read at a time while
+// replaying it
+const replayReadBytes = 1 << 20
+
+// OffsetAndMetadata is an offset committed for a partition
+type OffsetAndMetadata struct {
+	Offset          int64
+	LeaderEpoch     int32
+	Metadata        string
+	CommitTimestamp int64
+}
+
+// PartitionFor returns the partition of an internal topic with the given
+// number of partitions that holds a key, such as a group's offsets or a
+// transactional ID's state. Like Kafka it hashes the key with Java's
+// String.hashCode, so a log written by Kafka keeps its layout.
+func PartitionFor(key string, partitions int32) int32 {
+	var hash int32
+	for _, unit := range utf16.Encode([]rune(key)) {
+		hash = 31*hash + int32(unit)
+	}
+	// Kafka's Utils.abs maps MinInt32 to 0 rather than overflowing
+	switch {
+	case hash == math.MinInt32:
+		hash = 0
+	case hash < 0:
+		hash = -hash
+	}
+	return hash % partitions
+}
+
+// offsetRecord builds the offsets topic record of a committed offset, or
+// the tombstone removing it if offset is nil
+func offsetRecord(groupID string, tp storage.TopicPartition, offset *OffsetAndMetadata) record.Record {
+	key := protocol.NewEncoder(0)
+	key.PutInt16(offsetCommitRecord)
+	(&protocol.OffsetCommitRecordKey{Group: groupID, Topic: tp.Topic, Partition: tp.Partition}).Encode(key, 0)
+	rec := record.Record{Key: key.Bytes()}
+	if offset == nil {
+		return rec
+	}
+
+	value := protocol.NewEncoder(0)
+	value.PutInt16(offsetCommitValueVersion)
+	(&protocol.OffsetCommitRecordValue{
+		Offset:          offset.Offset,
+		LeaderEpoch:     offset.LeaderEpoch,
+		Metadata:        offset.Metadata,
+		CommitTimestamp: offset.CommitTimestamp,
+		ExpireTimestamp: -1,
+	}).Encode(value, offsetCommitValueVersion)
+	rec.Value = value.Bytes()
+	return rec
+}
+
+// Load creates a coordinator and rebuilds its offset cache by replaying
+// every partition of the offsets topic found in logs. Groups that only
+// have offsets come back as Empty groups.
+func Load(config Config, image *metadata.Image, logs *storage.Manager, logger *logger.Logger) (*Coordinator, error) {
+	for _, name := range config.ConsumerAssignors {
+		if assignors[name] == nil {
+			return nil, fmt.Errorf("unknown consumer group assignor %q", name)
+		}
+	}
+	if len(config.ConsumerAssignors) == 0 {
+		return nil, fmt.Errorf("no consumer group assignor configured")
+	}
+
+	c := New(config, image, logs, logger)
+	for p := int32(0); p < config.OffsetsTopicPartitions; p++ {
+		log, ok := logs.Get(OffsetsTopic, p)
+		if !ok {
+			continue
+		}
+		if err := c.replay(log); err != nil {
+			return nil, fmt.Errorf("failed to load %s-%d: %w", OffsetsTopic, p, err)
+		}
+	}
+
+	for groupID := range c.offsets {
+		if c.groups[groupID] == nil {
+			c.groups[groupID] = newClassicGroup(groupID)
+		}
+	}
+	if len(c.offsets) > 0 {
+		logger.Info("Loaded committed offsets of %d groups from %s", len(c.offsets), OffsetsTopic)
+	}
+	return c, nil
+}
+
+// replay applies every record of a partition of the offsets topic, in
+// order, so that the last commit of each partition wins
+func (c *Coordinator) replay(log *storage.Log) error {
+	for offset := log.LogStartOffset(); offset < log.LogEndOffset(); {
+		data, err := log.Read(offset, replayReadBytes, true)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			break
+		}
+		for len(data) > 0 {
+			batch, err := record.NextBatch(data)
+			if err != nil {
+				return err
+			}
+			data = data[batch.Size():]
+			offset = batch.NextOffset()
+			if batch.IsControl() {
+				if err := c.replayMarker(log.Partition(), batch); err != nil {
+					return fmt.Errorf("invalid control batch at offset %d: %w", batch.BaseOffset, err)
+				}
+				continue
+			}
+
+			producerID := record.NoProducerID
+			if batch.IsTransactional() {
+				producerID = batch.ProducerID
+			}
+			records, err := batch.Records()
+			if err != nil {
+				return err
+			}
+			for _, rec := range records {
+				if err := c.replayRecord(rec, producerID); err != nil {
+					return fmt.Errorf("invalid record at offset %d: %w", batch.BaseOffset+int64(rec.OffsetDelta), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// replayRecord applies a record of the offsets topic to the offset cache,
+// or for a record written in a transaction of producerID, to the offsets
+// pending on that transaction. Group metadata records are skipped:
+// membership is not kept across restarts, members simply rejoin.
+func (c *Coordinator) replayRecord(rec record.Record, producerID int64) error {
+	if len(rec.Key) < 2 {
+		return fmt.Errorf("key of %d bytes is too short", len(rec.Key))
+	}
+	switch recordType := int16(binary.BigEndian.Uint16(rec.Key)); recordType {
+	case legacyOffsetCommitRecord, offsetCommitRecord:
+	case groupMetadataRecord:
+		return nil
+	default:
+		c.logger.Debug("Skipping unknown record type %d in %s", recordType, OffsetsTopic)
+		return nil
+	}
+
+	key := &protocol.OffsetCommitRecordKey{}
+	if err := key.Decode(protocol.NewDecoder(rec.Key[2:]), 0); err != nil {
+		return err
+	}
+	tp := storage.TopicPartition{Topic: key.Topic, Partition: key.Partition}
+	if rec.Value == nil {
+		c.deleteOffset(key.Group, tp)
+		return nil
+	}
+
+	if len(rec.Value) < 2 {
+		return fmt.Errorf("value of %d bytes is too short", len(rec.Value))
+	}
+	value := &protocol.OffsetCommitRecordValue{}
+	version := int16(binary.BigEndian.Uint16(rec.Value))
+	if version < value.MinVersion() || version > value.MaxVersion() {
+		return fmt.Errorf("unknown offset commit value version %d", version)
+	}
+	if err := value.Decode(protocol.NewDecoder(rec.Value[2:]), version); err != nil {
+		return err
+	}
+	offset := OffsetAndMetadata{
+		Offset:          value.Offset,
+		LeaderEpoch:     value.LeaderEpoch,
+		Metadata:        value.Metadata,
+		CommitTimestamp: value.CommitTimestamp,
+	}
+	if producerID != record.NoProducerID {
+		c.storePendingOffset(producerID, key.Group, tp, offset)
+		return nil
+	}
+	c.storeOffset(key.Group, tp, offset)
+	return nil
+}
+
+// storeOffset caches a committed offset
+func (c *Coordinator) storeOffset(groupID string, tp storage.TopicPartition, offset OffsetAndMetadata) {
+	if c.offsets[groupID] == nil {
+		c.offsets[groupID] = make(map[storage.TopicPartition]OffsetAndMetadata)
+	}
+	c.offsets[groupID][tp] = offset
+}
+
+// deleteOffset drops a committed offset from the cache
+func (c *Coordinator) deleteOffset(groupID string, tp storage.TopicPartition) {
+	delete(c.offsets[groupID], tp)
+	if len(c.offsets[groupID]) == 0 {
+		delete(c.offsets, groupID)
+	}
+}
+
+// appendRecords writes records for a group to its partition of the
+// offsets topic
+func (c *Coordinator) appendRecords(groupID string, records []record.Record) error {
+	return c.appendBatch(groupID, record.Batch{
+		ProducerID:    record.NoProducerID,
+		ProducerEpoch: record.NoProducerEpoch,
+		BaseSequence:  record.NoSequence,
+	}, records)
+}
+
+// appendBatch writes records for a group to its partition of the offsets
+// topic, in a batch with the producer fields and attributes of header
+func (c *Coordinator) appendBatch(groupID string, header record.Batch, records []record.Record) error {
+	partition := PartitionFor(groupID, c.config.OffsetsTopicPartitions)
+	log, ok := c.logs.Get(OffsetsTopic, partition)
+	if !ok {
+		return fmt.Errorf("no log for %s-%d", OffsetsTopic, partition)
+	}
+
+	now := time.Now().UnixMilli()
+	header.BaseTimestamp, header.MaxTimestamp = now, now
+	_, err := log.Append(record.EncodeBatch(header, records).Data)
+	return err
+}
+
+// CommitOffsets stores the offsets of an OffsetCommit request, after
+// checking that it comes from a member of the group's current generation,
+// or for a consumer group, from a member at its current epoch. A request
+// with no generation may commit for an empty group, creating it if needed,
+// as consumers that assign partitions themselves do.
+func (c *Coordinator) CommitOffsets(ctx RequestContext, req *protocol.OffsetCommitRequest) *protocol.OffsetCommitResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errorCode := protocol.ErrorNone
+	g := c.groups[req.GroupId]
+	cg := c.consumerGroups[req.GroupId]
+	switch {
+	case c.closed:
+		errorCode = protocol.ErrorNotCoordinator
+	case req.GroupId == "":
+		errorCode = protocol.ErrorInvalidGroupID
+	case cg != nil:
+		errorCode = validateConsumerOffsetCommit(cg, ctx.APIVersion, req)
+	case g == nil && req.GenerationIdOrMemberEpoch >= 0:
+		errorCode = protocol.ErrorIllegalGeneration
+	case g == nil:
+		g = newClassicGroup(req.GroupId)
+		c.groups[req.GroupId] = g
+	default:
+		errorCode = c.validateOffsetCommit(g, req)
+	}
+
+	resp := &protocol.OffsetCommitResponse{}
+	resp.Default()
+	now := time.Now().UnixMilli()
+	// pending are the partitions whose offsets are written, indexed like
+	// the records holding them
+	type pendingOffset struct {
+		topic, partition int
+		tp               storage.TopicPartition
+		offset           OffsetAndMetadata
+	}
+	var pending []pendingOffset
+	var records []record.Record
+	resp.Topics = make([]protocol.OffsetCommitResponseTopic, len(req.Topics))
+	for i, topic := range req.Topics {
+		resp.Topics[i].Name = topic.Name
+		resp.Topics[i].Partitions = make([]protocol.OffsetCommitResponsePartition, len(topic.Partitions))
+		for j, p := range topic.Partitions {
+			result := &resp.Topics[i].Partitions[j]
+			result.PartitionIndex = p.PartitionIndex
+			result.ErrorCode = errorCode
+			if errorCode != protocol.ErrorNone {
+				continue
+			}
+
+			offset := OffsetAndMetadata{
+				Offset:          p.CommittedOffset,
+				LeaderEpoch:     p.CommittedLeaderEpoch,
+				CommitTimestamp: now,
+			}
+			if p.CommittedMetadata != nil {
+				offset.Metadata = *p.CommittedMetadata
+			}
+			if len(offset.Metadata) > c.config.OffsetMetadataMaxBytes {
+				result.ErrorCode = protocol.ErrorOffsetMetadataTooLarge
+				continue
+			}
+			tp := storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}
+			pending = append(pending, pendingOffset{topic: i, partition: j, tp: tp, offset: offset})
+			records = append(records, offsetRecord(req.GroupId, tp, &offset))
+		}
+	}
+	if len(records) == 0 {
+		return resp
+	}
+
+	if err := c.appendRecords(req.GroupId, records); err != nil {
+		c.logger.Error("Failed to write offsets of group %s: %s", req.GroupId, err.Error())
+		for _, p := range pending {
+			resp.Topics[p.topic].Partitions[p.partition].ErrorCode = protocol.ErrorCoordinatorNotAvailable
+		}
+		return resp
+	}
+	for _, p := range pending {
+		c.storeOffset(req.GroupId, p.tp, p.offset)
+	}
+	return resp
+}
+
+// validateOffsetCommit checks that an OffsetCommit comes from a member of
+// the group's current generation, and keeps that member's session alive
+func (c *Coordinator) validateOffsetCommit(g *classicGroup, req *protocol.OffsetCommitRequest) int16 {
+	switch {
+	case g.state == Dead:
+		return protocol.ErrorCoordinatorNotAvailable
+	case req.GenerationIdOrMemberEpoch < 0 && g.state == Empty:
+		// Commits from outside the group protocol, such as consumers that
+		// assign partitions themselves or admin tools
+		return protocol.ErrorNone
+	}
+	if errorCode := g.validateMember(req.MemberId, req.GroupInstanceId); errorCode != protocol.ErrorNone {
+		return errorCode
+	}
+	if req.GenerationIdOrMemberEpoch != g.generationID {
+		return protocol.ErrorIllegalGeneration
+	}
+	if g.state == CompletingRebalance {
+		// The member has its JoinGroup response but not its assignment;
+		// it retries once the rebalance completes
+		return protocol.ErrorRebalanceInProgress
+	}
+	c.scheduleHeartbeat(g, g.members[req.MemberId])
+	return protocol.ErrorNone
+}
+
+// validateConsumerOffsetCommit checks that an OffsetCommit for a consumer
+// group comes from a member at its current epoch. Only version 9 carries a
+// member epoch rather than a generation, so older versions may only commit
+// from outside the group.
+func validateConsumerOffsetCommit(g *consumerGroup, version int16, req *protocol.OffsetCommitRequest) int16 {
+	if req.GenerationIdOrMemberEpoch < 0 && len(g.members) == 0 {
+		return protocol.ErrorNone
+	}
+	m := g.members[req.MemberId]
+	switch {
+	case m == nil:
+		return protocol.ErrorUnknownMemberID
+	case version < 9:
+		return protocol.ErrorUnsupportedVersion
+	case req.GenerationIdOrMemberEpoch != m.epoch:
+		return protocol.ErrorStaleMemberEpoch
+	}
+	return protocol.ErrorNone
+}
+
+// FetchOffsets returns a group's committed offsets for the requested
+// partitions, or for every partition it has committed if Topics is nil.
+// Partitions without a committed offset get offset -1. With requireStable,
+// partitions with offsets pending on an open transaction get
+// UNSTABLE_OFFSET_COMMIT, so the consumer retries once it completes.
+func (c *Coordinator) FetchOffsets(req protocol.OffsetFetchRequestGroup, requireStable bool) protocol.OffsetFetchResponseGroup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resp := protocol.OffsetFetchResponseGroup{GroupId: req.GroupId, Topics: []protocol.OffsetFetchResponseTopics{}}
+	if c.closed {
+		resp.ErrorCode = protocol.ErrorNotCoordinator
+		return resp
+	}
+	if g := c.groups[req.GroupId]; g != nil && g.state == Dead {
+		resp.ErrorCode = protocol.ErrorCoordinatorNotAvailable
+		return resp
+	}
+	if g := c.consumerGroups[req.GroupId]; g != nil && (req.MemberId != nil || req.MemberEpoch != -1) {
+		// A consumer group member fetching its offsets must be at its
+		// current epoch
+		var m *consumerMember
+		if req.MemberId != nil {
+			m = g.members[*req.MemberId]
+		}
+		switch {
+		case m == nil:
+			resp.ErrorCode = protocol.ErrorUnknownMemberID
+			return resp
+		case m.epoch != req.MemberEpoch:
+			resp.ErrorCode = protocol.ErrorStaleMemberEpoch
+			return resp
+		}
+	}
+
+	offsets := c.offsets[req.GroupId]
+	topics := req.Topics
+	if topics == nil {
+		topics = committedTopics(offsets)
+	}
+	for _, topic := range topics {
+		result := protocol.OffsetFetchResponseTopics{Name: topic.Name}
+		for _, partition := range topic.PartitionIndexes {
+			p := protocol.OffsetFetchResponsePartitions{
+				PartitionIndex:       partition,
+				CommittedOffset:      -1,
+				CommittedLeaderEpoch: -1,
+				Metadata:             new(string),
+				ErrorCode:            protocol.ErrorNone,
+			}
+			tp := storage.TopicPartition{Topic: topic.Name, Partition: partition}
+			if requireStable && c.hasPendingOffset(req.GroupId, tp) {
+				p.ErrorCode = protocol.ErrorUnstableOffsetCommit
+				result.Partitions = append(result.Partitions, p)
+				continue
+			}
+			if offset, ok := offsets[tp]; ok {
+				p.CommittedOffset = offset.Offset
+				p.CommittedLeaderEpoch = offset.LeaderEpoch
+				metadata := offset.Metadata
+				p.Metadata = &metadata
+			}
+			result.Partitions = append(result.Partitions, p)
+		}
+		resp.Topics = append(resp.Topics, result)
+	}
+	return resp
+}
+
+// committedTopics lists the partitions a group has committed offsets for,
+// sorted by topic and partition
+func committedTopics(offsets map[storage.TopicPartition]OffsetAndMetadata) []protocol.OffsetFetchRequestTopics {
+	byTopic := make(map[string][]int32)
+	for tp := range offsets {
+		byTopic[tp.Topic] = append(byTopic[tp.Topic], tp.Partition)
+	}
+	topics := make([]protocol.OffsetFetchRequestTopics, 0, len(byTopic))
+	for name, partitions := range byTopic {
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+		topics = append(topics, protocol.OffsetFetchRequestTopics{Name: name, PartitionIndexes: partitions})
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics
+}
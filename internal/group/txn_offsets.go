@@ -0,0 +1,207 @@
+package group
+
+import (
+	"errors"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// CommitTransactionalOffsets writes the offsets of a TxnOffsetCommit
+// request to the offsets topic as part of the producer's transaction. They
+// stay pending, invisible to OffsetFetch, until the transaction's marker
+// reaches the offsets topic and CompleteTransaction applies or drops them.
+// A request with a generation must come from a current member of the
+// group, as for OffsetCommit.
+func (c *Coordinator) CommitTransactionalOffsets(req *protocol.TxnOffsetCommitRequest) *protocol.TxnOffsetCommitResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errorCode := protocol.ErrorNone
+	g := c.groups[req.GroupId]
+	cg := c.consumerGroups[req.GroupId]
+	switch {
+	case c.closed:
+		errorCode = protocol.ErrorNotCoordinator
+	case req.GroupId == "":
+		errorCode = protocol.ErrorInvalidGroupID
+	case cg != nil:
+		errorCode = validateConsumerTxnOffsetCommit(cg, req)
+	case g == nil && req.GenerationId >= 0:
+		errorCode = protocol.ErrorIllegalGeneration
+	case g == nil:
+		g = newClassicGroup(req.GroupId)
+		c.groups[req.GroupId] = g
+	default:
+		errorCode = validateTxnOffsetCommit(g, req)
+	}
+
+	resp := &protocol.TxnOffsetCommitResponse{}
+	resp.Default()
+	now := time.Now().UnixMilli()
+	pending := make(map[storage.TopicPartition]OffsetAndMetadata)
+	var records []record.Record
+	var written []*protocol.TxnOffsetCommitResponsePartition
+	resp.Topics = make([]protocol.TxnOffsetCommitResponseTopic, len(req.Topics))
+	for i, topic := range req.Topics {
+		resp.Topics[i].Name = topic.Name
+		resp.Topics[i].Partitions = make([]protocol.TxnOffsetCommitResponsePartition, len(topic.Partitions))
+		for j, p := range topic.Partitions {
+			result := &resp.Topics[i].Partitions[j]
+			result.PartitionIndex = p.PartitionIndex
+			result.ErrorCode = errorCode
+			if errorCode != protocol.ErrorNone {
+				continue
+			}
+
+			offset := OffsetAndMetadata{
+				Offset:          p.CommittedOffset,
+				LeaderEpoch:     p.CommittedLeaderEpoch,
+				CommitTimestamp: now,
+			}
+			if p.CommittedMetadata != nil {
+				offset.Metadata = *p.CommittedMetadata
+			}
+			if len(offset.Metadata) > c.config.OffsetMetadataMaxBytes {
+				result.ErrorCode = protocol.ErrorOffsetMetadataTooLarge
+				continue
+			}
+			tp := storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}
+			pending[tp] = offset
+			records = append(records, offsetRecord(req.GroupId, tp, &offset))
+			written = append(written, result)
+		}
+	}
+	if len(records) == 0 {
+		return resp
+	}
+
+	err := c.appendBatch(req.GroupId, record.Batch{
+		Attributes:    record.TransactionalAttribute,
+		ProducerID:    req.ProducerId,
+		ProducerEpoch: req.ProducerEpoch,
+		BaseSequence:  record.NoSequence,
+	}, records)
+	if err != nil {
+		errorCode := protocol.ErrorCoordinatorNotAvailable
+		if errors.Is(err, storage.ErrInvalidProducerEpoch) {
+			errorCode = protocol.ErrorInvalidProducerEpoch
+		} else {
+			c.logger.Error("Failed to write transactional offsets of group %s: %s", req.GroupId, err.Error())
+		}
+		for _, result := range written {
+			result.ErrorCode = errorCode
+		}
+		return resp
+	}
+	for tp, offset := range pending {
+		c.storePendingOffset(req.ProducerId, req.GroupId, tp, offset)
+	}
+	return resp
+}
+
+// validateTxnOffsetCommit checks that a TxnOffsetCommit naming a
+// generation comes from a member of the group's current generation.
+// Producers that do not pass on their consumer's group metadata commit
+// with no generation and are not checked.
+func validateTxnOffsetCommit(g *classicGroup, req *protocol.TxnOffsetCommitRequest) int16 {
+	switch {
+	case g.state == Dead:
+		return protocol.ErrorCoordinatorNotAvailable
+	case req.GenerationId < 0 && req.MemberId == "":
+		return protocol.ErrorNone
+	}
+	if errorCode := g.validateMember(req.MemberId, req.GroupInstanceId); errorCode != protocol.ErrorNone {
+		return errorCode
+	}
+	if req.GenerationId != g.generationID {
+		return protocol.ErrorIllegalGeneration
+	}
+	return protocol.ErrorNone
+}
+
+// validateConsumerTxnOffsetCommit checks that a TxnOffsetCommit for a
+// consumer group naming a member comes from that member at its current
+// epoch. The request has no room for STALE_MEMBER_EPOCH, so a stale epoch
+// is an illegal generation.
+func validateConsumerTxnOffsetCommit(g *consumerGroup, req *protocol.TxnOffsetCommitRequest) int16 {
+	if req.GenerationId < 0 && req.MemberId == "" {
+		return protocol.ErrorNone
+	}
+	m := g.members[req.MemberId]
+	switch {
+	case m == nil:
+		return protocol.ErrorUnknownMemberID
+	case req.GenerationId != m.epoch:
+		return protocol.ErrorIllegalGeneration
+	}
+	return protocol.ErrorNone
+}
+
+// CompleteTransaction applies, or with commit unset drops, the offsets
+// that producerID committed in its transaction to groups whose offsets
+// live in the given partition of the offsets topic. It is called once the
+// transaction's marker has been written to that partition.
+func (c *Coordinator) CompleteTransaction(producerID int64, partition int32, commit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.completeTransaction(producerID, partition, commit)
+}
+
+// completeTransaction implements CompleteTransaction
+func (c *Coordinator) completeTransaction(producerID int64, partition int32, commit bool) {
+	for groupID, offsets := range c.pendingOffsets[producerID] {
+		if PartitionFor(groupID, c.config.OffsetsTopicPartitions) != partition {
+			continue
+		}
+		if commit {
+			for tp, offset := range offsets {
+				c.storeOffset(groupID, tp, offset)
+			}
+		}
+		delete(c.pendingOffsets[producerID], groupID)
+	}
+	if len(c.pendingOffsets[producerID]) == 0 {
+		delete(c.pendingOffsets, producerID)
+	}
+}
+
+// replayMarker completes the transaction a control batch of a partition of
+// the offsets topic ends
+func (c *Coordinator) replayMarker(partition int32, batch *record.Batch) error {
+	controlType, err := batch.ControlType()
+	if err != nil {
+		return err
+	}
+	switch controlType {
+	case record.ControlCommit, record.ControlAbort:
+		c.completeTransaction(batch.ProducerID, partition, controlType == record.ControlCommit)
+	}
+	return nil
+}
+
+// storePendingOffset records an offset committed in producerID's open
+// transaction
+func (c *Coordinator) storePendingOffset(producerID int64, groupID string, tp storage.TopicPartition, offset OffsetAndMetadata) {
+	if c.pendingOffsets[producerID] == nil {
+		c.pendingOffsets[producerID] = make(map[string]map[storage.TopicPartition]OffsetAndMetadata)
+	}
+	if c.pendingOffsets[producerID][groupID] == nil {
+		c.pendingOffsets[producerID][groupID] = make(map[storage.TopicPartition]OffsetAndMetadata)
+	}
+	c.pendingOffsets[producerID][groupID][tp] = offset
+}
+
+// hasPendingOffset reports whether an open transaction has committed an
+// offset for a group's partition
+func (c *Coordinator) hasPendingOffset(groupID string, tp storage.TopicPartition) bool {
+	for _, groups := range c.pendingOffsets {
+		if _, ok := groups[groupID][tp]; ok {
+			return true
+		}
+	}
+	return false
+}
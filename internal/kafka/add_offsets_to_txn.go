@@ -0,0 +1,40 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// handleAddOffsetsToTxnRequest handles ADD_OFFSETS_TO_TXN requests by
+// adding the partition of the offsets topic holding the group's offsets to
+// the producer's transaction, ahead of its TxnOffsetCommit
+func (h *RequestHandler) handleAddOffsetsToTxnRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.AddOffsetsToTxnRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode AddOffsetsToTxn request: %w", err)
+	}
+
+	if err := h.ensureOffsetsTopic(); err != nil {
+		h.logger.Error("Failed to create %s: %s", group.OffsetsTopic, err.Error())
+		return h.sendResponse(conn, h.addOffsetsToTxnErrorResponse(req, protocol.ErrorCoordinatorNotAvailable))
+	}
+
+	resp := &protocol.AddOffsetsToTxnResponse{}
+	resp.Default()
+	tp := storage.TopicPartition{Topic: group.OffsetsTopic, Partition: h.groups.OffsetsPartitionFor(body.GroupId)}
+	resp.ErrorCode = h.txn.AddPartitions(body.TransactionalId, body.ProducerId, body.ProducerEpoch, []storage.TopicPartition{tp})
+	resp.ErrorCode = fencedError(resp.ErrorCode, req.ApiVersion, 2)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// addOffsetsToTxnErrorResponse builds a failed AddOffsetsToTxn response
+func (h *RequestHandler) addOffsetsToTxnErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.AddOffsetsToTxnResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,136 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// handleAddPartitionsToTxnRequest handles ADD_PARTITIONS_TO_TXN requests.
+// Requests before v4 come from producers and add partitions to a single
+// transaction. From v4 they come from brokers and batch transactions, each
+// of which may only verify that its partitions were already added
+// (KIP-890). If any partition names an unknown topic, nothing is added and
+// the other partitions fail with OPERATION_NOT_ATTEMPTED.
+func (h *RequestHandler) handleAddPartitionsToTxnRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.AddPartitionsToTxnRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode AddPartitionsToTxn request: %w", err)
+	}
+
+	resp := &protocol.AddPartitionsToTxnResponse{}
+	resp.Default()
+	for _, t := range addPartitionsToTxnTransactions(body, req.ApiVersion) {
+		result := protocol.AddPartitionsToTxnResponseAddPartitionsToTxnResult{TransactionalId: t.TransactionalId}
+		result.TopicResults = h.addPartitionsToTxn(&t, req.ApiVersion)
+		resp.ResultsByTransaction = append(resp.ResultsByTransaction, result)
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, addPartitionsToTxnResponse(resp, req.ApiVersion)))
+}
+
+// addPartitionsToTxn adds the partitions of one transaction, or only
+// verifies them, and returns the result of each
+func (h *RequestHandler) addPartitionsToTxn(t *protocol.AddPartitionsToTxnRequestAddPartitionsToTxnTransaction, version int16) []protocol.AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult {
+	var partitions []storage.TopicPartition
+	unknown := make(map[storage.TopicPartition]bool)
+	for _, topic := range t.Topics {
+		image := h.metadata.TopicByName(topic.Name)
+		for _, p := range topic.Partitions {
+			tp := storage.TopicPartition{Topic: topic.Name, Partition: p}
+			partitions = append(partitions, tp)
+			if image == nil {
+				unknown[tp] = true
+			} else if _, ok := image.Partition(p); !ok {
+				unknown[tp] = true
+			}
+		}
+	}
+
+	var results map[storage.TopicPartition]int16
+	errorCode := protocol.ErrorNone
+	switch {
+	case len(unknown) > 0:
+		errorCode = protocol.ErrorOperationNotAttempted
+	case t.VerifyOnly:
+		results, errorCode = h.txn.VerifyPartitions(t.TransactionalId, t.ProducerId, t.ProducerEpoch, partitions)
+	default:
+		errorCode = h.txn.AddPartitions(t.TransactionalId, t.ProducerId, t.ProducerEpoch, partitions)
+	}
+	errorCode = fencedError(errorCode, version, 2)
+
+	topicResults := make([]protocol.AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult, 0, len(t.Topics))
+	for _, topic := range t.Topics {
+		topicResult := protocol.AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			tp := storage.TopicPartition{Topic: topic.Name, Partition: p}
+			partitionError := errorCode
+			switch {
+			case unknown[tp]:
+				partitionError = protocol.ErrorUnknownTopic
+			case results != nil:
+				partitionError = results[tp]
+			}
+			topicResult.ResultsByPartition = append(topicResult.ResultsByPartition, protocol.AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult{
+				PartitionIndex:     p,
+				PartitionErrorCode: partitionError,
+			})
+		}
+		topicResults = append(topicResults, topicResult)
+	}
+	return topicResults
+}
+
+// addPartitionsToTxnTransactions returns the transactions a request adds
+// partitions to: a batch from v4, the single transaction of the top-level
+// fields before
+func addPartitionsToTxnTransactions(body *protocol.AddPartitionsToTxnRequest, version int16) []protocol.AddPartitionsToTxnRequestAddPartitionsToTxnTransaction {
+	if version >= 4 {
+		return body.Transactions
+	}
+	return []protocol.AddPartitionsToTxnRequestAddPartitionsToTxnTransaction{{
+		TransactionalId: body.V3AndBelowTransactionalId,
+		ProducerId:      body.V3AndBelowProducerId,
+		ProducerEpoch:   body.V3AndBelowProducerEpoch,
+		Topics:          body.V3AndBelowTopics,
+	}}
+}
+
+// addPartitionsToTxnResponse moves the single result of a pre-v4 response
+// into the top-level field those versions use
+func addPartitionsToTxnResponse(resp *protocol.AddPartitionsToTxnResponse, version int16) *protocol.AddPartitionsToTxnResponse {
+	if version >= 4 || len(resp.ResultsByTransaction) == 0 {
+		return resp
+	}
+	resp.ResultsByTopicV3AndBelow = resp.ResultsByTransaction[0].TopicResults
+	resp.ResultsByTransaction = nil
+	return resp
+}
+
+// addPartitionsToTxnErrorResponse builds an AddPartitionsToTxn response.
+// From v4 the error is top-level; before, every requested partition fails
+// with it.
+func (h *RequestHandler) addPartitionsToTxnErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.AddPartitionsToTxnRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.AddPartitionsToTxnResponse{}
+	resp.Default()
+	if req.ApiVersion >= 4 {
+		resp.ErrorCode = errorCode
+		return protocol.NewResponse(req, resp)
+	}
+	for _, topic := range body.V3AndBelowTopics {
+		result := protocol.AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			result.ResultsByPartition = append(result.ResultsByPartition, protocol.AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult{
+				PartitionIndex:     p,
+				PartitionErrorCode: errorCode,
+			})
+		}
+		resp.ResultsByTopicV3AndBelow = append(resp.ResultsByTopicV3AndBelow, result)
+	}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,124 @@
+// Package compression implements the codecs Kafka producers use for the
+// records of a batch: gzip, snappy, lz4 and zstd. Every codec is pure Go
+// and reads what the Java client and librdkafka write; compressed output
+// is meant to be read back by them, not to match their ratios.
+package compression
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Codec identifies a compression codec, numbered as in the attributes of
+// a RecordBatch
+type Codec int8
+
+// Codecs
+const (
+	None   Codec = 0
+	Gzip   Codec = 1
+	Snappy Codec = 2
+	LZ4    Codec = 3
+	Zstd   Codec = 4
+)
+
+var (
+	// ErrUnsupportedCodec is returned for codecs other than those above
+	ErrUnsupportedCodec = errors.New("unsupported compression codec")
+
+	// ErrCorrupt is returned when compressed data cannot be decoded
+	ErrCorrupt = errors.New("corrupt compressed data")
+
+	// ErrTooLarge is returned when data decompresses to more than the
+	// caller allowed
+	ErrTooLarge = errors.New("decompressed data is too large")
+)
+
+// codecNames are the names Kafka uses for codecs in configs
+var codecNames = map[Codec]string{
+	None:   "none",
+	Gzip:   "gzip",
+	Snappy: "snappy",
+	LZ4:    "lz4",
+	Zstd:   "zstd",
+}
+
+// String returns the codec's name as used in compression.type
+func (c Codec) String() string {
+	if name, ok := codecNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("codec(%d)", int8(c))
+}
+
+// ParseCodec returns the codec with the given name. Topic configs say
+// "uncompressed" where producers say "none"; both are accepted.
+func ParseCodec(name string) (Codec, error) {
+	if name == "uncompressed" {
+		return None, nil
+	}
+	for c, n := range codecNames {
+		if n == name {
+			return c, nil
+		}
+	}
+	return None, fmt.Errorf("%w: %q", ErrUnsupportedCodec, name)
+}
+
+// Compress compresses src with codec
+func Compress(codec Codec, src []byte) ([]byte, error) {
+	switch codec {
+	case None:
+		return src, nil
+	case Gzip:
+		return compressGzip(src)
+	case Snappy:
+		return compressSnappy(src), nil
+	case LZ4:
+		return compressLZ4(src), nil
+	case Zstd:
+		return compressZstd(src), nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnsupportedCodec, int8(codec))
+}
+
+// Decompress decompresses src, which was compressed with codec. It fails
+// with ErrTooLarge rather than produce more than maxSize bytes, so a small
+// batch cannot make the broker allocate without bound.
+func Decompress(codec Codec, src []byte, maxSize int) ([]byte, error) {
+	switch codec {
+	case None:
+		if len(src) > maxSize {
+			return nil, ErrTooLarge
+		}
+		return src, nil
+	case Gzip:
+		return decompressGzip(src, maxSize)
+	case Snappy:
+		return decompressSnappy(src, maxSize)
+	case LZ4:
+		return decompressLZ4(src, maxSize)
+	case Zstd:
+		return decompressZstd(src, maxSize)
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnsupportedCodec, int8(codec))
+}
+
+// corruptf returns an ErrCorrupt error describing what was wrong
+func corruptf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrCorrupt, fmt.Sprintf(format, args...))
+}
+
+// appendMatch appends a copy of the length bytes that start offset bytes
+// before the end of dst, byte by byte when the copy overlaps itself as LZ77
+// matches may
+func appendMatch(dst []byte, offset, length int) []byte {
+	start := len(dst) - offset
+	if offset >= length {
+		return append(dst, dst[start:start+length]...)
+	}
+	for i := 0; i < length; i++ {
+		dst = append(dst, dst[start+i])
+	}
+	return dst
+}
@@ -0,0 +1,240 @@
+package compression
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleText returns n bytes of text made of words picked by a fixed
+// generator, compressible but not trivially so
+func sampleText(n int) []byte {
+	words := strings.Fields("kafka broker partition offset record batch producer consumer " +
+		"topic leader replica segment index commit abort transaction fetch the a of and to in")
+	r := rand.New(rand.NewPCG(1, 2))
+	var b bytes.Buffer
+	for i := 0; b.Len() < n; i++ {
+		b.WriteString(words[r.IntN(len(words))])
+		if i%12 == 11 {
+			b.WriteByte('\n')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return b.Bytes()[:n]
+}
+
+// randomBytes returns n bytes that do not compress
+func randomBytes(n int) []byte {
+	r := rand.New(rand.NewPCG(3, 4))
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.Uint32())
+	}
+	return b
+}
+
+func TestRoundTrip(t *testing.T) {
+	inputs := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"one byte", []byte("a")},
+		{"short text", []byte("Hello, Kafka! Hello, Kafka! Hello, Kafka!")},
+		// Longer than a block of every codec, so frames hold several
+		{"text", sampleText(300000)},
+		{"one repeated byte", bytes.Repeat([]byte{'x'}, 300000)},
+		{"incompressible", randomBytes(300000)},
+	}
+	for _, codec := range []Codec{None, Gzip, Snappy, LZ4, Zstd} {
+		for _, in := range inputs {
+			t.Run(codec.String()+"/"+in.name, func(t *testing.T) {
+				compressed, err := Compress(codec, in.data)
+				if err != nil {
+					t.Fatalf("Compress: %v", err)
+				}
+				got, err := Decompress(codec, compressed, len(in.data))
+				if err != nil {
+					t.Fatalf("Decompress: %v", err)
+				}
+				if !bytes.Equal(got, in.data) {
+					t.Fatalf("round trip returned %d bytes differing from the %d compressed", len(got), len(in.data))
+				}
+				// Data that does not compress is stored with little overhead
+				if limit := len(in.data) + len(in.data)/100 + 64; len(compressed) > limit {
+					t.Errorf("compressed %d bytes to %d, more than %d", len(in.data), len(compressed), limit)
+				}
+			})
+		}
+	}
+}
+
+func TestLegacyLZ4RoundTrip(t *testing.T) {
+	for _, data := range [][]byte{nil, sampleText(300000), randomBytes(100000)} {
+		compressed := CompressLegacyLZ4(data)
+		got, err := DecompressLegacyLZ4(compressed, len(data))
+		if err != nil {
+			t.Fatalf("DecompressLegacyLZ4: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("round trip of %d bytes returned %d differing bytes", len(data), len(got))
+		}
+		// Only readers of the v0 format accept its descriptor checksum
+		if _, err := Decompress(LZ4, compressed, len(data)); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Decompress of a legacy frame: error = %v, want %v", err, ErrCorrupt)
+		}
+	}
+}
+
+// hexBytes decodes s, failing the test if it is not hex
+func hexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// The short frames below compress nothing or "Hello, Kafka! Hello, Kafka!
+// Hello, Kafka!". They were written by gzip 1.x (gzip -9 -n), the lz4 1.9.4
+// and zstd 1.5.6 command line tools, except for the snappy ones, which no
+// tool at hand writes: those are encoded by hand after the snappy format
+// description, as the reference encoder writes them.
+const (
+	hello = "Hello, Kafka! Hello, Kafka! Hello, Kafka!"
+
+	gzipEmpty = "1f8b080000000000020303000000000000000000"
+	gzipHello = "1f8b0800000000000203f348cdc9c9d751f04e4ccb4e5454f0c0cd0300fb2638d929000000"
+
+	// A literal of the first 14 bytes, then a 2-byte-offset copy of 27
+	// bytes from 14 back
+	snappyHello = "29" + "34" + "48656c6c6f2c204b61666b612120" + "6a0e00"
+	// The same block with xerial framing: magic, versions, block size
+	snappyXerialHello = "82534e4150505900" + "00000001" + "00000001" + "00000013" + snappyHello
+
+	lz4Empty = "04224d186440a700000000055dcc02"
+	lz4Hello = "04224d186440a718000000ef48656c6c6f2c204b61666b6121200e00035061666b61210000000080d2f74a"
+	// lz4Hello with the descriptor checksum old Kafka clients wrote, taken
+	// over the frame magic too
+	lz4LegacyHello = "04224d1864400c18000000ef48656c6c6f2c204b61666b6121200e00035061666b61210000000080d2f74a"
+
+	zstdEmpty = "28b52ffd240001000099e9d851"
+	zstdHello = "28b52ffd0458a500007048656c6c6f2c204b61666b6121200100114e2527dc59e5"
+)
+
+func TestDecompressReference(t *testing.T) {
+	sample, err := os.ReadFile(filepath.Join("testdata", "sample.txt.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sample, err = Decompress(Gzip, sample, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sample, sampleText(len(sample))) {
+		t.Fatal("testdata/sample.txt.gz does not hold sampleText")
+	}
+
+	tests := []struct {
+		name  string
+		codec Codec
+		// frame is hex data, or the name of a file in testdata that
+		// holds the compressed sample text
+		frame string
+		want  []byte
+	}{
+		{"gzip empty", Gzip, gzipEmpty, nil},
+		{"gzip", Gzip, gzipHello, []byte(hello)},
+		{"gzip multiple blocks", Gzip, "sample.txt.gz", sample},
+		{"gzip two members", Gzip, gzipHello + gzipHello, []byte(hello + hello)},
+		{"snappy bare block", Snappy, snappyHello, []byte(hello)},
+		{"snappy xerial", Snappy, snappyXerialHello, []byte(hello)},
+		{"lz4 empty", LZ4, lz4Empty, nil},
+		{"lz4", LZ4, lz4Hello, []byte(hello)},
+		// 64 KiB independent blocks with block checksums and the
+		// content size, and 64 KiB blocks matching into earlier ones
+		{"lz4 independent blocks", LZ4, "sample.txt.lz4", sample},
+		{"lz4 linked blocks", LZ4, "sample.txt.linked.lz4", sample},
+		{"lz4 two frames", LZ4, lz4Hello + lz4Empty + lz4Hello, []byte(hello + hello)},
+		{"zstd empty", Zstd, zstdEmpty, nil},
+		{"zstd", Zstd, zstdHello, []byte(hello)},
+		// Frames of more than one 128 KiB block: single segment at the
+		// default level and at level 19, and streamed without a content
+		// size or checksum
+		{"zstd multiple blocks", Zstd, "sample.txt.zst", sample},
+		{"zstd multiple blocks level 19", Zstd, "sample.txt.19.zst", sample},
+		{"zstd multiple blocks streamed", Zstd, "sample.txt.stream.zst", sample},
+		{"zstd two frames", Zstd, zstdHello + zstdEmpty + zstdHello, []byte(hello + hello)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var src []byte
+			if strings.HasPrefix(tt.frame, "sample.txt") {
+				if src, err = os.ReadFile(filepath.Join("testdata", tt.frame)); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				src = hexBytes(t, tt.frame)
+			}
+			got, err := Decompress(tt.codec, src, len(tt.want))
+			if err != nil {
+				t.Fatalf("Decompress: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Decompress = %d bytes %q, want %d bytes", len(got), truncate(got), len(tt.want))
+			}
+			if len(tt.want) > 0 {
+				if _, err := Decompress(tt.codec, src, len(tt.want)-1); !errors.Is(err, ErrTooLarge) {
+					t.Errorf("Decompress with room for one byte less: error = %v, want %v", err, ErrTooLarge)
+				}
+			}
+		})
+	}
+
+	// Legacy readers take both descriptor checksums
+	for _, frame := range []string{lz4Hello, lz4LegacyHello} {
+		got, err := DecompressLegacyLZ4(hexBytes(t, frame), len(hello))
+		if err != nil || string(got) != hello {
+			t.Errorf("DecompressLegacyLZ4(%s) = %q, %v, want %q", frame, got, err, hello)
+		}
+	}
+}
+
+// truncate returns the start of b, enough to tell what it holds
+func truncate(b []byte) []byte {
+	return b[:min(len(b), 32)]
+}
+
+func TestDecompressCorrupt(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+		frame string
+	}{
+		{"gzip truncated", Gzip, gzipHello[:len(gzipHello)-8]},
+		{"gzip bad checksum", Gzip, gzipHello[:len(gzipHello)-16] + "00000000" + gzipHello[len(gzipHello)-8:]},
+		{"snappy copy before the start", Snappy, "29" + "6a0e00"},
+		{"snappy length mismatch", Snappy, "2a" + snappyHello[2:]},
+		{"snappy xerial block overrun", Snappy, snappyXerialHello[:32] + "000000ff" + snappyHello},
+		{"lz4 truncated", LZ4, lz4Hello[:len(lz4Hello)-8]},
+		{"lz4 bad content checksum", LZ4, lz4Hello[:len(lz4Hello)-8] + "00000000"},
+		{"lz4 bad descriptor checksum", LZ4, lz4LegacyHello},
+		{"lz4 bad magic", LZ4, "05" + lz4Hello[2:]},
+		{"zstd truncated", Zstd, zstdHello[:len(zstdHello)-8]},
+		{"zstd bad content checksum", Zstd, zstdHello[:len(zstdHello)-8] + "00000000"},
+		{"zstd bad magic", Zstd, "29" + zstdHello[2:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decompress(tt.codec, hexBytes(t, tt.frame), 1<<20); !errors.Is(err, ErrCorrupt) {
+				t.Errorf("Decompress error = %v, want %v", err, ErrCorrupt)
+			}
+		})
+	}
+}
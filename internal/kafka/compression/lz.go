@@ -0,0 +1,79 @@
+package compression
+
+import "encoding/binary"
+
+// Parameters of the match finder shared by the LZ77 codecs
+const (
+	matchHashLog = 14
+	minMatch     = 4
+)
+
+// lzSequence is a run of literals followed by a match
+type lzSequence struct {
+	litLen   int
+	offset   int
+	matchLen int
+}
+
+// matchFinder splits data into literals and matches for the snappy, lz4
+// and zstd encoders. It is greedy and remembers only the last position of
+// each hashed 4-byte prefix: fast and simple rather than thorough.
+type matchFinder struct {
+	// table holds one plus the last position of each hashed prefix, zero
+	// marking an empty slot
+	table     []int32
+	maxOffset int
+	// literalTail and matchTail keep the end of a block free of matches,
+	// as lz4 requires: no match ends within literalTail bytes of the end
+	// nor starts within matchTail bytes of it
+	literalTail int
+	matchTail   int
+}
+
+// newMatchFinder returns a match finder for matches at most maxOffset
+// bytes back
+func newMatchFinder(maxOffset, literalTail, matchTail int) *matchFinder {
+	return &matchFinder{
+		table:       make([]int32, 1<<matchHashLog),
+		maxOffset:   maxOffset,
+		literalTail: literalTail,
+		matchTail:   max(matchTail, minMatch),
+	}
+}
+
+// hashPrefix hashes the 4 bytes that start a potential match
+func hashPrefix(v uint32) uint32 {
+	return (v * 2654435761) >> (32 - matchHashLog)
+}
+
+// parse appends to seqs the sequences that encode src[start:end], with
+// matches reaching back no further than base, and returns them with the
+// number of literals left after the last match. The table carries over
+// between calls on the same src, so consecutive blocks may match into one
+// another when base allows.
+func (m *matchFinder) parse(src []byte, base, start, end int, seqs []lzSequence) ([]lzSequence, int) {
+	lastStart := end - m.matchTail
+	matchEnd := end - m.literalTail
+
+	lit := start
+	for p := start; p < lastStart; {
+		v := binary.LittleEndian.Uint32(src[p:])
+		h := hashPrefix(v)
+		candidate := int(m.table[h]) - 1
+		m.table[h] = int32(p + 1)
+		if candidate < base || p-candidate > m.maxOffset || binary.LittleEndian.Uint32(src[candidate:]) != v {
+			// Step faster through data that is not matching
+			p += 1 + (p-lit)>>6
+			continue
+		}
+
+		n := minMatch
+		for p+n < matchEnd && src[candidate+n] == src[p+n] {
+			n++
+		}
+		seqs = append(seqs, lzSequence{litLen: p - lit, offset: p - candidate, matchLen: n})
+		p += n
+		lit = p
+	}
+	return seqs, end - lit
+}
@@ -0,0 +1,329 @@
+package compression
+
+import "encoding/binary"
+
+// LZ4 frame format constants. Kafka wraps lz4 blocks in the standard frame
+// format, though messages in the v0 format carry a broken descriptor
+// checksum (see CompressLegacyLZ4).
+const (
+	lz4FrameMagic     uint32 = 0x184d2204
+	lz4SkippableMagic uint32 = 0x184d2a50
+	lz4SkippableMask  uint32 = 0xfffffff0
+
+	// lz4 frame descriptor flags
+	lz4Version       = 0x40
+	lz4VersionMask   = 0xc0
+	lz4BlockIndep    = 0x20
+	lz4BlockChecksum = 0x10
+	lz4ContentSize   = 0x08
+	lz4ContentCheck  = 0x04
+	lz4Reserved      = 0x02
+	lz4DictID        = 0x01
+
+	// lz4BlockMax64KB is the block descriptor for 64 KiB blocks, the size
+	// the Java client uses
+	lz4BlockMax64KB = 4 << 4
+	lz4BlockSize    = 64 * 1024
+
+	// lz4Uncompressed marks a block stored as is
+	lz4Uncompressed = 1 << 31
+
+	lz4MaxOffset = 1<<16 - 1
+
+	// The last 5 bytes of a block are always literals, and the last match
+	// starts at least 12 bytes before its end
+	lz4LastLiterals = 5
+	lz4MatchLimit   = 12
+)
+
+// CompressLegacyLZ4 compresses src as lz4 for a message in the v0 format.
+// Kafka clients of that era checksummed the frame descriptor together with
+// the frame magic, and the clients reading v0 messages still expect it.
+func CompressLegacyLZ4(src []byte) []byte {
+	return compressLZ4Frame(src, true)
+}
+
+// DecompressLegacyLZ4 decompresses lz4 data from a message in the v0
+// format, accepting either the broken or the correct descriptor checksum
+func DecompressLegacyLZ4(src []byte, maxSize int) ([]byte, error) {
+	return decompressLZ4Frames(src, maxSize, true)
+}
+
+// compressLZ4 compresses src as one lz4 frame of independent 64 KiB blocks
+// followed by a content checksum
+func compressLZ4(src []byte) []byte {
+	return compressLZ4Frame(src, false)
+}
+
+// decompressLZ4 decompresses one or more lz4 frames, skipping skippable
+// frames
+func decompressLZ4(src []byte, maxSize int) ([]byte, error) {
+	return decompressLZ4Frames(src, maxSize, false)
+}
+
+// lz4DescriptorChecksum returns the checksum byte of a frame descriptor.
+// The legacy checksum also covers the frame magic before it.
+func lz4DescriptorChecksum(descriptor []byte, legacy bool) byte {
+	if legacy {
+		descriptor = append(binary.LittleEndian.AppendUint32(nil, lz4FrameMagic), descriptor...)
+	}
+	return byte(xxh32(descriptor) >> 8)
+}
+
+// compressLZ4Frame compresses src as one lz4 frame, with the legacy
+// descriptor checksum if asked
+func compressLZ4Frame(src []byte, legacy bool) []byte {
+	dst := make([]byte, 0, 16+len(src)+len(src)/255+16)
+	dst = binary.LittleEndian.AppendUint32(dst, lz4FrameMagic)
+	descriptor := []byte{lz4Version | lz4BlockIndep | lz4ContentCheck, lz4BlockMax64KB}
+	dst = append(dst, descriptor...)
+	dst = append(dst, lz4DescriptorChecksum(descriptor, legacy))
+
+	finder := newMatchFinder(lz4MaxOffset, lz4LastLiterals, lz4MatchLimit)
+	for rest, start := src, 0; len(rest) > 0; start += lz4BlockSize {
+		n := min(len(rest), lz4BlockSize)
+		sizeAt := len(dst)
+		dst = append(dst, 0, 0, 0, 0)
+		dst = encodeLZ4Block(dst, finder, src, start, start+n)
+		if size := len(dst) - sizeAt - 4; size < n {
+			binary.LittleEndian.PutUint32(dst[sizeAt:], uint32(size))
+		} else {
+			// Incompressible: store the block as is
+			dst = append(dst[:sizeAt+4], rest[:n]...)
+			binary.LittleEndian.PutUint32(dst[sizeAt:], uint32(n)|lz4Uncompressed)
+		}
+		rest = rest[n:]
+	}
+	dst = binary.LittleEndian.AppendUint32(dst, 0)
+	return binary.LittleEndian.AppendUint32(dst, xxh32(src))
+}
+
+// encodeLZ4Block appends the lz4 block encoding of src[start:end] to dst
+func encodeLZ4Block(dst []byte, finder *matchFinder, src []byte, start, end int) []byte {
+	seqs, tail := finder.parse(src, start, start, end, nil)
+	p := start
+	for _, s := range seqs {
+		dst = appendLZ4Sequence(dst, src[p:p+s.litLen], s.offset, s.matchLen)
+		p += s.litLen + s.matchLen
+	}
+	return appendLZ4Sequence(dst, src[end-tail:end], 0, 0)
+}
+
+// appendLZ4Sequence appends literals and the match that follows them. The
+// last sequence of a block has no match and is passed a zero offset.
+func appendLZ4Sequence(dst, literals []byte, offset, matchLen int) []byte {
+	token := byte(min(len(literals), 15)) << 4
+	if offset > 0 {
+		token |= byte(min(matchLen-minMatch, 15))
+	}
+	dst = append(dst, token)
+	dst = appendLZ4Length(dst, len(literals))
+	dst = append(dst, literals...)
+	if offset == 0 {
+		return dst
+	}
+	dst = binary.LittleEndian.AppendUint16(dst, uint16(offset))
+	return appendLZ4Length(dst, matchLen-minMatch)
+}
+
+// appendLZ4Length appends the bytes extending a length that did not fit
+// the 4 bits of the token
+func appendLZ4Length(dst []byte, n int) []byte {
+	if n < 15 {
+		return dst
+	}
+	for n -= 15; n >= 255; n -= 255 {
+		dst = append(dst, 255)
+	}
+	return append(dst, byte(n))
+}
+
+// decompressLZ4Frames decompresses one or more lz4 frames, skipping
+// skippable frames. Legacy data may use either descriptor checksum.
+func decompressLZ4Frames(src []byte, maxSize int, legacy bool) ([]byte, error) {
+	var dst []byte
+	for len(src) > 0 {
+		if len(src) < 4 {
+			return nil, corruptf("lz4: truncated frame")
+		}
+		magic := binary.LittleEndian.Uint32(src)
+		if magic&lz4SkippableMask == lz4SkippableMagic {
+			if len(src) < 8 {
+				return nil, corruptf("lz4: truncated skippable frame")
+			}
+			n := binary.LittleEndian.Uint32(src[4:])
+			if uint64(n) > uint64(len(src)-8) {
+				return nil, corruptf("lz4: skippable frame of %d bytes overruns data", n)
+			}
+			src = src[8+n:]
+			continue
+		}
+		if magic != lz4FrameMagic {
+			return nil, corruptf("lz4: bad frame magic %08x", magic)
+		}
+
+		var err error
+		if dst, src, err = decodeLZ4Frame(dst, src[4:], maxSize, legacy); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// decodeLZ4Frame appends the contents of the frame at the start of src,
+// past its magic, to dst and returns the data that follows the frame
+func decodeLZ4Frame(dst, src []byte, maxSize int, legacy bool) ([]byte, []byte, error) {
+	if len(src) < 3 {
+		return nil, nil, corruptf("lz4: truncated frame descriptor")
+	}
+	flags, bd := src[0], src[1]
+	if flags&lz4VersionMask != lz4Version {
+		return nil, nil, corruptf("lz4: unsupported frame version %d", flags>>6)
+	}
+	if flags&lz4Reserved != 0 || bd&0x8f != 0 {
+		return nil, nil, corruptf("lz4: reserved bits set in frame descriptor")
+	}
+	if flags&lz4DictID != 0 {
+		return nil, nil, corruptf("lz4: dictionaries are not supported")
+	}
+	if bd>>4 < 4 {
+		return nil, nil, corruptf("lz4: invalid block maximum size %d", bd>>4)
+	}
+	blockMax := 1 << (8 + 2*int(bd>>4))
+
+	descriptorSize := 2
+	if flags&lz4ContentSize != 0 {
+		descriptorSize += 8
+	}
+	if len(src) < descriptorSize+1 {
+		return nil, nil, corruptf("lz4: truncated frame descriptor")
+	}
+	checksum := src[descriptorSize]
+	if checksum != lz4DescriptorChecksum(src[:descriptorSize], false) &&
+		(!legacy || checksum != lz4DescriptorChecksum(src[:descriptorSize], true)) {
+		return nil, nil, corruptf("lz4: frame descriptor checksum mismatch")
+	}
+	if flags&lz4ContentSize != 0 {
+		if size := binary.LittleEndian.Uint64(src[2:]); size > uint64(maxSize-len(dst)) {
+			return nil, nil, ErrTooLarge
+		}
+	}
+	src = src[descriptorSize+1:]
+
+	frameStart := len(dst)
+	for {
+		if len(src) < 4 {
+			return nil, nil, corruptf("lz4: truncated block")
+		}
+		size := binary.LittleEndian.Uint32(src)
+		src = src[4:]
+		if size == 0 {
+			break
+		}
+		uncompressed := size&lz4Uncompressed != 0
+		size &^= lz4Uncompressed
+		if int(size) > blockMax || int(size) > len(src) {
+			return nil, nil, corruptf("lz4: block of %d bytes overruns frame", size)
+		}
+		block := src[:size]
+		src = src[size:]
+		if flags&lz4BlockChecksum != 0 {
+			if len(src) < 4 {
+				return nil, nil, corruptf("lz4: truncated block checksum")
+			}
+			if binary.LittleEndian.Uint32(src) != xxh32(block) {
+				return nil, nil, corruptf("lz4: block checksum mismatch")
+			}
+			src = src[4:]
+		}
+
+		if uncompressed {
+			if len(block) > maxSize-len(dst) {
+				return nil, nil, ErrTooLarge
+			}
+			dst = append(dst, block...)
+			continue
+		}
+		// Blocks that are not independent may match into the frame's
+		// earlier blocks
+		base := len(dst)
+		if flags&lz4BlockIndep == 0 {
+			base = frameStart
+		}
+		var err error
+		if dst, err = decodeLZ4Block(dst, block, base, maxSize); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if flags&lz4ContentCheck != 0 {
+		if len(src) < 4 {
+			return nil, nil, corruptf("lz4: truncated content checksum")
+		}
+		if binary.LittleEndian.Uint32(src) != xxh32(dst[frameStart:]) {
+			return nil, nil, corruptf("lz4: content checksum mismatch")
+		}
+		src = src[4:]
+	}
+	return dst, src, nil
+}
+
+// decodeLZ4Block appends the decoding of an lz4 block to dst. Matches may
+// reach back to base, and dst may not grow past limit.
+func decodeLZ4Block(dst, src []byte, base, limit int) ([]byte, error) {
+	for len(src) > 0 {
+		token := src[0]
+		src = src[1:]
+
+		n, rest, ok := readLZ4Length(src, int(token>>4))
+		if !ok || n > len(rest) {
+			return nil, corruptf("lz4: literals overrun block")
+		}
+		if n > limit-len(dst) {
+			return nil, ErrTooLarge
+		}
+		dst = append(dst, rest[:n]...)
+		src = rest[n:]
+		if len(src) == 0 {
+			// The last sequence has no match
+			return dst, nil
+		}
+
+		if len(src) < 2 {
+			return nil, corruptf("lz4: truncated match offset")
+		}
+		offset := int(binary.LittleEndian.Uint16(src))
+		if offset == 0 || offset > len(dst)-base {
+			return nil, corruptf("lz4: match offset %d is out of range", offset)
+		}
+		n, src, ok = readLZ4Length(src[2:], int(token&0x0f))
+		if !ok {
+			return nil, corruptf("lz4: truncated match length")
+		}
+		n += minMatch
+		if n > limit-len(dst) {
+			return nil, ErrTooLarge
+		}
+		dst = appendMatch(dst, offset, n)
+	}
+	return nil, corruptf("lz4: block does not end with literals")
+}
+
+// readLZ4Length completes a length from its 4 bits in the token, reading
+// the bytes that extend it when they are all set
+func readLZ4Length(src []byte, n int) (int, []byte, bool) {
+	if n < 15 {
+		return n, src, true
+	}
+	for {
+		if len(src) == 0 {
+			return 0, nil, false
+		}
+		b := src[0]
+		src = src[1:]
+		n += int(b)
+		if b != 255 {
+			return n, src, true
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package compression
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// The Java client wraps snappy in the framing of the xerial snappy-java
+// library: a header, then blocks each prefixed with their big-endian
+// size. Other clients send a bare snappy block, so both are read.
+var xerialMagic = []byte{0x82, 'S', 'N', 'A', 'P', 'P', 'Y', 0}
+
+const (
+	// xerialHeaderSize is the size of the magic plus the version and
+	// minimum compatible version, both 1
+	xerialHeaderSize = 16
+	xerialVersion    = 1
+
+	// xerialBlockSize is how much input each xerial block holds, as in
+	// snappy-java
+	xerialBlockSize = 32 * 1024
+
+	// snappyMaxOffset is the furthest back a copy may reach. Longer
+	// input is encoded in chunks of this size matching only within
+	// themselves, as the reference encoder does.
+	snappyMaxOffset = 1<<16 - 1
+)
+
+// Snappy element tags, in the low two bits of the tag byte
+const (
+	snappyLiteral = 0
+	snappyCopy1   = 1
+	snappyCopy2   = 2
+	snappyCopy4   = 3
+)
+
+// compressSnappy compresses src with xerial framing
+func compressSnappy(src []byte) []byte {
+	dst := make([]byte, 0, xerialHeaderSize+len(src)+len(src)/6+32)
+	dst = append(dst, xerialMagic...)
+	dst = binary.BigEndian.AppendUint32(dst, xerialVersion)
+	dst = binary.BigEndian.AppendUint32(dst, xerialVersion)
+	for len(src) > 0 {
+		n := min(len(src), xerialBlockSize)
+		sizeAt := len(dst)
+		dst = append(dst, 0, 0, 0, 0)
+		dst = encodeSnappyBlock(dst, src[:n])
+		binary.BigEndian.PutUint32(dst[sizeAt:], uint32(len(dst)-sizeAt-4))
+		src = src[n:]
+	}
+	return dst
+}
+
+// decompressSnappy decompresses a bare snappy block or xerial-framed blocks
+func decompressSnappy(src []byte, maxSize int) ([]byte, error) {
+	if len(src) < xerialHeaderSize || !bytes.HasPrefix(src, xerialMagic) {
+		return decodeSnappyBlock(nil, src, maxSize)
+	}
+
+	var dst []byte
+	for src = src[xerialHeaderSize:]; len(src) > 0; {
+		if len(src) < 4 {
+			return nil, corruptf("snappy: truncated block size")
+		}
+		n := binary.BigEndian.Uint32(src)
+		src = src[4:]
+		if uint64(n) > uint64(len(src)) {
+			return nil, corruptf("snappy: block of %d bytes overruns data", n)
+		}
+		var err error
+		if dst, err = decodeSnappyBlock(dst, src[:n], maxSize); err != nil {
+			return nil, err
+		}
+		src = src[n:]
+	}
+	return dst, nil
+}
+
+// encodeSnappyBlock appends the snappy block encoding of src to dst
+func encodeSnappyBlock(dst, src []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+
+	finder := newMatchFinder(snappyMaxOffset, 0, 0)
+	var seqs []lzSequence
+	for start := 0; start < len(src); start += snappyMaxOffset {
+		end := min(start+snappyMaxOffset, len(src))
+		var tail int
+		seqs, tail = finder.parse(src, start, start, end, seqs[:0])
+		p := start
+		for _, s := range seqs {
+			dst = appendSnappyLiteral(dst, src[p:p+s.litLen])
+			dst = appendSnappyCopy(dst, s.offset, s.matchLen)
+			p += s.litLen + s.matchLen
+		}
+		dst = appendSnappyLiteral(dst, src[end-tail:end])
+	}
+	return dst
+}
+
+// appendSnappyLiteral appends a literal element holding lit
+func appendSnappyLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	switch n := len(lit) - 1; {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|snappyLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|snappyLiteral, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2|snappyLiteral, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2|snappyLiteral, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2|snappyLiteral, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, lit...)
+}
+
+// appendSnappyCopy appends the copy elements for a match, which is at
+// least 4 bytes long and at most snappyMaxOffset bytes back
+func appendSnappyCopy(dst []byte, offset, length int) []byte {
+	// A copy element holds at most 64 bytes; keep the remainder at 4 or
+	// more so it still fits one
+	for length >= 68 {
+		dst = append(dst, 63<<2|snappyCopy2, byte(offset), byte(offset>>8))
+		length -= 64
+	}
+	if length > 64 {
+		dst = append(dst, 59<<2|snappyCopy2, byte(offset), byte(offset>>8))
+		length -= 60
+	}
+	if length < 12 && offset < 2048 {
+		return append(dst, byte(offset>>8)<<5|byte(length-4)<<2|snappyCopy1, byte(offset))
+	}
+	return append(dst, byte(length-1)<<2|snappyCopy2, byte(offset), byte(offset>>8))
+}
+
+// decodeSnappyBlock appends the decoding of a snappy block to dst, failing
+// if dst would grow past maxSize
+func decodeSnappyBlock(dst, src []byte, maxSize int) ([]byte, error) {
+	size, k := binary.Uvarint(src)
+	if k <= 0 {
+		return nil, corruptf("snappy: bad block length")
+	}
+	if size > uint64(maxSize-len(dst)) {
+		return nil, ErrTooLarge
+	}
+	src = src[k:]
+	start := len(dst)
+	want := start + int(size)
+	if cap(dst) < want {
+		dst = append(make([]byte, 0, want), dst...)
+	}
+
+	for len(src) > 0 {
+		tag := src[0]
+		var offset, length int
+		switch tag & 3 {
+		case snappyLiteral:
+			length = int(tag >> 2)
+			src = src[1:]
+			if length >= 60 {
+				n := length - 59
+				if len(src) < n {
+					return nil, corruptf("snappy: truncated literal length")
+				}
+				length = 0
+				for i := n - 1; i >= 0; i-- {
+					length = length<<8 | int(src[i])
+				}
+				src = src[n:]
+			}
+			length++
+			if length > len(src) || length > want-len(dst) {
+				return nil, corruptf("snappy: literal of %d bytes overruns block", length)
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case snappyCopy1:
+			if len(src) < 2 {
+				return nil, corruptf("snappy: truncated copy")
+			}
+			length = 4 + int(tag>>2&0x07)
+			offset = int(tag&0xe0)<<3 | int(src[1])
+			src = src[2:]
+		case snappyCopy2:
+			if len(src) < 3 {
+				return nil, corruptf("snappy: truncated copy")
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case snappyCopy4:
+			if len(src) < 5 {
+				return nil, corruptf("snappy: truncated copy")
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+		if offset <= 0 || offset > len(dst)-start || length > want-len(dst) {
+			return nil, corruptf("snappy: copy of %d bytes at offset %d is out of range", length, offset)
+		}
+		dst = appendMatch(dst, offset, length)
+	}
+	if len(dst) != want {
+		return nil, corruptf("snappy: block decoded to %d bytes, expected %d", len(dst)-start, size)
+	}
+	return dst, nil
+}
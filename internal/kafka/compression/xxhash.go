@@ -0,0 +1,126 @@
+package compression
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// xxHash32 primes. They are variables so seeding the accumulators may
+// wrap around as the algorithm intends.
+var (
+	prime32x1 uint32 = 2654435761
+	prime32x2 uint32 = 2246822519
+	prime32x3 uint32 = 3266489917
+	prime32x4 uint32 = 668265263
+	prime32x5 uint32 = 374761393
+)
+
+// xxHash64 primes, likewise
+var (
+	prime64x1 uint64 = 11400714785074694791
+	prime64x2 uint64 = 14029467366897019727
+	prime64x3 uint64 = 1609587929392839161
+	prime64x4 uint64 = 9650029242287828579
+	prime64x5 uint64 = 2870177450012600261
+)
+
+// xxh32 computes the 32-bit xxHash of data with a zero seed, which LZ4
+// frames use for their checksums
+func xxh32(data []byte) uint32 {
+	n := len(data)
+	var h uint32
+	if n >= 16 {
+		v1 := prime32x1 + prime32x2
+		v2 := prime32x2
+		v3 := uint32(0)
+		v4 := -prime32x1
+		round := func(v, lane uint32) uint32 {
+			return bits.RotateLeft32(v+lane*prime32x2, 13) * prime32x1
+		}
+		for ; len(data) >= 16; data = data[16:] {
+			v1 = round(v1, binary.LittleEndian.Uint32(data[0:]))
+			v2 = round(v2, binary.LittleEndian.Uint32(data[4:]))
+			v3 = round(v3, binary.LittleEndian.Uint32(data[8:]))
+			v4 = round(v4, binary.LittleEndian.Uint32(data[12:]))
+		}
+		h = bits.RotateLeft32(v1, 1) + bits.RotateLeft32(v2, 7) + bits.RotateLeft32(v3, 12) + bits.RotateLeft32(v4, 18)
+	} else {
+		h = prime32x5
+	}
+
+	h += uint32(n)
+	for ; len(data) >= 4; data = data[4:] {
+		h += binary.LittleEndian.Uint32(data) * prime32x3
+		h = bits.RotateLeft32(h, 17) * prime32x4
+	}
+	for _, b := range data {
+		h += uint32(b) * prime32x5
+		h = bits.RotateLeft32(h, 11) * prime32x1
+	}
+
+	h ^= h >> 15
+	h *= prime32x2
+	h ^= h >> 13
+	h *= prime32x3
+	h ^= h >> 16
+	return h
+}
+
+// xxh64Round mixes one 8-byte lane into an accumulator
+func xxh64Round(acc, lane uint64) uint64 {
+	return bits.RotateLeft64(acc+lane*prime64x2, 31) * prime64x1
+}
+
+// xxh64Merge folds an accumulator into the hash
+func xxh64Merge(h, acc uint64) uint64 {
+	h ^= xxh64Round(0, acc)
+	return h*prime64x1 + prime64x4
+}
+
+// xxh64 computes the 64-bit xxHash of data with a zero seed, whose low
+// 32 bits are a zstd frame's content checksum
+func xxh64(data []byte) uint64 {
+	n := len(data)
+	var h uint64
+	if n >= 32 {
+		v1 := prime64x1 + prime64x2
+		v2 := prime64x2
+		v3 := uint64(0)
+		v4 := -prime64x1
+		for ; len(data) >= 32; data = data[32:] {
+			v1 = xxh64Round(v1, binary.LittleEndian.Uint64(data[0:]))
+			v2 = xxh64Round(v2, binary.LittleEndian.Uint64(data[8:]))
+			v3 = xxh64Round(v3, binary.LittleEndian.Uint64(data[16:]))
+			v4 = xxh64Round(v4, binary.LittleEndian.Uint64(data[24:]))
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxh64Merge(h, v1)
+		h = xxh64Merge(h, v2)
+		h = xxh64Merge(h, v3)
+		h = xxh64Merge(h, v4)
+	} else {
+		h = prime64x5
+	}
+
+	h += uint64(n)
+	for ; len(data) >= 8; data = data[8:] {
+		h ^= xxh64Round(0, binary.LittleEndian.Uint64(data))
+		h = bits.RotateLeft64(h, 27)*prime64x1 + prime64x4
+	}
+	if len(data) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(data)) * prime64x1
+		h = bits.RotateLeft64(h, 23)*prime64x2 + prime64x3
+		data = data[4:]
+	}
+	for _, b := range data {
+		h ^= uint64(b) * prime64x5
+		h = bits.RotateLeft64(h, 11) * prime64x1
+	}
+
+	h ^= h >> 33
+	h *= prime64x2
+	h ^= h >> 29
+	h *= prime64x3
+	h ^= h >> 32
+	return h
+}
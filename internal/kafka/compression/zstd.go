@@ -0,0 +1,597 @@
+package compression
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Zstandard (RFC 8878) framing constants
+const (
+	zstdMagic          uint32 = 0xfd2fb528
+	zstdSkippableMagic uint32 = 0x184d2a50
+	zstdSkippableMask  uint32 = 0xfffffff0
+
+	// zstdMaxBlockSize bounds both the compressed and decompressed size of
+	// a block
+	zstdMaxBlockSize = 128 * 1024
+
+	// Frame header descriptor bits
+	zstdSingleSegment   = 0x20
+	zstdReservedBit     = 0x08
+	zstdContentChecksum = 0x04
+)
+
+// Block types
+const (
+	zstdBlockRaw        = 0
+	zstdBlockRLE        = 1
+	zstdBlockCompressed = 2
+)
+
+// Literals block types
+const (
+	zstdLiteralsRaw        = 0
+	zstdLiteralsRLE        = 1
+	zstdLiteralsCompressed = 2
+	zstdLiteralsTreeless   = 3
+)
+
+// Sequence table modes
+const (
+	zstdModePredefined = 0
+	zstdModeRLE        = 1
+	zstdModeFSE        = 2
+	zstdModeRepeat     = 3
+)
+
+// Kinds of sequence codes, each with its own FSE table
+const (
+	zstdLiteralLengths = iota
+	zstdOffsets
+	zstdMatchLengths
+)
+
+// Largest code and accuracy log of each kind of sequence code
+var (
+	zstdMaxCode        = [3]int{35, 31, 52}
+	zstdMaxAccuracyLog = [3]int{9, 8, 9}
+)
+
+// Literal length codes: the smallest length each stands for and how many
+// extra bits follow it
+var (
+	zstdLiteralLengthBase = []int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096,
+		8192, 16384, 32768, 65536,
+	}
+	zstdLiteralLengthBits = []int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+)
+
+// Match length codes, likewise
+var (
+	zstdMatchLengthBase = []int{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
+		19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34,
+		35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051,
+		4099, 8195, 16387, 32771, 65539,
+	}
+	zstdMatchLengthBits = []int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11,
+		12, 13, 14, 15, 16,
+	}
+)
+
+// Predefined distributions of the sequence codes, with their accuracy logs
+var (
+	zstdPredefinedCounts = [3][]int16{
+		{
+			4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+			2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+			-1, -1, -1, -1,
+		},
+		{
+			1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+		},
+		{
+			1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+			-1, -1, -1, -1, -1,
+		},
+	}
+	zstdPredefinedAccuracyLog = [3]int{6, 5, 6}
+)
+
+// zstdPredefinedTables are the decoding tables of the predefined
+// distributions
+var zstdPredefinedTables = func() [3]*fseTable {
+	var tables [3]*fseTable
+	for kind, counts := range zstdPredefinedCounts {
+		t, err := buildFSETable(counts, zstdPredefinedAccuracyLog[kind])
+		if err != nil {
+			panic(err)
+		}
+		tables[kind] = t
+	}
+	return tables
+}()
+
+// zstdFrameDecoder holds what a frame's blocks inherit from the ones
+// before them
+type zstdFrameDecoder struct {
+	// frameStart is where the frame's content starts in the output, the
+	// furthest back a match may reach
+	frameStart int
+	maxSize    int
+	repeats    [3]int
+	huffman    *huffmanTable
+	tables     [3]*fseTable
+}
+
+// decompressZstd decompresses one or more zstd frames, skipping skippable
+// frames
+func decompressZstd(src []byte, maxSize int) ([]byte, error) {
+	var dst []byte
+	for len(src) > 0 {
+		if len(src) < 4 {
+			return nil, corruptf("zstd: truncated frame")
+		}
+		magic := binary.LittleEndian.Uint32(src)
+		if magic&zstdSkippableMask == zstdSkippableMagic {
+			if len(src) < 8 {
+				return nil, corruptf("zstd: truncated skippable frame")
+			}
+			n := binary.LittleEndian.Uint32(src[4:])
+			if uint64(n) > uint64(len(src)-8) {
+				return nil, corruptf("zstd: skippable frame of %d bytes overruns data", n)
+			}
+			src = src[8+n:]
+			continue
+		}
+		if magic != zstdMagic {
+			return nil, corruptf("zstd: bad frame magic %08x", magic)
+		}
+
+		var err error
+		if dst, src, err = decodeZstdFrame(dst, src[4:], maxSize); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// decodeZstdFrame appends the contents of the frame at the start of src,
+// past its magic, to dst and returns the data that follows the frame
+func decodeZstdFrame(dst, src []byte, maxSize int) ([]byte, []byte, error) {
+	if len(src) < 1 {
+		return nil, nil, corruptf("zstd: truncated frame header")
+	}
+	descriptor := src[0]
+	if descriptor&zstdReservedBit != 0 {
+		return nil, nil, corruptf("zstd: reserved bit set in frame header")
+	}
+	singleSegment := descriptor&zstdSingleSegment != 0
+	dictIDSize := []int{0, 1, 2, 4}[descriptor&0x03]
+	contentSizeSize := []int{0, 2, 4, 8}[descriptor>>6]
+	if singleSegment && contentSizeSize == 0 {
+		contentSizeSize = 1
+	}
+	headerSize := 1 + dictIDSize + contentSizeSize
+	if !singleSegment {
+		headerSize++
+	}
+	if len(src) < headerSize {
+		return nil, nil, corruptf("zstd: truncated frame header")
+	}
+
+	// The window only matters to decoders that stream; this one keeps
+	// the whole frame, so the window descriptor is skipped
+	p := 1
+	if !singleSegment {
+		p++
+	}
+	dictID := readLittleEndian(src[p : p+dictIDSize])
+	p += dictIDSize
+	if dictID != 0 {
+		return nil, nil, corruptf("zstd: dictionaries are not supported")
+	}
+	contentSize := int64(-1)
+	if contentSizeSize > 0 {
+		contentSize = int64(readLittleEndian(src[p : p+contentSizeSize]))
+		if contentSizeSize == 2 {
+			contentSize += 256
+		}
+		if contentSize < 0 || contentSize > int64(maxSize-len(dst)) {
+			return nil, nil, ErrTooLarge
+		}
+		if cap(dst)-len(dst) < int(contentSize) {
+			dst = append(make([]byte, 0, len(dst)+int(contentSize)), dst...)
+		}
+	}
+	src = src[headerSize:]
+
+	d := &zstdFrameDecoder{frameStart: len(dst), maxSize: maxSize, repeats: [3]int{1, 4, 8}}
+	for last := false; !last; {
+		if len(src) < 3 {
+			return nil, nil, corruptf("zstd: truncated block header")
+		}
+		header := int(src[0]) | int(src[1])<<8 | int(src[2])<<16
+		src = src[3:]
+		last = header&1 != 0
+		size := header >> 3
+		if size > zstdMaxBlockSize {
+			return nil, nil, corruptf("zstd: block of %d bytes exceeds the maximum", size)
+		}
+
+		var err error
+		switch header >> 1 & 0x03 {
+		case zstdBlockRaw:
+			if size > len(src) {
+				return nil, nil, corruptf("zstd: block of %d bytes overruns frame", size)
+			}
+			if size > maxSize-len(dst) {
+				return nil, nil, ErrTooLarge
+			}
+			dst = append(dst, src[:size]...)
+			src = src[size:]
+		case zstdBlockRLE:
+			if len(src) < 1 {
+				return nil, nil, corruptf("zstd: truncated block")
+			}
+			if size > maxSize-len(dst) {
+				return nil, nil, ErrTooLarge
+			}
+			dst = append(dst, bytes.Repeat(src[:1], size)...)
+			src = src[1:]
+		case zstdBlockCompressed:
+			if size > len(src) {
+				return nil, nil, corruptf("zstd: block of %d bytes overruns frame", size)
+			}
+			if dst, err = d.decodeBlock(dst, src[:size]); err != nil {
+				return nil, nil, err
+			}
+			src = src[size:]
+		default:
+			return nil, nil, corruptf("zstd: reserved block type")
+		}
+	}
+
+	if contentSize >= 0 && int64(len(dst)-d.frameStart) != contentSize {
+		return nil, nil, corruptf("zstd: frame decoded to %d bytes, header says %d", len(dst)-d.frameStart, contentSize)
+	}
+	if descriptor&zstdContentChecksum != 0 {
+		if len(src) < 4 {
+			return nil, nil, corruptf("zstd: truncated content checksum")
+		}
+		if binary.LittleEndian.Uint32(src) != uint32(xxh64(dst[d.frameStart:])) {
+			return nil, nil, corruptf("zstd: content checksum mismatch")
+		}
+		src = src[4:]
+	}
+	return dst, src, nil
+}
+
+// readLittleEndian reads an unsigned little-endian integer of up to 8 bytes
+func readLittleEndian(b []byte) uint64 {
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
+
+// decodeBlock appends the contents of a compressed block to dst
+func (d *zstdFrameDecoder) decodeBlock(dst, src []byte) ([]byte, error) {
+	literals, n, err := d.decodeLiterals(src)
+	if err != nil {
+		return nil, err
+	}
+	blockStart := len(dst)
+	if dst, err = d.decodeSequences(dst, src[n:], literals); err != nil {
+		return nil, err
+	}
+	if len(dst)-blockStart > zstdMaxBlockSize {
+		return nil, corruptf("zstd: block decoded to more than the maximum block size")
+	}
+	return dst, nil
+}
+
+// decodeLiterals decodes the literals section of a block, returning the
+// literals and the size of the section
+func (d *zstdFrameDecoder) decodeLiterals(src []byte) ([]byte, int, error) {
+	if len(src) < 1 {
+		return nil, 0, corruptf("zstd: missing literals section")
+	}
+	b0 := int(src[0])
+	blockType := b0 & 0x03
+	sizeFormat := b0 >> 2 & 0x03
+
+	if blockType == zstdLiteralsRaw || blockType == zstdLiteralsRLE {
+		var size, headerSize int
+		switch sizeFormat {
+		case 0, 2:
+			size, headerSize = b0>>3, 1
+		case 1:
+			if len(src) < 2 {
+				return nil, 0, corruptf("zstd: truncated literals header")
+			}
+			size, headerSize = b0>>4|int(src[1])<<4, 2
+		case 3:
+			if len(src) < 3 {
+				return nil, 0, corruptf("zstd: truncated literals header")
+			}
+			size, headerSize = b0>>4|int(src[1])<<4|int(src[2])<<12, 3
+		}
+		if size > zstdMaxBlockSize {
+			return nil, 0, corruptf("zstd: %d literals exceed the maximum block size", size)
+		}
+		if blockType == zstdLiteralsRLE {
+			if len(src) < headerSize+1 {
+				return nil, 0, corruptf("zstd: truncated literals")
+			}
+			return bytes.Repeat(src[headerSize:headerSize+1], size), headerSize + 1, nil
+		}
+		if len(src) < headerSize+size {
+			return nil, 0, corruptf("zstd: truncated literals")
+		}
+		return src[headerSize : headerSize+size], headerSize + size, nil
+	}
+
+	// Huffman-coded literals, in one stream or four
+	var regenerated, compressed, headerSize int
+	streams := 4
+	switch sizeFormat {
+	case 0, 1:
+		if sizeFormat == 0 {
+			streams = 1
+		}
+		if len(src) < 3 {
+			return nil, 0, corruptf("zstd: truncated literals header")
+		}
+		h := b0 | int(src[1])<<8 | int(src[2])<<16
+		regenerated, compressed, headerSize = h>>4&0x3ff, h>>14&0x3ff, 3
+	case 2:
+		if len(src) < 4 {
+			return nil, 0, corruptf("zstd: truncated literals header")
+		}
+		h := int(binary.LittleEndian.Uint32(src))
+		regenerated, compressed, headerSize = h>>4&0x3fff, h>>18&0x3fff, 4
+	case 3:
+		if len(src) < 5 {
+			return nil, 0, corruptf("zstd: truncated literals header")
+		}
+		h := int(binary.LittleEndian.Uint32(src)) | int(src[4])<<32
+		regenerated, compressed, headerSize = h>>4&0x3ffff, h>>22&0x3ffff, 5
+	}
+	if regenerated > zstdMaxBlockSize {
+		return nil, 0, corruptf("zstd: %d literals exceed the maximum block size", regenerated)
+	}
+	if len(src) < headerSize+compressed {
+		return nil, 0, corruptf("zstd: truncated literals")
+	}
+	data := src[headerSize : headerSize+compressed]
+
+	if blockType == zstdLiteralsCompressed {
+		table, n, err := readHuffmanTable(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		d.huffman = table
+		data = data[n:]
+	} else if d.huffman == nil {
+		return nil, 0, corruptf("zstd: literals reuse a Huffman table that was never sent")
+	}
+
+	literals := make([]byte, 0, regenerated)
+	var err error
+	if streams == 1 {
+		literals, err = d.huffman.decode(literals, data, regenerated)
+	} else {
+		literals, err = d.decodeFourStreams(literals, data, regenerated)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return literals, headerSize + compressed, nil
+}
+
+// decodeFourStreams decodes literals split over four Huffman-coded
+// streams, whose sizes but the last are given by a jump table
+func (d *zstdFrameDecoder) decodeFourStreams(dst, src []byte, n int) ([]byte, error) {
+	if len(src) < 6 {
+		return nil, corruptf("zstd: truncated literals jump table")
+	}
+	var sizes [4]int
+	rest := len(src) - 6
+	for i := 0; i < 3; i++ {
+		sizes[i] = int(binary.LittleEndian.Uint16(src[2*i:]))
+		rest -= sizes[i]
+	}
+	sizes[3] = rest
+	if rest < 0 {
+		return nil, corruptf("zstd: literals jump table overruns literals")
+	}
+	segment := (n + 3) / 4
+	if 3*segment > n {
+		return nil, corruptf("zstd: too few literals for four streams")
+	}
+
+	src = src[6:]
+	var err error
+	for i, size := range sizes {
+		count := segment
+		if i == 3 {
+			count = n - 3*segment
+		}
+		if dst, err = d.huffman.decode(dst, src[:size], count); err != nil {
+			return nil, err
+		}
+		src = src[size:]
+	}
+	return dst, nil
+}
+
+// decodeSequences decodes the sequences section of a block and executes
+// the sequences, appending the block's contents to dst
+func (d *zstdFrameDecoder) decodeSequences(dst, src, literals []byte) ([]byte, error) {
+	if len(src) < 1 {
+		return nil, corruptf("zstd: missing sequences section")
+	}
+	var count, p int
+	switch b0 := int(src[0]); {
+	case b0 < 128:
+		count, p = b0, 1
+	case b0 < 255:
+		if len(src) < 2 {
+			return nil, corruptf("zstd: truncated sequences header")
+		}
+		count, p = (b0-128)<<8|int(src[1]), 2
+	default:
+		if len(src) < 3 {
+			return nil, corruptf("zstd: truncated sequences header")
+		}
+		count, p = int(binary.LittleEndian.Uint16(src[1:]))+0x7f00, 3
+	}
+	if count == 0 {
+		if p != len(src) {
+			return nil, corruptf("zstd: trailing data after an empty sequences section")
+		}
+		return d.appendLiterals(dst, literals)
+	}
+
+	if len(src) < p+1 {
+		return nil, corruptf("zstd: truncated sequences header")
+	}
+	modes := src[p]
+	p++
+	if modes&0x03 != 0 {
+		return nil, corruptf("zstd: reserved bits set in sequence modes")
+	}
+	for kind, shift := range [3]int{6, 4, 2} {
+		n, err := d.readSequenceTable(kind, int(modes>>shift&0x03), src[p:])
+		if err != nil {
+			return nil, err
+		}
+		p += n
+	}
+
+	r, err := newBackwardBits(src[p:])
+	if err != nil {
+		return nil, err
+	}
+	ll, of, ml := d.tables[zstdLiteralLengths], d.tables[zstdOffsets], d.tables[zstdMatchLengths]
+	llState := int(r.read(ll.accuracyLog))
+	ofState := int(r.read(of.accuracyLog))
+	mlState := int(r.read(ml.accuracyLog))
+	for i := 0; i < count; i++ {
+		llEntry, ofEntry, mlEntry := ll.entries[llState], of.entries[ofState], ml.entries[mlState]
+
+		// Extra bits come offset first, then match and literal length
+		offsetValue := 1<<ofEntry.symbol + int(r.read(int(ofEntry.symbol)))
+		matchLength := zstdMatchLengthBase[mlEntry.symbol] + int(r.read(zstdMatchLengthBits[mlEntry.symbol]))
+		literalLength := zstdLiteralLengthBase[llEntry.symbol] + int(r.read(zstdLiteralLengthBits[llEntry.symbol]))
+		offset := d.resolveOffset(offsetValue, literalLength)
+
+		if literalLength > len(literals) {
+			return nil, corruptf("zstd: sequence uses more literals than the block has")
+		}
+		if literalLength+matchLength > d.maxSize-len(dst) {
+			return nil, ErrTooLarge
+		}
+		dst = append(dst, literals[:literalLength]...)
+		literals = literals[literalLength:]
+		if offset <= 0 || offset > len(dst)-d.frameStart {
+			return nil, corruptf("zstd: match offset %d is out of range", offset)
+		}
+		dst = appendMatch(dst, offset, matchLength)
+
+		if i < count-1 {
+			llState = int(llEntry.base) + int(r.read(int(llEntry.nbBits)))
+			mlState = int(mlEntry.base) + int(r.read(int(mlEntry.nbBits)))
+			ofState = int(ofEntry.base) + int(r.read(int(ofEntry.nbBits)))
+		}
+		if r.pos < 0 {
+			return nil, corruptf("zstd: sequences overrun their bitstream")
+		}
+	}
+	if r.pos != 0 {
+		return nil, corruptf("zstd: trailing bits after the last sequence")
+	}
+	return d.appendLiterals(dst, literals)
+}
+
+// appendLiterals appends the literals that follow a block's last sequence
+func (d *zstdFrameDecoder) appendLiterals(dst, literals []byte) ([]byte, error) {
+	if len(literals) > d.maxSize-len(dst) {
+		return nil, ErrTooLarge
+	}
+	return append(dst, literals...), nil
+}
+
+// readSequenceTable sets the table for one kind of sequence code as mode
+// says, returning the number of bytes of src it used
+func (d *zstdFrameDecoder) readSequenceTable(kind, mode int, src []byte) (int, error) {
+	switch mode {
+	case zstdModePredefined:
+		d.tables[kind] = zstdPredefinedTables[kind]
+		return 0, nil
+	case zstdModeRLE:
+		if len(src) < 1 {
+			return 0, corruptf("zstd: truncated sequence table")
+		}
+		if int(src[0]) > zstdMaxCode[kind] {
+			return 0, corruptf("zstd: sequence code %d is out of range", src[0])
+		}
+		d.tables[kind] = rleFSETable(src[0])
+		return 1, nil
+	case zstdModeFSE:
+		counts, accuracyLog, n, err := readFSEDistribution(src, zstdMaxCode[kind], zstdMaxAccuracyLog[kind])
+		if err != nil {
+			return 0, err
+		}
+		if d.tables[kind], err = buildFSETable(counts, accuracyLog); err != nil {
+			return 0, err
+		}
+		return n, nil
+	default:
+		if d.tables[kind] == nil {
+			return 0, corruptf("zstd: sequences repeat a table that was never sent")
+		}
+		return 0, nil
+	}
+}
+
+// resolveOffset turns a sequence's offset value into a match offset,
+// resolving and updating the repeat offsets. Values above 3 are new
+// offsets; the rest pick a repeat offset, shifted by one when the
+// sequence has no literals.
+func (d *zstdFrameDecoder) resolveOffset(offsetValue, literalLength int) int {
+	rep := &d.repeats
+	if offsetValue > 3 {
+		offset := offsetValue - 3
+		rep[0], rep[1], rep[2] = offset, rep[0], rep[1]
+		return offset
+	}
+	if literalLength == 0 {
+		offsetValue++
+	}
+	switch offsetValue {
+	case 1:
+		return rep[0]
+	case 2:
+		rep[0], rep[1] = rep[1], rep[0]
+	case 3:
+		rep[0], rep[1], rep[2] = rep[2], rep[0], rep[1]
+	default:
+		rep[0], rep[1], rep[2] = rep[0]-1, rep[0], rep[1]
+	}
+	return rep[0]
+}
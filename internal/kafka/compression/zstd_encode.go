@@ -0,0 +1,174 @@
+package compression
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sort"
+)
+
+// zstdMaxOffset keeps offset codes within the 28 the predefined offsets
+// table covers
+const zstdMaxOffset = 1<<28 - 3
+
+// zstdPredefinedEncoders are the encoding tables of the predefined
+// distributions, the only ones the encoder uses
+var zstdPredefinedEncoders = func() [3]*fseEncoder {
+	var encoders [3]*fseEncoder
+	for kind, counts := range zstdPredefinedCounts {
+		encoders[kind] = newFSEEncoder(counts, zstdPredefinedAccuracyLog[kind])
+	}
+	return encoders
+}()
+
+// compressZstd compresses src as a single zstd frame. Blocks hold raw
+// literals and sequences coded with the predefined tables, and fall back
+// to raw blocks when that does not make them smaller.
+func compressZstd(src []byte) []byte {
+	dst := make([]byte, 0, len(src)+len(src)/zstdMaxBlockSize*3+32)
+	dst = binary.LittleEndian.AppendUint32(dst, zstdMagic)
+
+	// A single segment frame declares its content size in place of a
+	// window, and decoders may hold the whole frame
+	descriptor := byte(zstdSingleSegment | zstdContentChecksum)
+	switch n := uint64(len(src)); {
+	case n < 256:
+		dst = append(dst, descriptor, byte(n))
+	case n < 1<<16+256:
+		dst = append(dst, descriptor|1<<6)
+		dst = binary.LittleEndian.AppendUint16(dst, uint16(n-256))
+	case n < 1<<32:
+		dst = append(dst, descriptor|2<<6)
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(n))
+	default:
+		dst = append(dst, descriptor|3<<6)
+		dst = binary.LittleEndian.AppendUint64(dst, n)
+	}
+
+	if len(src) == 0 {
+		dst = appendZstdBlockHeader(dst, true, zstdBlockRaw, 0)
+	}
+	finder := newMatchFinder(zstdMaxOffset, 0, 0)
+	var block []byte
+	for start := 0; start < len(src); start += zstdMaxBlockSize {
+		end := min(start+zstdMaxBlockSize, len(src))
+		last := end == len(src)
+		block = encodeZstdBlock(block[:0], finder, src, start, end)
+		if len(block) > 0 && len(block) < end-start {
+			dst = appendZstdBlockHeader(dst, last, zstdBlockCompressed, len(block))
+			dst = append(dst, block...)
+		} else {
+			dst = appendZstdBlockHeader(dst, last, zstdBlockRaw, end-start)
+			dst = append(dst, src[start:end]...)
+		}
+	}
+	return binary.LittleEndian.AppendUint32(dst, uint32(xxh64(src)))
+}
+
+// appendZstdBlockHeader appends a block header
+func appendZstdBlockHeader(dst []byte, last bool, blockType, size int) []byte {
+	header := size<<3 | blockType<<1
+	if last {
+		header |= 1
+	}
+	return append(dst, byte(header), byte(header>>8), byte(header>>16))
+}
+
+// encodeZstdBlock appends the compressed block encoding src[start:end] to
+// dst. Matches may reach into earlier blocks of the frame. It appends
+// nothing if the block has no matches, which a raw block encodes better.
+func encodeZstdBlock(dst []byte, finder *matchFinder, src []byte, start, end int) []byte {
+	seqs, tail := finder.parse(src, 0, start, end, nil)
+	if len(seqs) == 0 {
+		return dst
+	}
+
+	// Literals section, stored raw
+	literals := 0
+	for _, s := range seqs {
+		literals += s.litLen
+	}
+	literals += tail
+	switch {
+	case literals < 1<<5:
+		dst = append(dst, byte(literals<<3|zstdLiteralsRaw))
+	case literals < 1<<12:
+		dst = append(dst, byte(literals<<4|1<<2|zstdLiteralsRaw), byte(literals>>4))
+	default:
+		dst = append(dst, byte(literals<<4|3<<2|zstdLiteralsRaw), byte(literals>>4), byte(literals>>12))
+	}
+	p := start
+	for _, s := range seqs {
+		dst = append(dst, src[p:p+s.litLen]...)
+		p += s.litLen + s.matchLen
+	}
+	dst = append(dst, src[end-tail:end]...)
+
+	// Sequences section, with every table predefined
+	switch n := len(seqs); {
+	case n < 128:
+		dst = append(dst, byte(n))
+	case n < 0x7f00:
+		dst = append(dst, byte(n>>8+128), byte(n))
+	default:
+		dst = append(dst, 255)
+		dst = binary.LittleEndian.AppendUint16(dst, uint16(n-0x7f00))
+	}
+	dst = append(dst, zstdModePredefined<<6|zstdModePredefined<<4|zstdModePredefined<<2)
+	return append(dst, encodeZstdSequences(seqs)...)
+}
+
+// zstdCode is a sequence field split into its code and extra bits
+type zstdCode struct {
+	code  uint8
+	extra uint64
+	bits  uint
+}
+
+// zstdLengthCode finds the code for a literal or match length
+func zstdLengthCode(n int, base, extraBits []int) zstdCode {
+	code := sort.Search(len(base), func(i int) bool { return base[i] > n }) - 1
+	return zstdCode{code: uint8(code), extra: uint64(n - base[code]), bits: uint(extraBits[code])}
+}
+
+// zstdOffsetCode finds the code for a new offset. The encoder never uses
+// repeat offsets, so the offset value is always the offset plus 3.
+func zstdOffsetCode(offset int) zstdCode {
+	value := uint64(offset + 3)
+	code := bits.Len64(value) - 1
+	return zstdCode{code: uint8(code), extra: value - 1<<code, bits: uint(code)}
+}
+
+// encodeZstdSequences encodes sequences into their backward bitstream.
+// They are written last to first so a decoder reads them in order.
+func encodeZstdSequences(seqs []lzSequence) []byte {
+	codes := make([][3]zstdCode, len(seqs))
+	for i, s := range seqs {
+		codes[i][zstdLiteralLengths] = zstdLengthCode(s.litLen, zstdLiteralLengthBase, zstdLiteralLengthBits)
+		codes[i][zstdOffsets] = zstdOffsetCode(s.offset)
+		codes[i][zstdMatchLengths] = zstdLengthCode(s.matchLen, zstdMatchLengthBase, zstdMatchLengthBits)
+	}
+	addExtraBits := func(w *bitWriter, c *[3]zstdCode) {
+		for _, kind := range []int{zstdLiteralLengths, zstdMatchLengths, zstdOffsets} {
+			w.add(c[kind].extra, c[kind].bits)
+		}
+	}
+
+	w := &bitWriter{buf: make([]byte, 0, 4*len(seqs)+8)}
+	var ll, of, ml fseEncodeState
+	last := &codes[len(codes)-1]
+	ml.init(zstdPredefinedEncoders[zstdMatchLengths], last[zstdMatchLengths].code)
+	of.init(zstdPredefinedEncoders[zstdOffsets], last[zstdOffsets].code)
+	ll.init(zstdPredefinedEncoders[zstdLiteralLengths], last[zstdLiteralLengths].code)
+	addExtraBits(w, last)
+	for i := len(codes) - 2; i >= 0; i-- {
+		c := &codes[i]
+		of.encode(w, c[zstdOffsets].code)
+		ml.encode(w, c[zstdMatchLengths].code)
+		ll.encode(w, c[zstdLiteralLengths].code)
+		addExtraBits(w, c)
+	}
+	ml.flush(w)
+	of.flush(w)
+	ll.flush(w)
+	return w.close()
+}
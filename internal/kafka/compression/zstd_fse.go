@@ -0,0 +1,345 @@
+package compression
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// backwardBits reads a zstd backward bitstream. The stream is written from
+// its first byte on and read from its last, whose highest set bit marks
+// where the data ends. Reading past the start yields zeros and leaves pos
+// negative, which decoders check for.
+type backwardBits struct {
+	data []byte
+	// pos is the number of bits not yet read
+	pos int
+}
+
+// newBackwardBits starts reading the backward bitstream in data
+func newBackwardBits(data []byte) (backwardBits, error) {
+	if len(data) == 0 {
+		return backwardBits{}, corruptf("zstd: empty bitstream")
+	}
+	last := data[len(data)-1]
+	if last == 0 {
+		return backwardBits{}, corruptf("zstd: bitstream lacks its end marker")
+	}
+	return backwardBits{data: data, pos: 8*(len(data)-1) + bits.Len8(last) - 1}, nil
+}
+
+// bitsAt returns the n (at most 56) bits of the stream starting at bit
+// start, taking bits before the start of the stream as zeros
+func (r *backwardBits) bitsAt(start, n int) uint64 {
+	if n == 0 {
+		return 0
+	}
+	if start < 0 {
+		if -start >= n {
+			return 0
+		}
+		return r.bitsAt(0, n+start) << -start
+	}
+	i := start >> 3
+	var v uint64
+	if i+8 <= len(r.data) {
+		v = binary.LittleEndian.Uint64(r.data[i:])
+	} else {
+		for j := len(r.data) - 1; j >= i; j-- {
+			v = v<<8 | uint64(r.data[j])
+		}
+	}
+	return v >> (start & 7) & (1<<n - 1)
+}
+
+// peek returns the next n bits without consuming them
+func (r *backwardBits) peek(n int) uint64 {
+	return r.bitsAt(r.pos-n, n)
+}
+
+// read consumes and returns the next n bits
+func (r *backwardBits) read(n int) uint64 {
+	v := r.peek(n)
+	r.pos -= n
+	return v
+}
+
+// forwardBits reads the little-endian bitstream of an FSE table description
+type forwardBits struct {
+	data []byte
+	pos  int
+}
+
+// read consumes n bits, reporting false if the data ends first
+func (r *forwardBits) read(n int) (int, bool) {
+	if r.pos+n > 8*len(r.data) {
+		return 0, false
+	}
+	v := 0
+	for i := 0; i < n; i++ {
+		bit := r.pos + i
+		v |= int(r.data[bit>>3]>>(bit&7)&1) << i
+	}
+	r.pos += n
+	return v, true
+}
+
+// bitWriter writes a zstd backward bitstream, least significant bits first
+type bitWriter struct {
+	buf []byte
+	acc uint64
+	n   uint
+}
+
+// add appends the low nbits (at most 32) bits of v
+func (w *bitWriter) add(v uint64, nbits uint) {
+	w.acc |= (v & (1<<nbits - 1)) << w.n
+	w.n += nbits
+	for w.n >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.n -= 8
+	}
+}
+
+// close appends the end marker bit and flushes the last partial byte
+func (w *bitWriter) close() []byte {
+	w.add(1, 1)
+	if w.n > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.n = 0, 0
+	}
+	return w.buf
+}
+
+// fseEntry is a state of an FSE decoding table: the symbol it decodes and
+// how to reach the next state
+type fseEntry struct {
+	symbol uint8
+	nbBits uint8
+	base   uint16
+}
+
+// fseTable is an FSE decoding table
+type fseTable struct {
+	accuracyLog int
+	entries     []fseEntry
+}
+
+// readFSEDistribution reads the normalized symbol counts that describe an
+// FSE table, returning them with the table's accuracy log and the number of
+// bytes read. A count of -1 marks a symbol less probable than one state.
+func readFSEDistribution(src []byte, maxSymbol, maxLog int) ([]int16, int, int, error) {
+	r := forwardBits{data: src}
+	v, ok := r.read(4)
+	if !ok {
+		return nil, 0, 0, corruptf("zstd: truncated FSE table")
+	}
+	accuracyLog := v + 5
+	if accuracyLog > maxLog {
+		return nil, 0, 0, corruptf("zstd: FSE accuracy log %d exceeds %d", accuracyLog, maxLog)
+	}
+
+	remaining := 1<<accuracyLog + 1
+	threshold := 1 << accuracyLog
+	nbBits := accuracyLog + 1
+	var counts []int16
+	for remaining > 1 {
+		if len(counts) > maxSymbol {
+			return nil, 0, 0, corruptf("zstd: FSE table has too many symbols")
+		}
+		// Small values take one bit less than large ones
+		limit := 2*threshold - 1 - remaining
+		value, ok := r.read(nbBits - 1)
+		if ok && value >= limit {
+			var high int
+			high, ok = r.read(1)
+			value |= high << (nbBits - 1)
+			if value >= threshold {
+				value -= limit
+			}
+		}
+		if !ok {
+			return nil, 0, 0, corruptf("zstd: truncated FSE table")
+		}
+
+		count := value - 1
+		if count < 0 {
+			remaining--
+		} else {
+			remaining -= count
+		}
+		if remaining < 1 {
+			return nil, 0, 0, corruptf("zstd: FSE counts overflow the table")
+		}
+		counts = append(counts, int16(count))
+
+		// A zero count is followed by 2-bit repeat flags for the zero
+		// counts that come after it
+		if count == 0 {
+			for {
+				repeat, ok := r.read(2)
+				if !ok {
+					return nil, 0, 0, corruptf("zstd: truncated FSE table")
+				}
+				for i := 0; i < repeat; i++ {
+					counts = append(counts, 0)
+				}
+				if repeat != 3 {
+					break
+				}
+			}
+		}
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+	}
+	if len(counts) > maxSymbol+1 {
+		return nil, 0, 0, corruptf("zstd: FSE table has too many symbols")
+	}
+	return counts, accuracyLog, (r.pos + 7) / 8, nil
+}
+
+// spreadFSESymbols lays the symbols of a distribution out over the states
+// of its table, as encoder and decoder both must. Symbols with a count of
+// -1 take the last states.
+func spreadFSESymbols(counts []int16, accuracyLog int) ([]uint8, error) {
+	size := 1 << accuracyLog
+	symbols := make([]uint8, size)
+	high := size - 1
+	for s, c := range counts {
+		if c == -1 {
+			symbols[high] = uint8(s)
+			high--
+		}
+	}
+
+	step := size>>1 + size>>3 + 3
+	pos := 0
+	for s, c := range counts {
+		for i := 0; i < int(c); i++ {
+			symbols[pos] = uint8(s)
+			for {
+				pos = (pos + step) & (size - 1)
+				if pos <= high {
+					break
+				}
+			}
+		}
+	}
+	if pos != 0 {
+		return nil, corruptf("zstd: FSE counts do not fill the table")
+	}
+	return symbols, nil
+}
+
+// buildFSETable builds the decoding table for a distribution
+func buildFSETable(counts []int16, accuracyLog int) (*fseTable, error) {
+	symbols, err := spreadFSESymbols(counts, accuracyLog)
+	if err != nil {
+		return nil, err
+	}
+
+	size := 1 << accuracyLog
+	next := make([]int, len(counts))
+	for s, c := range counts {
+		next[s] = max(int(c), 1)
+	}
+	t := &fseTable{accuracyLog: accuracyLog, entries: make([]fseEntry, size)}
+	for i, s := range symbols {
+		x := next[s]
+		next[s]++
+		nb := accuracyLog - (bits.Len(uint(x)) - 1)
+		t.entries[i] = fseEntry{symbol: s, nbBits: uint8(nb), base: uint16(x<<nb - size)}
+	}
+	return t, nil
+}
+
+// rleFSETable returns a table whose only state decodes symbol
+func rleFSETable(symbol uint8) *fseTable {
+	return &fseTable{entries: []fseEntry{{symbol: symbol}}}
+}
+
+// fseSymbolTransform is how an FSE encoder moves between states for a
+// symbol, in the form of the reference implementation
+type fseSymbolTransform struct {
+	deltaNbBits    int
+	deltaFindState int
+}
+
+// fseEncoder is an FSE encoding table
+type fseEncoder struct {
+	accuracyLog int
+	states      []uint16
+	transforms  []fseSymbolTransform
+}
+
+// newFSEEncoder builds the encoding table for a distribution
+func newFSEEncoder(counts []int16, accuracyLog int) *fseEncoder {
+	symbols, err := spreadFSESymbols(counts, accuracyLog)
+	if err != nil {
+		panic(err)
+	}
+
+	size := 1 << accuracyLog
+	cumul := make([]int, len(counts)+1)
+	for s, c := range counts {
+		cumul[s+1] = cumul[s] + max(int(c), 1)
+	}
+	e := &fseEncoder{
+		accuracyLog: accuracyLog,
+		states:      make([]uint16, size),
+		transforms:  make([]fseSymbolTransform, len(counts)),
+	}
+	for i, s := range symbols {
+		e.states[cumul[s]] = uint16(size + i)
+		cumul[s]++
+	}
+
+	total := 0
+	for s, c := range counts {
+		switch c {
+		case 0:
+			e.transforms[s].deltaNbBits = (accuracyLog+1)<<16 - size
+		case -1, 1:
+			e.transforms[s] = fseSymbolTransform{deltaNbBits: accuracyLog<<16 - size, deltaFindState: total - 1}
+			total++
+		default:
+			maxBitsOut := accuracyLog - (bits.Len(uint(c-1)) - 1)
+			minStatePlus := int(c) << maxBitsOut
+			e.transforms[s] = fseSymbolTransform{deltaNbBits: maxBitsOut<<16 - minStatePlus, deltaFindState: total - int(c)}
+			total += int(c)
+		}
+	}
+	return e
+}
+
+// fseEncodeState is the state of an FSE encoder as it works through a
+// stream of symbols, last to first
+type fseEncodeState struct {
+	enc   *fseEncoder
+	value int
+}
+
+// init starts encoding with the last symbol of the stream
+func (st *fseEncodeState) init(enc *fseEncoder, symbol uint8) {
+	tt := enc.transforms[symbol]
+	nbBitsOut := (tt.deltaNbBits + 1<<15) >> 16
+	value := nbBitsOut<<16 - tt.deltaNbBits
+	st.enc = enc
+	st.value = int(enc.states[value>>nbBitsOut+tt.deltaFindState])
+}
+
+// encode writes the bits that lead a decoder from symbol's state to the
+// current one
+func (st *fseEncodeState) encode(w *bitWriter, symbol uint8) {
+	tt := st.enc.transforms[symbol]
+	nbBitsOut := (st.value + tt.deltaNbBits) >> 16
+	w.add(uint64(st.value), uint(nbBitsOut))
+	st.value = int(st.enc.states[st.value>>nbBitsOut+tt.deltaFindState])
+}
+
+// flush writes the final state, which the decoder starts from
+func (st *fseEncodeState) flush(w *bitWriter) {
+	w.add(uint64(st.value), uint(st.enc.accuracyLog))
+}
@@ -0,0 +1,168 @@
+package compression
+
+import "math/bits"
+
+// Huffman limits for zstd literals
+const (
+	huffmanMaxBits    = 11
+	huffmanMaxSymbols = 256
+	// huffmanWeightsMaxLog is the largest accuracy log of the FSE table
+	// compressing Huffman weights
+	huffmanWeightsMaxLog = 6
+)
+
+// huffmanEntry is an entry of a Huffman decoding table
+type huffmanEntry struct {
+	symbol uint8
+	nbBits uint8
+}
+
+// huffmanTable decodes a literal from the next maxBits bits of a stream.
+// Shorter codes fill several consecutive entries.
+type huffmanTable struct {
+	maxBits int
+	entries []huffmanEntry
+}
+
+// readHuffmanTable reads a Huffman tree description, returning the table
+// and the number of bytes read
+func readHuffmanTable(src []byte) (*huffmanTable, int, error) {
+	if len(src) == 0 {
+		return nil, 0, corruptf("zstd: missing Huffman tree description")
+	}
+
+	var weights []uint8
+	var n int
+	if header := int(src[0]); header < 128 {
+		// FSE-compressed weights, header bytes long
+		n = 1 + header
+		if n > len(src) {
+			return nil, 0, corruptf("zstd: truncated Huffman weights")
+		}
+		var err error
+		if weights, err = decodeHuffmanWeights(src[1:n]); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		// Weights stored directly, 4 bits each
+		count := header - 127
+		n = 1 + (count+1)/2
+		if n > len(src) {
+			return nil, 0, corruptf("zstd: truncated Huffman weights")
+		}
+		weights = make([]uint8, count)
+		for i := range weights {
+			b := src[1+i/2]
+			if i%2 == 0 {
+				weights[i] = b >> 4
+			} else {
+				weights[i] = b & 0x0f
+			}
+		}
+	}
+	if len(weights) >= huffmanMaxSymbols {
+		return nil, 0, corruptf("zstd: too many Huffman weights")
+	}
+
+	// The last symbol's weight is implied: it completes the total to a
+	// power of two
+	total := 0
+	for _, w := range weights {
+		if w > huffmanMaxBits {
+			return nil, 0, corruptf("zstd: Huffman weight %d is too large", w)
+		}
+		if w > 0 {
+			total += 1 << (w - 1)
+		}
+	}
+	if total == 0 {
+		return nil, 0, corruptf("zstd: Huffman weights are all zero")
+	}
+	maxBits := bits.Len(uint(total))
+	rest := 1<<maxBits - total
+	if maxBits > huffmanMaxBits || rest&(rest-1) != 0 {
+		return nil, 0, corruptf("zstd: invalid Huffman weights")
+	}
+	weights = append(weights, uint8(bits.Len(uint(rest))))
+
+	// Symbols take table entries by increasing weight, then symbol value
+	var rankStart [huffmanMaxBits + 2]int
+	for _, w := range weights {
+		if w > 0 {
+			rankStart[w] += 1 << (w - 1)
+		}
+	}
+	pos := 0
+	for w := 1; w <= maxBits; w++ {
+		count := rankStart[w]
+		rankStart[w] = pos
+		pos += count
+	}
+	t := &huffmanTable{maxBits: maxBits, entries: make([]huffmanEntry, 1<<maxBits)}
+	for s, w := range weights {
+		if w == 0 {
+			continue
+		}
+		entry := huffmanEntry{symbol: uint8(s), nbBits: uint8(maxBits + 1 - int(w))}
+		length := 1 << (w - 1)
+		for i := rankStart[w]; i < rankStart[w]+length; i++ {
+			t.entries[i] = entry
+		}
+		rankStart[w] += length
+	}
+	return t, n, nil
+}
+
+// decodeHuffmanWeights decodes FSE-compressed Huffman weights, which two
+// interleaved states share one bitstream
+func decodeHuffmanWeights(src []byte) ([]uint8, error) {
+	counts, accuracyLog, n, err := readFSEDistribution(src, huffmanMaxSymbols-1, huffmanWeightsMaxLog)
+	if err != nil {
+		return nil, err
+	}
+	table, err := buildFSETable(counts, accuracyLog)
+	if err != nil {
+		return nil, err
+	}
+	if n > len(src) {
+		return nil, corruptf("zstd: truncated Huffman weights")
+	}
+	r, err := newBackwardBits(src[n:])
+	if err != nil {
+		return nil, err
+	}
+
+	// The stream ends when a state update reads past its start; the
+	// other state then decodes the last weight
+	states := [2]int{int(r.read(accuracyLog)), int(r.read(accuracyLog))}
+	var weights []uint8
+	for i := 0; ; i ^= 1 {
+		if len(weights) >= huffmanMaxSymbols {
+			return nil, corruptf("zstd: too many Huffman weights")
+		}
+		e := table.entries[states[i]]
+		weights = append(weights, e.symbol)
+		states[i] = int(e.base) + int(r.read(int(e.nbBits)))
+		if r.pos < 0 {
+			weights = append(weights, table.entries[states[i^1]].symbol)
+			return weights, nil
+		}
+	}
+}
+
+// decode appends the n literals of one Huffman-coded stream to dst
+func (t *huffmanTable) decode(dst, src []byte, n int) ([]byte, error) {
+	r, err := newBackwardBits(src)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		e := t.entries[r.peek(t.maxBits)]
+		dst = append(dst, e.symbol)
+		r.pos -= int(e.nbBits)
+	}
+	if r.pos != 0 {
+		return nil, corruptf("zstd: Huffman stream does not end with its literals")
+	}
+	return dst, nil
+}
@@ -0,0 +1,202 @@
+package kafka
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// Topic config names
+const (
+	CleanupPolicyConfig               = "cleanup.policy"
+	CompressionTypeConfig             = "compression.type"
+	DeleteRetentionMsConfig           = "delete.retention.ms"
+	MessageDownConversionEnableConfig = "message.downconversion.enable"
+	MessageTimestampTypeConfig        = "message.timestamp.type"
+	MaxMessageBytesConfig             = "max.message.bytes"
+	RetentionBytesConfig              = "retention.bytes"
+	RetentionMsConfig                 = "retention.ms"
+	SegmentBytesConfig                = "segment.bytes"
+	SegmentMsConfig                   = "segment.ms"
+)
+
+// Config sources reported to clients
+const (
+	configSourceDynamicTopic int8 = 1
+	configSourceDefault      int8 = 5
+)
+
+// configType is the type a config value must parse as
+type configType int
+
+const (
+	configString configType = iota
+	configInt
+	configLong
+	configDouble
+	configBool
+	configList
+)
+
+// topicConfigDef describes a topic config: its broker default and the
+// values it accepts
+type topicConfigDef struct {
+	defaultValue string
+	typ          configType
+	// validValues restricts string values, or each item of a list
+	validValues []string
+}
+
+// topicConfigDefs are the topic configs the broker accepts, with the
+// broker defaults used when a topic does not override them
+var topicConfigDefs = map[string]topicConfigDef{
+	CleanupPolicyConfig:                       {"delete", configList, []string{"compact", "delete"}},
+	CompressionTypeConfig:                     {"producer", configString, []string{"uncompressed", "zstd", "lz4", "snappy", "gzip", "producer"}},
+	DeleteRetentionMsConfig:                   {"86400000", configLong, nil},
+	"file.delete.delay.ms":                    {"60000", configLong, nil},
+	"flush.messages":                          {"9223372036854775807", configLong, nil},
+	"flush.ms":                                {"9223372036854775807", configLong, nil},
+	"index.interval.bytes":                    {"4096", configInt, nil},
+	"max.compaction.lag.ms":                   {"9223372036854775807", configLong, nil},
+	MaxMessageBytesConfig:                     {"1048588", configInt, nil},
+	"message.timestamp.after.max.ms":          {"9223372036854775807", configLong, nil},
+	"message.timestamp.before.max.ms":         {"9223372036854775807", configLong, nil},
+	MessageTimestampTypeConfig:                {"CreateTime", configString, []string{"CreateTime", "LogAppendTime"}},
+	"min.cleanable.dirty.ratio":               {"0.5", configDouble, nil},
+	"min.compaction.lag.ms":                   {"0", configLong, nil},
+	"min.insync.replicas":                     {"1", configInt, nil},
+	"preallocate":                             {"false", configBool, nil},
+	RetentionBytesConfig:                      {"-1", configLong, nil},
+	RetentionMsConfig:                         {"604800000", configLong, nil},
+	SegmentBytesConfig:                        {"1073741824", configInt, nil},
+	"segment.index.bytes":                     {"10485760", configInt, nil},
+	"segment.jitter.ms":                       {"0", configLong, nil},
+	SegmentMsConfig:                           {"604800000", configLong, nil},
+	"unclean.leader.election.enable":          {"false", configBool, nil},
+	MessageDownConversionEnableConfig:         {"true", configBool, nil},
+	"leader.replication.throttled.replicas":   {"", configList, nil},
+	"follower.replication.throttled.replicas": {"", configList, nil},
+}
+
+// topicConfig returns the value of a topic config, falling back to the
+// broker default
+func (h *RequestHandler) topicConfig(topic, name string) string {
+	if value, ok := h.metadata.Configs(metadata.ResourceTopic, topic)[name]; ok {
+		return value
+	}
+	return topicConfigDefs[name].defaultValue
+}
+
+// topicConfigInt returns the value of an integer topic config, falling
+// back to the broker default if the override is not a valid integer
+func (h *RequestHandler) topicConfigInt(topic, name string) int64 {
+	if v, err := strconv.ParseInt(h.topicConfig(topic, name), 10, 64); err == nil {
+		return v
+	}
+	v, _ := strconv.ParseInt(topicConfigDefs[name].defaultValue, 10, 64)
+	return v
+}
+
+// topicConfigBool returns the value of a boolean topic config
+func (h *RequestHandler) topicConfigBool(topic, name string) bool {
+	return strings.EqualFold(h.topicConfig(topic, name), "true")
+}
+
+// CompactionPolicy returns whether a topic's logs are compacted, from its
+// cleanup.policy and delete.retention.ms configs, as the compactor applies
+// it
+func (h *RequestHandler) CompactionPolicy(topic string) storage.CompactionPolicy {
+	policy := storage.CompactionPolicy{
+		DeleteRetentionMs: h.topicConfigInt(topic, DeleteRetentionMsConfig),
+	}
+	for _, item := range strings.Split(h.topicConfig(topic, CleanupPolicyConfig), ",") {
+		if strings.TrimSpace(item) == "compact" {
+			policy.Compact = true
+		}
+	}
+	return policy
+}
+
+// RetentionPolicy returns the retention of a topic's logs from its
+// cleanup.policy, retention.ms and retention.bytes configs, as the log
+// cleaner enforces it
+func (h *RequestHandler) RetentionPolicy(topic string) storage.RetentionPolicy {
+	policy := storage.RetentionPolicy{
+		RetentionMs:    h.topicConfigInt(topic, RetentionMsConfig),
+		RetentionBytes: h.topicConfigInt(topic, RetentionBytesConfig),
+	}
+	for _, item := range strings.Split(h.topicConfig(topic, CleanupPolicyConfig), ",") {
+		if strings.TrimSpace(item) == "delete" {
+			policy.Delete = true
+		}
+	}
+	return policy
+}
+
+// SegmentPolicy returns when a topic's logs roll their segments, from its
+// segment.bytes and segment.ms overrides. Without one, the broker's
+// log.segment.bytes and log.roll.ms apply.
+func (h *RequestHandler) SegmentPolicy(topic string) storage.SegmentPolicy {
+	configs := h.metadata.Configs(metadata.ResourceTopic, topic)
+	var policy storage.SegmentPolicy
+	if v, err := strconv.ParseInt(configs[SegmentBytesConfig], 10, 64); err == nil {
+		policy.SegmentBytes = v
+	}
+	if v, err := strconv.ParseInt(configs[SegmentMsConfig], 10, 64); err == nil {
+		policy.SegmentMs = v
+	}
+	return policy
+}
+
+// validateTopicConfig checks a topic config override, returning an
+// INVALID_CONFIG error for unknown names and malformed values
+func validateTopicConfig(name string, value *string) *topicError {
+	def, ok := topicConfigDefs[name]
+	if !ok {
+		return newTopicError(protocol.ErrorInvalidConfig, "Unknown topic config name: %s", name)
+	}
+	if value == nil {
+		return newTopicError(protocol.ErrorInvalidConfig, "Null value not supported for topic configs: %s", name)
+	}
+
+	v := strings.TrimSpace(*value)
+	// reason explains a rejected value the way Kafka's config validation does
+	var reason string
+	switch def.typ {
+	case configInt:
+		if _, err := strconv.ParseInt(v, 10, 32); err != nil {
+			reason = "Not a number of type INT"
+		}
+	case configLong:
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			reason = "Not a number of type LONG"
+		}
+	case configDouble:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			reason = "Not a number of type DOUBLE"
+		}
+	case configBool:
+		if !strings.EqualFold(v, "true") && !strings.EqualFold(v, "false") {
+			reason = "Expected value to be either true or false"
+		}
+	case configString:
+		if def.validValues != nil && !slices.Contains(def.validValues, v) {
+			reason = "String must be one of: " + strings.Join(def.validValues, ", ")
+		}
+	case configList:
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); def.validValues != nil && !slices.Contains(def.validValues, item) {
+				reason = "String must be one of: " + strings.Join(def.validValues, ", ")
+				break
+			}
+		}
+	}
+	if reason != "" {
+		return newTopicError(protocol.ErrorInvalidConfig, "Invalid value %s for configuration %s: %s", *value, name, reason)
+	}
+	return nil
+}
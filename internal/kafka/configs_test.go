@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// setTopicConfigs overrides configs of testTopic on h
+func setTopicConfigs(t *testing.T, h *RequestHandler, configs map[string]string) {
+	t.Helper()
+	for name, value := range configs {
+		err := h.metadata.Publish(&protocol.ConfigRecord{
+			ResourceType: metadata.ResourceTopic,
+			ResourceName: testTopic,
+			Name:         name,
+			Value:        &value,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCompactionPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs map[string]string
+		want    storage.CompactionPolicy
+	}{
+		{
+			name: "defaults",
+			want: storage.CompactionPolicy{DeleteRetentionMs: 86400000},
+		},
+		{
+			name:    "compact",
+			configs: map[string]string{CleanupPolicyConfig: "compact", DeleteRetentionMsConfig: "1000"},
+			want:    storage.CompactionPolicy{Compact: true, DeleteRetentionMs: 1000},
+		},
+		{
+			name:    "compact and delete",
+			configs: map[string]string{CleanupPolicyConfig: "compact, delete"},
+			want:    storage.CompactionPolicy{Compact: true, DeleteRetentionMs: 86400000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			setTopicConfigs(t, h, tt.configs)
+			if got := h.CompactionPolicy(testTopic); got != tt.want {
+				t.Errorf("CompactionPolicy = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs map[string]string
+		want    storage.RetentionPolicy
+	}{
+		{
+			name: "defaults",
+			want: storage.RetentionPolicy{Delete: true, RetentionMs: 604800000, RetentionBytes: -1},
+		},
+		{
+			name:    "compact",
+			configs: map[string]string{CleanupPolicyConfig: "compact"},
+			want:    storage.RetentionPolicy{RetentionMs: 604800000, RetentionBytes: -1},
+		},
+		{
+			name:    "compact and delete",
+			configs: map[string]string{CleanupPolicyConfig: "compact, delete", RetentionMsConfig: "5000", RetentionBytesConfig: "1024"},
+			want:    storage.RetentionPolicy{Delete: true, RetentionMs: 5000, RetentionBytes: 1024},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			setTopicConfigs(t, h, tt.configs)
+			if got := h.RetentionPolicy(testTopic); got != tt.want {
+				t.Errorf("RetentionPolicy = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
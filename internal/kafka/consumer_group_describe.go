@@ -0,0 +1,48 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleConsumerGroupDescribeRequest handles CONSUMER_GROUP_DESCRIBE
+// requests
+func (h *RequestHandler) handleConsumerGroupDescribeRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.ConsumerGroupDescribeRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode ConsumerGroupDescribe request: %w", err)
+	}
+
+	resp := &protocol.ConsumerGroupDescribeResponse{}
+	resp.Default()
+	resp.Groups = h.groups.DescribeConsumerGroups(body.GroupIds)
+	if body.IncludeAuthorizedOperations {
+		for i := range resp.Groups {
+			if resp.Groups[i].ErrorCode == protocol.ErrorNone {
+				resp.Groups[i].AuthorizedOperations = groupAuthorizedOperations
+			}
+		}
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// consumerGroupDescribeErrorResponse builds a ConsumerGroupDescribe
+// response that fails every requested group with errorCode
+func (h *RequestHandler) consumerGroupDescribeErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.ConsumerGroupDescribeRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.ConsumerGroupDescribeResponse{}
+	resp.Default()
+	for _, id := range body.GroupIds {
+		group := protocol.ConsumerGroupDescribeResponseDescribedGroup{}
+		group.Default()
+		group.GroupId = id
+		group.ErrorCode = errorCode
+		resp.Groups = append(resp.Groups, group)
+	}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,30 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleConsumerGroupHeartbeatRequest handles CONSUMER_GROUP_HEARTBEAT
+// requests, through which members of consumer groups join, leave and get
+// their assignment from the coordinator
+func (h *RequestHandler) handleConsumerGroupHeartbeatRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.ConsumerGroupHeartbeatRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode ConsumerGroupHeartbeat request: %w", err)
+	}
+
+	resp := h.groups.ConsumerGroupHeartbeat(groupRequestContext(conn, req), body)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// consumerGroupHeartbeatErrorResponse builds a failed ConsumerGroupHeartbeat
+// response
+func (h *RequestHandler) consumerGroupHeartbeatErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.ConsumerGroupHeartbeatResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,80 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleCreatePartitionsRequest handles CREATE_PARTITIONS requests
+func (h *RequestHandler) handleCreatePartitionsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.CreatePartitionsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode CreatePartitions request: %w", err)
+	}
+
+	counts := make(map[string]int, len(body.Topics))
+	for _, topic := range body.Topics {
+		counts[topic.Name]++
+	}
+
+	resp := &protocol.CreatePartitionsResponse{}
+	resp.Default()
+	answered := make(map[string]bool, len(body.Topics))
+	for _, topic := range body.Topics {
+		if answered[topic.Name] {
+			continue
+		}
+		answered[topic.Name] = true
+
+		if counts[topic.Name] > 1 {
+			resp.Results = append(resp.Results, createPartitionsResult(topic.Name,
+				newTopicError(protocol.ErrorInvalidRequest, "Duplicate topic name.")))
+			continue
+		}
+
+		var assignments [][]int32
+		if topic.Assignments != nil {
+			assignments = make([][]int32, len(topic.Assignments))
+			for i, a := range topic.Assignments {
+				assignments[i] = a.BrokerIds
+			}
+		}
+		err := h.addPartitions(topic.Name, topic.Count, assignments, body.ValidateOnly)
+		resp.Results = append(resp.Results, createPartitionsResult(topic.Name, err))
+	}
+
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// createPartitionsResult builds a topic result, failed with err's code and
+// message unless err is nil
+func createPartitionsResult(name string, err *topicError) protocol.CreatePartitionsResponseCreatePartitionsTopicResult {
+	result := protocol.CreatePartitionsResponseCreatePartitionsTopicResult{
+		Name:      name,
+		ErrorCode: protocol.ErrorNone,
+	}
+	if err != nil {
+		result.ErrorCode = err.code
+		if err.message != "" {
+			result.ErrorMessage = &err.message
+		}
+	}
+	return result
+}
+
+// createPartitionsErrorResponse builds a CreatePartitions response that
+// fails every requested topic with errorCode
+func (h *RequestHandler) createPartitionsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.CreatePartitionsRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.CreatePartitionsResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		resp.Results = append(resp.Results, createPartitionsResult(topic.Name, &topicError{code: errorCode}))
+	}
+	return protocol.NewResponse(req, resp)
+}
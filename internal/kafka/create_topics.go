@@ -0,0 +1,130 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleCreateTopicsRequest handles CREATE_TOPICS requests
+func (h *RequestHandler) handleCreateTopicsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.CreateTopicsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode CreateTopics request: %w", err)
+	}
+
+	// A topic listed more than once is rejected rather than created once
+	counts := make(map[string]int, len(body.Topics))
+	for _, topic := range body.Topics {
+		counts[topic.Name]++
+	}
+
+	resp := &protocol.CreateTopicsResponse{}
+	resp.Default()
+	answered := make(map[string]bool, len(body.Topics))
+	for i := range body.Topics {
+		topic := &body.Topics[i]
+		if answered[topic.Name] {
+			continue
+		}
+		answered[topic.Name] = true
+
+		if counts[topic.Name] > 1 {
+			resp.Topics = append(resp.Topics, createTopicsError(topic.Name, newTopicError(protocol.ErrorInvalidRequest,
+				"Create topics request contains multiple entries for the following topics: %s", topic.Name)))
+			continue
+		}
+		resp.Topics = append(resp.Topics, h.createTopicFromRequest(topic, body.ValidateOnly))
+	}
+
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// createTopicFromRequest creates, or with validateOnly only validates, one
+// topic of a CreateTopics request
+func (h *RequestHandler) createTopicFromRequest(topic *protocol.CreateTopicsRequestCreatableTopic, validateOnly bool) protocol.CreateTopicsResponseCreatableTopicResult {
+	assignments := make(map[int32][]int32, len(topic.Assignments))
+	for _, a := range topic.Assignments {
+		assignments[a.PartitionIndex] = a.BrokerIds
+	}
+	configs := make(map[string]*string, len(topic.Configs))
+	for _, c := range topic.Configs {
+		configs[c.Name] = c.Value
+	}
+
+	planned, err := h.planTopic(topic.Name, topic.NumPartitions, topic.ReplicationFactor, assignments, configs)
+	if err != nil {
+		return createTopicsError(topic.Name, err)
+	}
+
+	result := protocol.CreateTopicsResponseCreatableTopicResult{}
+	result.Default()
+	result.Name = topic.Name
+	result.ErrorCode = protocol.ErrorNone
+	result.NumPartitions = int32(len(planned.replicas))
+	result.ReplicationFactor = int16(len(planned.replicas[0]))
+	result.Configs = createdTopicConfigs(planned.configs)
+	if validateOnly {
+		return result
+	}
+
+	created, err := h.createTopic(planned)
+	if err != nil {
+		return createTopicsError(topic.Name, err)
+	}
+	result.TopicId = created.ID
+	return result
+}
+
+// createdTopicConfigs lists every topic config with the value a new topic
+// gets from its overrides or the broker defaults
+func createdTopicConfigs(overrides map[string]string) []protocol.CreateTopicsResponseCreatableTopicConfigs {
+	names := make([]string, 0, len(topicConfigDefs))
+	for name := range topicConfigDefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	configs := make([]protocol.CreateTopicsResponseCreatableTopicConfigs, 0, len(names))
+	for _, name := range names {
+		value, source := topicConfigDefs[name].defaultValue, configSourceDefault
+		if v, ok := overrides[name]; ok {
+			value, source = v, configSourceDynamicTopic
+		}
+		configs = append(configs, protocol.CreateTopicsResponseCreatableTopicConfigs{
+			Name:         name,
+			Value:        &value,
+			ConfigSource: source,
+		})
+	}
+	return configs
+}
+
+// createTopicsError builds a failed topic result
+func createTopicsError(name string, err *topicError) protocol.CreateTopicsResponseCreatableTopicResult {
+	result := protocol.CreateTopicsResponseCreatableTopicResult{}
+	result.Default()
+	result.Name = name
+	result.ErrorCode = err.code
+	if err.message != "" {
+		result.ErrorMessage = &err.message
+	}
+	return result
+}
+
+// createTopicsErrorResponse builds a CreateTopics response that fails every
+// requested topic with errorCode
+func (h *RequestHandler) createTopicsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.CreateTopicsRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.CreateTopicsResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		resp.Topics = append(resp.Topics, createTopicsError(topic.Name, &topicError{code: errorCode}))
+	}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleDeleteGroupsRequest handles DELETE_GROUPS requests
+func (h *RequestHandler) handleDeleteGroupsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.DeleteGroupsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode DeleteGroups request: %w", err)
+	}
+
+	resp := &protocol.DeleteGroupsResponse{}
+	resp.Default()
+	resp.Results = h.groups.DeleteGroups(body.GroupsNames)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// deleteGroupsErrorResponse builds a DeleteGroups response that fails
+// every requested group with errorCode
+func (h *RequestHandler) deleteGroupsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.DeleteGroupsRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.DeleteGroupsResponse{}
+	resp.Default()
+	for _, id := range body.GroupsNames {
+		resp.Results = append(resp.Results, protocol.DeleteGroupsResponseDeletableGroupResult{GroupId: id, ErrorCode: errorCode})
+	}
+	return protocol.NewResponse(req, resp)
+}
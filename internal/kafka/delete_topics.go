@@ -0,0 +1,120 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleDeleteTopicsRequest handles DELETE_TOPICS requests
+func (h *RequestHandler) handleDeleteTopicsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.DeleteTopicsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode DeleteTopics request: %w", err)
+	}
+
+	resp := &protocol.DeleteTopicsResponse{}
+	resp.Default()
+	seenNames := make(map[string]bool)
+	seenIDs := make(map[protocol.UUID]bool)
+	for _, topic := range deleteTopicsTargets(body, req.ApiVersion) {
+		switch {
+		case topic.Name != nil && topic.TopicId != protocol.ZeroUUID:
+			resp.Responses = append(resp.Responses, deleteTopicsResult(topic.Name, topic.TopicId,
+				newTopicError(protocol.ErrorInvalidRequest, "You may not specify both topic name and topic id.")))
+		case topic.Name != nil:
+			if seenNames[*topic.Name] {
+				resp.Responses = append(resp.Responses, deleteTopicsResult(topic.Name, protocol.ZeroUUID,
+					newTopicError(protocol.ErrorInvalidRequest, "Duplicate topic name.")))
+				continue
+			}
+			seenNames[*topic.Name] = true
+			resp.Responses = append(resp.Responses, h.deleteTopicByName(*topic.Name))
+		case topic.TopicId != protocol.ZeroUUID:
+			if seenIDs[topic.TopicId] {
+				resp.Responses = append(resp.Responses, deleteTopicsResult(nil, topic.TopicId,
+					newTopicError(protocol.ErrorInvalidRequest, "Duplicate topic id.")))
+				continue
+			}
+			seenIDs[topic.TopicId] = true
+			resp.Responses = append(resp.Responses, h.deleteTopicByID(topic.TopicId))
+		default:
+			resp.Responses = append(resp.Responses, deleteTopicsResult(nil, protocol.ZeroUUID,
+				newTopicError(protocol.ErrorInvalidRequest, "Neither topic name nor id were specified.")))
+		}
+	}
+
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// deleteTopicsTargets returns the topics a request asks to delete: by name
+// or ID from v6, and by name only before
+func deleteTopicsTargets(body *protocol.DeleteTopicsRequest, version int16) []protocol.DeleteTopicsRequestDeleteTopicState {
+	if version >= 6 {
+		return body.Topics
+	}
+	topics := make([]protocol.DeleteTopicsRequestDeleteTopicState, len(body.TopicNames))
+	for i := range body.TopicNames {
+		topics[i].Name = &body.TopicNames[i]
+	}
+	return topics
+}
+
+// deleteTopicByName deletes the topic with the given name
+func (h *RequestHandler) deleteTopicByName(name string) protocol.DeleteTopicsResponseDeletableTopicResult {
+	topic := h.metadata.TopicByName(name)
+	if topic == nil {
+		return deleteTopicsResult(&name, protocol.ZeroUUID,
+			newTopicError(protocol.ErrorUnknownTopic, "This server does not host this topic-partition."))
+	}
+	if err := h.deleteTopic(topic); err != nil {
+		return deleteTopicsResult(&name, topic.ID, err)
+	}
+	return deleteTopicsResult(&name, topic.ID, nil)
+}
+
+// deleteTopicByID deletes the topic with the given ID
+func (h *RequestHandler) deleteTopicByID(id protocol.UUID) protocol.DeleteTopicsResponseDeletableTopicResult {
+	topic := h.metadata.TopicByID(id)
+	if topic == nil {
+		return deleteTopicsResult(nil, id,
+			newTopicError(protocol.ErrorUnknownTopicID, "This server does not host this topic ID."))
+	}
+	if err := h.deleteTopic(topic); err != nil {
+		return deleteTopicsResult(&topic.Name, id, err)
+	}
+	return deleteTopicsResult(&topic.Name, id, nil)
+}
+
+// deleteTopicsResult builds a topic result, failed with err's code and
+// message unless err is nil
+func deleteTopicsResult(name *string, id protocol.UUID, err *topicError) protocol.DeleteTopicsResponseDeletableTopicResult {
+	result := protocol.DeleteTopicsResponseDeletableTopicResult{
+		Name:      name,
+		TopicId:   id,
+		ErrorCode: protocol.ErrorNone,
+	}
+	if err != nil {
+		result.ErrorCode = err.code
+		if err.message != "" {
+			result.ErrorMessage = &err.message
+		}
+	}
+	return result
+}
+
+// deleteTopicsErrorResponse builds a DeleteTopics response that fails every
+// requested topic with errorCode
+func (h *RequestHandler) deleteTopicsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.DeleteTopicsRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.DeleteTopicsResponse{}
+	resp.Default()
+	for _, topic := range deleteTopicsTargets(body, req.ApiVersion) {
+		resp.Responses = append(resp.Responses, deleteTopicsResult(topic.Name, topic.TopicId, &topicError{code: errorCode}))
+	}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,58 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// groupAuthorizedOperations is the ACL operation bitfield reported for
+// every group: READ, DELETE and DESCRIBE, since the broker does not enforce
+// ACLs
+const groupAuthorizedOperations int32 = 0x00000148
+
+// handleDescribeGroupsRequest handles DESCRIBE_GROUPS requests. Before v6
+// a group that does not exist is reported as Dead with no error, rather
+// than with GROUP_ID_NOT_FOUND.
+func (h *RequestHandler) handleDescribeGroupsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.DescribeGroupsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode DescribeGroups request: %w", err)
+	}
+
+	resp := &protocol.DescribeGroupsResponse{}
+	resp.Default()
+	resp.Groups = h.groups.DescribeGroups(body.Groups)
+	for i := range resp.Groups {
+		g := &resp.Groups[i]
+		if g.ErrorCode == protocol.ErrorGroupIDNotFound && req.ApiVersion < 6 {
+			g.ErrorCode = protocol.ErrorNone
+			g.ErrorMessage = nil
+			continue
+		}
+		if g.ErrorCode == protocol.ErrorNone && body.IncludeAuthorizedOperations {
+			g.AuthorizedOperations = groupAuthorizedOperations
+		}
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// describeGroupsErrorResponse builds a DescribeGroups response that fails
+// every requested group with errorCode
+func (h *RequestHandler) describeGroupsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.DescribeGroupsRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.DescribeGroupsResponse{}
+	resp.Default()
+	for _, id := range body.Groups {
+		group := protocol.DescribeGroupsResponseDescribedGroup{}
+		group.Default()
+		group.GroupId = id
+		group.ErrorCode = errorCode
+		resp.Groups = append(resp.Groups, group)
+	}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,158 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+)
+
+// topicAuthorizedOperations is the ACL operation bitfield reported for every
+// topic: READ, WRITE, CREATE, DELETE, ALTER, DESCRIBE, DESCRIBE_CONFIGS and
+// ALTER_CONFIGS, since the broker does not enforce ACLs
+const topicAuthorizedOperations int32 = 0x00000df8
+
+// maxDescribePartitions caps ResponsePartitionLimit, like Kafka's
+// max.request.partition.size.limit default
+const maxDescribePartitions = 2000
+
+// handleDescribeTopicPartitionsRequest handles DESCRIBE_TOPIC_PARTITIONS requests
+func (h *RequestHandler) handleDescribeTopicPartitionsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.DescribeTopicPartitionsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode DescribeTopicPartitions request: %w", err)
+	}
+
+	// Topics are answered in name order; an empty request describes them all
+	var names []string
+	if len(body.Topics) == 0 {
+		for _, topic := range h.metadata.Topics() {
+			names = append(names, topic.Name)
+		}
+	} else {
+		seen := make(map[string]bool, len(body.Topics))
+		for _, topic := range body.Topics {
+			if !seen[topic.Name] {
+				seen[topic.Name] = true
+				names = append(names, topic.Name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	limit := body.ResponsePartitionLimit
+	if limit <= 0 || limit > maxDescribePartitions {
+		limit = maxDescribePartitions
+	}
+
+	resp := &protocol.DescribeTopicPartitionsResponse{}
+	resp.Default()
+
+	// Partitions are paged: the cursor says where the previous page stopped
+	// and NextCursor where the next one should start
+	for _, name := range names {
+		firstPartition := int32(0)
+		if body.Cursor != nil {
+			if name < body.Cursor.TopicName {
+				continue
+			}
+			if name == body.Cursor.TopicName {
+				firstPartition = body.Cursor.PartitionIndex
+			}
+		}
+
+		topic := h.metadata.TopicByName(name)
+		if topic == nil {
+			resp.Topics = append(resp.Topics, describeTopicPartitionsError(name, protocol.ErrorUnknownTopic))
+			continue
+		}
+
+		entry := protocol.DescribeTopicPartitionsResponseTopic{
+			ErrorCode:                 protocol.ErrorNone,
+			Name:                      &topic.Name,
+			TopicId:                   topic.ID,
+			IsInternal:                topic.IsInternal(),
+			Partitions:                []protocol.DescribeTopicPartitionsResponsePartition{},
+			TopicAuthorizedOperations: topicAuthorizedOperations,
+		}
+		for i := range topic.Partitions {
+			p := &topic.Partitions[i]
+			if p.Index < firstPartition {
+				continue
+			}
+			if limit == 0 {
+				resp.NextCursor = &protocol.DescribeTopicPartitionsResponseCursor{
+					TopicName:      topic.Name,
+					PartitionIndex: p.Index,
+				}
+				break
+			}
+			entry.Partitions = append(entry.Partitions, describePartition(p))
+			limit--
+		}
+
+		// A topic the page has no room for at all is left to the next page
+		if resp.NextCursor != nil && len(entry.Partitions) == 0 {
+			break
+		}
+		resp.Topics = append(resp.Topics, entry)
+		if resp.NextCursor != nil {
+			break
+		}
+	}
+
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// describePartition converts a partition of the metadata image into its
+// DescribeTopicPartitions representation
+func describePartition(p *metadata.Partition) protocol.DescribeTopicPartitionsResponsePartition {
+	return protocol.DescribeTopicPartitionsResponsePartition{
+		ErrorCode:              protocol.ErrorNone,
+		PartitionIndex:         p.Index,
+		LeaderId:               p.Leader,
+		LeaderEpoch:            p.LeaderEpoch,
+		ReplicaNodes:           nonNilInt32s(p.Replicas),
+		IsrNodes:               nonNilInt32s(p.ISR),
+		EligibleLeaderReplicas: nonNilInt32s(p.EligibleLeaderReplicas),
+		LastKnownElr:           nonNilInt32s(p.LastKnownELR),
+		OfflineReplicas:        []int32{},
+	}
+}
+
+// describeTopicPartitionsError builds a failed topic entry
+func describeTopicPartitionsError(name string, errorCode int16) protocol.DescribeTopicPartitionsResponseTopic {
+	return protocol.DescribeTopicPartitionsResponseTopic{
+		ErrorCode:                 errorCode,
+		Name:                      &name,
+		TopicId:                   protocol.ZeroUUID,
+		Partitions:                []protocol.DescribeTopicPartitionsResponsePartition{},
+		TopicAuthorizedOperations: topicAuthorizedOperations,
+	}
+}
+
+// describeTopicPartitionsErrorResponse builds a DescribeTopicPartitions
+// response that fails every requested topic with errorCode
+func (h *RequestHandler) describeTopicPartitionsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.DescribeTopicPartitionsRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.DescribeTopicPartitionsResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		resp.Topics = append(resp.Topics, describeTopicPartitionsError(topic.Name, errorCode))
+	}
+	return protocol.NewResponse(req, resp)
+}
+
+// nonNilInt32s returns s, or an empty slice so that nullable arrays are
+// encoded as empty rather than null
+func nonNilInt32s(s []int32) []int32 {
+	if s == nil {
+		return []int32{}
+	}
+	return s
+}
@@ -0,0 +1,31 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleEndTxnRequest handles END_TXN requests. The transaction's markers
+// are written to all of its partitions before the response is sent.
+func (h *RequestHandler) handleEndTxnRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.EndTxnRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode EndTxn request: %w", err)
+	}
+
+	resp := &protocol.EndTxnResponse{}
+	resp.Default()
+	resp.ErrorCode = h.txn.EndTransaction(body.TransactionalId, body.ProducerId, body.ProducerEpoch, body.Committed)
+	resp.ErrorCode = fencedError(resp.ErrorCode, req.ApiVersion, 2)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// endTxnErrorResponse builds a failed EndTxn response
+func (h *RequestHandler) endTxnErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.EndTxnResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	return protocol.NewResponse(req, resp)
+}
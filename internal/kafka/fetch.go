@@ -0,0 +1,277 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/compression"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// Fetch versions from which consumers read each message format. Records
+// are down-converted for older consumers.
+const (
+	fetchMagicV1MinVersion int16 = 2
+	fetchMagicV2MinVersion int16 = 4
+)
+
+// maxConversionBytes bounds the records of one partition that are
+// converted between message formats at a time, so old clients cannot make
+// the broker hold more than this for them
+const maxConversionBytes = 32 << 20
+
+// fetchMagic returns the newest message format a Fetch version can read
+func fetchMagic(version int16) int8 {
+	switch {
+	case version < fetchMagicV1MinVersion:
+		return record.MagicV0
+	case version < fetchMagicV2MinVersion:
+		return record.MagicV1
+	}
+	return record.MagicV2
+}
+
+// handleFetchRequest handles FETCH requests
+func (h *RequestHandler) handleFetchRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.FetchRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode Fetch request: %w", err)
+	}
+
+	// Fetches that may have to wait watch their partitions before the
+	// first read, so an append racing with it still wakes them
+	minBytes := int(body.MinBytes)
+	var parked *delayedFetch
+	if body.MaxWaitMs > 0 && minBytes > 0 {
+		parked = h.fetches.watch(h.fetchPartitions(body, req.ApiVersion))
+		defer h.fetches.unwatch(parked)
+	}
+
+	resp, n := h.readFetch(body, req.ApiVersion)
+	if parked == nil || n >= minBytes || fetchFailed(resp) {
+		return h.sendResponse(conn, protocol.NewResponse(req, resp))
+	}
+
+	// Wait for enough data, the end of max_wait_ms or shutdown, whichever
+	// comes first; the final read answers with whatever is there
+	timer := time.NewTimer(time.Duration(body.MaxWaitMs) * time.Millisecond)
+	defer timer.Stop()
+	for n < minBytes && !fetchFailed(resp) {
+		select {
+		case <-parked.wake:
+		case <-timer.C:
+			resp, _ = h.readFetch(body, req.ApiVersion)
+			return h.sendResponse(conn, protocol.NewResponse(req, resp))
+		case <-h.done:
+			return h.sendResponse(conn, protocol.NewResponse(req, resp))
+		}
+		resp, n = h.readFetch(body, req.ApiVersion)
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// fetchPartitions returns the partitions a fetch reads from, skipping
+// topics that do not exist
+func (h *RequestHandler) fetchPartitions(body *protocol.FetchRequest, version int16) []storage.TopicPartition {
+	var partitions []storage.TopicPartition
+	for _, fetchTopic := range body.Topics {
+		name := fetchTopic.Topic
+		if version >= 13 {
+			topic := h.metadata.TopicByID(fetchTopic.TopicId)
+			if topic == nil {
+				continue
+			}
+			name = topic.Name
+		}
+		for _, fp := range fetchTopic.Partitions {
+			partitions = append(partitions, storage.TopicPartition{Topic: name, Partition: fp.Partition})
+		}
+	}
+	return partitions
+}
+
+// fetchFailed reports whether any part of a fetch response carries an
+// error, which completes the fetch without waiting
+func fetchFailed(resp *protocol.FetchResponse) bool {
+	if resp.ErrorCode != protocol.ErrorNone {
+		return true
+	}
+	for _, topic := range resp.Responses {
+		for _, partition := range topic.Partitions {
+			if partition.ErrorCode != protocol.ErrorNone {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readFetch reads the data a fetch asks for and returns the response along
+// with the number of record bytes in it
+func (h *RequestHandler) readFetch(body *protocol.FetchRequest, version int16) (*protocol.FetchResponse, int) {
+	resp := &protocol.FetchResponse{}
+	resp.Default()
+
+	// Fetch sessions (KIP-227) are not supported. Answering a full fetch
+	// with session ID 0 tells the client to keep sending full fetches; an
+	// incremental fetch refers to a session the broker cannot know.
+	if body.SessionId != 0 {
+		resp.ErrorCode = protocol.ErrorFetchSessionIDNotFound
+		return resp, 0
+	}
+
+	// MaxBytes bounds the whole response; the first batch is returned even
+	// if larger so that consumers can always make progress (KIP-74)
+	remaining := int(body.MaxBytes)
+	total := 0
+	for _, fetchTopic := range body.Topics {
+		topicResp := protocol.FetchResponseFetchableTopicResponse{Topic: fetchTopic.Topic, TopicId: fetchTopic.TopicId}
+
+		var topic *metadata.Topic
+		missing := protocol.ErrorUnknownTopic
+		if version >= 13 {
+			topic = h.metadata.TopicByID(fetchTopic.TopicId)
+			missing = protocol.ErrorUnknownTopicID
+		} else {
+			topic = h.metadata.TopicByName(fetchTopic.Topic)
+		}
+
+		for i := range fetchTopic.Partitions {
+			fp := &fetchTopic.Partitions[i]
+			if topic == nil {
+				topicResp.Partitions = append(topicResp.Partitions, fetchError(fp.Partition, missing))
+				continue
+			}
+
+			partResp := h.readPartition(version, topic, fp, body.IsolationLevel, remaining, total == 0)
+			n := len(partResp.Records)
+			total += n
+			remaining -= min(n, remaining)
+			topicResp.Partitions = append(topicResp.Partitions, partResp)
+		}
+		resp.Responses = append(resp.Responses, topicResp)
+	}
+	return resp, total
+}
+
+// readPartition reads up to maxBytes (capped by the partition's own limit)
+// from one partition's log. Read-committed fetches only read up to the last
+// stable offset and are told which transactions among the records were
+// aborted. Consumers too old for message format v2 get the records
+// down-converted, unless the topic disables it.
+func (h *RequestHandler) readPartition(version int16, topic *metadata.Topic, fp *protocol.FetchRequestFetchPartition, isolationLevel int8, maxBytes int, minOneBatch bool) protocol.FetchResponsePartitionData {
+	partition, ok := topic.Partition(fp.Partition)
+	if !ok {
+		return fetchError(fp.Partition, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	// A client with a newer leader epoch than ours has metadata we have
+	// not seen yet; one with an older epoch must refresh its metadata
+	switch {
+	case fp.CurrentLeaderEpoch < 0:
+	case fp.CurrentLeaderEpoch > partition.LeaderEpoch:
+		return fetchError(fp.Partition, protocol.ErrorUnknownLeaderEpoch)
+	case fp.CurrentLeaderEpoch < partition.LeaderEpoch:
+		return fetchError(fp.Partition, protocol.ErrorFencedLeaderEpoch)
+	}
+
+	magic := fetchMagic(version)
+	if magic < record.MagicV2 && !h.topicConfigBool(topic.Name, MessageDownConversionEnableConfig) {
+		return fetchError(fp.Partition, protocol.ErrorUnsupportedVersion)
+	}
+
+	log, ok := h.logs.Get(topic.Name, fp.Partition)
+	if !ok {
+		return fetchError(fp.Partition, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	resp := fetchError(fp.Partition, protocol.ErrorNone)
+	resp.HighWatermark = log.HighWatermark()
+	resp.LastStableOffset = log.LastStableOffset()
+	resp.LogStartOffset = log.LogStartOffset()
+
+	var records []byte
+	var aborted []storage.AbortedTransaction
+	var err error
+	maxBytes = min(int(fp.PartitionMaxBytes), maxBytes)
+	if magic < record.MagicV2 {
+		maxBytes = min(maxBytes, maxConversionBytes)
+	}
+	if isolationLevel == protocol.ReadCommitted {
+		records, aborted, err = log.ReadCommitted(fp.FetchOffset, maxBytes, minOneBatch)
+	} else {
+		records, err = log.Read(fp.FetchOffset, maxBytes, minOneBatch)
+	}
+	switch {
+	case errors.Is(err, storage.ErrOffsetOutOfRange):
+		resp.ErrorCode = protocol.ErrorOffsetOutOfRange
+	case err != nil:
+		h.logger.Error("Failed to read %s-%d at offset %d: %s", topic.Name, fp.Partition, fp.FetchOffset, err.Error())
+		resp.ErrorCode = protocol.ErrorKafkaStorageError
+	case records != nil && magic < record.MagicV2:
+		resp.Records, resp.ErrorCode = h.downConvert(topic.Name, fp, records, magic, maxBytes)
+	case records != nil:
+		resp.Records = records
+	}
+	if isolationLevel == protocol.ReadCommitted && resp.ErrorCode == protocol.ErrorNone {
+		resp.AbortedTransactions = make([]protocol.FetchResponseAbortedTransaction, 0, len(aborted))
+		for _, txn := range aborted {
+			resp.AbortedTransactions = append(resp.AbortedTransactions, protocol.FetchResponseAbortedTransaction{
+				ProducerId:  txn.ProducerID,
+				FirstOffset: txn.FirstOffset,
+			})
+		}
+	}
+	return resp
+}
+
+// downConvert converts records read for a consumer that predates message
+// format v2. The output is held to the same byte budget as the read,
+// which ends it at a batch boundary.
+func (h *RequestHandler) downConvert(topic string, fp *protocol.FetchRequestFetchPartition, records []byte, magic int8, maxBytes int) ([]byte, int16) {
+	converted, err := record.DownConvert(records, magic, fp.FetchOffset, maxBytes)
+	switch {
+	case errors.Is(err, compression.ErrUnsupportedCodec):
+		return []byte{}, protocol.ErrorUnsupportedCompressionType
+	case err != nil:
+		h.logger.Error("Failed to convert %s-%d at offset %d to message format v%d: %s", topic, fp.Partition, fp.FetchOffset, magic, err.Error())
+		return []byte{}, protocol.ErrorUnknownServerError
+	}
+	return converted, protocol.ErrorNone
+}
+
+// fetchError builds a partition response carrying errorCode
+func fetchError(index int32, errorCode int16) protocol.FetchResponsePartitionData {
+	resp := protocol.FetchResponsePartitionData{}
+	resp.Default()
+	resp.PartitionIndex = index
+	resp.ErrorCode = errorCode
+	resp.HighWatermark = -1
+	resp.Records = []byte{}
+	return resp
+}
+
+// fetchErrorResponse builds a Fetch response that fails every requested
+// partition with errorCode
+func (h *RequestHandler) fetchErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.FetchRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.FetchResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	for _, topic := range body.Topics {
+		topicResp := protocol.FetchResponseFetchableTopicResponse{Topic: topic.Topic, TopicId: topic.TopicId}
+		for _, partition := range topic.Partitions {
+			topicResp.Partitions = append(topicResp.Partitions, fetchError(partition.Partition, errorCode))
+		}
+		resp.Responses = append(resp.Responses, topicResp)
+	}
+	return protocol.NewResponse(req, resp)
+}
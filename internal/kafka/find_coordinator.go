@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/txn"
+)
+
+// handleFindCoordinatorRequest handles FIND_COORDINATOR requests. This
+// broker coordinates every group and transactional ID itself; the offsets
+// topic is created on the first lookup of a group, and the transaction
+// state topic on the first lookup of a transactional ID.
+func (h *RequestHandler) handleFindCoordinatorRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.FindCoordinatorRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode FindCoordinator request: %w", err)
+	}
+
+	resp := &protocol.FindCoordinatorResponse{}
+	resp.Default()
+	for _, key := range findCoordinatorKeys(body, req.ApiVersion) {
+		if body.KeyType != protocol.CoordinatorKeyGroup && body.KeyType != protocol.CoordinatorKeyTransaction {
+			resp.Coordinators = append(resp.Coordinators, findCoordinatorError(key, protocol.ErrorInvalidRequest,
+				fmt.Sprintf("Unsupported key type %d", body.KeyType)))
+			continue
+		}
+		if body.KeyType == protocol.CoordinatorKeyGroup {
+			if err := h.ensureOffsetsTopic(); err != nil {
+				h.logger.Error("Failed to create %s: %s", group.OffsetsTopic, err.Error())
+				resp.Coordinators = append(resp.Coordinators, findCoordinatorError(key, protocol.ErrorCoordinatorNotAvailable, ""))
+				continue
+			}
+		}
+		if body.KeyType == protocol.CoordinatorKeyTransaction {
+			if err := h.ensureTransactionStateTopic(); err != nil {
+				h.logger.Error("Failed to create %s: %s", txn.StateTopic, err.Error())
+				resp.Coordinators = append(resp.Coordinators, findCoordinatorError(key, protocol.ErrorCoordinatorNotAvailable, ""))
+				continue
+			}
+		}
+		resp.Coordinators = append(resp.Coordinators, protocol.FindCoordinatorResponseCoordinator{
+			Key:       key,
+			NodeId:    h.config.NodeID,
+			Host:      h.config.Host,
+			Port:      h.config.Port,
+			ErrorCode: protocol.ErrorNone,
+		})
+	}
+
+	return h.sendResponse(conn, protocol.NewResponse(req, findCoordinatorResponse(resp, req.ApiVersion)))
+}
+
+// findCoordinatorKeys returns the keys a request looks up: a batch from v4,
+// a single key before
+func findCoordinatorKeys(body *protocol.FindCoordinatorRequest, version int16) []string {
+	if version >= 4 {
+		return body.CoordinatorKeys
+	}
+	return []string{body.Key}
+}
+
+// findCoordinatorResponse moves the single result of a pre-v4 response
+// into the top-level fields those versions use
+func findCoordinatorResponse(resp *protocol.FindCoordinatorResponse, version int16) *protocol.FindCoordinatorResponse {
+	if version >= 4 || len(resp.Coordinators) == 0 {
+		return resp
+	}
+	c := resp.Coordinators[0]
+	resp.ErrorCode = c.ErrorCode
+	resp.ErrorMessage = c.ErrorMessage
+	resp.NodeId = c.NodeId
+	resp.Host = c.Host
+	resp.Port = c.Port
+	resp.Coordinators = nil
+	return resp
+}
+
+// findCoordinatorError builds a failed coordinator result
+func findCoordinatorError(key string, errorCode int16, message string) protocol.FindCoordinatorResponseCoordinator {
+	result := protocol.FindCoordinatorResponseCoordinator{
+		Key:       key,
+		NodeId:    -1,
+		Host:      "",
+		Port:      -1,
+		ErrorCode: errorCode,
+	}
+	if message != "" {
+		result.ErrorMessage = &message
+	}
+	return result
+}
+
+// findCoordinatorErrorResponse builds a FindCoordinator response that fails
+// every requested key with errorCode
+func (h *RequestHandler) findCoordinatorErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.FindCoordinatorRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.FindCoordinatorResponse{}
+	resp.Default()
+	for _, key := range findCoordinatorKeys(body, req.ApiVersion) {
+		resp.Coordinators = append(resp.Coordinators, findCoordinatorError(key, errorCode, ""))
+	}
+	return protocol.NewResponse(req, findCoordinatorResponse(resp, req.ApiVersion))
+}
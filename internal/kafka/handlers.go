@@ -5,298 +5,228 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"regexp"
+	"sync"
 
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
 	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/internal/txn"
 	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
 )
 
+// Feature flags advertised in ApiVersions responses
+const (
+	MetadataVersionFeature = "metadata.version"
+	KRaftVersionFeature    = "kraft.version"
+)
+
+// clientSoftwarePattern matches valid client software names and versions (KIP-511)
+var clientSoftwarePattern = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9\-.]*[a-zA-Z0-9])?$`)
+
+// BrokerConfig describes this broker to the request handler
+type BrokerConfig struct {
+	NodeID    int32
+	ClusterID string
+
+	// Host and Port are the address advertised to clients
+	Host string
+	Port int32
+
+	// AutoCreateTopics allows Metadata requests to create missing topics
+	AutoCreateTopics         bool
+	NumPartitions            int32
+	DefaultReplicationFactor int16
+}
+
 // RequestHandler handles incoming Kafka protocol requests
 type RequestHandler struct {
-	logger *logger.Logger
+	logger   *logger.Logger
+	config   BrokerConfig
+	registry *registry
+	metadata *metadata.Image
+	logs     *storage.Manager
+	fetches  *fetchPurgatory
+	groups   *group.Coordinator
+	txn      *txn.Coordinator
+
+	// topicsMu serializes topic creation and deletion
+	topicsMu sync.Mutex
+
+	// done is closed on shutdown to release requests that are waiting
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
-// NewRequestHandler creates a new request handler
-func NewRequestHandler(logger *logger.Logger) *RequestHandler {
-	return &RequestHandler{
-		logger: logger,
+// NewRequestHandler creates a new request handler for the broker described
+// by config, serving the given cluster metadata, partition logs, groups and
+// transactions
+func NewRequestHandler(logger *logger.Logger, config BrokerConfig, image *metadata.Image, logs *storage.Manager, groups *group.Coordinator, transactions *txn.Coordinator) *RequestHandler {
+	h := &RequestHandler{
+		logger:   logger,
+		config:   config,
+		registry: newRegistry(),
+		metadata: image,
+		logs:     logs,
+		fetches:  newFetchPurgatory(),
+		groups:   groups,
+		txn:      transactions,
+		done:     make(chan struct{}),
 	}
-}
-
-// HandleRequest processes a Kafka protocol request and sends the appropriate response
-func (h *RequestHandler) HandleRequest(conn net.Conn, req *protocol.Request) error {
-	// Check if API version is supported
-	if req.ApiVersion < protocol.MinSupportedVersion || req.ApiVersion > protocol.MaxSupportedVersion {
-		return h.sendErrorResponse(conn, req.CorrelationID, protocol.ErrorUnsupportedVersion)
+	transactions.OnMarkerWritten(h.fetches.notify)
+	logs.SetSegmentPolicy(h.SegmentPolicy)
+	h.registerHandlers()
+
+	// Requests only use existing logs, so partitions the metadata has but
+	// the log dir lacks get theirs now
+	for _, topic := range image.Topics() {
+		h.createLogs(topic.Name, 0, int32(len(topic.Partitions)))
 	}
+	h.registry.setFinalizedFeatures(image.FinalizedFeatures())
 
-	// Handle the request based on the API key
-	switch req.ApiKey {
-	case protocol.ApiVersionsKey:
-		return h.handleApiVersionsRequest(conn, req)
-	case protocol.DescribeTopicPartitionsKey:
-		return h.handleDescribeTopicPartitionsRequest(conn, req)
-	default:
-		return h.handleGenericRequest(conn, req)
-	}
+	return h
 }
 
-// handleApiVersionsRequest handles API_VERSIONS requests
-func (h *RequestHandler) handleApiVersionsRequest(conn net.Conn, req *protocol.Request) error {
-	// Create response for API_VERSIONS request
-	response := make([]byte, 26)
-
-	// Correlation ID
-	binary.BigEndian.PutUint32(response[0:4], uint32(req.CorrelationID))
-
-	// Error code (0 for success)
-	binary.BigEndian.PutUint16(response[4:6], protocol.ErrorNone)
-
-	// Number of API keys in response
-	response[6] = 3
-
-	// API Key 1 - API_VERSIONS (18)
-	binary.BigEndian.PutUint16(response[7:9], uint16(protocol.ApiVersionsKey))
-	binary.BigEndian.PutUint16(response[9:11], uint16(protocol.ApiVersionsMinVersion))  // Min version
-	binary.BigEndian.PutUint16(response[11:13], uint16(protocol.ApiVersionsMaxVersion)) // Max version
-	response[13] = 0                                                                    // _tagged_fields
-
-	// API Key 2 - DescribeTopicPartitions (75)
-	binary.BigEndian.PutUint16(response[14:16], uint16(protocol.DescribeTopicPartitionsKey))
-	binary.BigEndian.PutUint16(response[16:18], uint16(protocol.DescribeTopicMinVersion)) // Min version
-	binary.BigEndian.PutUint16(response[18:20], uint16(protocol.DescribeTopicMaxVersion)) // Max version
-	response[20] = 0                                                                      // _tagged_fields
-
-	// Throttle time
-	binary.BigEndian.PutUint32(response[21:25], 0)
-
-	// _tagged_fields for the overall response
-	response[25] = 0
-
-	return h.sendRawResponse(conn, response)
+// registerHandlers registers every supported API and feature with the registry
+func (h *RequestHandler) registerHandlers() {
+	h.registry.register(protocol.ProduceKey, protocol.ProduceMinVersion, protocol.ProduceMaxVersion,
+		h.handleProduceRequest, h.produceErrorResponse)
+	h.registry.register(protocol.FetchKey, protocol.FetchMinVersion, protocol.FetchMaxVersion,
+		h.handleFetchRequest, h.fetchErrorResponse)
+	h.registry.register(protocol.ListOffsetsKey, protocol.ListOffsetsMinVersion, protocol.ListOffsetsMaxVersion,
+		h.handleListOffsetsRequest, h.listOffsetsErrorResponse)
+	h.registry.register(protocol.MetadataKey, protocol.MetadataMinVersion, protocol.MetadataMaxVersion,
+		h.handleMetadataRequest, h.metadataErrorResponse)
+	h.registry.register(protocol.OffsetCommitKey, protocol.OffsetCommitMinVersion, protocol.OffsetCommitMaxVersion,
+		h.handleOffsetCommitRequest, h.offsetCommitErrorResponse)
+	h.registry.register(protocol.OffsetFetchKey, protocol.OffsetFetchMinVersion, protocol.OffsetFetchMaxVersion,
+		h.handleOffsetFetchRequest, h.offsetFetchErrorResponse)
+	h.registry.register(protocol.FindCoordinatorKey, protocol.FindCoordinatorMinVersion, protocol.FindCoordinatorMaxVersion,
+		h.handleFindCoordinatorRequest, h.findCoordinatorErrorResponse)
+	h.registry.register(protocol.JoinGroupKey, protocol.JoinGroupMinVersion, protocol.JoinGroupMaxVersion,
+		h.handleJoinGroupRequest, h.joinGroupErrorResponse)
+	h.registry.register(protocol.HeartbeatKey, protocol.HeartbeatMinVersion, protocol.HeartbeatMaxVersion,
+		h.handleHeartbeatRequest, h.heartbeatErrorResponse)
+	h.registry.register(protocol.LeaveGroupKey, protocol.LeaveGroupMinVersion, protocol.LeaveGroupMaxVersion,
+		h.handleLeaveGroupRequest, h.leaveGroupErrorResponse)
+	h.registry.register(protocol.SyncGroupKey, protocol.SyncGroupMinVersion, protocol.SyncGroupMaxVersion,
+		h.handleSyncGroupRequest, h.syncGroupErrorResponse)
+	h.registry.register(protocol.DescribeGroupsKey, protocol.DescribeGroupsMinVersion, protocol.DescribeGroupsMaxVersion,
+		h.handleDescribeGroupsRequest, h.describeGroupsErrorResponse)
+	h.registry.register(protocol.ListGroupsKey, protocol.ListGroupsMinVersion, protocol.ListGroupsMaxVersion,
+		h.handleListGroupsRequest, h.listGroupsErrorResponse)
+	h.registry.register(protocol.ApiVersionsKey, protocol.ApiVersionsMinVersion, protocol.ApiVersionsMaxVersion,
+		h.handleApiVersionsRequest, h.apiVersionsErrorResponse)
+	h.registry.register(protocol.CreateTopicsKey, protocol.CreateTopicsMinVersion, protocol.CreateTopicsMaxVersion,
+		h.handleCreateTopicsRequest, h.createTopicsErrorResponse)
+	h.registry.register(protocol.DeleteTopicsKey, protocol.DeleteTopicsMinVersion, protocol.DeleteTopicsMaxVersion,
+		h.handleDeleteTopicsRequest, h.deleteTopicsErrorResponse)
+	h.registry.register(protocol.InitProducerIdKey, protocol.InitProducerIdMinVersion, protocol.InitProducerIdMaxVersion,
+		h.handleInitProducerIdRequest, h.initProducerIdErrorResponse)
+	h.registry.register(protocol.AddPartitionsToTxnKey, protocol.AddPartitionsToTxnMinVersion, protocol.AddPartitionsToTxnMaxVersion,
+		h.handleAddPartitionsToTxnRequest, h.addPartitionsToTxnErrorResponse)
+	h.registry.register(protocol.AddOffsetsToTxnKey, protocol.AddOffsetsToTxnMinVersion, protocol.AddOffsetsToTxnMaxVersion,
+		h.handleAddOffsetsToTxnRequest, h.addOffsetsToTxnErrorResponse)
+	h.registry.register(protocol.EndTxnKey, protocol.EndTxnMinVersion, protocol.EndTxnMaxVersion,
+		h.handleEndTxnRequest, h.endTxnErrorResponse)
+	h.registry.register(protocol.WriteTxnMarkersKey, protocol.WriteTxnMarkersMinVersion, protocol.WriteTxnMarkersMaxVersion,
+		h.handleWriteTxnMarkersRequest, h.writeTxnMarkersErrorResponse)
+	h.registry.register(protocol.TxnOffsetCommitKey, protocol.TxnOffsetCommitMinVersion, protocol.TxnOffsetCommitMaxVersion,
+		h.handleTxnOffsetCommitRequest, h.txnOffsetCommitErrorResponse)
+	h.registry.register(protocol.CreatePartitionsKey, protocol.CreatePartitionsMinVersion, protocol.CreatePartitionsMaxVersion,
+		h.handleCreatePartitionsRequest, h.createPartitionsErrorResponse)
+	h.registry.register(protocol.DeleteGroupsKey, protocol.DeleteGroupsMinVersion, protocol.DeleteGroupsMaxVersion,
+		h.handleDeleteGroupsRequest, h.deleteGroupsErrorResponse)
+	h.registry.register(protocol.ConsumerGroupHeartbeatKey, protocol.ConsumerGroupHeartbeatMinVersion, protocol.ConsumerGroupHeartbeatMaxVersion,
+		h.handleConsumerGroupHeartbeatRequest, h.consumerGroupHeartbeatErrorResponse)
+	h.registry.register(protocol.ConsumerGroupDescribeKey, protocol.ConsumerGroupDescribeMinVersion, protocol.ConsumerGroupDescribeMaxVersion,
+		h.handleConsumerGroupDescribeRequest, h.consumerGroupDescribeErrorResponse)
+	h.registry.register(protocol.DescribeTopicPartitionsKey, protocol.DescribeTopicMinVersion, protocol.DescribeTopicMaxVersion,
+		h.handleDescribeTopicPartitionsRequest, h.describeTopicPartitionsErrorResponse)
+
+	h.registry.registerFeature(MetadataVersionFeature, 1, 21)
+	h.registry.registerFeature(KRaftVersionFeature, 0, 1)
 }
 
-// handleDescribeTopicPartitionsRequest handles DESCRIBE_TOPIC_PARTITIONS requests
-func (h *RequestHandler) handleDescribeTopicPartitionsRequest(conn net.Conn, req *protocol.Request) error {
-	// Parse the request
-	// The first part of the payload contains the client ID (string) followed by a tag buffer
-	offset := 0
+// Close releases requests parked waiting for data, such as long-polling
+// fetches and group joins, so that their connections can finish, and stops
+// the group and transaction timers
+func (h *RequestHandler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+		h.groups.Close()
+		h.txn.Close()
+	})
+}
 
-	// Skip the client ID
-	if offset >= len(req.Payload) {
-		return fmt.Errorf("unexpected end of payload when reading client ID length")
+// HandleRequest processes a Kafka protocol request and sends the appropriate response
+func (h *RequestHandler) HandleRequest(conn net.Conn, req *protocol.Request) error {
+	// Unknown API keys cannot be answered in any schema the client would
+	// understand, so like Kafka we drop the connection
+	entry, ok := h.registry.lookup(req.ApiKey)
+	if !ok {
+		return fmt.Errorf("unsupported API key %d", req.ApiKey)
 	}
 
-	// Client ID is a string with 2-byte length prefix
-	clientIDLength := int(binary.BigEndian.Uint16(req.Payload[offset : offset+2]))
-	offset += 2 + clientIDLength
-
-	// Skip the client ID tag buffer (1 byte)
-	if offset >= len(req.Payload) {
-		return fmt.Errorf("unexpected end of payload when reading client ID tag buffer")
+	// Check if API version is supported by this API's handler
+	if !entry.supportsVersion(req.ApiVersion) {
+		h.logger.Info("Unsupported %s version %d (supported %d-%d)",
+			protocol.APIName(req.ApiKey), req.ApiVersion, entry.minVersion, entry.maxVersion)
+		return h.sendResponse(conn, entry.errorResponse(req, protocol.ErrorUnsupportedVersion))
 	}
-	offset++
 
-	// Now we're at the topics array
-	// First byte indicates the array length (varint encoding: actual length + 1)
-	if offset >= len(req.Payload) {
-		return fmt.Errorf("unexpected end of payload when reading topics array length")
-	}
+	return entry.handle(conn, req)
+}
 
-	topicArrayLength := int(req.Payload[offset]) - 1
-	if topicArrayLength < 0 {
-		return fmt.Errorf("invalid topics array length")
+// handleApiVersionsRequest handles API_VERSIONS requests
+func (h *RequestHandler) handleApiVersionsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.ApiVersionsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode ApiVersions request: %w", err)
 	}
-	offset++
-
-	// Start building the response
-	// Estimate size - we'll resize later if needed
-	responseSize := 12 + (40 * topicArrayLength) // rough estimate: header + topics array
-	response := make([]byte, responseSize)
-
-	// Response header
-	// Correlation ID
-	binary.BigEndian.PutUint32(response[0:4], uint32(req.CorrelationID))
 
-	// Tag buffer for the header (empty)
-	response[4] = 0
+	resp := &protocol.ApiVersionsResponse{}
+	resp.Default()
 
-	// Throttle time (0)
-	binary.BigEndian.PutUint32(response[5:9], 0)
-
-	// Topics array length (compact array encoding)
-	response[9] = byte(topicArrayLength + 1) // varint encoding of array length + 1
-
-	// Parse and process each topic in the request
-	responseOffset := 10 // Start after the topics array length in response
-
-	for i := 0; i < topicArrayLength; i++ {
-		// Extract the topic name length (varint)
-		if offset >= len(req.Payload) {
-			return fmt.Errorf("unexpected end of payload when reading topic name length")
+	// v3+ clients identify themselves; reject malformed names and versions
+	if req.ApiVersion >= 3 {
+		h.logger.Debug("ApiVersions from client software %s %s", body.ClientSoftwareName, body.ClientSoftwareVersion)
+		if !clientSoftwarePattern.MatchString(body.ClientSoftwareName) || !clientSoftwarePattern.MatchString(body.ClientSoftwareVersion) {
+			return h.sendResponse(conn, h.apiVersionsErrorResponse(req, protocol.ErrorInvalidRequest))
 		}
-
-		topicNameLength := int(req.Payload[offset]) - 1
-		offset++
-
-		// Extract the topic name
-		if offset+topicNameLength > len(req.Payload) {
-			return fmt.Errorf("invalid topic name length or unexpected end of payload")
-		}
-
-		topicName := string(req.Payload[offset : offset+topicNameLength])
-		offset += topicNameLength
-
-		// Skip any tag buffer at the end of the topic entry
-		if offset < len(req.Payload) && req.Payload[offset] == 0 {
-			offset++
-		}
-
-		// Now add this topic to the response
-
-		// Topic error code - UNKNOWN_TOPIC (3)
-		if responseOffset+2 > len(response) {
-			// Expand response buffer if needed
-			newResponse := make([]byte, len(response)*2)
-			copy(newResponse, response)
-			response = newResponse
-		}
-		binary.BigEndian.PutUint16(response[responseOffset:responseOffset+2], protocol.ErrorUnknownTopic)
-		responseOffset += 2
-
-		// Topic name (compact string)
-		if responseOffset+1+len(topicName) > len(response) {
-			// Expand response buffer if needed
-			newResponse := make([]byte, len(response)*2)
-			copy(newResponse, response)
-			response = newResponse
-		}
-		response[responseOffset] = byte(len(topicName) + 1) // varint encoding of string length + 1
-		responseOffset++
-		copy(response[responseOffset:responseOffset+len(topicName)], topicName)
-		responseOffset += len(topicName)
-
-		// Topic ID (all zeros for unknown topic - 16 bytes)
-		if responseOffset+16 > len(response) {
-			// Expand response buffer if needed
-			newResponse := make([]byte, len(response)*2)
-			copy(newResponse, response)
-			response = newResponse
-		}
-		// No need to set these explicitly as the buffer is already zeroed
-		responseOffset += 16
-
-		// is_internal (false)
-		if responseOffset+1 > len(response) {
-			// Expand response buffer if needed
-			newResponse := make([]byte, len(response)*2)
-			copy(newResponse, response)
-			response = newResponse
-		}
-		response[responseOffset] = 0
-		responseOffset++
-
-		// Partitions array (empty)
-		if responseOffset+1 > len(response) {
-			// Expand response buffer if needed
-			newResponse := make([]byte, len(response)*2)
-			copy(newResponse, response)
-			response = newResponse
-		}
-		response[responseOffset] = 1 // varint encoding of array length + 1 (empty array)
-		responseOffset++
-
-		// Topic authorized operations
-		if responseOffset+4 > len(response) {
-			// Expand response buffer if needed
-			newResponse := make([]byte, len(response)*2)
-			copy(newResponse, response)
-			response = newResponse
-		}
-		binary.BigEndian.PutUint32(response[responseOffset:responseOffset+4], 0x00000df8)
-		responseOffset += 4
-
-		// Tag buffer for the topic (empty)
-		if responseOffset+1 > len(response) {
-			// Expand response buffer if needed
-			newResponse := make([]byte, len(response)*2)
-			copy(newResponse, response)
-			response = newResponse
-		}
-		response[responseOffset] = 0
-		responseOffset++
 	}
 
-	// Skip the response partition limit and cursor in the request
-	// (We don't need these values for the response)
+	resp.ErrorCode = protocol.ErrorNone
+	h.registry.apiVersions(resp, req.ApiVersion)
 
-	// Next cursor (null)
-	if responseOffset+1 > len(response) {
-		// Expand response buffer if needed
-		newResponse := make([]byte, len(response)*2)
-		copy(newResponse, response)
-		response = newResponse
-	}
-	response[responseOffset] = 0xff
-	responseOffset++
-
-	// Tag buffer (empty)
-	if responseOffset+1 > len(response) {
-		// Expand response buffer if needed
-		newResponse := make([]byte, len(response)*2)
-		copy(newResponse, response)
-		response = newResponse
-	}
-	response[responseOffset] = 0
-	responseOffset++
-
-	// Trim the response to the actual size used
-	return h.sendRawResponse(conn, response[:responseOffset])
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
 }
 
-// handleGenericRequest handles any other request type
-func (h *RequestHandler) handleGenericRequest(conn net.Conn, req *protocol.Request) error {
-	// Simple response for other API keys
-	resp := &protocol.Response{
-		CorrelationID: req.CorrelationID,
-		ErrorCode:     protocol.ErrorNone,
+// apiVersionsErrorResponse builds a failed ApiVersions response. When the
+// client asked for a version we do not support, the response is encoded as
+// v0 and lists the ApiVersions versions we do support, so the client can
+// retry with one of them (KIP-511).
+func (h *RequestHandler) apiVersionsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.ApiVersionsResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+
+	response := protocol.NewResponse(req, resp)
+	if errorCode == protocol.ErrorUnsupportedVersion {
+		resp.ApiKeys = []protocol.ApiVersionsResponseApiVersion{{
+			ApiKey:     protocol.ApiVersionsKey,
+			MinVersion: protocol.ApiVersionsMinVersion,
+			MaxVersion: protocol.ApiVersionsMaxVersion,
+		}}
+		response.ApiVersion = 0
 	}
-
-	return h.sendResponse(conn, resp)
-}
-
-// sendErrorResponse sends an error response back to the client
-func (h *RequestHandler) sendErrorResponse(conn net.Conn, correlationID int32, errorCode uint16) error {
-	// Create minimal error response
-	response := make([]byte, 6)
-	binary.BigEndian.PutUint32(response[0:4], uint32(correlationID))
-	binary.BigEndian.PutUint16(response[4:6], errorCode)
-
-	return h.sendRawResponse(conn, response)
+	return response
 }
 
 // sendResponse sends a structured response back to the client
 func (h *RequestHandler) sendResponse(conn net.Conn, response *protocol.Response) error {
-	// Calculate response size
-	responseSize := 6 // 4 bytes for correlationID + 2 bytes for errorCode
-	if response.Payload != nil {
-		responseSize += len(response.Payload)
-	}
-
-	// Create response buffer
-	buffer := make([]byte, responseSize)
-
-	// Write correlation ID
-	binary.BigEndian.PutUint32(buffer[0:4], uint32(response.CorrelationID))
-
-	// Write error code
-	binary.BigEndian.PutUint16(buffer[4:6], response.ErrorCode)
-
-	// Write payload if present
-	if response.Payload != nil {
-		copy(buffer[6:], response.Payload)
-	}
-
-	return h.sendRawResponse(conn, buffer)
+	return h.sendRawResponse(conn, response.Encode())
 }
 
 // sendRawResponse sends raw byte data back to the client
@@ -0,0 +1,224 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// exchange has h handle a request for body at the given API version and
+// returns the response, less its size
+func exchange(t *testing.T, h *RequestHandler, body protocol.Message, version int16) []byte {
+	t.Helper()
+	e := protocol.NewEncoder(64)
+	body.Encode(e, version)
+	req := &protocol.Request{
+		ApiKey:        body.APIKey(),
+		ApiVersion:    version,
+		CorrelationID: 42,
+		Payload:       e.Bytes(),
+	}
+
+	server, client := net.Pipe()
+	defer client.Close()
+	errs := make(chan error, 1)
+	go func() {
+		defer server.Close()
+		errs <- h.HandleRequest(server, req)
+	}()
+
+	var size int32
+	if err := binary.Read(client, binary.BigEndian, &size); err != nil {
+		t.Fatalf("reading response size: %v", err)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(client, data); err != nil {
+		t.Fatalf("reading response: %v", err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("HandleRequest: %v", err)
+	}
+	return data
+}
+
+// decodeResponse decodes data as a response header of headerVersion
+// followed by body at version, and fails unless that consumes all of it
+func decodeResponse(t *testing.T, data []byte, headerVersion int16, body protocol.Message, version int16) {
+	t.Helper()
+	d := protocol.NewDecoder(data)
+	header := &protocol.ResponseHeader{}
+	if err := header.Decode(d, headerVersion); err != nil {
+		t.Fatalf("decoding header v%d: %v", headerVersion, err)
+	}
+	if header.CorrelationId != 42 {
+		t.Errorf("correlation ID = %d, want 42", header.CorrelationId)
+	}
+	if err := body.Decode(d, version); err != nil {
+		t.Fatalf("decoding body v%d: %v", version, err)
+	}
+	if d.Remaining() != 0 {
+		t.Errorf("%d bytes left after the body", d.Remaining())
+	}
+}
+
+// newRegisteredHandler returns a test handler serving every API
+func newRegisteredHandler(t *testing.T) *RequestHandler {
+	h := newTestHandler(t)
+	h.registerHandlers()
+	return h
+}
+
+// apiVersionsRequest returns a valid ApiVersions request
+func apiVersionsRequest() *protocol.ApiVersionsRequest {
+	return &protocol.ApiVersionsRequest{ClientSoftwareName: "test-client", ClientSoftwareVersion: "1.0"}
+}
+
+func TestResponseHeaderVersion(t *testing.T) {
+	h := newRegisteredHandler(t)
+	topic := testTopic
+	tests := []struct {
+		name          string
+		body          protocol.Message
+		version       int16
+		headerVersion int16
+	}{
+		{"ApiVersions v0", apiVersionsRequest(), 0, 0},
+		// ApiVersions keeps a v0 header in flexible versions, so clients
+		// can read it before knowing what the broker supports
+		{"ApiVersions v3", apiVersionsRequest(), 3, 0},
+		{"ApiVersions v4", apiVersionsRequest(), 4, 0},
+		{"Metadata v8", &protocol.MetadataRequest{Topics: []protocol.MetadataRequestTopic{{Name: &topic}}}, 8, 0},
+		{"Metadata v9", &protocol.MetadataRequest{Topics: []protocol.MetadataRequestTopic{{Name: &topic}}}, 9, 1},
+		{"Metadata v12", &protocol.MetadataRequest{Topics: []protocol.MetadataRequestTopic{{Name: &topic}}}, 12, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protocol.ResponseHeaderVersion(tt.body.APIKey(), tt.version); got != tt.headerVersion {
+				t.Errorf("ResponseHeaderVersion = %d, want %d", got, tt.headerVersion)
+			}
+			var resp protocol.Message = &protocol.MetadataResponse{}
+			if tt.body.APIKey() == protocol.ApiVersionsKey {
+				resp = &protocol.ApiVersionsResponse{}
+			}
+			decodeResponse(t, exchange(t, h, tt.body, tt.version), tt.headerVersion, resp, tt.version)
+		})
+	}
+}
+
+func TestApiVersionsAdvertisesRegistry(t *testing.T) {
+	h := newRegisteredHandler(t)
+	for version := protocol.ApiVersionsMinVersion; version <= protocol.ApiVersionsMaxVersion; version++ {
+		resp := &protocol.ApiVersionsResponse{}
+		decodeResponse(t, exchange(t, h, apiVersionsRequest(), version), 0, resp, version)
+		if resp.ErrorCode != protocol.ErrorNone {
+			t.Fatalf("v%d: error code %d", version, resp.ErrorCode)
+		}
+
+		// Every registered API is advertised once, in key order, with the
+		// versions its handler supports
+		if len(resp.ApiKeys) != len(h.registry.apis) {
+			t.Errorf("v%d: %d APIs advertised, want %d", version, len(resp.ApiKeys), len(h.registry.apis))
+		}
+		for i, api := range resp.ApiKeys {
+			if i > 0 && api.ApiKey <= resp.ApiKeys[i-1].ApiKey {
+				t.Errorf("v%d: API %d advertised after %d", version, api.ApiKey, resp.ApiKeys[i-1].ApiKey)
+			}
+			entry, ok := h.registry.lookup(api.ApiKey)
+			if !ok {
+				t.Errorf("v%d: unregistered API %d advertised", version, api.ApiKey)
+				continue
+			}
+			if api.MinVersion != entry.minVersion || api.MaxVersion != entry.maxVersion {
+				t.Errorf("v%d: %s versions %d-%d, want %d-%d", version, protocol.APIName(api.ApiKey),
+					api.MinVersion, api.MaxVersion, entry.minVersion, entry.maxVersion)
+			}
+		}
+
+		// Features only exist from v3, and one with a min version of 0
+		// only from v4
+		features := make(map[string][2]int16)
+		for _, f := range resp.SupportedFeatures {
+			features[f.Name] = [2]int16{f.MinVersion, f.MaxVersion}
+		}
+		want := map[string][2]int16{}
+		if version >= 3 {
+			want[MetadataVersionFeature] = [2]int16{1, 21}
+		}
+		if version >= 4 {
+			want[KRaftVersionFeature] = [2]int16{0, 1}
+		}
+		if len(features) != len(want) {
+			t.Errorf("v%d: features %v, want %v", version, features, want)
+		}
+		for name, versions := range want {
+			if features[name] != versions {
+				t.Errorf("v%d: feature %s versions %v, want %v", version, name, features[name], versions)
+			}
+		}
+	}
+
+	// A newly registered API is advertised without further changes
+	h.registry.register(protocol.ProduceKey, 3, 5, h.handleProduceRequest, h.produceErrorResponse)
+	resp := &protocol.ApiVersionsResponse{}
+	decodeResponse(t, exchange(t, h, apiVersionsRequest(), 3), 0, resp, 3)
+	for _, api := range resp.ApiKeys {
+		if api.ApiKey == protocol.ProduceKey && (api.MinVersion != 3 || api.MaxVersion != 5) {
+			t.Errorf("Produce versions %d-%d, want 3-5", api.MinVersion, api.MaxVersion)
+		}
+	}
+}
+
+func TestApiVersionsInvalidClientSoftware(t *testing.T) {
+	h := newRegisteredHandler(t)
+	req := apiVersionsRequest()
+	req.ClientSoftwareName = "-bad name"
+	resp := &protocol.ApiVersionsResponse{}
+	decodeResponse(t, exchange(t, h, req, 3), 0, resp, 3)
+	if resp.ErrorCode != protocol.ErrorInvalidRequest {
+		t.Errorf("error code %d, want %d", resp.ErrorCode, protocol.ErrorInvalidRequest)
+	}
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	h := newRegisteredHandler(t)
+
+	// An ApiVersions version from the future is answered in v0, listing the
+	// versions to retry with
+	for _, version := range []int16{protocol.ApiVersionsMaxVersion + 1, 99} {
+		resp := &protocol.ApiVersionsResponse{}
+		decodeResponse(t, exchange(t, h, apiVersionsRequest(), version), 0, resp, 0)
+		if resp.ErrorCode != protocol.ErrorUnsupportedVersion {
+			t.Errorf("v%d: error code %d, want %d", version, resp.ErrorCode, protocol.ErrorUnsupportedVersion)
+		}
+		want := []protocol.ApiVersionsResponseApiVersion{{
+			ApiKey:     protocol.ApiVersionsKey,
+			MinVersion: protocol.ApiVersionsMinVersion,
+			MaxVersion: protocol.ApiVersionsMaxVersion,
+		}}
+		if !reflect.DeepEqual(resp.ApiKeys, want) {
+			t.Errorf("v%d: APIs %+v, want %+v", version, resp.ApiKeys, want)
+		}
+	}
+
+	// Other APIs answer in their own schema at the version asked for
+	topic := testTopic
+	version := protocol.MetadataMaxVersion + 1
+	req := &protocol.MetadataRequest{Topics: []protocol.MetadataRequestTopic{{Name: &topic}}}
+	resp := &protocol.MetadataResponse{}
+	decodeResponse(t, exchange(t, h, req, version), 1, resp, version)
+	if len(resp.Topics) != 1 || resp.Topics[0].ErrorCode != protocol.ErrorUnsupportedVersion {
+		t.Errorf("topics %+v, want %s with error code %d", resp.Topics, topic, protocol.ErrorUnsupportedVersion)
+	}
+
+	// Unknown API keys drop the connection
+	server, client := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	if err := h.HandleRequest(server, &protocol.Request{ApiKey: 9999, CorrelationID: 42}); err == nil {
+		t.Error("unknown API key handled")
+	}
+}
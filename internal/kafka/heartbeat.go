@@ -0,0 +1,29 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleHeartbeatRequest handles HEARTBEAT requests
+func (h *RequestHandler) handleHeartbeatRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.HeartbeatRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode Heartbeat request: %w", err)
+	}
+
+	resp := &protocol.HeartbeatResponse{}
+	resp.Default()
+	resp.ErrorCode = h.groups.Heartbeat(body)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// heartbeatErrorResponse builds a failed Heartbeat response
+func (h *RequestHandler) heartbeatErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.HeartbeatResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	return protocol.NewResponse(req, resp)
+}
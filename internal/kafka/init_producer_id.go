@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/txn"
+)
+
+// handleInitProducerIdRequest handles INIT_PRODUCER_ID requests. An
+// idempotent producer gets a fresh producer ID at epoch 0; it starts its
+// sequence numbers over with every new ID. A transactional producer gets
+// the producer ID of its transactional ID at a bumped epoch from the
+// transaction coordinator, which creates the state topic on first use.
+func (h *RequestHandler) handleInitProducerIdRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.InitProducerIdRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode InitProducerId request: %w", err)
+	}
+
+	resp := &protocol.InitProducerIdResponse{}
+	resp.Default()
+	if body.TransactionalId != nil {
+		if err := h.ensureTransactionStateTopic(); err != nil {
+			h.logger.Error("Failed to create %s: %s", txn.StateTopic, err.Error())
+			return h.sendResponse(conn, h.initProducerIdErrorResponse(req, protocol.ErrorCoordinatorNotAvailable))
+		}
+	}
+	resp.ProducerId, resp.ProducerEpoch, resp.ErrorCode = h.txn.InitProducerID(
+		body.TransactionalId, body.TransactionTimeoutMs, body.ProducerId, body.ProducerEpoch)
+	resp.ErrorCode = fencedError(resp.ErrorCode, req.ApiVersion, 4)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// initProducerIdErrorResponse builds a failed InitProducerId response
+func (h *RequestHandler) initProducerIdErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.InitProducerIdResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	return protocol.NewResponse(req, resp)
+}
+
+// fencedError returns errorCode, except that PRODUCER_FENCED becomes
+// INVALID_PRODUCER_EPOCH for request versions before firstVersion, which
+// predate it (KIP-588)
+func fencedError(errorCode, version, firstVersion int16) int16 {
+	if errorCode == protocol.ErrorProducerFenced && version < firstVersion {
+		return protocol.ErrorInvalidProducerEpoch
+	}
+	return errorCode
+}
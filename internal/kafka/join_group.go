@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleJoinGroupRequest handles JOIN_GROUP requests. The response waits,
+// blocking the connection like Kafka does, until the group's rebalance
+// has gathered its members.
+func (h *RequestHandler) handleJoinGroupRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.JoinGroupRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode JoinGroup request: %w", err)
+	}
+
+	resp := <-h.groups.JoinGroup(groupRequestContext(conn, req), body)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// groupRequestContext describes the client that sent a request to the
+// group coordinator
+func groupRequestContext(conn net.Conn, req *protocol.Request) group.RequestContext {
+	ctx := group.RequestContext{APIVersion: req.ApiVersion}
+	if req.ClientID != nil {
+		ctx.ClientID = *req.ClientID
+	}
+	// Kafka reports client hosts as "/<address>"
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		ctx.ClientHost = "/" + addr.IP.String()
+	}
+	return ctx
+}
+
+// joinGroupErrorResponse builds a failed JoinGroup response
+func (h *RequestHandler) joinGroupErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.JoinGroupRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.JoinGroupResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	resp.MemberId = body.MemberId
+	return protocol.NewResponse(req, resp)
+}
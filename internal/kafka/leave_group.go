@@ -0,0 +1,44 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleLeaveGroupRequest handles LEAVE_GROUP requests
+func (h *RequestHandler) handleLeaveGroupRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.LeaveGroupRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode LeaveGroup request: %w", err)
+	}
+
+	resp := &protocol.LeaveGroupResponse{}
+	resp.Default()
+	resp.ErrorCode, resp.Members = h.groups.LeaveGroup(body.GroupId, leaveGroupMembers(body, req.ApiVersion))
+
+	// Before v3 a request removes a single member, whose result is the
+	// response's error
+	if req.ApiVersion < 3 && resp.ErrorCode == protocol.ErrorNone && len(resp.Members) > 0 {
+		resp.ErrorCode = resp.Members[0].ErrorCode
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// leaveGroupMembers returns the members a request removes: a batch from
+// v3, a single member ID before
+func leaveGroupMembers(body *protocol.LeaveGroupRequest, version int16) []protocol.LeaveGroupRequestMemberIdentity {
+	if version >= 3 {
+		return body.Members
+	}
+	return []protocol.LeaveGroupRequestMemberIdentity{{MemberId: body.MemberId}}
+}
+
+// leaveGroupErrorResponse builds a failed LeaveGroup response
+func (h *RequestHandler) leaveGroupErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.LeaveGroupResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	return protocol.NewResponse(req, resp)
+}
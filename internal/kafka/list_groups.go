@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleListGroupsRequest handles LIST_GROUPS requests
+func (h *RequestHandler) handleListGroupsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.ListGroupsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode ListGroups request: %w", err)
+	}
+
+	resp := &protocol.ListGroupsResponse{}
+	resp.Default()
+	resp.Groups, resp.ErrorCode = h.groups.ListGroups(body.StatesFilter, body.TypesFilter)
+	if resp.Groups == nil {
+		resp.Groups = []protocol.ListGroupsResponseListedGroup{}
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// listGroupsErrorResponse builds a failed ListGroups response
+func (h *RequestHandler) listGroupsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.ListGroupsResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	resp.Groups = []protocol.ListGroupsResponseListedGroup{}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,156 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// Special ListOffsets timestamps, which ask for an offset rather than
+// searching by time
+const (
+	latestTimestamp        int64 = -1
+	earliestTimestamp      int64 = -2
+	maxTimestamp           int64 = -3 // v7+: the record with the largest timestamp
+	earliestLocalTimestamp int64 = -4 // v8+: the first offset kept locally
+	latestTieredTimestamp  int64 = -5 // v9+: the last offset in remote storage
+)
+
+// consumerReplicaID is the ReplicaId sent by consumers, as opposed to
+// followers and debugging tools
+const consumerReplicaID int32 = -1
+
+// handleListOffsetsRequest handles LIST_OFFSETS requests
+func (h *RequestHandler) handleListOffsetsRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.ListOffsetsRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode ListOffsets request: %w", err)
+	}
+
+	// A partition listed more than once cannot be answered unambiguously
+	counts := make(map[storage.TopicPartition]int)
+	for _, topic := range body.Topics {
+		for _, partition := range topic.Partitions {
+			counts[storage.TopicPartition{Topic: topic.Name, Partition: partition.PartitionIndex}]++
+		}
+	}
+
+	resp := &protocol.ListOffsetsResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		topicResp := protocol.ListOffsetsResponseListOffsetsTopicResponse{Name: topic.Name}
+		image := h.metadata.TopicByName(topic.Name)
+		for i := range topic.Partitions {
+			lp := &topic.Partitions[i]
+			var partitionResp protocol.ListOffsetsResponseListOffsetsPartitionResponse
+			switch {
+			case counts[storage.TopicPartition{Topic: topic.Name, Partition: lp.PartitionIndex}] > 1:
+				partitionResp = listOffsetsError(lp.PartitionIndex, protocol.ErrorInvalidRequest)
+			case image == nil:
+				partitionResp = listOffsetsError(lp.PartitionIndex, protocol.ErrorUnknownTopic)
+			default:
+				partitionResp = h.listPartitionOffset(image, lp, body.ReplicaId, body.IsolationLevel)
+			}
+			topicResp.Partitions = append(topicResp.Partitions, partitionResp)
+		}
+		resp.Topics = append(resp.Topics, topicResp)
+	}
+
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// listPartitionOffset answers the ListOffsets query for one partition
+func (h *RequestHandler) listPartitionOffset(topic *metadata.Topic, lp *protocol.ListOffsetsRequestListOffsetsPartition, replicaID int32, isolationLevel int8) protocol.ListOffsetsResponseListOffsetsPartitionResponse {
+	partition, ok := topic.Partition(lp.PartitionIndex)
+	if !ok {
+		return listOffsetsError(lp.PartitionIndex, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	switch {
+	case lp.CurrentLeaderEpoch < 0:
+	case lp.CurrentLeaderEpoch > partition.LeaderEpoch:
+		return listOffsetsError(lp.PartitionIndex, protocol.ErrorUnknownLeaderEpoch)
+	case lp.CurrentLeaderEpoch < partition.LeaderEpoch:
+		return listOffsetsError(lp.PartitionIndex, protocol.ErrorFencedLeaderEpoch)
+	}
+
+	log, ok := h.logs.Get(topic.Name, lp.PartitionIndex)
+	if !ok {
+		return listOffsetsError(lp.PartitionIndex, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	// Consumers only get to see offsets they could fetch; replicas and
+	// debugging tools see the whole log
+	lastFetchableOffset := log.LogEndOffset()
+	if replicaID == consumerReplicaID {
+		lastFetchableOffset = log.HighWatermark()
+		if isolationLevel == protocol.ReadCommitted {
+			lastFetchableOffset = log.LastStableOffset()
+		}
+	}
+
+	resp := listOffsetsError(lp.PartitionIndex, protocol.ErrorNone)
+	var found storage.TimestampOffset
+	var err error
+	switch lp.Timestamp {
+	case latestTimestamp:
+		resp.Offset = lastFetchableOffset
+		resp.LeaderEpoch = partition.LeaderEpoch
+		return resp
+	case earliestTimestamp, earliestLocalTimestamp:
+		resp.Offset = log.LogStartOffset()
+		resp.LeaderEpoch = partition.LeaderEpoch
+		return resp
+	case latestTieredTimestamp:
+		// Nothing is ever tiered to remote storage
+		return resp
+	case maxTimestamp:
+		found, ok, err = log.MaxTimestamp()
+	default:
+		found, ok, err = log.FindOffsetByTimestamp(lp.Timestamp)
+	}
+	if err != nil {
+		h.logger.Error("Failed to search %s-%d by timestamp: %s", topic.Name, lp.PartitionIndex, err.Error())
+		return listOffsetsError(lp.PartitionIndex, protocol.ErrorKafkaStorageError)
+	}
+
+	if ok && found.Offset < lastFetchableOffset {
+		resp.Timestamp = found.Timestamp
+		resp.Offset = found.Offset
+		resp.LeaderEpoch = partition.LeaderEpoch
+	}
+	return resp
+}
+
+// listOffsetsError builds a partition response carrying errorCode and no offset
+func listOffsetsError(index int32, errorCode int16) protocol.ListOffsetsResponseListOffsetsPartitionResponse {
+	return protocol.ListOffsetsResponseListOffsetsPartitionResponse{
+		PartitionIndex: index,
+		ErrorCode:      errorCode,
+		Timestamp:      -1,
+		Offset:         -1,
+		LeaderEpoch:    -1,
+	}
+}
+
+// listOffsetsErrorResponse builds a ListOffsets response that fails every
+// requested partition with errorCode
+func (h *RequestHandler) listOffsetsErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.ListOffsetsRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.ListOffsetsResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		topicResp := protocol.ListOffsetsResponseListOffsetsTopicResponse{Name: topic.Name}
+		for _, partition := range topic.Partitions {
+			topicResp.Partitions = append(topicResp.Partitions, listOffsetsError(partition.PartitionIndex, errorCode))
+		}
+		resp.Topics = append(resp.Topics, topicResp)
+	}
+	return protocol.NewResponse(req, resp)
+}
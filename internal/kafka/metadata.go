@@ -0,0 +1,166 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+)
+
+// clusterAuthorizedOperations is the ACL operation bitfield reported for
+// the cluster: CREATE, DESCRIBE, ALTER, CLUSTER_ACTION, DESCRIBE_CONFIGS,
+// ALTER_CONFIGS and IDEMPOTENT_WRITE, since the broker does not enforce ACLs
+const clusterAuthorizedOperations int32 = 0x000007f0
+
+// handleMetadataRequest handles METADATA requests
+func (h *RequestHandler) handleMetadataRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.MetadataRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode Metadata request: %w", err)
+	}
+
+	// Topic IDs can only be used to look topics up from v12
+	for _, topic := range body.Topics {
+		if req.ApiVersion < 12 && (topic.Name == nil || topic.TopicId != protocol.ZeroUUID) {
+			return h.sendResponse(conn, h.metadataErrorResponse(req, protocol.ErrorInvalidRequest))
+		}
+	}
+
+	resp := &protocol.MetadataResponse{}
+	resp.Default()
+	resp.Brokers = []protocol.MetadataResponseBroker{{
+		NodeId: h.config.NodeID,
+		Host:   h.config.Host,
+		Port:   h.config.Port,
+	}}
+	resp.ClusterId = &h.config.ClusterID
+	resp.ControllerId = h.config.NodeID
+	if body.IncludeClusterAuthorizedOperations {
+		resp.ClusterAuthorizedOperations = clusterAuthorizedOperations
+	}
+
+	// A null topic list, or an empty one in v0, asks for every topic
+	if body.Topics == nil || (req.ApiVersion == 0 && len(body.Topics) == 0) {
+		for _, topic := range h.metadata.Topics() {
+			resp.Topics = append(resp.Topics, h.metadataTopic(topic, body.IncludeTopicAuthorizedOperations))
+		}
+		return h.sendResponse(conn, protocol.NewResponse(req, resp))
+	}
+
+	// Before v4 clients could not opt out of auto-creation
+	autoCreate := h.config.AutoCreateTopics && (req.ApiVersion < 4 || body.AllowAutoTopicCreation)
+
+	seenNames := make(map[string]bool, len(body.Topics))
+	seenIDs := make(map[protocol.UUID]bool)
+	for _, requested := range body.Topics {
+		if requested.Name == nil {
+			if seenIDs[requested.TopicId] {
+				continue
+			}
+			seenIDs[requested.TopicId] = true
+
+			topic := h.metadata.TopicByID(requested.TopicId)
+			if topic == nil {
+				resp.Topics = append(resp.Topics, metadataTopicError(nil, requested.TopicId, protocol.ErrorUnknownTopicID))
+				continue
+			}
+			resp.Topics = append(resp.Topics, h.metadataTopic(topic, body.IncludeTopicAuthorizedOperations))
+			continue
+		}
+
+		name := *requested.Name
+		if seenNames[name] {
+			continue
+		}
+		seenNames[name] = true
+
+		topic := h.metadata.TopicByName(name)
+		if topic == nil && autoCreate {
+			t, err := h.planTopic(name, -1, -1, nil, nil)
+			if err == nil {
+				topic, err = h.createTopic(t)
+			}
+			if err != nil && err.code == protocol.ErrorTopicAlreadyExists {
+				// Another request created it in the meantime
+				topic, err = h.metadata.TopicByName(name), nil
+			}
+			if err != nil {
+				resp.Topics = append(resp.Topics, metadataTopicError(&name, protocol.ZeroUUID, err.code))
+				continue
+			}
+		}
+		if topic == nil {
+			resp.Topics = append(resp.Topics, metadataTopicError(&name, protocol.ZeroUUID, protocol.ErrorUnknownTopic))
+			continue
+		}
+		resp.Topics = append(resp.Topics, h.metadataTopic(topic, body.IncludeTopicAuthorizedOperations))
+	}
+
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// metadataTopic converts a topic of the metadata image into its Metadata
+// representation
+func (h *RequestHandler) metadataTopic(topic *metadata.Topic, includeAuthorizedOperations bool) protocol.MetadataResponseTopic {
+	entry := protocol.MetadataResponseTopic{
+		ErrorCode:                 protocol.ErrorNone,
+		Name:                      &topic.Name,
+		TopicId:                   topic.ID,
+		IsInternal:                topic.IsInternal(),
+		Partitions:                make([]protocol.MetadataResponsePartition, 0, len(topic.Partitions)),
+		TopicAuthorizedOperations: -2147483648,
+	}
+	if includeAuthorizedOperations {
+		entry.TopicAuthorizedOperations = topicAuthorizedOperations
+	}
+
+	for i := range topic.Partitions {
+		p := &topic.Partitions[i]
+		errorCode := protocol.ErrorNone
+		if p.Leader == metadata.NoLeader {
+			errorCode = protocol.ErrorLeaderNotAvailable
+		}
+		entry.Partitions = append(entry.Partitions, protocol.MetadataResponsePartition{
+			ErrorCode:       errorCode,
+			PartitionIndex:  p.Index,
+			LeaderId:        p.Leader,
+			LeaderEpoch:     p.LeaderEpoch,
+			ReplicaNodes:    nonNilInt32s(p.Replicas),
+			IsrNodes:        nonNilInt32s(p.ISR),
+			OfflineReplicas: []int32{},
+		})
+	}
+	return entry
+}
+
+// metadataTopicError builds a failed topic entry, identified by name or,
+// for lookups by ID, by topic ID alone
+func metadataTopicError(name *string, id protocol.UUID, errorCode int16) protocol.MetadataResponseTopic {
+	return protocol.MetadataResponseTopic{
+		ErrorCode:                 errorCode,
+		Name:                      name,
+		TopicId:                   id,
+		Partitions:                []protocol.MetadataResponsePartition{},
+		TopicAuthorizedOperations: -2147483648,
+	}
+}
+
+// metadataErrorResponse builds a Metadata response that fails every
+// requested topic with errorCode
+func (h *RequestHandler) metadataErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.MetadataRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.MetadataResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		name := topic.Name
+		if name == nil && req.ApiVersion < 12 {
+			name = new(string)
+		}
+		resp.Topics = append(resp.Topics, metadataTopicError(name, topic.TopicId, errorCode))
+	}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,102 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// handleOffsetCommitRequest handles OFFSET_COMMIT requests. Partitions of
+// unknown topics are rejected here; the rest are committed by the group
+// coordinator. RetentionTimeMs is ignored, as in Kafka from v5 on:
+// committed offsets only go away with their group.
+func (h *RequestHandler) handleOffsetCommitRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.OffsetCommitRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode OffsetCommit request: %w", err)
+	}
+
+	if err := h.ensureOffsetsTopic(); err != nil {
+		h.logger.Error("Failed to create %s: %s", group.OffsetsTopic, err.Error())
+		return h.sendResponse(conn, protocol.NewResponse(req, offsetCommitError(body, protocol.ErrorCoordinatorNotAvailable)))
+	}
+
+	// Commit the known partitions, remembering why the others failed
+	known := *body
+	known.Topics = nil
+	failed := make(map[storage.TopicPartition]int16)
+	for _, topic := range body.Topics {
+		image := h.metadata.TopicByName(topic.Name)
+		valid := protocol.OffsetCommitRequestTopic{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			if image == nil {
+				failed[storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}] = protocol.ErrorUnknownTopic
+				continue
+			}
+			if _, ok := image.Partition(p.PartitionIndex); !ok {
+				failed[storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}] = protocol.ErrorUnknownTopicOrPartition
+				continue
+			}
+			valid.Partitions = append(valid.Partitions, p)
+		}
+		if len(valid.Partitions) > 0 {
+			known.Topics = append(known.Topics, valid)
+		}
+	}
+	committed := make(map[storage.TopicPartition]int16)
+	for _, topic := range h.groups.CommitOffsets(groupRequestContext(conn, req), &known).Topics {
+		for _, p := range topic.Partitions {
+			committed[storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}] = p.ErrorCode
+		}
+	}
+
+	// Answer in the order of the request
+	resp := &protocol.OffsetCommitResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		result := protocol.OffsetCommitResponseTopic{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			tp := storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}
+			errorCode, ok := failed[tp]
+			if !ok {
+				errorCode = committed[tp]
+			}
+			result.Partitions = append(result.Partitions, protocol.OffsetCommitResponsePartition{
+				PartitionIndex: p.PartitionIndex,
+				ErrorCode:      errorCode,
+			})
+		}
+		resp.Topics = append(resp.Topics, result)
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// offsetCommitError builds an OffsetCommit response that fails every
+// requested partition with errorCode
+func offsetCommitError(body *protocol.OffsetCommitRequest, errorCode int16) *protocol.OffsetCommitResponse {
+	resp := &protocol.OffsetCommitResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		result := protocol.OffsetCommitResponseTopic{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			result.Partitions = append(result.Partitions, protocol.OffsetCommitResponsePartition{
+				PartitionIndex: p.PartitionIndex,
+				ErrorCode:      errorCode,
+			})
+		}
+		resp.Topics = append(resp.Topics, result)
+	}
+	return resp
+}
+
+// offsetCommitErrorResponse builds an OffsetCommit response that fails
+// every requested partition with errorCode
+func (h *RequestHandler) offsetCommitErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.OffsetCommitRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+	return protocol.NewResponse(req, offsetCommitError(body, errorCode))
+}
@@ -0,0 +1,93 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleOffsetFetchRequest handles OFFSET_FETCH requests. Requests before
+// v8 name a single group; they are answered through the same path as the
+// batched requests of later versions. With RequireStable, partitions whose
+// offsets are pending on a transaction fail with UNSTABLE_OFFSET_COMMIT
+// rather than the request waiting for the transaction.
+func (h *RequestHandler) handleOffsetFetchRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.OffsetFetchRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode OffsetFetch request: %w", err)
+	}
+
+	resp := &protocol.OffsetFetchResponse{}
+	resp.Default()
+	for _, g := range offsetFetchGroups(body, req.ApiVersion) {
+		resp.Groups = append(resp.Groups, h.groups.FetchOffsets(g, body.RequireStable))
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, offsetFetchResponse(resp, req.ApiVersion)))
+}
+
+// offsetFetchGroups returns the groups a request fetches offsets for: a
+// batch from v8, a single group before
+func offsetFetchGroups(body *protocol.OffsetFetchRequest, version int16) []protocol.OffsetFetchRequestGroup {
+	if version >= 8 {
+		return body.Groups
+	}
+	g := protocol.OffsetFetchRequestGroup{GroupId: body.GroupId, MemberEpoch: -1}
+	if body.Topics != nil {
+		g.Topics = make([]protocol.OffsetFetchRequestTopics, 0, len(body.Topics))
+		for _, topic := range body.Topics {
+			g.Topics = append(g.Topics, protocol.OffsetFetchRequestTopics{Name: topic.Name, PartitionIndexes: topic.PartitionIndexes})
+		}
+	}
+	return []protocol.OffsetFetchRequestGroup{g}
+}
+
+// offsetFetchResponse moves the single group of a pre-v8 response into the
+// top-level fields those versions use. Before v2 there is no top-level
+// error, so a group error is reported on every partition instead.
+func offsetFetchResponse(resp *protocol.OffsetFetchResponse, version int16) *protocol.OffsetFetchResponse {
+	if version >= 8 || len(resp.Groups) == 0 {
+		return resp
+	}
+	g := resp.Groups[0]
+	resp.ErrorCode = g.ErrorCode
+	resp.Topics = []protocol.OffsetFetchResponseTopic{}
+	for _, topic := range g.Topics {
+		result := protocol.OffsetFetchResponseTopic{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			errorCode := p.ErrorCode
+			if version < 2 && g.ErrorCode != protocol.ErrorNone {
+				errorCode = g.ErrorCode
+			}
+			result.Partitions = append(result.Partitions, protocol.OffsetFetchResponsePartition{
+				PartitionIndex:       p.PartitionIndex,
+				CommittedOffset:      p.CommittedOffset,
+				CommittedLeaderEpoch: p.CommittedLeaderEpoch,
+				Metadata:             p.Metadata,
+				ErrorCode:            errorCode,
+			})
+		}
+		resp.Topics = append(resp.Topics, result)
+	}
+	resp.Groups = nil
+	return resp
+}
+
+// offsetFetchErrorResponse builds an OffsetFetch response that fails every
+// requested group with errorCode
+func (h *RequestHandler) offsetFetchErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.OffsetFetchRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.OffsetFetchResponse{}
+	resp.Default()
+	for _, g := range offsetFetchGroups(body, req.ApiVersion) {
+		resp.Groups = append(resp.Groups, protocol.OffsetFetchResponseGroup{
+			GroupId:   g.GroupId,
+			Topics:    []protocol.OffsetFetchResponseTopics{},
+			ErrorCode: errorCode,
+		})
+	}
+	return protocol.NewResponse(req, offsetFetchResponse(resp, req.ApiVersion))
+}
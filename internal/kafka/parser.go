@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
 	"net"
 
@@ -9,6 +10,15 @@ import (
 	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
 )
 
+// maxRequestSize bounds the size of a single request frame, matching
+// Kafka's default socket.request.max.bytes
+const maxRequestSize = 100 * 1024 * 1024
+
+// minRequestSize is the size of the smallest request frame: a v1 header
+// with a null client ID and an empty body. Every request header has at
+// least v1 fields.
+const minRequestSize = 10
+
 // MessageParser reads and parses Kafka protocol messages from a connection
 type MessageParser struct {
 	logger *logger.Logger
@@ -29,27 +39,34 @@ func (p *MessageParser) ReadRequest(conn net.Conn) (*protocol.Request, error) {
 	if err := binary.Read(conn, binary.BigEndian, &request.Length); err != nil {
 		return nil, err
 	}
-
-	// Read API key
-	if err := binary.Read(conn, binary.BigEndian, &request.ApiKey); err != nil {
-		return nil, err
+	if request.Length < minRequestSize || request.Length > maxRequestSize {
+		return nil, fmt.Errorf("invalid request length %d", request.Length)
 	}
 
-	// Read API version
-	if err := binary.Read(conn, binary.BigEndian, &request.ApiVersion); err != nil {
+	// Read the whole frame
+	frame := make([]byte, request.Length)
+	if _, err := io.ReadFull(conn, frame); err != nil {
 		return nil, err
 	}
 
-	// Read correlation ID
-	if err := binary.Read(conn, binary.BigEndian, &request.CorrelationID); err != nil {
-		return nil, err
-	}
+	// The API key and version select the header version that follows
+	request.ApiKey = int16(binary.BigEndian.Uint16(frame[0:2]))
+	request.ApiVersion = int16(binary.BigEndian.Uint16(frame[2:4]))
+	request.HeaderVersion = protocol.RequestHeaderVersion(request.ApiKey, request.ApiVersion)
 
-	// Read the rest of the request payload
-	request.Payload = make([]byte, request.Length-8) // 8 bytes for ApiKey, ApiVersion, and CorrelationID
-	if _, err := io.ReadFull(conn, request.Payload); err != nil {
-		return nil, err
+	// Decode the header: correlation ID, nullable client ID (v1+) and
+	// tagged fields (v2+)
+	header := &protocol.RequestHeader{}
+	d := protocol.NewDecoder(frame)
+	if err := header.Decode(d, request.HeaderVersion); err != nil {
+		return nil, fmt.Errorf("failed to decode request header v%d: %w", request.HeaderVersion, err)
 	}
+	request.CorrelationID = header.CorrelationId
+	request.ClientID = header.ClientId
+	request.TaggedFields = header.UnknownTaggedFields
+
+	// The body starts right after the header
+	request.Payload = d.Rest()
 
 	p.logRequest(request)
 
@@ -58,6 +75,10 @@ func (p *MessageParser) ReadRequest(conn net.Conn) (*protocol.Request, error) {
 
 // logRequest logs the details of a Kafka protocol request
 func (p *MessageParser) logRequest(request *protocol.Request) {
-	p.logger.Debug("Request details: Length=%d, ApiKey=%d, ApiVersion=%d, CorrelationID=%d",
-		request.Length, request.ApiKey, request.ApiVersion, request.CorrelationID)
+	clientID := "<null>"
+	if request.ClientID != nil {
+		clientID = *request.ClientID
+	}
+	p.logger.Debug("Request details: Length=%d, ApiKey=%d (%s), ApiVersion=%d, CorrelationID=%d, ClientID=%s",
+		request.Length, request.ApiKey, protocol.APIName(request.ApiKey), request.ApiVersion, request.CorrelationID, clientID)
 }
@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// readFrame has ReadRequest read data as sent by a client
+func readFrame(data []byte) (*protocol.Request, error) {
+	server, client := net.Pipe()
+	defer server.Close()
+	go func() {
+		client.Write(data)
+		client.Close()
+	}()
+	return NewMessageParser(logger.New(logger.ERROR)).ReadRequest(server)
+}
+
+// frame prefixes a request header of version and body with their size
+func frame(header *protocol.RequestHeader, version int16, body []byte) []byte {
+	e := protocol.NewEncoder(0)
+	e.PutInt32(0)
+	header.Encode(e, version)
+	e.PutRaw(body)
+	data := e.Bytes()
+	binary.BigEndian.PutUint32(data, uint32(len(data)-4))
+	return data
+}
+
+func TestReadRequest(t *testing.T) {
+	clientID := "client"
+	body := []byte{1, 2, 3}
+	tests := []struct {
+		name   string
+		header protocol.RequestHeader
+		// version is the header version the API version calls for
+		version int16
+	}{
+		{
+			name:    "v1 header",
+			header:  protocol.RequestHeader{RequestApiKey: protocol.ApiVersionsKey, RequestApiVersion: 2, CorrelationId: 7, ClientId: &clientID},
+			version: 1,
+		},
+		{
+			name:    "v1 header with a null client ID",
+			header:  protocol.RequestHeader{RequestApiKey: protocol.ApiVersionsKey, RequestApiVersion: 2, CorrelationId: 7},
+			version: 1,
+		},
+		{
+			name: "v2 header",
+			header: protocol.RequestHeader{
+				RequestApiKey: protocol.ApiVersionsKey, RequestApiVersion: 3, CorrelationId: 7, ClientId: &clientID,
+				UnknownTaggedFields: []protocol.TaggedField{{Tag: 5, Data: []byte{9}}},
+			},
+			version: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := readFrame(frame(&tt.header, tt.version, body))
+			if err != nil {
+				t.Fatalf("ReadRequest: %v", err)
+			}
+			if req.ApiKey != tt.header.RequestApiKey || req.ApiVersion != tt.header.RequestApiVersion || req.CorrelationID != tt.header.CorrelationId {
+				t.Errorf("read key %d version %d correlation ID %d", req.ApiKey, req.ApiVersion, req.CorrelationID)
+			}
+			if req.HeaderVersion != tt.version {
+				t.Errorf("header version %d, want %d", req.HeaderVersion, tt.version)
+			}
+			if !reflect.DeepEqual(req.ClientID, tt.header.ClientId) {
+				t.Errorf("client ID %v, want %v", req.ClientID, tt.header.ClientId)
+			}
+			if !reflect.DeepEqual(req.TaggedFields, tt.header.UnknownTaggedFields) {
+				t.Errorf("tagged fields %+v, want %+v", req.TaggedFields, tt.header.UnknownTaggedFields)
+			}
+			if !bytes.Equal(req.Payload, body) {
+				t.Errorf("payload %x, want %x", req.Payload, body)
+			}
+		})
+	}
+}
+
+func TestReadRequestInvalid(t *testing.T) {
+	// The smallest frame is an empty request with a v1 header
+	smallest := frame(&protocol.RequestHeader{RequestApiKey: protocol.ApiVersionsKey}, 1, nil)
+	if _, err := readFrame(smallest); err != nil {
+		t.Errorf("smallest frame: %v", err)
+	}
+	// shortened returns the smallest frame cut to size bytes after its
+	// length, which says so
+	shortened := func(size int) []byte {
+		data := bytes.Clone(smallest[:4+size])
+		binary.BigEndian.PutUint32(data, uint32(size))
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		// lengthErr is set for frames refused for their length alone
+		lengthErr bool
+	}{
+		// Too short for the client ID length of a v1 header
+		{"length 8", shortened(8), true},
+		{"length 9", shortened(9), true},
+		{"negative length", binary.BigEndian.AppendUint32(nil, 0xffffffff), true},
+		{"length over the maximum", binary.BigEndian.AppendUint32(nil, maxRequestSize+1), true},
+		{"truncated frame", smallest[:len(smallest)-1], false},
+		{"client ID past the frame", func() []byte {
+			data := bytes.Clone(smallest)
+			binary.BigEndian.PutUint16(data[len(data)-2:], 5)
+			return data
+		}(), false},
+	}
+	for _, tt := range tests {
+		_, err := readFrame(tt.data)
+		switch {
+		case err == nil:
+			t.Errorf("%s: no error", tt.name)
+		case tt.lengthErr && !strings.Contains(err.Error(), "invalid request length"):
+			t.Errorf("%s: error %q, want an invalid request length", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/compression"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// Produce versions that change what producers may write
+const (
+	// produceMagicV2MinVersion is the first Produce version limited to
+	// message format v2; older producers may send v0 and v1 message sets
+	produceMagicV2MinVersion int16 = 3
+
+	// produceZstdMinVersion is the first Produce version that may carry
+	// zstd batches
+	produceZstdMinVersion int16 = 7
+)
+
+// handleProduceRequest handles PRODUCE requests
+func (h *RequestHandler) handleProduceRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.ProduceRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode Produce request: %w", err)
+	}
+
+	// The broker is the only replica, so acks=1 and acks=-1 are both
+	// satisfied once the leader has appended the records
+	validAcks := body.Acks == 0 || body.Acks == 1 || body.Acks == -1
+
+	resp := &protocol.ProduceResponse{}
+	resp.Default()
+	for _, topic := range body.TopicData {
+		topicResp := protocol.ProduceResponseTopicProduceResponse{Name: topic.Name}
+		for i := range topic.PartitionData {
+			var partResp protocol.ProduceResponsePartitionProduceResponse
+			if validAcks {
+				partResp = h.produceToPartition(req.ApiVersion, body.TransactionalId, topic.Name, &topic.PartitionData[i])
+			} else {
+				partResp = produceError(topic.PartitionData[i].Index, protocol.ErrorInvalidRequiredAcks)
+			}
+			topicResp.PartitionResponses = append(topicResp.PartitionResponses, partResp)
+		}
+		resp.Responses = append(resp.Responses, topicResp)
+	}
+
+	// With acks=0 the producer neither waits for nor reads a response
+	if body.Acks == 0 {
+		return nil
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// produceToPartition validates the record batches for one partition and
+// appends them to its log, recompressed if the topic's compression.type
+// asks for it. Transactional batches must come from a transactional
+// producer and go to a partition already added to its open transaction.
+func (h *RequestHandler) produceToPartition(version int16, transactionalID *string, topicName string, data *protocol.ProduceRequestPartitionProduceData) protocol.ProduceResponsePartitionProduceResponse {
+	topic := h.metadata.TopicByName(topicName)
+	if topic == nil {
+		return produceError(data.Index, protocol.ErrorUnknownTopic)
+	}
+	if _, ok := topic.Partition(data.Index); !ok {
+		return produceError(data.Index, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	batches, errorCode, message := h.validateBatches(topicName, data.Records, version)
+	if errorCode != protocol.ErrorNone {
+		h.logger.Info("Rejecting produce to %s-%d: %s", topicName, data.Index, message)
+		resp := produceError(data.Index, errorCode)
+		resp.ErrorMessage = &message
+		return resp
+	}
+	// Any batch may be transactional, and each must be part of its
+	// producer's open transaction; batches of the same producer and epoch
+	// are verified once
+	var verified *record.Batch
+	for _, b := range batches {
+		if !b.IsTransactional() {
+			continue
+		}
+		if transactionalID == nil {
+			message := "transactional batches require a transactional ID"
+			h.logger.Info("Rejecting produce to %s-%d: %s", topicName, data.Index, message)
+			resp := produceError(data.Index, protocol.ErrorInvalidRecord)
+			resp.ErrorMessage = &message
+			return resp
+		}
+		if verified != nil && b.ProducerID == verified.ProducerID && b.ProducerEpoch == verified.ProducerEpoch {
+			continue
+		}
+		if errorCode := h.verifyTransaction(*transactionalID, topicName, data.Index, b); errorCode != protocol.ErrorNone {
+			return produceError(data.Index, errorCode)
+		}
+		verified = b
+	}
+
+	recompressed, err := h.recompressBatches(topicName, batches)
+	if err != nil {
+		h.logger.Error("Failed to recompress records for %s-%d: %s", topicName, data.Index, err.Error())
+		return produceError(data.Index, protocol.ErrorUnknownServerError)
+	}
+
+	// Topics using log append time get the broker's clock instead of the
+	// producer's timestamps
+	logAppendTime := int64(-1)
+	if h.topicConfig(topicName, MessageTimestampTypeConfig) == "LogAppendTime" {
+		logAppendTime = time.Now().UnixMilli()
+		for _, b := range batches {
+			b.SetLogAppendTime(logAppendTime)
+		}
+	}
+
+	// Batches are stamped in place, so unless they were recompressed or
+	// converted from a legacy message set the request's records are what
+	// gets appended
+	records := data.Records
+	if recompressed || version < produceMagicV2MinVersion {
+		records = nil
+		for _, b := range batches {
+			records = append(records, b.Data...)
+		}
+	}
+
+	// A log missing from the manager belongs to a topic deleted meanwhile
+	log, ok := h.logs.Get(topicName, data.Index)
+	if !ok {
+		return produceError(data.Index, protocol.ErrorUnknownTopicOrPartition)
+	}
+	info, err := log.Append(records)
+	switch {
+	case errors.Is(err, storage.ErrOutOfOrderSequence), errors.Is(err, storage.ErrInvalidProducerEpoch):
+		h.logger.Info("Rejecting produce to %s-%d: %s", topicName, data.Index, err.Error())
+		errorCode := protocol.ErrorOutOfOrderSequenceNumber
+		if errors.Is(err, storage.ErrInvalidProducerEpoch) {
+			errorCode = protocol.ErrorInvalidProducerEpoch
+		}
+		resp := produceError(data.Index, errorCode)
+		message := err.Error()
+		resp.ErrorMessage = &message
+		return resp
+	case err != nil:
+		h.logger.Error("Failed to append to %s-%d: %s", topicName, data.Index, err.Error())
+		return produceError(data.Index, protocol.ErrorKafkaStorageError)
+	}
+	h.fetches.notify(storage.TopicPartition{Topic: topicName, Partition: data.Index})
+
+	resp := produceError(data.Index, protocol.ErrorNone)
+	resp.BaseOffset = info.FirstOffset
+	resp.LogAppendTimeMs = logAppendTime
+	resp.LogStartOffset = log.LogStartOffset()
+	return resp
+}
+
+// verifyTransaction checks with the transaction coordinator that a
+// partition is in the open transaction of the producer that wrote batch.
+// Produce responses predate PRODUCER_FENCED, so a fenced producer gets
+// INVALID_PRODUCER_EPOCH.
+func (h *RequestHandler) verifyTransaction(transactionalID, topic string, partition int32, batch *record.Batch) int16 {
+	tp := storage.TopicPartition{Topic: topic, Partition: partition}
+	results, errorCode := h.txn.VerifyPartitions(transactionalID, batch.ProducerID, batch.ProducerEpoch, []storage.TopicPartition{tp})
+	if errorCode == protocol.ErrorNone {
+		errorCode = results[tp]
+	}
+	if errorCode == protocol.ErrorProducerFenced {
+		errorCode = protocol.ErrorInvalidProducerEpoch
+	}
+	if errorCode != protocol.ErrorNone {
+		h.logger.Info("Rejecting transactional produce to %s-%d from %s: error %d", topic, partition, transactionalID, errorCode)
+	}
+	return errorCode
+}
+
+// validateBatches parses and checks the record batches of a partition,
+// decompressing them to check their records. Legacy message sets from old
+// producers are converted to a single batch first. On failure it returns
+// the error code and a message for the producer.
+func (h *RequestHandler) validateBatches(topic string, records []byte, version int16) ([]*record.Batch, int16, string) {
+	if len(records) == 0 {
+		return nil, protocol.ErrorInvalidRecord, "no record batches"
+	}
+
+	if magic, err := record.PeekMagic(records); err == nil && magic < record.MagicV2 && version < produceMagicV2MinVersion {
+		b, err := record.UpConvert(records, maxConversionBytes)
+		switch {
+		case errors.Is(err, record.ErrTooLarge):
+			return nil, protocol.ErrorMessageTooLarge, fmt.Sprintf("message set decompresses to more than %d bytes", maxConversionBytes)
+		case errors.Is(err, compression.ErrUnsupportedCodec):
+			return nil, protocol.ErrorUnsupportedCompressionType, err.Error()
+		case err != nil:
+			return nil, protocol.ErrorCorruptMessage, err.Error()
+		}
+		records = b.Data
+	}
+
+	maxBytes := h.topicConfigInt(topic, MaxMessageBytesConfig)
+	var batches []*record.Batch
+	for rest := records; len(rest) > 0; {
+		b, err := record.ParseBatch(rest)
+		switch {
+		case errors.Is(err, record.ErrUnsupportedMagic):
+			return nil, protocol.ErrorInvalidRecord, "produce requests must use message format v2"
+		case err != nil:
+			return nil, protocol.ErrorCorruptMessage, err.Error()
+		}
+		rest = rest[b.Size():]
+
+		if err := b.VerifyCRC(); err != nil {
+			return nil, protocol.ErrorCorruptMessage, err.Error()
+		}
+		if int64(b.Size()) > maxBytes {
+			return nil, protocol.ErrorMessageTooLarge, fmt.Sprintf("batch of %d bytes exceeds %s %d", b.Size(), MaxMessageBytesConfig, maxBytes)
+		}
+		if b.Compression() > record.CompressionZstd {
+			return nil, protocol.ErrorUnsupportedCompressionType, fmt.Sprintf("unknown compression codec %d", b.Compression())
+		}
+		// Clients that may not read zstd are not allowed to write it
+		if b.Compression() == record.CompressionZstd && version < produceZstdMinVersion {
+			return nil, protocol.ErrorUnsupportedCompressionType, fmt.Sprintf("zstd requires produce version %d or later", produceZstdMinVersion)
+		}
+		if b.IsControl() {
+			return nil, protocol.ErrorInvalidRecord, "producers may not write control batches"
+		}
+		if b.NumRecords <= 0 || b.LastOffsetDelta != b.NumRecords-1 {
+			return nil, protocol.ErrorInvalidRecord, fmt.Sprintf("batch has %d records but a last offset delta of %d", b.NumRecords, b.LastOffsetDelta)
+		}
+		batchRecords, err := b.Records()
+		if err != nil {
+			return nil, protocol.ErrorCorruptMessage, err.Error()
+		}
+		// Offsets are assigned from the deltas, which must count up from 0
+		for i := range batchRecords {
+			if batchRecords[i].OffsetDelta != int32(i) {
+				return nil, protocol.ErrorInvalidRecord, fmt.Sprintf("record %d of the batch has an offset delta of %d", i, batchRecords[i].OffsetDelta)
+			}
+		}
+		batches = append(batches, b)
+	}
+	return batches, protocol.ErrorNone, ""
+}
+
+// recompressBatches recompresses the batches that do not use the topic's
+// compression.type, unless it is "producer", which keeps whatever the
+// producer sent. It reports whether any batch changed; those that did no
+// longer alias the request.
+func (h *RequestHandler) recompressBatches(topic string, batches []*record.Batch) (bool, error) {
+	target := h.topicConfig(topic, CompressionTypeConfig)
+	if target == "producer" {
+		return false, nil
+	}
+	codec, err := compression.ParseCodec(target)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for i, b := range batches {
+		if b.Compression() == codec {
+			continue
+		}
+		if batches[i], err = b.Recompress(codec); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// produceError builds a partition response carrying errorCode
+func produceError(index int32, errorCode int16) protocol.ProduceResponsePartitionProduceResponse {
+	resp := protocol.ProduceResponsePartitionProduceResponse{}
+	resp.Default()
+	resp.Index = index
+	resp.ErrorCode = errorCode
+	resp.BaseOffset = -1
+	return resp
+}
+
+// produceErrorResponse builds a Produce response that fails every
+// partition with errorCode
+func (h *RequestHandler) produceErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.ProduceRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.ProduceResponse{}
+	resp.Default()
+	for _, topic := range body.TopicData {
+		topicResp := protocol.ProduceResponseTopicProduceResponse{Name: topic.Name}
+		for _, partition := range topic.PartitionData {
+			topicResp.PartitionResponses = append(topicResp.PartitionResponses, produceError(partition.Index, errorCode))
+		}
+		resp.Responses = append(resp.Responses, topicResp)
+	}
+	return protocol.NewResponse(req, resp)
+}
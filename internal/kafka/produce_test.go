@@ -0,0 +1,207 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/txn"
+)
+
+// testRecords encodes a batch of one record per value
+func testRecords(values ...string) []byte {
+	records := make([]record.Record, len(values))
+	for i, v := range values {
+		records[i].Value = []byte(v)
+	}
+	now := time.Now().UnixMilli()
+	return record.EncodeBatch(record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		BaseTimestamp:        now,
+		MaxTimestamp:         now,
+		ProducerID:           record.NoProducerID,
+		ProducerEpoch:        record.NoProducerEpoch,
+		BaseSequence:         record.NoSequence,
+	}, records).Data
+}
+
+func TestDeletedLogIsNotRecreated(t *testing.T) {
+	h := newTestHandler(t)
+	log, _ := h.logs.Get(testTopic, 0)
+	dir := log.Dir()
+
+	// The topic is still in the metadata, as when a request looked it up
+	// just before DeleteTopics removed its logs
+	if err := h.logs.DeleteTopic(testTopic); err != nil {
+		t.Fatal(err)
+	}
+
+	data := &protocol.ProduceRequestPartitionProduceData{Index: 0, Records: testRecords("late")}
+	if resp := h.produceToPartition(9, nil, testTopic, data); resp.ErrorCode != protocol.ErrorUnknownTopicOrPartition {
+		t.Errorf("produce error = %d, want %d", resp.ErrorCode, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	topic := h.metadata.TopicByName(testTopic)
+	fp := &protocol.FetchRequestFetchPartition{CurrentLeaderEpoch: -1, PartitionMaxBytes: 1 << 20}
+	if resp := h.readPartition(fetchVersion, topic, fp, 0, 1<<20, true); resp.ErrorCode != protocol.ErrorUnknownTopicOrPartition {
+		t.Errorf("fetch error = %d, want %d", resp.ErrorCode, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	lp := &protocol.ListOffsetsRequestListOffsetsPartition{CurrentLeaderEpoch: -1, Timestamp: latestTimestamp}
+	if resp := h.listPartitionOffset(topic, lp, consumerReplicaID, 0); resp.ErrorCode != protocol.ErrorUnknownTopicOrPartition {
+		t.Errorf("list offsets error = %d, want %d", resp.ErrorCode, protocol.ErrorUnknownTopicOrPartition)
+	}
+
+	if _, ok := h.logs.Get(testTopic, 0); ok {
+		t.Errorf("log %s-0 recreated", testTopic)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("log dir %s recreated: %v", filepath.Base(dir), err)
+	}
+}
+
+// withOffsetDeltas re-encodes a batch built by testRecords with its
+// records' offset deltas replaced by deltas
+func withOffsetDeltas(t *testing.T, data []byte, deltas ...int32) []byte {
+	t.Helper()
+	b, err := record.ParseBatch(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := b.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := append([]byte(nil), data[:record.HeaderSize]...)
+	for i := range records {
+		records[i].OffsetDelta = deltas[i]
+		out = record.AppendRecord(out, &records[i])
+	}
+
+	// Fix up the batch length and the CRC-32C of everything after it
+	binary.BigEndian.PutUint32(out[8:], uint32(len(out)-record.LogOverhead))
+	binary.BigEndian.PutUint32(out[17:], crc32.Checksum(out[21:], crc32.MakeTable(crc32.Castagnoli)))
+	return out
+}
+
+func TestProduceOffsetDeltas(t *testing.T) {
+	tests := []struct {
+		name   string
+		deltas []int32
+		want   int16
+	}{
+		{"sequential", []int32{0, 1, 2}, protocol.ErrorNone},
+		{"gap", []int32{0, 2, 3}, protocol.ErrorInvalidRecord},
+		{"not from zero", []int32{1, 2, 3}, protocol.ErrorInvalidRecord},
+		{"repeated", []int32{0, 1, 1}, protocol.ErrorInvalidRecord},
+		{"out of order", []int32{0, 2, 1}, protocol.ErrorInvalidRecord},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			records := withOffsetDeltas(t, testRecords("a", "b", "c"), tt.deltas...)
+			data := &protocol.ProduceRequestPartitionProduceData{Index: 0, Records: records}
+			if resp := h.produceToPartition(9, nil, testTopic, data); resp.ErrorCode != tt.want {
+				t.Errorf("produce error = %d, want %d", resp.ErrorCode, tt.want)
+			}
+		})
+	}
+}
+
+// legacyRecords converts a batch of one record with value to a message
+// set in the v1 format, compressed with codec
+func legacyRecords(t *testing.T, codec record.Compression, value []byte) []byte {
+	t.Helper()
+	b, err := record.ParseBatch(testRecords(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, _ := b.Records()
+	records[0].Value = value
+	if b, err = b.WithRecords(records); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = b.Recompress(codec); err != nil {
+		t.Fatal(err)
+	}
+	data, err := record.DownConvert(b.Data, record.MagicV1, 0, len(value)+b.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestProduceLegacyConversionLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		value []byte
+		want  int16
+	}{
+		{"within limit", bytes.Repeat([]byte{'a'}, 1000), protocol.ErrorNone},
+		// The wrapper compresses well, but decompresses to more than the
+		// broker converts at a time
+		{"over limit", make([]byte, maxConversionBytes), protocol.ErrorMessageTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			data := &protocol.ProduceRequestPartitionProduceData{Index: 0, Records: legacyRecords(t, record.CompressionGzip, tt.value)}
+			if resp := h.produceToPartition(produceMagicV2MinVersion-1, nil, testTopic, data); resp.ErrorCode != tt.want {
+				t.Errorf("produce error = %d, want %d", resp.ErrorCode, tt.want)
+			}
+		})
+	}
+}
+
+// transactionalRecords encodes a batch of one record written in a
+// transaction of producerID
+func transactionalRecords(producerID int64) []byte {
+	now := time.Now().UnixMilli()
+	return record.EncodeBatch(record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		Attributes:           record.TransactionalAttribute,
+		BaseTimestamp:        now,
+		MaxTimestamp:         now,
+		ProducerID:           producerID,
+		ProducerEpoch:        0,
+		BaseSequence:         0,
+	}, []record.Record{{Value: []byte("value")}}).Data
+}
+
+func TestProduceTransactionalBatches(t *testing.T) {
+	transactionalID := "transactional"
+	tests := []struct {
+		name            string
+		transactionalID *string
+		records         []byte
+		want            int16
+	}{
+		{"without transactional ID", nil, transactionalRecords(5), protocol.ErrorInvalidRecord},
+		// Every batch is checked, not only the first
+		{"after a plain batch without transactional ID", nil, append(testRecords("a"), transactionalRecords(5)...), protocol.ErrorInvalidRecord},
+		{"after a plain batch outside the transaction", &transactionalID, append(testRecords("a"), transactionalRecords(5)...), protocol.ErrorInvalidProducerIDMapping},
+		{"plain batch with transactional ID", &transactionalID, testRecords("a"), protocol.ErrorNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			h.txn = txn.New(txn.Config{StateTopicPartitions: 1, MaxTimeout: time.Hour}, h.logs, nil, txn.NewProducerIDManager(h.metadata, 1), h.logger)
+			t.Cleanup(h.txn.Close)
+
+			data := &protocol.ProduceRequestPartitionProduceData{Index: 0, Records: tt.records}
+			if resp := h.produceToPartition(9, tt.transactionalID, testTopic, data); resp.ErrorCode != tt.want {
+				t.Errorf("produce error = %d, want %d", resp.ErrorCode, tt.want)
+			}
+			log, _ := h.logs.Get(testTopic, 0)
+			if tt.want != protocol.ErrorNone && log.LogEndOffset() != 0 {
+				t.Errorf("rejected produce appended up to offset %d", log.LogEndOffset())
+			}
+		})
+	}
+}
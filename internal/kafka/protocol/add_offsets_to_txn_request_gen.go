@@ -0,0 +1,71 @@
+// Code generated by protogen from messages/AddOffsetsToTxnRequest.json. DO NOT EDIT.
+
+package protocol
+
+// AddOffsetsToTxnRequest is the request for API key 25, versions 0-4.
+type AddOffsetsToTxnRequest struct {
+	// The transactional id corresponding to the transaction.
+	TransactionalId string
+	// Current producer id in use by the transactional id.
+	ProducerId int64
+	// Current epoch associated with the producer id.
+	ProducerEpoch int16
+	// The unique group identifier.
+	GroupId string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of AddOffsetsToTxnRequest
+func (*AddOffsetsToTxnRequest) APIKey() int16 { return 25 }
+
+// MinVersion returns the lowest supported version of AddOffsetsToTxnRequest
+func (*AddOffsetsToTxnRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of AddOffsetsToTxnRequest
+func (*AddOffsetsToTxnRequest) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of AddOffsetsToTxnRequest uses the flexible encoding
+func (*AddOffsetsToTxnRequest) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes AddOffsetsToTxnRequest in the given version
+func (m *AddOffsetsToTxnRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads AddOffsetsToTxnRequest in the given version
+func (m *AddOffsetsToTxnRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets AddOffsetsToTxnRequest to its default field values
+func (m *AddOffsetsToTxnRequest) Default() {
+	*m = AddOffsetsToTxnRequest{}
+}
+
+func (m *AddOffsetsToTxnRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TransactionalId, flexible)
+	e.PutInt64(m.ProducerId)
+	e.PutInt16(m.ProducerEpoch)
+	e.PutString(m.GroupId, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddOffsetsToTxnRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TransactionalId = d.String(flexible)
+	m.ProducerId = d.Int64()
+	m.ProducerEpoch = d.Int16()
+	m.GroupId = d.String(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Code generated by protogen from messages/AddOffsetsToTxnResponse.json. DO NOT EDIT.
+
+package protocol
+
+// AddOffsetsToTxnResponse is the response for API key 25, versions 0-4.
+type AddOffsetsToTxnResponse struct {
+	// Duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The response error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of AddOffsetsToTxnResponse
+func (*AddOffsetsToTxnResponse) APIKey() int16 { return 25 }
+
+// MinVersion returns the lowest supported version of AddOffsetsToTxnResponse
+func (*AddOffsetsToTxnResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of AddOffsetsToTxnResponse
+func (*AddOffsetsToTxnResponse) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of AddOffsetsToTxnResponse uses the flexible encoding
+func (*AddOffsetsToTxnResponse) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes AddOffsetsToTxnResponse in the given version
+func (m *AddOffsetsToTxnResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads AddOffsetsToTxnResponse in the given version
+func (m *AddOffsetsToTxnResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets AddOffsetsToTxnResponse to its default field values
+func (m *AddOffsetsToTxnResponse) Default() {
+	*m = AddOffsetsToTxnResponse{}
+}
+
+func (m *AddOffsetsToTxnResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddOffsetsToTxnResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Code generated by protogen from messages/AddPartitionsToTxnRequest.json. DO NOT EDIT.
+
+package protocol
+
+// AddPartitionsToTxnRequest is the request for API key 24, versions 0-5.
+type AddPartitionsToTxnRequest struct {
+	// List of transactions to add partitions to.
+	Transactions []AddPartitionsToTxnRequestAddPartitionsToTxnTransaction
+	// The transactional id corresponding to the transaction.
+	V3AndBelowTransactionalId string
+	// Current producer id in use by the transactional id.
+	V3AndBelowProducerId int64
+	// Current epoch associated with the producer id.
+	V3AndBelowProducerEpoch int16
+	// The partitions to add to the transaction.
+	V3AndBelowTopics []AddPartitionsToTxnRequestAddPartitionsToTxnTopic
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of AddPartitionsToTxnRequest
+func (*AddPartitionsToTxnRequest) APIKey() int16 { return 24 }
+
+// MinVersion returns the lowest supported version of AddPartitionsToTxnRequest
+func (*AddPartitionsToTxnRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of AddPartitionsToTxnRequest
+func (*AddPartitionsToTxnRequest) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of AddPartitionsToTxnRequest uses the flexible encoding
+func (*AddPartitionsToTxnRequest) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes AddPartitionsToTxnRequest in the given version
+func (m *AddPartitionsToTxnRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads AddPartitionsToTxnRequest in the given version
+func (m *AddPartitionsToTxnRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets AddPartitionsToTxnRequest to its default field values
+func (m *AddPartitionsToTxnRequest) Default() {
+	*m = AddPartitionsToTxnRequest{}
+}
+
+func (m *AddPartitionsToTxnRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 4 {
+		e.PutArrayLength(len(m.Transactions), flexible)
+		for i := range m.Transactions {
+			m.Transactions[i].encode(e, version, flexible)
+		}
+	}
+	if version <= 3 {
+		e.PutString(m.V3AndBelowTransactionalId, flexible)
+	}
+	if version <= 3 {
+		e.PutInt64(m.V3AndBelowProducerId)
+	}
+	if version <= 3 {
+		e.PutInt16(m.V3AndBelowProducerEpoch)
+	}
+	if version <= 3 {
+		e.PutArrayLength(len(m.V3AndBelowTopics), flexible)
+		for i := range m.V3AndBelowTopics {
+			m.V3AndBelowTopics[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddPartitionsToTxnRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 4 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Transactions = make([]AddPartitionsToTxnRequestAddPartitionsToTxnTransaction, n)
+			for i := range m.Transactions {
+				m.Transactions[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Transactions = nil
+		}
+	}
+	if version <= 3 {
+		m.V3AndBelowTransactionalId = d.String(flexible)
+	}
+	if version <= 3 {
+		m.V3AndBelowProducerId = d.Int64()
+	}
+	if version <= 3 {
+		m.V3AndBelowProducerEpoch = d.Int16()
+	}
+	if version <= 3 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.V3AndBelowTopics = make([]AddPartitionsToTxnRequestAddPartitionsToTxnTopic, n)
+			for i := range m.V3AndBelowTopics {
+				m.V3AndBelowTopics[i].decode(d, version, flexible)
+			}
+		} else {
+			m.V3AndBelowTopics = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// AddPartitionsToTxnRequestAddPartitionsToTxnTransaction is an element of AddPartitionsToTxnRequest.Transactions.
+type AddPartitionsToTxnRequestAddPartitionsToTxnTransaction struct {
+	// The transactional id corresponding to the transaction.
+	TransactionalId string
+	// Current producer id in use by the transactional id.
+	ProducerId int64
+	// Current epoch associated with the producer id.
+	ProducerEpoch int16
+	// Boolean to signify if we want to check if the partition is in the transaction rather than add it.
+	VerifyOnly bool
+	// The partitions to add to the transaction.
+	Topics []AddPartitionsToTxnRequestAddPartitionsToTxnTopic
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets AddPartitionsToTxnRequestAddPartitionsToTxnTransaction to its default field values
+func (m *AddPartitionsToTxnRequestAddPartitionsToTxnTransaction) Default() {
+	*m = AddPartitionsToTxnRequestAddPartitionsToTxnTransaction{}
+}
+
+func (m *AddPartitionsToTxnRequestAddPartitionsToTxnTransaction) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TransactionalId, flexible)
+	e.PutInt64(m.ProducerId)
+	e.PutInt16(m.ProducerEpoch)
+	e.PutBool(m.VerifyOnly)
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddPartitionsToTxnRequestAddPartitionsToTxnTransaction) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TransactionalId = d.String(flexible)
+	m.ProducerId = d.Int64()
+	m.ProducerEpoch = d.Int16()
+	m.VerifyOnly = d.Bool()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]AddPartitionsToTxnRequestAddPartitionsToTxnTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// AddPartitionsToTxnRequestAddPartitionsToTxnTopic is an element of AddPartitionsToTxnRequest.V3AndBelowTopics.
+type AddPartitionsToTxnRequestAddPartitionsToTxnTopic struct {
+	// The name of the topic.
+	Name string
+	// The partition indexes to add to the transaction.
+	Partitions []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets AddPartitionsToTxnRequestAddPartitionsToTxnTopic to its default field values
+func (m *AddPartitionsToTxnRequestAddPartitionsToTxnTopic) Default() {
+	*m = AddPartitionsToTxnRequestAddPartitionsToTxnTopic{}
+}
+
+func (m *AddPartitionsToTxnRequestAddPartitionsToTxnTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		e.PutInt32(m.Partitions[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddPartitionsToTxnRequestAddPartitionsToTxnTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]int32, n)
+		for i := range m.Partitions {
+			m.Partitions[i] = d.Int32()
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
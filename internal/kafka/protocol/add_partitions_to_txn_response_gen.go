@@ -0,0 +1,234 @@
+// Code generated by protogen from messages/AddPartitionsToTxnResponse.json. DO NOT EDIT.
+
+package protocol
+
+// AddPartitionsToTxnResponse is the response for API key 24, versions 0-5.
+type AddPartitionsToTxnResponse struct {
+	// Duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The response top level error code.
+	ErrorCode int16
+	// Results categorized by transactional ID.
+	ResultsByTransaction []AddPartitionsToTxnResponseAddPartitionsToTxnResult
+	// The results for each topic.
+	ResultsByTopicV3AndBelow []AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of AddPartitionsToTxnResponse
+func (*AddPartitionsToTxnResponse) APIKey() int16 { return 24 }
+
+// MinVersion returns the lowest supported version of AddPartitionsToTxnResponse
+func (*AddPartitionsToTxnResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of AddPartitionsToTxnResponse
+func (*AddPartitionsToTxnResponse) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of AddPartitionsToTxnResponse uses the flexible encoding
+func (*AddPartitionsToTxnResponse) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes AddPartitionsToTxnResponse in the given version
+func (m *AddPartitionsToTxnResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads AddPartitionsToTxnResponse in the given version
+func (m *AddPartitionsToTxnResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets AddPartitionsToTxnResponse to its default field values
+func (m *AddPartitionsToTxnResponse) Default() {
+	*m = AddPartitionsToTxnResponse{}
+}
+
+func (m *AddPartitionsToTxnResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	if version >= 4 {
+		e.PutInt16(m.ErrorCode)
+	}
+	if version >= 4 {
+		e.PutArrayLength(len(m.ResultsByTransaction), flexible)
+		for i := range m.ResultsByTransaction {
+			m.ResultsByTransaction[i].encode(e, version, flexible)
+		}
+	}
+	if version <= 3 {
+		e.PutArrayLength(len(m.ResultsByTopicV3AndBelow), flexible)
+		for i := range m.ResultsByTopicV3AndBelow {
+			m.ResultsByTopicV3AndBelow[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddPartitionsToTxnResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if version >= 4 {
+		m.ErrorCode = d.Int16()
+	}
+	if version >= 4 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.ResultsByTransaction = make([]AddPartitionsToTxnResponseAddPartitionsToTxnResult, n)
+			for i := range m.ResultsByTransaction {
+				m.ResultsByTransaction[i].decode(d, version, flexible)
+			}
+		} else {
+			m.ResultsByTransaction = nil
+		}
+	}
+	if version <= 3 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.ResultsByTopicV3AndBelow = make([]AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult, n)
+			for i := range m.ResultsByTopicV3AndBelow {
+				m.ResultsByTopicV3AndBelow[i].decode(d, version, flexible)
+			}
+		} else {
+			m.ResultsByTopicV3AndBelow = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// AddPartitionsToTxnResponseAddPartitionsToTxnResult is an element of AddPartitionsToTxnResponse.ResultsByTransaction.
+type AddPartitionsToTxnResponseAddPartitionsToTxnResult struct {
+	// The transactional id corresponding to the transaction.
+	TransactionalId string
+	// The results for each topic.
+	TopicResults []AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets AddPartitionsToTxnResponseAddPartitionsToTxnResult to its default field values
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnResult) Default() {
+	*m = AddPartitionsToTxnResponseAddPartitionsToTxnResult{}
+}
+
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TransactionalId, flexible)
+	e.PutArrayLength(len(m.TopicResults), flexible)
+	for i := range m.TopicResults {
+		m.TopicResults[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TransactionalId = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.TopicResults = make([]AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult, n)
+		for i := range m.TopicResults {
+			m.TopicResults[i].decode(d, version, flexible)
+		}
+	} else {
+		m.TopicResults = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult is an element of AddPartitionsToTxnResponse.ResultsByTopicV3AndBelow.
+type AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult struct {
+	// The topic name.
+	Name string
+	// The results for each partition.
+	ResultsByPartition []AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult to its default field values
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult) Default() {
+	*m = AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult{}
+}
+
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.ResultsByPartition), flexible)
+	for i := range m.ResultsByPartition {
+		m.ResultsByPartition[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.ResultsByPartition = make([]AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult, n)
+		for i := range m.ResultsByPartition {
+			m.ResultsByPartition[i].decode(d, version, flexible)
+		}
+	} else {
+		m.ResultsByPartition = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult is an element of AddPartitionsToTxnResponseAddPartitionsToTxnTopicResult.ResultsByPartition.
+type AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult struct {
+	// The partition indexes.
+	PartitionIndex int32
+	// The response error code.
+	PartitionErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult to its default field values
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult) Default() {
+	*m = AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult{}
+}
+
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt16(m.PartitionErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *AddPartitionsToTxnResponseAddPartitionsToTxnPartitionResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.PartitionErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
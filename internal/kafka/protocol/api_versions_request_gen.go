@@ -0,0 +1,71 @@
+// Code generated by protogen from messages/ApiVersionsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// ApiVersionsRequest is the request for API key 18, versions 0-4.
+type ApiVersionsRequest struct {
+	// The name of the client.
+	ClientSoftwareName string
+	// The version of the client.
+	ClientSoftwareVersion string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ApiVersionsRequest
+func (*ApiVersionsRequest) APIKey() int16 { return 18 }
+
+// MinVersion returns the lowest supported version of ApiVersionsRequest
+func (*ApiVersionsRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ApiVersionsRequest
+func (*ApiVersionsRequest) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of ApiVersionsRequest uses the flexible encoding
+func (*ApiVersionsRequest) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes ApiVersionsRequest in the given version
+func (m *ApiVersionsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ApiVersionsRequest in the given version
+func (m *ApiVersionsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ApiVersionsRequest to its default field values
+func (m *ApiVersionsRequest) Default() {
+	*m = ApiVersionsRequest{}
+}
+
+func (m *ApiVersionsRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 3 {
+		e.PutString(m.ClientSoftwareName, flexible)
+	}
+	if version >= 3 {
+		e.PutString(m.ClientSoftwareVersion, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ApiVersionsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 3 {
+		m.ClientSoftwareName = d.String(flexible)
+	}
+	if version >= 3 {
+		m.ClientSoftwareVersion = d.String(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
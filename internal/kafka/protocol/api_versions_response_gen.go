@@ -0,0 +1,262 @@
+// Code generated by protogen from messages/ApiVersionsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// ApiVersionsResponse is the response for API key 18, versions 0-4.
+type ApiVersionsResponse struct {
+	// The top-level error code.
+	ErrorCode int16
+	// The APIs supported by the broker.
+	ApiKeys []ApiVersionsResponseApiVersion
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// Features supported by the broker. Note: in v0-v3, features with MinSupportedVersion = 0 are omitted.
+	SupportedFeatures []ApiVersionsResponseSupportedFeatureKey
+	// The monotonically increasing epoch for the finalized features information. Valid values are >= 0. A value of -1 is special and represents unknown epoch.
+	FinalizedFeaturesEpoch int64
+	// List of cluster-wide finalized features. The information is valid only if FinalizedFeaturesEpoch >= 0.
+	FinalizedFeatures []ApiVersionsResponseFinalizedFeatureKey
+	// Set by a KRaft controller if the required configurations for ZK migration are present.
+	ZkMigrationReady bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ApiVersionsResponse
+func (*ApiVersionsResponse) APIKey() int16 { return 18 }
+
+// MinVersion returns the lowest supported version of ApiVersionsResponse
+func (*ApiVersionsResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ApiVersionsResponse
+func (*ApiVersionsResponse) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of ApiVersionsResponse uses the flexible encoding
+func (*ApiVersionsResponse) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes ApiVersionsResponse in the given version
+func (m *ApiVersionsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ApiVersionsResponse in the given version
+func (m *ApiVersionsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ApiVersionsResponse to its default field values
+func (m *ApiVersionsResponse) Default() {
+	*m = ApiVersionsResponse{}
+	m.FinalizedFeaturesEpoch = -1
+}
+
+func (m *ApiVersionsResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ErrorCode)
+	e.PutArrayLength(len(m.ApiKeys), flexible)
+	for i := range m.ApiKeys {
+		m.ApiKeys[i].encode(e, version, flexible)
+	}
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	if flexible {
+		var tagged []TaggedField
+		if len(m.SupportedFeatures) > 0 {
+			te := NewEncoder(0)
+			te.PutArrayLength(len(m.SupportedFeatures), true)
+			for i := range m.SupportedFeatures {
+				m.SupportedFeatures[i].encode(te, version, flexible)
+			}
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		if m.FinalizedFeaturesEpoch != -1 {
+			te := NewEncoder(0)
+			te.PutInt64(m.FinalizedFeaturesEpoch)
+			tagged = append(tagged, TaggedField{Tag: 1, Data: te.Bytes()})
+		}
+		if len(m.FinalizedFeatures) > 0 {
+			te := NewEncoder(0)
+			te.PutArrayLength(len(m.FinalizedFeatures), true)
+			for i := range m.FinalizedFeatures {
+				m.FinalizedFeatures[i].encode(te, version, flexible)
+			}
+			tagged = append(tagged, TaggedField{Tag: 2, Data: te.Bytes()})
+		}
+		if m.ZkMigrationReady {
+			te := NewEncoder(0)
+			te.PutBool(m.ZkMigrationReady)
+			tagged = append(tagged, TaggedField{Tag: 3, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *ApiVersionsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ErrorCode = d.Int16()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.ApiKeys = make([]ApiVersionsResponseApiVersion, n)
+		for i := range m.ApiKeys {
+			m.ApiKeys[i].decode(d, version, flexible)
+		}
+	} else {
+		m.ApiKeys = nil
+	}
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.SupportedFeatures = make([]ApiVersionsResponseSupportedFeatureKey, n)
+					for i := range m.SupportedFeatures {
+						m.SupportedFeatures[i].decode(fd, version, flexible)
+					}
+				} else {
+					m.SupportedFeatures = nil
+				}
+			case 1:
+				m.FinalizedFeaturesEpoch = fd.Int64()
+			case 2:
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.FinalizedFeatures = make([]ApiVersionsResponseFinalizedFeatureKey, n)
+					for i := range m.FinalizedFeatures {
+						m.FinalizedFeatures[i].decode(fd, version, flexible)
+					}
+				} else {
+					m.FinalizedFeatures = nil
+				}
+			case 3:
+				m.ZkMigrationReady = fd.Bool()
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ApiVersionsResponseApiVersion is an element of ApiVersionsResponse.ApiKeys.
+type ApiVersionsResponseApiVersion struct {
+	// The API index.
+	ApiKey int16
+	// The minimum supported version, inclusive.
+	MinVersion int16
+	// The maximum supported version, inclusive.
+	MaxVersion int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ApiVersionsResponseApiVersion to its default field values
+func (m *ApiVersionsResponseApiVersion) Default() {
+	*m = ApiVersionsResponseApiVersion{}
+}
+
+func (m *ApiVersionsResponseApiVersion) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ApiKey)
+	e.PutInt16(m.MinVersion)
+	e.PutInt16(m.MaxVersion)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ApiVersionsResponseApiVersion) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ApiKey = d.Int16()
+	m.MinVersion = d.Int16()
+	m.MaxVersion = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ApiVersionsResponseSupportedFeatureKey is an element of ApiVersionsResponse.SupportedFeatures.
+type ApiVersionsResponseSupportedFeatureKey struct {
+	// The name of the feature.
+	Name string
+	// The minimum supported version for the feature.
+	MinVersion int16
+	// The maximum supported version for the feature.
+	MaxVersion int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ApiVersionsResponseSupportedFeatureKey to its default field values
+func (m *ApiVersionsResponseSupportedFeatureKey) Default() {
+	*m = ApiVersionsResponseSupportedFeatureKey{}
+}
+
+func (m *ApiVersionsResponseSupportedFeatureKey) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutInt16(m.MinVersion)
+	e.PutInt16(m.MaxVersion)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ApiVersionsResponseSupportedFeatureKey) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.MinVersion = d.Int16()
+	m.MaxVersion = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ApiVersionsResponseFinalizedFeatureKey is an element of ApiVersionsResponse.FinalizedFeatures.
+type ApiVersionsResponseFinalizedFeatureKey struct {
+	// The name of the feature.
+	Name string
+	// The cluster-wide finalized max version level for the feature.
+	MaxVersionLevel int16
+	// The cluster-wide finalized min version level for the feature.
+	MinVersionLevel int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ApiVersionsResponseFinalizedFeatureKey to its default field values
+func (m *ApiVersionsResponseFinalizedFeatureKey) Default() {
+	*m = ApiVersionsResponseFinalizedFeatureKey{}
+}
+
+func (m *ApiVersionsResponseFinalizedFeatureKey) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutInt16(m.MaxVersionLevel)
+	e.PutInt16(m.MinVersionLevel)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ApiVersionsResponseFinalizedFeatureKey) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.MaxVersionLevel = d.Int16()
+	m.MinVersionLevel = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
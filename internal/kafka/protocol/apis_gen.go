@@ -0,0 +1,34 @@
+// Code generated by protogen. DO NOT EDIT.
+
+package protocol
+
+// apiSpecs holds the versions defined by each request spec, keyed by API key
+var apiSpecs = map[int16]apiSpec{
+	0:  {name: "Produce", minVersion: 0, maxVersion: 11, firstFlexibleVersion: 9},
+	1:  {name: "Fetch", minVersion: 0, maxVersion: 16, firstFlexibleVersion: 12},
+	2:  {name: "ListOffsets", minVersion: 1, maxVersion: 9, firstFlexibleVersion: 6},
+	3:  {name: "Metadata", minVersion: 0, maxVersion: 12, firstFlexibleVersion: 9},
+	8:  {name: "OffsetCommit", minVersion: 2, maxVersion: 9, firstFlexibleVersion: 8},
+	9:  {name: "OffsetFetch", minVersion: 1, maxVersion: 9, firstFlexibleVersion: 6},
+	10: {name: "FindCoordinator", minVersion: 0, maxVersion: 5, firstFlexibleVersion: 3},
+	11: {name: "JoinGroup", minVersion: 2, maxVersion: 9, firstFlexibleVersion: 6},
+	12: {name: "Heartbeat", minVersion: 0, maxVersion: 4, firstFlexibleVersion: 4},
+	13: {name: "LeaveGroup", minVersion: 0, maxVersion: 5, firstFlexibleVersion: 4},
+	14: {name: "SyncGroup", minVersion: 0, maxVersion: 5, firstFlexibleVersion: 4},
+	15: {name: "DescribeGroups", minVersion: 0, maxVersion: 6, firstFlexibleVersion: 5},
+	16: {name: "ListGroups", minVersion: 0, maxVersion: 5, firstFlexibleVersion: 3},
+	18: {name: "ApiVersions", minVersion: 0, maxVersion: 4, firstFlexibleVersion: 3},
+	19: {name: "CreateTopics", minVersion: 2, maxVersion: 7, firstFlexibleVersion: 5},
+	20: {name: "DeleteTopics", minVersion: 1, maxVersion: 6, firstFlexibleVersion: 4},
+	22: {name: "InitProducerId", minVersion: 0, maxVersion: 5, firstFlexibleVersion: 2},
+	24: {name: "AddPartitionsToTxn", minVersion: 0, maxVersion: 5, firstFlexibleVersion: 3},
+	25: {name: "AddOffsetsToTxn", minVersion: 0, maxVersion: 4, firstFlexibleVersion: 3},
+	26: {name: "EndTxn", minVersion: 0, maxVersion: 4, firstFlexibleVersion: 3},
+	27: {name: "WriteTxnMarkers", minVersion: 0, maxVersion: 1, firstFlexibleVersion: 1},
+	28: {name: "TxnOffsetCommit", minVersion: 0, maxVersion: 5, firstFlexibleVersion: 3},
+	37: {name: "CreatePartitions", minVersion: 0, maxVersion: 3, firstFlexibleVersion: 2},
+	42: {name: "DeleteGroups", minVersion: 0, maxVersion: 2, firstFlexibleVersion: 2},
+	68: {name: "ConsumerGroupHeartbeat", minVersion: 0, maxVersion: 1, firstFlexibleVersion: 0},
+	69: {name: "ConsumerGroupDescribe", minVersion: 0, maxVersion: 1, firstFlexibleVersion: 0},
+	75: {name: "DescribeTopicPartitions", minVersion: 0, maxVersion: 0, firstFlexibleVersion: 0},
+}
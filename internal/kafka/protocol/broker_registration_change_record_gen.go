@@ -0,0 +1,109 @@
+// Code generated by protogen from messages/BrokerRegistrationChangeRecord.json. DO NOT EDIT.
+
+package protocol
+
+// BrokerRegistrationChangeRecord is the metadata record of type 17, versions 0-2.
+type BrokerRegistrationChangeRecord struct {
+	// The broker id.
+	BrokerId int32
+	// The broker epoch assigned by the controller.
+	BrokerEpoch int64
+	// -1 if the broker has been unfenced, 0 if no change, 1 if the broker has been fenced.
+	Fenced int8
+	// 0 if no change, 1 if the broker is in controlled shutdown.
+	InControlledShutdown int8
+	// Log directories configured in this broker which are available.
+	LogDirs []UUID
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of BrokerRegistrationChangeRecord
+func (*BrokerRegistrationChangeRecord) APIKey() int16 { return 17 }
+
+// MinVersion returns the lowest supported version of BrokerRegistrationChangeRecord
+func (*BrokerRegistrationChangeRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of BrokerRegistrationChangeRecord
+func (*BrokerRegistrationChangeRecord) MaxVersion() int16 { return 2 }
+
+// IsFlexible reports whether the given version of BrokerRegistrationChangeRecord uses the flexible encoding
+func (*BrokerRegistrationChangeRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes BrokerRegistrationChangeRecord in the given version
+func (m *BrokerRegistrationChangeRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads BrokerRegistrationChangeRecord in the given version
+func (m *BrokerRegistrationChangeRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets BrokerRegistrationChangeRecord to its default field values
+func (m *BrokerRegistrationChangeRecord) Default() {
+	*m = BrokerRegistrationChangeRecord{}
+}
+
+func (m *BrokerRegistrationChangeRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.BrokerId)
+	e.PutInt64(m.BrokerEpoch)
+	if flexible {
+		var tagged []TaggedField
+		if m.Fenced != 0 {
+			te := NewEncoder(0)
+			te.PutInt8(m.Fenced)
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		if (version >= 1) && m.InControlledShutdown != 0 {
+			te := NewEncoder(0)
+			te.PutInt8(m.InControlledShutdown)
+			tagged = append(tagged, TaggedField{Tag: 1, Data: te.Bytes()})
+		}
+		if (version >= 2) && len(m.LogDirs) > 0 {
+			te := NewEncoder(0)
+			te.PutArrayLength(len(m.LogDirs), true)
+			for i := range m.LogDirs {
+				te.PutUUID(m.LogDirs[i])
+			}
+			tagged = append(tagged, TaggedField{Tag: 2, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *BrokerRegistrationChangeRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.BrokerId = d.Int32()
+	m.BrokerEpoch = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				m.Fenced = fd.Int8()
+			case 1:
+				if !(version >= 1) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				m.InControlledShutdown = fd.Int8()
+			case 2:
+				if !(version >= 2) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.LogDirs = make([]UUID, n)
+					for i := range m.LogDirs {
+						m.LogDirs[i] = fd.UUID()
+					}
+				} else {
+					m.LogDirs = nil
+				}
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Code generated by protogen from messages/ConfigRecord.json. DO NOT EDIT.
+
+package protocol
+
+// ConfigRecord is the metadata record of type 4, version 0.
+type ConfigRecord struct {
+	// The type of resource this configuration applies to.
+	ResourceType int8
+	// The name of the resource this configuration applies to.
+	ResourceName string
+	// The name of the configuration key.
+	Name string
+	// The value of the configuration, or null if the it should be deleted.
+	Value *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ConfigRecord
+func (*ConfigRecord) APIKey() int16 { return 4 }
+
+// MinVersion returns the lowest supported version of ConfigRecord
+func (*ConfigRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ConfigRecord
+func (*ConfigRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of ConfigRecord uses the flexible encoding
+func (*ConfigRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes ConfigRecord in the given version
+func (m *ConfigRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ConfigRecord in the given version
+func (m *ConfigRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ConfigRecord to its default field values
+func (m *ConfigRecord) Default() {
+	*m = ConfigRecord{}
+}
+
+func (m *ConfigRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt8(m.ResourceType)
+	e.PutString(m.ResourceName, flexible)
+	e.PutString(m.Name, flexible)
+	e.PutNullableString(m.Value, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConfigRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ResourceType = d.Int8()
+	m.ResourceName = d.String(flexible)
+	m.Name = d.String(flexible)
+	m.Value = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
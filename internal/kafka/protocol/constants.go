@@ -3,27 +3,161 @@ package protocol
 
 // API Keys for Kafka protocol
 const (
+	ProduceKey                 int16 = 0
+	FetchKey                   int16 = 1
+	ListOffsetsKey             int16 = 2
+	MetadataKey                int16 = 3
+	OffsetCommitKey            int16 = 8
+	OffsetFetchKey             int16 = 9
+	FindCoordinatorKey         int16 = 10
+	JoinGroupKey               int16 = 11
+	HeartbeatKey               int16 = 12
+	LeaveGroupKey              int16 = 13
+	SyncGroupKey               int16 = 14
+	DescribeGroupsKey          int16 = 15
+	ListGroupsKey              int16 = 16
 	ApiVersionsKey             int16 = 18
+	CreateTopicsKey            int16 = 19
+	DeleteTopicsKey            int16 = 20
+	InitProducerIdKey          int16 = 22
+	AddPartitionsToTxnKey      int16 = 24
+	AddOffsetsToTxnKey         int16 = 25
+	EndTxnKey                  int16 = 26
+	WriteTxnMarkersKey         int16 = 27
+	TxnOffsetCommitKey         int16 = 28
+	CreatePartitionsKey        int16 = 37
+	DeleteGroupsKey            int16 = 42
+	ConsumerGroupHeartbeatKey  int16 = 68
+	ConsumerGroupDescribeKey   int16 = 69
 	DescribeTopicPartitionsKey int16 = 75
 )
 
 // Error codes for Kafka protocol
 const (
-	ErrorNone               uint16 = 0
-	ErrorUnsupportedVersion uint16 = 35
-	ErrorUnknownTopic       uint16 = 3
+	ErrorUnknownServerError         int16 = -1
+	ErrorNone                       int16 = 0
+	ErrorOffsetOutOfRange           int16 = 1
+	ErrorCorruptMessage             int16 = 2
+	ErrorUnknownTopic               int16 = 3
+	ErrorLeaderNotAvailable         int16 = 5
+	ErrorMessageTooLarge            int16 = 10
+	ErrorOffsetMetadataTooLarge     int16 = 12
+	ErrorCoordinatorNotAvailable    int16 = 15
+	ErrorNotCoordinator             int16 = 16
+	ErrorInvalidTopic               int16 = 17
+	ErrorInvalidRequiredAcks        int16 = 21
+	ErrorIllegalGeneration          int16 = 22
+	ErrorInconsistentGroupProtocol  int16 = 23
+	ErrorInvalidGroupID             int16 = 24
+	ErrorUnknownMemberID            int16 = 25
+	ErrorInvalidSessionTimeout      int16 = 26
+	ErrorRebalanceInProgress        int16 = 27
+	ErrorUnsupportedVersion         int16 = 35
+	ErrorTopicAlreadyExists         int16 = 36
+	ErrorInvalidPartitions          int16 = 37
+	ErrorInvalidReplicationFactor   int16 = 38
+	ErrorInvalidReplicaAssignment   int16 = 39
+	ErrorInvalidConfig              int16 = 40
+	ErrorInvalidRequest             int16 = 42
+	ErrorOutOfOrderSequenceNumber   int16 = 45
+	ErrorInvalidProducerEpoch       int16 = 47
+	ErrorInvalidTxnState            int16 = 48
+	ErrorInvalidProducerIDMapping   int16 = 49
+	ErrorInvalidTransactionTimeout  int16 = 50
+	ErrorConcurrentTransactions     int16 = 51
+	ErrorOperationNotAttempted      int16 = 55
+	ErrorKafkaStorageError          int16 = 56
+	ErrorNonEmptyGroup              int16 = 68
+	ErrorGroupIDNotFound            int16 = 69
+	ErrorFetchSessionIDNotFound     int16 = 70
+	ErrorFencedLeaderEpoch          int16 = 74
+	ErrorUnknownLeaderEpoch         int16 = 75
+	ErrorUnsupportedCompressionType int16 = 76
+	ErrorMemberIDRequired           int16 = 79
+	ErrorGroupMaxSizeReached        int16 = 81
+	ErrorFencedInstanceID           int16 = 82
+	ErrorInvalidRecord              int16 = 87
+	ErrorUnstableOffsetCommit       int16 = 88
+	ErrorProducerFenced             int16 = 90
+	ErrorUnknownTopicID             int16 = 100
+	ErrorFencedMemberEpoch          int16 = 110
+	ErrorUnreleasedInstanceID       int16 = 111
+	ErrorUnsupportedAssignor        int16 = 112
+	ErrorStaleMemberEpoch           int16 = 113
+	ErrorInvalidRegularExpression   int16 = 130
 )
 
-// Version constraints
+// ErrorUnknownTopicOrPartition is the same code as ErrorUnknownTopic, under
+// Kafka's name for it (UNKNOWN_TOPIC_OR_PARTITION), for partitions a topic
+// does not have
+const ErrorUnknownTopicOrPartition = ErrorUnknownTopic
+
+// API version ranges
 const (
-	MinSupportedVersion int16 = 0
-	MaxSupportedVersion int16 = 4
+	ProduceMinVersion                int16 = 0
+	ProduceMaxVersion                int16 = 11
+	FetchMinVersion                  int16 = 0
+	FetchMaxVersion                  int16 = 16
+	ListOffsetsMinVersion            int16 = 1
+	ListOffsetsMaxVersion            int16 = 9
+	MetadataMinVersion               int16 = 0
+	MetadataMaxVersion               int16 = 12
+	OffsetCommitMinVersion           int16 = 2
+	OffsetCommitMaxVersion           int16 = 9
+	OffsetFetchMinVersion            int16 = 1
+	OffsetFetchMaxVersion            int16 = 9
+	FindCoordinatorMinVersion        int16 = 0
+	FindCoordinatorMaxVersion        int16 = 5
+	JoinGroupMinVersion              int16 = 2
+	JoinGroupMaxVersion              int16 = 9
+	HeartbeatMinVersion              int16 = 0
+	HeartbeatMaxVersion              int16 = 4
+	LeaveGroupMinVersion             int16 = 0
+	LeaveGroupMaxVersion             int16 = 5
+	SyncGroupMinVersion              int16 = 0
+	SyncGroupMaxVersion              int16 = 5
+	DescribeGroupsMinVersion         int16 = 0
+	DescribeGroupsMaxVersion         int16 = 6
+	ListGroupsMinVersion             int16 = 0
+	ListGroupsMaxVersion             int16 = 5
+	ApiVersionsMinVersion            int16 = 0
+	ApiVersionsMaxVersion            int16 = 4
+	CreateTopicsMinVersion           int16 = 2
+	CreateTopicsMaxVersion           int16 = 7
+	DeleteTopicsMinVersion           int16 = 1
+	DeleteTopicsMaxVersion           int16 = 6
+	InitProducerIdMinVersion         int16 = 0
+	InitProducerIdMaxVersion         int16 = 5
+	AddPartitionsToTxnMinVersion     int16 = 0
+	AddPartitionsToTxnMaxVersion     int16 = 5
+	AddOffsetsToTxnMinVersion        int16 = 0
+	AddOffsetsToTxnMaxVersion        int16 = 4
+	EndTxnMinVersion                 int16 = 0
+	EndTxnMaxVersion                 int16 = 4
+	WriteTxnMarkersMinVersion        int16 = 0
+	WriteTxnMarkersMaxVersion        int16 = 1
+	TxnOffsetCommitMinVersion        int16 = 0
+	TxnOffsetCommitMaxVersion        int16 = 5
+	CreatePartitionsMinVersion       int16 = 0
+	CreatePartitionsMaxVersion       int16 = 3
+	DeleteGroupsMinVersion           int16 = 0
+	DeleteGroupsMaxVersion           int16 = 2
+	ConsumerGroupHeartbeatMinVersion int16 = 0
+	ConsumerGroupHeartbeatMaxVersion int16 = 1
+	ConsumerGroupDescribeMinVersion  int16 = 0
+	ConsumerGroupDescribeMaxVersion  int16 = 1
+	DescribeTopicMinVersion          int16 = 0
+	DescribeTopicMaxVersion          int16 = 0
 )
 
-// API version ranges
+// Coordinator key types of FindCoordinator requests
+const (
+	CoordinatorKeyGroup       int8 = 0
+	CoordinatorKeyTransaction int8 = 1
+)
+
+// Isolation levels of Fetch and ListOffsets requests
 const (
-	ApiVersionsMinVersion   int16 = 0
-	ApiVersionsMaxVersion   int16 = 4
-	DescribeTopicMinVersion int16 = 0
-	DescribeTopicMaxVersion int16 = 0
+	ReadUncommitted int8 = 0
+	ReadCommitted   int8 = 1
 )
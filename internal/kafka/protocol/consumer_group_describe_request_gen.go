@@ -0,0 +1,73 @@
+// Code generated by protogen from messages/ConsumerGroupDescribeRequest.json. DO NOT EDIT.
+
+package protocol
+
+// ConsumerGroupDescribeRequest is the request for API key 69, versions 0-1.
+type ConsumerGroupDescribeRequest struct {
+	// The ids of the groups to describe.
+	GroupIds []string
+	// Whether to include authorized operations.
+	IncludeAuthorizedOperations bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ConsumerGroupDescribeRequest
+func (*ConsumerGroupDescribeRequest) APIKey() int16 { return 69 }
+
+// MinVersion returns the lowest supported version of ConsumerGroupDescribeRequest
+func (*ConsumerGroupDescribeRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ConsumerGroupDescribeRequest
+func (*ConsumerGroupDescribeRequest) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of ConsumerGroupDescribeRequest uses the flexible encoding
+func (*ConsumerGroupDescribeRequest) IsFlexible(version int16) bool { return true }
+
+// Encode writes ConsumerGroupDescribeRequest in the given version
+func (m *ConsumerGroupDescribeRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ConsumerGroupDescribeRequest in the given version
+func (m *ConsumerGroupDescribeRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ConsumerGroupDescribeRequest to its default field values
+func (m *ConsumerGroupDescribeRequest) Default() {
+	*m = ConsumerGroupDescribeRequest{}
+}
+
+func (m *ConsumerGroupDescribeRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.GroupIds), flexible)
+	for i := range m.GroupIds {
+		e.PutString(m.GroupIds[i], flexible)
+	}
+	e.PutBool(m.IncludeAuthorizedOperations)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupDescribeRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.GroupIds = make([]string, n)
+		for i := range m.GroupIds {
+			m.GroupIds[i] = d.String(flexible)
+		}
+	} else {
+		m.GroupIds = nil
+	}
+	m.IncludeAuthorizedOperations = d.Bool()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+// Code generated by protogen from messages/ConsumerGroupDescribeResponse.json. DO NOT EDIT.
+
+package protocol
+
+// ConsumerGroupDescribeResponse is the response for API key 69, versions 0-1.
+type ConsumerGroupDescribeResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// Each described group.
+	Groups []ConsumerGroupDescribeResponseDescribedGroup
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ConsumerGroupDescribeResponse
+func (*ConsumerGroupDescribeResponse) APIKey() int16 { return 69 }
+
+// MinVersion returns the lowest supported version of ConsumerGroupDescribeResponse
+func (*ConsumerGroupDescribeResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ConsumerGroupDescribeResponse
+func (*ConsumerGroupDescribeResponse) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of ConsumerGroupDescribeResponse uses the flexible encoding
+func (*ConsumerGroupDescribeResponse) IsFlexible(version int16) bool { return true }
+
+// Encode writes ConsumerGroupDescribeResponse in the given version
+func (m *ConsumerGroupDescribeResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ConsumerGroupDescribeResponse in the given version
+func (m *ConsumerGroupDescribeResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ConsumerGroupDescribeResponse to its default field values
+func (m *ConsumerGroupDescribeResponse) Default() {
+	*m = ConsumerGroupDescribeResponse{}
+}
+
+func (m *ConsumerGroupDescribeResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutArrayLength(len(m.Groups), flexible)
+	for i := range m.Groups {
+		m.Groups[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupDescribeResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Groups = make([]ConsumerGroupDescribeResponseDescribedGroup, n)
+		for i := range m.Groups {
+			m.Groups[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Groups = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ConsumerGroupDescribeResponseDescribedGroup is an element of ConsumerGroupDescribeResponse.Groups.
+type ConsumerGroupDescribeResponseDescribedGroup struct {
+	// The describe error, or 0 if there was no error.
+	ErrorCode int16
+	// The top-level error message, or null if there was no error.
+	ErrorMessage *string
+	// The group ID string.
+	GroupId string
+	// The group state string, or the empty string.
+	GroupState string
+	// The group epoch.
+	GroupEpoch int32
+	// The assignment epoch.
+	AssignmentEpoch int32
+	// The selected assignor.
+	AssignorName string
+	// The members.
+	Members []ConsumerGroupDescribeResponseMember
+	// 32-bit bitfield to represent authorized operations for this group.
+	AuthorizedOperations int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ConsumerGroupDescribeResponseDescribedGroup to its default field values
+func (m *ConsumerGroupDescribeResponseDescribedGroup) Default() {
+	*m = ConsumerGroupDescribeResponseDescribedGroup{}
+	m.AuthorizedOperations = -2147483648
+}
+
+func (m *ConsumerGroupDescribeResponseDescribedGroup) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ErrorCode)
+	e.PutNullableString(m.ErrorMessage, flexible)
+	e.PutString(m.GroupId, flexible)
+	e.PutString(m.GroupState, flexible)
+	e.PutInt32(m.GroupEpoch)
+	e.PutInt32(m.AssignmentEpoch)
+	e.PutString(m.AssignorName, flexible)
+	e.PutArrayLength(len(m.Members), flexible)
+	for i := range m.Members {
+		m.Members[i].encode(e, version, flexible)
+	}
+	e.PutInt32(m.AuthorizedOperations)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupDescribeResponseDescribedGroup) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ErrorCode = d.Int16()
+	m.ErrorMessage = d.NullableString(flexible)
+	m.GroupId = d.String(flexible)
+	m.GroupState = d.String(flexible)
+	m.GroupEpoch = d.Int32()
+	m.AssignmentEpoch = d.Int32()
+	m.AssignorName = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Members = make([]ConsumerGroupDescribeResponseMember, n)
+		for i := range m.Members {
+			m.Members[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Members = nil
+	}
+	m.AuthorizedOperations = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ConsumerGroupDescribeResponseMember is an element of ConsumerGroupDescribeResponseDescribedGroup.Members.
+type ConsumerGroupDescribeResponseMember struct {
+	// The member ID.
+	MemberId string
+	// The member instance ID.
+	InstanceId *string
+	// The member rack ID.
+	RackId *string
+	// The current member epoch.
+	MemberEpoch int32
+	// The client ID.
+	ClientId string
+	// The client host.
+	ClientHost string
+	// The subscribed topic names.
+	SubscribedTopicNames []string
+	// the subscribed topic regex otherwise or null of not provided.
+	SubscribedTopicRegex *string
+	// The current assignment.
+	Assignment ConsumerGroupDescribeResponseAssignment
+	// The target assignment.
+	TargetAssignment ConsumerGroupDescribeResponseAssignment
+	// -1 for unknown. 0 for classic member. +1 for consumer member.
+	MemberType int8
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ConsumerGroupDescribeResponseMember to its default field values
+func (m *ConsumerGroupDescribeResponseMember) Default() {
+	*m = ConsumerGroupDescribeResponseMember{}
+	m.Assignment.Default()
+	m.TargetAssignment.Default()
+	m.MemberType = -1
+}
+
+func (m *ConsumerGroupDescribeResponseMember) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.MemberId, flexible)
+	e.PutNullableString(m.InstanceId, flexible)
+	e.PutNullableString(m.RackId, flexible)
+	e.PutInt32(m.MemberEpoch)
+	e.PutString(m.ClientId, flexible)
+	e.PutString(m.ClientHost, flexible)
+	e.PutArrayLength(len(m.SubscribedTopicNames), flexible)
+	for i := range m.SubscribedTopicNames {
+		e.PutString(m.SubscribedTopicNames[i], flexible)
+	}
+	e.PutNullableString(m.SubscribedTopicRegex, flexible)
+	m.Assignment.encode(e, version, flexible)
+	m.TargetAssignment.encode(e, version, flexible)
+	if version >= 1 {
+		e.PutInt8(m.MemberType)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupDescribeResponseMember) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.MemberId = d.String(flexible)
+	m.InstanceId = d.NullableString(flexible)
+	m.RackId = d.NullableString(flexible)
+	m.MemberEpoch = d.Int32()
+	m.ClientId = d.String(flexible)
+	m.ClientHost = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.SubscribedTopicNames = make([]string, n)
+		for i := range m.SubscribedTopicNames {
+			m.SubscribedTopicNames[i] = d.String(flexible)
+		}
+	} else {
+		m.SubscribedTopicNames = nil
+	}
+	m.SubscribedTopicRegex = d.NullableString(flexible)
+	m.Assignment.decode(d, version, flexible)
+	m.TargetAssignment.decode(d, version, flexible)
+	if version >= 1 {
+		m.MemberType = d.Int8()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ConsumerGroupDescribeResponseAssignment is the type of ConsumerGroupDescribeResponseMember.Assignment.
+type ConsumerGroupDescribeResponseAssignment struct {
+	// The assigned topic-partitions to the member.
+	TopicPartitions []ConsumerGroupDescribeResponseTopicPartitions
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ConsumerGroupDescribeResponseAssignment to its default field values
+func (m *ConsumerGroupDescribeResponseAssignment) Default() {
+	*m = ConsumerGroupDescribeResponseAssignment{}
+}
+
+func (m *ConsumerGroupDescribeResponseAssignment) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.TopicPartitions), flexible)
+	for i := range m.TopicPartitions {
+		m.TopicPartitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupDescribeResponseAssignment) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.TopicPartitions = make([]ConsumerGroupDescribeResponseTopicPartitions, n)
+		for i := range m.TopicPartitions {
+			m.TopicPartitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.TopicPartitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ConsumerGroupDescribeResponseTopicPartitions is an element of ConsumerGroupDescribeResponseAssignment.TopicPartitions.
+type ConsumerGroupDescribeResponseTopicPartitions struct {
+	// The topic ID.
+	TopicId UUID
+	// The topic name.
+	TopicName string
+	// The partitions.
+	Partitions []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ConsumerGroupDescribeResponseTopicPartitions to its default field values
+func (m *ConsumerGroupDescribeResponseTopicPartitions) Default() {
+	*m = ConsumerGroupDescribeResponseTopicPartitions{}
+}
+
+func (m *ConsumerGroupDescribeResponseTopicPartitions) encode(e *Encoder, version int16, flexible bool) {
+	e.PutUUID(m.TopicId)
+	e.PutString(m.TopicName, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		e.PutInt32(m.Partitions[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupDescribeResponseTopicPartitions) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TopicId = d.UUID()
+	m.TopicName = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]int32, n)
+		for i := range m.Partitions {
+			m.Partitions[i] = d.Int32()
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Code generated by protogen from messages/ConsumerGroupHeartbeatRequest.json. DO NOT EDIT.
+
+package protocol
+
+// ConsumerGroupHeartbeatRequest is the request for API key 68, versions 0-1.
+type ConsumerGroupHeartbeatRequest struct {
+	// The group identifier.
+	GroupId string
+	// The member id generated by the consumer. The member id must be kept during the entire lifetime of the consumer process.
+	MemberId string
+	// The current member epoch; 0 to join the group; -1 to leave the group; -2 to indicate that the static member will rejoin.
+	MemberEpoch int32
+	// null if not provided or if it didn't change since the last heartbeat; the instance Id otherwise.
+	InstanceId *string
+	// null if not provided or if it didn't change since the last heartbeat; the rack ID of consumer otherwise.
+	RackId *string
+	// -1 if it didn't change since the last heartbeat; the maximum time in milliseconds that the coordinator will wait on the member to revoke its partitions otherwise.
+	RebalanceTimeoutMs int32
+	// null if it didn't change since the last heartbeat; the subscribed topic names otherwise.
+	SubscribedTopicNames []string
+	// null if it didn't change since the last heartbeat; the subscribed topic regex otherwise.
+	SubscribedTopicRegex *string
+	// null if not used or if it didn't change since the last heartbeat; the server side assignor to use otherwise.
+	ServerAssignor *string
+	// null if it didn't change since the last heartbeat; the partitions owned by the member.
+	TopicPartitions []ConsumerGroupHeartbeatRequestTopicPartitions
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ConsumerGroupHeartbeatRequest
+func (*ConsumerGroupHeartbeatRequest) APIKey() int16 { return 68 }
+
+// MinVersion returns the lowest supported version of ConsumerGroupHeartbeatRequest
+func (*ConsumerGroupHeartbeatRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ConsumerGroupHeartbeatRequest
+func (*ConsumerGroupHeartbeatRequest) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of ConsumerGroupHeartbeatRequest uses the flexible encoding
+func (*ConsumerGroupHeartbeatRequest) IsFlexible(version int16) bool { return true }
+
+// Encode writes ConsumerGroupHeartbeatRequest in the given version
+func (m *ConsumerGroupHeartbeatRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ConsumerGroupHeartbeatRequest in the given version
+func (m *ConsumerGroupHeartbeatRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ConsumerGroupHeartbeatRequest to its default field values
+func (m *ConsumerGroupHeartbeatRequest) Default() {
+	*m = ConsumerGroupHeartbeatRequest{}
+	m.RebalanceTimeoutMs = -1
+}
+
+func (m *ConsumerGroupHeartbeatRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutString(m.MemberId, flexible)
+	e.PutInt32(m.MemberEpoch)
+	e.PutNullableString(m.InstanceId, flexible)
+	e.PutNullableString(m.RackId, flexible)
+	e.PutInt32(m.RebalanceTimeoutMs)
+	if m.SubscribedTopicNames == nil {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.SubscribedTopicNames), flexible)
+		for i := range m.SubscribedTopicNames {
+			e.PutString(m.SubscribedTopicNames[i], flexible)
+		}
+	}
+	if version >= 1 {
+		e.PutNullableString(m.SubscribedTopicRegex, flexible)
+	}
+	e.PutNullableString(m.ServerAssignor, flexible)
+	if m.TopicPartitions == nil {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.TopicPartitions), flexible)
+		for i := range m.TopicPartitions {
+			m.TopicPartitions[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupHeartbeatRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	m.MemberId = d.String(flexible)
+	m.MemberEpoch = d.Int32()
+	m.InstanceId = d.NullableString(flexible)
+	m.RackId = d.NullableString(flexible)
+	m.RebalanceTimeoutMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.SubscribedTopicNames = make([]string, n)
+		for i := range m.SubscribedTopicNames {
+			m.SubscribedTopicNames[i] = d.String(flexible)
+		}
+	} else {
+		m.SubscribedTopicNames = nil
+	}
+	if version >= 1 {
+		m.SubscribedTopicRegex = d.NullableString(flexible)
+	}
+	m.ServerAssignor = d.NullableString(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.TopicPartitions = make([]ConsumerGroupHeartbeatRequestTopicPartitions, n)
+		for i := range m.TopicPartitions {
+			m.TopicPartitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.TopicPartitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ConsumerGroupHeartbeatRequestTopicPartitions is an element of ConsumerGroupHeartbeatRequest.TopicPartitions.
+type ConsumerGroupHeartbeatRequestTopicPartitions struct {
+	// The topic ID.
+	TopicId UUID
+	// The partitions.
+	Partitions []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ConsumerGroupHeartbeatRequestTopicPartitions to its default field values
+func (m *ConsumerGroupHeartbeatRequestTopicPartitions) Default() {
+	*m = ConsumerGroupHeartbeatRequestTopicPartitions{}
+}
+
+func (m *ConsumerGroupHeartbeatRequestTopicPartitions) encode(e *Encoder, version int16, flexible bool) {
+	e.PutUUID(m.TopicId)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		e.PutInt32(m.Partitions[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupHeartbeatRequestTopicPartitions) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TopicId = d.UUID()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]int32, n)
+		for i := range m.Partitions {
+			m.Partitions[i] = d.Int32()
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
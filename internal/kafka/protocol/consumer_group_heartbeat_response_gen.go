@@ -0,0 +1,183 @@
+// Code generated by protogen from messages/ConsumerGroupHeartbeatResponse.json. DO NOT EDIT.
+
+package protocol
+
+// ConsumerGroupHeartbeatResponse is the response for API key 68, versions 0-1.
+type ConsumerGroupHeartbeatResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The top-level error code, or 0 if there was no error
+	ErrorCode int16
+	// The top-level error message, or null if there was no error.
+	ErrorMessage *string
+	// The member id is generated by the consumer starting from version 1, while in version 0, it can be provided by users or generated by the group coordinator.
+	MemberId *string
+	// The member epoch.
+	MemberEpoch int32
+	// The heartbeat interval in milliseconds.
+	HeartbeatIntervalMs int32
+	// null if not provided; the assignment otherwise.
+	Assignment *ConsumerGroupHeartbeatResponseAssignment
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ConsumerGroupHeartbeatResponse
+func (*ConsumerGroupHeartbeatResponse) APIKey() int16 { return 68 }
+
+// MinVersion returns the lowest supported version of ConsumerGroupHeartbeatResponse
+func (*ConsumerGroupHeartbeatResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ConsumerGroupHeartbeatResponse
+func (*ConsumerGroupHeartbeatResponse) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of ConsumerGroupHeartbeatResponse uses the flexible encoding
+func (*ConsumerGroupHeartbeatResponse) IsFlexible(version int16) bool { return true }
+
+// Encode writes ConsumerGroupHeartbeatResponse in the given version
+func (m *ConsumerGroupHeartbeatResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ConsumerGroupHeartbeatResponse in the given version
+func (m *ConsumerGroupHeartbeatResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ConsumerGroupHeartbeatResponse to its default field values
+func (m *ConsumerGroupHeartbeatResponse) Default() {
+	*m = ConsumerGroupHeartbeatResponse{}
+}
+
+func (m *ConsumerGroupHeartbeatResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutInt16(m.ErrorCode)
+	e.PutNullableString(m.ErrorMessage, flexible)
+	e.PutNullableString(m.MemberId, flexible)
+	e.PutInt32(m.MemberEpoch)
+	e.PutInt32(m.HeartbeatIntervalMs)
+	if m.Assignment == nil {
+		e.PutInt8(-1)
+	} else {
+		e.PutInt8(1)
+		m.Assignment.encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupHeartbeatResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	m.ErrorCode = d.Int16()
+	m.ErrorMessage = d.NullableString(flexible)
+	m.MemberId = d.NullableString(flexible)
+	m.MemberEpoch = d.Int32()
+	m.HeartbeatIntervalMs = d.Int32()
+	if d.Int8() >= 0 {
+		m.Assignment = &ConsumerGroupHeartbeatResponseAssignment{}
+		m.Assignment.decode(d, version, flexible)
+	} else {
+		m.Assignment = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ConsumerGroupHeartbeatResponseAssignment is the type of ConsumerGroupHeartbeatResponse.Assignment.
+type ConsumerGroupHeartbeatResponseAssignment struct {
+	// The partitions assigned to the member that can be used immediately.
+	TopicPartitions []ConsumerGroupHeartbeatResponseTopicPartitions
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ConsumerGroupHeartbeatResponseAssignment to its default field values
+func (m *ConsumerGroupHeartbeatResponseAssignment) Default() {
+	*m = ConsumerGroupHeartbeatResponseAssignment{}
+}
+
+func (m *ConsumerGroupHeartbeatResponseAssignment) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.TopicPartitions), flexible)
+	for i := range m.TopicPartitions {
+		m.TopicPartitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupHeartbeatResponseAssignment) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.TopicPartitions = make([]ConsumerGroupHeartbeatResponseTopicPartitions, n)
+		for i := range m.TopicPartitions {
+			m.TopicPartitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.TopicPartitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ConsumerGroupHeartbeatResponseTopicPartitions is an element of ConsumerGroupHeartbeatResponseAssignment.TopicPartitions.
+type ConsumerGroupHeartbeatResponseTopicPartitions struct {
+	// The topic ID.
+	TopicId UUID
+	// The partitions.
+	Partitions []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ConsumerGroupHeartbeatResponseTopicPartitions to its default field values
+func (m *ConsumerGroupHeartbeatResponseTopicPartitions) Default() {
+	*m = ConsumerGroupHeartbeatResponseTopicPartitions{}
+}
+
+func (m *ConsumerGroupHeartbeatResponseTopicPartitions) encode(e *Encoder, version int16, flexible bool) {
+	e.PutUUID(m.TopicId)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		e.PutInt32(m.Partitions[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ConsumerGroupHeartbeatResponseTopicPartitions) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TopicId = d.UUID()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]int32, n)
+		for i := range m.Partitions {
+			m.Partitions[i] = d.Int32()
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
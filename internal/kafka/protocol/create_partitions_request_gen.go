@@ -0,0 +1,175 @@
+// Code generated by protogen from messages/CreatePartitionsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// CreatePartitionsRequest is the request for API key 37, versions 0-3.
+type CreatePartitionsRequest struct {
+	// Each topic that we want to create new partitions inside.
+	Topics []CreatePartitionsRequestCreatePartitionsTopic
+	// The time in ms to wait for the partitions to be created.
+	TimeoutMs int32
+	// If true, then validate the request, but don't actually increase the number of partitions.
+	ValidateOnly bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of CreatePartitionsRequest
+func (*CreatePartitionsRequest) APIKey() int16 { return 37 }
+
+// MinVersion returns the lowest supported version of CreatePartitionsRequest
+func (*CreatePartitionsRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of CreatePartitionsRequest
+func (*CreatePartitionsRequest) MaxVersion() int16 { return 3 }
+
+// IsFlexible reports whether the given version of CreatePartitionsRequest uses the flexible encoding
+func (*CreatePartitionsRequest) IsFlexible(version int16) bool { return version >= 2 }
+
+// Encode writes CreatePartitionsRequest in the given version
+func (m *CreatePartitionsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads CreatePartitionsRequest in the given version
+func (m *CreatePartitionsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets CreatePartitionsRequest to its default field values
+func (m *CreatePartitionsRequest) Default() {
+	*m = CreatePartitionsRequest{}
+}
+
+func (m *CreatePartitionsRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	e.PutInt32(m.TimeoutMs)
+	e.PutBool(m.ValidateOnly)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreatePartitionsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]CreatePartitionsRequestCreatePartitionsTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	m.TimeoutMs = d.Int32()
+	m.ValidateOnly = d.Bool()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreatePartitionsRequestCreatePartitionsTopic is an element of CreatePartitionsRequest.Topics.
+type CreatePartitionsRequestCreatePartitionsTopic struct {
+	// The topic name.
+	Name string
+	// The new partition count.
+	Count int32
+	// The new partition assignments.
+	Assignments []CreatePartitionsRequestCreatePartitionsAssignment
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreatePartitionsRequestCreatePartitionsTopic to its default field values
+func (m *CreatePartitionsRequestCreatePartitionsTopic) Default() {
+	*m = CreatePartitionsRequestCreatePartitionsTopic{}
+}
+
+func (m *CreatePartitionsRequestCreatePartitionsTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutInt32(m.Count)
+	if m.Assignments == nil {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.Assignments), flexible)
+		for i := range m.Assignments {
+			m.Assignments[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreatePartitionsRequestCreatePartitionsTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.Count = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Assignments = make([]CreatePartitionsRequestCreatePartitionsAssignment, n)
+		for i := range m.Assignments {
+			m.Assignments[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Assignments = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreatePartitionsRequestCreatePartitionsAssignment is an element of CreatePartitionsRequestCreatePartitionsTopic.Assignments.
+type CreatePartitionsRequestCreatePartitionsAssignment struct {
+	// The assigned broker IDs.
+	BrokerIds []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreatePartitionsRequestCreatePartitionsAssignment to its default field values
+func (m *CreatePartitionsRequestCreatePartitionsAssignment) Default() {
+	*m = CreatePartitionsRequestCreatePartitionsAssignment{}
+}
+
+func (m *CreatePartitionsRequestCreatePartitionsAssignment) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.BrokerIds), flexible)
+	for i := range m.BrokerIds {
+		e.PutInt32(m.BrokerIds[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreatePartitionsRequestCreatePartitionsAssignment) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.BrokerIds = make([]int32, n)
+		for i := range m.BrokerIds {
+			m.BrokerIds[i] = d.Int32()
+		}
+	} else {
+		m.BrokerIds = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Code generated by protogen from messages/CreatePartitionsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// CreatePartitionsResponse is the response for API key 37, versions 0-3.
+type CreatePartitionsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The partition creation results for each topic.
+	Results []CreatePartitionsResponseCreatePartitionsTopicResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of CreatePartitionsResponse
+func (*CreatePartitionsResponse) APIKey() int16 { return 37 }
+
+// MinVersion returns the lowest supported version of CreatePartitionsResponse
+func (*CreatePartitionsResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of CreatePartitionsResponse
+func (*CreatePartitionsResponse) MaxVersion() int16 { return 3 }
+
+// IsFlexible reports whether the given version of CreatePartitionsResponse uses the flexible encoding
+func (*CreatePartitionsResponse) IsFlexible(version int16) bool { return version >= 2 }
+
+// Encode writes CreatePartitionsResponse in the given version
+func (m *CreatePartitionsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads CreatePartitionsResponse in the given version
+func (m *CreatePartitionsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets CreatePartitionsResponse to its default field values
+func (m *CreatePartitionsResponse) Default() {
+	*m = CreatePartitionsResponse{}
+}
+
+func (m *CreatePartitionsResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutArrayLength(len(m.Results), flexible)
+	for i := range m.Results {
+		m.Results[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreatePartitionsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Results = make([]CreatePartitionsResponseCreatePartitionsTopicResult, n)
+		for i := range m.Results {
+			m.Results[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Results = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreatePartitionsResponseCreatePartitionsTopicResult is an element of CreatePartitionsResponse.Results.
+type CreatePartitionsResponseCreatePartitionsTopicResult struct {
+	// The topic name.
+	Name string
+	// The result error, or zero if there was no error.
+	ErrorCode int16
+	// The result message, or null if there was no error.
+	ErrorMessage *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreatePartitionsResponseCreatePartitionsTopicResult to its default field values
+func (m *CreatePartitionsResponseCreatePartitionsTopicResult) Default() {
+	*m = CreatePartitionsResponseCreatePartitionsTopicResult{}
+}
+
+func (m *CreatePartitionsResponseCreatePartitionsTopicResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutInt16(m.ErrorCode)
+	e.PutNullableString(m.ErrorMessage, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreatePartitionsResponseCreatePartitionsTopicResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.ErrorCode = d.Int16()
+	m.ErrorMessage = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Code generated by protogen from messages/CreateTopicsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// CreateTopicsRequest is the request for API key 19, versions 2-7.
+type CreateTopicsRequest struct {
+	// The topics to create.
+	Topics []CreateTopicsRequestCreatableTopic
+	// How long to wait in milliseconds before timing out the request.
+	TimeoutMs int32
+	// If true, check that the topics can be created as specified, but don't create anything.
+	ValidateOnly bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of CreateTopicsRequest
+func (*CreateTopicsRequest) APIKey() int16 { return 19 }
+
+// MinVersion returns the lowest supported version of CreateTopicsRequest
+func (*CreateTopicsRequest) MinVersion() int16 { return 2 }
+
+// MaxVersion returns the highest supported version of CreateTopicsRequest
+func (*CreateTopicsRequest) MaxVersion() int16 { return 7 }
+
+// IsFlexible reports whether the given version of CreateTopicsRequest uses the flexible encoding
+func (*CreateTopicsRequest) IsFlexible(version int16) bool { return version >= 5 }
+
+// Encode writes CreateTopicsRequest in the given version
+func (m *CreateTopicsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads CreateTopicsRequest in the given version
+func (m *CreateTopicsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets CreateTopicsRequest to its default field values
+func (m *CreateTopicsRequest) Default() {
+	*m = CreateTopicsRequest{}
+	m.TimeoutMs = 60000
+}
+
+func (m *CreateTopicsRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	e.PutInt32(m.TimeoutMs)
+	e.PutBool(m.ValidateOnly)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreateTopicsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]CreateTopicsRequestCreatableTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	m.TimeoutMs = d.Int32()
+	m.ValidateOnly = d.Bool()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreateTopicsRequestCreatableTopic is an element of CreateTopicsRequest.Topics.
+type CreateTopicsRequestCreatableTopic struct {
+	// The topic name.
+	Name string
+	// The number of partitions to create in the topic, or -1 if we are either specifying a manual partition assignment or using the default partitions.
+	NumPartitions int32
+	// The number of replicas to create for each partition in the topic, or -1 if we are either specifying a manual partition assignment or using the default replication factor.
+	ReplicationFactor int16
+	// The manual partition assignment, or the empty array if we are using automatic assignment.
+	Assignments []CreateTopicsRequestCreatableReplicaAssignment
+	// The custom topic configurations to set.
+	Configs []CreateTopicsRequestCreatableTopicConfig
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreateTopicsRequestCreatableTopic to its default field values
+func (m *CreateTopicsRequestCreatableTopic) Default() {
+	*m = CreateTopicsRequestCreatableTopic{}
+}
+
+func (m *CreateTopicsRequestCreatableTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutInt32(m.NumPartitions)
+	e.PutInt16(m.ReplicationFactor)
+	e.PutArrayLength(len(m.Assignments), flexible)
+	for i := range m.Assignments {
+		m.Assignments[i].encode(e, version, flexible)
+	}
+	e.PutArrayLength(len(m.Configs), flexible)
+	for i := range m.Configs {
+		m.Configs[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreateTopicsRequestCreatableTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.NumPartitions = d.Int32()
+	m.ReplicationFactor = d.Int16()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Assignments = make([]CreateTopicsRequestCreatableReplicaAssignment, n)
+		for i := range m.Assignments {
+			m.Assignments[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Assignments = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Configs = make([]CreateTopicsRequestCreatableTopicConfig, n)
+		for i := range m.Configs {
+			m.Configs[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Configs = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreateTopicsRequestCreatableReplicaAssignment is an element of CreateTopicsRequestCreatableTopic.Assignments.
+type CreateTopicsRequestCreatableReplicaAssignment struct {
+	// The partition index.
+	PartitionIndex int32
+	// The brokers to place the partition on.
+	BrokerIds []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreateTopicsRequestCreatableReplicaAssignment to its default field values
+func (m *CreateTopicsRequestCreatableReplicaAssignment) Default() {
+	*m = CreateTopicsRequestCreatableReplicaAssignment{}
+}
+
+func (m *CreateTopicsRequestCreatableReplicaAssignment) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutArrayLength(len(m.BrokerIds), flexible)
+	for i := range m.BrokerIds {
+		e.PutInt32(m.BrokerIds[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreateTopicsRequestCreatableReplicaAssignment) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.BrokerIds = make([]int32, n)
+		for i := range m.BrokerIds {
+			m.BrokerIds[i] = d.Int32()
+		}
+	} else {
+		m.BrokerIds = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreateTopicsRequestCreatableTopicConfig is an element of CreateTopicsRequestCreatableTopic.Configs.
+type CreateTopicsRequestCreatableTopicConfig struct {
+	// The configuration name.
+	Name string
+	// The configuration value.
+	Value *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreateTopicsRequestCreatableTopicConfig to its default field values
+func (m *CreateTopicsRequestCreatableTopicConfig) Default() {
+	*m = CreateTopicsRequestCreatableTopicConfig{}
+}
+
+func (m *CreateTopicsRequestCreatableTopicConfig) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutNullableString(m.Value, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreateTopicsRequestCreatableTopicConfig) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.Value = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Code generated by protogen from messages/CreateTopicsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// CreateTopicsResponse is the response for API key 19, versions 2-7.
+type CreateTopicsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// Results for each topic we tried to create.
+	Topics []CreateTopicsResponseCreatableTopicResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of CreateTopicsResponse
+func (*CreateTopicsResponse) APIKey() int16 { return 19 }
+
+// MinVersion returns the lowest supported version of CreateTopicsResponse
+func (*CreateTopicsResponse) MinVersion() int16 { return 2 }
+
+// MaxVersion returns the highest supported version of CreateTopicsResponse
+func (*CreateTopicsResponse) MaxVersion() int16 { return 7 }
+
+// IsFlexible reports whether the given version of CreateTopicsResponse uses the flexible encoding
+func (*CreateTopicsResponse) IsFlexible(version int16) bool { return version >= 5 }
+
+// Encode writes CreateTopicsResponse in the given version
+func (m *CreateTopicsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads CreateTopicsResponse in the given version
+func (m *CreateTopicsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets CreateTopicsResponse to its default field values
+func (m *CreateTopicsResponse) Default() {
+	*m = CreateTopicsResponse{}
+}
+
+func (m *CreateTopicsResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreateTopicsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]CreateTopicsResponseCreatableTopicResult, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreateTopicsResponseCreatableTopicResult is an element of CreateTopicsResponse.Topics.
+type CreateTopicsResponseCreatableTopicResult struct {
+	// The topic name.
+	Name string
+	// The unique topic ID.
+	TopicId UUID
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// The error message, or null if there was no error.
+	ErrorMessage *string
+	// Optional topic config error returned if configs are not returned in the response.
+	TopicConfigErrorCode int16
+	// Number of partitions of the topic.
+	NumPartitions int32
+	// Replication factor of the topic.
+	ReplicationFactor int16
+	// Configuration of the topic.
+	Configs []CreateTopicsResponseCreatableTopicConfigs
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreateTopicsResponseCreatableTopicResult to its default field values
+func (m *CreateTopicsResponseCreatableTopicResult) Default() {
+	*m = CreateTopicsResponseCreatableTopicResult{}
+	m.NumPartitions = -1
+	m.ReplicationFactor = -1
+}
+
+func (m *CreateTopicsResponseCreatableTopicResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	if version >= 7 {
+		e.PutUUID(m.TopicId)
+	}
+	e.PutInt16(m.ErrorCode)
+	e.PutNullableString(m.ErrorMessage, flexible)
+	if version >= 5 {
+		e.PutInt32(m.NumPartitions)
+	}
+	if version >= 5 {
+		e.PutInt16(m.ReplicationFactor)
+	}
+	if version >= 5 {
+		if m.Configs == nil {
+			e.PutArrayLength(-1, flexible)
+		} else {
+			e.PutArrayLength(len(m.Configs), flexible)
+			for i := range m.Configs {
+				m.Configs[i].encode(e, version, flexible)
+			}
+		}
+	}
+	if flexible {
+		var tagged []TaggedField
+		if m.TopicConfigErrorCode != 0 {
+			te := NewEncoder(0)
+			te.PutInt16(m.TopicConfigErrorCode)
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *CreateTopicsResponseCreatableTopicResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if version >= 7 {
+		m.TopicId = d.UUID()
+	}
+	m.ErrorCode = d.Int16()
+	m.ErrorMessage = d.NullableString(flexible)
+	if version >= 5 {
+		m.NumPartitions = d.Int32()
+	}
+	if version >= 5 {
+		m.ReplicationFactor = d.Int16()
+	}
+	if version >= 5 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Configs = make([]CreateTopicsResponseCreatableTopicConfigs, n)
+			for i := range m.Configs {
+				m.Configs[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Configs = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				m.TopicConfigErrorCode = fd.Int16()
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// CreateTopicsResponseCreatableTopicConfigs is an element of CreateTopicsResponseCreatableTopicResult.Configs.
+type CreateTopicsResponseCreatableTopicConfigs struct {
+	// The configuration name.
+	Name string
+	// The configuration value.
+	Value *string
+	// True if the configuration is read-only.
+	ReadOnly bool
+	// The configuration source.
+	ConfigSource int8
+	// True if this configuration is sensitive.
+	IsSensitive bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets CreateTopicsResponseCreatableTopicConfigs to its default field values
+func (m *CreateTopicsResponseCreatableTopicConfigs) Default() {
+	*m = CreateTopicsResponseCreatableTopicConfigs{}
+	m.ConfigSource = -1
+}
+
+func (m *CreateTopicsResponseCreatableTopicConfigs) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutNullableString(m.Value, flexible)
+	e.PutBool(m.ReadOnly)
+	e.PutInt8(m.ConfigSource)
+	e.PutBool(m.IsSensitive)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *CreateTopicsResponseCreatableTopicConfigs) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.Value = d.NullableString(flexible)
+	m.ReadOnly = d.Bool()
+	m.ConfigSource = d.Int8()
+	m.IsSensitive = d.Bool()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Code generated by protogen from messages/DeleteGroupsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// DeleteGroupsRequest is the request for API key 42, versions 0-2.
+type DeleteGroupsRequest struct {
+	// The group names to delete.
+	GroupsNames []string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DeleteGroupsRequest
+func (*DeleteGroupsRequest) APIKey() int16 { return 42 }
+
+// MinVersion returns the lowest supported version of DeleteGroupsRequest
+func (*DeleteGroupsRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of DeleteGroupsRequest
+func (*DeleteGroupsRequest) MaxVersion() int16 { return 2 }
+
+// IsFlexible reports whether the given version of DeleteGroupsRequest uses the flexible encoding
+func (*DeleteGroupsRequest) IsFlexible(version int16) bool { return version >= 2 }
+
+// Encode writes DeleteGroupsRequest in the given version
+func (m *DeleteGroupsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DeleteGroupsRequest in the given version
+func (m *DeleteGroupsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DeleteGroupsRequest to its default field values
+func (m *DeleteGroupsRequest) Default() {
+	*m = DeleteGroupsRequest{}
+}
+
+func (m *DeleteGroupsRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.GroupsNames), flexible)
+	for i := range m.GroupsNames {
+		e.PutString(m.GroupsNames[i], flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DeleteGroupsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.GroupsNames = make([]string, n)
+		for i := range m.GroupsNames {
+			m.GroupsNames[i] = d.String(flexible)
+		}
+	} else {
+		m.GroupsNames = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Code generated by protogen from messages/DeleteGroupsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// DeleteGroupsResponse is the response for API key 42, versions 0-2.
+type DeleteGroupsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The deletion results.
+	Results []DeleteGroupsResponseDeletableGroupResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DeleteGroupsResponse
+func (*DeleteGroupsResponse) APIKey() int16 { return 42 }
+
+// MinVersion returns the lowest supported version of DeleteGroupsResponse
+func (*DeleteGroupsResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of DeleteGroupsResponse
+func (*DeleteGroupsResponse) MaxVersion() int16 { return 2 }
+
+// IsFlexible reports whether the given version of DeleteGroupsResponse uses the flexible encoding
+func (*DeleteGroupsResponse) IsFlexible(version int16) bool { return version >= 2 }
+
+// Encode writes DeleteGroupsResponse in the given version
+func (m *DeleteGroupsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DeleteGroupsResponse in the given version
+func (m *DeleteGroupsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DeleteGroupsResponse to its default field values
+func (m *DeleteGroupsResponse) Default() {
+	*m = DeleteGroupsResponse{}
+}
+
+func (m *DeleteGroupsResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutArrayLength(len(m.Results), flexible)
+	for i := range m.Results {
+		m.Results[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DeleteGroupsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Results = make([]DeleteGroupsResponseDeletableGroupResult, n)
+		for i := range m.Results {
+			m.Results[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Results = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DeleteGroupsResponseDeletableGroupResult is an element of DeleteGroupsResponse.Results.
+type DeleteGroupsResponseDeletableGroupResult struct {
+	// The group id.
+	GroupId string
+	// The deletion error, or 0 if the deletion succeeded.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DeleteGroupsResponseDeletableGroupResult to its default field values
+func (m *DeleteGroupsResponseDeletableGroupResult) Default() {
+	*m = DeleteGroupsResponseDeletableGroupResult{}
+}
+
+func (m *DeleteGroupsResponseDeletableGroupResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DeleteGroupsResponseDeletableGroupResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
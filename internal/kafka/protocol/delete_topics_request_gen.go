@@ -0,0 +1,132 @@
+// Code generated by protogen from messages/DeleteTopicsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// DeleteTopicsRequest is the request for API key 20, versions 1-6.
+type DeleteTopicsRequest struct {
+	// The name or topic ID of the topic.
+	Topics []DeleteTopicsRequestDeleteTopicState
+	// The names of the topics to delete.
+	TopicNames []string
+	// The length of time in milliseconds to wait for the deletions to complete.
+	TimeoutMs int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DeleteTopicsRequest
+func (*DeleteTopicsRequest) APIKey() int16 { return 20 }
+
+// MinVersion returns the lowest supported version of DeleteTopicsRequest
+func (*DeleteTopicsRequest) MinVersion() int16 { return 1 }
+
+// MaxVersion returns the highest supported version of DeleteTopicsRequest
+func (*DeleteTopicsRequest) MaxVersion() int16 { return 6 }
+
+// IsFlexible reports whether the given version of DeleteTopicsRequest uses the flexible encoding
+func (*DeleteTopicsRequest) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes DeleteTopicsRequest in the given version
+func (m *DeleteTopicsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DeleteTopicsRequest in the given version
+func (m *DeleteTopicsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DeleteTopicsRequest to its default field values
+func (m *DeleteTopicsRequest) Default() {
+	*m = DeleteTopicsRequest{}
+}
+
+func (m *DeleteTopicsRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 6 {
+		e.PutArrayLength(len(m.Topics), flexible)
+		for i := range m.Topics {
+			m.Topics[i].encode(e, version, flexible)
+		}
+	}
+	if version <= 5 {
+		e.PutArrayLength(len(m.TopicNames), flexible)
+		for i := range m.TopicNames {
+			e.PutString(m.TopicNames[i], flexible)
+		}
+	}
+	e.PutInt32(m.TimeoutMs)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DeleteTopicsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 6 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Topics = make([]DeleteTopicsRequestDeleteTopicState, n)
+			for i := range m.Topics {
+				m.Topics[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Topics = nil
+		}
+	}
+	if version <= 5 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.TopicNames = make([]string, n)
+			for i := range m.TopicNames {
+				m.TopicNames[i] = d.String(flexible)
+			}
+		} else {
+			m.TopicNames = nil
+		}
+	}
+	m.TimeoutMs = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DeleteTopicsRequestDeleteTopicState is an element of DeleteTopicsRequest.Topics.
+type DeleteTopicsRequestDeleteTopicState struct {
+	// The topic name.
+	Name *string
+	// The unique topic ID.
+	TopicId UUID
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DeleteTopicsRequestDeleteTopicState to its default field values
+func (m *DeleteTopicsRequestDeleteTopicState) Default() {
+	*m = DeleteTopicsRequestDeleteTopicState{}
+}
+
+func (m *DeleteTopicsRequestDeleteTopicState) encode(e *Encoder, version int16, flexible bool) {
+	e.PutNullableString(m.Name, flexible)
+	e.PutUUID(m.TopicId)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DeleteTopicsRequestDeleteTopicState) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.NullableString(flexible)
+	m.TopicId = d.UUID()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
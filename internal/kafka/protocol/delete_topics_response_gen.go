@@ -0,0 +1,135 @@
+// Code generated by protogen from messages/DeleteTopicsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// DeleteTopicsResponse is the response for API key 20, versions 1-6.
+type DeleteTopicsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The results for each topic we tried to delete.
+	Responses []DeleteTopicsResponseDeletableTopicResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DeleteTopicsResponse
+func (*DeleteTopicsResponse) APIKey() int16 { return 20 }
+
+// MinVersion returns the lowest supported version of DeleteTopicsResponse
+func (*DeleteTopicsResponse) MinVersion() int16 { return 1 }
+
+// MaxVersion returns the highest supported version of DeleteTopicsResponse
+func (*DeleteTopicsResponse) MaxVersion() int16 { return 6 }
+
+// IsFlexible reports whether the given version of DeleteTopicsResponse uses the flexible encoding
+func (*DeleteTopicsResponse) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes DeleteTopicsResponse in the given version
+func (m *DeleteTopicsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DeleteTopicsResponse in the given version
+func (m *DeleteTopicsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DeleteTopicsResponse to its default field values
+func (m *DeleteTopicsResponse) Default() {
+	*m = DeleteTopicsResponse{}
+}
+
+func (m *DeleteTopicsResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutArrayLength(len(m.Responses), flexible)
+	for i := range m.Responses {
+		m.Responses[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DeleteTopicsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Responses = make([]DeleteTopicsResponseDeletableTopicResult, n)
+		for i := range m.Responses {
+			m.Responses[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Responses = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DeleteTopicsResponseDeletableTopicResult is an element of DeleteTopicsResponse.Responses.
+type DeleteTopicsResponseDeletableTopicResult struct {
+	// The topic name.
+	Name *string
+	// The unique topic ID.
+	TopicId UUID
+	// The deletion error, or 0 if the deletion succeeded.
+	ErrorCode int16
+	// The error message, or null if there was no error.
+	ErrorMessage *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DeleteTopicsResponseDeletableTopicResult to its default field values
+func (m *DeleteTopicsResponseDeletableTopicResult) Default() {
+	*m = DeleteTopicsResponseDeletableTopicResult{}
+}
+
+func (m *DeleteTopicsResponseDeletableTopicResult) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 6 {
+		e.PutNullableString(m.Name, flexible)
+	} else {
+		e.PutString(derefString(m.Name), flexible)
+	}
+	if version >= 6 {
+		e.PutUUID(m.TopicId)
+	}
+	e.PutInt16(m.ErrorCode)
+	if version >= 5 {
+		e.PutNullableString(m.ErrorMessage, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DeleteTopicsResponseDeletableTopicResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 6 {
+		m.Name = d.NullableString(flexible)
+	} else {
+		s := d.String(flexible)
+		m.Name = &s
+	}
+	if version >= 6 {
+		m.TopicId = d.UUID()
+	}
+	m.ErrorCode = d.Int16()
+	if version >= 5 {
+		m.ErrorMessage = d.NullableString(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
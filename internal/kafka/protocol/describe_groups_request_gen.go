@@ -0,0 +1,77 @@
+// Code generated by protogen from messages/DescribeGroupsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// DescribeGroupsRequest is the request for API key 15, versions 0-6.
+type DescribeGroupsRequest struct {
+	// The names of the groups to describe.
+	Groups []string
+	// Whether to include authorized operations.
+	IncludeAuthorizedOperations bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DescribeGroupsRequest
+func (*DescribeGroupsRequest) APIKey() int16 { return 15 }
+
+// MinVersion returns the lowest supported version of DescribeGroupsRequest
+func (*DescribeGroupsRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of DescribeGroupsRequest
+func (*DescribeGroupsRequest) MaxVersion() int16 { return 6 }
+
+// IsFlexible reports whether the given version of DescribeGroupsRequest uses the flexible encoding
+func (*DescribeGroupsRequest) IsFlexible(version int16) bool { return version >= 5 }
+
+// Encode writes DescribeGroupsRequest in the given version
+func (m *DescribeGroupsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DescribeGroupsRequest in the given version
+func (m *DescribeGroupsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DescribeGroupsRequest to its default field values
+func (m *DescribeGroupsRequest) Default() {
+	*m = DescribeGroupsRequest{}
+}
+
+func (m *DescribeGroupsRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.Groups), flexible)
+	for i := range m.Groups {
+		e.PutString(m.Groups[i], flexible)
+	}
+	if version >= 3 {
+		e.PutBool(m.IncludeAuthorizedOperations)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeGroupsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Groups = make([]string, n)
+		for i := range m.Groups {
+			m.Groups[i] = d.String(flexible)
+		}
+	} else {
+		m.Groups = nil
+	}
+	if version >= 3 {
+		m.IncludeAuthorizedOperations = d.Bool()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
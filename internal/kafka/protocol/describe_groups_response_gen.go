@@ -0,0 +1,214 @@
+// Code generated by protogen from messages/DescribeGroupsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// DescribeGroupsResponse is the response for API key 15, versions 0-6.
+type DescribeGroupsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// Each described group.
+	Groups []DescribeGroupsResponseDescribedGroup
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DescribeGroupsResponse
+func (*DescribeGroupsResponse) APIKey() int16 { return 15 }
+
+// MinVersion returns the lowest supported version of DescribeGroupsResponse
+func (*DescribeGroupsResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of DescribeGroupsResponse
+func (*DescribeGroupsResponse) MaxVersion() int16 { return 6 }
+
+// IsFlexible reports whether the given version of DescribeGroupsResponse uses the flexible encoding
+func (*DescribeGroupsResponse) IsFlexible(version int16) bool { return version >= 5 }
+
+// Encode writes DescribeGroupsResponse in the given version
+func (m *DescribeGroupsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DescribeGroupsResponse in the given version
+func (m *DescribeGroupsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DescribeGroupsResponse to its default field values
+func (m *DescribeGroupsResponse) Default() {
+	*m = DescribeGroupsResponse{}
+}
+
+func (m *DescribeGroupsResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutArrayLength(len(m.Groups), flexible)
+	for i := range m.Groups {
+		m.Groups[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeGroupsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Groups = make([]DescribeGroupsResponseDescribedGroup, n)
+		for i := range m.Groups {
+			m.Groups[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Groups = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DescribeGroupsResponseDescribedGroup is an element of DescribeGroupsResponse.Groups.
+type DescribeGroupsResponseDescribedGroup struct {
+	// The describe error, or 0 if there was no error.
+	ErrorCode int16
+	// The describe error message, or null if there was no error.
+	ErrorMessage *string
+	// The group ID string.
+	GroupId string
+	// The group state string, or the empty string.
+	GroupState string
+	// The group protocol type, or the empty string.
+	ProtocolType string
+	// The group protocol data, or the empty string.
+	ProtocolData string
+	// The group members.
+	Members []DescribeGroupsResponseDescribedGroupMember
+	// 32-bit bitfield to represent authorized operations for this group.
+	AuthorizedOperations int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DescribeGroupsResponseDescribedGroup to its default field values
+func (m *DescribeGroupsResponseDescribedGroup) Default() {
+	*m = DescribeGroupsResponseDescribedGroup{}
+	m.AuthorizedOperations = -2147483648
+}
+
+func (m *DescribeGroupsResponseDescribedGroup) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ErrorCode)
+	if version >= 6 {
+		e.PutNullableString(m.ErrorMessage, flexible)
+	}
+	e.PutString(m.GroupId, flexible)
+	e.PutString(m.GroupState, flexible)
+	e.PutString(m.ProtocolType, flexible)
+	e.PutString(m.ProtocolData, flexible)
+	e.PutArrayLength(len(m.Members), flexible)
+	for i := range m.Members {
+		m.Members[i].encode(e, version, flexible)
+	}
+	if version >= 3 {
+		e.PutInt32(m.AuthorizedOperations)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeGroupsResponseDescribedGroup) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ErrorCode = d.Int16()
+	if version >= 6 {
+		m.ErrorMessage = d.NullableString(flexible)
+	}
+	m.GroupId = d.String(flexible)
+	m.GroupState = d.String(flexible)
+	m.ProtocolType = d.String(flexible)
+	m.ProtocolData = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Members = make([]DescribeGroupsResponseDescribedGroupMember, n)
+		for i := range m.Members {
+			m.Members[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Members = nil
+	}
+	if version >= 3 {
+		m.AuthorizedOperations = d.Int32()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DescribeGroupsResponseDescribedGroupMember is an element of DescribeGroupsResponseDescribedGroup.Members.
+type DescribeGroupsResponseDescribedGroupMember struct {
+	// The member id.
+	MemberId string
+	// The unique identifier of the consumer instance provided by end user.
+	GroupInstanceId *string
+	// The client ID used in the member's latest join group request.
+	ClientId string
+	// The client host.
+	ClientHost string
+	// The metadata corresponding to the current group protocol in use.
+	MemberMetadata []byte
+	// The current assignment provided by the group leader.
+	MemberAssignment []byte
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DescribeGroupsResponseDescribedGroupMember to its default field values
+func (m *DescribeGroupsResponseDescribedGroupMember) Default() {
+	*m = DescribeGroupsResponseDescribedGroupMember{}
+}
+
+func (m *DescribeGroupsResponseDescribedGroupMember) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.MemberId, flexible)
+	if version >= 4 {
+		e.PutNullableString(m.GroupInstanceId, flexible)
+	}
+	e.PutString(m.ClientId, flexible)
+	e.PutString(m.ClientHost, flexible)
+	e.PutBytes(m.MemberMetadata, flexible)
+	e.PutBytes(m.MemberAssignment, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeGroupsResponseDescribedGroupMember) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.MemberId = d.String(flexible)
+	if version >= 4 {
+		m.GroupInstanceId = d.NullableString(flexible)
+	}
+	m.ClientId = d.String(flexible)
+	m.ClientHost = d.String(flexible)
+	m.MemberMetadata = d.Bytes(flexible)
+	m.MemberAssignment = d.Bytes(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Code generated by protogen from messages/DescribeTopicPartitionsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// DescribeTopicPartitionsRequest is the request for API key 75, version 0.
+type DescribeTopicPartitionsRequest struct {
+	// The topics to fetch details for.
+	Topics []DescribeTopicPartitionsRequestTopicRequest
+	// The maximum number of partitions included in the response.
+	ResponsePartitionLimit int32
+	// The first topic and partition index to fetch details for.
+	Cursor *DescribeTopicPartitionsRequestCursor
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DescribeTopicPartitionsRequest
+func (*DescribeTopicPartitionsRequest) APIKey() int16 { return 75 }
+
+// MinVersion returns the lowest supported version of DescribeTopicPartitionsRequest
+func (*DescribeTopicPartitionsRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of DescribeTopicPartitionsRequest
+func (*DescribeTopicPartitionsRequest) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of DescribeTopicPartitionsRequest uses the flexible encoding
+func (*DescribeTopicPartitionsRequest) IsFlexible(version int16) bool { return true }
+
+// Encode writes DescribeTopicPartitionsRequest in the given version
+func (m *DescribeTopicPartitionsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DescribeTopicPartitionsRequest in the given version
+func (m *DescribeTopicPartitionsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DescribeTopicPartitionsRequest to its default field values
+func (m *DescribeTopicPartitionsRequest) Default() {
+	*m = DescribeTopicPartitionsRequest{}
+	m.ResponsePartitionLimit = 2000
+}
+
+func (m *DescribeTopicPartitionsRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	e.PutInt32(m.ResponsePartitionLimit)
+	if m.Cursor == nil {
+		e.PutInt8(-1)
+	} else {
+		e.PutInt8(1)
+		m.Cursor.encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeTopicPartitionsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]DescribeTopicPartitionsRequestTopicRequest, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	m.ResponsePartitionLimit = d.Int32()
+	if d.Int8() >= 0 {
+		m.Cursor = &DescribeTopicPartitionsRequestCursor{}
+		m.Cursor.decode(d, version, flexible)
+	} else {
+		m.Cursor = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DescribeTopicPartitionsRequestTopicRequest is an element of DescribeTopicPartitionsRequest.Topics.
+type DescribeTopicPartitionsRequestTopicRequest struct {
+	// The topic name.
+	Name string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DescribeTopicPartitionsRequestTopicRequest to its default field values
+func (m *DescribeTopicPartitionsRequestTopicRequest) Default() {
+	*m = DescribeTopicPartitionsRequestTopicRequest{}
+}
+
+func (m *DescribeTopicPartitionsRequestTopicRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeTopicPartitionsRequestTopicRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DescribeTopicPartitionsRequestCursor is the type of DescribeTopicPartitionsRequest.Cursor.
+type DescribeTopicPartitionsRequestCursor struct {
+	// The name for the first topic to process.
+	TopicName string
+	// The partition index to start with.
+	PartitionIndex int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DescribeTopicPartitionsRequestCursor to its default field values
+func (m *DescribeTopicPartitionsRequestCursor) Default() {
+	*m = DescribeTopicPartitionsRequestCursor{}
+}
+
+func (m *DescribeTopicPartitionsRequestCursor) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TopicName, flexible)
+	e.PutInt32(m.PartitionIndex)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeTopicPartitionsRequestCursor) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TopicName = d.String(flexible)
+	m.PartitionIndex = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
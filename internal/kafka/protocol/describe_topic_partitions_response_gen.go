@@ -0,0 +1,312 @@
+// Code generated by protogen from messages/DescribeTopicPartitionsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// DescribeTopicPartitionsResponse is the response for API key 75, version 0.
+type DescribeTopicPartitionsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// Each topic in the response.
+	Topics []DescribeTopicPartitionsResponseTopic
+	// The next topic and partition index to fetch details for.
+	NextCursor *DescribeTopicPartitionsResponseCursor
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of DescribeTopicPartitionsResponse
+func (*DescribeTopicPartitionsResponse) APIKey() int16 { return 75 }
+
+// MinVersion returns the lowest supported version of DescribeTopicPartitionsResponse
+func (*DescribeTopicPartitionsResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of DescribeTopicPartitionsResponse
+func (*DescribeTopicPartitionsResponse) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of DescribeTopicPartitionsResponse uses the flexible encoding
+func (*DescribeTopicPartitionsResponse) IsFlexible(version int16) bool { return true }
+
+// Encode writes DescribeTopicPartitionsResponse in the given version
+func (m *DescribeTopicPartitionsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads DescribeTopicPartitionsResponse in the given version
+func (m *DescribeTopicPartitionsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets DescribeTopicPartitionsResponse to its default field values
+func (m *DescribeTopicPartitionsResponse) Default() {
+	*m = DescribeTopicPartitionsResponse{}
+}
+
+func (m *DescribeTopicPartitionsResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if m.NextCursor == nil {
+		e.PutInt8(-1)
+	} else {
+		e.PutInt8(1)
+		m.NextCursor.encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeTopicPartitionsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]DescribeTopicPartitionsResponseTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if d.Int8() >= 0 {
+		m.NextCursor = &DescribeTopicPartitionsResponseCursor{}
+		m.NextCursor.decode(d, version, flexible)
+	} else {
+		m.NextCursor = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DescribeTopicPartitionsResponseTopic is an element of DescribeTopicPartitionsResponse.Topics.
+type DescribeTopicPartitionsResponseTopic struct {
+	// The topic error, or 0 if there was no error.
+	ErrorCode int16
+	// The topic name.
+	Name *string
+	// The topic id.
+	TopicId UUID
+	// True if the topic is internal.
+	IsInternal bool
+	// Each partition in the topic.
+	Partitions []DescribeTopicPartitionsResponsePartition
+	// 32-bit bitfield to represent authorized operations for this topic.
+	TopicAuthorizedOperations int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DescribeTopicPartitionsResponseTopic to its default field values
+func (m *DescribeTopicPartitionsResponseTopic) Default() {
+	*m = DescribeTopicPartitionsResponseTopic{}
+	m.TopicAuthorizedOperations = -2147483648
+}
+
+func (m *DescribeTopicPartitionsResponseTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ErrorCode)
+	e.PutNullableString(m.Name, flexible)
+	e.PutUUID(m.TopicId)
+	e.PutBool(m.IsInternal)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	e.PutInt32(m.TopicAuthorizedOperations)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeTopicPartitionsResponseTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ErrorCode = d.Int16()
+	m.Name = d.NullableString(flexible)
+	m.TopicId = d.UUID()
+	m.IsInternal = d.Bool()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]DescribeTopicPartitionsResponsePartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	m.TopicAuthorizedOperations = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DescribeTopicPartitionsResponseCursor is the type of DescribeTopicPartitionsResponse.NextCursor.
+type DescribeTopicPartitionsResponseCursor struct {
+	// The name for the first topic to process.
+	TopicName string
+	// The partition index to start with.
+	PartitionIndex int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DescribeTopicPartitionsResponseCursor to its default field values
+func (m *DescribeTopicPartitionsResponseCursor) Default() {
+	*m = DescribeTopicPartitionsResponseCursor{}
+}
+
+func (m *DescribeTopicPartitionsResponseCursor) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TopicName, flexible)
+	e.PutInt32(m.PartitionIndex)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeTopicPartitionsResponseCursor) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TopicName = d.String(flexible)
+	m.PartitionIndex = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// DescribeTopicPartitionsResponsePartition is an element of DescribeTopicPartitionsResponseTopic.Partitions.
+type DescribeTopicPartitionsResponsePartition struct {
+	// The partition error, or 0 if there was no error.
+	ErrorCode int16
+	// The partition index.
+	PartitionIndex int32
+	// The ID of the leader broker.
+	LeaderId int32
+	// The leader epoch of this partition.
+	LeaderEpoch int32
+	// The set of all nodes that host this partition.
+	ReplicaNodes []int32
+	// The set of nodes that are in sync with the leader for this partition.
+	IsrNodes []int32
+	// The new eligible leader replicas otherwise.
+	EligibleLeaderReplicas []int32
+	// The last known ELR.
+	LastKnownElr []int32
+	// The set of offline replicas of this partition.
+	OfflineReplicas []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets DescribeTopicPartitionsResponsePartition to its default field values
+func (m *DescribeTopicPartitionsResponsePartition) Default() {
+	*m = DescribeTopicPartitionsResponsePartition{}
+	m.LeaderEpoch = -1
+}
+
+func (m *DescribeTopicPartitionsResponsePartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ErrorCode)
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt32(m.LeaderId)
+	e.PutInt32(m.LeaderEpoch)
+	e.PutArrayLength(len(m.ReplicaNodes), flexible)
+	for i := range m.ReplicaNodes {
+		e.PutInt32(m.ReplicaNodes[i])
+	}
+	e.PutArrayLength(len(m.IsrNodes), flexible)
+	for i := range m.IsrNodes {
+		e.PutInt32(m.IsrNodes[i])
+	}
+	if m.EligibleLeaderReplicas == nil {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.EligibleLeaderReplicas), flexible)
+		for i := range m.EligibleLeaderReplicas {
+			e.PutInt32(m.EligibleLeaderReplicas[i])
+		}
+	}
+	if m.LastKnownElr == nil {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.LastKnownElr), flexible)
+		for i := range m.LastKnownElr {
+			e.PutInt32(m.LastKnownElr[i])
+		}
+	}
+	e.PutArrayLength(len(m.OfflineReplicas), flexible)
+	for i := range m.OfflineReplicas {
+		e.PutInt32(m.OfflineReplicas[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *DescribeTopicPartitionsResponsePartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ErrorCode = d.Int16()
+	m.PartitionIndex = d.Int32()
+	m.LeaderId = d.Int32()
+	m.LeaderEpoch = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.ReplicaNodes = make([]int32, n)
+		for i := range m.ReplicaNodes {
+			m.ReplicaNodes[i] = d.Int32()
+		}
+	} else {
+		m.ReplicaNodes = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.IsrNodes = make([]int32, n)
+		for i := range m.IsrNodes {
+			m.IsrNodes[i] = d.Int32()
+		}
+	} else {
+		m.IsrNodes = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.EligibleLeaderReplicas = make([]int32, n)
+		for i := range m.EligibleLeaderReplicas {
+			m.EligibleLeaderReplicas[i] = d.Int32()
+		}
+	} else {
+		m.EligibleLeaderReplicas = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.LastKnownElr = make([]int32, n)
+		for i := range m.LastKnownElr {
+			m.LastKnownElr[i] = d.Int32()
+		}
+	} else {
+		m.LastKnownElr = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.OfflineReplicas = make([]int32, n)
+		for i := range m.OfflineReplicas {
+			m.OfflineReplicas[i] = d.Int32()
+		}
+	} else {
+		m.OfflineReplicas = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
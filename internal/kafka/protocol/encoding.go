@@ -0,0 +1,485 @@
+package protocol
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrInsufficientData is returned when a decoder runs past the end of its buffer
+var ErrInsufficientData = errors.New("insufficient data to decode message")
+
+// UUID is a 128-bit Kafka UUID such as a topic ID
+type UUID [16]byte
+
+// ZeroUUID is the all-zero UUID used for unknown topic IDs
+var ZeroUUID UUID
+
+// String returns the UUID in the standard 8-4-4-4-12 hex form
+func (u UUID) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// Base64 returns the UUID in Kafka's URL-safe base64 form, as used for
+// cluster IDs
+func (u UUID) Base64() string {
+	return base64.RawURLEncoding.EncodeToString(u[:])
+}
+
+// RandomUUID returns a random (version 4) UUID. Like Kafka it never
+// returns the zero UUID, the reserved metadata topic ID (1), or a UUID
+// whose base64 form starts with '-', which would read as a CLI flag.
+func RandomUUID() UUID {
+	for {
+		var u UUID
+		if _, err := rand.Read(u[:]); err != nil {
+			panic(fmt.Sprintf("crypto/rand failed: %s", err.Error()))
+		}
+		u[6] = u[6]&0x0f | 0x40
+		u[8] = u[8]&0x3f | 0x80
+		if u != ZeroUUID && u != (UUID{15: 1}) && u.Base64()[0] != '-' {
+			return u
+		}
+	}
+}
+
+// Encoder appends Kafka wire protocol primitives to a byte buffer
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder creates a new encoder with the given initial capacity
+func NewEncoder(capacity int) *Encoder {
+	return &Encoder{buf: make([]byte, 0, capacity)}
+}
+
+// Bytes returns the encoded bytes
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Len returns the number of bytes encoded so far
+func (e *Encoder) Len() int {
+	return len(e.buf)
+}
+
+// PutBool writes a boolean as a single byte
+func (e *Encoder) PutBool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+// PutInt8 writes a signed 8-bit integer
+func (e *Encoder) PutInt8(v int8) {
+	e.buf = append(e.buf, byte(v))
+}
+
+// PutInt16 writes a big-endian signed 16-bit integer
+func (e *Encoder) PutInt16(v int16) {
+	e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v))
+}
+
+// PutUint16 writes a big-endian unsigned 16-bit integer
+func (e *Encoder) PutUint16(v uint16) {
+	e.buf = binary.BigEndian.AppendUint16(e.buf, v)
+}
+
+// PutInt32 writes a big-endian signed 32-bit integer
+func (e *Encoder) PutInt32(v int32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+}
+
+// PutUint32 writes a big-endian unsigned 32-bit integer
+func (e *Encoder) PutUint32(v uint32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, v)
+}
+
+// PutInt64 writes a big-endian signed 64-bit integer
+func (e *Encoder) PutInt64(v int64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v))
+}
+
+// PutFloat64 writes an IEEE 754 double
+func (e *Encoder) PutFloat64(v float64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+// PutUUID writes a 16-byte UUID
+func (e *Encoder) PutUUID(v UUID) {
+	e.buf = append(e.buf, v[:]...)
+}
+
+// PutVarint writes a zigzag-encoded variable length integer
+func (e *Encoder) PutVarint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+// PutUvarint writes an unsigned variable length integer
+func (e *Encoder) PutUvarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+// PutRaw appends raw bytes without a length prefix
+func (e *Encoder) PutRaw(v []byte) {
+	e.buf = append(e.buf, v...)
+}
+
+// PutString writes a string with an int16 length prefix, or a compact string when flexible
+func (e *Encoder) PutString(v string, flexible bool) {
+	if flexible {
+		e.PutUvarint(uint64(len(v)) + 1)
+	} else {
+		e.PutInt16(int16(len(v)))
+	}
+	e.buf = append(e.buf, v...)
+}
+
+// PutNullableString writes a string that may be null
+func (e *Encoder) PutNullableString(v *string, flexible bool) {
+	if v == nil {
+		if flexible {
+			e.PutUvarint(0)
+		} else {
+			e.PutInt16(-1)
+		}
+		return
+	}
+	e.PutString(*v, flexible)
+}
+
+// PutBytes writes a byte slice with an int32 length prefix, or compact bytes when flexible
+func (e *Encoder) PutBytes(v []byte, flexible bool) {
+	if flexible {
+		e.PutUvarint(uint64(len(v)) + 1)
+	} else {
+		e.PutInt32(int32(len(v)))
+	}
+	e.buf = append(e.buf, v...)
+}
+
+// PutNullableBytes writes a byte slice where nil is encoded as null
+func (e *Encoder) PutNullableBytes(v []byte, flexible bool) {
+	if v == nil {
+		e.PutArrayLength(-1, flexible)
+		return
+	}
+	e.PutBytes(v, flexible)
+}
+
+// PutArrayLength writes an array length, where -1 means a null array
+func (e *Encoder) PutArrayLength(n int, flexible bool) {
+	if flexible {
+		e.PutUvarint(uint64(n + 1))
+	} else {
+		e.PutInt32(int32(n))
+	}
+}
+
+// PutEmptyTaggedFields writes an empty tagged field section
+func (e *Encoder) PutEmptyTaggedFields() {
+	e.buf = append(e.buf, 0)
+}
+
+// PutTaggedFields writes a tagged field section, ordering the fields by tag
+func (e *Encoder) PutTaggedFields(fields []TaggedField) {
+	if !slices.IsSortedFunc(fields, compareTaggedFields) {
+		fields = slices.Clone(fields)
+		slices.SortFunc(fields, compareTaggedFields)
+	}
+	e.PutUvarint(uint64(len(fields)))
+	for _, f := range fields {
+		e.PutUvarint(f.Tag)
+		e.PutUvarint(uint64(len(f.Data)))
+		e.PutRaw(f.Data)
+	}
+}
+
+// compareTaggedFields orders tagged fields by ascending tag
+func compareTaggedFields(a, b TaggedField) int {
+	switch {
+	case a.Tag < b.Tag:
+		return -1
+	case a.Tag > b.Tag:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Decoder reads Kafka wire protocol primitives from a byte buffer.
+// The first error is sticky: once set, every read returns a zero value
+// and Err reports the failure.
+type Decoder struct {
+	buf []byte
+	off int
+	err error
+}
+
+// NewDecoder creates a decoder over the given bytes
+func NewDecoder(buf []byte) *Decoder {
+	return &Decoder{buf: buf}
+}
+
+// Err returns the first error encountered while decoding
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// SetErr records an error if none has been recorded yet
+func (d *Decoder) SetErr(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// Offset returns the number of bytes consumed so far
+func (d *Decoder) Offset() int {
+	return d.off
+}
+
+// Remaining returns the number of unread bytes
+func (d *Decoder) Remaining() int {
+	return len(d.buf) - d.off
+}
+
+// Rest returns the unread bytes without consuming them
+func (d *Decoder) Rest() []byte {
+	return d.buf[d.off:]
+}
+
+// next consumes n bytes, recording ErrInsufficientData if there are not enough
+func (d *Decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf)-d.off {
+		d.err = ErrInsufficientData
+		return nil
+	}
+	b := d.buf[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+// Bool reads a single byte boolean
+func (d *Decoder) Bool() bool {
+	b := d.next(1)
+	if b == nil {
+		return false
+	}
+	return b[0] != 0
+}
+
+// Int8 reads a signed 8-bit integer
+func (d *Decoder) Int8() int8 {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return int8(b[0])
+}
+
+// Int16 reads a big-endian signed 16-bit integer
+func (d *Decoder) Int16() int16 {
+	return int16(d.Uint16())
+}
+
+// Uint16 reads a big-endian unsigned 16-bit integer
+func (d *Decoder) Uint16() uint16 {
+	b := d.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+// Int32 reads a big-endian signed 32-bit integer
+func (d *Decoder) Int32() int32 {
+	return int32(d.Uint32())
+}
+
+// Uint32 reads a big-endian unsigned 32-bit integer
+func (d *Decoder) Uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+// Int64 reads a big-endian signed 64-bit integer
+func (d *Decoder) Int64() int64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+// Float64 reads an IEEE 754 double
+func (d *Decoder) Float64() float64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b))
+}
+
+// UUID reads a 16-byte UUID
+func (d *Decoder) UUID() UUID {
+	var u UUID
+	copy(u[:], d.next(16))
+	return u
+}
+
+// Varint reads a zigzag-encoded variable length integer
+func (d *Decoder) Varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf[d.off:])
+	if n <= 0 {
+		d.err = ErrInsufficientData
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+// Uvarint reads an unsigned variable length integer
+func (d *Decoder) Uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf[d.off:])
+	if n <= 0 {
+		d.err = ErrInsufficientData
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+// Raw consumes n bytes and returns them without copying
+func (d *Decoder) Raw(n int) []byte {
+	return d.next(n)
+}
+
+// length reads a string or bytes length prefix, returning -1 for null
+func (d *Decoder) length(flexible bool, wide bool) int {
+	if flexible {
+		return int(d.Uvarint()) - 1
+	}
+	if wide {
+		return int(d.Int32())
+	}
+	return int(d.Int16())
+}
+
+// String reads a string with an int16 length prefix, or a compact string when flexible
+func (d *Decoder) String(flexible bool) string {
+	n := d.length(flexible, false)
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(n))
+}
+
+// NullableString reads a string that may be null
+func (d *Decoder) NullableString(flexible bool) *string {
+	n := d.length(flexible, false)
+	if n < 0 || d.err != nil {
+		return nil
+	}
+	s := string(d.next(n))
+	return &s
+}
+
+// Bytes reads a byte slice with an int32 length prefix, or compact bytes when flexible
+func (d *Decoder) Bytes(flexible bool) []byte {
+	n := d.length(flexible, true)
+	if n < 0 {
+		return []byte{}
+	}
+	return d.copyBytes(n)
+}
+
+// NullableBytes reads a byte slice that may be null
+func (d *Decoder) NullableBytes(flexible bool) []byte {
+	n := d.length(flexible, true)
+	if n < 0 {
+		return nil
+	}
+	return d.copyBytes(n)
+}
+
+// copyBytes consumes n bytes and returns a copy that outlives the buffer
+func (d *Decoder) copyBytes(n int) []byte {
+	b := d.next(n)
+	if b == nil {
+		return nil
+	}
+	out := make([]byte, n)
+	copy(out, b)
+	return out
+}
+
+// ArrayLength reads an array length, returning -1 for a null array.
+// Lengths that could not possibly fit in the remaining bytes are rejected
+// so a corrupt frame cannot trigger a huge allocation.
+func (d *Decoder) ArrayLength(flexible bool) int {
+	n := d.length(flexible, true)
+	if n > d.Remaining() {
+		d.SetErr(fmt.Errorf("array length %d exceeds remaining %d bytes", n, d.Remaining()))
+		return 0
+	}
+	return n
+}
+
+// TaggedFields reads a tagged field section, calling fn for each field.
+// fn receives a decoder scoped to the field's data; fields it does not
+// consume are skipped.
+func (d *Decoder) TaggedFields(fn func(tag uint64, fd *Decoder)) {
+	count := d.Uvarint()
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		tag := d.Uvarint()
+		size := int(d.Uvarint())
+		data := d.next(size)
+		if d.err != nil {
+			return
+		}
+		if fn != nil {
+			fd := NewDecoder(data)
+			fn(tag, fd)
+			if fd.err != nil {
+				d.SetErr(fd.err)
+			}
+		}
+	}
+}
+
+// UnknownTaggedField consumes the rest of a tagged field's data and returns
+// it as a raw TaggedField
+func (d *Decoder) UnknownTaggedField(tag uint64) TaggedField {
+	return TaggedField{Tag: tag, Data: d.copyBytes(d.Remaining())}
+}
+
+// SkipTaggedFields reads and discards a tagged field section
+func (d *Decoder) SkipTaggedFields() {
+	d.TaggedFields(nil)
+}
+
+// derefString returns the value of a nullable string, or "" when it is null
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,361 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// unhex decodes hex written in groups separated by spaces
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// checkEncoding fails the test unless e holds want, written as for unhex
+func checkEncoding(t *testing.T, name string, e *Encoder, want string) {
+	t.Helper()
+	if got := e.Bytes(); !bytes.Equal(got, unhex(t, want)) {
+		t.Errorf("%s encoded as %x, want %s", name, got, want)
+	}
+}
+
+// checkConsumed fails the test if d failed or has bytes left
+func checkConsumed(t *testing.T, name string, d *Decoder) {
+	t.Helper()
+	if d.Err() != nil {
+		t.Errorf("%s: decode error %v", name, d.Err())
+	}
+	if d.Remaining() != 0 {
+		t.Errorf("%s: %d bytes left after decoding", name, d.Remaining())
+	}
+}
+
+func TestVarints(t *testing.T) {
+	signed := []struct {
+		v    int64
+		want string
+	}{
+		{0, "00"},
+		{-1, "01"},
+		{1, "02"},
+		{-64, "7f"},
+		{64, "8001"},
+		{300, "d804"},
+		{math.MaxInt32, "feffffff0f"},
+		{math.MinInt64, "ffffffffffffffffff01"},
+	}
+	for _, tt := range signed {
+		e := NewEncoder(0)
+		e.PutVarint(tt.v)
+		checkEncoding(t, "varint", e, tt.want)
+		d := NewDecoder(e.Bytes())
+		if got := d.Varint(); got != tt.v {
+			t.Errorf("varint %d decoded as %d", tt.v, got)
+		}
+		checkConsumed(t, "varint", d)
+	}
+
+	unsigned := []struct {
+		v    uint64
+		want string
+	}{
+		{0, "00"},
+		{127, "7f"},
+		{128, "8001"},
+		{300, "ac02"},
+		{math.MaxUint64, "ffffffffffffffffff01"},
+	}
+	for _, tt := range unsigned {
+		e := NewEncoder(0)
+		e.PutUvarint(tt.v)
+		checkEncoding(t, "uvarint", e, tt.want)
+		d := NewDecoder(e.Bytes())
+		if got := d.Uvarint(); got != tt.v {
+			t.Errorf("uvarint %d decoded as %d", tt.v, got)
+		}
+		checkConsumed(t, "uvarint", d)
+	}
+
+	// A varint cut short is an error, not a value
+	for _, data := range []string{"", "80", "ffff"} {
+		d := NewDecoder(unhex(t, data))
+		d.Varint()
+		if !errors.Is(d.Err(), ErrInsufficientData) {
+			t.Errorf("varint from %q: error %v, want %v", data, d.Err(), ErrInsufficientData)
+		}
+	}
+}
+
+func TestStrings(t *testing.T) {
+	ab := "ab"
+	empty := ""
+	tests := []struct {
+		name     string
+		v        *string
+		flexible bool
+		want     string
+	}{
+		{"string", &ab, false, "0002 6162"},
+		{"empty string", &empty, false, "0000"},
+		{"null string", nil, false, "ffff"},
+		// Compact strings store the length plus one, leaving 0 for null
+		{"compact string", &ab, true, "03 6162"},
+		{"empty compact string", &empty, true, "01"},
+		{"null compact string", nil, true, "00"},
+	}
+	for _, tt := range tests {
+		e := NewEncoder(0)
+		e.PutNullableString(tt.v, tt.flexible)
+		checkEncoding(t, tt.name, e, tt.want)
+
+		d := NewDecoder(e.Bytes())
+		got := d.NullableString(tt.flexible)
+		if (got == nil) != (tt.v == nil) || got != nil && *got != *tt.v {
+			t.Errorf("%s decoded as %v", tt.name, got)
+		}
+		checkConsumed(t, tt.name, d)
+
+		// A null read as a non-nullable string is empty
+		if tt.v == nil {
+			d := NewDecoder(e.Bytes())
+			if got := d.String(tt.flexible); got != "" {
+				t.Errorf("%s read as %q", tt.name, got)
+			}
+			checkConsumed(t, tt.name, d)
+		}
+	}
+
+	// Longer compact strings need more than one byte of length
+	long := strings.Repeat("x", 200)
+	e := NewEncoder(0)
+	e.PutString(long, true)
+	if !bytes.Equal(e.Bytes()[:2], unhex(t, "c901")) {
+		t.Errorf("length of a 200 byte compact string encoded as %x, want c901", e.Bytes()[:2])
+	}
+	d := NewDecoder(e.Bytes())
+	if got := d.String(true); got != long {
+		t.Errorf("200 byte compact string decoded as %d bytes", len(got))
+	}
+	checkConsumed(t, "long compact string", d)
+
+	// A string longer than the data is an error
+	d = NewDecoder(unhex(t, "0005 6162"))
+	d.String(false)
+	if !errors.Is(d.Err(), ErrInsufficientData) {
+		t.Errorf("truncated string: error %v, want %v", d.Err(), ErrInsufficientData)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        []byte
+		flexible bool
+		want     string
+	}{
+		{"bytes", []byte{1, 2}, false, "00000002 0102"},
+		{"empty bytes", []byte{}, false, "00000000"},
+		{"null bytes", nil, false, "ffffffff"},
+		{"compact bytes", []byte{1, 2}, true, "03 0102"},
+		{"empty compact bytes", []byte{}, true, "01"},
+		{"null compact bytes", nil, true, "00"},
+	}
+	for _, tt := range tests {
+		e := NewEncoder(0)
+		e.PutNullableBytes(tt.v, tt.flexible)
+		checkEncoding(t, tt.name, e, tt.want)
+
+		d := NewDecoder(e.Bytes())
+		got := d.NullableBytes(tt.flexible)
+		if (got == nil) != (tt.v == nil) || !bytes.Equal(got, tt.v) {
+			t.Errorf("%s decoded as %x (nil %t)", tt.name, got, got == nil)
+		}
+		checkConsumed(t, tt.name, d)
+	}
+
+	// Decoded bytes do not alias the buffer
+	data := unhex(t, "03 0102")
+	got := NewDecoder(data).Bytes(true)
+	data[1] = 9
+	if got[0] != 1 {
+		t.Error("decoded bytes changed with the buffer")
+	}
+}
+
+func TestArrayLengths(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		flexible bool
+		want     string
+	}{
+		{"array", 2, false, "00000002"},
+		{"null array", -1, false, "ffffffff"},
+		{"compact array", 2, true, "03"},
+		{"empty compact array", 0, true, "01"},
+		{"null compact array", -1, true, "00"},
+	}
+	for _, tt := range tests {
+		e := NewEncoder(0)
+		e.PutArrayLength(tt.n, tt.flexible)
+		checkEncoding(t, tt.name, e, tt.want)
+
+		// Two elements of a byte each follow the length
+		d := NewDecoder(append(e.Bytes(), 0, 0))
+		if got := d.ArrayLength(tt.flexible); got != tt.n {
+			t.Errorf("%s decoded as %d", tt.name, got)
+		}
+	}
+
+	// A length the remaining bytes cannot hold is rejected before anything
+	// is allocated for it
+	d := NewDecoder(unhex(t, "7fffffff 00"))
+	if n := d.ArrayLength(false); n != 0 || d.Err() == nil {
+		t.Errorf("array of 2^31-1 elements in 1 byte: length %d, error %v", n, d.Err())
+	}
+}
+
+func TestTaggedFields(t *testing.T) {
+	// Fields are written ordered by tag, whatever order they are given in
+	e := NewEncoder(0)
+	e.PutTaggedFields([]TaggedField{{Tag: 300, Data: []byte{3}}, {Tag: 1, Data: []byte{1, 1}}, {Tag: 2, Data: nil}})
+	checkEncoding(t, "tagged fields", e, "03 01 02 0101 02 00 ac02 01 03")
+
+	var got []TaggedField
+	d := NewDecoder(append(e.Bytes(), 0xee))
+	d.TaggedFields(func(tag uint64, fd *Decoder) {
+		// Fields left unread are skipped
+		if tag == 2 {
+			return
+		}
+		got = append(got, fd.UnknownTaggedField(tag))
+	})
+	want := []TaggedField{{Tag: 1, Data: []byte{1, 1}}, {Tag: 300, Data: []byte{3}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tagged fields decoded as %+v, want %+v", got, want)
+	}
+	if d.Err() != nil || d.Remaining() != 1 {
+		t.Errorf("after tagged fields: error %v, %d bytes left, want the byte after them", d.Err(), d.Remaining())
+	}
+
+	e = NewEncoder(0)
+	e.PutEmptyTaggedFields()
+	checkEncoding(t, "empty tagged fields", e, "00")
+
+	// A field longer than the data is an error
+	d = NewDecoder(unhex(t, "01 00 05 0102"))
+	d.SkipTaggedFields()
+	if !errors.Is(d.Err(), ErrInsufficientData) {
+		t.Errorf("truncated tagged field: error %v, want %v", d.Err(), ErrInsufficientData)
+	}
+}
+
+func TestDecoderErrorIsSticky(t *testing.T) {
+	d := NewDecoder(unhex(t, "0001 02"))
+	if got := d.Int16(); got != 1 {
+		t.Fatalf("Int16 = %d, want 1", got)
+	}
+	// The int32 does not fit, and nothing after it is read either, though
+	// the byte left would hold an int8
+	if got := d.Int32(); got != 0 {
+		t.Errorf("truncated Int32 = %d, want 0", got)
+	}
+	if got := d.Int8(); got != 0 {
+		t.Errorf("Int8 after an error = %d, want 0", got)
+	}
+	if !errors.Is(d.Err(), ErrInsufficientData) {
+		t.Errorf("error %v, want %v", d.Err(), ErrInsufficientData)
+	}
+}
+
+// goldenApiVersionsResponse is the ApiVersionsResponse of the golden
+// encodings in TestApiVersionsResponseGolden
+func goldenApiVersionsResponse() *ApiVersionsResponse {
+	resp := &ApiVersionsResponse{}
+	resp.Default()
+	resp.ApiKeys = []ApiVersionsResponseApiVersion{{ApiKey: ApiVersionsKey, MinVersion: 0, MaxVersion: 4}}
+	resp.ThrottleTimeMs = 7
+	resp.FinalizedFeaturesEpoch = 5
+	resp.FinalizedFeatures = []ApiVersionsResponseFinalizedFeatureKey{{Name: "mv", MaxVersionLevel: 20, MinVersionLevel: 1}}
+	return resp
+}
+
+func TestApiVersionsResponseGolden(t *testing.T) {
+	tests := []struct {
+		version int16
+		want    string
+		// wantDecoded is what the encoding decodes to, without the fields
+		// the version cannot carry
+		wantDecoded func(*ApiVersionsResponse)
+	}{
+		{
+			version: 0,
+			want:    "0000 00000001 0012 0000 0004",
+			wantDecoded: func(m *ApiVersionsResponse) {
+				m.ThrottleTimeMs, m.FinalizedFeaturesEpoch, m.FinalizedFeatures = 0, -1, nil
+			},
+		},
+		{
+			version: 2,
+			want:    "0000 00000001 0012 0000 0004 00000007",
+			wantDecoded: func(m *ApiVersionsResponse) {
+				m.FinalizedFeaturesEpoch, m.FinalizedFeatures = -1, nil
+			},
+		},
+		{
+			// Flexible versions use compact arrays and carry the features
+			// as tagged fields 1 and 2
+			version: 3,
+			want: "0000 02 0012 0000 0004 00 00000007 " +
+				"02 01 08 0000000000000005 02 09 02 03 6d76 0014 0001 00",
+			wantDecoded: func(*ApiVersionsResponse) {},
+		},
+	}
+	for _, tt := range tests {
+		e := NewEncoder(0)
+		goldenApiVersionsResponse().Encode(e, tt.version)
+		checkEncoding(t, "ApiVersionsResponse", e, tt.want)
+
+		got := &ApiVersionsResponse{}
+		d := NewDecoder(e.Bytes())
+		if err := got.Decode(d, tt.version); err != nil {
+			t.Fatalf("v%d: Decode: %v", tt.version, err)
+		}
+		checkConsumed(t, "ApiVersionsResponse", d)
+		want := goldenApiVersionsResponse()
+		tt.wantDecoded(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("v%d decoded as %+v, want %+v", tt.version, got, want)
+		}
+	}
+}
+
+func TestUnknownTaggedFieldsRoundTrip(t *testing.T) {
+	// Tag 9 is not in the spec of ApiVersionsResponse; it survives decoding
+	// and encoding again, in tag order with the known fields
+	data := unhex(t, "0000 01 00000000 02 01 08 0000000000000005 09 02 abcd")
+	resp := &ApiVersionsResponse{}
+	if err := resp.Decode(NewDecoder(data), 3); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if want := []TaggedField{{Tag: 9, Data: []byte{0xab, 0xcd}}}; !reflect.DeepEqual(resp.UnknownTaggedFields, want) {
+		t.Errorf("unknown tagged fields %+v, want %+v", resp.UnknownTaggedFields, want)
+	}
+	if resp.FinalizedFeaturesEpoch != 5 {
+		t.Errorf("finalized features epoch %d, want 5", resp.FinalizedFeaturesEpoch)
+	}
+	e := NewEncoder(0)
+	resp.Encode(e, 3)
+	if !bytes.Equal(e.Bytes(), data) {
+		t.Errorf("encoded again as %x, want %x", e.Bytes(), data)
+	}
+}
@@ -0,0 +1,71 @@
+// Code generated by protogen from messages/EndTxnRequest.json. DO NOT EDIT.
+
+package protocol
+
+// EndTxnRequest is the request for API key 26, versions 0-4.
+type EndTxnRequest struct {
+	// The ID of the transaction to end.
+	TransactionalId string
+	// The producer ID.
+	ProducerId int64
+	// The current epoch associated with the producer.
+	ProducerEpoch int16
+	// True if the transaction was committed, false if it was aborted.
+	Committed bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of EndTxnRequest
+func (*EndTxnRequest) APIKey() int16 { return 26 }
+
+// MinVersion returns the lowest supported version of EndTxnRequest
+func (*EndTxnRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of EndTxnRequest
+func (*EndTxnRequest) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of EndTxnRequest uses the flexible encoding
+func (*EndTxnRequest) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes EndTxnRequest in the given version
+func (m *EndTxnRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads EndTxnRequest in the given version
+func (m *EndTxnRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets EndTxnRequest to its default field values
+func (m *EndTxnRequest) Default() {
+	*m = EndTxnRequest{}
+}
+
+func (m *EndTxnRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TransactionalId, flexible)
+	e.PutInt64(m.ProducerId)
+	e.PutInt16(m.ProducerEpoch)
+	e.PutBool(m.Committed)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *EndTxnRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TransactionalId = d.String(flexible)
+	m.ProducerId = d.Int64()
+	m.ProducerEpoch = d.Int16()
+	m.Committed = d.Bool()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Code generated by protogen from messages/EndTxnResponse.json. DO NOT EDIT.
+
+package protocol
+
+// EndTxnResponse is the response for API key 26, versions 0-4.
+type EndTxnResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of EndTxnResponse
+func (*EndTxnResponse) APIKey() int16 { return 26 }
+
+// MinVersion returns the lowest supported version of EndTxnResponse
+func (*EndTxnResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of EndTxnResponse
+func (*EndTxnResponse) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of EndTxnResponse uses the flexible encoding
+func (*EndTxnResponse) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes EndTxnResponse in the given version
+func (m *EndTxnResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads EndTxnResponse in the given version
+func (m *EndTxnResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets EndTxnResponse to its default field values
+func (m *EndTxnResponse) Default() {
+	*m = EndTxnResponse{}
+}
+
+func (m *EndTxnResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *EndTxnResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
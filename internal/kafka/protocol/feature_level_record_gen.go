@@ -0,0 +1,63 @@
+// Code generated by protogen from messages/FeatureLevelRecord.json. DO NOT EDIT.
+
+package protocol
+
+// FeatureLevelRecord is the metadata record of type 12, version 0.
+type FeatureLevelRecord struct {
+	// The feature name.
+	Name string
+	// The current finalized feature level of this feature for the cluster, a value of 0 means feature not supported.
+	FeatureLevel int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of FeatureLevelRecord
+func (*FeatureLevelRecord) APIKey() int16 { return 12 }
+
+// MinVersion returns the lowest supported version of FeatureLevelRecord
+func (*FeatureLevelRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of FeatureLevelRecord
+func (*FeatureLevelRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of FeatureLevelRecord uses the flexible encoding
+func (*FeatureLevelRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes FeatureLevelRecord in the given version
+func (m *FeatureLevelRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads FeatureLevelRecord in the given version
+func (m *FeatureLevelRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets FeatureLevelRecord to its default field values
+func (m *FeatureLevelRecord) Default() {
+	*m = FeatureLevelRecord{}
+}
+
+func (m *FeatureLevelRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutInt16(m.FeatureLevel)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FeatureLevelRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.FeatureLevel = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
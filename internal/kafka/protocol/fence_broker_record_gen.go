@@ -0,0 +1,63 @@
+// Code generated by protogen from messages/FenceBrokerRecord.json. DO NOT EDIT.
+
+package protocol
+
+// FenceBrokerRecord is the metadata record of type 8, version 0.
+type FenceBrokerRecord struct {
+	// The broker ID to fence. It will be removed from all ISRs.
+	Id int32
+	// The epoch of the broker to fence.
+	Epoch int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of FenceBrokerRecord
+func (*FenceBrokerRecord) APIKey() int16 { return 8 }
+
+// MinVersion returns the lowest supported version of FenceBrokerRecord
+func (*FenceBrokerRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of FenceBrokerRecord
+func (*FenceBrokerRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of FenceBrokerRecord uses the flexible encoding
+func (*FenceBrokerRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes FenceBrokerRecord in the given version
+func (m *FenceBrokerRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads FenceBrokerRecord in the given version
+func (m *FenceBrokerRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets FenceBrokerRecord to its default field values
+func (m *FenceBrokerRecord) Default() {
+	*m = FenceBrokerRecord{}
+}
+
+func (m *FenceBrokerRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.Id)
+	e.PutInt64(m.Epoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FenceBrokerRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Id = d.Int32()
+	m.Epoch = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,406 @@
+// Code generated by protogen from messages/FetchRequest.json. DO NOT EDIT.
+
+package protocol
+
+// FetchRequest is the request for API key 1, versions 0-16.
+type FetchRequest struct {
+	// The clusterId if known. This is used to validate metadata fetches prior to broker registration.
+	ClusterId *string
+	// The broker ID of the follower, of -1 if this request is from a consumer.
+	ReplicaId int32
+	// The state of the replica in the follower.
+	ReplicaState FetchRequestReplicaState
+	// The maximum time in milliseconds to wait for the response.
+	MaxWaitMs int32
+	// The minimum bytes to accumulate in the response.
+	MinBytes int32
+	// The maximum bytes to fetch.  See KIP-74 for cases where this limit may not be honored.
+	MaxBytes int32
+	// This setting controls the visibility of transactional records. Using READ_UNCOMMITTED (isolation_level = 0) makes all records visible. With READ_COMMITTED (isolation_level = 1), non-transactional and COMMITTED transactional records are visible. To be more concrete, READ_COMMITTED returns all data from offsets smaller than the current LSO (last stable offset), and enables the inclusion of the list of aborted transactions in the result, which allows consumers to discard ABORTED transactional records.
+	IsolationLevel int8
+	// The fetch session ID.
+	SessionId int32
+	// The fetch session epoch, which is used for ordering requests in a session.
+	SessionEpoch int32
+	// The topics to fetch.
+	Topics []FetchRequestFetchTopic
+	// In an incremental fetch request, the partitions to remove.
+	ForgottenTopicsData []FetchRequestForgottenTopic
+	// Rack ID of the consumer making this request.
+	RackId string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of FetchRequest
+func (*FetchRequest) APIKey() int16 { return 1 }
+
+// MinVersion returns the lowest supported version of FetchRequest
+func (*FetchRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of FetchRequest
+func (*FetchRequest) MaxVersion() int16 { return 16 }
+
+// IsFlexible reports whether the given version of FetchRequest uses the flexible encoding
+func (*FetchRequest) IsFlexible(version int16) bool { return version >= 12 }
+
+// Encode writes FetchRequest in the given version
+func (m *FetchRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads FetchRequest in the given version
+func (m *FetchRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets FetchRequest to its default field values
+func (m *FetchRequest) Default() {
+	*m = FetchRequest{}
+	m.ReplicaId = -1
+	m.ReplicaState.Default()
+	m.MaxBytes = 0x7fffffff
+	m.SessionEpoch = -1
+}
+
+func (m *FetchRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version <= 14 {
+		e.PutInt32(m.ReplicaId)
+	}
+	e.PutInt32(m.MaxWaitMs)
+	e.PutInt32(m.MinBytes)
+	if version >= 3 {
+		e.PutInt32(m.MaxBytes)
+	}
+	if version >= 4 {
+		e.PutInt8(m.IsolationLevel)
+	}
+	if version >= 7 {
+		e.PutInt32(m.SessionId)
+	}
+	if version >= 7 {
+		e.PutInt32(m.SessionEpoch)
+	}
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if version >= 7 {
+		e.PutArrayLength(len(m.ForgottenTopicsData), flexible)
+		for i := range m.ForgottenTopicsData {
+			m.ForgottenTopicsData[i].encode(e, version, flexible)
+		}
+	}
+	if version >= 11 {
+		e.PutString(m.RackId, flexible)
+	}
+	if flexible {
+		var tagged []TaggedField
+		if m.ClusterId != nil {
+			te := NewEncoder(0)
+			te.PutNullableString(m.ClusterId, true)
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		if (version >= 15) && !m.ReplicaState.isDefault() {
+			te := NewEncoder(0)
+			m.ReplicaState.encode(te, version, flexible)
+			tagged = append(tagged, TaggedField{Tag: 1, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *FetchRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version <= 14 {
+		m.ReplicaId = d.Int32()
+	}
+	m.MaxWaitMs = d.Int32()
+	m.MinBytes = d.Int32()
+	if version >= 3 {
+		m.MaxBytes = d.Int32()
+	}
+	if version >= 4 {
+		m.IsolationLevel = d.Int8()
+	}
+	if version >= 7 {
+		m.SessionId = d.Int32()
+	}
+	if version >= 7 {
+		m.SessionEpoch = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]FetchRequestFetchTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if version >= 7 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.ForgottenTopicsData = make([]FetchRequestForgottenTopic, n)
+			for i := range m.ForgottenTopicsData {
+				m.ForgottenTopicsData[i].decode(d, version, flexible)
+			}
+		} else {
+			m.ForgottenTopicsData = nil
+		}
+	}
+	if version >= 11 {
+		m.RackId = d.String(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				m.ClusterId = fd.NullableString(true)
+			case 1:
+				if !(version >= 15) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				m.ReplicaState.decode(fd, version, flexible)
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchRequestReplicaState is the type of FetchRequest.ReplicaState.
+type FetchRequestReplicaState struct {
+	// The replica ID of the follower, or -1 if this request is from a consumer.
+	ReplicaId int32
+	// The epoch of this follower, or -1 if not available.
+	ReplicaEpoch int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchRequestReplicaState to its default field values
+func (m *FetchRequestReplicaState) Default() {
+	*m = FetchRequestReplicaState{}
+	m.ReplicaId = -1
+	m.ReplicaEpoch = -1
+}
+
+// isDefault reports whether FetchRequestReplicaState holds only default field values
+func (m *FetchRequestReplicaState) isDefault() bool {
+	if m.ReplicaId != -1 {
+		return false
+	}
+	if m.ReplicaEpoch != -1 {
+		return false
+	}
+	return len(m.UnknownTaggedFields) == 0
+}
+
+func (m *FetchRequestReplicaState) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ReplicaId)
+	e.PutInt64(m.ReplicaEpoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchRequestReplicaState) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ReplicaId = d.Int32()
+	m.ReplicaEpoch = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchRequestFetchTopic is an element of FetchRequest.Topics.
+type FetchRequestFetchTopic struct {
+	// The name of the topic to fetch.
+	Topic string
+	// The unique topic ID.
+	TopicId UUID
+	// The partitions to fetch.
+	Partitions []FetchRequestFetchPartition
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchRequestFetchTopic to its default field values
+func (m *FetchRequestFetchTopic) Default() {
+	*m = FetchRequestFetchTopic{}
+}
+
+func (m *FetchRequestFetchTopic) encode(e *Encoder, version int16, flexible bool) {
+	if version <= 12 {
+		e.PutString(m.Topic, flexible)
+	}
+	if version >= 13 {
+		e.PutUUID(m.TopicId)
+	}
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchRequestFetchTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version <= 12 {
+		m.Topic = d.String(flexible)
+	}
+	if version >= 13 {
+		m.TopicId = d.UUID()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]FetchRequestFetchPartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchRequestForgottenTopic is an element of FetchRequest.ForgottenTopicsData.
+type FetchRequestForgottenTopic struct {
+	// The topic name.
+	Topic string
+	// The unique topic ID.
+	TopicId UUID
+	// The partitions indexes to forget.
+	Partitions []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchRequestForgottenTopic to its default field values
+func (m *FetchRequestForgottenTopic) Default() {
+	*m = FetchRequestForgottenTopic{}
+}
+
+func (m *FetchRequestForgottenTopic) encode(e *Encoder, version int16, flexible bool) {
+	if version <= 12 {
+		e.PutString(m.Topic, flexible)
+	}
+	if version >= 13 {
+		e.PutUUID(m.TopicId)
+	}
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		e.PutInt32(m.Partitions[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchRequestForgottenTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version <= 12 {
+		m.Topic = d.String(flexible)
+	}
+	if version >= 13 {
+		m.TopicId = d.UUID()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]int32, n)
+		for i := range m.Partitions {
+			m.Partitions[i] = d.Int32()
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchRequestFetchPartition is an element of FetchRequestFetchTopic.Partitions.
+type FetchRequestFetchPartition struct {
+	// The partition index.
+	Partition int32
+	// The current leader epoch of the partition.
+	CurrentLeaderEpoch int32
+	// The message offset.
+	FetchOffset int64
+	// The epoch of the last fetched record or -1 if there is none.
+	LastFetchedEpoch int32
+	// The earliest available offset of the follower replica.  The field is only used when the request is sent by the follower.
+	LogStartOffset int64
+	// The maximum bytes to fetch from this partition.  See KIP-74 for cases where this limit may not be honored.
+	PartitionMaxBytes int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchRequestFetchPartition to its default field values
+func (m *FetchRequestFetchPartition) Default() {
+	*m = FetchRequestFetchPartition{}
+	m.CurrentLeaderEpoch = -1
+	m.LastFetchedEpoch = -1
+	m.LogStartOffset = -1
+}
+
+func (m *FetchRequestFetchPartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.Partition)
+	if version >= 9 {
+		e.PutInt32(m.CurrentLeaderEpoch)
+	}
+	e.PutInt64(m.FetchOffset)
+	if version >= 12 {
+		e.PutInt32(m.LastFetchedEpoch)
+	}
+	if version >= 5 {
+		e.PutInt64(m.LogStartOffset)
+	}
+	e.PutInt32(m.PartitionMaxBytes)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchRequestFetchPartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Partition = d.Int32()
+	if version >= 9 {
+		m.CurrentLeaderEpoch = d.Int32()
+	}
+	m.FetchOffset = d.Int64()
+	if version >= 12 {
+		m.LastFetchedEpoch = d.Int32()
+	}
+	if version >= 5 {
+		m.LogStartOffset = d.Int64()
+	}
+	m.PartitionMaxBytes = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
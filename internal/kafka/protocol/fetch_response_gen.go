@@ -0,0 +1,533 @@
+// Code generated by protogen from messages/FetchResponse.json. DO NOT EDIT.
+
+package protocol
+
+// FetchResponse is the response for API key 1, versions 0-16.
+type FetchResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The top level response error code.
+	ErrorCode int16
+	// The fetch session ID, or 0 if this is not part of a fetch session.
+	SessionId int32
+	// The response topics.
+	Responses []FetchResponseFetchableTopicResponse
+	// Endpoints for all current-leaders enumerated in PartitionData, with errors NOT_LEADER_OR_FOLLOWER.
+	NodeEndpoints []FetchResponseNodeEndpoint
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of FetchResponse
+func (*FetchResponse) APIKey() int16 { return 1 }
+
+// MinVersion returns the lowest supported version of FetchResponse
+func (*FetchResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of FetchResponse
+func (*FetchResponse) MaxVersion() int16 { return 16 }
+
+// IsFlexible reports whether the given version of FetchResponse uses the flexible encoding
+func (*FetchResponse) IsFlexible(version int16) bool { return version >= 12 }
+
+// Encode writes FetchResponse in the given version
+func (m *FetchResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads FetchResponse in the given version
+func (m *FetchResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets FetchResponse to its default field values
+func (m *FetchResponse) Default() {
+	*m = FetchResponse{}
+}
+
+func (m *FetchResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	if version >= 7 {
+		e.PutInt16(m.ErrorCode)
+	}
+	if version >= 7 {
+		e.PutInt32(m.SessionId)
+	}
+	e.PutArrayLength(len(m.Responses), flexible)
+	for i := range m.Responses {
+		m.Responses[i].encode(e, version, flexible)
+	}
+	if flexible {
+		var tagged []TaggedField
+		if (version >= 16) && len(m.NodeEndpoints) > 0 {
+			te := NewEncoder(0)
+			te.PutArrayLength(len(m.NodeEndpoints), true)
+			for i := range m.NodeEndpoints {
+				m.NodeEndpoints[i].encode(te, version, flexible)
+			}
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *FetchResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if version >= 7 {
+		m.ErrorCode = d.Int16()
+	}
+	if version >= 7 {
+		m.SessionId = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Responses = make([]FetchResponseFetchableTopicResponse, n)
+		for i := range m.Responses {
+			m.Responses[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Responses = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				if !(version >= 16) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.NodeEndpoints = make([]FetchResponseNodeEndpoint, n)
+					for i := range m.NodeEndpoints {
+						m.NodeEndpoints[i].decode(fd, version, flexible)
+					}
+				} else {
+					m.NodeEndpoints = nil
+				}
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchResponseFetchableTopicResponse is an element of FetchResponse.Responses.
+type FetchResponseFetchableTopicResponse struct {
+	// The topic name.
+	Topic string
+	// The unique topic ID.
+	TopicId UUID
+	// The topic partitions.
+	Partitions []FetchResponsePartitionData
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchResponseFetchableTopicResponse to its default field values
+func (m *FetchResponseFetchableTopicResponse) Default() {
+	*m = FetchResponseFetchableTopicResponse{}
+}
+
+func (m *FetchResponseFetchableTopicResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version <= 12 {
+		e.PutString(m.Topic, flexible)
+	}
+	if version >= 13 {
+		e.PutUUID(m.TopicId)
+	}
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchResponseFetchableTopicResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version <= 12 {
+		m.Topic = d.String(flexible)
+	}
+	if version >= 13 {
+		m.TopicId = d.UUID()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]FetchResponsePartitionData, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchResponseNodeEndpoint is an element of FetchResponse.NodeEndpoints.
+type FetchResponseNodeEndpoint struct {
+	// The ID of the associated node.
+	NodeId int32
+	// The node's hostname.
+	Host string
+	// The node's port.
+	Port int32
+	// The rack of the node, or null if it has not been assigned to a rack.
+	Rack *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchResponseNodeEndpoint to its default field values
+func (m *FetchResponseNodeEndpoint) Default() {
+	*m = FetchResponseNodeEndpoint{}
+}
+
+func (m *FetchResponseNodeEndpoint) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.NodeId)
+	e.PutString(m.Host, flexible)
+	e.PutInt32(m.Port)
+	e.PutNullableString(m.Rack, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchResponseNodeEndpoint) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.NodeId = d.Int32()
+	m.Host = d.String(flexible)
+	m.Port = d.Int32()
+	m.Rack = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchResponsePartitionData is an element of FetchResponseFetchableTopicResponse.Partitions.
+type FetchResponsePartitionData struct {
+	// The partition index.
+	PartitionIndex int32
+	// The error code, or 0 if there was no fetch error.
+	ErrorCode int16
+	// The current high water mark.
+	HighWatermark int64
+	// The last stable offset (or LSO) of the partition. This is the last offset such that the state of all transactional records prior to this offset have been decided (ABORTED or COMMITTED).
+	LastStableOffset int64
+	// The current log start offset.
+	LogStartOffset int64
+	// In case divergence is detected based on the `LastFetchedEpoch` and `FetchOffset` in the request, this field indicates the largest epoch and its end offset such that subsequent records are known to diverge.
+	DivergingEpoch FetchResponseEpochEndOffset
+	// The current leader of the partition.
+	CurrentLeader FetchResponseLeaderIdAndEpoch
+	// In the case of fetching an offset less than the LogStartOffset, this is the end offset and epoch that should be used in the FetchSnapshot request.
+	SnapshotId FetchResponseSnapshotId
+	// The aborted transactions.
+	AbortedTransactions []FetchResponseAbortedTransaction
+	// The preferred read replica for the consumer to use on its next fetch request.
+	PreferredReadReplica int32
+	// The record data.
+	Records []byte
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchResponsePartitionData to its default field values
+func (m *FetchResponsePartitionData) Default() {
+	*m = FetchResponsePartitionData{}
+	m.LastStableOffset = -1
+	m.LogStartOffset = -1
+	m.DivergingEpoch.Default()
+	m.CurrentLeader.Default()
+	m.SnapshotId.Default()
+	m.PreferredReadReplica = -1
+}
+
+func (m *FetchResponsePartitionData) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt16(m.ErrorCode)
+	e.PutInt64(m.HighWatermark)
+	if version >= 4 {
+		e.PutInt64(m.LastStableOffset)
+	}
+	if version >= 5 {
+		e.PutInt64(m.LogStartOffset)
+	}
+	if version >= 4 {
+		if m.AbortedTransactions == nil {
+			e.PutArrayLength(-1, flexible)
+		} else {
+			e.PutArrayLength(len(m.AbortedTransactions), flexible)
+			for i := range m.AbortedTransactions {
+				m.AbortedTransactions[i].encode(e, version, flexible)
+			}
+		}
+	}
+	if version >= 11 {
+		e.PutInt32(m.PreferredReadReplica)
+	}
+	e.PutNullableBytes(m.Records, flexible)
+	if flexible {
+		var tagged []TaggedField
+		if !m.DivergingEpoch.isDefault() {
+			te := NewEncoder(0)
+			m.DivergingEpoch.encode(te, version, flexible)
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		if !m.CurrentLeader.isDefault() {
+			te := NewEncoder(0)
+			m.CurrentLeader.encode(te, version, flexible)
+			tagged = append(tagged, TaggedField{Tag: 1, Data: te.Bytes()})
+		}
+		if !m.SnapshotId.isDefault() {
+			te := NewEncoder(0)
+			m.SnapshotId.encode(te, version, flexible)
+			tagged = append(tagged, TaggedField{Tag: 2, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *FetchResponsePartitionData) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.ErrorCode = d.Int16()
+	m.HighWatermark = d.Int64()
+	if version >= 4 {
+		m.LastStableOffset = d.Int64()
+	}
+	if version >= 5 {
+		m.LogStartOffset = d.Int64()
+	}
+	if version >= 4 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.AbortedTransactions = make([]FetchResponseAbortedTransaction, n)
+			for i := range m.AbortedTransactions {
+				m.AbortedTransactions[i].decode(d, version, flexible)
+			}
+		} else {
+			m.AbortedTransactions = nil
+		}
+	}
+	if version >= 11 {
+		m.PreferredReadReplica = d.Int32()
+	}
+	m.Records = d.NullableBytes(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				m.DivergingEpoch.decode(fd, version, flexible)
+			case 1:
+				m.CurrentLeader.decode(fd, version, flexible)
+			case 2:
+				m.SnapshotId.decode(fd, version, flexible)
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchResponseEpochEndOffset is the type of FetchResponsePartitionData.DivergingEpoch.
+type FetchResponseEpochEndOffset struct {
+	// The largest epoch.
+	Epoch int32
+	// The end offset of the epoch.
+	EndOffset int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchResponseEpochEndOffset to its default field values
+func (m *FetchResponseEpochEndOffset) Default() {
+	*m = FetchResponseEpochEndOffset{}
+	m.Epoch = -1
+	m.EndOffset = -1
+}
+
+// isDefault reports whether FetchResponseEpochEndOffset holds only default field values
+func (m *FetchResponseEpochEndOffset) isDefault() bool {
+	if m.Epoch != -1 {
+		return false
+	}
+	if m.EndOffset != -1 {
+		return false
+	}
+	return len(m.UnknownTaggedFields) == 0
+}
+
+func (m *FetchResponseEpochEndOffset) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.Epoch)
+	e.PutInt64(m.EndOffset)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchResponseEpochEndOffset) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Epoch = d.Int32()
+	m.EndOffset = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchResponseLeaderIdAndEpoch is the type of FetchResponsePartitionData.CurrentLeader.
+type FetchResponseLeaderIdAndEpoch struct {
+	// The ID of the current leader or -1 if the leader is unknown.
+	LeaderId int32
+	// The latest known leader epoch.
+	LeaderEpoch int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchResponseLeaderIdAndEpoch to its default field values
+func (m *FetchResponseLeaderIdAndEpoch) Default() {
+	*m = FetchResponseLeaderIdAndEpoch{}
+	m.LeaderId = -1
+	m.LeaderEpoch = -1
+}
+
+// isDefault reports whether FetchResponseLeaderIdAndEpoch holds only default field values
+func (m *FetchResponseLeaderIdAndEpoch) isDefault() bool {
+	if m.LeaderId != -1 {
+		return false
+	}
+	if m.LeaderEpoch != -1 {
+		return false
+	}
+	return len(m.UnknownTaggedFields) == 0
+}
+
+func (m *FetchResponseLeaderIdAndEpoch) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.LeaderId)
+	e.PutInt32(m.LeaderEpoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchResponseLeaderIdAndEpoch) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.LeaderId = d.Int32()
+	m.LeaderEpoch = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchResponseSnapshotId is the type of FetchResponsePartitionData.SnapshotId.
+type FetchResponseSnapshotId struct {
+	// The end offset of the epoch.
+	EndOffset int64
+	// The largest epoch.
+	Epoch int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchResponseSnapshotId to its default field values
+func (m *FetchResponseSnapshotId) Default() {
+	*m = FetchResponseSnapshotId{}
+	m.EndOffset = -1
+	m.Epoch = -1
+}
+
+// isDefault reports whether FetchResponseSnapshotId holds only default field values
+func (m *FetchResponseSnapshotId) isDefault() bool {
+	if m.EndOffset != -1 {
+		return false
+	}
+	if m.Epoch != -1 {
+		return false
+	}
+	return len(m.UnknownTaggedFields) == 0
+}
+
+func (m *FetchResponseSnapshotId) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt64(m.EndOffset)
+	e.PutInt32(m.Epoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchResponseSnapshotId) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.EndOffset = d.Int64()
+	m.Epoch = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FetchResponseAbortedTransaction is an element of FetchResponsePartitionData.AbortedTransactions.
+type FetchResponseAbortedTransaction struct {
+	// The producer id associated with the aborted transaction.
+	ProducerId int64
+	// The first offset in the aborted transaction.
+	FirstOffset int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FetchResponseAbortedTransaction to its default field values
+func (m *FetchResponseAbortedTransaction) Default() {
+	*m = FetchResponseAbortedTransaction{}
+}
+
+func (m *FetchResponseAbortedTransaction) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt64(m.ProducerId)
+	e.PutInt64(m.FirstOffset)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FetchResponseAbortedTransaction) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ProducerId = d.Int64()
+	m.FirstOffset = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Code generated by protogen from messages/FindCoordinatorRequest.json. DO NOT EDIT.
+
+package protocol
+
+// FindCoordinatorRequest is the request for API key 10, versions 0-5.
+type FindCoordinatorRequest struct {
+	// The coordinator key.
+	Key string
+	// The coordinator key type. (group, transaction, share).
+	KeyType int8
+	// The coordinator keys.
+	CoordinatorKeys []string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of FindCoordinatorRequest
+func (*FindCoordinatorRequest) APIKey() int16 { return 10 }
+
+// MinVersion returns the lowest supported version of FindCoordinatorRequest
+func (*FindCoordinatorRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of FindCoordinatorRequest
+func (*FindCoordinatorRequest) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of FindCoordinatorRequest uses the flexible encoding
+func (*FindCoordinatorRequest) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes FindCoordinatorRequest in the given version
+func (m *FindCoordinatorRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads FindCoordinatorRequest in the given version
+func (m *FindCoordinatorRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets FindCoordinatorRequest to its default field values
+func (m *FindCoordinatorRequest) Default() {
+	*m = FindCoordinatorRequest{}
+}
+
+func (m *FindCoordinatorRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version <= 3 {
+		e.PutString(m.Key, flexible)
+	}
+	if version >= 1 {
+		e.PutInt8(m.KeyType)
+	}
+	if version >= 4 {
+		e.PutArrayLength(len(m.CoordinatorKeys), flexible)
+		for i := range m.CoordinatorKeys {
+			e.PutString(m.CoordinatorKeys[i], flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FindCoordinatorRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version <= 3 {
+		m.Key = d.String(flexible)
+	}
+	if version >= 1 {
+		m.KeyType = d.Int8()
+	}
+	if version >= 4 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.CoordinatorKeys = make([]string, n)
+			for i := range m.CoordinatorKeys {
+				m.CoordinatorKeys[i] = d.String(flexible)
+			}
+		} else {
+			m.CoordinatorKeys = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Code generated by protogen from messages/FindCoordinatorResponse.json. DO NOT EDIT.
+
+package protocol
+
+// FindCoordinatorResponse is the response for API key 10, versions 0-5.
+type FindCoordinatorResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// The error message, or null if there was no error.
+	ErrorMessage *string
+	// The node id.
+	NodeId int32
+	// The host name.
+	Host string
+	// The port.
+	Port int32
+	// Each coordinator result in the response.
+	Coordinators []FindCoordinatorResponseCoordinator
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of FindCoordinatorResponse
+func (*FindCoordinatorResponse) APIKey() int16 { return 10 }
+
+// MinVersion returns the lowest supported version of FindCoordinatorResponse
+func (*FindCoordinatorResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of FindCoordinatorResponse
+func (*FindCoordinatorResponse) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of FindCoordinatorResponse uses the flexible encoding
+func (*FindCoordinatorResponse) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes FindCoordinatorResponse in the given version
+func (m *FindCoordinatorResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads FindCoordinatorResponse in the given version
+func (m *FindCoordinatorResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets FindCoordinatorResponse to its default field values
+func (m *FindCoordinatorResponse) Default() {
+	*m = FindCoordinatorResponse{}
+}
+
+func (m *FindCoordinatorResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	if version <= 3 {
+		e.PutInt16(m.ErrorCode)
+	}
+	if version >= 1 && version <= 3 {
+		e.PutNullableString(m.ErrorMessage, flexible)
+	}
+	if version <= 3 {
+		e.PutInt32(m.NodeId)
+	}
+	if version <= 3 {
+		e.PutString(m.Host, flexible)
+	}
+	if version <= 3 {
+		e.PutInt32(m.Port)
+	}
+	if version >= 4 {
+		e.PutArrayLength(len(m.Coordinators), flexible)
+		for i := range m.Coordinators {
+			m.Coordinators[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FindCoordinatorResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if version <= 3 {
+		m.ErrorCode = d.Int16()
+	}
+	if version >= 1 && version <= 3 {
+		m.ErrorMessage = d.NullableString(flexible)
+	}
+	if version <= 3 {
+		m.NodeId = d.Int32()
+	}
+	if version <= 3 {
+		m.Host = d.String(flexible)
+	}
+	if version <= 3 {
+		m.Port = d.Int32()
+	}
+	if version >= 4 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Coordinators = make([]FindCoordinatorResponseCoordinator, n)
+			for i := range m.Coordinators {
+				m.Coordinators[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Coordinators = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// FindCoordinatorResponseCoordinator is an element of FindCoordinatorResponse.Coordinators.
+type FindCoordinatorResponseCoordinator struct {
+	// The coordinator key.
+	Key string
+	// The node id.
+	NodeId int32
+	// The host name.
+	Host string
+	// The port.
+	Port int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// The error message, or null if there was no error.
+	ErrorMessage *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets FindCoordinatorResponseCoordinator to its default field values
+func (m *FindCoordinatorResponseCoordinator) Default() {
+	*m = FindCoordinatorResponseCoordinator{}
+}
+
+func (m *FindCoordinatorResponseCoordinator) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Key, flexible)
+	e.PutInt32(m.NodeId)
+	e.PutString(m.Host, flexible)
+	e.PutInt32(m.Port)
+	e.PutInt16(m.ErrorCode)
+	e.PutNullableString(m.ErrorMessage, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *FindCoordinatorResponseCoordinator) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Key = d.String(flexible)
+	m.NodeId = d.Int32()
+	m.Host = d.String(flexible)
+	m.Port = d.Int32()
+	m.ErrorCode = d.Int16()
+	m.ErrorMessage = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package protocol
+
+// The *_gen.go files in this package are generated from the Kafka JSON
+// message specs in the messages directory. After adding or editing a spec,
+// regenerate them with:
+//
+//	go generate ./internal/kafka/protocol
+
+//go:generate go run ../../../cmd/protogen -dir messages -out .
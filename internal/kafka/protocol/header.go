@@ -0,0 +1,53 @@
+package protocol
+
+import "fmt"
+
+// apiSpec describes the versions of a request defined by its message spec
+type apiSpec struct {
+	name                 string
+	minVersion           int16
+	maxVersion           int16
+	firstFlexibleVersion int16 // -1 when no version is flexible
+}
+
+// APIName returns the name of an API key, for logging
+func APIName(apiKey int16) string {
+	if spec, ok := apiSpecs[apiKey]; ok {
+		return spec.name
+	}
+	return fmt.Sprintf("Unknown(%d)", apiKey)
+}
+
+// IsFlexibleVersion reports whether the given API version uses the flexible
+// encoding. Versions past the newest known one are treated like it, which
+// matches how clients encode requests the broker does not yet support.
+func IsFlexibleVersion(apiKey, apiVersion int16) bool {
+	spec, ok := apiSpecs[apiKey]
+	if !ok || spec.firstFlexibleVersion < 0 {
+		return false
+	}
+	return apiVersion >= spec.firstFlexibleVersion
+}
+
+// RequestHeaderVersion returns the request header version used by the given
+// API version: v2 adds tagged fields for flexible versions, v1 otherwise
+func RequestHeaderVersion(apiKey, apiVersion int16) int16 {
+	if IsFlexibleVersion(apiKey, apiVersion) {
+		return 2
+	}
+	return 1
+}
+
+// ResponseHeaderVersion returns the response header version for the given
+// API version: v1 adds tagged fields for flexible versions, v0 otherwise.
+// ApiVersions responses always use v0 so that clients can parse them before
+// they know which versions the broker supports.
+func ResponseHeaderVersion(apiKey, apiVersion int16) int16 {
+	if apiKey == ApiVersionsKey {
+		return 0
+	}
+	if IsFlexibleVersion(apiKey, apiVersion) {
+		return 1
+	}
+	return 0
+}
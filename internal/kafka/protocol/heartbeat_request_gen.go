@@ -0,0 +1,75 @@
+// Code generated by protogen from messages/HeartbeatRequest.json. DO NOT EDIT.
+
+package protocol
+
+// HeartbeatRequest is the request for API key 12, versions 0-4.
+type HeartbeatRequest struct {
+	// The group id.
+	GroupId string
+	// The generation of the group.
+	GenerationId int32
+	// The member ID.
+	MemberId string
+	// The unique identifier of the consumer instance provided by end user.
+	GroupInstanceId *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of HeartbeatRequest
+func (*HeartbeatRequest) APIKey() int16 { return 12 }
+
+// MinVersion returns the lowest supported version of HeartbeatRequest
+func (*HeartbeatRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of HeartbeatRequest
+func (*HeartbeatRequest) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of HeartbeatRequest uses the flexible encoding
+func (*HeartbeatRequest) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes HeartbeatRequest in the given version
+func (m *HeartbeatRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads HeartbeatRequest in the given version
+func (m *HeartbeatRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets HeartbeatRequest to its default field values
+func (m *HeartbeatRequest) Default() {
+	*m = HeartbeatRequest{}
+}
+
+func (m *HeartbeatRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutInt32(m.GenerationId)
+	e.PutString(m.MemberId, flexible)
+	if version >= 3 {
+		e.PutNullableString(m.GroupInstanceId, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *HeartbeatRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	m.GenerationId = d.Int32()
+	m.MemberId = d.String(flexible)
+	if version >= 3 {
+		m.GroupInstanceId = d.NullableString(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Code generated by protogen from messages/HeartbeatResponse.json. DO NOT EDIT.
+
+package protocol
+
+// HeartbeatResponse is the response for API key 12, versions 0-4.
+type HeartbeatResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of HeartbeatResponse
+func (*HeartbeatResponse) APIKey() int16 { return 12 }
+
+// MinVersion returns the lowest supported version of HeartbeatResponse
+func (*HeartbeatResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of HeartbeatResponse
+func (*HeartbeatResponse) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of HeartbeatResponse uses the flexible encoding
+func (*HeartbeatResponse) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes HeartbeatResponse in the given version
+func (m *HeartbeatResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads HeartbeatResponse in the given version
+func (m *HeartbeatResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets HeartbeatResponse to its default field values
+func (m *HeartbeatResponse) Default() {
+	*m = HeartbeatResponse{}
+}
+
+func (m *HeartbeatResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *HeartbeatResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
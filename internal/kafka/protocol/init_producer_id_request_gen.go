@@ -0,0 +1,81 @@
+// Code generated by protogen from messages/InitProducerIdRequest.json. DO NOT EDIT.
+
+package protocol
+
+// InitProducerIdRequest is the request for API key 22, versions 0-5.
+type InitProducerIdRequest struct {
+	// The transactional id, or null if the producer is not transactional.
+	TransactionalId *string
+	// The time in ms to wait before aborting idle transactions sent by this producer. This is only relevant if a TransactionalId has been defined.
+	TransactionTimeoutMs int32
+	// The producer id. This is used to disambiguate requests if a transactional id is reused following its expiration.
+	ProducerId int64
+	// The producer's current epoch. This will be checked against the producer epoch on the broker, and the request will return an error if they do not match.
+	ProducerEpoch int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of InitProducerIdRequest
+func (*InitProducerIdRequest) APIKey() int16 { return 22 }
+
+// MinVersion returns the lowest supported version of InitProducerIdRequest
+func (*InitProducerIdRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of InitProducerIdRequest
+func (*InitProducerIdRequest) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of InitProducerIdRequest uses the flexible encoding
+func (*InitProducerIdRequest) IsFlexible(version int16) bool { return version >= 2 }
+
+// Encode writes InitProducerIdRequest in the given version
+func (m *InitProducerIdRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads InitProducerIdRequest in the given version
+func (m *InitProducerIdRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets InitProducerIdRequest to its default field values
+func (m *InitProducerIdRequest) Default() {
+	*m = InitProducerIdRequest{}
+	m.ProducerId = -1
+	m.ProducerEpoch = -1
+}
+
+func (m *InitProducerIdRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutNullableString(m.TransactionalId, flexible)
+	e.PutInt32(m.TransactionTimeoutMs)
+	if version >= 3 {
+		e.PutInt64(m.ProducerId)
+	}
+	if version >= 3 {
+		e.PutInt16(m.ProducerEpoch)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *InitProducerIdRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TransactionalId = d.NullableString(flexible)
+	m.TransactionTimeoutMs = d.Int32()
+	if version >= 3 {
+		m.ProducerId = d.Int64()
+	}
+	if version >= 3 {
+		m.ProducerEpoch = d.Int16()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Code generated by protogen from messages/InitProducerIdResponse.json. DO NOT EDIT.
+
+package protocol
+
+// InitProducerIdResponse is the response for API key 22, versions 0-5.
+type InitProducerIdResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// The current producer id.
+	ProducerId int64
+	// The current epoch associated with the producer id.
+	ProducerEpoch int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of InitProducerIdResponse
+func (*InitProducerIdResponse) APIKey() int16 { return 22 }
+
+// MinVersion returns the lowest supported version of InitProducerIdResponse
+func (*InitProducerIdResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of InitProducerIdResponse
+func (*InitProducerIdResponse) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of InitProducerIdResponse uses the flexible encoding
+func (*InitProducerIdResponse) IsFlexible(version int16) bool { return version >= 2 }
+
+// Encode writes InitProducerIdResponse in the given version
+func (m *InitProducerIdResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads InitProducerIdResponse in the given version
+func (m *InitProducerIdResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets InitProducerIdResponse to its default field values
+func (m *InitProducerIdResponse) Default() {
+	*m = InitProducerIdResponse{}
+	m.ProducerId = -1
+}
+
+func (m *InitProducerIdResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutInt16(m.ErrorCode)
+	e.PutInt64(m.ProducerId)
+	e.PutInt16(m.ProducerEpoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *InitProducerIdResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	m.ErrorCode = d.Int16()
+	m.ProducerId = d.Int64()
+	m.ProducerEpoch = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
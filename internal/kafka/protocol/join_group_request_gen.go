@@ -0,0 +1,143 @@
+// Code generated by protogen from messages/JoinGroupRequest.json. DO NOT EDIT.
+
+package protocol
+
+// JoinGroupRequest is the request for API key 11, versions 2-9.
+type JoinGroupRequest struct {
+	// The group identifier.
+	GroupId string
+	// The coordinator considers the consumer dead if it receives no heartbeat after this timeout in milliseconds.
+	SessionTimeoutMs int32
+	// The maximum time in milliseconds that the coordinator will wait for each member to rejoin when rebalancing the group.
+	RebalanceTimeoutMs int32
+	// The member id assigned by the group coordinator.
+	MemberId string
+	// The unique identifier of the consumer instance provided by end user.
+	GroupInstanceId *string
+	// The unique name the for class of protocols implemented by the group we want to join.
+	ProtocolType string
+	// The list of protocols that the member supports.
+	Protocols []JoinGroupRequestProtocol
+	// The reason why the member (re-)joins the group.
+	Reason *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of JoinGroupRequest
+func (*JoinGroupRequest) APIKey() int16 { return 11 }
+
+// MinVersion returns the lowest supported version of JoinGroupRequest
+func (*JoinGroupRequest) MinVersion() int16 { return 2 }
+
+// MaxVersion returns the highest supported version of JoinGroupRequest
+func (*JoinGroupRequest) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of JoinGroupRequest uses the flexible encoding
+func (*JoinGroupRequest) IsFlexible(version int16) bool { return version >= 6 }
+
+// Encode writes JoinGroupRequest in the given version
+func (m *JoinGroupRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads JoinGroupRequest in the given version
+func (m *JoinGroupRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets JoinGroupRequest to its default field values
+func (m *JoinGroupRequest) Default() {
+	*m = JoinGroupRequest{}
+	m.RebalanceTimeoutMs = -1
+}
+
+func (m *JoinGroupRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutInt32(m.SessionTimeoutMs)
+	e.PutInt32(m.RebalanceTimeoutMs)
+	e.PutString(m.MemberId, flexible)
+	if version >= 5 {
+		e.PutNullableString(m.GroupInstanceId, flexible)
+	}
+	e.PutString(m.ProtocolType, flexible)
+	e.PutArrayLength(len(m.Protocols), flexible)
+	for i := range m.Protocols {
+		m.Protocols[i].encode(e, version, flexible)
+	}
+	if version >= 8 {
+		e.PutNullableString(m.Reason, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *JoinGroupRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	m.SessionTimeoutMs = d.Int32()
+	m.RebalanceTimeoutMs = d.Int32()
+	m.MemberId = d.String(flexible)
+	if version >= 5 {
+		m.GroupInstanceId = d.NullableString(flexible)
+	}
+	m.ProtocolType = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Protocols = make([]JoinGroupRequestProtocol, n)
+		for i := range m.Protocols {
+			m.Protocols[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Protocols = nil
+	}
+	if version >= 8 {
+		m.Reason = d.NullableString(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// JoinGroupRequestProtocol is an element of JoinGroupRequest.Protocols.
+type JoinGroupRequestProtocol struct {
+	// The protocol name.
+	Name string
+	// The protocol metadata.
+	Metadata []byte
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets JoinGroupRequestProtocol to its default field values
+func (m *JoinGroupRequestProtocol) Default() {
+	*m = JoinGroupRequestProtocol{}
+}
+
+func (m *JoinGroupRequestProtocol) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutBytes(m.Metadata, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *JoinGroupRequestProtocol) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.Metadata = d.Bytes(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
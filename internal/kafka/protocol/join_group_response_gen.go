@@ -0,0 +1,164 @@
+// Code generated by protogen from messages/JoinGroupResponse.json. DO NOT EDIT.
+
+package protocol
+
+// JoinGroupResponse is the response for API key 11, versions 2-9.
+type JoinGroupResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// The generation ID of the group.
+	GenerationId int32
+	// The group protocol name.
+	ProtocolType *string
+	// The group protocol selected by the coordinator.
+	ProtocolName *string
+	// The leader of the group.
+	Leader string
+	// True if the leader must skip running the assignment.
+	SkipAssignment bool
+	// The member ID assigned by the group coordinator.
+	MemberId string
+	// The group members.
+	Members []JoinGroupResponseMember
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of JoinGroupResponse
+func (*JoinGroupResponse) APIKey() int16 { return 11 }
+
+// MinVersion returns the lowest supported version of JoinGroupResponse
+func (*JoinGroupResponse) MinVersion() int16 { return 2 }
+
+// MaxVersion returns the highest supported version of JoinGroupResponse
+func (*JoinGroupResponse) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of JoinGroupResponse uses the flexible encoding
+func (*JoinGroupResponse) IsFlexible(version int16) bool { return version >= 6 }
+
+// Encode writes JoinGroupResponse in the given version
+func (m *JoinGroupResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads JoinGroupResponse in the given version
+func (m *JoinGroupResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets JoinGroupResponse to its default field values
+func (m *JoinGroupResponse) Default() {
+	*m = JoinGroupResponse{}
+	m.GenerationId = -1
+}
+
+func (m *JoinGroupResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutInt16(m.ErrorCode)
+	e.PutInt32(m.GenerationId)
+	if version >= 7 {
+		e.PutNullableString(m.ProtocolType, flexible)
+	}
+	if version >= 7 {
+		e.PutNullableString(m.ProtocolName, flexible)
+	} else {
+		e.PutString(derefString(m.ProtocolName), flexible)
+	}
+	e.PutString(m.Leader, flexible)
+	if version >= 9 {
+		e.PutBool(m.SkipAssignment)
+	}
+	e.PutString(m.MemberId, flexible)
+	e.PutArrayLength(len(m.Members), flexible)
+	for i := range m.Members {
+		m.Members[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *JoinGroupResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	m.ErrorCode = d.Int16()
+	m.GenerationId = d.Int32()
+	if version >= 7 {
+		m.ProtocolType = d.NullableString(flexible)
+	}
+	if version >= 7 {
+		m.ProtocolName = d.NullableString(flexible)
+	} else {
+		s := d.String(flexible)
+		m.ProtocolName = &s
+	}
+	m.Leader = d.String(flexible)
+	if version >= 9 {
+		m.SkipAssignment = d.Bool()
+	}
+	m.MemberId = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Members = make([]JoinGroupResponseMember, n)
+		for i := range m.Members {
+			m.Members[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Members = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// JoinGroupResponseMember is an element of JoinGroupResponse.Members.
+type JoinGroupResponseMember struct {
+	// The group member ID.
+	MemberId string
+	// The unique identifier of the consumer instance provided by end user.
+	GroupInstanceId *string
+	// The group member metadata.
+	Metadata []byte
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets JoinGroupResponseMember to its default field values
+func (m *JoinGroupResponseMember) Default() {
+	*m = JoinGroupResponseMember{}
+}
+
+func (m *JoinGroupResponseMember) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.MemberId, flexible)
+	if version >= 5 {
+		e.PutNullableString(m.GroupInstanceId, flexible)
+	}
+	e.PutBytes(m.Metadata, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *JoinGroupResponseMember) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.MemberId = d.String(flexible)
+	if version >= 5 {
+		m.GroupInstanceId = d.NullableString(flexible)
+	}
+	m.Metadata = d.Bytes(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
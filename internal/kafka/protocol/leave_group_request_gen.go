@@ -0,0 +1,130 @@
+// Code generated by protogen from messages/LeaveGroupRequest.json. DO NOT EDIT.
+
+package protocol
+
+// LeaveGroupRequest is the request for API key 13, versions 0-5.
+type LeaveGroupRequest struct {
+	// The ID of the group to leave.
+	GroupId string
+	// The member ID to remove from the group.
+	MemberId string
+	// List of leaving member identities.
+	Members []LeaveGroupRequestMemberIdentity
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of LeaveGroupRequest
+func (*LeaveGroupRequest) APIKey() int16 { return 13 }
+
+// MinVersion returns the lowest supported version of LeaveGroupRequest
+func (*LeaveGroupRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of LeaveGroupRequest
+func (*LeaveGroupRequest) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of LeaveGroupRequest uses the flexible encoding
+func (*LeaveGroupRequest) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes LeaveGroupRequest in the given version
+func (m *LeaveGroupRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads LeaveGroupRequest in the given version
+func (m *LeaveGroupRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets LeaveGroupRequest to its default field values
+func (m *LeaveGroupRequest) Default() {
+	*m = LeaveGroupRequest{}
+}
+
+func (m *LeaveGroupRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	if version <= 2 {
+		e.PutString(m.MemberId, flexible)
+	}
+	if version >= 3 {
+		e.PutArrayLength(len(m.Members), flexible)
+		for i := range m.Members {
+			m.Members[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *LeaveGroupRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	if version <= 2 {
+		m.MemberId = d.String(flexible)
+	}
+	if version >= 3 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Members = make([]LeaveGroupRequestMemberIdentity, n)
+			for i := range m.Members {
+				m.Members[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Members = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// LeaveGroupRequestMemberIdentity is an element of LeaveGroupRequest.Members.
+type LeaveGroupRequestMemberIdentity struct {
+	// The member ID to remove from the group.
+	MemberId string
+	// The group instance ID to remove from the group.
+	GroupInstanceId *string
+	// The reason why the member left the group.
+	Reason *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets LeaveGroupRequestMemberIdentity to its default field values
+func (m *LeaveGroupRequestMemberIdentity) Default() {
+	*m = LeaveGroupRequestMemberIdentity{}
+}
+
+func (m *LeaveGroupRequestMemberIdentity) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.MemberId, flexible)
+	e.PutNullableString(m.GroupInstanceId, flexible)
+	if version >= 5 {
+		e.PutNullableString(m.Reason, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *LeaveGroupRequestMemberIdentity) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.MemberId = d.String(flexible)
+	m.GroupInstanceId = d.NullableString(flexible)
+	if version >= 5 {
+		m.Reason = d.NullableString(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Code generated by protogen from messages/LeaveGroupResponse.json. DO NOT EDIT.
+
+package protocol
+
+// LeaveGroupResponse is the response for API key 13, versions 0-5.
+type LeaveGroupResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// List of leaving member responses.
+	Members []LeaveGroupResponseMemberResponse
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of LeaveGroupResponse
+func (*LeaveGroupResponse) APIKey() int16 { return 13 }
+
+// MinVersion returns the lowest supported version of LeaveGroupResponse
+func (*LeaveGroupResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of LeaveGroupResponse
+func (*LeaveGroupResponse) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of LeaveGroupResponse uses the flexible encoding
+func (*LeaveGroupResponse) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes LeaveGroupResponse in the given version
+func (m *LeaveGroupResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads LeaveGroupResponse in the given version
+func (m *LeaveGroupResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets LeaveGroupResponse to its default field values
+func (m *LeaveGroupResponse) Default() {
+	*m = LeaveGroupResponse{}
+}
+
+func (m *LeaveGroupResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutInt16(m.ErrorCode)
+	if version >= 3 {
+		e.PutArrayLength(len(m.Members), flexible)
+		for i := range m.Members {
+			m.Members[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *LeaveGroupResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	m.ErrorCode = d.Int16()
+	if version >= 3 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Members = make([]LeaveGroupResponseMemberResponse, n)
+			for i := range m.Members {
+				m.Members[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Members = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// LeaveGroupResponseMemberResponse is an element of LeaveGroupResponse.Members.
+type LeaveGroupResponseMemberResponse struct {
+	// The member ID to remove from the group.
+	MemberId string
+	// The group instance ID to remove from the group.
+	GroupInstanceId *string
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets LeaveGroupResponseMemberResponse to its default field values
+func (m *LeaveGroupResponseMemberResponse) Default() {
+	*m = LeaveGroupResponseMemberResponse{}
+}
+
+func (m *LeaveGroupResponseMemberResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.MemberId, flexible)
+	e.PutNullableString(m.GroupInstanceId, flexible)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *LeaveGroupResponseMemberResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.MemberId = d.String(flexible)
+	m.GroupInstanceId = d.NullableString(flexible)
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Code generated by protogen from messages/ListGroupsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// ListGroupsRequest is the request for API key 16, versions 0-5.
+type ListGroupsRequest struct {
+	// The states of the groups we want to list. If empty, all groups are returned with their state.
+	StatesFilter []string
+	// The types of the groups we want to list. If empty, all groups are returned with their type.
+	TypesFilter []string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ListGroupsRequest
+func (*ListGroupsRequest) APIKey() int16 { return 16 }
+
+// MinVersion returns the lowest supported version of ListGroupsRequest
+func (*ListGroupsRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ListGroupsRequest
+func (*ListGroupsRequest) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of ListGroupsRequest uses the flexible encoding
+func (*ListGroupsRequest) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes ListGroupsRequest in the given version
+func (m *ListGroupsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ListGroupsRequest in the given version
+func (m *ListGroupsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ListGroupsRequest to its default field values
+func (m *ListGroupsRequest) Default() {
+	*m = ListGroupsRequest{}
+}
+
+func (m *ListGroupsRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 4 {
+		e.PutArrayLength(len(m.StatesFilter), flexible)
+		for i := range m.StatesFilter {
+			e.PutString(m.StatesFilter[i], flexible)
+		}
+	}
+	if version >= 5 {
+		e.PutArrayLength(len(m.TypesFilter), flexible)
+		for i := range m.TypesFilter {
+			e.PutString(m.TypesFilter[i], flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListGroupsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 4 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.StatesFilter = make([]string, n)
+			for i := range m.StatesFilter {
+				m.StatesFilter[i] = d.String(flexible)
+			}
+		} else {
+			m.StatesFilter = nil
+		}
+	}
+	if version >= 5 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.TypesFilter = make([]string, n)
+			for i := range m.TypesFilter {
+				m.TypesFilter[i] = d.String(flexible)
+			}
+		} else {
+			m.TypesFilter = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Code generated by protogen from messages/ListGroupsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// ListGroupsResponse is the response for API key 16, versions 0-5.
+type ListGroupsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Each group in the response.
+	Groups []ListGroupsResponseListedGroup
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ListGroupsResponse
+func (*ListGroupsResponse) APIKey() int16 { return 16 }
+
+// MinVersion returns the lowest supported version of ListGroupsResponse
+func (*ListGroupsResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ListGroupsResponse
+func (*ListGroupsResponse) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of ListGroupsResponse uses the flexible encoding
+func (*ListGroupsResponse) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes ListGroupsResponse in the given version
+func (m *ListGroupsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ListGroupsResponse in the given version
+func (m *ListGroupsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ListGroupsResponse to its default field values
+func (m *ListGroupsResponse) Default() {
+	*m = ListGroupsResponse{}
+}
+
+func (m *ListGroupsResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutInt16(m.ErrorCode)
+	e.PutArrayLength(len(m.Groups), flexible)
+	for i := range m.Groups {
+		m.Groups[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListGroupsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	m.ErrorCode = d.Int16()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Groups = make([]ListGroupsResponseListedGroup, n)
+		for i := range m.Groups {
+			m.Groups[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Groups = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ListGroupsResponseListedGroup is an element of ListGroupsResponse.Groups.
+type ListGroupsResponseListedGroup struct {
+	// The group ID.
+	GroupId string
+	// The group protocol type.
+	ProtocolType string
+	// The group state name.
+	GroupState string
+	// The group type name.
+	GroupType string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ListGroupsResponseListedGroup to its default field values
+func (m *ListGroupsResponseListedGroup) Default() {
+	*m = ListGroupsResponseListedGroup{}
+}
+
+func (m *ListGroupsResponseListedGroup) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutString(m.ProtocolType, flexible)
+	if version >= 4 {
+		e.PutString(m.GroupState, flexible)
+	}
+	if version >= 5 {
+		e.PutString(m.GroupType, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListGroupsResponseListedGroup) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	m.ProtocolType = d.String(flexible)
+	if version >= 4 {
+		m.GroupState = d.String(flexible)
+	}
+	if version >= 5 {
+		m.GroupType = d.String(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
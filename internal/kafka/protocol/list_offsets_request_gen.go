@@ -0,0 +1,174 @@
+// Code generated by protogen from messages/ListOffsetsRequest.json. DO NOT EDIT.
+
+package protocol
+
+// ListOffsetsRequest is the request for API key 2, versions 1-9.
+type ListOffsetsRequest struct {
+	// The broker ID of the requester, or -1 if this request is being made by a normal consumer.
+	ReplicaId int32
+	// This setting controls the visibility of transactional records. Using READ_UNCOMMITTED (isolation_level = 0) makes all records visible. With READ_COMMITTED (isolation_level = 1), non-transactional and COMMITTED transactional records are visible. To be more concrete, READ_COMMITTED returns all data from offsets smaller than the current LSO (last stable offset), and enables the inclusion of the list of aborted transactions in the result, which allows consumers to discard ABORTED transactional records.
+	IsolationLevel int8
+	// Each topic in the request.
+	Topics []ListOffsetsRequestListOffsetsTopic
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ListOffsetsRequest
+func (*ListOffsetsRequest) APIKey() int16 { return 2 }
+
+// MinVersion returns the lowest supported version of ListOffsetsRequest
+func (*ListOffsetsRequest) MinVersion() int16 { return 1 }
+
+// MaxVersion returns the highest supported version of ListOffsetsRequest
+func (*ListOffsetsRequest) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of ListOffsetsRequest uses the flexible encoding
+func (*ListOffsetsRequest) IsFlexible(version int16) bool { return version >= 6 }
+
+// Encode writes ListOffsetsRequest in the given version
+func (m *ListOffsetsRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ListOffsetsRequest in the given version
+func (m *ListOffsetsRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ListOffsetsRequest to its default field values
+func (m *ListOffsetsRequest) Default() {
+	*m = ListOffsetsRequest{}
+}
+
+func (m *ListOffsetsRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ReplicaId)
+	if version >= 2 {
+		e.PutInt8(m.IsolationLevel)
+	}
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListOffsetsRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ReplicaId = d.Int32()
+	if version >= 2 {
+		m.IsolationLevel = d.Int8()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]ListOffsetsRequestListOffsetsTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ListOffsetsRequestListOffsetsTopic is an element of ListOffsetsRequest.Topics.
+type ListOffsetsRequestListOffsetsTopic struct {
+	// The topic name.
+	Name string
+	// Each partition in the request.
+	Partitions []ListOffsetsRequestListOffsetsPartition
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ListOffsetsRequestListOffsetsTopic to its default field values
+func (m *ListOffsetsRequestListOffsetsTopic) Default() {
+	*m = ListOffsetsRequestListOffsetsTopic{}
+}
+
+func (m *ListOffsetsRequestListOffsetsTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListOffsetsRequestListOffsetsTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]ListOffsetsRequestListOffsetsPartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ListOffsetsRequestListOffsetsPartition is an element of ListOffsetsRequestListOffsetsTopic.Partitions.
+type ListOffsetsRequestListOffsetsPartition struct {
+	// The partition index.
+	PartitionIndex int32
+	// The current leader epoch.
+	CurrentLeaderEpoch int32
+	// The current timestamp.
+	Timestamp int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ListOffsetsRequestListOffsetsPartition to its default field values
+func (m *ListOffsetsRequestListOffsetsPartition) Default() {
+	*m = ListOffsetsRequestListOffsetsPartition{}
+	m.CurrentLeaderEpoch = -1
+}
+
+func (m *ListOffsetsRequestListOffsetsPartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	if version >= 4 {
+		e.PutInt32(m.CurrentLeaderEpoch)
+	}
+	e.PutInt64(m.Timestamp)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListOffsetsRequestListOffsetsPartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	if version >= 4 {
+		m.CurrentLeaderEpoch = d.Int32()
+	}
+	m.Timestamp = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
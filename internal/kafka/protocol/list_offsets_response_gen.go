@@ -0,0 +1,180 @@
+// Code generated by protogen from messages/ListOffsetsResponse.json. DO NOT EDIT.
+
+package protocol
+
+// ListOffsetsResponse is the response for API key 2, versions 1-9.
+type ListOffsetsResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// Each topic in the response.
+	Topics []ListOffsetsResponseListOffsetsTopicResponse
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ListOffsetsResponse
+func (*ListOffsetsResponse) APIKey() int16 { return 2 }
+
+// MinVersion returns the lowest supported version of ListOffsetsResponse
+func (*ListOffsetsResponse) MinVersion() int16 { return 1 }
+
+// MaxVersion returns the highest supported version of ListOffsetsResponse
+func (*ListOffsetsResponse) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of ListOffsetsResponse uses the flexible encoding
+func (*ListOffsetsResponse) IsFlexible(version int16) bool { return version >= 6 }
+
+// Encode writes ListOffsetsResponse in the given version
+func (m *ListOffsetsResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ListOffsetsResponse in the given version
+func (m *ListOffsetsResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ListOffsetsResponse to its default field values
+func (m *ListOffsetsResponse) Default() {
+	*m = ListOffsetsResponse{}
+}
+
+func (m *ListOffsetsResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 2 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListOffsetsResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 2 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]ListOffsetsResponseListOffsetsTopicResponse, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ListOffsetsResponseListOffsetsTopicResponse is an element of ListOffsetsResponse.Topics.
+type ListOffsetsResponseListOffsetsTopicResponse struct {
+	// The topic name.
+	Name string
+	// Each partition in the response.
+	Partitions []ListOffsetsResponseListOffsetsPartitionResponse
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ListOffsetsResponseListOffsetsTopicResponse to its default field values
+func (m *ListOffsetsResponseListOffsetsTopicResponse) Default() {
+	*m = ListOffsetsResponseListOffsetsTopicResponse{}
+}
+
+func (m *ListOffsetsResponseListOffsetsTopicResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListOffsetsResponseListOffsetsTopicResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]ListOffsetsResponseListOffsetsPartitionResponse, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ListOffsetsResponseListOffsetsPartitionResponse is an element of ListOffsetsResponseListOffsetsTopicResponse.Partitions.
+type ListOffsetsResponseListOffsetsPartitionResponse struct {
+	// The partition index.
+	PartitionIndex int32
+	// The partition error code, or 0 if there was no error.
+	ErrorCode int16
+	// The timestamp associated with the returned offset.
+	Timestamp int64
+	// The returned offset.
+	Offset int64
+	// The leader epoch associated with the returned offset.
+	LeaderEpoch int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ListOffsetsResponseListOffsetsPartitionResponse to its default field values
+func (m *ListOffsetsResponseListOffsetsPartitionResponse) Default() {
+	*m = ListOffsetsResponseListOffsetsPartitionResponse{}
+	m.Timestamp = -1
+	m.Offset = -1
+	m.LeaderEpoch = -1
+}
+
+func (m *ListOffsetsResponseListOffsetsPartitionResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt16(m.ErrorCode)
+	e.PutInt64(m.Timestamp)
+	e.PutInt64(m.Offset)
+	if version >= 4 {
+		e.PutInt32(m.LeaderEpoch)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ListOffsetsResponseListOffsetsPartitionResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.ErrorCode = d.Int16()
+	m.Timestamp = d.Int64()
+	m.Offset = d.Int64()
+	if version >= 4 {
+		m.LeaderEpoch = d.Int32()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
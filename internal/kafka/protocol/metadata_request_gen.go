@@ -0,0 +1,148 @@
+// Code generated by protogen from messages/MetadataRequest.json. DO NOT EDIT.
+
+package protocol
+
+// MetadataRequest is the request for API key 3, versions 0-12.
+type MetadataRequest struct {
+	// The topics to fetch metadata for.
+	Topics []MetadataRequestTopic
+	// If this is true, the broker may auto-create topics that we requested which do not already exist, if it is configured to do so.
+	AllowAutoTopicCreation bool
+	// Whether to include cluster authorized operations.
+	IncludeClusterAuthorizedOperations bool
+	// Whether to include topic authorized operations.
+	IncludeTopicAuthorizedOperations bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of MetadataRequest
+func (*MetadataRequest) APIKey() int16 { return 3 }
+
+// MinVersion returns the lowest supported version of MetadataRequest
+func (*MetadataRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of MetadataRequest
+func (*MetadataRequest) MaxVersion() int16 { return 12 }
+
+// IsFlexible reports whether the given version of MetadataRequest uses the flexible encoding
+func (*MetadataRequest) IsFlexible(version int16) bool { return version >= 9 }
+
+// Encode writes MetadataRequest in the given version
+func (m *MetadataRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads MetadataRequest in the given version
+func (m *MetadataRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets MetadataRequest to its default field values
+func (m *MetadataRequest) Default() {
+	*m = MetadataRequest{}
+	m.AllowAutoTopicCreation = true
+}
+
+func (m *MetadataRequest) encode(e *Encoder, version int16, flexible bool) {
+	if m.Topics == nil && (version >= 1) {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.Topics), flexible)
+		for i := range m.Topics {
+			m.Topics[i].encode(e, version, flexible)
+		}
+	}
+	if version >= 4 {
+		e.PutBool(m.AllowAutoTopicCreation)
+	}
+	if version >= 8 && version <= 10 {
+		e.PutBool(m.IncludeClusterAuthorizedOperations)
+	}
+	if version >= 8 {
+		e.PutBool(m.IncludeTopicAuthorizedOperations)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *MetadataRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]MetadataRequestTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if version >= 4 {
+		m.AllowAutoTopicCreation = d.Bool()
+	}
+	if version >= 8 && version <= 10 {
+		m.IncludeClusterAuthorizedOperations = d.Bool()
+	}
+	if version >= 8 {
+		m.IncludeTopicAuthorizedOperations = d.Bool()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// MetadataRequestTopic is an element of MetadataRequest.Topics.
+type MetadataRequestTopic struct {
+	// The topic id.
+	TopicId UUID
+	// The topic name.
+	Name *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets MetadataRequestTopic to its default field values
+func (m *MetadataRequestTopic) Default() {
+	*m = MetadataRequestTopic{}
+}
+
+func (m *MetadataRequestTopic) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 10 {
+		e.PutUUID(m.TopicId)
+	}
+	if version >= 10 {
+		e.PutNullableString(m.Name, flexible)
+	} else {
+		e.PutString(derefString(m.Name), flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *MetadataRequestTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 10 {
+		m.TopicId = d.UUID()
+	}
+	if version >= 10 {
+		m.Name = d.NullableString(flexible)
+	} else {
+		s := d.String(flexible)
+		m.Name = &s
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Code generated by protogen from messages/MetadataResponse.json. DO NOT EDIT.
+
+package protocol
+
+// MetadataResponse is the response for API key 3, versions 0-12.
+type MetadataResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// A list of brokers present in the cluster.
+	Brokers []MetadataResponseBroker
+	// The cluster ID that responding broker belongs to.
+	ClusterId *string
+	// The ID of the controller broker.
+	ControllerId int32
+	// Each topic in the response.
+	Topics []MetadataResponseTopic
+	// 32-bit bitfield to represent authorized operations for this cluster.
+	ClusterAuthorizedOperations int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of MetadataResponse
+func (*MetadataResponse) APIKey() int16 { return 3 }
+
+// MinVersion returns the lowest supported version of MetadataResponse
+func (*MetadataResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of MetadataResponse
+func (*MetadataResponse) MaxVersion() int16 { return 12 }
+
+// IsFlexible reports whether the given version of MetadataResponse uses the flexible encoding
+func (*MetadataResponse) IsFlexible(version int16) bool { return version >= 9 }
+
+// Encode writes MetadataResponse in the given version
+func (m *MetadataResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads MetadataResponse in the given version
+func (m *MetadataResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets MetadataResponse to its default field values
+func (m *MetadataResponse) Default() {
+	*m = MetadataResponse{}
+	m.ControllerId = -1
+	m.ClusterAuthorizedOperations = -2147483648
+}
+
+func (m *MetadataResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 3 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutArrayLength(len(m.Brokers), flexible)
+	for i := range m.Brokers {
+		m.Brokers[i].encode(e, version, flexible)
+	}
+	if version >= 2 {
+		e.PutNullableString(m.ClusterId, flexible)
+	}
+	if version >= 1 {
+		e.PutInt32(m.ControllerId)
+	}
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if version >= 8 && version <= 10 {
+		e.PutInt32(m.ClusterAuthorizedOperations)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *MetadataResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 3 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Brokers = make([]MetadataResponseBroker, n)
+		for i := range m.Brokers {
+			m.Brokers[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Brokers = nil
+	}
+	if version >= 2 {
+		m.ClusterId = d.NullableString(flexible)
+	}
+	if version >= 1 {
+		m.ControllerId = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]MetadataResponseTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if version >= 8 && version <= 10 {
+		m.ClusterAuthorizedOperations = d.Int32()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// MetadataResponseBroker is an element of MetadataResponse.Brokers.
+type MetadataResponseBroker struct {
+	// The broker ID.
+	NodeId int32
+	// The broker hostname.
+	Host string
+	// The broker port.
+	Port int32
+	// The rack of the broker, or null if it has not been assigned to a rack.
+	Rack *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets MetadataResponseBroker to its default field values
+func (m *MetadataResponseBroker) Default() {
+	*m = MetadataResponseBroker{}
+}
+
+func (m *MetadataResponseBroker) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.NodeId)
+	e.PutString(m.Host, flexible)
+	e.PutInt32(m.Port)
+	if version >= 1 {
+		e.PutNullableString(m.Rack, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *MetadataResponseBroker) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.NodeId = d.Int32()
+	m.Host = d.String(flexible)
+	m.Port = d.Int32()
+	if version >= 1 {
+		m.Rack = d.NullableString(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// MetadataResponseTopic is an element of MetadataResponse.Topics.
+type MetadataResponseTopic struct {
+	// The topic error, or 0 if there was no error.
+	ErrorCode int16
+	// The topic name. Null for non-existing topics queried by ID. This is never null when ErrorCode is zero. One of Name and TopicId is always populated.
+	Name *string
+	// The topic id. Zero for non-existing topics queried by name. This is never zero when ErrorCode is zero. One of Name and TopicId is always populated.
+	TopicId UUID
+	// True if the topic is internal.
+	IsInternal bool
+	// Each partition in the topic.
+	Partitions []MetadataResponsePartition
+	// 32-bit bitfield to represent authorized operations for this topic.
+	TopicAuthorizedOperations int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets MetadataResponseTopic to its default field values
+func (m *MetadataResponseTopic) Default() {
+	*m = MetadataResponseTopic{}
+	m.TopicAuthorizedOperations = -2147483648
+}
+
+func (m *MetadataResponseTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ErrorCode)
+	if version >= 12 {
+		e.PutNullableString(m.Name, flexible)
+	} else {
+		e.PutString(derefString(m.Name), flexible)
+	}
+	if version >= 10 {
+		e.PutUUID(m.TopicId)
+	}
+	if version >= 1 {
+		e.PutBool(m.IsInternal)
+	}
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if version >= 8 {
+		e.PutInt32(m.TopicAuthorizedOperations)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *MetadataResponseTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ErrorCode = d.Int16()
+	if version >= 12 {
+		m.Name = d.NullableString(flexible)
+	} else {
+		s := d.String(flexible)
+		m.Name = &s
+	}
+	if version >= 10 {
+		m.TopicId = d.UUID()
+	}
+	if version >= 1 {
+		m.IsInternal = d.Bool()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]MetadataResponsePartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if version >= 8 {
+		m.TopicAuthorizedOperations = d.Int32()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// MetadataResponsePartition is an element of MetadataResponseTopic.Partitions.
+type MetadataResponsePartition struct {
+	// The partition error, or 0 if there was no error.
+	ErrorCode int16
+	// The partition index.
+	PartitionIndex int32
+	// The ID of the leader broker.
+	LeaderId int32
+	// The leader epoch of this partition.
+	LeaderEpoch int32
+	// The set of all nodes that host this partition.
+	ReplicaNodes []int32
+	// The set of nodes that are in sync with the leader for this partition.
+	IsrNodes []int32
+	// The set of offline replicas of this partition.
+	OfflineReplicas []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets MetadataResponsePartition to its default field values
+func (m *MetadataResponsePartition) Default() {
+	*m = MetadataResponsePartition{}
+	m.LeaderEpoch = -1
+}
+
+func (m *MetadataResponsePartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.ErrorCode)
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt32(m.LeaderId)
+	if version >= 7 {
+		e.PutInt32(m.LeaderEpoch)
+	}
+	e.PutArrayLength(len(m.ReplicaNodes), flexible)
+	for i := range m.ReplicaNodes {
+		e.PutInt32(m.ReplicaNodes[i])
+	}
+	e.PutArrayLength(len(m.IsrNodes), flexible)
+	for i := range m.IsrNodes {
+		e.PutInt32(m.IsrNodes[i])
+	}
+	if version >= 5 {
+		e.PutArrayLength(len(m.OfflineReplicas), flexible)
+		for i := range m.OfflineReplicas {
+			e.PutInt32(m.OfflineReplicas[i])
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *MetadataResponsePartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ErrorCode = d.Int16()
+	m.PartitionIndex = d.Int32()
+	m.LeaderId = d.Int32()
+	if version >= 7 {
+		m.LeaderEpoch = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.ReplicaNodes = make([]int32, n)
+		for i := range m.ReplicaNodes {
+			m.ReplicaNodes[i] = d.Int32()
+		}
+	} else {
+		m.ReplicaNodes = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.IsrNodes = make([]int32, n)
+		for i := range m.IsrNodes {
+			m.IsrNodes[i] = d.Int32()
+		}
+	} else {
+		m.IsrNodes = nil
+	}
+	if version >= 5 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.OfflineReplicas = make([]int32, n)
+			for i := range m.OfflineReplicas {
+				m.OfflineReplicas[i] = d.Int32()
+			}
+		} else {
+			m.OfflineReplicas = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
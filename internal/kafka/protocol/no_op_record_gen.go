@@ -0,0 +1,55 @@
+// Code generated by protogen from messages/NoOpRecord.json. DO NOT EDIT.
+
+package protocol
+
+// NoOpRecord is the metadata record of type 20, version 0.
+type NoOpRecord struct {
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of NoOpRecord
+func (*NoOpRecord) APIKey() int16 { return 20 }
+
+// MinVersion returns the lowest supported version of NoOpRecord
+func (*NoOpRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of NoOpRecord
+func (*NoOpRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of NoOpRecord uses the flexible encoding
+func (*NoOpRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes NoOpRecord in the given version
+func (m *NoOpRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads NoOpRecord in the given version
+func (m *NoOpRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets NoOpRecord to its default field values
+func (m *NoOpRecord) Default() {
+	*m = NoOpRecord{}
+}
+
+func (m *NoOpRecord) encode(e *Encoder, version int16, flexible bool) {
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *NoOpRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
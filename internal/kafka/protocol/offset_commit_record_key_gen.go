@@ -0,0 +1,54 @@
+// Code generated by protogen from messages/OffsetCommitRecordKey.json. DO NOT EDIT.
+
+package protocol
+
+// OffsetCommitRecordKey is the key of coordinator record type 1, version 0.
+type OffsetCommitRecordKey struct {
+	// The group id.
+	Group string
+	// The topic name.
+	Topic string
+	// The partition index.
+	Partition int32
+}
+
+// APIKey returns the API key of OffsetCommitRecordKey
+func (*OffsetCommitRecordKey) APIKey() int16 { return 1 }
+
+// MinVersion returns the lowest supported version of OffsetCommitRecordKey
+func (*OffsetCommitRecordKey) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of OffsetCommitRecordKey
+func (*OffsetCommitRecordKey) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of OffsetCommitRecordKey uses the flexible encoding
+func (*OffsetCommitRecordKey) IsFlexible(version int16) bool { return false }
+
+// Encode writes OffsetCommitRecordKey in the given version
+func (m *OffsetCommitRecordKey) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads OffsetCommitRecordKey in the given version
+func (m *OffsetCommitRecordKey) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets OffsetCommitRecordKey to its default field values
+func (m *OffsetCommitRecordKey) Default() {
+	*m = OffsetCommitRecordKey{}
+}
+
+func (m *OffsetCommitRecordKey) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Group, flexible)
+	e.PutString(m.Topic, flexible)
+	e.PutInt32(m.Partition)
+}
+
+func (m *OffsetCommitRecordKey) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Group = d.String(flexible)
+	m.Topic = d.String(flexible)
+	m.Partition = d.Int32()
+}
@@ -0,0 +1,85 @@
+// Code generated by protogen from messages/OffsetCommitRecordValue.json. DO NOT EDIT.
+
+package protocol
+
+// OffsetCommitRecordValue is the value of coordinator record type 1, versions 0-4.
+type OffsetCommitRecordValue struct {
+	// The offset that the consumer wants to store (for this partition).
+	Offset int64
+	// The leader epoch of the last consumed record.
+	LeaderEpoch int32
+	// Any metadata the client wants to keep.
+	Metadata string
+	// The time at which the commit was added to the log.
+	CommitTimestamp int64
+	// The time at which the offset will expire.
+	ExpireTimestamp int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of OffsetCommitRecordValue
+func (*OffsetCommitRecordValue) APIKey() int16 { return 1 }
+
+// MinVersion returns the lowest supported version of OffsetCommitRecordValue
+func (*OffsetCommitRecordValue) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of OffsetCommitRecordValue
+func (*OffsetCommitRecordValue) MaxVersion() int16 { return 4 }
+
+// IsFlexible reports whether the given version of OffsetCommitRecordValue uses the flexible encoding
+func (*OffsetCommitRecordValue) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes OffsetCommitRecordValue in the given version
+func (m *OffsetCommitRecordValue) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads OffsetCommitRecordValue in the given version
+func (m *OffsetCommitRecordValue) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets OffsetCommitRecordValue to its default field values
+func (m *OffsetCommitRecordValue) Default() {
+	*m = OffsetCommitRecordValue{}
+	m.LeaderEpoch = -1
+	m.ExpireTimestamp = -1
+}
+
+func (m *OffsetCommitRecordValue) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt64(m.Offset)
+	if version >= 3 {
+		e.PutInt32(m.LeaderEpoch)
+	}
+	e.PutString(m.Metadata, flexible)
+	e.PutInt64(m.CommitTimestamp)
+	if version >= 1 && version <= 1 {
+		e.PutInt64(m.ExpireTimestamp)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetCommitRecordValue) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Offset = d.Int64()
+	if version >= 3 {
+		m.LeaderEpoch = d.Int32()
+	}
+	m.Metadata = d.String(flexible)
+	m.CommitTimestamp = d.Int64()
+	if version >= 1 && version <= 1 {
+		m.ExpireTimestamp = d.Int64()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Code generated by protogen from messages/OffsetCommitRequest.json. DO NOT EDIT.
+
+package protocol
+
+// OffsetCommitRequest is the request for API key 8, versions 2-9.
+type OffsetCommitRequest struct {
+	// The unique group identifier.
+	GroupId string
+	// The generation of the group if using the classic group protocol or the member epoch if using the consumer protocol.
+	GenerationIdOrMemberEpoch int32
+	// The member ID assigned by the group coordinator.
+	MemberId string
+	// The unique identifier of the consumer instance provided by end user.
+	GroupInstanceId *string
+	// The time period in ms to retain the offset.
+	RetentionTimeMs int64
+	// The topics to commit offsets for.
+	Topics []OffsetCommitRequestTopic
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of OffsetCommitRequest
+func (*OffsetCommitRequest) APIKey() int16 { return 8 }
+
+// MinVersion returns the lowest supported version of OffsetCommitRequest
+func (*OffsetCommitRequest) MinVersion() int16 { return 2 }
+
+// MaxVersion returns the highest supported version of OffsetCommitRequest
+func (*OffsetCommitRequest) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of OffsetCommitRequest uses the flexible encoding
+func (*OffsetCommitRequest) IsFlexible(version int16) bool { return version >= 8 }
+
+// Encode writes OffsetCommitRequest in the given version
+func (m *OffsetCommitRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads OffsetCommitRequest in the given version
+func (m *OffsetCommitRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets OffsetCommitRequest to its default field values
+func (m *OffsetCommitRequest) Default() {
+	*m = OffsetCommitRequest{}
+	m.GenerationIdOrMemberEpoch = -1
+	m.RetentionTimeMs = -1
+}
+
+func (m *OffsetCommitRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutInt32(m.GenerationIdOrMemberEpoch)
+	e.PutString(m.MemberId, flexible)
+	if version >= 7 {
+		e.PutNullableString(m.GroupInstanceId, flexible)
+	}
+	if version <= 4 {
+		e.PutInt64(m.RetentionTimeMs)
+	}
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetCommitRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	m.GenerationIdOrMemberEpoch = d.Int32()
+	m.MemberId = d.String(flexible)
+	if version >= 7 {
+		m.GroupInstanceId = d.NullableString(flexible)
+	}
+	if version <= 4 {
+		m.RetentionTimeMs = d.Int64()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]OffsetCommitRequestTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetCommitRequestTopic is an element of OffsetCommitRequest.Topics.
+type OffsetCommitRequestTopic struct {
+	// The topic name.
+	Name string
+	// Each partition to commit offsets for.
+	Partitions []OffsetCommitRequestPartition
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetCommitRequestTopic to its default field values
+func (m *OffsetCommitRequestTopic) Default() {
+	*m = OffsetCommitRequestTopic{}
+}
+
+func (m *OffsetCommitRequestTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetCommitRequestTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]OffsetCommitRequestPartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetCommitRequestPartition is an element of OffsetCommitRequestTopic.Partitions.
+type OffsetCommitRequestPartition struct {
+	// The partition index.
+	PartitionIndex int32
+	// The message offset to be committed.
+	CommittedOffset int64
+	// The leader epoch of this partition.
+	CommittedLeaderEpoch int32
+	// Any associated metadata the client wants to keep.
+	CommittedMetadata *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetCommitRequestPartition to its default field values
+func (m *OffsetCommitRequestPartition) Default() {
+	*m = OffsetCommitRequestPartition{}
+	m.CommittedLeaderEpoch = -1
+}
+
+func (m *OffsetCommitRequestPartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt64(m.CommittedOffset)
+	if version >= 6 {
+		e.PutInt32(m.CommittedLeaderEpoch)
+	}
+	e.PutNullableString(m.CommittedMetadata, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetCommitRequestPartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.CommittedOffset = d.Int64()
+	if version >= 6 {
+		m.CommittedLeaderEpoch = d.Int32()
+	}
+	m.CommittedMetadata = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
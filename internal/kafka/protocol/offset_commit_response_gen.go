@@ -0,0 +1,161 @@
+// Code generated by protogen from messages/OffsetCommitResponse.json. DO NOT EDIT.
+
+package protocol
+
+// OffsetCommitResponse is the response for API key 8, versions 2-9.
+type OffsetCommitResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The responses for each topic.
+	Topics []OffsetCommitResponseTopic
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of OffsetCommitResponse
+func (*OffsetCommitResponse) APIKey() int16 { return 8 }
+
+// MinVersion returns the lowest supported version of OffsetCommitResponse
+func (*OffsetCommitResponse) MinVersion() int16 { return 2 }
+
+// MaxVersion returns the highest supported version of OffsetCommitResponse
+func (*OffsetCommitResponse) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of OffsetCommitResponse uses the flexible encoding
+func (*OffsetCommitResponse) IsFlexible(version int16) bool { return version >= 8 }
+
+// Encode writes OffsetCommitResponse in the given version
+func (m *OffsetCommitResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads OffsetCommitResponse in the given version
+func (m *OffsetCommitResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets OffsetCommitResponse to its default field values
+func (m *OffsetCommitResponse) Default() {
+	*m = OffsetCommitResponse{}
+}
+
+func (m *OffsetCommitResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 3 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetCommitResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 3 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]OffsetCommitResponseTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetCommitResponseTopic is an element of OffsetCommitResponse.Topics.
+type OffsetCommitResponseTopic struct {
+	// The topic name.
+	Name string
+	// The responses for each partition in the topic.
+	Partitions []OffsetCommitResponsePartition
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetCommitResponseTopic to its default field values
+func (m *OffsetCommitResponseTopic) Default() {
+	*m = OffsetCommitResponseTopic{}
+}
+
+func (m *OffsetCommitResponseTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetCommitResponseTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]OffsetCommitResponsePartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetCommitResponsePartition is an element of OffsetCommitResponseTopic.Partitions.
+type OffsetCommitResponsePartition struct {
+	// The partition index.
+	PartitionIndex int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetCommitResponsePartition to its default field values
+func (m *OffsetCommitResponsePartition) Default() {
+	*m = OffsetCommitResponsePartition{}
+}
+
+func (m *OffsetCommitResponsePartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetCommitResponsePartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
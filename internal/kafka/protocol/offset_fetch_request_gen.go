@@ -0,0 +1,273 @@
+// Code generated by protogen from messages/OffsetFetchRequest.json. DO NOT EDIT.
+
+package protocol
+
+// OffsetFetchRequest is the request for API key 9, versions 1-9.
+type OffsetFetchRequest struct {
+	// The group to fetch offsets for.
+	GroupId string
+	// Each topic we would like to fetch offsets for, or null to fetch offsets for all topics.
+	Topics []OffsetFetchRequestTopic
+	// Each group we would like to fetch offsets for.
+	Groups []OffsetFetchRequestGroup
+	// Whether broker should hold on returning unstable offsets but set a retriable error code for the partitions.
+	RequireStable bool
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of OffsetFetchRequest
+func (*OffsetFetchRequest) APIKey() int16 { return 9 }
+
+// MinVersion returns the lowest supported version of OffsetFetchRequest
+func (*OffsetFetchRequest) MinVersion() int16 { return 1 }
+
+// MaxVersion returns the highest supported version of OffsetFetchRequest
+func (*OffsetFetchRequest) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of OffsetFetchRequest uses the flexible encoding
+func (*OffsetFetchRequest) IsFlexible(version int16) bool { return version >= 6 }
+
+// Encode writes OffsetFetchRequest in the given version
+func (m *OffsetFetchRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads OffsetFetchRequest in the given version
+func (m *OffsetFetchRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets OffsetFetchRequest to its default field values
+func (m *OffsetFetchRequest) Default() {
+	*m = OffsetFetchRequest{}
+}
+
+func (m *OffsetFetchRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version <= 7 {
+		e.PutString(m.GroupId, flexible)
+	}
+	if version <= 7 {
+		if m.Topics == nil && (version >= 2) {
+			e.PutArrayLength(-1, flexible)
+		} else {
+			e.PutArrayLength(len(m.Topics), flexible)
+			for i := range m.Topics {
+				m.Topics[i].encode(e, version, flexible)
+			}
+		}
+	}
+	if version >= 8 {
+		e.PutArrayLength(len(m.Groups), flexible)
+		for i := range m.Groups {
+			m.Groups[i].encode(e, version, flexible)
+		}
+	}
+	if version >= 7 {
+		e.PutBool(m.RequireStable)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version <= 7 {
+		m.GroupId = d.String(flexible)
+	}
+	if version <= 7 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Topics = make([]OffsetFetchRequestTopic, n)
+			for i := range m.Topics {
+				m.Topics[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Topics = nil
+		}
+	}
+	if version >= 8 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Groups = make([]OffsetFetchRequestGroup, n)
+			for i := range m.Groups {
+				m.Groups[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Groups = nil
+		}
+	}
+	if version >= 7 {
+		m.RequireStable = d.Bool()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchRequestTopic is an element of OffsetFetchRequest.Topics.
+type OffsetFetchRequestTopic struct {
+	// The topic name.
+	Name string
+	// The partition indexes we would like to fetch offsets for.
+	PartitionIndexes []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchRequestTopic to its default field values
+func (m *OffsetFetchRequestTopic) Default() {
+	*m = OffsetFetchRequestTopic{}
+}
+
+func (m *OffsetFetchRequestTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.PartitionIndexes), flexible)
+	for i := range m.PartitionIndexes {
+		e.PutInt32(m.PartitionIndexes[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchRequestTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.PartitionIndexes = make([]int32, n)
+		for i := range m.PartitionIndexes {
+			m.PartitionIndexes[i] = d.Int32()
+		}
+	} else {
+		m.PartitionIndexes = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchRequestGroup is an element of OffsetFetchRequest.Groups.
+type OffsetFetchRequestGroup struct {
+	// The group ID.
+	GroupId string
+	// The member id.
+	MemberId *string
+	// The member epoch if using the new consumer protocol (KIP-848).
+	MemberEpoch int32
+	// Each topic we would like to fetch offsets for, or null to fetch offsets for all topics.
+	Topics []OffsetFetchRequestTopics
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchRequestGroup to its default field values
+func (m *OffsetFetchRequestGroup) Default() {
+	*m = OffsetFetchRequestGroup{}
+	m.MemberEpoch = -1
+}
+
+func (m *OffsetFetchRequestGroup) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	if version >= 9 {
+		e.PutNullableString(m.MemberId, flexible)
+	}
+	if version >= 9 {
+		e.PutInt32(m.MemberEpoch)
+	}
+	if m.Topics == nil {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.Topics), flexible)
+		for i := range m.Topics {
+			m.Topics[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchRequestGroup) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	if version >= 9 {
+		m.MemberId = d.NullableString(flexible)
+	}
+	if version >= 9 {
+		m.MemberEpoch = d.Int32()
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]OffsetFetchRequestTopics, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchRequestTopics is an element of OffsetFetchRequestGroup.Topics.
+type OffsetFetchRequestTopics struct {
+	// The topic name.
+	Name string
+	// The partition indexes we would like to fetch offsets for.
+	PartitionIndexes []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchRequestTopics to its default field values
+func (m *OffsetFetchRequestTopics) Default() {
+	*m = OffsetFetchRequestTopics{}
+}
+
+func (m *OffsetFetchRequestTopics) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.PartitionIndexes), flexible)
+	for i := range m.PartitionIndexes {
+		e.PutInt32(m.PartitionIndexes[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchRequestTopics) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.PartitionIndexes = make([]int32, n)
+		for i := range m.PartitionIndexes {
+			m.PartitionIndexes[i] = d.Int32()
+		}
+	} else {
+		m.PartitionIndexes = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+// Code generated by protogen from messages/OffsetFetchResponse.json. DO NOT EDIT.
+
+package protocol
+
+// OffsetFetchResponse is the response for API key 9, versions 1-9.
+type OffsetFetchResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The responses per topic.
+	Topics []OffsetFetchResponseTopic
+	// The top-level error code, or 0 if there was no error.
+	ErrorCode int16
+	// The responses per group id.
+	Groups []OffsetFetchResponseGroup
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of OffsetFetchResponse
+func (*OffsetFetchResponse) APIKey() int16 { return 9 }
+
+// MinVersion returns the lowest supported version of OffsetFetchResponse
+func (*OffsetFetchResponse) MinVersion() int16 { return 1 }
+
+// MaxVersion returns the highest supported version of OffsetFetchResponse
+func (*OffsetFetchResponse) MaxVersion() int16 { return 9 }
+
+// IsFlexible reports whether the given version of OffsetFetchResponse uses the flexible encoding
+func (*OffsetFetchResponse) IsFlexible(version int16) bool { return version >= 6 }
+
+// Encode writes OffsetFetchResponse in the given version
+func (m *OffsetFetchResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads OffsetFetchResponse in the given version
+func (m *OffsetFetchResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets OffsetFetchResponse to its default field values
+func (m *OffsetFetchResponse) Default() {
+	*m = OffsetFetchResponse{}
+}
+
+func (m *OffsetFetchResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 3 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	if version <= 7 {
+		e.PutArrayLength(len(m.Topics), flexible)
+		for i := range m.Topics {
+			m.Topics[i].encode(e, version, flexible)
+		}
+	}
+	if version >= 2 && version <= 7 {
+		e.PutInt16(m.ErrorCode)
+	}
+	if version >= 8 {
+		e.PutArrayLength(len(m.Groups), flexible)
+		for i := range m.Groups {
+			m.Groups[i].encode(e, version, flexible)
+		}
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 3 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if version <= 7 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Topics = make([]OffsetFetchResponseTopic, n)
+			for i := range m.Topics {
+				m.Topics[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Topics = nil
+		}
+	}
+	if version >= 2 && version <= 7 {
+		m.ErrorCode = d.Int16()
+	}
+	if version >= 8 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Groups = make([]OffsetFetchResponseGroup, n)
+			for i := range m.Groups {
+				m.Groups[i].decode(d, version, flexible)
+			}
+		} else {
+			m.Groups = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchResponseTopic is an element of OffsetFetchResponse.Topics.
+type OffsetFetchResponseTopic struct {
+	// The topic name.
+	Name string
+	// The responses per partition.
+	Partitions []OffsetFetchResponsePartition
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchResponseTopic to its default field values
+func (m *OffsetFetchResponseTopic) Default() {
+	*m = OffsetFetchResponseTopic{}
+}
+
+func (m *OffsetFetchResponseTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchResponseTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]OffsetFetchResponsePartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchResponseGroup is an element of OffsetFetchResponse.Groups.
+type OffsetFetchResponseGroup struct {
+	// The group ID.
+	GroupId string
+	// The responses per topic.
+	Topics []OffsetFetchResponseTopics
+	// The group-level error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchResponseGroup to its default field values
+func (m *OffsetFetchResponseGroup) Default() {
+	*m = OffsetFetchResponseGroup{}
+}
+
+func (m *OffsetFetchResponseGroup) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchResponseGroup) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]OffsetFetchResponseTopics, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchResponsePartition is an element of OffsetFetchResponseTopic.Partitions.
+type OffsetFetchResponsePartition struct {
+	// The partition index.
+	PartitionIndex int32
+	// The committed message offset.
+	CommittedOffset int64
+	// The leader epoch.
+	CommittedLeaderEpoch int32
+	// The partition metadata.
+	Metadata *string
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchResponsePartition to its default field values
+func (m *OffsetFetchResponsePartition) Default() {
+	*m = OffsetFetchResponsePartition{}
+	m.CommittedLeaderEpoch = -1
+}
+
+func (m *OffsetFetchResponsePartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt64(m.CommittedOffset)
+	if version >= 5 {
+		e.PutInt32(m.CommittedLeaderEpoch)
+	}
+	e.PutNullableString(m.Metadata, flexible)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchResponsePartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.CommittedOffset = d.Int64()
+	if version >= 5 {
+		m.CommittedLeaderEpoch = d.Int32()
+	}
+	m.Metadata = d.NullableString(flexible)
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchResponseTopics is an element of OffsetFetchResponseGroup.Topics.
+type OffsetFetchResponseTopics struct {
+	// The topic name.
+	Name string
+	// The responses per partition.
+	Partitions []OffsetFetchResponsePartitions
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchResponseTopics to its default field values
+func (m *OffsetFetchResponseTopics) Default() {
+	*m = OffsetFetchResponseTopics{}
+}
+
+func (m *OffsetFetchResponseTopics) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchResponseTopics) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]OffsetFetchResponsePartitions, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// OffsetFetchResponsePartitions is an element of OffsetFetchResponseTopics.Partitions.
+type OffsetFetchResponsePartitions struct {
+	// The partition index.
+	PartitionIndex int32
+	// The committed message offset.
+	CommittedOffset int64
+	// The leader epoch.
+	CommittedLeaderEpoch int32
+	// The partition metadata.
+	Metadata *string
+	// The partition-level error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets OffsetFetchResponsePartitions to its default field values
+func (m *OffsetFetchResponsePartitions) Default() {
+	*m = OffsetFetchResponsePartitions{}
+	m.CommittedLeaderEpoch = -1
+}
+
+func (m *OffsetFetchResponsePartitions) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt64(m.CommittedOffset)
+	e.PutInt32(m.CommittedLeaderEpoch)
+	e.PutNullableString(m.Metadata, flexible)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *OffsetFetchResponsePartitions) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.CommittedOffset = d.Int64()
+	m.CommittedLeaderEpoch = d.Int32()
+	m.Metadata = d.NullableString(flexible)
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Code generated by protogen from messages/PartitionChangeRecord.json. DO NOT EDIT.
+
+package protocol
+
+// PartitionChangeRecord is the metadata record of type 5, versions 0-2.
+type PartitionChangeRecord struct {
+	// The partition id.
+	PartitionId int32
+	// The unique ID of this topic.
+	TopicId UUID
+	// null if the ISR didn't change; the new in-sync replicas otherwise.
+	Isr []int32
+	// -1 if there is now no leader; -2 if the leader didn't change; the new leader otherwise.
+	Leader int32
+	// null if the replicas didn't change; the new replicas otherwise.
+	Replicas []int32
+	// null if the removing replicas didn't change; the new removing replicas otherwise.
+	RemovingReplicas []int32
+	// null if the adding replicas didn't change; the new adding replicas otherwise.
+	AddingReplicas []int32
+	// -1 if it didn't change; 0 if the leader was elected from the ISR or recovered from an unclean election; 1 if the leader that was elected using unclean leader election and it is still recovering.
+	LeaderRecoveryState int8
+	// null if the log dirs didn't change; the new log directory for each replica otherwise.
+	Directories []UUID
+	// null if the ELR didn't change; the new eligible leader replicas otherwise.
+	EligibleLeaderReplicas []int32
+	// null if the LastKnownElr didn't change; the last known eligible leader replicas otherwise.
+	LastKnownElr []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of PartitionChangeRecord
+func (*PartitionChangeRecord) APIKey() int16 { return 5 }
+
+// MinVersion returns the lowest supported version of PartitionChangeRecord
+func (*PartitionChangeRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of PartitionChangeRecord
+func (*PartitionChangeRecord) MaxVersion() int16 { return 2 }
+
+// IsFlexible reports whether the given version of PartitionChangeRecord uses the flexible encoding
+func (*PartitionChangeRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes PartitionChangeRecord in the given version
+func (m *PartitionChangeRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads PartitionChangeRecord in the given version
+func (m *PartitionChangeRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets PartitionChangeRecord to its default field values
+func (m *PartitionChangeRecord) Default() {
+	*m = PartitionChangeRecord{}
+	m.PartitionId = -1
+	m.Leader = -2
+	m.LeaderRecoveryState = -1
+}
+
+func (m *PartitionChangeRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionId)
+	e.PutUUID(m.TopicId)
+	if flexible {
+		var tagged []TaggedField
+		if m.Isr != nil {
+			te := NewEncoder(0)
+			if m.Isr == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.Isr), true)
+				for i := range m.Isr {
+					te.PutInt32(m.Isr[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		if m.Leader != -2 {
+			te := NewEncoder(0)
+			te.PutInt32(m.Leader)
+			tagged = append(tagged, TaggedField{Tag: 1, Data: te.Bytes()})
+		}
+		if m.Replicas != nil {
+			te := NewEncoder(0)
+			if m.Replicas == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.Replicas), true)
+				for i := range m.Replicas {
+					te.PutInt32(m.Replicas[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 2, Data: te.Bytes()})
+		}
+		if m.RemovingReplicas != nil {
+			te := NewEncoder(0)
+			if m.RemovingReplicas == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.RemovingReplicas), true)
+				for i := range m.RemovingReplicas {
+					te.PutInt32(m.RemovingReplicas[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 3, Data: te.Bytes()})
+		}
+		if m.AddingReplicas != nil {
+			te := NewEncoder(0)
+			if m.AddingReplicas == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.AddingReplicas), true)
+				for i := range m.AddingReplicas {
+					te.PutInt32(m.AddingReplicas[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 4, Data: te.Bytes()})
+		}
+		if m.LeaderRecoveryState != -1 {
+			te := NewEncoder(0)
+			te.PutInt8(m.LeaderRecoveryState)
+			tagged = append(tagged, TaggedField{Tag: 5, Data: te.Bytes()})
+		}
+		if (version >= 1) && m.Directories != nil {
+			te := NewEncoder(0)
+			if m.Directories == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.Directories), true)
+				for i := range m.Directories {
+					te.PutUUID(m.Directories[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 6, Data: te.Bytes()})
+		}
+		if (version >= 2) && m.EligibleLeaderReplicas != nil {
+			te := NewEncoder(0)
+			if m.EligibleLeaderReplicas == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.EligibleLeaderReplicas), true)
+				for i := range m.EligibleLeaderReplicas {
+					te.PutInt32(m.EligibleLeaderReplicas[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 7, Data: te.Bytes()})
+		}
+		if (version >= 2) && m.LastKnownElr != nil {
+			te := NewEncoder(0)
+			if m.LastKnownElr == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.LastKnownElr), true)
+				for i := range m.LastKnownElr {
+					te.PutInt32(m.LastKnownElr[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 8, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *PartitionChangeRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionId = d.Int32()
+	m.TopicId = d.UUID()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.Isr = make([]int32, n)
+					for i := range m.Isr {
+						m.Isr[i] = fd.Int32()
+					}
+				} else {
+					m.Isr = nil
+				}
+			case 1:
+				m.Leader = fd.Int32()
+			case 2:
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.Replicas = make([]int32, n)
+					for i := range m.Replicas {
+						m.Replicas[i] = fd.Int32()
+					}
+				} else {
+					m.Replicas = nil
+				}
+			case 3:
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.RemovingReplicas = make([]int32, n)
+					for i := range m.RemovingReplicas {
+						m.RemovingReplicas[i] = fd.Int32()
+					}
+				} else {
+					m.RemovingReplicas = nil
+				}
+			case 4:
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.AddingReplicas = make([]int32, n)
+					for i := range m.AddingReplicas {
+						m.AddingReplicas[i] = fd.Int32()
+					}
+				} else {
+					m.AddingReplicas = nil
+				}
+			case 5:
+				m.LeaderRecoveryState = fd.Int8()
+			case 6:
+				if !(version >= 1) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.Directories = make([]UUID, n)
+					for i := range m.Directories {
+						m.Directories[i] = fd.UUID()
+					}
+				} else {
+					m.Directories = nil
+				}
+			case 7:
+				if !(version >= 2) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.EligibleLeaderReplicas = make([]int32, n)
+					for i := range m.EligibleLeaderReplicas {
+						m.EligibleLeaderReplicas[i] = fd.Int32()
+					}
+				} else {
+					m.EligibleLeaderReplicas = nil
+				}
+			case 8:
+				if !(version >= 2) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.LastKnownElr = make([]int32, n)
+					for i := range m.LastKnownElr {
+						m.LastKnownElr[i] = fd.Int32()
+					}
+				} else {
+					m.LastKnownElr = nil
+				}
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
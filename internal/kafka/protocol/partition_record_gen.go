@@ -0,0 +1,217 @@
+// Code generated by protogen from messages/PartitionRecord.json. DO NOT EDIT.
+
+package protocol
+
+// PartitionRecord is the metadata record of type 3, versions 0-2.
+type PartitionRecord struct {
+	// The partition id.
+	PartitionId int32
+	// The unique ID of this topic.
+	TopicId UUID
+	// The replicas of this partition, sorted by preferred order.
+	Replicas []int32
+	// The in-sync replicas of this partition
+	Isr []int32
+	// The replicas that we are in the process of removing.
+	RemovingReplicas []int32
+	// The replicas that we are in the process of adding.
+	AddingReplicas []int32
+	// The lead replica, or -1 if there is no leader.
+	Leader int32
+	// 1 if the partition is recovering from an unclean leader election; 0 otherwise.
+	LeaderRecoveryState int8
+	// The epoch of the partition leader.
+	LeaderEpoch int32
+	// An epoch that gets incremented each time we change anything in the partition.
+	PartitionEpoch int32
+	// The log directory hosting each replica, sorted in the same exact order as the Replicas field.
+	Directories []UUID
+	// The eligible leader replicas of this partition.
+	EligibleLeaderReplicas []int32
+	// The last known eligible leader replicas of this partition.
+	LastKnownElr []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of PartitionRecord
+func (*PartitionRecord) APIKey() int16 { return 3 }
+
+// MinVersion returns the lowest supported version of PartitionRecord
+func (*PartitionRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of PartitionRecord
+func (*PartitionRecord) MaxVersion() int16 { return 2 }
+
+// IsFlexible reports whether the given version of PartitionRecord uses the flexible encoding
+func (*PartitionRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes PartitionRecord in the given version
+func (m *PartitionRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads PartitionRecord in the given version
+func (m *PartitionRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets PartitionRecord to its default field values
+func (m *PartitionRecord) Default() {
+	*m = PartitionRecord{}
+	m.PartitionId = -1
+	m.Leader = -1
+	m.LeaderEpoch = -1
+	m.PartitionEpoch = -1
+}
+
+func (m *PartitionRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionId)
+	e.PutUUID(m.TopicId)
+	e.PutArrayLength(len(m.Replicas), flexible)
+	for i := range m.Replicas {
+		e.PutInt32(m.Replicas[i])
+	}
+	e.PutArrayLength(len(m.Isr), flexible)
+	for i := range m.Isr {
+		e.PutInt32(m.Isr[i])
+	}
+	e.PutArrayLength(len(m.RemovingReplicas), flexible)
+	for i := range m.RemovingReplicas {
+		e.PutInt32(m.RemovingReplicas[i])
+	}
+	e.PutArrayLength(len(m.AddingReplicas), flexible)
+	for i := range m.AddingReplicas {
+		e.PutInt32(m.AddingReplicas[i])
+	}
+	e.PutInt32(m.Leader)
+	e.PutInt32(m.LeaderEpoch)
+	e.PutInt32(m.PartitionEpoch)
+	if version >= 1 {
+		e.PutArrayLength(len(m.Directories), flexible)
+		for i := range m.Directories {
+			e.PutUUID(m.Directories[i])
+		}
+	}
+	if flexible {
+		var tagged []TaggedField
+		if m.LeaderRecoveryState != 0 {
+			te := NewEncoder(0)
+			te.PutInt8(m.LeaderRecoveryState)
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		if (version >= 2) && m.EligibleLeaderReplicas != nil {
+			te := NewEncoder(0)
+			if m.EligibleLeaderReplicas == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.EligibleLeaderReplicas), true)
+				for i := range m.EligibleLeaderReplicas {
+					te.PutInt32(m.EligibleLeaderReplicas[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 1, Data: te.Bytes()})
+		}
+		if (version >= 2) && m.LastKnownElr != nil {
+			te := NewEncoder(0)
+			if m.LastKnownElr == nil {
+				te.PutArrayLength(-1, true)
+			} else {
+				te.PutArrayLength(len(m.LastKnownElr), true)
+				for i := range m.LastKnownElr {
+					te.PutInt32(m.LastKnownElr[i])
+				}
+			}
+			tagged = append(tagged, TaggedField{Tag: 2, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *PartitionRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionId = d.Int32()
+	m.TopicId = d.UUID()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Replicas = make([]int32, n)
+		for i := range m.Replicas {
+			m.Replicas[i] = d.Int32()
+		}
+	} else {
+		m.Replicas = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Isr = make([]int32, n)
+		for i := range m.Isr {
+			m.Isr[i] = d.Int32()
+		}
+	} else {
+		m.Isr = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.RemovingReplicas = make([]int32, n)
+		for i := range m.RemovingReplicas {
+			m.RemovingReplicas[i] = d.Int32()
+		}
+	} else {
+		m.RemovingReplicas = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.AddingReplicas = make([]int32, n)
+		for i := range m.AddingReplicas {
+			m.AddingReplicas[i] = d.Int32()
+		}
+	} else {
+		m.AddingReplicas = nil
+	}
+	m.Leader = d.Int32()
+	m.LeaderEpoch = d.Int32()
+	m.PartitionEpoch = d.Int32()
+	if version >= 1 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.Directories = make([]UUID, n)
+			for i := range m.Directories {
+				m.Directories[i] = d.UUID()
+			}
+		} else {
+			m.Directories = nil
+		}
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				m.LeaderRecoveryState = fd.Int8()
+			case 1:
+				if !(version >= 2) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.EligibleLeaderReplicas = make([]int32, n)
+					for i := range m.EligibleLeaderReplicas {
+						m.EligibleLeaderReplicas[i] = fd.Int32()
+					}
+				} else {
+					m.EligibleLeaderReplicas = nil
+				}
+			case 2:
+				if !(version >= 2) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.LastKnownElr = make([]int32, n)
+					for i := range m.LastKnownElr {
+						m.LastKnownElr[i] = fd.Int32()
+					}
+				} else {
+					m.LastKnownElr = nil
+				}
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
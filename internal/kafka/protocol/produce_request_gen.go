@@ -0,0 +1,169 @@
+// Code generated by protogen from messages/ProduceRequest.json. DO NOT EDIT.
+
+package protocol
+
+// ProduceRequest is the request for API key 0, versions 0-11.
+type ProduceRequest struct {
+	// The transactional ID, or null if the producer is not transactional.
+	TransactionalId *string
+	// The number of acknowledgments the producer requires the leader to have received before considering a request complete. Allowed values: 0 for no acknowledgments, 1 for only the leader and -1 for the full ISR.
+	Acks int16
+	// The timeout to await a response in milliseconds.
+	TimeoutMs int32
+	// Each topic to produce to.
+	TopicData []ProduceRequestTopicProduceData
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ProduceRequest
+func (*ProduceRequest) APIKey() int16 { return 0 }
+
+// MinVersion returns the lowest supported version of ProduceRequest
+func (*ProduceRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ProduceRequest
+func (*ProduceRequest) MaxVersion() int16 { return 11 }
+
+// IsFlexible reports whether the given version of ProduceRequest uses the flexible encoding
+func (*ProduceRequest) IsFlexible(version int16) bool { return version >= 9 }
+
+// Encode writes ProduceRequest in the given version
+func (m *ProduceRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ProduceRequest in the given version
+func (m *ProduceRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ProduceRequest to its default field values
+func (m *ProduceRequest) Default() {
+	*m = ProduceRequest{}
+}
+
+func (m *ProduceRequest) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 3 {
+		e.PutNullableString(m.TransactionalId, flexible)
+	}
+	e.PutInt16(m.Acks)
+	e.PutInt32(m.TimeoutMs)
+	e.PutArrayLength(len(m.TopicData), flexible)
+	for i := range m.TopicData {
+		m.TopicData[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProduceRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 3 {
+		m.TransactionalId = d.NullableString(flexible)
+	}
+	m.Acks = d.Int16()
+	m.TimeoutMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.TopicData = make([]ProduceRequestTopicProduceData, n)
+		for i := range m.TopicData {
+			m.TopicData[i].decode(d, version, flexible)
+		}
+	} else {
+		m.TopicData = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ProduceRequestTopicProduceData is an element of ProduceRequest.TopicData.
+type ProduceRequestTopicProduceData struct {
+	// The topic name.
+	Name string
+	// Each partition to produce to.
+	PartitionData []ProduceRequestPartitionProduceData
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ProduceRequestTopicProduceData to its default field values
+func (m *ProduceRequestTopicProduceData) Default() {
+	*m = ProduceRequestTopicProduceData{}
+}
+
+func (m *ProduceRequestTopicProduceData) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.PartitionData), flexible)
+	for i := range m.PartitionData {
+		m.PartitionData[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProduceRequestTopicProduceData) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.PartitionData = make([]ProduceRequestPartitionProduceData, n)
+		for i := range m.PartitionData {
+			m.PartitionData[i].decode(d, version, flexible)
+		}
+	} else {
+		m.PartitionData = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ProduceRequestPartitionProduceData is an element of ProduceRequestTopicProduceData.PartitionData.
+type ProduceRequestPartitionProduceData struct {
+	// The partition index.
+	Index int32
+	// The record data to be produced.
+	Records []byte
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ProduceRequestPartitionProduceData to its default field values
+func (m *ProduceRequestPartitionProduceData) Default() {
+	*m = ProduceRequestPartitionProduceData{}
+}
+
+func (m *ProduceRequestPartitionProduceData) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.Index)
+	e.PutNullableBytes(m.Records, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProduceRequestPartitionProduceData) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Index = d.Int32()
+	m.Records = d.NullableBytes(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+// Code generated by protogen from messages/ProduceResponse.json. DO NOT EDIT.
+
+package protocol
+
+// ProduceResponse is the response for API key 0, versions 0-11.
+type ProduceResponse struct {
+	// Each produce response.
+	Responses []ProduceResponseTopicProduceResponse
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// Endpoints for all current-leaders enumerated in PartitionProduceResponses, with errors NOT_LEADER_OR_FOLLOWER.
+	NodeEndpoints []ProduceResponseNodeEndpoint
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ProduceResponse
+func (*ProduceResponse) APIKey() int16 { return 0 }
+
+// MinVersion returns the lowest supported version of ProduceResponse
+func (*ProduceResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ProduceResponse
+func (*ProduceResponse) MaxVersion() int16 { return 11 }
+
+// IsFlexible reports whether the given version of ProduceResponse uses the flexible encoding
+func (*ProduceResponse) IsFlexible(version int16) bool { return version >= 9 }
+
+// Encode writes ProduceResponse in the given version
+func (m *ProduceResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ProduceResponse in the given version
+func (m *ProduceResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ProduceResponse to its default field values
+func (m *ProduceResponse) Default() {
+	*m = ProduceResponse{}
+}
+
+func (m *ProduceResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.Responses), flexible)
+	for i := range m.Responses {
+		m.Responses[i].encode(e, version, flexible)
+	}
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	if flexible {
+		var tagged []TaggedField
+		if (version >= 10) && len(m.NodeEndpoints) > 0 {
+			te := NewEncoder(0)
+			te.PutArrayLength(len(m.NodeEndpoints), true)
+			for i := range m.NodeEndpoints {
+				m.NodeEndpoints[i].encode(te, version, flexible)
+			}
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *ProduceResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Responses = make([]ProduceResponseTopicProduceResponse, n)
+		for i := range m.Responses {
+			m.Responses[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Responses = nil
+	}
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				if !(version >= 10) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.NodeEndpoints = make([]ProduceResponseNodeEndpoint, n)
+					for i := range m.NodeEndpoints {
+						m.NodeEndpoints[i].decode(fd, version, flexible)
+					}
+				} else {
+					m.NodeEndpoints = nil
+				}
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ProduceResponseTopicProduceResponse is an element of ProduceResponse.Responses.
+type ProduceResponseTopicProduceResponse struct {
+	// The topic name.
+	Name string
+	// Each partition that we produced to within the topic.
+	PartitionResponses []ProduceResponsePartitionProduceResponse
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ProduceResponseTopicProduceResponse to its default field values
+func (m *ProduceResponseTopicProduceResponse) Default() {
+	*m = ProduceResponseTopicProduceResponse{}
+}
+
+func (m *ProduceResponseTopicProduceResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.PartitionResponses), flexible)
+	for i := range m.PartitionResponses {
+		m.PartitionResponses[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProduceResponseTopicProduceResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.PartitionResponses = make([]ProduceResponsePartitionProduceResponse, n)
+		for i := range m.PartitionResponses {
+			m.PartitionResponses[i].decode(d, version, flexible)
+		}
+	} else {
+		m.PartitionResponses = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ProduceResponseNodeEndpoint is an element of ProduceResponse.NodeEndpoints.
+type ProduceResponseNodeEndpoint struct {
+	// The ID of the associated node.
+	NodeId int32
+	// The node's hostname.
+	Host string
+	// The node's port.
+	Port int32
+	// The rack of the node, or null if it has not been assigned to a rack.
+	Rack *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ProduceResponseNodeEndpoint to its default field values
+func (m *ProduceResponseNodeEndpoint) Default() {
+	*m = ProduceResponseNodeEndpoint{}
+}
+
+func (m *ProduceResponseNodeEndpoint) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.NodeId)
+	e.PutString(m.Host, flexible)
+	e.PutInt32(m.Port)
+	e.PutNullableString(m.Rack, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProduceResponseNodeEndpoint) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.NodeId = d.Int32()
+	m.Host = d.String(flexible)
+	m.Port = d.Int32()
+	m.Rack = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ProduceResponsePartitionProduceResponse is an element of ProduceResponseTopicProduceResponse.PartitionResponses.
+type ProduceResponsePartitionProduceResponse struct {
+	// The partition index.
+	Index int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// The base offset.
+	BaseOffset int64
+	// The timestamp returned by broker after appending the messages. If CreateTime is used for the topic, the timestamp will be -1.  If LogAppendTime is used for the topic, the timestamp will be the broker local time when the messages are appended.
+	LogAppendTimeMs int64
+	// The log start offset.
+	LogStartOffset int64
+	// The batch indices of records that caused the batch to be dropped.
+	RecordErrors []ProduceResponseBatchIndexAndErrorMessage
+	// The global error message summarizing the common root cause of the records that caused the batch to be dropped.
+	ErrorMessage *string
+	// The leader broker that the producer should use for future requests.
+	CurrentLeader ProduceResponseLeaderIdAndEpoch
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ProduceResponsePartitionProduceResponse to its default field values
+func (m *ProduceResponsePartitionProduceResponse) Default() {
+	*m = ProduceResponsePartitionProduceResponse{}
+	m.LogAppendTimeMs = -1
+	m.LogStartOffset = -1
+	m.CurrentLeader.Default()
+}
+
+func (m *ProduceResponsePartitionProduceResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.Index)
+	e.PutInt16(m.ErrorCode)
+	e.PutInt64(m.BaseOffset)
+	if version >= 2 {
+		e.PutInt64(m.LogAppendTimeMs)
+	}
+	if version >= 5 {
+		e.PutInt64(m.LogStartOffset)
+	}
+	if version >= 8 {
+		e.PutArrayLength(len(m.RecordErrors), flexible)
+		for i := range m.RecordErrors {
+			m.RecordErrors[i].encode(e, version, flexible)
+		}
+	}
+	if version >= 8 {
+		e.PutNullableString(m.ErrorMessage, flexible)
+	}
+	if flexible {
+		var tagged []TaggedField
+		if (version >= 10) && !m.CurrentLeader.isDefault() {
+			te := NewEncoder(0)
+			m.CurrentLeader.encode(te, version, flexible)
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *ProduceResponsePartitionProduceResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Index = d.Int32()
+	m.ErrorCode = d.Int16()
+	m.BaseOffset = d.Int64()
+	if version >= 2 {
+		m.LogAppendTimeMs = d.Int64()
+	}
+	if version >= 5 {
+		m.LogStartOffset = d.Int64()
+	}
+	if version >= 8 {
+		if n := d.ArrayLength(flexible); n >= 0 {
+			m.RecordErrors = make([]ProduceResponseBatchIndexAndErrorMessage, n)
+			for i := range m.RecordErrors {
+				m.RecordErrors[i].decode(d, version, flexible)
+			}
+		} else {
+			m.RecordErrors = nil
+		}
+	}
+	if version >= 8 {
+		m.ErrorMessage = d.NullableString(flexible)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				if !(version >= 10) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				m.CurrentLeader.decode(fd, version, flexible)
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ProduceResponseBatchIndexAndErrorMessage is an element of ProduceResponsePartitionProduceResponse.RecordErrors.
+type ProduceResponseBatchIndexAndErrorMessage struct {
+	// The batch index of the record that caused the batch to be dropped.
+	BatchIndex int32
+	// The error message of the record that caused the batch to be dropped.
+	BatchIndexErrorMessage *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ProduceResponseBatchIndexAndErrorMessage to its default field values
+func (m *ProduceResponseBatchIndexAndErrorMessage) Default() {
+	*m = ProduceResponseBatchIndexAndErrorMessage{}
+}
+
+func (m *ProduceResponseBatchIndexAndErrorMessage) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.BatchIndex)
+	e.PutNullableString(m.BatchIndexErrorMessage, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProduceResponseBatchIndexAndErrorMessage) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.BatchIndex = d.Int32()
+	m.BatchIndexErrorMessage = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// ProduceResponseLeaderIdAndEpoch is the type of ProduceResponsePartitionProduceResponse.CurrentLeader.
+type ProduceResponseLeaderIdAndEpoch struct {
+	// The ID of the current leader or -1 if the leader is unknown.
+	LeaderId int32
+	// The latest known leader epoch.
+	LeaderEpoch int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets ProduceResponseLeaderIdAndEpoch to its default field values
+func (m *ProduceResponseLeaderIdAndEpoch) Default() {
+	*m = ProduceResponseLeaderIdAndEpoch{}
+	m.LeaderId = -1
+	m.LeaderEpoch = -1
+}
+
+// isDefault reports whether ProduceResponseLeaderIdAndEpoch holds only default field values
+func (m *ProduceResponseLeaderIdAndEpoch) isDefault() bool {
+	if m.LeaderId != -1 {
+		return false
+	}
+	if m.LeaderEpoch != -1 {
+		return false
+	}
+	return len(m.UnknownTaggedFields) == 0
+}
+
+func (m *ProduceResponseLeaderIdAndEpoch) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.LeaderId)
+	e.PutInt32(m.LeaderEpoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProduceResponseLeaderIdAndEpoch) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.LeaderId = d.Int32()
+	m.LeaderEpoch = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Code generated by protogen from messages/ProducerIdsRecord.json. DO NOT EDIT.
+
+package protocol
+
+// ProducerIdsRecord is the metadata record of type 15, version 0.
+type ProducerIdsRecord struct {
+	// The ID of the requesting broker
+	BrokerId int32
+	// The epoch of the requesting broker
+	BrokerEpoch int64
+	// The next producerId that will be assigned (i.e. the first producerId in the next assigned block)
+	NextProducerId int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of ProducerIdsRecord
+func (*ProducerIdsRecord) APIKey() int16 { return 15 }
+
+// MinVersion returns the lowest supported version of ProducerIdsRecord
+func (*ProducerIdsRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ProducerIdsRecord
+func (*ProducerIdsRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of ProducerIdsRecord uses the flexible encoding
+func (*ProducerIdsRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes ProducerIdsRecord in the given version
+func (m *ProducerIdsRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ProducerIdsRecord in the given version
+func (m *ProducerIdsRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ProducerIdsRecord to its default field values
+func (m *ProducerIdsRecord) Default() {
+	*m = ProducerIdsRecord{}
+	m.BrokerEpoch = -1
+}
+
+func (m *ProducerIdsRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.BrokerId)
+	e.PutInt64(m.BrokerEpoch)
+	e.PutInt64(m.NextProducerId)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ProducerIdsRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.BrokerId = d.Int32()
+	m.BrokerEpoch = d.Int64()
+	m.NextProducerId = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Code generated by protogen from messages/RegisterBrokerRecord.json. DO NOT EDIT.
+
+package protocol
+
+// RegisterBrokerRecord is the metadata record of type 0, versions 0-3.
+type RegisterBrokerRecord struct {
+	// The broker id.
+	BrokerId int32
+	// True if the registering broker is a ZK broker.
+	IsMigratingZkBroker bool
+	// The incarnation ID of the broker process
+	IncarnationId UUID
+	// The broker epoch assigned by the controller.
+	BrokerEpoch int64
+	// The endpoints that can be used to communicate with this broker.
+	EndPoints []RegisterBrokerRecordBrokerEndpoint
+	// The features on this broker
+	Features []RegisterBrokerRecordBrokerFeature
+	// The broker rack.
+	Rack *string
+	// True if the broker is fenced.
+	Fenced bool
+	// True if the broker is in controlled shutdown.
+	InControlledShutdown bool
+	// Log directories configured in this broker which are available.
+	LogDirs []UUID
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of RegisterBrokerRecord
+func (*RegisterBrokerRecord) APIKey() int16 { return 0 }
+
+// MinVersion returns the lowest supported version of RegisterBrokerRecord
+func (*RegisterBrokerRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of RegisterBrokerRecord
+func (*RegisterBrokerRecord) MaxVersion() int16 { return 3 }
+
+// IsFlexible reports whether the given version of RegisterBrokerRecord uses the flexible encoding
+func (*RegisterBrokerRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes RegisterBrokerRecord in the given version
+func (m *RegisterBrokerRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads RegisterBrokerRecord in the given version
+func (m *RegisterBrokerRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets RegisterBrokerRecord to its default field values
+func (m *RegisterBrokerRecord) Default() {
+	*m = RegisterBrokerRecord{}
+	m.Fenced = true
+}
+
+func (m *RegisterBrokerRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.BrokerId)
+	if version >= 2 {
+		e.PutBool(m.IsMigratingZkBroker)
+	}
+	e.PutUUID(m.IncarnationId)
+	e.PutInt64(m.BrokerEpoch)
+	e.PutArrayLength(len(m.EndPoints), flexible)
+	for i := range m.EndPoints {
+		m.EndPoints[i].encode(e, version, flexible)
+	}
+	e.PutArrayLength(len(m.Features), flexible)
+	for i := range m.Features {
+		m.Features[i].encode(e, version, flexible)
+	}
+	e.PutNullableString(m.Rack, flexible)
+	e.PutBool(m.Fenced)
+	if version >= 1 {
+		e.PutBool(m.InControlledShutdown)
+	}
+	if flexible {
+		var tagged []TaggedField
+		if (version >= 3) && len(m.LogDirs) > 0 {
+			te := NewEncoder(0)
+			te.PutArrayLength(len(m.LogDirs), true)
+			for i := range m.LogDirs {
+				te.PutUUID(m.LogDirs[i])
+			}
+			tagged = append(tagged, TaggedField{Tag: 0, Data: te.Bytes()})
+		}
+		e.PutTaggedFields(append(tagged, m.UnknownTaggedFields...))
+	}
+}
+
+func (m *RegisterBrokerRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.BrokerId = d.Int32()
+	if version >= 2 {
+		m.IsMigratingZkBroker = d.Bool()
+	}
+	m.IncarnationId = d.UUID()
+	m.BrokerEpoch = d.Int64()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.EndPoints = make([]RegisterBrokerRecordBrokerEndpoint, n)
+		for i := range m.EndPoints {
+			m.EndPoints[i].decode(d, version, flexible)
+		}
+	} else {
+		m.EndPoints = nil
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Features = make([]RegisterBrokerRecordBrokerFeature, n)
+		for i := range m.Features {
+			m.Features[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Features = nil
+	}
+	m.Rack = d.NullableString(flexible)
+	m.Fenced = d.Bool()
+	if version >= 1 {
+		m.InControlledShutdown = d.Bool()
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			case 0:
+				if !(version >= 3) {
+					m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+					return
+				}
+				if n := fd.ArrayLength(true); n >= 0 {
+					m.LogDirs = make([]UUID, n)
+					for i := range m.LogDirs {
+						m.LogDirs[i] = fd.UUID()
+					}
+				} else {
+					m.LogDirs = nil
+				}
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// RegisterBrokerRecordBrokerEndpoint is an element of RegisterBrokerRecord.EndPoints.
+type RegisterBrokerRecordBrokerEndpoint struct {
+	// The name of the endpoint.
+	Name string
+	// The hostname.
+	Host string
+	// The port.
+	Port uint16
+	// The security protocol.
+	SecurityProtocol int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets RegisterBrokerRecordBrokerEndpoint to its default field values
+func (m *RegisterBrokerRecordBrokerEndpoint) Default() {
+	*m = RegisterBrokerRecordBrokerEndpoint{}
+}
+
+func (m *RegisterBrokerRecordBrokerEndpoint) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutString(m.Host, flexible)
+	e.PutUint16(m.Port)
+	e.PutInt16(m.SecurityProtocol)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *RegisterBrokerRecordBrokerEndpoint) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.Host = d.String(flexible)
+	m.Port = d.Uint16()
+	m.SecurityProtocol = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// RegisterBrokerRecordBrokerFeature is an element of RegisterBrokerRecord.Features.
+type RegisterBrokerRecordBrokerFeature struct {
+	// The feature name.
+	Name string
+	// The minimum supported feature level.
+	MinSupportedVersion int16
+	// The maximum supported feature level.
+	MaxSupportedVersion int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets RegisterBrokerRecordBrokerFeature to its default field values
+func (m *RegisterBrokerRecordBrokerFeature) Default() {
+	*m = RegisterBrokerRecordBrokerFeature{}
+}
+
+func (m *RegisterBrokerRecordBrokerFeature) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutInt16(m.MinSupportedVersion)
+	e.PutInt16(m.MaxSupportedVersion)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *RegisterBrokerRecordBrokerFeature) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.MinSupportedVersion = d.Int16()
+	m.MaxSupportedVersion = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
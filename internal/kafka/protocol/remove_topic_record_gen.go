@@ -0,0 +1,59 @@
+// Code generated by protogen from messages/RemoveTopicRecord.json. DO NOT EDIT.
+
+package protocol
+
+// RemoveTopicRecord is the metadata record of type 9, version 0.
+type RemoveTopicRecord struct {
+	// The topic to remove. All associated partitions will be removed as well.
+	TopicId UUID
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of RemoveTopicRecord
+func (*RemoveTopicRecord) APIKey() int16 { return 9 }
+
+// MinVersion returns the lowest supported version of RemoveTopicRecord
+func (*RemoveTopicRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of RemoveTopicRecord
+func (*RemoveTopicRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of RemoveTopicRecord uses the flexible encoding
+func (*RemoveTopicRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes RemoveTopicRecord in the given version
+func (m *RemoveTopicRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads RemoveTopicRecord in the given version
+func (m *RemoveTopicRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets RemoveTopicRecord to its default field values
+func (m *RemoveTopicRecord) Default() {
+	*m = RemoveTopicRecord{}
+}
+
+func (m *RemoveTopicRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutUUID(m.TopicId)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *RemoveTopicRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TopicId = d.UUID()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Code generated by protogen from messages/RequestHeader.json. DO NOT EDIT.
+
+package protocol
+
+// RequestHeader is a header, versions 0-2.
+type RequestHeader struct {
+	// The API key of this request.
+	RequestApiKey int16
+	// The API version of this request.
+	RequestApiVersion int16
+	// The correlation ID of this request.
+	CorrelationId int32
+	// The client ID string.
+	ClientId *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// MinVersion returns the lowest supported version of RequestHeader
+func (*RequestHeader) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of RequestHeader
+func (*RequestHeader) MaxVersion() int16 { return 2 }
+
+// IsFlexible reports whether the given version of RequestHeader uses the flexible encoding
+func (*RequestHeader) IsFlexible(version int16) bool { return version >= 2 }
+
+// Encode writes RequestHeader in the given version
+func (m *RequestHeader) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads RequestHeader in the given version
+func (m *RequestHeader) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets RequestHeader to its default field values
+func (m *RequestHeader) Default() {
+	*m = RequestHeader{}
+}
+
+func (m *RequestHeader) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt16(m.RequestApiKey)
+	e.PutInt16(m.RequestApiVersion)
+	e.PutInt32(m.CorrelationId)
+	if version >= 1 {
+		e.PutNullableString(m.ClientId, false)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *RequestHeader) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.RequestApiKey = d.Int16()
+	m.RequestApiVersion = d.Int16()
+	m.CorrelationId = d.Int32()
+	if version >= 1 {
+		m.ClientId = d.NullableString(false)
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
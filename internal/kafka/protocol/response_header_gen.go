@@ -0,0 +1,56 @@
+// Code generated by protogen from messages/ResponseHeader.json. DO NOT EDIT.
+
+package protocol
+
+// ResponseHeader is a header, versions 0-1.
+type ResponseHeader struct {
+	// The correlation ID of this response.
+	CorrelationId int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// MinVersion returns the lowest supported version of ResponseHeader
+func (*ResponseHeader) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of ResponseHeader
+func (*ResponseHeader) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of ResponseHeader uses the flexible encoding
+func (*ResponseHeader) IsFlexible(version int16) bool { return version >= 1 }
+
+// Encode writes ResponseHeader in the given version
+func (m *ResponseHeader) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads ResponseHeader in the given version
+func (m *ResponseHeader) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets ResponseHeader to its default field values
+func (m *ResponseHeader) Default() {
+	*m = ResponseHeader{}
+}
+
+func (m *ResponseHeader) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.CorrelationId)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *ResponseHeader) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.CorrelationId = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
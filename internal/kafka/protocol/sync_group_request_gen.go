@@ -0,0 +1,142 @@
+// Code generated by protogen from messages/SyncGroupRequest.json. DO NOT EDIT.
+
+package protocol
+
+// SyncGroupRequest is the request for API key 14, versions 0-5.
+type SyncGroupRequest struct {
+	// The unique group identifier.
+	GroupId string
+	// The generation of the group.
+	GenerationId int32
+	// The member ID assigned by the group.
+	MemberId string
+	// The unique identifier of the consumer instance provided by end user.
+	GroupInstanceId *string
+	// The group protocol type.
+	ProtocolType *string
+	// The group protocol name.
+	ProtocolName *string
+	// Each assignment.
+	Assignments []SyncGroupRequestAssignment
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of SyncGroupRequest
+func (*SyncGroupRequest) APIKey() int16 { return 14 }
+
+// MinVersion returns the lowest supported version of SyncGroupRequest
+func (*SyncGroupRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of SyncGroupRequest
+func (*SyncGroupRequest) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of SyncGroupRequest uses the flexible encoding
+func (*SyncGroupRequest) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes SyncGroupRequest in the given version
+func (m *SyncGroupRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads SyncGroupRequest in the given version
+func (m *SyncGroupRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets SyncGroupRequest to its default field values
+func (m *SyncGroupRequest) Default() {
+	*m = SyncGroupRequest{}
+}
+
+func (m *SyncGroupRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.GroupId, flexible)
+	e.PutInt32(m.GenerationId)
+	e.PutString(m.MemberId, flexible)
+	if version >= 3 {
+		e.PutNullableString(m.GroupInstanceId, flexible)
+	}
+	if version >= 5 {
+		e.PutNullableString(m.ProtocolType, flexible)
+	}
+	if version >= 5 {
+		e.PutNullableString(m.ProtocolName, flexible)
+	}
+	e.PutArrayLength(len(m.Assignments), flexible)
+	for i := range m.Assignments {
+		m.Assignments[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *SyncGroupRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.GroupId = d.String(flexible)
+	m.GenerationId = d.Int32()
+	m.MemberId = d.String(flexible)
+	if version >= 3 {
+		m.GroupInstanceId = d.NullableString(flexible)
+	}
+	if version >= 5 {
+		m.ProtocolType = d.NullableString(flexible)
+	}
+	if version >= 5 {
+		m.ProtocolName = d.NullableString(flexible)
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Assignments = make([]SyncGroupRequestAssignment, n)
+		for i := range m.Assignments {
+			m.Assignments[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Assignments = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// SyncGroupRequestAssignment is an element of SyncGroupRequest.Assignments.
+type SyncGroupRequestAssignment struct {
+	// The ID of the member to assign.
+	MemberId string
+	// The member assignment.
+	Assignment []byte
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets SyncGroupRequestAssignment to its default field values
+func (m *SyncGroupRequestAssignment) Default() {
+	*m = SyncGroupRequestAssignment{}
+}
+
+func (m *SyncGroupRequestAssignment) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.MemberId, flexible)
+	e.PutBytes(m.Assignment, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *SyncGroupRequestAssignment) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.MemberId = d.String(flexible)
+	m.Assignment = d.Bytes(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Code generated by protogen from messages/SyncGroupResponse.json. DO NOT EDIT.
+
+package protocol
+
+// SyncGroupResponse is the response for API key 14, versions 0-5.
+type SyncGroupResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// The group protocol type.
+	ProtocolType *string
+	// The group protocol name.
+	ProtocolName *string
+	// The member assignment.
+	Assignment []byte
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of SyncGroupResponse
+func (*SyncGroupResponse) APIKey() int16 { return 14 }
+
+// MinVersion returns the lowest supported version of SyncGroupResponse
+func (*SyncGroupResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of SyncGroupResponse
+func (*SyncGroupResponse) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of SyncGroupResponse uses the flexible encoding
+func (*SyncGroupResponse) IsFlexible(version int16) bool { return version >= 4 }
+
+// Encode writes SyncGroupResponse in the given version
+func (m *SyncGroupResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads SyncGroupResponse in the given version
+func (m *SyncGroupResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets SyncGroupResponse to its default field values
+func (m *SyncGroupResponse) Default() {
+	*m = SyncGroupResponse{}
+}
+
+func (m *SyncGroupResponse) encode(e *Encoder, version int16, flexible bool) {
+	if version >= 1 {
+		e.PutInt32(m.ThrottleTimeMs)
+	}
+	e.PutInt16(m.ErrorCode)
+	if version >= 5 {
+		e.PutNullableString(m.ProtocolType, flexible)
+	}
+	if version >= 5 {
+		e.PutNullableString(m.ProtocolName, flexible)
+	}
+	e.PutBytes(m.Assignment, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *SyncGroupResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if version >= 1 {
+		m.ThrottleTimeMs = d.Int32()
+	}
+	m.ErrorCode = d.Int16()
+	if version >= 5 {
+		m.ProtocolType = d.NullableString(flexible)
+	}
+	if version >= 5 {
+		m.ProtocolName = d.NullableString(flexible)
+	}
+	m.Assignment = d.Bytes(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
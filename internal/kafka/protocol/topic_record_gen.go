@@ -0,0 +1,63 @@
+// Code generated by protogen from messages/TopicRecord.json. DO NOT EDIT.
+
+package protocol
+
+// TopicRecord is the metadata record of type 2, version 0.
+type TopicRecord struct {
+	// The topic name.
+	Name string
+	// The unique ID of this topic.
+	TopicId UUID
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of TopicRecord
+func (*TopicRecord) APIKey() int16 { return 2 }
+
+// MinVersion returns the lowest supported version of TopicRecord
+func (*TopicRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of TopicRecord
+func (*TopicRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of TopicRecord uses the flexible encoding
+func (*TopicRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes TopicRecord in the given version
+func (m *TopicRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads TopicRecord in the given version
+func (m *TopicRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets TopicRecord to its default field values
+func (m *TopicRecord) Default() {
+	*m = TopicRecord{}
+}
+
+func (m *TopicRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutUUID(m.TopicId)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TopicRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	m.TopicId = d.UUID()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by protogen from messages/TransactionLogKey.json. DO NOT EDIT.
+
+package protocol
+
+// TransactionLogKey is the key of coordinator record type 0, version 0.
+type TransactionLogKey struct {
+	// The transactional id of the transaction.
+	TransactionalId string
+}
+
+// APIKey returns the API key of TransactionLogKey
+func (*TransactionLogKey) APIKey() int16 { return 0 }
+
+// MinVersion returns the lowest supported version of TransactionLogKey
+func (*TransactionLogKey) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of TransactionLogKey
+func (*TransactionLogKey) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of TransactionLogKey uses the flexible encoding
+func (*TransactionLogKey) IsFlexible(version int16) bool { return false }
+
+// Encode writes TransactionLogKey in the given version
+func (m *TransactionLogKey) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads TransactionLogKey in the given version
+func (m *TransactionLogKey) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets TransactionLogKey to its default field values
+func (m *TransactionLogKey) Default() {
+	*m = TransactionLogKey{}
+}
+
+func (m *TransactionLogKey) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TransactionalId, flexible)
+}
+
+func (m *TransactionLogKey) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TransactionalId = d.String(flexible)
+}
@@ -0,0 +1,152 @@
+// Code generated by protogen from messages/TransactionLogValue.json. DO NOT EDIT.
+
+package protocol
+
+// TransactionLogValue is the value of coordinator record type 0, versions 0-1.
+type TransactionLogValue struct {
+	// Producer id in use by the transactional id.
+	ProducerId int64
+	// Producer id used by the last committed transaction.
+	PreviousProducerId int64
+	// Latest producer ID sent to the producer for the given transactional id.
+	NextProducerId int64
+	// Epoch associated with the producer id.
+	ProducerEpoch int16
+	// Transaction timeout in milliseconds.
+	TransactionTimeoutMs int32
+	// TransactionState the transaction is in.
+	TransactionStatus int8
+	// Set of partitions involved in the transaction.
+	TransactionPartitions []TransactionLogValuePartitionsSchema
+	// Time the transaction was last updated.
+	TransactionLastUpdateTimestampMs int64
+	// Time the transaction was started.
+	TransactionStartTimestampMs int64
+	// The transaction version used by the client.
+	ClientTransactionVersion int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of TransactionLogValue
+func (*TransactionLogValue) APIKey() int16 { return 0 }
+
+// MinVersion returns the lowest supported version of TransactionLogValue
+func (*TransactionLogValue) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of TransactionLogValue
+func (*TransactionLogValue) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of TransactionLogValue uses the flexible encoding
+func (*TransactionLogValue) IsFlexible(version int16) bool { return version >= 1 }
+
+// Encode writes TransactionLogValue in the given version
+func (m *TransactionLogValue) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads TransactionLogValue in the given version
+func (m *TransactionLogValue) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets TransactionLogValue to its default field values
+func (m *TransactionLogValue) Default() {
+	*m = TransactionLogValue{}
+	m.PreviousProducerId = -1
+	m.NextProducerId = -1
+}
+
+func (m *TransactionLogValue) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt64(m.ProducerId)
+	e.PutInt16(m.ProducerEpoch)
+	e.PutInt32(m.TransactionTimeoutMs)
+	e.PutInt8(m.TransactionStatus)
+	if m.TransactionPartitions == nil {
+		e.PutArrayLength(-1, flexible)
+	} else {
+		e.PutArrayLength(len(m.TransactionPartitions), flexible)
+		for i := range m.TransactionPartitions {
+			m.TransactionPartitions[i].encode(e, version, flexible)
+		}
+	}
+	e.PutInt64(m.TransactionLastUpdateTimestampMs)
+	e.PutInt64(m.TransactionStartTimestampMs)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TransactionLogValue) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ProducerId = d.Int64()
+	m.ProducerEpoch = d.Int16()
+	m.TransactionTimeoutMs = d.Int32()
+	m.TransactionStatus = d.Int8()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.TransactionPartitions = make([]TransactionLogValuePartitionsSchema, n)
+		for i := range m.TransactionPartitions {
+			m.TransactionPartitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.TransactionPartitions = nil
+	}
+	m.TransactionLastUpdateTimestampMs = d.Int64()
+	m.TransactionStartTimestampMs = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// TransactionLogValuePartitionsSchema is an element of TransactionLogValue.TransactionPartitions.
+type TransactionLogValuePartitionsSchema struct {
+	// Topic involved in the transaction.
+	Topic string
+	// Partition ids involved in the transaction.
+	PartitionIds []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets TransactionLogValuePartitionsSchema to its default field values
+func (m *TransactionLogValuePartitionsSchema) Default() {
+	*m = TransactionLogValuePartitionsSchema{}
+}
+
+func (m *TransactionLogValuePartitionsSchema) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Topic, flexible)
+	e.PutArrayLength(len(m.PartitionIds), flexible)
+	for i := range m.PartitionIds {
+		e.PutInt32(m.PartitionIds[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TransactionLogValuePartitionsSchema) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Topic = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.PartitionIds = make([]int32, n)
+		for i := range m.PartitionIds {
+			m.PartitionIds[i] = d.Int32()
+		}
+	} else {
+		m.PartitionIds = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
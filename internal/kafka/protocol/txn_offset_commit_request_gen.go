@@ -0,0 +1,207 @@
+// Code generated by protogen from messages/TxnOffsetCommitRequest.json. DO NOT EDIT.
+
+package protocol
+
+// TxnOffsetCommitRequest is the request for API key 28, versions 0-5.
+type TxnOffsetCommitRequest struct {
+	// The ID of the transaction.
+	TransactionalId string
+	// The ID of the group.
+	GroupId string
+	// The current producer ID in use by the transactional ID.
+	ProducerId int64
+	// The current epoch associated with the producer ID.
+	ProducerEpoch int16
+	// The generation of the consumer.
+	GenerationId int32
+	// The member ID assigned by the group coordinator.
+	MemberId string
+	// The unique identifier of the consumer instance provided by end user.
+	GroupInstanceId *string
+	// Each topic that we want to commit offsets for.
+	Topics []TxnOffsetCommitRequestTopic
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of TxnOffsetCommitRequest
+func (*TxnOffsetCommitRequest) APIKey() int16 { return 28 }
+
+// MinVersion returns the lowest supported version of TxnOffsetCommitRequest
+func (*TxnOffsetCommitRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of TxnOffsetCommitRequest
+func (*TxnOffsetCommitRequest) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of TxnOffsetCommitRequest uses the flexible encoding
+func (*TxnOffsetCommitRequest) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes TxnOffsetCommitRequest in the given version
+func (m *TxnOffsetCommitRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads TxnOffsetCommitRequest in the given version
+func (m *TxnOffsetCommitRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets TxnOffsetCommitRequest to its default field values
+func (m *TxnOffsetCommitRequest) Default() {
+	*m = TxnOffsetCommitRequest{}
+	m.GenerationId = -1
+}
+
+func (m *TxnOffsetCommitRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.TransactionalId, flexible)
+	e.PutString(m.GroupId, flexible)
+	e.PutInt64(m.ProducerId)
+	e.PutInt16(m.ProducerEpoch)
+	if version >= 3 {
+		e.PutInt32(m.GenerationId)
+	}
+	if version >= 3 {
+		e.PutString(m.MemberId, flexible)
+	}
+	if version >= 3 {
+		e.PutNullableString(m.GroupInstanceId, flexible)
+	}
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TxnOffsetCommitRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.TransactionalId = d.String(flexible)
+	m.GroupId = d.String(flexible)
+	m.ProducerId = d.Int64()
+	m.ProducerEpoch = d.Int16()
+	if version >= 3 {
+		m.GenerationId = d.Int32()
+	}
+	if version >= 3 {
+		m.MemberId = d.String(flexible)
+	}
+	if version >= 3 {
+		m.GroupInstanceId = d.NullableString(flexible)
+	}
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]TxnOffsetCommitRequestTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// TxnOffsetCommitRequestTopic is an element of TxnOffsetCommitRequest.Topics.
+type TxnOffsetCommitRequestTopic struct {
+	// The topic name.
+	Name string
+	// The partitions inside the topic that we want to commit offsets for.
+	Partitions []TxnOffsetCommitRequestPartition
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets TxnOffsetCommitRequestTopic to its default field values
+func (m *TxnOffsetCommitRequestTopic) Default() {
+	*m = TxnOffsetCommitRequestTopic{}
+}
+
+func (m *TxnOffsetCommitRequestTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TxnOffsetCommitRequestTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]TxnOffsetCommitRequestPartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// TxnOffsetCommitRequestPartition is an element of TxnOffsetCommitRequestTopic.Partitions.
+type TxnOffsetCommitRequestPartition struct {
+	// The index of the partition within the topic.
+	PartitionIndex int32
+	// The message offset to be committed.
+	CommittedOffset int64
+	// The leader epoch of the last consumed record.
+	CommittedLeaderEpoch int32
+	// Any associated metadata the client wants to keep.
+	CommittedMetadata *string
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets TxnOffsetCommitRequestPartition to its default field values
+func (m *TxnOffsetCommitRequestPartition) Default() {
+	*m = TxnOffsetCommitRequestPartition{}
+	m.CommittedLeaderEpoch = -1
+}
+
+func (m *TxnOffsetCommitRequestPartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt64(m.CommittedOffset)
+	if version >= 2 {
+		e.PutInt32(m.CommittedLeaderEpoch)
+	}
+	e.PutNullableString(m.CommittedMetadata, flexible)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TxnOffsetCommitRequestPartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.CommittedOffset = d.Int64()
+	if version >= 2 {
+		m.CommittedLeaderEpoch = d.Int32()
+	}
+	m.CommittedMetadata = d.NullableString(flexible)
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
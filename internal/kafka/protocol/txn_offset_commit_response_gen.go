@@ -0,0 +1,157 @@
+// Code generated by protogen from messages/TxnOffsetCommitResponse.json. DO NOT EDIT.
+
+package protocol
+
+// TxnOffsetCommitResponse is the response for API key 28, versions 0-5.
+type TxnOffsetCommitResponse struct {
+	// The duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
+	ThrottleTimeMs int32
+	// The responses for each topic.
+	Topics []TxnOffsetCommitResponseTopic
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of TxnOffsetCommitResponse
+func (*TxnOffsetCommitResponse) APIKey() int16 { return 28 }
+
+// MinVersion returns the lowest supported version of TxnOffsetCommitResponse
+func (*TxnOffsetCommitResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of TxnOffsetCommitResponse
+func (*TxnOffsetCommitResponse) MaxVersion() int16 { return 5 }
+
+// IsFlexible reports whether the given version of TxnOffsetCommitResponse uses the flexible encoding
+func (*TxnOffsetCommitResponse) IsFlexible(version int16) bool { return version >= 3 }
+
+// Encode writes TxnOffsetCommitResponse in the given version
+func (m *TxnOffsetCommitResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads TxnOffsetCommitResponse in the given version
+func (m *TxnOffsetCommitResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets TxnOffsetCommitResponse to its default field values
+func (m *TxnOffsetCommitResponse) Default() {
+	*m = TxnOffsetCommitResponse{}
+}
+
+func (m *TxnOffsetCommitResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.ThrottleTimeMs)
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TxnOffsetCommitResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ThrottleTimeMs = d.Int32()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]TxnOffsetCommitResponseTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// TxnOffsetCommitResponseTopic is an element of TxnOffsetCommitResponse.Topics.
+type TxnOffsetCommitResponseTopic struct {
+	// The topic name.
+	Name string
+	// The responses for each partition in the topic.
+	Partitions []TxnOffsetCommitResponsePartition
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets TxnOffsetCommitResponseTopic to its default field values
+func (m *TxnOffsetCommitResponseTopic) Default() {
+	*m = TxnOffsetCommitResponseTopic{}
+}
+
+func (m *TxnOffsetCommitResponseTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TxnOffsetCommitResponseTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]TxnOffsetCommitResponsePartition, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// TxnOffsetCommitResponsePartition is an element of TxnOffsetCommitResponseTopic.Partitions.
+type TxnOffsetCommitResponsePartition struct {
+	// The partition index.
+	PartitionIndex int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets TxnOffsetCommitResponsePartition to its default field values
+func (m *TxnOffsetCommitResponsePartition) Default() {
+	*m = TxnOffsetCommitResponsePartition{}
+}
+
+func (m *TxnOffsetCommitResponsePartition) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *TxnOffsetCommitResponsePartition) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
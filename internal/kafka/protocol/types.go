@@ -6,12 +6,50 @@ type Request struct {
 	ApiKey        int16
 	ApiVersion    int16
 	CorrelationID int32
-	Payload       []byte
+	HeaderVersion int16
+	ClientID      *string
+	TaggedFields  []TaggedField
+	Payload       []byte // request body, starting right after the header
 }
 
-// Response represents a generic Kafka protocol response
+// Response represents a Kafka protocol response ready to be written
 type Response struct {
 	CorrelationID int32
-	ErrorCode     uint16
-	Payload       []byte
+	ApiVersion    int16
+	HeaderVersion int16
+	Body          Message
+}
+
+// NewResponse creates the response to req carrying the given body, using
+// the header version that matches the request's API key and version
+func NewResponse(req *Request, body Message) *Response {
+	return &Response{
+		CorrelationID: req.CorrelationID,
+		ApiVersion:    req.ApiVersion,
+		HeaderVersion: ResponseHeaderVersion(req.ApiKey, req.ApiVersion),
+		Body:          body,
+	}
+}
+
+// Encode serialises the response header and body, without the size prefix
+func (r *Response) Encode() []byte {
+	e := NewEncoder(256)
+	header := &ResponseHeader{CorrelationId: r.CorrelationID}
+	header.Encode(e, r.HeaderVersion)
+	r.Body.Encode(e, r.ApiVersion)
+	return e.Bytes()
+}
+
+// Message is implemented by every generated request and response body
+type Message interface {
+	APIKey() int16
+	IsFlexible(version int16) bool
+	Encode(e *Encoder, version int16)
+	Decode(d *Decoder, version int16) error
+}
+
+// TaggedField is a raw tagged field from a flexible message
+type TaggedField struct {
+	Tag  uint64
+	Data []byte
 }
@@ -0,0 +1,63 @@
+// Code generated by protogen from messages/UnfenceBrokerRecord.json. DO NOT EDIT.
+
+package protocol
+
+// UnfenceBrokerRecord is the metadata record of type 11, version 0.
+type UnfenceBrokerRecord struct {
+	// The broker ID to unfence.
+	Id int32
+	// The epoch of the broker to unfence.
+	Epoch int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of UnfenceBrokerRecord
+func (*UnfenceBrokerRecord) APIKey() int16 { return 11 }
+
+// MinVersion returns the lowest supported version of UnfenceBrokerRecord
+func (*UnfenceBrokerRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of UnfenceBrokerRecord
+func (*UnfenceBrokerRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of UnfenceBrokerRecord uses the flexible encoding
+func (*UnfenceBrokerRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes UnfenceBrokerRecord in the given version
+func (m *UnfenceBrokerRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads UnfenceBrokerRecord in the given version
+func (m *UnfenceBrokerRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets UnfenceBrokerRecord to its default field values
+func (m *UnfenceBrokerRecord) Default() {
+	*m = UnfenceBrokerRecord{}
+}
+
+func (m *UnfenceBrokerRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.Id)
+	e.PutInt64(m.Epoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *UnfenceBrokerRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Id = d.Int32()
+	m.Epoch = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
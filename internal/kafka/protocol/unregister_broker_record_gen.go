@@ -0,0 +1,63 @@
+// Code generated by protogen from messages/UnregisterBrokerRecord.json. DO NOT EDIT.
+
+package protocol
+
+// UnregisterBrokerRecord is the metadata record of type 13, version 0.
+type UnregisterBrokerRecord struct {
+	// The broker id.
+	BrokerId int32
+	// The broker epoch.
+	BrokerEpoch int64
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of UnregisterBrokerRecord
+func (*UnregisterBrokerRecord) APIKey() int16 { return 13 }
+
+// MinVersion returns the lowest supported version of UnregisterBrokerRecord
+func (*UnregisterBrokerRecord) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of UnregisterBrokerRecord
+func (*UnregisterBrokerRecord) MaxVersion() int16 { return 0 }
+
+// IsFlexible reports whether the given version of UnregisterBrokerRecord uses the flexible encoding
+func (*UnregisterBrokerRecord) IsFlexible(version int16) bool { return true }
+
+// Encode writes UnregisterBrokerRecord in the given version
+func (m *UnregisterBrokerRecord) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads UnregisterBrokerRecord in the given version
+func (m *UnregisterBrokerRecord) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets UnregisterBrokerRecord to its default field values
+func (m *UnregisterBrokerRecord) Default() {
+	*m = UnregisterBrokerRecord{}
+}
+
+func (m *UnregisterBrokerRecord) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.BrokerId)
+	e.PutInt64(m.BrokerEpoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *UnregisterBrokerRecord) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.BrokerId = d.Int32()
+	m.BrokerEpoch = d.Int64()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Code generated by protogen from messages/WriteTxnMarkersRequest.json. DO NOT EDIT.
+
+package protocol
+
+// WriteTxnMarkersRequest is the request for API key 27, versions 0-1.
+type WriteTxnMarkersRequest struct {
+	// The transaction markers to be written.
+	Markers []WriteTxnMarkersRequestWritableTxnMarker
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of WriteTxnMarkersRequest
+func (*WriteTxnMarkersRequest) APIKey() int16 { return 27 }
+
+// MinVersion returns the lowest supported version of WriteTxnMarkersRequest
+func (*WriteTxnMarkersRequest) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of WriteTxnMarkersRequest
+func (*WriteTxnMarkersRequest) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of WriteTxnMarkersRequest uses the flexible encoding
+func (*WriteTxnMarkersRequest) IsFlexible(version int16) bool { return version >= 1 }
+
+// Encode writes WriteTxnMarkersRequest in the given version
+func (m *WriteTxnMarkersRequest) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads WriteTxnMarkersRequest in the given version
+func (m *WriteTxnMarkersRequest) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets WriteTxnMarkersRequest to its default field values
+func (m *WriteTxnMarkersRequest) Default() {
+	*m = WriteTxnMarkersRequest{}
+}
+
+func (m *WriteTxnMarkersRequest) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.Markers), flexible)
+	for i := range m.Markers {
+		m.Markers[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *WriteTxnMarkersRequest) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Markers = make([]WriteTxnMarkersRequestWritableTxnMarker, n)
+		for i := range m.Markers {
+			m.Markers[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Markers = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// WriteTxnMarkersRequestWritableTxnMarker is an element of WriteTxnMarkersRequest.Markers.
+type WriteTxnMarkersRequestWritableTxnMarker struct {
+	// The current producer ID.
+	ProducerId int64
+	// The current epoch associated with the producer ID.
+	ProducerEpoch int16
+	// The result of the transaction to write to the partitions (false = ABORT, true = COMMIT).
+	TransactionResult bool
+	// Each topic that we want to write transaction marker(s) for.
+	Topics []WriteTxnMarkersRequestWritableTxnMarkerTopic
+	// Epoch associated with the transaction state partition hosted by this transaction coordinator.
+	CoordinatorEpoch int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets WriteTxnMarkersRequestWritableTxnMarker to its default field values
+func (m *WriteTxnMarkersRequestWritableTxnMarker) Default() {
+	*m = WriteTxnMarkersRequestWritableTxnMarker{}
+}
+
+func (m *WriteTxnMarkersRequestWritableTxnMarker) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt64(m.ProducerId)
+	e.PutInt16(m.ProducerEpoch)
+	e.PutBool(m.TransactionResult)
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	e.PutInt32(m.CoordinatorEpoch)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *WriteTxnMarkersRequestWritableTxnMarker) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ProducerId = d.Int64()
+	m.ProducerEpoch = d.Int16()
+	m.TransactionResult = d.Bool()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]WriteTxnMarkersRequestWritableTxnMarkerTopic, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	m.CoordinatorEpoch = d.Int32()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// WriteTxnMarkersRequestWritableTxnMarkerTopic is an element of WriteTxnMarkersRequestWritableTxnMarker.Topics.
+type WriteTxnMarkersRequestWritableTxnMarkerTopic struct {
+	// The topic name.
+	Name string
+	// The indexes of the partitions to write transaction markers for.
+	PartitionIndexes []int32
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets WriteTxnMarkersRequestWritableTxnMarkerTopic to its default field values
+func (m *WriteTxnMarkersRequestWritableTxnMarkerTopic) Default() {
+	*m = WriteTxnMarkersRequestWritableTxnMarkerTopic{}
+}
+
+func (m *WriteTxnMarkersRequestWritableTxnMarkerTopic) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.PartitionIndexes), flexible)
+	for i := range m.PartitionIndexes {
+		e.PutInt32(m.PartitionIndexes[i])
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *WriteTxnMarkersRequestWritableTxnMarkerTopic) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.PartitionIndexes = make([]int32, n)
+		for i := range m.PartitionIndexes {
+			m.PartitionIndexes[i] = d.Int32()
+		}
+	} else {
+		m.PartitionIndexes = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Code generated by protogen from messages/WriteTxnMarkersResponse.json. DO NOT EDIT.
+
+package protocol
+
+// WriteTxnMarkersResponse is the response for API key 27, versions 0-1.
+type WriteTxnMarkersResponse struct {
+	// The results for writing makers.
+	Markers []WriteTxnMarkersResponseWritableTxnMarkerResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// APIKey returns the API key of WriteTxnMarkersResponse
+func (*WriteTxnMarkersResponse) APIKey() int16 { return 27 }
+
+// MinVersion returns the lowest supported version of WriteTxnMarkersResponse
+func (*WriteTxnMarkersResponse) MinVersion() int16 { return 0 }
+
+// MaxVersion returns the highest supported version of WriteTxnMarkersResponse
+func (*WriteTxnMarkersResponse) MaxVersion() int16 { return 1 }
+
+// IsFlexible reports whether the given version of WriteTxnMarkersResponse uses the flexible encoding
+func (*WriteTxnMarkersResponse) IsFlexible(version int16) bool { return version >= 1 }
+
+// Encode writes WriteTxnMarkersResponse in the given version
+func (m *WriteTxnMarkersResponse) Encode(e *Encoder, version int16) {
+	m.encode(e, version, m.IsFlexible(version))
+}
+
+// Decode reads WriteTxnMarkersResponse in the given version
+func (m *WriteTxnMarkersResponse) Decode(d *Decoder, version int16) error {
+	m.decode(d, version, m.IsFlexible(version))
+	return d.Err()
+}
+
+// Default resets WriteTxnMarkersResponse to its default field values
+func (m *WriteTxnMarkersResponse) Default() {
+	*m = WriteTxnMarkersResponse{}
+}
+
+func (m *WriteTxnMarkersResponse) encode(e *Encoder, version int16, flexible bool) {
+	e.PutArrayLength(len(m.Markers), flexible)
+	for i := range m.Markers {
+		m.Markers[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *WriteTxnMarkersResponse) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Markers = make([]WriteTxnMarkersResponseWritableTxnMarkerResult, n)
+		for i := range m.Markers {
+			m.Markers[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Markers = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// WriteTxnMarkersResponseWritableTxnMarkerResult is an element of WriteTxnMarkersResponse.Markers.
+type WriteTxnMarkersResponseWritableTxnMarkerResult struct {
+	// The current producer ID in use by the transactional ID.
+	ProducerId int64
+	// The results by topic.
+	Topics []WriteTxnMarkersResponseWritableTxnMarkerTopicResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets WriteTxnMarkersResponseWritableTxnMarkerResult to its default field values
+func (m *WriteTxnMarkersResponseWritableTxnMarkerResult) Default() {
+	*m = WriteTxnMarkersResponseWritableTxnMarkerResult{}
+}
+
+func (m *WriteTxnMarkersResponseWritableTxnMarkerResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt64(m.ProducerId)
+	e.PutArrayLength(len(m.Topics), flexible)
+	for i := range m.Topics {
+		m.Topics[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *WriteTxnMarkersResponseWritableTxnMarkerResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.ProducerId = d.Int64()
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Topics = make([]WriteTxnMarkersResponseWritableTxnMarkerTopicResult, n)
+		for i := range m.Topics {
+			m.Topics[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Topics = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// WriteTxnMarkersResponseWritableTxnMarkerTopicResult is an element of WriteTxnMarkersResponseWritableTxnMarkerResult.Topics.
+type WriteTxnMarkersResponseWritableTxnMarkerTopicResult struct {
+	// The topic name.
+	Name string
+	// The results by partition.
+	Partitions []WriteTxnMarkersResponseWritableTxnMarkerPartitionResult
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets WriteTxnMarkersResponseWritableTxnMarkerTopicResult to its default field values
+func (m *WriteTxnMarkersResponseWritableTxnMarkerTopicResult) Default() {
+	*m = WriteTxnMarkersResponseWritableTxnMarkerTopicResult{}
+}
+
+func (m *WriteTxnMarkersResponseWritableTxnMarkerTopicResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutString(m.Name, flexible)
+	e.PutArrayLength(len(m.Partitions), flexible)
+	for i := range m.Partitions {
+		m.Partitions[i].encode(e, version, flexible)
+	}
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *WriteTxnMarkersResponseWritableTxnMarkerTopicResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.Name = d.String(flexible)
+	if n := d.ArrayLength(flexible); n >= 0 {
+		m.Partitions = make([]WriteTxnMarkersResponseWritableTxnMarkerPartitionResult, n)
+		for i := range m.Partitions {
+			m.Partitions[i].decode(d, version, flexible)
+		}
+	} else {
+		m.Partitions = nil
+	}
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
+
+// WriteTxnMarkersResponseWritableTxnMarkerPartitionResult is an element of WriteTxnMarkersResponseWritableTxnMarkerTopicResult.Partitions.
+type WriteTxnMarkersResponseWritableTxnMarkerPartitionResult struct {
+	// The partition index.
+	PartitionIndex int32
+	// The error code, or 0 if there was no error.
+	ErrorCode int16
+	// Tagged fields not defined by the spec, preserved as raw bytes.
+	UnknownTaggedFields []TaggedField
+}
+
+// Default resets WriteTxnMarkersResponseWritableTxnMarkerPartitionResult to its default field values
+func (m *WriteTxnMarkersResponseWritableTxnMarkerPartitionResult) Default() {
+	*m = WriteTxnMarkersResponseWritableTxnMarkerPartitionResult{}
+}
+
+func (m *WriteTxnMarkersResponseWritableTxnMarkerPartitionResult) encode(e *Encoder, version int16, flexible bool) {
+	e.PutInt32(m.PartitionIndex)
+	e.PutInt16(m.ErrorCode)
+	if flexible {
+		e.PutTaggedFields(m.UnknownTaggedFields)
+	}
+}
+
+func (m *WriteTxnMarkersResponseWritableTxnMarkerPartitionResult) decode(d *Decoder, version int16, flexible bool) {
+	m.Default()
+	m.PartitionIndex = d.Int32()
+	m.ErrorCode = d.Int16()
+	if flexible {
+		d.TaggedFields(func(tag uint64, fd *Decoder) {
+			switch tag {
+			default:
+				m.UnknownTaggedFields = append(m.UnknownTaggedFields, fd.UnknownTaggedField(tag))
+			}
+		})
+	}
+}
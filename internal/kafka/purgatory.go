@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// delayedFetch is a fetch parked in the purgatory until data arrives on
+// one of its partitions
+type delayedFetch struct {
+	partitions []storage.TopicPartition
+	wake       chan struct{}
+}
+
+// fetchPurgatory holds the fetches waiting for min_bytes of data. Each
+// parked fetch blocks only its own connection's goroutine; appends to a
+// partition wake every fetch watching it so it can re-read.
+type fetchPurgatory struct {
+	mu       sync.Mutex
+	watchers map[storage.TopicPartition]map[*delayedFetch]struct{}
+}
+
+// newFetchPurgatory creates an empty purgatory
+func newFetchPurgatory() *fetchPurgatory {
+	return &fetchPurgatory{
+		watchers: make(map[storage.TopicPartition]map[*delayedFetch]struct{}),
+	}
+}
+
+// watch parks a fetch on the given partitions. It must be called before
+// the fetch first reads, so that no append can slip in unnoticed.
+func (p *fetchPurgatory) watch(partitions []storage.TopicPartition) *delayedFetch {
+	f := &delayedFetch{partitions: partitions, wake: make(chan struct{}, 1)}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, tp := range partitions {
+		if p.watchers[tp] == nil {
+			p.watchers[tp] = make(map[*delayedFetch]struct{})
+		}
+		p.watchers[tp][f] = struct{}{}
+	}
+	return f
+}
+
+// unwatch removes a completed fetch from the purgatory
+func (p *fetchPurgatory) unwatch(f *delayedFetch) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, tp := range f.partitions {
+		delete(p.watchers[tp], f)
+		if len(p.watchers[tp]) == 0 {
+			delete(p.watchers, tp)
+		}
+	}
+}
+
+// notify wakes the fetches watching a partition that has new data
+func (p *fetchPurgatory) notify(tp storage.TopicPartition) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for f := range p.watchers[tp] {
+		// A pending wake-up already makes the fetch re-read
+		select {
+		case f.wake <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// testTopic is the single-partition topic of the handlers made by
+// newTestHandler
+const testTopic = "events"
+
+// newTestHandler returns a handler serving testTopic from logs in a
+// temporary directory. It has no group or transaction coordinator.
+func newTestHandler(t *testing.T) *RequestHandler {
+	t.Helper()
+	log := logger.New(logger.ERROR)
+	logs, err := storage.Open(t.TempDir(), storage.DefaultConfig(), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logs.Close() })
+
+	image := metadata.NewImage()
+	id := protocol.RandomUUID()
+	err = image.Publish(
+		&protocol.TopicRecord{Name: testTopic, TopicId: id},
+		&protocol.PartitionRecord{TopicId: id, PartitionId: 0, Replicas: []int32{1}, Isr: []int32{1}, Leader: 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := logs.GetOrCreate(testTopic, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &RequestHandler{
+		logger:   log,
+		registry: newRegistry(),
+		metadata: image,
+		logs:     logs,
+		fetches:  newFetchPurgatory(),
+		done:     make(chan struct{}),
+	}
+	t.Cleanup(func() { close(h.done) })
+	return h
+}
+
+// fetchVersion is the Fetch version the tests send, the last one with a
+// non-flexible response header
+const fetchVersion int16 = 11
+
+// fetchResult is the response to a fetch, or why none could be read
+type fetchResult struct {
+	resp *protocol.FetchResponse
+	err  error
+}
+
+// startFetch sends a fetch of testTopic from offset 0 to h, returning a
+// channel that gets the response once h answers
+func startFetch(t *testing.T, h *RequestHandler, maxWaitMs, minBytes int32) <-chan fetchResult {
+	t.Helper()
+	body := &protocol.FetchRequest{}
+	body.Default()
+	body.ReplicaId = -1
+	body.MaxWaitMs = maxWaitMs
+	body.MinBytes = minBytes
+	body.MaxBytes = 1 << 20
+	body.Topics = []protocol.FetchRequestFetchTopic{{
+		Topic: testTopic,
+		Partitions: []protocol.FetchRequestFetchPartition{{
+			CurrentLeaderEpoch: -1,
+			LogStartOffset:     -1,
+			PartitionMaxBytes:  1 << 20,
+		}},
+	}}
+	e := protocol.NewEncoder(128)
+	body.Encode(e, fetchVersion)
+	req := &protocol.Request{ApiKey: protocol.FetchKey, ApiVersion: fetchVersion, CorrelationID: 7, Payload: e.Bytes()}
+
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	go func() {
+		defer server.Close()
+		h.handleFetchRequest(server, req)
+	}()
+
+	results := make(chan fetchResult, 1)
+	go func() {
+		resp, err := readFetchResponse(client, req.CorrelationID)
+		results <- fetchResult{resp, err}
+	}()
+	return results
+}
+
+// readFetchResponse reads a Fetch response in fetchVersion from conn
+func readFetchResponse(conn net.Conn, correlationID int32) (*protocol.FetchResponse, error) {
+	var size int32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return nil, fmt.Errorf("reading response size: %w", err)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	d := protocol.NewDecoder(data)
+	if id := d.Int32(); id != correlationID {
+		return nil, fmt.Errorf("response correlation ID = %d, want %d", id, correlationID)
+	}
+	resp := &protocol.FetchResponse{}
+	if err := resp.Decode(d, fetchVersion); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return resp, nil
+}
+
+// waitParked waits until a fetch is parked on testTopic's partition, or,
+// with parked unset, until none is
+func waitParked(t *testing.T, h *RequestHandler, parked bool) {
+	t.Helper()
+	tp := storage.TopicPartition{Topic: testTopic, Partition: 0}
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		h.fetches.mu.Lock()
+		n := len(h.fetches.watchers[tp])
+		h.fetches.mu.Unlock()
+		if (n > 0) == parked {
+			return
+		}
+	}
+	t.Fatalf("fetch parked on %s: %v, want %v", tp, !parked, parked)
+}
+
+// produceTestRecord produces one record to testTopic through h
+func produceTestRecord(t *testing.T, h *RequestHandler) {
+	t.Helper()
+	now := time.Now().UnixMilli()
+	batch := record.EncodeBatch(record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		BaseTimestamp:        now,
+		MaxTimestamp:         now,
+		ProducerID:           record.NoProducerID,
+		ProducerEpoch:        record.NoProducerEpoch,
+		BaseSequence:         record.NoSequence,
+	}, []record.Record{{Value: []byte("hello")}})
+
+	data := &protocol.ProduceRequestPartitionProduceData{Index: 0, Records: batch.Data}
+	if resp := h.produceToPartition(9, nil, testTopic, data); resp.ErrorCode != protocol.ErrorNone {
+		t.Fatalf("produce failed with error %d", resp.ErrorCode)
+	}
+}
+
+func TestFetchPurgatory(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxWaitMs int32
+		minBytes  int32
+		// produce says whether a record is produced once the fetch is
+		// parked
+		produce     bool
+		wantRecords bool
+		// minWait and maxWait bound how long the fetch takes to answer
+		minWait time.Duration
+		maxWait time.Duration
+	}{
+		{
+			name:        "woken by produce",
+			maxWaitMs:   30000,
+			minBytes:    1,
+			produce:     true,
+			wantRecords: true,
+			maxWait:     10 * time.Second,
+		},
+		{
+			name:      "times out without data",
+			maxWaitMs: 200,
+			minBytes:  1,
+			minWait:   200 * time.Millisecond,
+			maxWait:   10 * time.Second,
+		},
+		{
+			name:        "times out below min bytes",
+			maxWaitMs:   200,
+			minBytes:    1 << 20,
+			produce:     true,
+			wantRecords: true,
+			minWait:     200 * time.Millisecond,
+			maxWait:     10 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			start := time.Now()
+			results := startFetch(t, h, tt.maxWaitMs, tt.minBytes)
+			waitParked(t, h, true)
+			if tt.produce {
+				produceTestRecord(t, h)
+			}
+
+			var result fetchResult
+			select {
+			case result = <-results:
+			case <-time.After(tt.maxWait):
+				t.Fatalf("no response after %s", tt.maxWait)
+			}
+			if result.err != nil {
+				t.Fatal(result.err)
+			}
+			resp := result.resp
+			if elapsed := time.Since(start); elapsed < tt.minWait {
+				t.Errorf("answered after %s, before max_wait_ms", elapsed)
+			}
+
+			if len(resp.Responses) != 1 || len(resp.Responses[0].Partitions) != 1 {
+				t.Fatalf("response has %d topics, want 1 with 1 partition", len(resp.Responses))
+			}
+			partition := resp.Responses[0].Partitions[0]
+			if partition.ErrorCode != protocol.ErrorNone {
+				t.Fatalf("partition error %d", partition.ErrorCode)
+			}
+			if got := len(partition.Records) > 0; got != tt.wantRecords {
+				t.Errorf("response has %d bytes of records, want records: %v", len(partition.Records), tt.wantRecords)
+			}
+
+			// The completed fetch no longer watches its partition
+			waitParked(t, h, false)
+		})
+	}
+}
+
+func TestFetchWithoutWait(t *testing.T) {
+	h := newTestHandler(t)
+	select {
+	case result := <-startFetch(t, h, 0, 1):
+		if result.err != nil {
+			t.Fatal(result.err)
+		}
+		if len(result.resp.Responses[0].Partitions[0].Records) != 0 {
+			t.Errorf("fetch from an empty log returned records")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("fetch with max_wait_ms 0 was parked")
+	}
+}
@@ -0,0 +1,331 @@
+// Package record implements the Kafka RecordBatch (magic v2) on-disk and
+// on-the-wire format, and converts to and from the legacy v0 and v1
+// message sets that old clients use
+package record
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/compression"
+)
+
+// Batch header layout, in bytes from the start of the batch
+const (
+	baseOffsetOffset           = 0
+	batchLengthOffset          = 8
+	partitionLeaderEpochOffset = 12
+	magicOffset                = 16
+	crcOffset                  = 17
+	attributesOffset           = 21
+	lastOffsetDeltaOffset      = 23
+	baseTimestampOffset        = 27
+	maxTimestampOffset         = 35
+	producerIDOffset           = 43
+	producerEpochOffset        = 51
+	baseSequenceOffset         = 53
+	recordsCountOffset         = 57
+
+	// HeaderSize is the size of a RecordBatch header
+	HeaderSize = 61
+
+	// LogOverhead is the size of the base offset and batch length fields,
+	// which are not counted in the batch length
+	LogOverhead = 12
+)
+
+// MagicV2 is the only message format version this package reads natively
+const MagicV2 int8 = 2
+
+// Batch attribute bits
+const (
+	compressionMask   int16 = 0x07
+	timestampTypeMask int16 = 0x08
+	transactionalMask int16 = 0x10
+	controlMask       int16 = 0x20
+	deleteHorizonMask int16 = 0x40
+)
+
+// TransactionalAttribute marks a batch built with EncodeBatch as part of
+// a transaction
+const TransactionalAttribute = transactionalMask
+
+// Sentinel values for batches written without an idempotent producer
+const (
+	NoProducerID           int64 = -1
+	NoProducerEpoch        int16 = -1
+	NoSequence             int32 = -1
+	NoPartitionLeaderEpoch int32 = -1
+)
+
+// Compression is the codec used for a batch's records
+type Compression = compression.Codec
+
+// Compression codecs, as stored in the batch attributes
+const (
+	CompressionNone   = compression.None
+	CompressionGzip   = compression.Gzip
+	CompressionSnappy = compression.Snappy
+	CompressionLZ4    = compression.LZ4
+	CompressionZstd   = compression.Zstd
+)
+
+// maxRecordsSize bounds what the records of a compressed batch may
+// decompress to, so a small batch cannot exhaust the broker's memory
+const maxRecordsSize = 256 << 20
+
+// TimestampType says whether batch timestamps were set by the producer or the broker
+type TimestampType int8
+
+// Timestamp types
+const (
+	CreateTime    TimestampType = 0
+	LogAppendTime TimestampType = 1
+)
+
+var (
+	// ErrShortBatch is returned when data ends before a complete batch,
+	// as happens with a torn write at the tail of a log segment
+	ErrShortBatch = errors.New("record batch is truncated")
+
+	// ErrCorruptBatch is returned when a batch fails validation
+	ErrCorruptBatch = errors.New("record batch is corrupt")
+
+	// ErrUnsupportedMagic is returned for batches in a message format other
+	// than v2
+	ErrUnsupportedMagic = errors.New("unsupported message format")
+)
+
+// crcTable is the CRC-32C (Castagnoli) table used for batch checksums
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Batch is a RecordBatch header together with the raw bytes of the whole batch
+type Batch struct {
+	BaseOffset           int64
+	BatchLength          int32
+	PartitionLeaderEpoch int32
+	Magic                int8
+	CRC                  uint32
+	Attributes           int16
+	LastOffsetDelta      int32
+	BaseTimestamp        int64
+	MaxTimestamp         int64
+	ProducerID           int64
+	ProducerEpoch        int16
+	BaseSequence         int32
+	NumRecords           int32
+
+	// Data holds the complete encoded batch, header included
+	Data []byte
+}
+
+// PeekSize returns the total size of the batch starting at data, or
+// ErrShortBatch if not even the size prefix is present
+func PeekSize(data []byte) (int, error) {
+	if len(data) < LogOverhead {
+		return 0, ErrShortBatch
+	}
+	length := int32(binary.BigEndian.Uint32(data[batchLengthOffset:]))
+	if length < HeaderSize-LogOverhead {
+		return 0, fmt.Errorf("%w: batch length %d is smaller than the header", ErrCorruptBatch, length)
+	}
+	return LogOverhead + int(length), nil
+}
+
+// ParseHeader parses the header of the batch at the start of data without
+// requiring its records to be present. The returned batch's Data holds only
+// the header.
+func ParseHeader(data []byte) (*Batch, error) {
+	if _, err := PeekSize(data); err != nil {
+		return nil, err
+	}
+	if len(data) < HeaderSize {
+		return nil, ErrShortBatch
+	}
+	data = data[:HeaderSize]
+
+	b := &Batch{
+		BaseOffset:           int64(binary.BigEndian.Uint64(data[baseOffsetOffset:])),
+		BatchLength:          int32(binary.BigEndian.Uint32(data[batchLengthOffset:])),
+		PartitionLeaderEpoch: int32(binary.BigEndian.Uint32(data[partitionLeaderEpochOffset:])),
+		Magic:                int8(data[magicOffset]),
+		CRC:                  binary.BigEndian.Uint32(data[crcOffset:]),
+		Attributes:           int16(binary.BigEndian.Uint16(data[attributesOffset:])),
+		LastOffsetDelta:      int32(binary.BigEndian.Uint32(data[lastOffsetDeltaOffset:])),
+		BaseTimestamp:        int64(binary.BigEndian.Uint64(data[baseTimestampOffset:])),
+		MaxTimestamp:         int64(binary.BigEndian.Uint64(data[maxTimestampOffset:])),
+		ProducerID:           int64(binary.BigEndian.Uint64(data[producerIDOffset:])),
+		ProducerEpoch:        int16(binary.BigEndian.Uint16(data[producerEpochOffset:])),
+		BaseSequence:         int32(binary.BigEndian.Uint32(data[baseSequenceOffset:])),
+		NumRecords:           int32(binary.BigEndian.Uint32(data[recordsCountOffset:])),
+		Data:                 data,
+	}
+	if b.Magic != MagicV2 {
+		return nil, fmt.Errorf("%w: magic %d", ErrUnsupportedMagic, b.Magic)
+	}
+	return b, nil
+}
+
+// ParseBatch parses the batch at the start of data. The returned batch's
+// Data aliases data. It returns ErrShortBatch if data holds only part of
+// a batch.
+func ParseBatch(data []byte) (*Batch, error) {
+	b, err := ParseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < b.Size() {
+		return nil, ErrShortBatch
+	}
+	b.Data = data[:b.Size()]
+	return b, nil
+}
+
+// Size returns the total encoded size of the batch
+func (b *Batch) Size() int {
+	return LogOverhead + int(b.BatchLength)
+}
+
+// LastOffset returns the offset of the last record in the batch
+func (b *Batch) LastOffset() int64 {
+	return b.BaseOffset + int64(b.LastOffsetDelta)
+}
+
+// NextOffset returns the offset following the batch
+func (b *Batch) NextOffset() int64 {
+	return b.LastOffset() + 1
+}
+
+// Compression returns the codec of the batch's records
+func (b *Batch) Compression() Compression {
+	return Compression(b.Attributes & compressionMask)
+}
+
+// TimestampType returns whether the batch uses create or log append time
+func (b *Batch) TimestampType() TimestampType {
+	if b.Attributes&timestampTypeMask != 0 {
+		return LogAppendTime
+	}
+	return CreateTime
+}
+
+// IsTransactional reports whether the batch belongs to a transaction
+func (b *Batch) IsTransactional() bool {
+	return b.Attributes&transactionalMask != 0
+}
+
+// IsControl reports whether the batch holds control records such as
+// transaction markers rather than user data
+func (b *Batch) IsControl() bool {
+	return b.Attributes&controlMask != 0
+}
+
+// HasDeleteHorizon reports whether BaseTimestamp holds a compaction delete horizon
+func (b *Batch) HasDeleteHorizon() bool {
+	return b.Attributes&deleteHorizonMask != 0
+}
+
+// ComputeCRC computes the CRC-32C of the batch, which covers every byte
+// from the attributes to the end of the batch
+func (b *Batch) ComputeCRC() uint32 {
+	return crc32.Checksum(b.Data[attributesOffset:], crcTable)
+}
+
+// VerifyCRC checks the stored checksum against the batch contents
+func (b *Batch) VerifyCRC() error {
+	if crc := b.ComputeCRC(); crc != b.CRC {
+		return fmt.Errorf("%w: crc mismatch (stored %08x, computed %08x)", ErrCorruptBatch, b.CRC, crc)
+	}
+	return nil
+}
+
+// RecordsData returns the (possibly compressed) record bytes that follow the header
+func (b *Batch) RecordsData() []byte {
+	return b.Data[HeaderSize:]
+}
+
+// UncompressedRecordsData returns the record bytes of the batch,
+// decompressing them if needed
+func (b *Batch) UncompressedRecordsData() ([]byte, error) {
+	data, err := compression.Decompress(b.Compression(), b.RecordsData(), maxRecordsSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decompress %s records: %w", ErrCorruptBatch, b.Compression(), err)
+	}
+	return data, nil
+}
+
+// Records decodes the records of the batch
+func (b *Batch) Records() ([]Record, error) {
+	data, err := b.UncompressedRecordsData()
+	if err != nil {
+		return nil, err
+	}
+	return DecodeRecords(data, int(b.NumRecords))
+}
+
+// Recompress returns a copy of the batch with its records compressed with
+// codec instead. The records themselves and every other header field are
+// kept, so offsets, timestamps and producer state are unchanged.
+func (b *Batch) Recompress(codec Compression) (*Batch, error) {
+	data, err := b.UncompressedRecordsData()
+	if err != nil {
+		return nil, err
+	}
+	if data, err = compression.Compress(codec, data); err != nil {
+		return nil, err
+	}
+	header := *b
+	header.Attributes = header.Attributes&^compressionMask | int16(codec)
+	return encodeBatch(header, data), nil
+}
+
+// WithRecords returns a copy of the batch holding records, some of its
+// own, in place of its records, compressed with its codec again. The
+// records keep their offset deltas and the header its last offset delta,
+// so the batch still spans the offsets it did, as in a compacted log.
+func (b *Batch) WithRecords(records []Record) (*Batch, error) {
+	var data []byte
+	for i := range records {
+		data = AppendRecord(data, &records[i])
+	}
+	data, err := compression.Compress(b.Compression(), data)
+	if err != nil {
+		return nil, err
+	}
+	header := *b
+	header.NumRecords = int32(len(records))
+	return encodeBatch(header, data), nil
+}
+
+// NextBatch parses the batch at the start of data and verifies its CRC
+func NextBatch(data []byte) (*Batch, error) {
+	b, err := ParseBatch(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.VerifyCRC(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SetBaseOffset rewrites the batch's base offset in place. The base offset
+// is not covered by the CRC, so the batch stays valid.
+func (b *Batch) SetBaseOffset(offset int64) {
+	binary.BigEndian.PutUint64(b.Data[baseOffsetOffset:], uint64(offset))
+	b.BaseOffset = offset
+}
+
+// SetLogAppendTime stamps the batch with the broker's append time, as
+// topics with message.timestamp.type=LogAppendTime require, and updates
+// the CRC
+func (b *Batch) SetLogAppendTime(timestamp int64) {
+	b.Attributes |= timestampTypeMask
+	b.MaxTimestamp = timestamp
+	binary.BigEndian.PutUint16(b.Data[attributesOffset:], uint16(b.Attributes))
+	binary.BigEndian.PutUint64(b.Data[maxTimestampOffset:], uint64(timestamp))
+	b.CRC = b.ComputeCRC()
+	binary.BigEndian.PutUint32(b.Data[crcOffset:], b.CRC)
+}
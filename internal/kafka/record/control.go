@@ -0,0 +1,60 @@
+package record
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// ControlType is the type of a control record, which says what a control
+// batch marks
+type ControlType int16
+
+// Control record types of transaction markers
+const (
+	ControlAbort  ControlType = 0
+	ControlCommit ControlType = 1
+)
+
+// controlRecordVersion is the version of control record keys and of end
+// transaction marker values
+const controlRecordVersion int16 = 0
+
+// EncodeEndTxnMarker builds the control batch that ends a producer's
+// transaction in a partition, committing or aborting the records it wrote
+// there. Its value records the epoch of the coordinator that wrote it.
+func EncodeEndTxnMarker(producerID int64, producerEpoch int16, coordinatorEpoch int32, commit bool, timestamp int64) *Batch {
+	controlType := ControlAbort
+	if commit {
+		controlType = ControlCommit
+	}
+	key := binary.BigEndian.AppendUint16(nil, uint16(controlRecordVersion))
+	key = binary.BigEndian.AppendUint16(key, uint16(controlType))
+	value := binary.BigEndian.AppendUint16(nil, uint16(controlRecordVersion))
+	value = binary.BigEndian.AppendUint32(value, uint32(coordinatorEpoch))
+
+	return EncodeBatch(Batch{
+		Attributes:           transactionalMask | controlMask,
+		PartitionLeaderEpoch: NoPartitionLeaderEpoch,
+		BaseTimestamp:        timestamp,
+		MaxTimestamp:         timestamp,
+		ProducerID:           producerID,
+		ProducerEpoch:        producerEpoch,
+		BaseSequence:         NoSequence,
+	}, []Record{{Key: key, Value: value}})
+}
+
+// ControlType returns the type of a control batch, read from the key of
+// its single record
+func (b *Batch) ControlType() (ControlType, error) {
+	if !b.IsControl() {
+		return 0, fmt.Errorf("batch at offset %d is not a control batch", b.BaseOffset)
+	}
+	records, err := b.Records()
+	if err != nil {
+		return 0, err
+	}
+	if len(records) != 1 || len(records[0].Key) < 4 {
+		return 0, fmt.Errorf("%w: invalid control record", ErrCorruptBatch)
+	}
+	return ControlType(binary.BigEndian.Uint16(records[0].Key[2:])), nil
+}
@@ -0,0 +1,394 @@
+package record
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/compression"
+)
+
+// Legacy message format versions, which predate RecordBatch. Clients
+// before Kafka 0.10 use v0; v1 adds a timestamp to every message.
+const (
+	MagicV0 int8 = 0
+	MagicV1 int8 = 1
+)
+
+// NoTimestamp is the timestamp of messages in the v0 format, which have none
+const NoTimestamp int64 = -1
+
+// Legacy message layout, in bytes from the start of the message's offset.
+// Both formats keep the magic where a RecordBatch does.
+const (
+	messageSizeOffset       = 8
+	messageCRCOffset        = 12
+	messageMagicOffset      = 16
+	messageAttributesOffset = 17
+	messageTimestampOffset  = 18
+
+	// messageMinSizeV0 is the size of a v0 message with a null key and
+	// value, counting from its CRC
+	messageMinSizeV0 = 14
+	messageMinSizeV1 = messageMinSizeV0 + 8
+)
+
+// Legacy message attribute bits. A compressed message is a wrapper whose
+// value holds the real messages as a compressed message set.
+const (
+	messageCompressionMask   int8 = 0x07
+	messageTimestampTypeMask int8 = 0x08
+)
+
+// ErrTooLarge is returned when converting records would take more memory
+// than the caller allowed
+var ErrTooLarge = compression.ErrTooLarge
+
+// message is a message in the v0 or v1 format
+type message struct {
+	offset     int64
+	magic      int8
+	attributes int8
+	timestamp  int64
+	key        []byte // nil for a null key
+	value      []byte // nil for a null value
+}
+
+// PeekMagic returns the message format of the batch or message set at the
+// start of data
+func PeekMagic(data []byte) (int8, error) {
+	if len(data) <= magicOffset {
+		return 0, ErrShortBatch
+	}
+	return int8(data[magicOffset]), nil
+}
+
+// parseMessage parses the message at the start of data, returning it along
+// with its size including the offset and size fields
+func parseMessage(data []byte) (*message, int, error) {
+	if len(data) < LogOverhead {
+		return nil, 0, ErrShortBatch
+	}
+	size := int(int32(binary.BigEndian.Uint32(data[messageSizeOffset:])))
+	if size < messageMinSizeV0 {
+		return nil, 0, fmt.Errorf("%w: message size %d is smaller than its header", ErrCorruptBatch, size)
+	}
+	if len(data) < LogOverhead+size {
+		return nil, 0, ErrShortBatch
+	}
+	data = data[:LogOverhead+size]
+
+	m := &message{
+		offset:     int64(binary.BigEndian.Uint64(data)),
+		magic:      int8(data[messageMagicOffset]),
+		attributes: int8(data[messageAttributesOffset]),
+		timestamp:  NoTimestamp,
+	}
+	if m.magic > MagicV1 {
+		return nil, 0, fmt.Errorf("%w: magic %d in a legacy message set", ErrUnsupportedMagic, m.magic)
+	}
+	stored := binary.BigEndian.Uint32(data[messageCRCOffset:])
+	if crc := crc32.ChecksumIEEE(data[messageMagicOffset:]); crc != stored {
+		return nil, 0, fmt.Errorf("%w: message crc mismatch (stored %08x, computed %08x)", ErrCorruptBatch, stored, crc)
+	}
+
+	r := &legacyReader{buf: data, off: messageTimestampOffset}
+	if m.magic == MagicV1 {
+		if size < messageMinSizeV1 {
+			return nil, 0, fmt.Errorf("%w: message size %d is smaller than its header", ErrCorruptBatch, size)
+		}
+		m.timestamp = int64(binary.BigEndian.Uint64(data[messageTimestampOffset:]))
+		r.off += 8
+	}
+	m.key = r.bytes()
+	m.value = r.bytes()
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	if r.off != len(data) {
+		return nil, 0, fmt.Errorf("%w: %d trailing bytes in message", ErrCorruptBatch, len(data)-r.off)
+	}
+	return m, len(data), nil
+}
+
+// legacyReader reads the int32 length-prefixed fields of legacy messages
+type legacyReader struct {
+	buf []byte
+	off int
+	err error
+}
+
+// bytes reads an int32 length-prefixed byte slice, where -1 means null
+func (r *legacyReader) bytes() []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.buf)-r.off < 4 {
+		r.err = fmt.Errorf("%w: truncated message field", ErrCorruptBatch)
+		return nil
+	}
+	n := int(int32(binary.BigEndian.Uint32(r.buf[r.off:])))
+	r.off += 4
+	if n < 0 {
+		return nil
+	}
+	if n > len(r.buf)-r.off {
+		r.err = fmt.Errorf("%w: field of %d bytes overruns message", ErrCorruptBatch, n)
+		return nil
+	}
+	b := r.buf[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// appendMessage appends m, with its offset and size, to dst
+func appendMessage(dst []byte, m *message) []byte {
+	start := len(dst)
+	dst = binary.BigEndian.AppendUint64(dst, uint64(m.offset))
+	dst = append(dst, 0, 0, 0, 0, 0, 0, 0, 0)
+	dst = append(dst, byte(m.magic), byte(m.attributes))
+	if m.magic == MagicV1 {
+		dst = binary.BigEndian.AppendUint64(dst, uint64(m.timestamp))
+	}
+	dst = appendLegacyBytes(dst, m.key)
+	dst = appendLegacyBytes(dst, m.value)
+
+	msg := dst[start:]
+	binary.BigEndian.PutUint32(msg[messageSizeOffset:], uint32(len(msg)-LogOverhead))
+	binary.BigEndian.PutUint32(msg[messageCRCOffset:], crc32.ChecksumIEEE(msg[messageMagicOffset:]))
+	return dst
+}
+
+// appendLegacyBytes appends an int32 length-prefixed byte slice, encoding
+// nil as -1
+func appendLegacyBytes(dst, b []byte) []byte {
+	if b == nil {
+		return binary.BigEndian.AppendUint32(dst, 0xffffffff)
+	}
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(b)))
+	return append(dst, b...)
+}
+
+// checkLegacyCodec checks that a wrapper message may use codec; the legacy
+// formats predate zstd
+func checkLegacyCodec(codec Compression, magic int8) error {
+	if codec > CompressionLZ4 {
+		return fmt.Errorf("%w: %s in message format v%d", compression.ErrUnsupportedCodec, codec, magic)
+	}
+	return nil
+}
+
+// compressMessageSet compresses the message set inside a wrapper message
+func compressMessageSet(codec Compression, magic int8, data []byte) ([]byte, error) {
+	if codec == CompressionLZ4 && magic == MagicV0 {
+		return compression.CompressLegacyLZ4(data), nil
+	}
+	return compression.Compress(codec, data)
+}
+
+// decompressMessageSet decompresses the message set inside a wrapper message
+func decompressMessageSet(codec Compression, magic int8, data []byte, maxSize int) ([]byte, error) {
+	if codec == CompressionLZ4 && magic == MagicV0 {
+		return compression.DecompressLegacyLZ4(data, maxSize)
+	}
+	return compression.Decompress(codec, data, maxSize)
+}
+
+// UpConvert converts a message set in the v0 or v1 format, as producers
+// before Kafka 0.11 send, to a single RecordBatch with offsets from 0.
+// Compressed wrapper messages are unpacked and the batch is compressed with
+// the codec of the first of them. What the wrappers decompress to may not
+// exceed maxSize bytes in total.
+func UpConvert(data []byte, maxSize int) (*Batch, error) {
+	var records []Record
+	codec := CompressionNone
+	add := func(m *message, timestamp int64) {
+		records = append(records, Record{TimestampDelta: timestamp, Key: m.key, Value: m.value})
+	}
+
+	budget := maxSize
+	for rest := data; len(rest) > 0; {
+		m, n, err := parseMessage(rest)
+		if err != nil {
+			return nil, err
+		}
+		rest = rest[n:]
+
+		c := Compression(m.attributes & messageCompressionMask)
+		if c == CompressionNone {
+			add(m, m.timestamp)
+			continue
+		}
+		if err := checkLegacyCodec(c, m.magic); err != nil {
+			return nil, err
+		}
+		if codec == CompressionNone {
+			codec = c
+		}
+		inner, err := decompressMessageSet(c, m.magic, m.value, budget)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to decompress %s message set: %w", ErrCorruptBatch, c, err)
+		}
+		budget -= len(inner)
+
+		// A wrapper stamped with log append time overrides the timestamps
+		// of its messages
+		for len(inner) > 0 {
+			im, n, err := parseMessage(inner)
+			if err != nil {
+				return nil, err
+			}
+			inner = inner[n:]
+			if im.magic != m.magic || im.attributes&messageCompressionMask != 0 {
+				return nil, fmt.Errorf("%w: nested message does not match its wrapper", ErrCorruptBatch)
+			}
+			timestamp := im.timestamp
+			if m.attributes&messageTimestampTypeMask != 0 {
+				timestamp = m.timestamp
+			}
+			add(im, timestamp)
+		}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: empty message set", ErrCorruptBatch)
+	}
+
+	// Records hold their absolute timestamps until the base is known
+	header := Batch{
+		PartitionLeaderEpoch: NoPartitionLeaderEpoch,
+		BaseTimestamp:        records[0].TimestampDelta,
+		MaxTimestamp:         NoTimestamp,
+		ProducerID:           NoProducerID,
+		ProducerEpoch:        NoProducerEpoch,
+		BaseSequence:         NoSequence,
+	}
+	for i := range records {
+		header.MaxTimestamp = max(header.MaxTimestamp, records[i].TimestampDelta)
+		records[i].TimestampDelta -= header.BaseTimestamp
+	}
+	b := EncodeBatch(header, records)
+	if codec == CompressionNone {
+		return b, nil
+	}
+	return b.Recompress(codec)
+}
+
+// DownConvert converts the RecordBatches in data to a message set in the
+// v0 or v1 format, for clients too old to read v2. Control batches are
+// dropped, as are record headers, which the old formats cannot carry.
+// Compressed batches become wrapper messages with the same codec, except
+// that zstd cannot be expressed and fails with ErrUnsupportedCodec.
+//
+// Records before firstOffset, which a fetch may find at the start of its
+// first batch, are left out. Conversion stops before the batch that would
+// take the output past maxBytes, but the first batch is always converted
+// so that consumers can make progress.
+func DownConvert(data []byte, magic int8, firstOffset int64, maxBytes int) ([]byte, error) {
+	out := []byte{}
+	var converted []byte
+	for rest := data; len(rest) > 0; {
+		b, err := ParseBatch(rest)
+		if err != nil {
+			return nil, err
+		}
+		rest = rest[b.Size():]
+		if b.IsControl() || b.NumRecords == 0 {
+			continue
+		}
+
+		if converted, err = downConvertBatch(converted[:0], b, magic, firstOffset); err != nil {
+			return nil, err
+		}
+		if len(converted) == 0 {
+			continue
+		}
+		if len(out) > 0 && len(out)+len(converted) > maxBytes {
+			break
+		}
+		out = append(out, converted...)
+	}
+	return out, nil
+}
+
+// downConvertBatch appends the messages of one batch from firstOffset on
+// to dst in format magic
+func downConvertBatch(dst []byte, b *Batch, magic int8, firstOffset int64) ([]byte, error) {
+	codec := b.Compression()
+	if err := checkLegacyCodec(codec, magic); err != nil {
+		return nil, err
+	}
+	records, err := b.Records()
+	if err != nil {
+		return nil, err
+	}
+	for len(records) > 0 && b.BaseOffset+int64(records[0].OffsetDelta) < firstOffset {
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		return dst, nil
+	}
+
+	var attributes int8
+	if magic == MagicV1 && b.TimestampType() == LogAppendTime {
+		attributes |= messageTimestampTypeMask
+	}
+	timestamp := func(r *Record) int64 {
+		switch {
+		case magic == MagicV0:
+			return NoTimestamp
+		case b.TimestampType() == LogAppendTime:
+			return b.MaxTimestamp
+		}
+		return b.BaseTimestamp + r.TimestampDelta
+	}
+
+	if codec == CompressionNone {
+		for i := range records {
+			r := &records[i]
+			dst = appendMessage(dst, &message{
+				offset:     b.BaseOffset + int64(r.OffsetDelta),
+				magic:      magic,
+				attributes: attributes,
+				timestamp:  timestamp(r),
+				key:        r.Key,
+				value:      r.Value,
+			})
+		}
+		return dst, nil
+	}
+
+	// v0 wrappers hold absolute offsets; v1 wrappers hold offsets relative
+	// to the first message and carry the last one's absolute offset
+	first := b.BaseOffset + int64(records[0].OffsetDelta)
+	var inner []byte
+	for i := range records {
+		r := &records[i]
+		offset := b.BaseOffset + int64(r.OffsetDelta)
+		if magic == MagicV1 {
+			offset -= first
+		}
+		inner = appendMessage(inner, &message{
+			offset:     offset,
+			magic:      magic,
+			attributes: attributes,
+			timestamp:  timestamp(r),
+			key:        r.Key,
+			value:      r.Value,
+		})
+	}
+	value, err := compressMessageSet(codec, magic, inner)
+	if err != nil {
+		return nil, err
+	}
+	wrapperTimestamp := NoTimestamp
+	if magic == MagicV1 {
+		wrapperTimestamp = b.MaxTimestamp
+	}
+	return appendMessage(dst, &message{
+		offset:     b.BaseOffset + int64(records[len(records)-1].OffsetDelta),
+		magic:      magic,
+		attributes: attributes | int8(codec),
+		timestamp:  wrapperTimestamp,
+		value:      value,
+	}), nil
+}
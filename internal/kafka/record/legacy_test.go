@@ -0,0 +1,470 @@
+package record
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"slices"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/compression"
+)
+
+// baseTimestamp is the timestamp of the first record of the conversion tests
+const baseTimestamp int64 = 1700000000000
+
+// legacyCodecs are the codecs the legacy formats can carry
+var legacyCodecs = []Compression{CompressionNone, CompressionGzip, CompressionSnappy, CompressionLZ4}
+
+// sampleRecords returns records with null and empty keys and values and
+// timestamps that do not count up
+func sampleRecords() []Record {
+	return []Record{
+		{TimestampDelta: 0, Key: []byte("k1"), Value: []byte("v1")},
+		{TimestampDelta: 30, Key: nil, Value: []byte("no key")},
+		{TimestampDelta: 10, Key: []byte("k3"), Value: nil},
+		{TimestampDelta: 20, Key: []byte{}, Value: []byte{}},
+	}
+}
+
+// sampleBatch encodes sampleRecords at baseOffset, compressed with codec
+func sampleBatch(t *testing.T, baseOffset int64, codec Compression) *Batch {
+	t.Helper()
+	b := EncodeBatch(Batch{
+		BaseOffset:           baseOffset,
+		PartitionLeaderEpoch: NoPartitionLeaderEpoch,
+		BaseTimestamp:        baseTimestamp,
+		MaxTimestamp:         baseTimestamp + 30,
+		ProducerID:           NoProducerID,
+		ProducerEpoch:        NoProducerEpoch,
+		BaseSequence:         NoSequence,
+	}, sampleRecords())
+	if codec == CompressionNone {
+		return b
+	}
+	b, err := b.Recompress(codec)
+	if err != nil {
+		t.Fatalf("Recompress: %v", err)
+	}
+	return b
+}
+
+// parseMessages parses a message set, failing the test if it is invalid
+func parseMessages(t *testing.T, data []byte) []*message {
+	t.Helper()
+	var messages []*message
+	for len(data) > 0 {
+		m, n, err := parseMessage(data)
+		if err != nil {
+			t.Fatalf("parseMessage: %v", err)
+		}
+		messages = append(messages, m)
+		data = data[n:]
+	}
+	return messages
+}
+
+// encodeMessages encodes messages as a message set
+func encodeMessages(messages ...*message) []byte {
+	var data []byte
+	for _, m := range messages {
+		data = appendMessage(data, m)
+	}
+	return data
+}
+
+// wrap returns a wrapper message of format magic holding messages
+// compressed with codec
+func wrap(t *testing.T, codec Compression, magic int8, attributes int8, timestamp int64, messages ...*message) *message {
+	t.Helper()
+	value, err := compressMessageSet(codec, magic, encodeMessages(messages...))
+	if err != nil {
+		t.Fatalf("compressMessageSet: %v", err)
+	}
+	return &message{magic: magic, attributes: attributes | int8(codec), timestamp: timestamp, value: value}
+}
+
+// checkRecords fails the test unless b holds the keys, values and absolute
+// timestamps of want, which are relative to wantBase
+func checkRecords(t *testing.T, b *Batch, want []Record, wantBase int64) {
+	t.Helper()
+	got, err := b.Records()
+	if err != nil {
+		t.Fatalf("Records: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := &got[i], &want[i]
+		if g.OffsetDelta != int32(i) {
+			t.Errorf("record %d: offset delta %d", i, g.OffsetDelta)
+		}
+		if !bytes.Equal(g.Key, w.Key) || (g.Key == nil) != (w.Key == nil) {
+			t.Errorf("record %d: key %q, want %q", i, g.Key, w.Key)
+		}
+		if !bytes.Equal(g.Value, w.Value) || (g.Value == nil) != (w.Value == nil) {
+			t.Errorf("record %d: value %q, want %q", i, g.Value, w.Value)
+		}
+		if ts, wantTs := b.BaseTimestamp+g.TimestampDelta, wantBase+w.TimestampDelta; ts != wantTs {
+			t.Errorf("record %d: timestamp %d, want %d", i, ts, wantTs)
+		}
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	for _, magic := range []int8{MagicV0, MagicV1} {
+		for _, codec := range legacyCodecs {
+			t.Run(fmt.Sprintf("v%d/%s", magic, codec), func(t *testing.T) {
+				b := sampleBatch(t, 100, codec)
+				data, err := DownConvert(b.Data, magic, 100, 1<<20)
+				if err != nil {
+					t.Fatalf("DownConvert: %v", err)
+				}
+				if got, _ := PeekMagic(data); got != magic {
+					t.Errorf("converted to magic %d", got)
+				}
+
+				up, err := UpConvert(data, 1<<20)
+				if err != nil {
+					t.Fatalf("UpConvert: %v", err)
+				}
+				if up.BaseOffset != 0 || up.Compression() != codec {
+					t.Errorf("batch at offset %d with %s, want offset 0 with %s", up.BaseOffset, up.Compression(), codec)
+				}
+
+				// v0 messages have no timestamp to bring back
+				want := sampleRecords()
+				wantBase, wantMax := baseTimestamp, baseTimestamp+30
+				if magic == MagicV0 {
+					for i := range want {
+						want[i].TimestampDelta = 0
+					}
+					wantBase, wantMax = NoTimestamp, NoTimestamp
+				}
+				checkRecords(t, up, want, wantBase)
+				if up.MaxTimestamp != wantMax {
+					t.Errorf("max timestamp %d, want %d", up.MaxTimestamp, wantMax)
+				}
+			})
+		}
+	}
+}
+
+func TestDownConvertOffsets(t *testing.T) {
+	tests := []struct {
+		magic int8
+		codec Compression
+		// wantOffsets are the offsets of the messages of the set, and
+		// wantInner those of the messages in its wrapper
+		wantOffsets []int64
+		wantInner   []int64
+	}{
+		{MagicV0, CompressionNone, []int64{101, 102, 103}, nil},
+		{MagicV1, CompressionNone, []int64{101, 102, 103}, nil},
+		// v0 wrappers hold absolute offsets, v1 wrappers offsets relative
+		// to the first message
+		{MagicV0, CompressionGzip, []int64{103}, []int64{101, 102, 103}},
+		{MagicV1, CompressionGzip, []int64{103}, []int64{0, 1, 2}},
+		{MagicV0, CompressionLZ4, []int64{103}, []int64{101, 102, 103}},
+		{MagicV1, CompressionLZ4, []int64{103}, []int64{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("v%d/%s", tt.magic, tt.codec), func(t *testing.T) {
+			// The fetch starts after the first record of the batch
+			data, err := DownConvert(sampleBatch(t, 100, tt.codec).Data, tt.magic, 101, 1<<20)
+			if err != nil {
+				t.Fatalf("DownConvert: %v", err)
+			}
+			messages := parseMessages(t, data)
+			var offsets []int64
+			for _, m := range messages {
+				offsets = append(offsets, m.offset)
+			}
+			if !slices.Equal(offsets, tt.wantOffsets) {
+				t.Errorf("offsets %v, want %v", offsets, tt.wantOffsets)
+			}
+			if tt.wantInner == nil {
+				return
+			}
+
+			w := messages[0]
+			if got := Compression(w.attributes & messageCompressionMask); got != tt.codec {
+				t.Errorf("wrapper compressed with %s, want %s", got, tt.codec)
+			}
+			inner, err := decompressMessageSet(tt.codec, tt.magic, w.value, 1<<20)
+			if err != nil {
+				t.Fatalf("decompressMessageSet: %v", err)
+			}
+			offsets = nil
+			for _, m := range parseMessages(t, inner) {
+				offsets = append(offsets, m.offset)
+			}
+			if !slices.Equal(offsets, tt.wantInner) {
+				t.Errorf("inner offsets %v, want %v", offsets, tt.wantInner)
+			}
+		})
+	}
+}
+
+func TestDownConvertTimestampType(t *testing.T) {
+	for _, codec := range []Compression{CompressionNone, CompressionGzip} {
+		t.Run(codec.String(), func(t *testing.T) {
+			b := sampleBatch(t, 0, codec)
+			b.SetLogAppendTime(baseTimestamp + 1000)
+
+			// v1 messages carry the broker's append time and say so
+			data, err := DownConvert(b.Data, MagicV1, 0, 1<<20)
+			if err != nil {
+				t.Fatalf("DownConvert: %v", err)
+			}
+			messages := parseMessages(t, data)
+			if codec != CompressionNone {
+				inner, err := decompressMessageSet(codec, MagicV1, messages[0].value, 1<<20)
+				if err != nil {
+					t.Fatalf("decompressMessageSet: %v", err)
+				}
+				messages = append(messages, parseMessages(t, inner)...)
+			}
+			for i, m := range messages {
+				if m.attributes&messageTimestampTypeMask == 0 || m.timestamp != baseTimestamp+1000 {
+					t.Errorf("message %d: attributes %#x and timestamp %d, want log append time %d", i, m.attributes, m.timestamp, baseTimestamp+1000)
+				}
+			}
+
+			// v0 messages have neither
+			data, err = DownConvert(b.Data, MagicV0, 0, 1<<20)
+			if err != nil {
+				t.Fatalf("DownConvert: %v", err)
+			}
+			for i, m := range parseMessages(t, data) {
+				if m.attributes&messageTimestampTypeMask != 0 || m.timestamp != NoTimestamp {
+					t.Errorf("v0 message %d: attributes %#x and timestamp %d", i, m.attributes, m.timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestUpConvertWrappers(t *testing.T) {
+	plain := func(timestamp int64, value string) *message {
+		return &message{magic: MagicV1, timestamp: timestamp, key: []byte("k"), value: []byte(value)}
+	}
+	tests := []struct {
+		name     string
+		messages func(t *testing.T) []*message
+		// wantCodec is the codec of the batch, and wantTimestamps the
+		// timestamps of its records
+		wantCodec      Compression
+		wantTimestamps []int64
+	}{
+		{
+			name: "create time",
+			messages: func(t *testing.T) []*message {
+				return []*message{wrap(t, CompressionGzip, MagicV1, 0, 300, plain(100, "a"), plain(300, "b"))}
+			},
+			wantCodec:      CompressionGzip,
+			wantTimestamps: []int64{100, 300},
+		},
+		{
+			// The wrapper's timestamp overrides those of its messages
+			name: "log append time",
+			messages: func(t *testing.T) []*message {
+				return []*message{wrap(t, CompressionSnappy, MagicV1, messageTimestampTypeMask, 500, plain(100, "a"), plain(300, "b"))}
+			},
+			wantCodec:      CompressionSnappy,
+			wantTimestamps: []int64{500, 500},
+		},
+		{
+			// The batch takes the codec of the first wrapper
+			name: "mixed",
+			messages: func(t *testing.T) []*message {
+				return []*message{
+					plain(50, "a"),
+					wrap(t, CompressionLZ4, MagicV1, 0, 200, plain(100, "b"), plain(200, "c")),
+					wrap(t, CompressionGzip, MagicV1, 0, 400, plain(400, "d")),
+				}
+			},
+			wantCodec:      CompressionLZ4,
+			wantTimestamps: []int64{50, 100, 200, 400},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := UpConvert(encodeMessages(tt.messages(t)...), 1<<20)
+			if err != nil {
+				t.Fatalf("UpConvert: %v", err)
+			}
+			if b.Compression() != tt.wantCodec {
+				t.Errorf("batch compressed with %s, want %s", b.Compression(), tt.wantCodec)
+			}
+			records, err := b.Records()
+			if err != nil {
+				t.Fatalf("Records: %v", err)
+			}
+			var timestamps []int64
+			for _, r := range records {
+				timestamps = append(timestamps, b.BaseTimestamp+r.TimestampDelta)
+			}
+			if !slices.Equal(timestamps, tt.wantTimestamps) {
+				t.Errorf("timestamps %v, want %v", timestamps, tt.wantTimestamps)
+			}
+			if b.MaxTimestamp != slices.Max(tt.wantTimestamps) {
+				t.Errorf("max timestamp %d, want %d", b.MaxTimestamp, slices.Max(tt.wantTimestamps))
+			}
+		})
+	}
+}
+
+func TestUpConvertMaxSize(t *testing.T) {
+	value := bytes.Repeat([]byte{'x'}, 1000)
+	one := encodeMessages(&message{magic: MagicV0, value: value})
+	size := len(one)
+	set := encodeMessages(
+		wrap(t, CompressionGzip, MagicV0, 0, NoTimestamp, &message{magic: MagicV0, value: value}),
+		wrap(t, CompressionGzip, MagicV0, 0, NoTimestamp, &message{magic: MagicV0, value: value}),
+	)
+
+	// The limit counts what every wrapper decompresses to together
+	tests := []struct {
+		maxSize int
+		wantErr error
+	}{
+		{2 * size, nil},
+		{2*size - 1, ErrTooLarge},
+		{size - 1, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		_, err := UpConvert(set, tt.maxSize)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("maxSize %d: err = %v, want %v", tt.maxSize, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDownConvertMaxBytes(t *testing.T) {
+	first := sampleBatch(t, 0, CompressionNone)
+	var data []byte
+	data = append(data, first.Data...)
+	data = append(data, EncodeEndTxnMarker(1, 0, 0, true, baseTimestamp).Data...)
+	data = append(data, sampleBatch(t, 5, CompressionNone).Data...)
+	one, err := DownConvert(first.Data, MagicV1, 0, 1<<20)
+	if err != nil {
+		t.Fatalf("DownConvert: %v", err)
+	}
+
+	// Control batches are dropped; a batch that does not fit is left for
+	// the next fetch, unless it is the first
+	tests := []struct {
+		maxBytes     int
+		wantMessages int
+	}{
+		{1 << 20, 8},
+		{2*len(one) - 1, 4},
+		{1, 4},
+	}
+	for _, tt := range tests {
+		out, err := DownConvert(data, MagicV1, 0, tt.maxBytes)
+		if err != nil {
+			t.Fatalf("maxBytes %d: DownConvert: %v", tt.maxBytes, err)
+		}
+		if got := len(parseMessages(t, out)); got != tt.wantMessages {
+			t.Errorf("maxBytes %d: %d messages, want %d", tt.maxBytes, got, tt.wantMessages)
+		}
+	}
+}
+
+func TestDownConvertZstd(t *testing.T) {
+	_, err := DownConvert(sampleBatch(t, 0, CompressionZstd).Data, MagicV1, 0, 1<<20)
+	if !errors.Is(err, compression.ErrUnsupportedCodec) {
+		t.Errorf("err = %v, want %v", err, compression.ErrUnsupportedCodec)
+	}
+}
+
+func TestUpConvertInvalid(t *testing.T) {
+	valid := func() []byte {
+		return encodeMessages(&message{magic: MagicV1, timestamp: 1, key: []byte("k"), value: []byte("value")})
+	}
+	tests := []struct {
+		name    string
+		data    func(t *testing.T) []byte
+		wantErr error
+	}{
+		{"empty", func(*testing.T) []byte { return nil }, ErrCorruptBatch},
+		{"truncated header", func(*testing.T) []byte { return valid()[:LogOverhead-1] }, ErrShortBatch},
+		{"truncated message", func(*testing.T) []byte { d := valid(); return d[:len(d)-1] }, ErrShortBatch},
+		{"bad crc", func(*testing.T) []byte { d := valid(); d[len(d)-1] ^= 0xff; return d }, ErrCorruptBatch},
+		{"size below header", func(*testing.T) []byte {
+			d := valid()
+			d[messageSizeOffset+3] = messageMinSizeV0 - 1
+			return d
+		}, ErrCorruptBatch},
+		{"v1 size below header", func(*testing.T) []byte {
+			d := encodeMessages(&message{magic: MagicV1})[:LogOverhead+messageMinSizeV0]
+			return recomputeCRC(d, func(m []byte) { m[messageSizeOffset+3] = messageMinSizeV0 })
+		}, ErrCorruptBatch},
+		{"magic v2", func(*testing.T) []byte {
+			return encodeMessages(&message{magic: MagicV2, value: []byte("v")})
+		}, ErrUnsupportedMagic},
+		{"field overruns message", func(*testing.T) []byte {
+			return recomputeCRC(valid(), func(m []byte) { m[messageTimestampOffset+8+3] = 100 })
+		}, ErrCorruptBatch},
+		{"trailing bytes", func(*testing.T) []byte {
+			return recomputeCRC(valid(), func(m []byte) { m[len(m)-1-5] = 4 })
+		}, ErrCorruptBatch},
+		{"zstd wrapper", func(*testing.T) []byte {
+			return encodeMessages(&message{magic: MagicV1, attributes: int8(CompressionZstd), value: []byte{}})
+		}, compression.ErrUnsupportedCodec},
+		{"corrupt wrapper value", func(*testing.T) []byte {
+			return encodeMessages(&message{magic: MagicV1, attributes: int8(CompressionGzip), value: []byte("not gzip")})
+		}, ErrCorruptBatch},
+		{"truncated inner message set", func(t *testing.T) []byte {
+			inner := valid()
+			value, _ := compressMessageSet(CompressionGzip, MagicV1, inner[:len(inner)-1])
+			return encodeMessages(&message{magic: MagicV1, attributes: int8(CompressionGzip), value: value})
+		}, ErrShortBatch},
+		{"nested wrapper", func(t *testing.T) []byte {
+			nested := wrap(t, CompressionGzip, MagicV1, 0, 1, &message{magic: MagicV1, value: []byte("v")})
+			return encodeMessages(wrap(t, CompressionGzip, MagicV1, 0, 1, nested))
+		}, ErrCorruptBatch},
+		{"inner magic differs", func(t *testing.T) []byte {
+			return encodeMessages(wrap(t, CompressionGzip, MagicV1, 0, 1, &message{magic: MagicV0, value: []byte("v")}))
+		}, ErrCorruptBatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UpConvert(tt.data(t), 1<<20); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recomputeCRC edits the single message in data with edit and fixes up
+// its CRC, so that only the edit makes it invalid
+func recomputeCRC(data []byte, edit func([]byte)) []byte {
+	edit(data)
+	binary.BigEndian.PutUint32(data[messageCRCOffset:], crc32.ChecksumIEEE(data[messageMagicOffset:]))
+	return data
+}
+
+func TestDownConvertCorrupt(t *testing.T) {
+	data := sampleBatch(t, 0, CompressionGzip).Data
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"truncated", data[:len(data)-1], ErrShortBatch},
+		{"corrupt records", append(append([]byte(nil), data[:HeaderSize]...), bytes.Repeat([]byte{0xff}, len(data)-HeaderSize)...), ErrCorruptBatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DownConvert(tt.data, MagicV1, 0, 1<<20); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
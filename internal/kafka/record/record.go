@@ -0,0 +1,182 @@
+package record
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Header is a record header
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Record is a single record within a batch. Offset and timestamp are
+// stored as deltas from the batch's base offset and base timestamp.
+type Record struct {
+	Attributes     int8
+	TimestampDelta int64
+	OffsetDelta    int32
+	Key            []byte // nil for a null key
+	Value          []byte // nil for a null value (a tombstone)
+	Headers        []Header
+}
+
+// recordReader reads the varint-encoded fields of records
+type recordReader struct {
+	buf []byte
+	off int
+	err error
+}
+
+// varint reads a zigzag varint
+func (r *recordReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf[r.off:])
+	if n <= 0 {
+		r.err = fmt.Errorf("%w: bad varint at offset %d", ErrCorruptBatch, r.off)
+		return 0
+	}
+	r.off += n
+	return v
+}
+
+// bytes reads a varint length-prefixed byte slice, where -1 means null
+func (r *recordReader) bytes() []byte {
+	n := int(r.varint())
+	if r.err != nil || n < 0 {
+		return nil
+	}
+	if n > len(r.buf)-r.off {
+		r.err = fmt.Errorf("%w: field of %d bytes overruns record", ErrCorruptBatch, n)
+		return nil
+	}
+	b := r.buf[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// DecodeRecords decodes count records from uncompressed record data
+func DecodeRecords(data []byte, count int) ([]Record, error) {
+	if count < 0 || count > len(data) {
+		return nil, fmt.Errorf("%w: invalid record count %d", ErrCorruptBatch, count)
+	}
+
+	records := make([]Record, 0, count)
+	r := &recordReader{buf: data}
+	for i := 0; i < count; i++ {
+		length := int(r.varint())
+		if r.err != nil {
+			return nil, r.err
+		}
+		end := r.off + length
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("%w: record %d of %d bytes overruns batch", ErrCorruptBatch, i, length)
+		}
+
+		if r.off >= len(data) {
+			return nil, fmt.Errorf("%w: empty record %d", ErrCorruptBatch, i)
+		}
+		rec := Record{Attributes: int8(data[r.off])}
+		r.off++
+		rec.TimestampDelta = r.varint()
+		rec.OffsetDelta = int32(r.varint())
+		rec.Key = r.bytes()
+		rec.Value = r.bytes()
+		numHeaders := int(r.varint())
+		if numHeaders < 0 || numHeaders > length {
+			return nil, fmt.Errorf("%w: invalid header count %d", ErrCorruptBatch, numHeaders)
+		}
+		for j := 0; j < numHeaders && r.err == nil; j++ {
+			key := r.bytes()
+			value := r.bytes()
+			rec.Headers = append(rec.Headers, Header{Key: string(key), Value: value})
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		if r.off != end {
+			return nil, fmt.Errorf("%w: record %d length %d does not match its contents", ErrCorruptBatch, i, length)
+		}
+		records = append(records, rec)
+	}
+	if r.off != len(data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes after %d records", ErrCorruptBatch, len(data)-r.off, count)
+	}
+	return records, nil
+}
+
+// AppendRecord appends the encoding of rec, including its length prefix, to dst
+func AppendRecord(dst []byte, rec *Record) []byte {
+	body := make([]byte, 0, 16+len(rec.Key)+len(rec.Value))
+	body = append(body, byte(rec.Attributes))
+	body = binary.AppendVarint(body, rec.TimestampDelta)
+	body = binary.AppendVarint(body, int64(rec.OffsetDelta))
+	body = appendVarBytes(body, rec.Key)
+	body = appendVarBytes(body, rec.Value)
+	body = binary.AppendVarint(body, int64(len(rec.Headers)))
+	for _, h := range rec.Headers {
+		body = appendVarBytes(body, []byte(h.Key))
+		body = appendVarBytes(body, h.Value)
+	}
+
+	dst = binary.AppendVarint(dst, int64(len(body)))
+	return append(dst, body...)
+}
+
+// appendVarBytes appends a varint length-prefixed byte slice, encoding nil as -1
+func appendVarBytes(dst, b []byte) []byte {
+	if b == nil {
+		return binary.AppendVarint(dst, -1)
+	}
+	dst = binary.AppendVarint(dst, int64(len(b)))
+	return append(dst, b...)
+}
+
+// EncodeBatch builds an uncompressed batch from records, taking the header
+// fields that describe the batch (base offset, timestamps, attributes and
+// producer state) from header and computing the rest
+func EncodeBatch(header Batch, records []Record) *Batch {
+	var data []byte
+	for i := range records {
+		records[i].OffsetDelta = int32(i)
+		data = AppendRecord(data, &records[i])
+	}
+
+	header.LastOffsetDelta = int32(len(records) - 1)
+	header.NumRecords = int32(len(records))
+	header.Attributes &^= compressionMask
+	return encodeBatch(header, data)
+}
+
+// encodeBatch builds a batch around records data, already compressed as
+// the header's attributes say. Only the length, magic and CRC are computed;
+// the record count and last offset delta come from header.
+func encodeBatch(header Batch, records []byte) *Batch {
+	data := make([]byte, HeaderSize, HeaderSize+len(records))
+	data = append(data, records...)
+
+	b := header
+	b.Magic = MagicV2
+	b.BatchLength = int32(len(data) - LogOverhead)
+	b.Data = data
+
+	binary.BigEndian.PutUint64(data[baseOffsetOffset:], uint64(b.BaseOffset))
+	binary.BigEndian.PutUint32(data[batchLengthOffset:], uint32(b.BatchLength))
+	binary.BigEndian.PutUint32(data[partitionLeaderEpochOffset:], uint32(b.PartitionLeaderEpoch))
+	data[magicOffset] = byte(b.Magic)
+	binary.BigEndian.PutUint16(data[attributesOffset:], uint16(b.Attributes))
+	binary.BigEndian.PutUint32(data[lastOffsetDeltaOffset:], uint32(b.LastOffsetDelta))
+	binary.BigEndian.PutUint64(data[baseTimestampOffset:], uint64(b.BaseTimestamp))
+	binary.BigEndian.PutUint64(data[maxTimestampOffset:], uint64(b.MaxTimestamp))
+	binary.BigEndian.PutUint64(data[producerIDOffset:], uint64(b.ProducerID))
+	binary.BigEndian.PutUint16(data[producerEpochOffset:], uint16(b.ProducerEpoch))
+	binary.BigEndian.PutUint32(data[baseSequenceOffset:], uint32(b.BaseSequence))
+	binary.BigEndian.PutUint32(data[recordsCountOffset:], uint32(b.NumRecords))
+
+	b.CRC = b.ComputeCRC()
+	binary.BigEndian.PutUint32(data[crcOffset:], b.CRC)
+	return &b
+}
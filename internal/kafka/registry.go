@@ -0,0 +1,140 @@
+package kafka
+
+import (
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handlerFunc processes a request for a single API key and writes its response
+type handlerFunc func(conn net.Conn, req *protocol.Request) error
+
+// errorResponseFunc builds the response an API returns when a request fails
+// as a whole, with errorCode set everywhere the API's schema carries one
+type errorResponseFunc func(req *protocol.Request, errorCode int16) *protocol.Response
+
+// apiEntry is a registered API with the versions its handler supports
+type apiEntry struct {
+	apiKey        int16
+	minVersion    int16
+	maxVersion    int16
+	handle        handlerFunc
+	errorResponse errorResponseFunc
+}
+
+// supportsVersion reports whether the handler accepts the given version
+func (e *apiEntry) supportsVersion(version int16) bool {
+	return version >= e.minVersion && version <= e.maxVersion
+}
+
+// feature is a feature flag the broker supports, such as metadata.version
+type feature struct {
+	name       string
+	minVersion int16
+	maxVersion int16
+}
+
+// registry tracks the APIs and features the broker supports. The
+// ApiVersions response is generated from it, so registering a handler is
+// all it takes to advertise a new API.
+type registry struct {
+	apis     map[int16]*apiEntry
+	features []feature
+
+	mu                sync.RWMutex
+	finalizedEpoch    int64
+	finalizedFeatures map[string]int16
+}
+
+// newRegistry creates an empty registry with no finalized features
+func newRegistry() *registry {
+	return &registry{
+		apis:           make(map[int16]*apiEntry),
+		finalizedEpoch: -1,
+	}
+}
+
+// register adds a handler for an API key, the versions it supports and
+// the builder for its whole-request error responses
+func (r *registry) register(apiKey, minVersion, maxVersion int16, handle handlerFunc, errorResponse errorResponseFunc) {
+	r.apis[apiKey] = &apiEntry{
+		apiKey:        apiKey,
+		minVersion:    minVersion,
+		maxVersion:    maxVersion,
+		handle:        handle,
+		errorResponse: errorResponse,
+	}
+}
+
+// registerFeature adds a supported feature flag and its version range
+func (r *registry) registerFeature(name string, minVersion, maxVersion int16) {
+	r.features = append(r.features, feature{name: name, minVersion: minVersion, maxVersion: maxVersion})
+}
+
+// lookup returns the registered entry for an API key
+func (r *registry) lookup(apiKey int16) (*apiEntry, bool) {
+	entry, ok := r.apis[apiKey]
+	return entry, ok
+}
+
+// setFinalizedFeatures records the cluster-wide finalized feature levels
+// and the epoch at which they were finalized
+func (r *registry) setFinalizedFeatures(epoch int64, levels map[string]int16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.finalizedEpoch = epoch
+	r.finalizedFeatures = levels
+}
+
+// apiVersions fills resp with every registered API, sorted by API key, and
+// the supported and finalized features for the given ApiVersions version
+func (r *registry) apiVersions(resp *protocol.ApiVersionsResponse, version int16) {
+	keys := make([]int, 0, len(r.apis))
+	for key := range r.apis {
+		keys = append(keys, int(key))
+	}
+	sort.Ints(keys)
+
+	resp.ApiKeys = make([]protocol.ApiVersionsResponseApiVersion, 0, len(keys))
+	for _, key := range keys {
+		entry := r.apis[int16(key)]
+		resp.ApiKeys = append(resp.ApiKeys, protocol.ApiVersionsResponseApiVersion{
+			ApiKey:     entry.apiKey,
+			MinVersion: entry.minVersion,
+			MaxVersion: entry.maxVersion,
+		})
+	}
+
+	for _, f := range r.features {
+		// Before v4 clients rejected a MinVersion of 0 (KAFKA-17011)
+		if version < 4 && f.minVersion == 0 {
+			continue
+		}
+		resp.SupportedFeatures = append(resp.SupportedFeatures, protocol.ApiVersionsResponseSupportedFeatureKey{
+			Name:       f.name,
+			MinVersion: f.minVersion,
+			MaxVersion: f.maxVersion,
+		})
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resp.FinalizedFeaturesEpoch = r.finalizedEpoch
+	names := make([]string, 0, len(r.finalizedFeatures))
+	for name := range r.finalizedFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		level := r.finalizedFeatures[name]
+		resp.FinalizedFeatures = append(resp.FinalizedFeatures, protocol.ApiVersionsResponseFinalizedFeatureKey{
+			Name:            name,
+			MaxVersionLevel: level,
+			MinVersionLevel: level,
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// handleSyncGroupRequest handles SYNC_GROUP requests. Members that sync
+// before their leader wait for it to send the assignment.
+func (h *RequestHandler) handleSyncGroupRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.SyncGroupRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode SyncGroup request: %w", err)
+	}
+
+	resp := <-h.groups.SyncGroup(body)
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// syncGroupErrorResponse builds a failed SyncGroup response
+func (h *RequestHandler) syncGroupErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	resp := &protocol.SyncGroupResponse{}
+	resp.Default()
+	resp.ErrorCode = errorCode
+	resp.Assignment = []byte{}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,365 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/txn"
+)
+
+// maxTopicNameLength is the longest legal topic name, leaving room for the
+// partition suffix of the log directory within a 255 byte file name
+const maxTopicNameLength = 249
+
+// topicError is a failed topic operation, carrying the Kafka error code
+// and the message reported to the client
+type topicError struct {
+	code    int16
+	message string
+}
+
+func (e *topicError) Error() string {
+	return e.message
+}
+
+// newTopicError creates a topicError with a formatted message
+func newTopicError(code int16, format string, args ...any) *topicError {
+	return &topicError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// validateTopicName checks a topic name against Kafka's naming rules
+func validateTopicName(name string) *topicError {
+	switch {
+	case name == "":
+		return newTopicError(protocol.ErrorInvalidTopic, "Topic name is illegal, it can't be empty")
+	case name == "." || name == "..":
+		return newTopicError(protocol.ErrorInvalidTopic, "Topic name cannot be \".\" or \"..\"")
+	case len(name) > maxTopicNameLength:
+		return newTopicError(protocol.ErrorInvalidTopic,
+			"Topic name is illegal, it can't be longer than %d characters, topic name: %s", maxTopicNameLength, name)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-') {
+			return newTopicError(protocol.ErrorInvalidTopic,
+				"Topic name %s is illegal, it contains a character other than ASCII alphanumerics, '.', '_' and '-'", name)
+		}
+	}
+	return nil
+}
+
+// collidingTopic returns an existing topic whose name differs from name
+// only by '.' and '_', which would clash in metric names
+func (h *RequestHandler) collidingTopic(name string) (string, bool) {
+	normalized := strings.ReplaceAll(name, ".", "_")
+	for _, topic := range h.metadata.Topics() {
+		if topic.Name != name && strings.ReplaceAll(topic.Name, ".", "_") == normalized {
+			return topic.Name, true
+		}
+	}
+	return "", false
+}
+
+// newTopic is a validated topic creation: the replicas of each partition
+// and the config overrides
+type newTopic struct {
+	name     string
+	replicas [][]int32
+	configs  map[string]string
+}
+
+// planTopic validates a topic creation and assigns its replicas. Negative
+// numPartitions or replicationFactor select the broker defaults; both must
+// be -1 when assignments gives the replicas of each partition instead.
+func (h *RequestHandler) planTopic(name string, numPartitions int32, replicationFactor int16, assignments map[int32][]int32, configs map[string]*string) (*newTopic, *topicError) {
+	if err := validateTopicName(name); err != nil {
+		return nil, err
+	}
+	if h.metadata.TopicByName(name) != nil {
+		return nil, newTopicError(protocol.ErrorTopicAlreadyExists, "Topic '%s' already exists.", name)
+	}
+	if existing, ok := h.collidingTopic(name); ok {
+		return nil, newTopicError(protocol.ErrorInvalidTopic, "Topic '%s' collides with existing topic: %s", name, existing)
+	}
+
+	t := &newTopic{name: name, configs: make(map[string]string, len(configs))}
+	if len(assignments) > 0 {
+		if numPartitions != -1 || replicationFactor != -1 {
+			return nil, newTopicError(protocol.ErrorInvalidRequest,
+				"Both numPartitions or replicationFactor and replicasAssignments were set. Both cannot be used at the same time.")
+		}
+		replicas, err := h.validateAssignments(assignments, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		t.replicas = replicas
+	} else {
+		if numPartitions == -1 {
+			numPartitions = h.config.NumPartitions
+		}
+		if replicationFactor == -1 {
+			replicationFactor = h.config.DefaultReplicationFactor
+		}
+		if numPartitions <= 0 {
+			return nil, newTopicError(protocol.ErrorInvalidPartitions, "Number of partitions was set to an invalid non-positive value.")
+		}
+		if replicationFactor <= 0 {
+			return nil, newTopicError(protocol.ErrorInvalidReplicationFactor,
+				"Replication factor must be larger than 0, or -1 to use the default value.")
+		}
+		// This broker is the whole cluster, so it can hold a single replica
+		if replicationFactor > 1 {
+			return nil, newTopicError(protocol.ErrorInvalidReplicationFactor,
+				"Unable to replicate the partition %d time(s): The target replication factor of %d cannot be reached because only 1 broker(s) are registered.",
+				replicationFactor, replicationFactor)
+		}
+		for i := int32(0); i < numPartitions; i++ {
+			t.replicas = append(t.replicas, []int32{h.config.NodeID})
+		}
+	}
+
+	for key, value := range configs {
+		if err := validateTopicConfig(key, value); err != nil {
+			return nil, err
+		}
+		t.configs[key] = *value
+	}
+	return t, nil
+}
+
+// validateAssignments checks manual replica assignments for the partitions
+// from firstPartition on and returns them in partition order. Every
+// partition must have replicationFactor replicas, or as many as the first
+// one when replicationFactor is -1.
+func (h *RequestHandler) validateAssignments(assignments map[int32][]int32, firstPartition int32, replicationFactor int) ([][]int32, *topicError) {
+	replicas := make([][]int32, len(assignments))
+	for i := range replicas {
+		partition := firstPartition + int32(i)
+		brokers, ok := assignments[partition]
+		if !ok {
+			return nil, newTopicError(protocol.ErrorInvalidReplicaAssignment,
+				"Partitions should be a consecutive 0-based integer sequence: missing partition %d", partition)
+		}
+		if len(brokers) == 0 {
+			return nil, newTopicError(protocol.ErrorInvalidReplicaAssignment,
+				"The manual partition assignment includes an empty replica list.")
+		}
+		if replicationFactor == -1 {
+			replicationFactor = len(brokers)
+		}
+		if len(brokers) != replicationFactor {
+			return nil, newTopicError(protocol.ErrorInvalidReplicaAssignment,
+				"The manual partition assignment includes a partition with %d replica(s), but this is not consistent with previous partitions, which have %d replica(s).",
+				len(brokers), replicationFactor)
+		}
+		seen := make(map[int32]bool, len(brokers))
+		for _, broker := range brokers {
+			if seen[broker] {
+				return nil, newTopicError(protocol.ErrorInvalidReplicaAssignment,
+					"The manual partition assignment includes the broker %d more than once.", broker)
+			}
+			seen[broker] = true
+			if broker != h.config.NodeID {
+				return nil, newTopicError(protocol.ErrorInvalidReplicaAssignment,
+					"The manual partition assignment includes broker %d, but no such broker is registered.", broker)
+			}
+		}
+		replicas[i] = brokers
+	}
+	return replicas, nil
+}
+
+// partitionRecords builds the records of new partitions led by the first
+// of their replicas
+func partitionRecords(topicID protocol.UUID, firstPartition int32, replicas [][]int32) []protocol.Message {
+	records := make([]protocol.Message, 0, len(replicas))
+	for i, brokers := range replicas {
+		p := &protocol.PartitionRecord{}
+		p.Default()
+		p.PartitionId = firstPartition + int32(i)
+		p.TopicId = topicID
+		p.Replicas = brokers
+		p.Isr = brokers
+		p.Leader = brokers[0]
+		p.LeaderEpoch = 0
+		p.PartitionEpoch = 0
+		records = append(records, p)
+	}
+	return records
+}
+
+// createTopic publishes a planned topic to the metadata log, together with
+// its config overrides, and creates its partition logs
+func (h *RequestHandler) createTopic(t *newTopic) (*metadata.Topic, *topicError) {
+	// Checking and publishing must not interleave with another creation
+	h.topicsMu.Lock()
+	defer h.topicsMu.Unlock()
+
+	if h.metadata.TopicByName(t.name) != nil {
+		return nil, newTopicError(protocol.ErrorTopicAlreadyExists, "Topic '%s' already exists.", t.name)
+	}
+	if existing, ok := h.collidingTopic(t.name); ok {
+		return nil, newTopicError(protocol.ErrorInvalidTopic, "Topic '%s' collides with existing topic: %s", t.name, existing)
+	}
+
+	id := protocol.RandomUUID()
+	records := []protocol.Message{&protocol.TopicRecord{Name: t.name, TopicId: id}}
+	records = append(records, partitionRecords(id, 0, t.replicas)...)
+	keys := make([]string, 0, len(t.configs))
+	for key := range t.configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := t.configs[key]
+		records = append(records, &protocol.ConfigRecord{
+			ResourceType: metadata.ResourceTopic,
+			ResourceName: t.name,
+			Name:         key,
+			Value:        &value,
+		})
+	}
+
+	if err := h.metadata.Publish(records...); err != nil {
+		h.logger.Error("Failed to create topic %s: %s", t.name, err.Error())
+		return nil, newTopicError(protocol.ErrorUnknownServerError, "Failed to create topic %s", t.name)
+	}
+	h.createLogs(t.name, 0, int32(len(t.replicas)))
+
+	h.logger.Info("Created topic %s with %d partitions", t.name, len(t.replicas))
+	return h.metadata.TopicByName(t.name), nil
+}
+
+// ensureOffsetsTopic creates the internal offsets topic the first time a
+// group needs it, compacted so that only the latest offsets are kept
+func (h *RequestHandler) ensureOffsetsTopic() *topicError {
+	if h.metadata.TopicByName(group.OffsetsTopic) != nil {
+		return nil
+	}
+	policy, compression, segmentBytes := "compact", "producer", "104857600"
+	t, err := h.planTopic(group.OffsetsTopic, h.groups.OffsetsTopicPartitions(), 1, nil, map[string]*string{
+		CleanupPolicyConfig:   &policy,
+		CompressionTypeConfig: &compression,
+		SegmentBytesConfig:    &segmentBytes,
+	})
+	if err == nil {
+		_, err = h.createTopic(t)
+	}
+	if err != nil && err.code != protocol.ErrorTopicAlreadyExists {
+		return err
+	}
+	return nil
+}
+
+// ensureTransactionStateTopic creates the internal transaction state topic
+// the first time a transactional producer needs it, compacted so that only
+// the latest state of each transactional ID is kept
+func (h *RequestHandler) ensureTransactionStateTopic() *topicError {
+	if h.metadata.TopicByName(txn.StateTopic) != nil {
+		return nil
+	}
+	policy, segmentBytes := "compact", "104857600"
+	t, err := h.planTopic(txn.StateTopic, h.txn.StateTopicPartitions(), 1, nil, map[string]*string{
+		CleanupPolicyConfig: &policy,
+		SegmentBytesConfig:  &segmentBytes,
+	})
+	if err == nil {
+		_, err = h.createTopic(t)
+	}
+	if err != nil && err.code != protocol.ErrorTopicAlreadyExists {
+		return err
+	}
+	return nil
+}
+
+// createLogs creates the logs of partitions [first, end) of a topic
+func (h *RequestHandler) createLogs(topic string, first, end int32) {
+	for i := first; i < end; i++ {
+		if _, err := h.logs.GetOrCreate(topic, i); err != nil {
+			h.logger.Error("Failed to create log for %s-%d: %s", topic, i, err.Error())
+		}
+	}
+}
+
+// addPartitions grows a topic to count partitions, placing the new ones on
+// the given replicas or, when assignments is nil, on this broker. With
+// validateOnly the change is only checked.
+func (h *RequestHandler) addPartitions(name string, count int32, assignments [][]int32, validateOnly bool) *topicError {
+	h.topicsMu.Lock()
+	defer h.topicsMu.Unlock()
+
+	topic := h.metadata.TopicByName(name)
+	if topic == nil {
+		return newTopicError(protocol.ErrorUnknownTopic, "The topic '%s' does not exist.", name)
+	}
+	current := int32(len(topic.Partitions))
+	if count < current {
+		return newTopicError(protocol.ErrorInvalidPartitions,
+			"The topic %s currently has %d partition(s); %d would not be an increase.", name, current, count)
+	}
+	if count == current {
+		return newTopicError(protocol.ErrorInvalidPartitions, "Topic already has %d partition(s).", current)
+	}
+
+	additional := count - current
+	var replicas [][]int32
+	if assignments != nil {
+		if int32(len(assignments)) != additional {
+			return newTopicError(protocol.ErrorInvalidReplicaAssignment,
+				"Attempted to add %d additional partition(s), but only %d assignment(s) were specified.", additional, len(assignments))
+		}
+		byPartition := make(map[int32][]int32, len(assignments))
+		for i, brokers := range assignments {
+			byPartition[current+int32(i)] = brokers
+		}
+		var err *topicError
+		replicas, err = h.validateAssignments(byPartition, current, len(topic.Partitions[0].Replicas))
+		if err != nil {
+			return err
+		}
+	} else {
+		for i := int32(0); i < additional; i++ {
+			replicas = append(replicas, []int32{h.config.NodeID})
+		}
+	}
+	if validateOnly {
+		return nil
+	}
+
+	if err := h.metadata.Publish(partitionRecords(topic.ID, current, replicas)...); err != nil {
+		h.logger.Error("Failed to add partitions to topic %s: %s", name, err.Error())
+		return newTopicError(protocol.ErrorUnknownServerError, "Failed to add partitions to topic %s", name)
+	}
+	h.createLogs(name, current, count)
+
+	h.logger.Info("Increased partitions of topic %s from %d to %d", name, current, count)
+	return nil
+}
+
+// deleteTopic removes a topic from the metadata log and deletes its
+// partition logs
+func (h *RequestHandler) deleteTopic(topic *metadata.Topic) *topicError {
+	if topic.IsInternal() {
+		return newTopicError(protocol.ErrorInvalidRequest, "Deletion of internal topic %s is not allowed.", topic.Name)
+	}
+
+	h.topicsMu.Lock()
+	defer h.topicsMu.Unlock()
+
+	if h.metadata.TopicByID(topic.ID) == nil {
+		return newTopicError(protocol.ErrorUnknownTopicID, "This server does not host this topic ID.")
+	}
+	if err := h.metadata.Publish(&protocol.RemoveTopicRecord{TopicId: topic.ID}); err != nil {
+		h.logger.Error("Failed to delete topic %s: %s", topic.Name, err.Error())
+		return newTopicError(protocol.ErrorUnknownServerError, "Failed to delete topic %s", topic.Name)
+	}
+	if err := h.logs.DeleteTopic(topic.Name); err != nil {
+		h.logger.Error("Failed to delete logs of topic %s: %s", topic.Name, err.Error())
+	}
+
+	h.logger.Info("Deleted topic %s", topic.Name)
+	return nil
+}
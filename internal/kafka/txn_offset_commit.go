@@ -0,0 +1,108 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// handleTxnOffsetCommitRequest handles TXN_OFFSET_COMMIT requests. The
+// group's partition of the offsets topic must already be in the producer's
+// transaction, added by AddOffsetsToTxn. Partitions of unknown topics are
+// rejected here; the rest are committed by the group coordinator, pending
+// until the transaction ends.
+func (h *RequestHandler) handleTxnOffsetCommitRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.TxnOffsetCommitRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode TxnOffsetCommit request: %w", err)
+	}
+
+	tp := storage.TopicPartition{Topic: group.OffsetsTopic, Partition: h.groups.OffsetsPartitionFor(body.GroupId)}
+	results, errorCode := h.txn.VerifyPartitions(body.TransactionalId, body.ProducerId, body.ProducerEpoch, []storage.TopicPartition{tp})
+	if errorCode == protocol.ErrorNone {
+		errorCode = results[tp]
+	}
+	if errorCode != protocol.ErrorNone {
+		errorCode = fencedError(errorCode, req.ApiVersion, 3)
+		return h.sendResponse(conn, protocol.NewResponse(req, txnOffsetCommitError(body, errorCode)))
+	}
+
+	// Commit the known partitions, remembering why the others failed
+	known := *body
+	known.Topics = nil
+	failed := make(map[storage.TopicPartition]int16)
+	for _, topic := range body.Topics {
+		image := h.metadata.TopicByName(topic.Name)
+		valid := protocol.TxnOffsetCommitRequestTopic{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			if image == nil {
+				failed[storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}] = protocol.ErrorUnknownTopic
+				continue
+			}
+			if _, ok := image.Partition(p.PartitionIndex); !ok {
+				failed[storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}] = protocol.ErrorUnknownTopicOrPartition
+				continue
+			}
+			valid.Partitions = append(valid.Partitions, p)
+		}
+		if len(valid.Partitions) > 0 {
+			known.Topics = append(known.Topics, valid)
+		}
+	}
+	committed := make(map[storage.TopicPartition]int16)
+	for _, topic := range h.groups.CommitTransactionalOffsets(&known).Topics {
+		for _, p := range topic.Partitions {
+			committed[storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}] = fencedError(p.ErrorCode, req.ApiVersion, 3)
+		}
+	}
+
+	// Answer in the order of the request
+	resp := &protocol.TxnOffsetCommitResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		result := protocol.TxnOffsetCommitResponseTopic{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			tp := storage.TopicPartition{Topic: topic.Name, Partition: p.PartitionIndex}
+			errorCode, ok := failed[tp]
+			if !ok {
+				errorCode = committed[tp]
+			}
+			result.Partitions = append(result.Partitions, protocol.TxnOffsetCommitResponsePartition{
+				PartitionIndex: p.PartitionIndex,
+				ErrorCode:      errorCode,
+			})
+		}
+		resp.Topics = append(resp.Topics, result)
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// txnOffsetCommitError builds a TxnOffsetCommit response that fails every
+// requested partition with errorCode
+func txnOffsetCommitError(body *protocol.TxnOffsetCommitRequest, errorCode int16) *protocol.TxnOffsetCommitResponse {
+	resp := &protocol.TxnOffsetCommitResponse{}
+	resp.Default()
+	for _, topic := range body.Topics {
+		result := protocol.TxnOffsetCommitResponseTopic{Name: topic.Name}
+		for _, p := range topic.Partitions {
+			result.Partitions = append(result.Partitions, protocol.TxnOffsetCommitResponsePartition{
+				PartitionIndex: p.PartitionIndex,
+				ErrorCode:      errorCode,
+			})
+		}
+		resp.Topics = append(resp.Topics, result)
+	}
+	return resp
+}
+
+// txnOffsetCommitErrorResponse builds a TxnOffsetCommit response that fails
+// every requested partition with errorCode
+func (h *RequestHandler) txnOffsetCommitErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.TxnOffsetCommitRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+	return protocol.NewResponse(req, txnOffsetCommitError(body, errorCode))
+}
@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// handleWriteTxnMarkersRequest handles WRITE_TXN_MARKERS requests, which
+// transaction coordinators send to the leaders of a transaction's
+// partitions to end it there. This broker's own coordinator writes its
+// markers directly, but the request is served the same way.
+func (h *RequestHandler) handleWriteTxnMarkersRequest(conn net.Conn, req *protocol.Request) error {
+	body := &protocol.WriteTxnMarkersRequest{}
+	if err := body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion); err != nil {
+		return fmt.Errorf("failed to decode WriteTxnMarkers request: %w", err)
+	}
+
+	resp := &protocol.WriteTxnMarkersResponse{}
+	resp.Default()
+	for _, marker := range body.Markers {
+		result := protocol.WriteTxnMarkersResponseWritableTxnMarkerResult{ProducerId: marker.ProducerId}
+		for _, topic := range marker.Topics {
+			image := h.metadata.TopicByName(topic.Name)
+			topicResult := protocol.WriteTxnMarkersResponseWritableTxnMarkerTopicResult{Name: topic.Name}
+			for _, p := range topic.PartitionIndexes {
+				errorCode := protocol.ErrorNone
+				if image == nil {
+					errorCode = protocol.ErrorUnknownTopic
+				} else if _, ok := image.Partition(p); !ok {
+					errorCode = protocol.ErrorUnknownTopicOrPartition
+				} else {
+					tp := storage.TopicPartition{Topic: topic.Name, Partition: p}
+					errorCode = h.writeTxnMarker(tp, &marker)
+				}
+				topicResult.Partitions = append(topicResult.Partitions, protocol.WriteTxnMarkersResponseWritableTxnMarkerPartitionResult{
+					PartitionIndex: p,
+					ErrorCode:      errorCode,
+				})
+			}
+			result.Topics = append(result.Topics, topicResult)
+		}
+		resp.Markers = append(resp.Markers, result)
+	}
+	return h.sendResponse(conn, protocol.NewResponse(req, resp))
+}
+
+// writeTxnMarker writes one marker of a request to a partition
+func (h *RequestHandler) writeTxnMarker(tp storage.TopicPartition, marker *protocol.WriteTxnMarkersRequestWritableTxnMarker) int16 {
+	err := h.txn.WriteMarker(tp, marker.ProducerId, marker.ProducerEpoch, marker.CoordinatorEpoch, marker.TransactionResult)
+	switch {
+	case err == nil:
+		return protocol.ErrorNone
+	case errors.Is(err, storage.ErrInvalidProducerEpoch):
+		h.logger.Info("Rejecting transaction marker for %s-%d: %s", tp.Topic, tp.Partition, err.Error())
+		return protocol.ErrorInvalidProducerEpoch
+	}
+	h.logger.Error("Failed to write transaction marker: %s", err.Error())
+	return protocol.ErrorKafkaStorageError
+}
+
+// writeTxnMarkersErrorResponse builds a WriteTxnMarkers response that fails
+// every requested partition with errorCode
+func (h *RequestHandler) writeTxnMarkersErrorResponse(req *protocol.Request, errorCode int16) *protocol.Response {
+	// Decoding is best effort: the request may be in a version we cannot read
+	body := &protocol.WriteTxnMarkersRequest{}
+	_ = body.Decode(protocol.NewDecoder(req.Payload), req.ApiVersion)
+
+	resp := &protocol.WriteTxnMarkersResponse{}
+	resp.Default()
+	for _, marker := range body.Markers {
+		result := protocol.WriteTxnMarkersResponseWritableTxnMarkerResult{ProducerId: marker.ProducerId}
+		for _, topic := range marker.Topics {
+			topicResult := protocol.WriteTxnMarkersResponseWritableTxnMarkerTopicResult{Name: topic.Name}
+			for _, p := range topic.PartitionIndexes {
+				topicResult.Partitions = append(topicResult.Partitions, protocol.WriteTxnMarkersResponseWritableTxnMarkerPartitionResult{
+					PartitionIndex: p,
+					ErrorCode:      errorCode,
+				})
+			}
+			result.Topics = append(result.Topics, topicResult)
+		}
+		resp.Markers = append(resp.Markers, result)
+	}
+	return protocol.NewResponse(req, resp)
+}
@@ -0,0 +1,504 @@
+// Package metadata maintains the broker's view of the cluster metadata,
+// rebuilt from the KRaft __cluster_metadata log
+package metadata
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// Leader sentinels used by partition records
+const (
+	NoLeader       int32 = -1
+	NoLeaderChange int32 = -2
+)
+
+// Config resource types used by ConfigRecord
+const (
+	ResourceTopic  int8 = 2
+	ResourceBroker int8 = 4
+)
+
+// internalTopics are topics owned by the broker rather than by users
+var internalTopics = map[string]bool{
+	"__consumer_offsets":  true,
+	"__transaction_state": true,
+}
+
+// Partition is the replica assignment and leadership state of a partition
+type Partition struct {
+	Index                  int32
+	Leader                 int32
+	LeaderEpoch            int32
+	PartitionEpoch         int32
+	LeaderRecoveryState    int8
+	Replicas               []int32
+	ISR                    []int32
+	RemovingReplicas       []int32
+	AddingReplicas         []int32
+	EligibleLeaderReplicas []int32
+	LastKnownELR           []int32
+	Directories            []protocol.UUID
+}
+
+// Topic is a topic and its partitions, sorted by partition index.
+// Topics in an image are never modified in place: every change installs a
+// new copy, so callers may keep and read a Topic without locking.
+type Topic struct {
+	Name       string
+	ID         protocol.UUID
+	Partitions []Partition
+}
+
+// IsInternal reports whether the topic is one of Kafka's internal topics
+func (t *Topic) IsInternal() bool {
+	return internalTopics[t.Name]
+}
+
+// Partition returns the partition with the given index
+func (t *Topic) Partition(index int32) (*Partition, bool) {
+	i, found := slices.BinarySearchFunc(t.Partitions, index, func(p Partition, index int32) int {
+		return int(p.Index) - int(index)
+	})
+	if !found {
+		return nil, false
+	}
+	return &t.Partitions[i], true
+}
+
+// withPartition returns a copy of the topic with p added or replaced
+func (t *Topic) withPartition(p Partition) *Topic {
+	clone := *t
+	clone.Partitions = slices.Clone(t.Partitions)
+	i, found := slices.BinarySearchFunc(clone.Partitions, p.Index, func(p Partition, index int32) int {
+		return int(p.Index) - int(index)
+	})
+	if found {
+		clone.Partitions[i] = p
+	} else {
+		clone.Partitions = slices.Insert(clone.Partitions, i, p)
+	}
+	return &clone
+}
+
+// Endpoint is a listener a broker accepts connections on
+type Endpoint struct {
+	Name             string
+	Host             string
+	Port             uint16
+	SecurityProtocol int16
+}
+
+// Broker is a registered broker
+type Broker struct {
+	ID        int32
+	Epoch     int64
+	Endpoints []Endpoint
+	Rack      *string
+	Fenced    bool
+}
+
+// configKey identifies the resource a set of configs applies to
+type configKey struct {
+	resourceType int8
+	resourceName string
+}
+
+// Image is the in-memory cluster metadata image: topics, partitions,
+// brokers, finalized features, dynamic configs and the producer IDs handed
+// out so far. It is safe for concurrent use.
+type Image struct {
+	// publishMu serializes writers so records land in the log in the
+	// order they are applied
+	publishMu   sync.Mutex
+	log         *storage.Log
+	leaderEpoch int32
+
+	mu       sync.RWMutex
+	offset   int64
+	topics   map[protocol.UUID]*Topic
+	topicIDs map[string]protocol.UUID
+	brokers  map[int32]*Broker
+	features map[string]int16
+	configs  map[configKey]map[string]string
+	// nextProducerID is the first producer ID not yet reserved by a
+	// ProducerIdsRecord
+	nextProducerID int64
+}
+
+// NewImage creates an empty metadata image
+func NewImage() *Image {
+	return &Image{
+		offset:      -1,
+		leaderEpoch: -1,
+		topics:      make(map[protocol.UUID]*Topic),
+		topicIDs:    make(map[string]protocol.UUID),
+		brokers:     make(map[int32]*Broker),
+		features:    make(map[string]int16),
+		configs:     make(map[configKey]map[string]string),
+	}
+}
+
+// Offset returns the offset of the last record applied to the image, or -1
+func (img *Image) Offset() int64 {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	return img.offset
+}
+
+// TopicByName returns the topic with the given name, or nil
+func (img *Image) TopicByName(name string) *Topic {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	id, ok := img.topicIDs[name]
+	if !ok {
+		return nil
+	}
+	return img.topics[id]
+}
+
+// TopicByID returns the topic with the given ID, or nil
+func (img *Image) TopicByID(id protocol.UUID) *Topic {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	return img.topics[id]
+}
+
+// Topics returns every topic, sorted by name
+func (img *Image) Topics() []*Topic {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	topics := make([]*Topic, 0, len(img.topics))
+	for _, t := range img.topics {
+		topics = append(topics, t)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics
+}
+
+// Brokers returns every registered broker, sorted by ID
+func (img *Image) Brokers() []Broker {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	brokers := make([]Broker, 0, len(img.brokers))
+	for _, b := range img.brokers {
+		brokers = append(brokers, *b)
+	}
+	sort.Slice(brokers, func(i, j int) bool { return brokers[i].ID < brokers[j].ID })
+	return brokers
+}
+
+// FinalizedFeatures returns the finalized feature levels and the epoch at
+// which they were read, which is the offset of the last applied record
+func (img *Image) FinalizedFeatures() (int64, map[string]int16) {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	levels := make(map[string]int16, len(img.features))
+	for name, level := range img.features {
+		levels[name] = level
+	}
+	return img.offset, levels
+}
+
+// NextProducerID returns the first producer ID no broker has reserved
+func (img *Image) NextProducerID() int64 {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	return img.nextProducerID
+}
+
+// Configs returns the dynamic configs set for a resource
+func (img *Image) Configs(resourceType int8, resourceName string) map[string]string {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	configs := make(map[string]string)
+	for name, value := range img.configs[configKey{resourceType, resourceName}] {
+		configs[name] = value
+	}
+	return configs
+}
+
+// Apply applies a decoded metadata record found at the given log offset
+func (img *Image) Apply(offset int64, record protocol.Message) error {
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	return img.apply(offset, record)
+}
+
+// Publish appends records to the metadata log as a single batch and
+// applies them to the image. An image without a log, such as one made by
+// NewImage, only applies them. Records the image would reject are never
+// appended, since replaying them would keep the broker from starting.
+func (img *Image) Publish(records ...protocol.Message) error {
+	img.publishMu.Lock()
+	defer img.publishMu.Unlock()
+
+	if err := img.validate(records); err != nil {
+		return err
+	}
+
+	first := img.Offset() + 1
+	if img.log != nil {
+		values := make([]record.Record, len(records))
+		for i, r := range records {
+			values[i].Value = EncodeRecord(r, recordVersion(r))
+		}
+		now := time.Now().UnixMilli()
+		batch := record.EncodeBatch(record.Batch{
+			PartitionLeaderEpoch: img.leaderEpoch,
+			BaseTimestamp:        now,
+			MaxTimestamp:         now,
+			ProducerID:           record.NoProducerID,
+			ProducerEpoch:        record.NoProducerEpoch,
+			BaseSequence:         record.NoSequence,
+		}, values)
+
+		info, err := img.log.Append(batch.Data)
+		if err != nil {
+			return fmt.Errorf("failed to append to the metadata log: %w", err)
+		}
+		if err := img.log.Flush(); err != nil {
+			return fmt.Errorf("failed to flush the metadata log: %w", err)
+		}
+		first = info.FirstOffset
+	}
+
+	img.mu.Lock()
+	defer img.mu.Unlock()
+
+	for i, r := range records {
+		if err := img.apply(first+int64(i), r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the metadata log
+func (img *Image) Close() error {
+	img.publishMu.Lock()
+	defer img.publishMu.Unlock()
+
+	if img.log == nil {
+		return nil
+	}
+	return img.log.Close()
+}
+
+// validate checks that records, applied in order, would all apply to the
+// image: partition records must refer to topics that exist by then, and
+// partition change records to partitions that do
+func (img *Image) validate(records []protocol.Message) error {
+	img.mu.RLock()
+	defer img.mu.RUnlock()
+
+	// partitions holds the partition indexes of the topics the records
+	// touch, as they stand after the records so far; nil marks a topic
+	// removed by them
+	partitions := make(map[protocol.UUID]map[int32]bool)
+	lookup := func(id protocol.UUID) (map[int32]bool, bool) {
+		if p, ok := partitions[id]; ok {
+			return p, p != nil
+		}
+		topic, ok := img.topics[id]
+		if !ok {
+			return nil, false
+		}
+		p := make(map[int32]bool, len(topic.Partitions))
+		for _, partition := range topic.Partitions {
+			p[partition.Index] = true
+		}
+		partitions[id] = p
+		return p, true
+	}
+
+	for _, record := range records {
+		switch r := record.(type) {
+		case *protocol.TopicRecord:
+			partitions[r.TopicId] = make(map[int32]bool)
+		case *protocol.RemoveTopicRecord:
+			partitions[r.TopicId] = nil
+		case *protocol.PartitionRecord:
+			p, ok := lookup(r.TopicId)
+			if !ok {
+				return fmt.Errorf("partition record for unknown topic %s", r.TopicId)
+			}
+			p[r.PartitionId] = true
+		case *protocol.PartitionChangeRecord:
+			p, ok := lookup(r.TopicId)
+			if !ok {
+				return fmt.Errorf("partition change record for unknown topic %s", r.TopicId)
+			}
+			if !p[r.PartitionId] {
+				return fmt.Errorf("partition change record for unknown partition %s-%d", r.TopicId, r.PartitionId)
+			}
+		}
+	}
+	return nil
+}
+
+// recordVersion returns the version a record is written in: the highest
+// its generated codec supports
+func recordVersion(r protocol.Message) int16 {
+	if v, ok := r.(interface{ MaxVersion() int16 }); ok {
+		return v.MaxVersion()
+	}
+	return 0
+}
+
+// apply applies a record with img.mu held
+func (img *Image) apply(offset int64, record protocol.Message) error {
+	switch r := record.(type) {
+	case *protocol.TopicRecord:
+		img.topics[r.TopicId] = &Topic{Name: r.Name, ID: r.TopicId}
+		img.topicIDs[r.Name] = r.TopicId
+	case *protocol.PartitionRecord:
+		topic, ok := img.topics[r.TopicId]
+		if !ok {
+			return fmt.Errorf("partition record for unknown topic %s", r.TopicId)
+		}
+		img.topics[r.TopicId] = topic.withPartition(Partition{
+			Index:                  r.PartitionId,
+			Leader:                 r.Leader,
+			LeaderEpoch:            r.LeaderEpoch,
+			PartitionEpoch:         r.PartitionEpoch,
+			LeaderRecoveryState:    r.LeaderRecoveryState,
+			Replicas:               r.Replicas,
+			ISR:                    r.Isr,
+			RemovingReplicas:       r.RemovingReplicas,
+			AddingReplicas:         r.AddingReplicas,
+			EligibleLeaderReplicas: r.EligibleLeaderReplicas,
+			LastKnownELR:           r.LastKnownElr,
+			Directories:            r.Directories,
+		})
+	case *protocol.PartitionChangeRecord:
+		if err := img.applyPartitionChange(r); err != nil {
+			return err
+		}
+	case *protocol.RemoveTopicRecord:
+		if topic, ok := img.topics[r.TopicId]; ok {
+			delete(img.topicIDs, topic.Name)
+			delete(img.topics, r.TopicId)
+			delete(img.configs, configKey{ResourceTopic, topic.Name})
+		}
+	case *protocol.ConfigRecord:
+		key := configKey{r.ResourceType, r.ResourceName}
+		if r.Value == nil {
+			delete(img.configs[key], r.Name)
+			break
+		}
+		if img.configs[key] == nil {
+			img.configs[key] = make(map[string]string)
+		}
+		img.configs[key][r.Name] = *r.Value
+	case *protocol.FeatureLevelRecord:
+		if r.FeatureLevel == 0 {
+			delete(img.features, r.Name)
+		} else {
+			img.features[r.Name] = r.FeatureLevel
+		}
+	case *protocol.RegisterBrokerRecord:
+		broker := &Broker{ID: r.BrokerId, Epoch: r.BrokerEpoch, Rack: r.Rack, Fenced: r.Fenced}
+		for _, ep := range r.EndPoints {
+			broker.Endpoints = append(broker.Endpoints, Endpoint{
+				Name:             ep.Name,
+				Host:             ep.Host,
+				Port:             ep.Port,
+				SecurityProtocol: ep.SecurityProtocol,
+			})
+		}
+		img.brokers[r.BrokerId] = broker
+	case *protocol.UnregisterBrokerRecord:
+		delete(img.brokers, r.BrokerId)
+	case *protocol.FenceBrokerRecord:
+		img.setFenced(r.Id, true)
+	case *protocol.UnfenceBrokerRecord:
+		img.setFenced(r.Id, false)
+	case *protocol.ProducerIdsRecord:
+		img.nextProducerID = r.NextProducerId
+	case *protocol.BrokerRegistrationChangeRecord:
+		switch r.Fenced {
+		case 1:
+			img.setFenced(r.BrokerId, true)
+		case -1:
+			img.setFenced(r.BrokerId, false)
+		}
+	}
+
+	if offset > img.offset {
+		img.offset = offset
+	}
+	return nil
+}
+
+// applyPartitionChange merges a PartitionChangeRecord into its partition.
+// Null fields in the record mean "unchanged".
+func (img *Image) applyPartitionChange(r *protocol.PartitionChangeRecord) error {
+	topic, ok := img.topics[r.TopicId]
+	if !ok {
+		return fmt.Errorf("partition change record for unknown topic %s", r.TopicId)
+	}
+	current, ok := topic.Partition(r.PartitionId)
+	if !ok {
+		return fmt.Errorf("partition change record for unknown partition %s-%d", topic.Name, r.PartitionId)
+	}
+
+	p := *current
+	if r.Isr != nil {
+		p.ISR = r.Isr
+	}
+	if r.Leader != NoLeaderChange {
+		p.Leader = r.Leader
+		p.LeaderEpoch++
+	}
+	if r.Replicas != nil {
+		p.Replicas = r.Replicas
+	}
+	if r.RemovingReplicas != nil {
+		p.RemovingReplicas = r.RemovingReplicas
+	}
+	if r.AddingReplicas != nil {
+		p.AddingReplicas = r.AddingReplicas
+	}
+	if r.LeaderRecoveryState != -1 {
+		p.LeaderRecoveryState = r.LeaderRecoveryState
+	}
+	if r.Directories != nil {
+		p.Directories = r.Directories
+	}
+	if r.EligibleLeaderReplicas != nil {
+		p.EligibleLeaderReplicas = r.EligibleLeaderReplicas
+	}
+	if r.LastKnownElr != nil {
+		p.LastKnownELR = r.LastKnownElr
+	}
+	p.PartitionEpoch++
+
+	img.topics[r.TopicId] = topic.withPartition(p)
+	return nil
+}
+
+// setFenced updates the fencing state of a registered broker
+func (img *Image) setFenced(id int32, fenced bool) {
+	if b, ok := img.brokers[id]; ok {
+		clone := *b
+		clone.Fenced = fenced
+		img.brokers[id] = &clone
+	}
+}
@@ -0,0 +1,109 @@
+package metadata
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// loadTestImage loads the image of the metadata log under logDir
+func loadTestImage(t *testing.T, logDir string) *Image {
+	t.Helper()
+	img, err := Load(logDir, logger.New(logger.ERROR))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return img
+}
+
+// partitionRecord returns the record of a partition of topic led by broker 1
+func partitionRecord(topic protocol.UUID, partition int32) *protocol.PartitionRecord {
+	return &protocol.PartitionRecord{TopicId: topic, PartitionId: partition, Replicas: []int32{1}, Isr: []int32{1}, Leader: 1}
+}
+
+// partitionChange returns a record moving the leader of a partition of
+// topic to broker 2
+func partitionChange(topic protocol.UUID, partition int32) *protocol.PartitionChangeRecord {
+	r := &protocol.PartitionChangeRecord{}
+	r.Default()
+	r.TopicId, r.PartitionId, r.Leader = topic, partition, 2
+	return r
+}
+
+// topicNames returns the names of the topics of img with their partition
+// counts, as name/count
+func topicNames(img *Image) []string {
+	var names []string
+	for _, t := range img.Topics() {
+		names = append(names, fmt.Sprintf("%s/%d", t.Name, len(t.Partitions)))
+	}
+	return names
+}
+
+func TestPublishRejected(t *testing.T) {
+	dir := t.TempDir()
+	img := loadTestImage(t, dir)
+	known := protocol.RandomUUID()
+	if err := img.Publish(&protocol.TopicRecord{Name: "known", TopicId: known}, partitionRecord(known, 0)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	offset, end := img.Offset(), img.log.LogEndOffset()
+	topics := topicNames(img)
+
+	unknown, added := protocol.RandomUUID(), protocol.RandomUUID()
+	tests := []struct {
+		name    string
+		records []protocol.Message
+	}{
+		{"partition of unknown topic", []protocol.Message{partitionRecord(unknown, 0)}},
+		{"change of unknown topic", []protocol.Message{partitionChange(unknown, 0)}},
+		{"change of unknown partition", []protocol.Message{partitionChange(known, 1)}},
+		// Valid records before the invalid one are not applied either
+		{"after valid records", []protocol.Message{
+			&protocol.TopicRecord{Name: "added", TopicId: added},
+			partitionRecord(added, 0),
+			partitionRecord(known, 1),
+			partitionChange(added, 1),
+		}},
+		{"partition of topic removed before", []protocol.Message{
+			&protocol.RemoveTopicRecord{TopicId: known},
+			partitionRecord(known, 1),
+		}},
+	}
+	for _, tt := range tests {
+		if err := img.Publish(tt.records...); err == nil {
+			t.Errorf("%s: Publish succeeded", tt.name)
+		}
+		if got := img.Offset(); got != offset {
+			t.Errorf("%s: image offset = %d, want %d", tt.name, got, offset)
+		}
+		if got := img.log.LogEndOffset(); got != end {
+			t.Errorf("%s: log end offset = %d, want %d", tt.name, got, end)
+		}
+		if got := topicNames(img); !slices.Equal(got, topics) {
+			t.Errorf("%s: topics = %v, want %v", tt.name, got, topics)
+		}
+	}
+
+	// Records that depend on each other in one batch are valid
+	if err := img.Publish(&protocol.TopicRecord{Name: "added", TopicId: added}, partitionRecord(added, 0), partitionChange(added, 0)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	topics = topicNames(img)
+
+	// Nothing rejected was written for the next load to trip over
+	if err := img.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	img = loadTestImage(t, dir)
+	defer img.Close()
+	if got := topicNames(img); !slices.Equal(got, topics) {
+		t.Errorf("topics after reload = %v, want %v", got, topics)
+	}
+	if p, _ := img.TopicByName("added").Partition(0); p.Leader != 2 {
+		t.Errorf("leader after reload = %d, want 2", p.Leader)
+	}
+}
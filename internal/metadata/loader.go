@@ -0,0 +1,208 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// TopicName is the name of the KRaft metadata topic
+const TopicName = "__cluster_metadata"
+
+// Dir returns the directory holding the metadata log under logDir
+func Dir(logDir string) string {
+	return filepath.Join(logDir, TopicName+"-0")
+}
+
+// Load builds an image from the metadata log under logDir. It starts from
+// the newest snapshot (*.checkpoint), if any, and replays the log segments
+// (*.log) past it. A missing metadata log yields an empty image. The log
+// is then kept open so that new records can be published to it.
+func Load(logDir string, logger *logger.Logger) (*Image, error) {
+	img := NewImage()
+	dir := Dir(logDir)
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Info("No metadata log found in %s, starting with an empty image", dir)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read metadata log directory: %w", err)
+	}
+
+	var segments, snapshots []string
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".log":
+			segments = append(segments, entry.Name())
+		case ".checkpoint":
+			snapshots = append(snapshots, entry.Name())
+		}
+	}
+	// File names start with a zero-padded offset, so they sort by offset
+	sort.Strings(segments)
+	sort.Strings(snapshots)
+
+	// Records before the snapshot's end offset are already in the snapshot
+	startOffset := int64(0)
+	if len(snapshots) > 0 {
+		name := snapshots[len(snapshots)-1]
+		endOffset, err := strconv.ParseInt(strings.SplitN(name, "-", 2)[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot file name %s", name)
+		}
+		if err := replayFile(img, filepath.Join(dir, name), -1, logger); err != nil {
+			return nil, err
+		}
+		startOffset = endOffset
+	}
+
+	for _, name := range segments {
+		if err := replayFile(img, filepath.Join(dir, name), startOffset, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	log, err := storage.OpenLog(dir, TopicName, 0, storage.DefaultConfig(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metadata log: %w", err)
+	}
+	// New records must follow the snapshot even if the log behind it is gone
+	if log.LogEndOffset() < startOffset {
+		if err := log.TruncateFullyAndStartAt(startOffset); err != nil {
+			log.Close()
+			return nil, fmt.Errorf("failed to reset metadata log: %w", err)
+		}
+	}
+	img.log = log
+
+	logger.Info("Loaded metadata image at offset %d: %d topics, %d brokers",
+		img.Offset(), len(img.topics), len(img.brokers))
+	return img, nil
+}
+
+// replayFile applies every record batch of a segment or snapshot file to
+// the image. Records below startOffset are skipped; snapshots pass -1 since
+// their offsets restart at zero. Replay stops at the first torn or corrupt
+// batch, which can only be the unflushed tail of the log.
+func replayFile(img *Image, path string, startOffset int64, logger *logger.Logger) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for len(data) > 0 {
+		batch, err := record.NextBatch(data)
+		if err != nil {
+			logger.Error("Stopping metadata replay of %s: %s", filepath.Base(path), err.Error())
+			return nil
+		}
+		data = data[batch.Size():]
+		img.leaderEpoch = batch.PartitionLeaderEpoch
+
+		// Control batches hold KRaft leader changes and snapshot markers
+		if batch.IsControl() || batch.LastOffset() < startOffset {
+			continue
+		}
+
+		records, err := batch.Records()
+		if err != nil {
+			return fmt.Errorf("failed to read metadata batch at offset %d: %w", batch.BaseOffset, err)
+		}
+		for i := range records {
+			offset := batch.BaseOffset + int64(records[i].OffsetDelta)
+			if offset < startOffset {
+				continue
+			}
+
+			msg, err := DecodeRecord(records[i].Value)
+			if err != nil {
+				return fmt.Errorf("failed to decode metadata record at offset %d: %w", offset, err)
+			}
+			if msg == nil {
+				logger.Debug("Skipping unknown metadata record at offset %d", offset)
+				continue
+			}
+			if err := img.Apply(offset, msg); err != nil {
+				return fmt.Errorf("failed to apply metadata record at offset %d: %w", offset, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DecodeRecord decodes the value of a metadata log record: a frame
+// version, the record type and its version, followed by the record body.
+// It returns nil for record types the image does not track.
+func DecodeRecord(value []byte) (protocol.Message, error) {
+	d := protocol.NewDecoder(value)
+	frameVersion := d.Uvarint()
+	recordType := d.Uvarint()
+	version := int16(d.Uvarint())
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	if frameVersion != 0 {
+		return nil, fmt.Errorf("unsupported metadata frame version %d", frameVersion)
+	}
+
+	msg := newRecord(int16(recordType))
+	if msg == nil {
+		return nil, nil
+	}
+	if err := msg.Decode(d, version); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// EncodeRecord encodes a metadata record as a log record value
+func EncodeRecord(msg protocol.Message, version int16) []byte {
+	e := protocol.NewEncoder(64)
+	e.PutUvarint(0)
+	e.PutUvarint(uint64(msg.APIKey()))
+	e.PutUvarint(uint64(version))
+	msg.Encode(e, version)
+	return e.Bytes()
+}
+
+// newRecord returns an empty record of the given type, or nil
+func newRecord(recordType int16) protocol.Message {
+	switch recordType {
+	case (*protocol.RegisterBrokerRecord)(nil).APIKey():
+		return &protocol.RegisterBrokerRecord{}
+	case (*protocol.UnregisterBrokerRecord)(nil).APIKey():
+		return &protocol.UnregisterBrokerRecord{}
+	case (*protocol.TopicRecord)(nil).APIKey():
+		return &protocol.TopicRecord{}
+	case (*protocol.PartitionRecord)(nil).APIKey():
+		return &protocol.PartitionRecord{}
+	case (*protocol.ConfigRecord)(nil).APIKey():
+		return &protocol.ConfigRecord{}
+	case (*protocol.PartitionChangeRecord)(nil).APIKey():
+		return &protocol.PartitionChangeRecord{}
+	case (*protocol.FenceBrokerRecord)(nil).APIKey():
+		return &protocol.FenceBrokerRecord{}
+	case (*protocol.RemoveTopicRecord)(nil).APIKey():
+		return &protocol.RemoveTopicRecord{}
+	case (*protocol.UnfenceBrokerRecord)(nil).APIKey():
+		return &protocol.UnfenceBrokerRecord{}
+	case (*protocol.FeatureLevelRecord)(nil).APIKey():
+		return &protocol.FeatureLevelRecord{}
+	case (*protocol.ProducerIdsRecord)(nil).APIKey():
+		return &protocol.ProducerIdsRecord{}
+	case (*protocol.BrokerRegistrationChangeRecord)(nil).APIKey():
+		return &protocol.BrokerRegistrationChangeRecord{}
+	case (*protocol.NoOpRecord)(nil).APIKey():
+		return &protocol.NoOpRecord{}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+// encodeBatches encodes each group of records as a metadata log batch,
+// with offsets following on from each other
+func encodeBatches(batches ...[]protocol.Message) []byte {
+	var data []byte
+	offset := int64(0)
+	for _, records := range batches {
+		values := make([]record.Record, len(records))
+		for i, r := range records {
+			values[i].Value = EncodeRecord(r, recordVersion(r))
+		}
+		batch := record.EncodeBatch(record.Batch{
+			BaseOffset:    offset,
+			ProducerID:    record.NoProducerID,
+			ProducerEpoch: record.NoProducerEpoch,
+			BaseSequence:  record.NoSequence,
+		}, values)
+		data = append(data, batch.Data...)
+		offset += int64(len(records))
+	}
+	return data
+}
+
+// writeSegment writes data as the first segment of the metadata log under
+// logDir
+func writeSegment(t *testing.T, logDir string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(Dir(logDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(Dir(logDir), "00000000000000000000.log"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// topicConfig returns a record setting, or with a nil value deleting, a
+// config of topic
+func topicConfig(topic, name string, value *string) *protocol.ConfigRecord {
+	return &protocol.ConfigRecord{ResourceType: ResourceTopic, ResourceName: topic, Name: name, Value: value}
+}
+
+func TestLoadReplaysInOrder(t *testing.T) {
+	orders, removed, recreated := protocol.RandomUUID(), protocol.RandomUUID(), protocol.RandomUUID()
+	short, long, compact := "1000", "2000", "compact"
+	data := encodeBatches(
+		[]protocol.Message{
+			&protocol.TopicRecord{Name: "orders", TopicId: orders},
+			partitionRecord(orders, 0),
+			topicConfig("orders", "retention.ms", &short),
+			topicConfig("orders", "cleanup.policy", &compact),
+		},
+		[]protocol.Message{
+			// Later records override earlier ones
+			topicConfig("orders", "retention.ms", &long),
+			topicConfig("orders", "cleanup.policy", nil),
+			&protocol.TopicRecord{Name: "payments", TopicId: removed},
+			partitionRecord(removed, 0),
+			topicConfig("payments", "retention.ms", &short),
+		},
+		[]protocol.Message{
+			// Removing a topic drops its configs, and its name can be
+			// reused by a new topic
+			&protocol.RemoveTopicRecord{TopicId: removed},
+			&protocol.TopicRecord{Name: "payments", TopicId: recreated},
+			partitionRecord(recreated, 0),
+			partitionRecord(recreated, 1),
+		},
+	)
+	dir := t.TempDir()
+	writeSegment(t, dir, data)
+
+	img := loadTestImage(t, dir)
+	defer img.Close()
+	if got := img.Offset(); got != 12 {
+		t.Errorf("offset = %d, want 12", got)
+	}
+	if got, want := topicNames(img), []string{"orders/1", "payments/2"}; !slices.Equal(got, want) {
+		t.Errorf("topics = %v, want %v", got, want)
+	}
+	if topic := img.TopicByName("payments"); topic == nil || topic.ID != recreated {
+		t.Errorf("payments = %+v, want topic ID %s", topic, recreated)
+	}
+	if got := img.Configs(ResourceTopic, "orders"); len(got) != 1 || got["retention.ms"] != long {
+		t.Errorf("orders configs = %v, want retention.ms=%s", got, long)
+	}
+	if got := img.Configs(ResourceTopic, "payments"); len(got) != 0 {
+		t.Errorf("payments configs = %v, want none", got)
+	}
+}
+
+func TestLoadTruncatedBatch(t *testing.T) {
+	id, torn := protocol.RandomUUID(), protocol.RandomUUID()
+	replayed := [][]protocol.Message{
+		{&protocol.TopicRecord{Name: "orders", TopicId: id}, partitionRecord(id, 0)},
+		{partitionRecord(id, 1)},
+	}
+	full := len(encodeBatches(replayed...))
+	data := encodeBatches(append(replayed,
+		[]protocol.Message{&protocol.TopicRecord{Name: "torn", TopicId: torn}, partitionRecord(torn, 0)},
+	)...)
+
+	// A batch cut short anywhere, even within its header, is the unflushed
+	// tail of the log: the batches before it are replayed and it is not
+	for _, cut := range []int{1, record.HeaderSize, len(data) - full - 1} {
+		dir := t.TempDir()
+		writeSegment(t, dir, data[:full+cut])
+
+		img := loadTestImage(t, dir)
+		if got := img.Offset(); got != 2 {
+			t.Errorf("cut %d: offset = %d, want 2", cut, got)
+		}
+		if got, want := topicNames(img), []string{"orders/2"}; !slices.Equal(got, want) {
+			t.Errorf("cut %d: topics = %v, want %v", cut, got, want)
+		}
+
+		// New records replace the torn batch and survive a reload
+		if err := img.Publish(partitionRecord(id, 2)); err != nil {
+			t.Fatalf("cut %d: Publish: %v", cut, err)
+		}
+		if err := img.Close(); err != nil {
+			t.Fatalf("cut %d: Close: %v", cut, err)
+		}
+		img = loadTestImage(t, dir)
+		if got, want := topicNames(img), []string{"orders/3"}; !slices.Equal(got, want) {
+			t.Errorf("cut %d: topics after reload = %v, want %v", cut, got, want)
+		}
+		img.Close()
+	}
+}
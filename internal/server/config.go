@@ -0,0 +1,305 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/internal/txn"
+)
+
+// DefaultLogDir is where logs are kept when no log.dirs is configured
+const DefaultLogDir = "/tmp/kraft-combined-logs"
+
+// Config holds server configuration
+type Config struct {
+	Host       string
+	Port       int
+	MaxClients int
+	NodeID     int32
+	LogDir     string
+
+	// AdvertisedHost and AdvertisedPort are handed to clients in Metadata
+	// responses; by default they follow the listener
+	AdvertisedHost string
+	AdvertisedPort int
+
+	// AutoCreateTopics allows Metadata requests to create missing topics
+	// with NumPartitions partitions
+	AutoCreateTopics         bool
+	NumPartitions            int32
+	DefaultReplicationFactor int16
+
+	// MetadataLogDir holds the __cluster_metadata log; empty means LogDir
+	MetadataLogDir string
+
+	// Log holds the partition log settings
+	Log storage.Config
+
+	// Groups holds the group coordinator settings
+	Groups group.Config
+
+	// Transactions holds the transaction coordinator settings
+	Transactions txn.Config
+}
+
+// DefaultConfig returns the configuration used when no properties file is given
+func DefaultConfig() Config {
+	return Config{
+		Host:                     "0.0.0.0",
+		Port:                     9092,
+		NodeID:                   1,
+		LogDir:                   DefaultLogDir,
+		AutoCreateTopics:         true,
+		NumPartitions:            1,
+		DefaultReplicationFactor: 1,
+		Log:                      storage.DefaultConfig(),
+		Groups:                   group.DefaultConfig(),
+		Transactions:             txn.DefaultConfig(),
+	}
+}
+
+// LoadConfig reads a Kafka server.properties file on top of DefaultConfig.
+// Only the settings this broker understands are used; the rest are ignored.
+func LoadConfig(path string) (Config, error) {
+	config := DefaultConfig()
+
+	props, err := readProperties(path)
+	if err != nil {
+		return config, err
+	}
+
+	if v, ok := props["node.id"]; ok {
+		id, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return config, fmt.Errorf("invalid node.id %q", v)
+		}
+		config.NodeID = int32(id)
+	}
+
+	// Only the first of several log dirs is used
+	if v, ok := props["log.dirs"]; ok {
+		config.LogDir = strings.TrimSpace(strings.Split(v, ",")[0])
+	} else if v, ok := props["log.dir"]; ok {
+		config.LogDir = v
+	}
+	if v, ok := props["metadata.log.dir"]; ok {
+		config.MetadataLogDir = v
+	}
+
+	if v, ok := props["log.segment.bytes"]; ok {
+		if config.Log.SegmentBytes, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return config, fmt.Errorf("invalid log.segment.bytes %q", v)
+		}
+	}
+	if v, ok := props["log.roll.hours"]; ok {
+		hours, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return config, fmt.Errorf("invalid log.roll.hours %q", v)
+		}
+		config.Log.SegmentMs = hours * 60 * 60 * 1000
+	}
+	if v, ok := props["log.roll.ms"]; ok {
+		if config.Log.SegmentMs, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return config, fmt.Errorf("invalid log.roll.ms %q", v)
+		}
+	}
+	if v, ok := props["log.index.interval.bytes"]; ok {
+		if config.Log.IndexIntervalBytes, err = strconv.Atoi(v); err != nil {
+			return config, fmt.Errorf("invalid log.index.interval.bytes %q", v)
+		}
+	}
+	if v, ok := props["log.cleaner.backoff.ms"]; ok {
+		if config.Log.CompactionIntervalMs, err = strconv.ParseInt(v, 10, 64); err != nil || config.Log.CompactionIntervalMs <= 0 {
+			return config, fmt.Errorf("invalid log.cleaner.backoff.ms %q", v)
+		}
+	}
+	if v, ok := props["log.retention.check.interval.ms"]; ok {
+		if config.Log.RetentionCheckIntervalMs, err = strconv.ParseInt(v, 10, 64); err != nil || config.Log.RetentionCheckIntervalMs <= 0 {
+			return config, fmt.Errorf("invalid log.retention.check.interval.ms %q", v)
+		}
+	}
+
+	if v, ok := props["listeners"]; ok {
+		host, port, err := plaintextListener(v)
+		if err != nil {
+			return config, err
+		}
+		config.Host, config.Port = host, port
+	}
+	if v, ok := props["advertised.listeners"]; ok {
+		host, port, err := plaintextListener(v)
+		if err != nil {
+			return config, err
+		}
+		config.AdvertisedHost, config.AdvertisedPort = host, port
+	}
+
+	if v, ok := props["auto.create.topics.enable"]; ok {
+		if config.AutoCreateTopics, err = strconv.ParseBool(v); err != nil {
+			return config, fmt.Errorf("invalid auto.create.topics.enable %q", v)
+		}
+	}
+	if v, ok := props["num.partitions"]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 1 {
+			return config, fmt.Errorf("invalid num.partitions %q", v)
+		}
+		config.NumPartitions = int32(n)
+	}
+	if v, ok := props["default.replication.factor"]; ok {
+		n, err := strconv.ParseInt(v, 10, 16)
+		if err != nil || n < 1 {
+			return config, fmt.Errorf("invalid default.replication.factor %q", v)
+		}
+		config.DefaultReplicationFactor = int16(n)
+	}
+
+	for name, setting := range map[string]*time.Duration{
+		"group.min.session.timeout.ms":     &config.Groups.MinSessionTimeout,
+		"group.max.session.timeout.ms":     &config.Groups.MaxSessionTimeout,
+		"group.initial.rebalance.delay.ms": &config.Groups.InitialRebalanceDelay,
+
+		"group.consumer.session.timeout.ms":    &config.Groups.ConsumerSessionTimeout,
+		"group.consumer.heartbeat.interval.ms": &config.Groups.ConsumerHeartbeatInterval,
+
+		"transaction.max.timeout.ms": &config.Transactions.MaxTimeout,
+	} {
+		if v, ok := props[name]; ok {
+			ms, err := strconv.ParseInt(v, 10, 32)
+			if err != nil || ms < 0 {
+				return config, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*setting = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if v, ok := props["group.max.size"]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 1 {
+			return config, fmt.Errorf("invalid group.max.size %q", v)
+		}
+		config.Groups.MaxSize = int(n)
+	}
+	if v, ok := props["group.consumer.max.size"]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 1 {
+			return config, fmt.Errorf("invalid group.consumer.max.size %q", v)
+		}
+		config.Groups.ConsumerMaxSize = int(n)
+	}
+	if v, ok := props["group.consumer.assignors"]; ok {
+		config.Groups.ConsumerAssignors = nil
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				config.Groups.ConsumerAssignors = append(config.Groups.ConsumerAssignors, name)
+			}
+		}
+	}
+	if v, ok := props["offsets.topic.num.partitions"]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 1 {
+			return config, fmt.Errorf("invalid offsets.topic.num.partitions %q", v)
+		}
+		config.Groups.OffsetsTopicPartitions = int32(n)
+	}
+	if v, ok := props["offset.metadata.max.bytes"]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 0 {
+			return config, fmt.Errorf("invalid offset.metadata.max.bytes %q", v)
+		}
+		config.Groups.OffsetMetadataMaxBytes = int(n)
+	}
+	if v, ok := props["transaction.state.log.num.partitions"]; ok {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 1 {
+			return config, fmt.Errorf("invalid transaction.state.log.num.partitions %q", v)
+		}
+		config.Transactions.StateTopicPartitions = int32(n)
+	}
+
+	return config, nil
+}
+
+// advertisedListener returns the address clients are told to connect to.
+// Without advertised.listeners it is the listener address, with a wildcard
+// host replaced by localhost.
+func (c Config) advertisedListener() (string, int) {
+	if c.AdvertisedHost != "" {
+		return c.AdvertisedHost, c.AdvertisedPort
+	}
+	if ip := net.ParseIP(c.Host); ip != nil && ip.IsUnspecified() {
+		return "localhost", c.Port
+	}
+	return c.Host, c.Port
+}
+
+// metadataLogDir returns the directory holding the metadata log, which
+// shares the data log dir unless metadata.log.dir is set, as in Kafka
+func (c Config) metadataLogDir() string {
+	if c.MetadataLogDir != "" {
+		return c.MetadataLogDir
+	}
+	return c.LogDir
+}
+
+// plaintextListener returns the host and port of the PLAINTEXT listener in
+// a listeners value such as "PLAINTEXT://:9092,CONTROLLER://:9093"
+func plaintextListener(listeners string) (string, int, error) {
+	for _, listener := range strings.Split(listeners, ",") {
+		name, addr, ok := strings.Cut(strings.TrimSpace(listener), "://")
+		if !ok || name != "PLAINTEXT" {
+			continue
+		}
+
+		host, portStr, err := net.SplitHostPort(addr)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid listener %q: %w", listener, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid listener port %q", portStr)
+		}
+		if host == "" {
+			host = "0.0.0.0"
+		}
+		return host, port, nil
+	}
+	return "", 0, fmt.Errorf("no PLAINTEXT listener in %q", listeners)
+}
+
+// readProperties parses a Java properties file of key=value lines
+func readProperties(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config %s: %w", path, err)
+	}
+	defer f.Close()
+
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if !ok {
+			continue
+		}
+		props[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	return props, nil
+}
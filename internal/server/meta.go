@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+)
+
+// metaPropertiesFile is written by kafka-storage.sh format into every log
+// dir and records the cluster the dir belongs to
+const metaPropertiesFile = "meta.properties"
+
+// loadClusterID returns the cluster.id from the log dir's meta.properties.
+// A log dir that was never formatted gets a new random cluster ID, which
+// is saved so that it survives restarts.
+func loadClusterID(logDir string, nodeID int32) (string, error) {
+	path := filepath.Join(logDir, metaPropertiesFile)
+	props, err := readProperties(path)
+	if err == nil {
+		if id := props["cluster.id"]; id != "" {
+			return id, nil
+		}
+		return "", fmt.Errorf("no cluster.id in %s", path)
+	}
+	if _, statErr := os.Stat(path); !errors.Is(statErr, os.ErrNotExist) {
+		return "", err
+	}
+
+	id := protocol.RandomUUID().Base64()
+	if err := os.MkdirAll(logDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create log dir: %w", err)
+	}
+	contents := fmt.Sprintf("version=1\ncluster.id=%s\nnode.id=%d\n", id, nodeID)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return id, nil
+}
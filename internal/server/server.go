@@ -6,18 +6,16 @@ import (
 	"io"
 	"net"
 	"sync"
+	"time"
 
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
 	"github.com/codecrafters-io/kafka-starter-go/internal/kafka"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/internal/txn"
 	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
 )
 
-// Config holds server configuration
-type Config struct {
-	Host       string
-	Port       int
-	MaxClients int
-}
-
 // Server represents a Kafka server
 type Server struct {
 	config    Config
@@ -25,25 +23,66 @@ type Server struct {
 	listener  net.Listener
 	parser    *kafka.MessageParser
 	handler   *kafka.RequestHandler
+	image     *metadata.Image
+	logs      *storage.Manager
 	wg        sync.WaitGroup
 	clients   map[string]net.Conn
 	clientsMu sync.Mutex
 	shutdown  chan struct{}
 }
 
-// New creates a new Kafka server
-func New(config Config, logger *logger.Logger) *Server {
+// New creates a new Kafka server, loading the cluster metadata and opening
+// the partition logs from the configured log dirs
+func New(config Config, logger *logger.Logger) (*Server, error) {
+	clusterID, err := loadClusterID(config.LogDir, config.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cluster ID: %w", err)
+	}
+	image, err := metadata.Load(config.metadataLogDir(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cluster metadata: %w", err)
+	}
+	logs, err := storage.Open(config.LogDir, config.Log, logger)
+	if err != nil {
+		image.Close()
+		return nil, fmt.Errorf("failed to open partition logs: %w", err)
+	}
+	groups, err := group.Load(config.Groups, image, logs, logger)
+	if err != nil {
+		logs.Close()
+		image.Close()
+		return nil, fmt.Errorf("failed to start group coordinator: %w", err)
+	}
+	transactions, err := txn.Load(config.Transactions, logs, groups, txn.NewProducerIDManager(image, config.NodeID), logger)
+	if err != nil {
+		groups.Close()
+		logs.Close()
+		image.Close()
+		return nil, fmt.Errorf("failed to start transaction coordinator: %w", err)
+	}
+
+	host, port := config.advertisedListener()
 	parser := kafka.NewMessageParser(logger)
-	handler := kafka.NewRequestHandler(logger)
+	handler := kafka.NewRequestHandler(logger, kafka.BrokerConfig{
+		NodeID:                   config.NodeID,
+		ClusterID:                clusterID,
+		Host:                     host,
+		Port:                     int32(port),
+		AutoCreateTopics:         config.AutoCreateTopics,
+		NumPartitions:            config.NumPartitions,
+		DefaultReplicationFactor: config.DefaultReplicationFactor,
+	}, image, logs, groups, transactions)
 
 	return &Server{
 		config:   config,
 		logger:   logger,
 		parser:   parser,
 		handler:  handler,
+		image:    image,
+		logs:     logs,
 		clients:  make(map[string]net.Conn),
 		shutdown: make(chan struct{}),
-	}
+	}, nil
 }
 
 // Start starts the Kafka server
@@ -61,13 +100,30 @@ func (s *Server) Start() error {
 	s.wg.Add(1)
 	go s.acceptConnections()
 
+	// Compact the logs of compacted topics until shutdown
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		interval := time.Duration(s.config.Log.CompactionIntervalMs) * time.Millisecond
+		s.logs.RunCompactor(interval, s.handler.CompactionPolicy, s.shutdown)
+	}()
+
+	// Run the log cleaner until shutdown
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		interval := time.Duration(s.config.Log.RetentionCheckIntervalMs) * time.Millisecond
+		s.logs.RunCleaner(interval, s.handler.RetentionPolicy, s.shutdown)
+	}()
+
 	return nil
 }
 
 // Stop stops the Kafka server
 func (s *Server) Stop() error {
-	// Signal the shutdown
+	// Signal the shutdown and release requests parked in the handler
 	close(s.shutdown)
+	s.handler.Close()
 
 	// Close the listener
 	if s.listener != nil {
@@ -88,6 +144,14 @@ func (s *Server) Stop() error {
 	// Wait for all goroutines to finish
 	s.wg.Wait()
 
+	// Close the partition logs once nothing can write to them
+	if err := s.logs.Close(); err != nil {
+		s.logger.Error("Error closing partition logs: %s", err.Error())
+	}
+	if err := s.image.Close(); err != nil {
+		s.logger.Error("Error closing metadata log: %s", err.Error())
+	}
+
 	s.logger.Info("Kafka server stopped")
 	return nil
 }
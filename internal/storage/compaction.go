@@ -0,0 +1,298 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+// cleanedFileSuffix is appended to the path of the .log file a segment is
+// compacted into, until it replaces the segment's log. Files left behind
+// by a compaction that did not finish are deleted when the log is opened.
+const cleanedFileSuffix = ".cleaned"
+
+// CompactionPolicy says whether a partition's log is compacted
+type CompactionPolicy struct {
+	// Compact is set for topics whose logs keep only the last record of
+	// each key (cleanup.policy=compact)
+	Compact bool
+	// DeleteRetentionMs is how long compaction keeps a tombstone after its
+	// batch was written (delete.retention.ms)
+	DeleteRetentionMs int64
+}
+
+// CompactionFunc returns the compaction policy of a topic's logs
+type CompactionFunc func(topic string) CompactionPolicy
+
+// RunCompactor compacts the logs of compacted topics every interval, until
+// shutdown is closed
+func (m *Manager) RunCompactor(interval time.Duration, compaction CompactionFunc, shutdown <-chan struct{}) {
+	// A ticker needs a positive interval; without one the default applies
+	if interval <= 0 {
+		interval = time.Duration(DefaultConfig().CompactionIntervalMs) * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-ticker.C:
+			m.Compact(compaction, time.Now().UnixMilli())
+		}
+	}
+}
+
+// Compact compacts every log whose policy asks for it at time now.
+// Failures are logged and leave the log for the next run.
+func (m *Manager) Compact(compaction CompactionFunc, now int64) {
+	for _, log := range m.Logs() {
+		policy := compaction(log.topic)
+		if !policy.Compact {
+			continue
+		}
+		if err := m.compact(log, policy, now); err != nil {
+			m.logger.Error("Failed to compact %s-%d: %s", log.topic, log.partition, err.Error())
+		}
+	}
+}
+
+// compact compacts one log. Holding the manager's lock keeps the log from
+// being deleted or closed meanwhile; a log that already was is skipped.
+func (m *Manager) compact(log *Log, policy CompactionPolicy, now int64) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.logs[TopicPartition{log.topic, log.partition}] != log {
+		return nil
+	}
+	return log.Compact(policy, now)
+}
+
+// compaction is what a run of Compact learns from the segments it cleans
+// before rewriting them
+type compaction struct {
+	// latest is the offset of the last record of each key, or -1 if that
+	// record is a tombstone past its delete retention, which goes too
+	latest map[string]int64
+	// aborted are the aborted transactions of the whole log, whose records
+	// are dropped
+	aborted []abortedTxn
+}
+
+// isAborted reports whether b holds records of an aborted transaction
+func (c *compaction) isAborted(b *record.Batch) bool {
+	if !b.IsTransactional() || b.IsControl() {
+		return false
+	}
+	for _, txn := range c.aborted {
+		if txn.producerID == b.ProducerID && txn.firstOffset <= b.BaseOffset && b.BaseOffset < txn.lastOffset {
+			return true
+		}
+	}
+	return false
+}
+
+// keep reports whether a record of batch b is the one compaction keeps for
+// its key. Records without a key have nothing to replace them and are kept.
+func (c *compaction) keep(b *record.Batch, r *record.Record) bool {
+	return r.Key == nil || c.latest[string(r.Key)] == b.BaseOffset+int64(r.OffsetDelta)
+}
+
+// clean returns what compaction keeps of a batch: the batch itself if it
+// loses nothing, a copy holding the records kept, or nil if none is
+func (c *compaction) clean(b *record.Batch) (*record.Batch, error) {
+	if c.isAborted(b) {
+		return nil, nil
+	}
+	// Control batches, such as transaction markers, are kept whole
+	if b.IsControl() {
+		return b, nil
+	}
+	records, err := b.Records()
+	if err != nil {
+		return nil, err
+	}
+	kept := records[:0]
+	for i := range records {
+		if c.keep(b, &records[i]) {
+			kept = append(kept, records[i])
+		}
+	}
+	switch len(kept) {
+	case len(records):
+		return b, nil
+	case 0:
+		return nil, nil
+	}
+	return b.WithRecords(kept)
+}
+
+// Compact rewrites the sealed segments of the log below its last stable
+// offset so that they keep only the last record of each key, as topics
+// with cleanup.policy=compact are cleaned. Tombstones, records with a null
+// value, are dropped too once their batch is older than DeleteRetentionMs
+// at time now, and so are the records of aborted transactions. Records
+// keep their offsets, and the log start offset stays where it is.
+func (l *Log) Compact(policy CompactionPolicy, now int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Records of the active segment may still be replaced before it is
+	// sealed, and those of open transactions may yet be aborted
+	active := l.activeSegment()
+	end := min(active.baseOffset, l.producers.lastStableOffset(active.nextOffset))
+	if end == l.compactedEnd {
+		return nil
+	}
+	var sealed []*segment
+	for _, seg := range l.segments[:len(l.segments)-1] {
+		if seg.nextOffset > end {
+			break
+		}
+		sealed = append(sealed, seg)
+	}
+	if len(sealed) == 0 {
+		return nil
+	}
+
+	c := &compaction{latest: make(map[string]int64)}
+	for _, seg := range l.segments {
+		c.aborted = append(c.aborted, seg.txnIndex.entries...)
+	}
+	// keyed counts the keyed records of each sealed segment, and dirty
+	// marks those to rewrite, here the ones holding aborted records
+	keyed := make([]int, len(sealed))
+	dirty := make([]bool, len(sealed))
+	tombstones := false
+	for i, seg := range sealed {
+		err := seg.forEachBatch(func(b *record.Batch) error {
+			if c.isAborted(b) {
+				dirty[i] = true
+				return nil
+			}
+			if b.IsControl() {
+				return nil
+			}
+			records, err := b.Records()
+			if err != nil {
+				return err
+			}
+			expired := now-b.MaxTimestamp > policy.DeleteRetentionMs
+			for _, r := range records {
+				if r.Key == nil {
+					continue
+				}
+				keyed[i]++
+				offset := b.BaseOffset + int64(r.OffsetDelta)
+				if r.Value == nil {
+					if expired {
+						offset = -1
+					} else {
+						tombstones = true
+					}
+				}
+				c.latest[string(r.Key)] = offset
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read segment %d: %w", seg.baseOffset, err)
+		}
+	}
+
+	// A segment keeps the keys whose last record it holds; those losing any
+	// other record are rewritten
+	kept := make([]int, len(sealed))
+	for _, offset := range c.latest {
+		if offset < 0 {
+			continue
+		}
+		i := sort.Search(len(sealed), func(i int) bool { return sealed[i].baseOffset > offset }) - 1
+		kept[i]++
+	}
+	for i := range sealed {
+		dirty[i] = dirty[i] || kept[i] < keyed[i]
+	}
+
+	segments := make([]*segment, 0, len(l.segments))
+	for i, seg := range l.segments {
+		if i >= len(sealed) || !dirty[i] {
+			segments = append(segments, seg)
+			continue
+		}
+		cleaned, err := l.cleanSegment(seg, c)
+		if err != nil {
+			l.segments = append(segments, l.segments[i:]...)
+			return err
+		}
+
+		// Segments left empty are deleted, except the first, which holds
+		// the log start offset
+		if cleaned.size == 0 && len(segments) > 0 {
+			l.logger.Debug("Deleting segment %d of %s-%d, emptied by compaction", cleaned.baseOffset, l.topic, l.partition)
+			if err := cleaned.remove(); err != nil {
+				l.segments = append(segments, l.segments[i+1:]...)
+				return err
+			}
+			continue
+		}
+		segments = append(segments, cleaned)
+	}
+	l.segments = segments
+
+	// Until more of the log is sealed, running again changes nothing,
+	// unless tombstones kept for now are due to go
+	if !tombstones {
+		l.compactedEnd = end
+	}
+	return nil
+}
+
+// cleanSegment rewrites a segment with what compaction keeps of its
+// batches and returns it reopened. The cleaned log is written aside and
+// renamed over the segment's, which replaces it at once; the indexes are
+// rebuilt from it.
+func (l *Log) cleanSegment(seg *segment, c *compaction) (*segment, error) {
+	path := segmentPath(l.dir, seg.baseOffset, logFileSuffix)
+	f, err := os.OpenFile(path+cleanedFileSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cleaned segment: %w", err)
+	}
+	err = seg.forEachBatch(func(b *record.Batch) error {
+		b, err := c.clean(b)
+		if err != nil || b == nil {
+			return err
+		}
+		_, err = f.Write(b.Data)
+		return err
+	})
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(path+cleanedFileSuffix, path)
+	}
+	if err != nil {
+		os.Remove(path + cleanedFileSuffix)
+		return nil, fmt.Errorf("failed to compact segment %d: %w", seg.baseOffset, err)
+	}
+
+	seg.close()
+	cleaned, _, err := openSegment(l.dir, seg.baseOffset, l.config.IndexIntervalBytes, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := cleaned.seal(); err != nil {
+		cleaned.close()
+		return nil, err
+	}
+	return cleaned, nil
+}
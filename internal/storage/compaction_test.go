@@ -0,0 +1,386 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+// Batch timestamps of the compaction tests, which compact at compactNow
+// with a delete retention of compactDeleteRetentionMs: tombstones written
+// at oldTimestamp are past it, those at recentTimestamp are not
+const (
+	compactNow               int64 = 100000
+	compactDeleteRetentionMs int64 = 1000
+	oldTimestamp             int64 = 1000
+	recentTimestamp          int64 = 99500
+)
+
+// testRecord parses "key=value" into a record, "key" into a tombstone of
+// key and "=value" into a record without a key
+func testRecord(s string) record.Record {
+	key, value, ok := strings.Cut(s, "=")
+	var r record.Record
+	if key != "" || !ok {
+		r.Key = []byte(key)
+	}
+	if ok {
+		r.Value = []byte(value)
+	}
+	return r
+}
+
+// keyedBatch builds a batch at timestamp of the records testRecord parses,
+// without a producer
+func keyedBatch(timestamp int64, records ...string) *record.Batch {
+	return txnBatch(record.NoProducerID, record.NoSequence, timestamp, records...)
+}
+
+// txnBatch builds a batch at timestamp of the records testRecord parses,
+// in a transaction of producerID unless it is record.NoProducerID
+func txnBatch(producerID int64, sequence int32, timestamp int64, records ...string) *record.Batch {
+	header := record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		BaseTimestamp:        timestamp,
+		MaxTimestamp:         timestamp,
+		ProducerID:           producerID,
+		ProducerEpoch:        record.NoProducerEpoch,
+		BaseSequence:         sequence,
+	}
+	if producerID != record.NoProducerID {
+		header.ProducerEpoch = 0
+		header.Attributes = record.TransactionalAttribute
+	}
+	recs := make([]record.Record, len(records))
+	for i, s := range records {
+		recs[i] = testRecord(s)
+	}
+	return record.EncodeBatch(header, recs)
+}
+
+// rollLog rolls l to a new active segment
+func rollLog(t *testing.T, l *Log) {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.roll(); err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+}
+
+// readRecords returns every record of l as "offset:key=value", written
+// like testRecord parses them, and every transaction marker as
+// "offset:abort" or "offset:commit"
+func readRecords(t *testing.T, l *Log) []string {
+	t.Helper()
+	var got []string
+	for offset := l.LogStartOffset(); ; {
+		data, err := l.Read(offset, 1<<20, true)
+		if err != nil {
+			t.Fatalf("Read(%d): %v", offset, err)
+		}
+		if data == nil {
+			return got
+		}
+		for len(data) > 0 {
+			b, err := record.NextBatch(data)
+			if err != nil {
+				t.Fatalf("batch at offset %d: %v", offset, err)
+			}
+			data, offset = data[b.Size():], b.NextOffset()
+			if b.IsControl() {
+				controlType, err := b.ControlType()
+				if err != nil {
+					t.Fatal(err)
+				}
+				marker := "abort"
+				if controlType == record.ControlCommit {
+					marker = "commit"
+				}
+				got = append(got, fmt.Sprintf("%d:%s", b.BaseOffset, marker))
+				continue
+			}
+			records, err := b.Records()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range records {
+				s := fmt.Sprintf("%d:%s", b.BaseOffset+int64(r.OffsetDelta), r.Key)
+				if r.Value != nil {
+					s += "=" + string(r.Value)
+				}
+				got = append(got, s)
+			}
+		}
+	}
+}
+
+// segmentBases returns the base offsets of l's segments
+func segmentBases(l *Log) []int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var bases []int64
+	for _, seg := range l.segments {
+		bases = append(bases, seg.baseOffset)
+	}
+	return bases
+}
+
+// equalStrings reports whether a and b hold the same strings in order
+func equalStrings(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+// testSegment is a segment of one batch the compaction tests write
+type testSegment struct {
+	timestamp int64
+	records   []string
+}
+
+// seg describes a segment of one batch at timestamp of the records
+// testRecord parses
+func seg(timestamp int64, records ...string) testSegment {
+	return testSegment{timestamp, records}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name string
+		// segments are written in order; the last one stays active, and is
+		// left empty if it has no records
+		segments     []testSegment
+		codec        record.Compression
+		want         []string
+		wantSegments []int64
+	}{
+		{
+			name: "latest value per key",
+			segments: []testSegment{
+				seg(oldTimestamp, "a=1", "b=1"),
+				seg(oldTimestamp, "a=2"),
+				seg(oldTimestamp, "b=2"),
+				seg(oldTimestamp, "a=3"),
+			},
+			want: []string{"2:a=2", "3:b=2", "4:a=3"},
+			// The first segment is kept empty, holding the log start offset
+			wantSegments: []int64{0, 2, 3, 4},
+		},
+		{
+			name: "partial batch",
+			segments: []testSegment{
+				seg(oldTimestamp, "a=1", "b=1", "c=1"),
+				seg(oldTimestamp, "b=2"),
+				seg(oldTimestamp),
+			},
+			want:         []string{"0:a=1", "2:c=1", "3:b=2"},
+			wantSegments: []int64{0, 3, 4},
+		},
+		{
+			name: "partial compressed batch",
+			segments: []testSegment{
+				seg(oldTimestamp, "a=1", "b=1", "c=1"),
+				seg(oldTimestamp, "a=2", "c=2"),
+				seg(oldTimestamp),
+			},
+			codec:        record.CompressionZstd,
+			want:         []string{"1:b=1", "3:a=2", "4:c=2"},
+			wantSegments: []int64{0, 3, 5},
+		},
+		{
+			name: "emptied segment deleted",
+			segments: []testSegment{
+				seg(oldTimestamp, "x=1"),
+				seg(oldTimestamp, "a=1"),
+				seg(oldTimestamp, "a=2"),
+				seg(oldTimestamp),
+			},
+			want:         []string{"0:x=1", "2:a=2"},
+			wantSegments: []int64{0, 2, 3},
+		},
+		{
+			name: "tombstones past delete retention dropped",
+			segments: []testSegment{
+				seg(oldTimestamp, "a=1", "b=1"),
+				seg(oldTimestamp, "a"),
+				seg(recentTimestamp, "b"),
+				seg(oldTimestamp),
+			},
+			want:         []string{"3:b"},
+			wantSegments: []int64{0, 3, 4},
+		},
+		{
+			name: "records without key kept",
+			segments: []testSegment{
+				seg(oldTimestamp, "=x", "a=1", "=y"),
+				seg(oldTimestamp, "a=2"),
+				seg(oldTimestamp),
+			},
+			want:         []string{"0:=x", "2:=y", "3:a=2"},
+			wantSegments: []int64{0, 3, 4},
+		},
+		{
+			name: "active segment left alone",
+			segments: []testSegment{
+				seg(oldTimestamp, "a=1", "a"),
+				seg(oldTimestamp, "a=2", "a=3", "b"),
+			},
+			want:         []string{"2:a=2", "3:a=3", "4:b"},
+			wantSegments: []int64{0, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := openTestLog(t, dir, testConfig())
+			for i, s := range tt.segments {
+				if i > 0 {
+					rollLog(t, l)
+				}
+				if len(s.records) == 0 {
+					continue
+				}
+				b := keyedBatch(s.timestamp, s.records...)
+				if tt.codec != record.CompressionNone {
+					var err error
+					if b, err = b.Recompress(tt.codec); err != nil {
+						t.Fatal(err)
+					}
+				}
+				appendBatch(t, l, b)
+			}
+			end := l.LogEndOffset()
+
+			policy := CompactionPolicy{Compact: true, DeleteRetentionMs: compactDeleteRetentionMs}
+			if err := l.Compact(policy, compactNow); err != nil {
+				t.Fatalf("Compact: %v", err)
+			}
+			check := func(l *Log) {
+				t.Helper()
+				if got := readRecords(t, l); !equalStrings(got, tt.want) {
+					t.Errorf("records = %v, want %v", got, tt.want)
+				}
+				if got := segmentBases(l); !equalOffsets(got, tt.wantSegments) {
+					t.Errorf("segments = %v, want %v", got, tt.wantSegments)
+				}
+				if got := l.LogStartOffset(); got != 0 {
+					t.Errorf("log start offset = %d, want 0", got)
+				}
+				if got := l.LogEndOffset(); got != end {
+					t.Errorf("log end offset = %d, want %d", got, end)
+				}
+			}
+			check(l)
+
+			// Compacting again changes nothing
+			if err := l.Compact(policy, compactNow); err != nil {
+				t.Fatalf("second Compact: %v", err)
+			}
+			check(l)
+
+			// The rewritten segments load with their rebuilt indexes
+			if err := l.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			l = openTestLog(t, dir, testConfig())
+			defer l.Close()
+			check(l)
+		})
+	}
+}
+
+func TestCompactTransactions(t *testing.T) {
+	l := openTestLog(t, t.TempDir(), testConfig())
+	defer l.Close()
+	policy := CompactionPolicy{Compact: true, DeleteRetentionMs: compactDeleteRetentionMs}
+	marker := func(producerID int64, commit bool) *record.Batch {
+		return record.EncodeEndTxnMarker(producerID, 0, 0, commit, oldTimestamp)
+	}
+
+	// Producer 1 aborts its transaction, and producer 2's is still open
+	// when the segments are compacted
+	appendBatch(t, l, keyedBatch(oldTimestamp, "a=1"))
+	appendBatch(t, l, txnBatch(1, 0, oldTimestamp, "a=aborted"))
+	appendBatch(t, l, marker(1, false))
+	rollLog(t, l)
+	appendBatch(t, l, txnBatch(2, 0, oldTimestamp, "a=open"))
+	rollLog(t, l)
+	if err := l.Compact(policy, compactNow); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	// The aborted record goes without taking a=1 with it, and the open
+	// transaction, past the last stable offset, is not compacted
+	want := []string{"0:a=1", "2:abort", "3:a=open"}
+	if got := readRecords(t, l); !equalStrings(got, want) {
+		t.Errorf("records with an open transaction = %v, want %v", got, want)
+	}
+
+	// Once committed, the transaction's record replaces a=1
+	appendBatch(t, l, marker(2, true))
+	rollLog(t, l)
+	if err := l.Compact(policy, compactNow); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	want = []string{"2:abort", "3:a=open", "4:commit"}
+	if got := readRecords(t, l); !equalStrings(got, want) {
+		t.Errorf("records after the commit = %v, want %v", got, want)
+	}
+}
+
+func TestCompactLeftoverIsDeleted(t *testing.T) {
+	dir := t.TempDir()
+	l := openTestLog(t, dir, testConfig())
+	appendBatch(t, l, keyedBatch(oldTimestamp, "a=1"))
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// A compaction interrupted before its rename leaves the segment as it was
+	leftover := segmentPath(dir, 0, logFileSuffix) + cleanedFileSuffix
+	if err := os.WriteFile(leftover, []byte("partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l = openTestLog(t, dir, testConfig())
+	defer l.Close()
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Errorf("%s still present after OpenLog: %v", leftover, err)
+	}
+	if got, want := readRecords(t, l), []string{"0:a=1"}; !equalStrings(got, want) {
+		t.Errorf("records = %v, want %v", got, want)
+	}
+}
+
+func TestManagerCompact(t *testing.T) {
+	m := openTestManager(t, t.TempDir(), testConfig())
+	defer m.Close()
+	for _, topic := range []string{"compacted", "kept"} {
+		l, err := m.GetOrCreate(topic, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		appendBatch(t, l, keyedBatch(oldTimestamp, "a=1"))
+		appendBatch(t, l, keyedBatch(oldTimestamp, "a=2"))
+		rollLog(t, l)
+	}
+
+	m.Compact(func(topic string) CompactionPolicy {
+		return CompactionPolicy{Compact: topic == "compacted", DeleteRetentionMs: compactDeleteRetentionMs}
+	}, compactNow)
+
+	tests := []struct {
+		topic string
+		want  []string
+	}{
+		{"compacted", []string{"1:a=2"}},
+		{"kept", []string{"0:a=1", "1:a=2"}},
+	}
+	for _, tt := range tests {
+		l, _ := m.Get(tt.topic, 0)
+		if got := readRecords(t, l); !equalStrings(got, tt.want) {
+			t.Errorf("%s records = %v, want %v", tt.topic, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Index entry sizes, as in Kafka's .index and .timeindex files
+const (
+	offsetIndexEntrySize = 8  // relative offset (int32), position (int32)
+	timeIndexEntrySize   = 12 // timestamp (int64), relative offset (int32)
+)
+
+// offsetIndexEntry maps an offset to the file position of the batch holding it
+type offsetIndexEntry struct {
+	offset   int64
+	position int64
+}
+
+// timeIndexEntry maps a timestamp to the first offset at or after which
+// every batch has a max timestamp no smaller than it
+type timeIndexEntry struct {
+	timestamp int64
+	offset    int64
+}
+
+// offsetIndex is a segment's sparse offset index. Entries are kept in
+// memory and appended to the .index file as they are added.
+type offsetIndex struct {
+	file       *os.File
+	baseOffset int64
+	entries    []offsetIndexEntry
+}
+
+// openOffsetIndex opens (creating if needed) the offset index at path
+func openOffsetIndex(path string, baseOffset int64) (*offsetIndex, error) {
+	file, data, err := openIndexFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &offsetIndex{file: file, baseOffset: baseOffset}
+	for i := 0; i+offsetIndexEntrySize <= len(data); i += offsetIndexEntrySize {
+		idx.entries = append(idx.entries, offsetIndexEntry{
+			offset:   baseOffset + int64(binary.BigEndian.Uint32(data[i:])),
+			position: int64(binary.BigEndian.Uint32(data[i+4:])),
+		})
+	}
+	return idx, nil
+}
+
+// sane reports whether the index entries are strictly increasing and lie
+// within a log file of the given size
+func (idx *offsetIndex) sane(logSize int64) bool {
+	for i, e := range idx.entries {
+		if e.position >= logSize {
+			return false
+		}
+		if i > 0 && (e.offset <= idx.entries[i-1].offset || e.position <= idx.entries[i-1].position) {
+			return false
+		}
+	}
+	return true
+}
+
+// append adds an entry for offset at position
+func (idx *offsetIndex) append(offset, position int64) error {
+	var buf [offsetIndexEntrySize]byte
+	binary.BigEndian.PutUint32(buf[0:], uint32(offset-idx.baseOffset))
+	binary.BigEndian.PutUint32(buf[4:], uint32(position))
+	if _, err := idx.file.WriteAt(buf[:], int64(len(idx.entries))*offsetIndexEntrySize); err != nil {
+		return fmt.Errorf("failed to write offset index: %w", err)
+	}
+	idx.entries = append(idx.entries, offsetIndexEntry{offset: offset, position: position})
+	return nil
+}
+
+// lookup returns the position of the last indexed batch at or before
+// offset, or zero if there is none
+func (idx *offsetIndex) lookup(offset int64) int64 {
+	i := sort.Search(len(idx.entries), func(i int) bool { return idx.entries[i].offset > offset })
+	if i == 0 {
+		return 0
+	}
+	return idx.entries[i-1].position
+}
+
+// last returns the last entry of the index, if any
+func (idx *offsetIndex) last() (offsetIndexEntry, bool) {
+	if len(idx.entries) == 0 {
+		return offsetIndexEntry{}, false
+	}
+	return idx.entries[len(idx.entries)-1], true
+}
+
+// reset drops every entry
+func (idx *offsetIndex) reset() error {
+	idx.entries = nil
+	return idx.file.Truncate(0)
+}
+
+// truncateTo drops the entries at or past position
+func (idx *offsetIndex) truncateTo(position int64) error {
+	n := sort.Search(len(idx.entries), func(i int) bool { return idx.entries[i].position >= position })
+	idx.entries = idx.entries[:n]
+	return idx.file.Truncate(int64(n) * offsetIndexEntrySize)
+}
+
+// timeIndex is a segment's sparse time index
+type timeIndex struct {
+	file       *os.File
+	baseOffset int64
+	entries    []timeIndexEntry
+}
+
+// openTimeIndex opens (creating if needed) the time index at path
+func openTimeIndex(path string, baseOffset int64) (*timeIndex, error) {
+	file, data, err := openIndexFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &timeIndex{file: file, baseOffset: baseOffset}
+	for i := 0; i+timeIndexEntrySize <= len(data); i += timeIndexEntrySize {
+		idx.entries = append(idx.entries, timeIndexEntry{
+			timestamp: int64(binary.BigEndian.Uint64(data[i:])),
+			offset:    baseOffset + int64(binary.BigEndian.Uint32(data[i+8:])),
+		})
+	}
+	return idx, nil
+}
+
+// sane reports whether the index timestamps and offsets never decrease
+// and the offsets lie before nextOffset
+func (idx *timeIndex) sane(nextOffset int64) bool {
+	for i, e := range idx.entries {
+		if e.offset >= nextOffset {
+			return false
+		}
+		if i > 0 && (e.timestamp < idx.entries[i-1].timestamp || e.offset < idx.entries[i-1].offset) {
+			return false
+		}
+	}
+	return true
+}
+
+// maybeAppend adds an entry unless it would not advance the largest
+// indexed timestamp
+func (idx *timeIndex) maybeAppend(timestamp, offset int64) error {
+	if last, ok := idx.last(); ok && timestamp <= last.timestamp {
+		return nil
+	}
+
+	var buf [timeIndexEntrySize]byte
+	binary.BigEndian.PutUint64(buf[0:], uint64(timestamp))
+	binary.BigEndian.PutUint32(buf[8:], uint32(offset-idx.baseOffset))
+	if _, err := idx.file.WriteAt(buf[:], int64(len(idx.entries))*timeIndexEntrySize); err != nil {
+		return fmt.Errorf("failed to write time index: %w", err)
+	}
+	idx.entries = append(idx.entries, timeIndexEntry{timestamp: timestamp, offset: offset})
+	return nil
+}
+
+// lookup returns the offset to start scanning from for the first batch
+// with a timestamp at or after timestamp
+func (idx *timeIndex) lookup(timestamp int64) int64 {
+	i := sort.Search(len(idx.entries), func(i int) bool { return idx.entries[i].timestamp >= timestamp })
+	if i == 0 {
+		return idx.baseOffset
+	}
+	return idx.entries[i-1].offset
+}
+
+// last returns the last entry of the index, if any
+func (idx *timeIndex) last() (timeIndexEntry, bool) {
+	if len(idx.entries) == 0 {
+		return timeIndexEntry{}, false
+	}
+	return idx.entries[len(idx.entries)-1], true
+}
+
+// reset drops every entry
+func (idx *timeIndex) reset() error {
+	idx.entries = nil
+	return idx.file.Truncate(0)
+}
+
+// truncateTo drops the entries at or past offset
+func (idx *timeIndex) truncateTo(offset int64) error {
+	n := sort.Search(len(idx.entries), func(i int) bool { return idx.entries[i].offset >= offset })
+	idx.entries = idx.entries[:n]
+	return idx.file.Truncate(int64(n) * timeIndexEntrySize)
+}
+
+// openIndexFile opens an index file read-write and returns its contents
+func openIndexFile(path string) (*os.File, []byte, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open index %s: %w", path, err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to read index %s: %w", path, err)
+	}
+	return file, data, nil
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOffsetIndexLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	idx, err := openOffsetIndex(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []offsetIndexEntry{{110, 500}, {120, 1000}, {135, 1500}} {
+		if err := idx.append(e.offset, e.position); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx.file.Close()
+
+	// Entries survive a reopen, relative to the base offset
+	if idx, err = openOffsetIndex(path, 100); err != nil {
+		t.Fatal(err)
+	}
+	defer idx.file.Close()
+
+	tests := []struct {
+		offset int64
+		want   int64
+	}{
+		{100, 0},
+		{109, 0},
+		{110, 500},
+		{119, 500},
+		{120, 1000},
+		{134, 1000},
+		{135, 1500},
+		{1 << 40, 1500},
+	}
+	for _, tt := range tests {
+		if got := idx.lookup(tt.offset); got != tt.want {
+			t.Errorf("lookup(%d) = %d, want %d", tt.offset, got, tt.want)
+		}
+	}
+
+	if !idx.sane(1501) {
+		t.Errorf("index is not sane for a log of 1501 bytes")
+	}
+	if idx.sane(1500) {
+		t.Errorf("index is sane for a log of 1500 bytes, shorter than its last entry")
+	}
+
+	if err := idx.truncateTo(1000); err != nil {
+		t.Fatal(err)
+	}
+	if got := idx.lookup(200); got != 500 {
+		t.Errorf("lookup(200) after truncating to position 1000 = %d, want 500", got)
+	}
+}
+
+func TestTimeIndexLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeindex")
+	idx, err := openTimeIndex(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second entry at 2000 does not advance the largest timestamp and
+	// is not added
+	for _, e := range []timeIndexEntry{{1000, 105}, {2000, 110}, {2000, 115}, {3000, 120}} {
+		if err := idx.maybeAppend(e.timestamp, e.offset); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx.file.Close()
+
+	if idx, err = openTimeIndex(path, 100); err != nil {
+		t.Fatal(err)
+	}
+	defer idx.file.Close()
+	if len(idx.entries) != 3 {
+		t.Fatalf("index has %d entries, want 3", len(idx.entries))
+	}
+
+	tests := []struct {
+		timestamp int64
+		want      int64
+	}{
+		{0, 100},
+		{1000, 100},
+		{1001, 105},
+		{2000, 105},
+		{2500, 110},
+		{3000, 110},
+		{3001, 120},
+	}
+	for _, tt := range tests {
+		if got := idx.lookup(tt.timestamp); got != tt.want {
+			t.Errorf("lookup(%d) = %d, want %d", tt.timestamp, got, tt.want)
+		}
+	}
+
+	if !idx.sane(121) {
+		t.Errorf("index is not sane with next offset 121")
+	}
+	if idx.sane(120) {
+		t.Errorf("index is sane with next offset 120, at its last entry")
+	}
+}
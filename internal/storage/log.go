@@ -0,0 +1,573 @@
+// Package storage implements the broker's on-disk partition logs: one
+// directory per partition holding Kafka-format .log segments with sparse
+// .index and .timeindex files and .txnindex files of aborted transactions,
+// and .snapshot files of the producer state
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// ErrOffsetOutOfRange is returned when reading an offset outside the log
+var ErrOffsetOutOfRange = errors.New("offset out of range")
+
+// Config holds the settings of partition logs
+type Config struct {
+	// SegmentBytes is the size at which a segment is rolled (log.segment.bytes)
+	SegmentBytes int64
+	// SegmentMs is the age at which a segment is rolled (log.roll.ms)
+	SegmentMs int64
+	// IndexIntervalBytes is how many bytes are appended between index
+	// entries (log.index.interval.bytes)
+	IndexIntervalBytes int
+	// CompactionIntervalMs is how often the logs of compacted topics are
+	// compacted (log.cleaner.backoff.ms)
+	CompactionIntervalMs int64
+	// RetentionCheckIntervalMs is how often the log cleaner deletes the
+	// segments retention no longer keeps (log.retention.check.interval.ms)
+	RetentionCheckIntervalMs int64
+}
+
+// DefaultConfig returns Kafka's default log settings
+func DefaultConfig() Config {
+	return Config{
+		SegmentBytes:             1 << 30,
+		SegmentMs:                7 * 24 * 60 * 60 * 1000,
+		IndexIntervalBytes:       4096,
+		CompactionIntervalMs:     15 * 1000,
+		RetentionCheckIntervalMs: 5 * 60 * 1000,
+	}
+}
+
+// SegmentPolicy says when a partition's active segment is rolled. Zero
+// fields leave the setting to the log's Config.
+type SegmentPolicy struct {
+	// SegmentBytes is the size at which a segment is rolled (segment.bytes)
+	SegmentBytes int64
+	// SegmentMs is the age at which a segment is rolled (segment.ms)
+	SegmentMs int64
+}
+
+// SegmentFunc returns the segment policy of a topic's logs
+type SegmentFunc func(topic string) SegmentPolicy
+
+// AppendInfo describes batches appended to a log
+type AppendInfo struct {
+	FirstOffset  int64
+	LastOffset   int64
+	MaxTimestamp int64
+}
+
+// TimestampOffset is a record's timestamp and offset, as found by a
+// timestamp search
+type TimestampOffset struct {
+	Timestamp int64
+	Offset    int64
+}
+
+// Log is the log of a single partition. It is safe for concurrent use.
+type Log struct {
+	mu        sync.RWMutex
+	dir       string
+	topic     string
+	partition int32
+	config    Config
+	logger    *logger.Logger
+	segments  []*segment // sorted by base offset; the last one is active
+	producers *producerState
+
+	// compactedEnd is the offset the last compaction cleaned up to, if it
+	// kept no tombstone to drop later
+	compactedEnd int64
+
+	// segmentPolicy overrides the rolling settings of config per topic
+	// when set
+	segmentPolicy SegmentFunc
+}
+
+// OpenLog opens a log outside of a Manager, recovering any torn tail. It
+// is used for logs with their own lifecycle such as the metadata log.
+func OpenLog(dir, topic string, partition int32, config Config, logger *logger.Logger) (*Log, error) {
+	return openLog(dir, topic, partition, config, true, logger)
+}
+
+// openLog opens the partition log in dir, creating the first segment of a
+// new log. With recover set every segment is checked and repaired.
+func openLog(dir, topic string, partition int32, config Config, recover bool, logger *logger.Logger) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+	var baseOffsets []int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, cleanedFileSuffix) {
+			logger.Info("Deleting %s of %s-%d, left by an unfinished compaction", name, topic, partition)
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		if !strings.HasSuffix(name, logFileSuffix) {
+			continue
+		}
+		base, err := strconv.ParseInt(strings.TrimSuffix(name, logFileSuffix), 10, 64)
+		if err != nil {
+			logger.Error("Ignoring unexpected file %s in %s", name, dir)
+			continue
+		}
+		baseOffsets = append(baseOffsets, base)
+	}
+	sort.Slice(baseOffsets, func(i, j int) bool { return baseOffsets[i] < baseOffsets[j] })
+
+	l := &Log{dir: dir, topic: topic, partition: partition, config: config, logger: logger, producers: newProducerState(dir)}
+	for i, base := range baseOffsets {
+		seg, truncated, err := openSegment(dir, base, config.IndexIntervalBytes, recover)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		l.segments = append(l.segments, seg)
+
+		// Once a segment is torn, everything after it is unusable
+		if truncated > 0 {
+			logger.Info("Recovered %s-%d: truncated %d bytes from segment %d", topic, partition, truncated, base)
+			for _, later := range baseOffsets[i+1:] {
+				logger.Info("Recovered %s-%d: deleting segment %d past the truncation point", topic, partition, later)
+				(&segment{dir: dir, baseOffset: later}).remove()
+			}
+			break
+		}
+	}
+
+	if len(l.segments) == 0 {
+		seg, err := createSegment(dir, 0, config.IndexIntervalBytes)
+		if err != nil {
+			return nil, err
+		}
+		l.segments = append(l.segments, seg)
+	}
+	if err := l.loadProducerState(); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// loadProducerState rebuilds the producer state from the latest valid
+// snapshot, replaying the batches appended after it. Snapshots past the
+// log end offset, left behind by a truncated log, are deleted first.
+func (l *Log) loadProducerState() error {
+	end := l.activeSegment().nextOffset
+	if err := l.producers.deleteSnapshots(func(offset int64) bool { return offset <= end }); err != nil {
+		return fmt.Errorf("failed to delete producer snapshots: %w", err)
+	}
+	offsets, err := l.producers.snapshotOffsets()
+	if err != nil {
+		return err
+	}
+
+	from := l.segments[0].baseOffset
+	for i := len(offsets) - 1; i >= 0; i-- {
+		if err := l.producers.readSnapshot(offsets[i]); err != nil {
+			l.logger.Error("Deleting invalid producer snapshot %d of %s-%d: %s", offsets[i], l.topic, l.partition, err.Error())
+			os.Remove(l.producers.snapshotPath(offsets[i]))
+			continue
+		}
+		from = offsets[i]
+		break
+	}
+
+	// Aborted transactions are indexed again as their markers are
+	// replayed, in case the index lost them
+	for _, seg := range l.segments {
+		if seg.nextOffset <= from {
+			continue
+		}
+		err := seg.scanHeaders(from, func(b *record.Batch) error {
+			txn, err := l.producers.replay(b)
+			if err != nil || txn == nil || !txn.aborted {
+				return err
+			}
+			return seg.txnIndex.append(l.abortedTxn(txn, b.NextOffset()))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay producer state: %w", err)
+		}
+	}
+	return nil
+}
+
+// abortedTxn builds the transaction index entry of an aborted transaction,
+// with the last stable offset once the log reached highWatermark
+func (l *Log) abortedTxn(txn *completedTxn, highWatermark int64) abortedTxn {
+	return abortedTxn{
+		producerID:       txn.producerID,
+		firstOffset:      txn.firstOffset,
+		lastOffset:       txn.lastOffset,
+		lastStableOffset: l.producers.lastStableOffset(highWatermark),
+	}
+}
+
+// Topic returns the topic the log belongs to
+func (l *Log) Topic() string {
+	return l.topic
+}
+
+// Partition returns the partition the log belongs to
+func (l *Log) Partition() int32 {
+	return l.partition
+}
+
+// setSegmentPolicy makes the log roll its segments as policy says for its
+// topic
+func (l *Log) setSegmentPolicy(policy SegmentFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.segmentPolicy = policy
+}
+
+// Dir returns the directory of the log
+func (l *Log) Dir() string {
+	return l.dir
+}
+
+// LogStartOffset returns the first offset in the log
+func (l *Log) LogStartOffset() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.segments[0].baseOffset
+}
+
+// LogEndOffset returns the offset the next appended record will get
+func (l *Log) LogEndOffset() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.activeSegment().nextOffset
+}
+
+// HighWatermark returns the offset up to which records are committed. The
+// broker is the only replica, so every appended record is committed.
+func (l *Log) HighWatermark() int64 {
+	return l.LogEndOffset()
+}
+
+// LastStableOffset returns the offset below which no transaction is
+// still open: the first offset of the oldest open transaction, or the high
+// watermark if there is none
+func (l *Log) LastStableOffset() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.producers.lastStableOffset(l.activeSegment().nextOffset)
+}
+
+// FindOffsetByTimestamp returns the first record whose timestamp is at
+// least timestamp, or false if every record is older
+func (l *Log) FindOffsetByTimestamp(timestamp int64) (TimestampOffset, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	start := l.segments[0].baseOffset
+	for _, seg := range l.segments {
+		found, ok, err := seg.findOffsetByTimestamp(timestamp, start)
+		if err != nil || ok {
+			return found, ok, err
+		}
+	}
+	return TimestampOffset{}, false, nil
+}
+
+// MaxTimestamp returns the first record carrying the largest timestamp in
+// the log, or false if the log is empty
+func (l *Log) MaxTimestamp() (TimestampOffset, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var latest *segment
+	for _, seg := range l.segments {
+		if seg.maxTimestamp >= 0 && (latest == nil || seg.maxTimestamp > latest.maxTimestamp) {
+			latest = seg
+		}
+	}
+	if latest == nil {
+		return TimestampOffset{}, false, nil
+	}
+	return latest.findOffsetByTimestamp(latest.maxTimestamp, l.segments[0].baseOffset)
+}
+
+// Append assigns offsets to one or more record batches and appends them
+// to the log. The base offsets are rewritten in data.
+func (l *Log) Append(data []byte) (AppendInfo, error) {
+	var batches []*record.Batch
+	for rest := data; len(rest) > 0; {
+		b, err := record.ParseBatch(rest)
+		if err != nil {
+			return AppendInfo{}, err
+		}
+		batches = append(batches, b)
+		rest = rest[b.Size():]
+	}
+	if len(batches) == 0 {
+		return AppendInfo{}, fmt.Errorf("no record batches to append")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A producer retrying batches that were already appended gets the
+	// original offsets back. Retried batches cannot be told apart from new
+	// ones sent along with them, so a mix of the two is rejected whole.
+	var dups []batchMetadata
+	for _, b := range batches {
+		if dup, ok := l.producers.findDuplicate(b); ok {
+			dups = append(dups, dup)
+		}
+	}
+	if len(dups) > 0 && len(dups) < len(batches) {
+		return AppendInfo{}, fmt.Errorf("%w: %d of %d batches are duplicates", ErrOutOfOrderSequence, len(dups), len(batches))
+	}
+	if len(dups) > 0 {
+		info := AppendInfo{FirstOffset: dups[0].firstOffset(), LastOffset: dups[0].lastOffset, MaxTimestamp: dups[0].timestamp}
+		for _, dup := range dups[1:] {
+			info.FirstOffset = min(info.FirstOffset, dup.firstOffset())
+			info.LastOffset = max(info.LastOffset, dup.lastOffset)
+			info.MaxTimestamp = max(info.MaxTimestamp, dup.timestamp)
+		}
+		return info, nil
+	}
+
+	info := AppendInfo{FirstOffset: l.activeSegment().nextOffset, MaxTimestamp: -1}
+	next := info.FirstOffset
+	for _, b := range batches {
+		b.SetBaseOffset(next)
+		next = b.NextOffset()
+		if b.MaxTimestamp > info.MaxTimestamp {
+			info.MaxTimestamp = b.MaxTimestamp
+		}
+	}
+	info.LastOffset = next - 1
+
+	updates, completed, err := l.producers.prepare(batches)
+	if err != nil {
+		return AppendInfo{}, err
+	}
+	if err := l.maybeRoll(len(data), info); err != nil {
+		return AppendInfo{}, err
+	}
+	active := l.activeSegment()
+	for _, b := range batches {
+		if err := active.append(b); err != nil {
+			return AppendInfo{}, err
+		}
+	}
+	l.producers.apply(updates)
+	for _, txn := range completed {
+		if !txn.aborted {
+			continue
+		}
+		if err := active.txnIndex.append(l.abortedTxn(txn, active.nextOffset)); err != nil {
+			return AppendInfo{}, err
+		}
+	}
+	return info, nil
+}
+
+// maybeRoll starts a new segment if appending size bytes would overflow
+// the active one, it is older than SegmentMs, or the new offsets no longer
+// fit the index's 32-bit relative offsets
+func (l *Log) maybeRoll(size int, info AppendInfo) error {
+	active := l.activeSegment()
+	if active.size == 0 {
+		return nil
+	}
+
+	var policy SegmentPolicy
+	if l.segmentPolicy != nil {
+		policy = l.segmentPolicy(l.topic)
+	}
+	if policy.SegmentBytes <= 0 {
+		policy.SegmentBytes = l.config.SegmentBytes
+	}
+	if policy.SegmentMs <= 0 {
+		policy.SegmentMs = l.config.SegmentMs
+	}
+	full := active.size+int64(size) > policy.SegmentBytes
+	expired := active.rollingTimestamp >= 0 && info.MaxTimestamp-active.rollingTimestamp > policy.SegmentMs
+	overflow := info.LastOffset-active.baseOffset > math.MaxInt32
+	if !full && !expired && !overflow {
+		return nil
+	}
+	return l.roll()
+}
+
+// roll seals the active segment and starts a new one at the log end
+// offset, snapshotting the producer state there and dropping older
+// snapshots. The lock must be held.
+func (l *Log) roll() error {
+	active := l.activeSegment()
+	if err := active.seal(); err != nil {
+		return err
+	}
+	if err := active.flush(); err != nil {
+		return err
+	}
+	if err := l.producers.takeSnapshot(active.nextOffset); err != nil {
+		return err
+	}
+	if err := l.producers.deleteOldSnapshots(); err != nil {
+		return fmt.Errorf("failed to delete producer snapshots: %w", err)
+	}
+	seg, err := createSegment(l.dir, active.nextOffset, l.config.IndexIntervalBytes)
+	if err != nil {
+		return err
+	}
+	l.segments = append(l.segments, seg)
+	l.logger.Debug("Rolled %s-%d to new segment %d", l.topic, l.partition, seg.baseOffset)
+	return nil
+}
+
+// Read returns whole batches starting with the one holding offset, up to
+// maxBytes in total. If minOneBatch is set the first batch is returned even
+// when it is larger than maxBytes. Reading at the log end offset returns
+// no data; reading outside the log returns ErrOffsetOutOfRange.
+func (l *Log) Read(offset int64, maxBytes int, minOneBatch bool) ([]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.read(offset, l.activeSegment().nextOffset, maxBytes, minOneBatch)
+}
+
+// ReadCommitted reads like Read, but only the batches below the last
+// stable offset, as read_committed consumers do. It also returns the
+// transactions aborted among the batches read, whose records the consumer
+// must drop.
+func (l *Log) ReadCommitted(offset int64, maxBytes int, minOneBatch bool) ([]byte, []AbortedTransaction, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	lso := l.producers.lastStableOffset(l.activeSegment().nextOffset)
+	data, err := l.read(offset, lso, maxBytes, minOneBatch)
+	if err != nil || data == nil {
+		return data, nil, err
+	}
+
+	// The aborted transactions that matter are those with records before
+	// the end of the data read, aborted after the fetch offset. Their
+	// markers all lie in the segments from the one holding offset on.
+	end := offset
+	for rest := data; len(rest) > 0; {
+		b, err := record.ParseHeader(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		end = b.NextOffset()
+		rest = rest[b.Size():]
+	}
+	aborted := []AbortedTransaction{}
+	for _, seg := range l.segments {
+		if seg.nextOffset <= offset {
+			continue
+		}
+		aborted = append(aborted, seg.txnIndex.collect(offset, end)...)
+	}
+	return data, aborted, nil
+}
+
+// read returns whole batches from offset up to maxBytes, stopping before
+// maxOffset. The lock must be held.
+func (l *Log) read(offset, maxOffset int64, maxBytes int, minOneBatch bool) ([]byte, error) {
+	end := l.activeSegment().nextOffset
+	if offset < l.segments[0].baseOffset || offset > end {
+		return nil, ErrOffsetOutOfRange
+	}
+	if offset == end {
+		return nil, nil
+	}
+
+	// Start from the last segment whose base offset is at or before offset
+	i := sort.Search(len(l.segments), func(i int) bool { return l.segments[i].baseOffset > offset }) - 1
+	for ; i < len(l.segments); i++ {
+		data, err := l.segments[i].read(offset, maxOffset, maxBytes, minOneBatch)
+		if err != nil || data != nil {
+			return data, err
+		}
+	}
+	return nil, nil
+}
+
+// TruncateFullyAndStartAt deletes every segment and restarts the log,
+// empty, at offset
+func (l *Log) TruncateFullyAndStartAt(offset int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, seg := range l.segments {
+		if err := seg.remove(); err != nil {
+			return err
+		}
+	}
+	l.producers.clear()
+	if err := l.producers.deleteSnapshots(func(int64) bool { return false }); err != nil {
+		return err
+	}
+	seg, err := createSegment(l.dir, offset, l.config.IndexIntervalBytes)
+	if err != nil {
+		return err
+	}
+	l.segments = []*segment{seg}
+	l.compactedEnd = 0
+	return nil
+}
+
+// Flush syncs the active segment to disk
+func (l *Log) Flush() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.activeSegment().flush()
+}
+
+// Close flushes and closes every segment of the log, snapshotting the
+// producer state at the log end offset so that it loads quickly on restart
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var firstErr error
+	if len(l.segments) > 0 {
+		firstErr = l.producers.takeSnapshot(l.activeSegment().nextOffset)
+	}
+	for i, seg := range l.segments {
+		if i == len(l.segments)-1 {
+			if err := seg.flush(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if err := seg.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// activeSegment returns the segment appends go to
+func (l *Log) activeSegment() *segment {
+	return l.segments[len(l.segments)-1]
+}
@@ -0,0 +1,397 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+var testLogger = logger.New(logger.ERROR)
+
+// testConfig returns log settings that never roll a segment on their own
+func testConfig() Config {
+	config := DefaultConfig()
+	config.IndexIntervalBytes = 0
+	return config
+}
+
+// openTestLog opens a log in dir, failing the test on error
+func openTestLog(t *testing.T, dir string, config Config) *Log {
+	t.Helper()
+	l, err := OpenLog(dir, "test", 0, config, testLogger)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	return l
+}
+
+// testBatch builds a batch of one record per timestamp, without a producer
+func testBatch(timestamps ...int64) *record.Batch {
+	records := make([]record.Record, len(timestamps))
+	maxTimestamp := timestamps[0]
+	for i, ts := range timestamps {
+		records[i].TimestampDelta = ts - timestamps[0]
+		records[i].Value = []byte("value")
+		maxTimestamp = max(maxTimestamp, ts)
+	}
+	return record.EncodeBatch(record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		BaseTimestamp:        timestamps[0],
+		MaxTimestamp:         maxTimestamp,
+		ProducerID:           record.NoProducerID,
+		ProducerEpoch:        record.NoProducerEpoch,
+		BaseSequence:         record.NoSequence,
+	}, records)
+}
+
+// appendBatch appends b to l, failing the test on error
+func appendBatch(t *testing.T, l *Log, b *record.Batch) AppendInfo {
+	t.Helper()
+	info, err := l.Append(b.Data)
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return info
+}
+
+// readAll reads every batch of l from offset on, one Read at a time as a
+// consumer would, and returns their base offsets
+func readAll(t *testing.T, l *Log, offset int64) []int64 {
+	t.Helper()
+	var bases []int64
+	for {
+		data, err := l.Read(offset, 1<<20, true)
+		if err != nil {
+			t.Fatalf("Read(%d): %v", offset, err)
+		}
+		if data == nil {
+			return bases
+		}
+		for len(data) > 0 {
+			b, err := record.ParseBatch(data)
+			if err != nil {
+				t.Fatalf("ParseBatch at offset %d: %v", offset, err)
+			}
+			bases = append(bases, b.BaseOffset)
+			offset = b.NextOffset()
+			data = data[b.Size():]
+		}
+	}
+}
+
+func TestAppendAndReadAcrossRoll(t *testing.T) {
+	batchSize := int64(testBatch(0, 0).Size())
+	tests := []struct {
+		name         string
+		segmentBytes int64
+		segmentMs    int64
+		timestamps   []int64 // of the batches appended
+		wantSegments []int64 // base offsets
+	}{
+		{
+			name:         "no roll",
+			segmentBytes: 1 << 20,
+			segmentMs:    1 << 40,
+			timestamps:   []int64{1000, 1001, 1002, 1003},
+			wantSegments: []int64{0},
+		},
+		{
+			name:         "roll on size",
+			segmentBytes: 2 * batchSize,
+			segmentMs:    1 << 40,
+			timestamps:   []int64{1000, 1001, 1002, 1003, 1004},
+			wantSegments: []int64{0, 4, 8},
+		},
+		{
+			name:         "roll on time",
+			segmentBytes: 1 << 20,
+			segmentMs:    100,
+			timestamps:   []int64{1000, 1050, 1100, 1101, 1300},
+			wantSegments: []int64{0, 6, 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := testConfig()
+			config.SegmentBytes, config.SegmentMs = tt.segmentBytes, tt.segmentMs
+			l := openTestLog(t, dir, config)
+
+			var wantBases []int64
+			for i, ts := range tt.timestamps {
+				info := appendBatch(t, l, testBatch(ts, ts))
+				if want := int64(2 * i); info.FirstOffset != want || info.LastOffset != want+1 {
+					t.Fatalf("batch %d appended at [%d, %d], want [%d, %d]", i, info.FirstOffset, info.LastOffset, want, want+1)
+				}
+				wantBases = append(wantBases, info.FirstOffset)
+			}
+
+			var segments []int64
+			for _, seg := range l.segments {
+				segments = append(segments, seg.baseOffset)
+			}
+			if !equalOffsets(segments, tt.wantSegments) {
+				t.Errorf("segments = %v, want %v", segments, tt.wantSegments)
+			}
+			if got := readAll(t, l, 0); !equalOffsets(got, wantBases) {
+				t.Errorf("read batches %v, want %v", got, wantBases)
+			}
+
+			// Reading from the middle of a batch returns the whole batch
+			if got := readAll(t, l, 3); !equalOffsets(got, wantBases[1:]) {
+				t.Errorf("read batches from offset 3: %v, want %v", got, wantBases[1:])
+			}
+			end := l.LogEndOffset()
+			if data, err := l.Read(end, 1<<20, true); data != nil || err != nil {
+				t.Errorf("Read at the log end offset = %d bytes, %v; want none", len(data), err)
+			}
+			if _, err := l.Read(end+1, 1<<20, true); !errors.Is(err, ErrOffsetOutOfRange) {
+				t.Errorf("Read past the log end offset: %v, want ErrOffsetOutOfRange", err)
+			}
+
+			// Everything is still there once the log is reopened
+			if err := l.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			l = openTestLog(t, dir, config)
+			defer l.Close()
+			if got := l.LogEndOffset(); got != end {
+				t.Errorf("reopened log end offset = %d, want %d", got, end)
+			}
+			if got := readAll(t, l, 0); !equalOffsets(got, wantBases) {
+				t.Errorf("reopened log read batches %v, want %v", got, wantBases)
+			}
+		})
+	}
+}
+
+func TestReadMaxBytes(t *testing.T) {
+	l := openTestLog(t, t.TempDir(), testConfig())
+	defer l.Close()
+	for i := 0; i < 3; i++ {
+		appendBatch(t, l, testBatch(1000, 1000))
+	}
+	batchSize := testBatch(1000, 1000).Size()
+
+	tests := []struct {
+		name        string
+		maxBytes    int
+		minOneBatch bool
+		wantSize    int
+	}{
+		{"whole batches only", 2*batchSize + 1, false, 2 * batchSize},
+		{"too small", batchSize - 1, false, 0},
+		{"too small with min one batch", batchSize - 1, true, batchSize},
+		{"everything", 1 << 20, false, 3 * batchSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := l.Read(0, tt.maxBytes, tt.minOneBatch)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if len(data) != tt.wantSize {
+				t.Errorf("read %d bytes, want %d", len(data), tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	batchSize := int64(testBatch(0).Size())
+	tests := []struct {
+		name string
+		// damage damages the log of three segments of two one-record
+		// batches each
+		damage       func(t *testing.T, dir string)
+		wantEnd      int64
+		wantSegments int
+	}{
+		{
+			name:         "intact",
+			damage:       func(t *testing.T, dir string) {},
+			wantEnd:      6,
+			wantSegments: 3,
+		},
+		{
+			name: "truncated tail",
+			damage: func(t *testing.T, dir string) {
+				truncateFile(t, segmentPath(dir, 4, logFileSuffix), 2*batchSize-7)
+			},
+			wantEnd:      5,
+			wantSegments: 3,
+		},
+		{
+			name: "truncated header",
+			damage: func(t *testing.T, dir string) {
+				truncateFile(t, segmentPath(dir, 4, logFileSuffix), batchSize+10)
+			},
+			wantEnd:      5,
+			wantSegments: 3,
+		},
+		{
+			name: "corrupted tail",
+			damage: func(t *testing.T, dir string) {
+				flipByte(t, segmentPath(dir, 4, logFileSuffix), 2*batchSize-1)
+			},
+			wantEnd:      5,
+			wantSegments: 3,
+		},
+		{
+			name: "zeroed tail",
+			damage: func(t *testing.T, dir string) {
+				appendFile(t, segmentPath(dir, 4, logFileSuffix), make([]byte, 100))
+			},
+			wantEnd:      6,
+			wantSegments: 3,
+		},
+		{
+			name: "corrupted earlier segment",
+			damage: func(t *testing.T, dir string) {
+				flipByte(t, segmentPath(dir, 2, logFileSuffix), batchSize+record.HeaderSize)
+			},
+			wantEnd:      3,
+			wantSegments: 2,
+		},
+		{
+			name: "missing indexes",
+			damage: func(t *testing.T, dir string) {
+				for _, base := range []int64{0, 2, 4} {
+					os.Remove(segmentPath(dir, base, indexFileSuffix))
+					os.Remove(segmentPath(dir, base, timeIndexFileSuffix))
+				}
+			},
+			wantEnd:      6,
+			wantSegments: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := testConfig()
+			config.SegmentBytes = 2 * batchSize
+			l := openTestLog(t, dir, config)
+			for i := int64(0); i < 6; i++ {
+				appendBatch(t, l, testBatch(1000+i))
+			}
+			if err := l.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			tt.damage(t, dir)
+			l = openTestLog(t, dir, config)
+			defer l.Close()
+
+			if got := l.LogEndOffset(); got != tt.wantEnd {
+				t.Errorf("log end offset = %d, want %d", got, tt.wantEnd)
+			}
+			if got := len(l.segments); got != tt.wantSegments {
+				t.Errorf("%d segments, want %d", got, tt.wantSegments)
+			}
+			var want []int64
+			for offset := int64(0); offset < tt.wantEnd; offset++ {
+				want = append(want, offset)
+			}
+			if got := readAll(t, l, 0); !equalOffsets(got, want) {
+				t.Errorf("read batches %v, want %v", got, want)
+			}
+
+			// The log takes appends again right after what survived
+			if info := appendBatch(t, l, testBatch(2000)); info.FirstOffset != tt.wantEnd {
+				t.Errorf("appended at %d, want %d", info.FirstOffset, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFindOffsetByTimestamp(t *testing.T) {
+	config := testConfig()
+	config.SegmentBytes = int64(2 * testBatch(0, 0).Size())
+	l := openTestLog(t, t.TempDir(), config)
+	defer l.Close()
+
+	// Offsets 0-1, 2-3 and 4-5, 6-7 in two segments, with the timestamps
+	// out of order across batches and within one
+	for _, timestamps := range [][]int64{{100, 200}, {150, 160}, {300, 250}, {400, 400}} {
+		appendBatch(t, l, testBatch(timestamps...))
+	}
+
+	tests := []struct {
+		timestamp int64
+		want      TimestampOffset
+		wantOK    bool
+	}{
+		{timestamp: 0, want: TimestampOffset{Timestamp: 100, Offset: 0}, wantOK: true},
+		{timestamp: 100, want: TimestampOffset{Timestamp: 100, Offset: 0}, wantOK: true},
+		{timestamp: 101, want: TimestampOffset{Timestamp: 200, Offset: 1}, wantOK: true},
+		{timestamp: 155, want: TimestampOffset{Timestamp: 200, Offset: 1}, wantOK: true},
+		{timestamp: 201, want: TimestampOffset{Timestamp: 300, Offset: 4}, wantOK: true},
+		{timestamp: 260, want: TimestampOffset{Timestamp: 300, Offset: 4}, wantOK: true},
+		{timestamp: 301, want: TimestampOffset{Timestamp: 400, Offset: 6}, wantOK: true},
+		{timestamp: 401, wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok, err := l.FindOffsetByTimestamp(tt.timestamp)
+		if err != nil {
+			t.Fatalf("FindOffsetByTimestamp(%d): %v", tt.timestamp, err)
+		}
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("FindOffsetByTimestamp(%d) = %+v, %v; want %+v, %v", tt.timestamp, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	got, ok, err := l.MaxTimestamp()
+	if want := (TimestampOffset{Timestamp: 400, Offset: 6}); err != nil || !ok || got != want {
+		t.Errorf("MaxTimestamp() = %+v, %v, %v; want %+v", got, ok, err, want)
+	}
+}
+
+// equalOffsets reports whether two offset lists are the same
+func equalOffsets(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// truncateFile truncates the file at path to size bytes
+func truncateFile(t *testing.T, path string, size int64) {
+	t.Helper()
+	if err := os.Truncate(path, size); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// flipByte inverts the byte at pos in the file at path
+func flipByte(t *testing.T, path string, pos int64) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[pos] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// appendFile appends data to the file at path
+func appendFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// cleanShutdownFile marks a log dir whose logs were all closed cleanly, so
+// they need no recovery on the next start
+const cleanShutdownFile = ".kafka_cleanshutdown"
+
+// metadataLogDir is the KRaft metadata log, which is managed by the
+// metadata package rather than as a partition log
+const metadataLogDir = "__cluster_metadata-0"
+
+// deleteDirSuffix marks a log dir that is being deleted. Like Kafka, a
+// deleted log is renamed to "<topic>-<partition>.<unique id>-delete" first,
+// freeing its name, and removed in the background.
+const deleteDirSuffix = "-delete"
+
+// TopicPartition identifies a partition
+type TopicPartition struct {
+	Topic     string
+	Partition int32
+}
+
+// String returns the partition in Kafka's topic-partition notation
+func (tp TopicPartition) String() string {
+	return fmt.Sprintf("%s-%d", tp.Topic, tp.Partition)
+}
+
+// Manager owns every partition log under a log dir
+type Manager struct {
+	dir    string
+	config Config
+	logger *logger.Logger
+
+	mu   sync.RWMutex
+	logs map[TopicPartition]*Log
+	// segmentPolicy is given to every log once set
+	segmentPolicy SegmentFunc
+
+	// deletions tracks the removal of renamed log dirs
+	deletions sync.WaitGroup
+}
+
+// Open opens every partition log under dir. Unless the previous shutdown
+// was clean, each log is recovered, truncating any torn tail.
+func Open(dir string, config Config, logger *logger.Logger) (*Manager, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log dir: %w", err)
+	}
+
+	marker := filepath.Join(dir, cleanShutdownFile)
+	_, err := os.Stat(marker)
+	recover := errors.Is(err, os.ErrNotExist)
+	if !recover {
+		if err := os.Remove(marker); err != nil {
+			return nil, fmt.Errorf("failed to remove clean shutdown marker: %w", err)
+		}
+	}
+
+	m := &Manager{
+		dir:    dir,
+		config: config,
+		logger: logger,
+		logs:   make(map[TopicPartition]*Log),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log dir: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == metadataLogDir {
+			continue
+		}
+		// Finish deletions interrupted by the previous shutdown
+		if strings.HasSuffix(entry.Name(), deleteDirSuffix) {
+			m.removeDir(filepath.Join(dir, entry.Name()))
+			continue
+		}
+		tp, ok := parseLogDirName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		log, err := openLog(filepath.Join(dir, entry.Name()), tp.Topic, tp.Partition, config, recover, logger)
+		if err != nil {
+			m.closeLogs()
+			return nil, fmt.Errorf("failed to open log %s: %w", tp, err)
+		}
+		m.logs[tp] = log
+	}
+
+	logger.Info("Loaded %d partition logs from %s", len(m.logs), dir)
+	return m, nil
+}
+
+// parseLogDirName splits a "<topic>-<partition>" directory name. Other
+// directories, such as those of deleted logs, are not partition logs.
+func parseLogDirName(name string) (TopicPartition, bool) {
+	i := strings.LastIndexByte(name, '-')
+	if i <= 0 {
+		return TopicPartition{}, false
+	}
+	partition, err := strconv.ParseInt(name[i+1:], 10, 32)
+	if err != nil || partition < 0 {
+		return TopicPartition{}, false
+	}
+	return TopicPartition{Topic: name[:i], Partition: int32(partition)}, true
+}
+
+// Get returns the log of a partition
+func (m *Manager) Get(topic string, partition int32) (*Log, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	log, ok := m.logs[TopicPartition{topic, partition}]
+	return log, ok
+}
+
+// GetOrCreate returns the log of a partition, creating it if needed. Only
+// topic creation should create logs: a request racing with a topic's
+// deletion would bring the log back.
+func (m *Manager) GetOrCreate(topic string, partition int32) (*Log, error) {
+	if log, ok := m.Get(topic, partition); ok {
+		return log, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tp := TopicPartition{topic, partition}
+	if log, ok := m.logs[tp]; ok {
+		return log, nil
+	}
+	log, err := openLog(filepath.Join(m.dir, tp.String()), topic, partition, m.config, false, m.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log %s: %w", tp, err)
+	}
+	log.segmentPolicy = m.segmentPolicy
+	m.logs[tp] = log
+	m.logger.Info("Created log for partition %s in %s", tp, log.Dir())
+	return log, nil
+}
+
+// SetSegmentPolicy makes every log, including those created later, roll
+// its segments as policy says for its topic rather than as the manager's
+// Config does
+func (m *Manager) SetSegmentPolicy(policy SegmentFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.segmentPolicy = policy
+	for _, log := range m.logs {
+		log.setSegmentPolicy(policy)
+	}
+}
+
+// DeleteTopic deletes the logs of every partition of a topic. Each log is
+// closed and its directory renamed for deletion before this returns; the
+// files are removed in the background.
+func (m *Manager) DeleteTopic(topic string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var firstErr error
+	for tp, log := range m.logs {
+		if tp.Topic != topic {
+			continue
+		}
+		delete(m.logs, tp)
+		if err := m.deleteLog(tp, log); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// deleteLog closes a log, renames its directory for deletion and starts
+// removing it
+func (m *Manager) deleteLog(tp TopicPartition, log *Log) error {
+	if err := log.Close(); err != nil {
+		m.logger.Error("Error closing deleted log %s: %s", tp, err.Error())
+	}
+
+	var id [16]byte
+	rand.Read(id[:])
+	renamed := filepath.Join(m.dir, fmt.Sprintf("%s.%x%s", tp, id, deleteDirSuffix))
+	if err := os.Rename(log.Dir(), renamed); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to rename log %s for deletion: %w", tp, err)
+	}
+
+	m.logger.Info("Scheduled deletion of log %s in %s", tp, renamed)
+	m.removeDir(renamed)
+	return nil
+}
+
+// removeDir removes a directory renamed for deletion in the background
+func (m *Manager) removeDir(dir string) {
+	m.deletions.Add(1)
+	go func() {
+		defer m.deletions.Done()
+		if err := os.RemoveAll(dir); err != nil {
+			m.logger.Error("Failed to delete %s: %s", dir, err.Error())
+			return
+		}
+		m.logger.Info("Deleted %s", dir)
+	}()
+}
+
+// Logs returns every partition log, sorted by topic and partition
+func (m *Manager) Logs() []*Log {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	logs := make([]*Log, 0, len(m.logs))
+	for _, log := range m.logs {
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].topic != logs[j].topic {
+			return logs[i].topic < logs[j].topic
+		}
+		return logs[i].partition < logs[j].partition
+	})
+	return logs
+}
+
+// Close closes every log and, if all closed cleanly, marks the log dir so
+// the next start skips recovery
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.closeLogs(); err != nil {
+		return err
+	}
+	m.deletions.Wait()
+	return os.WriteFile(filepath.Join(m.dir, cleanShutdownFile), nil, 0o644)
+}
+
+// closeLogs closes every log, returning the first error
+func (m *Manager) closeLogs() error {
+	var firstErr error
+	for tp, log := range m.logs {
+		if err := log.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close log %s: %w", tp, err)
+		}
+	}
+	m.logs = make(map[TopicPartition]*Log)
+	return firstErr
+}
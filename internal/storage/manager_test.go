@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+// openTestManager opens a manager on dir, failing the test on error
+func openTestManager(t *testing.T, dir string, config Config) *Manager {
+	t.Helper()
+	m, err := Open(dir, config, testLogger)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return m
+}
+
+func TestManagerCleanShutdown(t *testing.T) {
+	batchSize := int64(testBatch(0).Size())
+	tests := []struct {
+		name string
+		// damage damages the log of one segment of four one-record
+		// batches, left by a clean or an unclean shutdown
+		damage  func(t *testing.T, logDir string)
+		clean   bool
+		wantEnd int64
+	}{
+		{
+			name:    "clean",
+			damage:  func(t *testing.T, logDir string) {},
+			clean:   true,
+			wantEnd: 4,
+		},
+		{
+			name: "unclean with torn tail",
+			damage: func(t *testing.T, logDir string) {
+				truncateFile(t, segmentPath(logDir, 0, logFileSuffix), 4*batchSize-1)
+			},
+			wantEnd: 3,
+		},
+		{
+			name: "unclean with corrupted tail",
+			damage: func(t *testing.T, logDir string) {
+				flipByte(t, segmentPath(logDir, 0, logFileSuffix), 3*batchSize+record.HeaderSize)
+			},
+			wantEnd: 3,
+		},
+		{
+			// A clean shutdown only skips the CRC checks; a log that no
+			// longer matches its index is still recovered
+			name: "clean with index past the log",
+			damage: func(t *testing.T, logDir string) {
+				truncateFile(t, segmentPath(logDir, 0, logFileSuffix), 2*batchSize)
+			},
+			clean:   true,
+			wantEnd: 2,
+		},
+		{
+			name: "clean with torn tail past the index",
+			damage: func(t *testing.T, logDir string) {
+				appendFile(t, segmentPath(logDir, 0, logFileSuffix), testBatch(0).Data[:20])
+			},
+			clean:   true,
+			wantEnd: 4,
+		},
+		{
+			name: "clean with missing index",
+			damage: func(t *testing.T, logDir string) {
+				os.Remove(segmentPath(logDir, 0, indexFileSuffix))
+			},
+			clean:   true,
+			wantEnd: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			m := openTestManager(t, dir, testConfig())
+			l, err := m.GetOrCreate("test", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			logDir := l.Dir()
+			for i := int64(0); i < 4; i++ {
+				appendBatch(t, l, testBatch(1000+i))
+			}
+
+			if tt.clean {
+				if err := m.Close(); err != nil {
+					t.Fatalf("Close: %v", err)
+				}
+				if _, err := os.Stat(filepath.Join(dir, cleanShutdownFile)); err != nil {
+					t.Fatalf("no clean shutdown marker after Close: %v", err)
+				}
+			} else if err := l.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			tt.damage(t, logDir)
+			m = openTestManager(t, dir, testConfig())
+			defer m.Close()
+			if _, err := os.Stat(filepath.Join(dir, cleanShutdownFile)); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("clean shutdown marker still present after Open: %v", err)
+			}
+
+			l, ok := m.Get("test", 0)
+			if !ok {
+				t.Fatalf("log test-0 not loaded")
+			}
+			if got := l.LogEndOffset(); got != tt.wantEnd {
+				t.Errorf("log end offset = %d, want %d", got, tt.wantEnd)
+			}
+			var want []int64
+			for offset := int64(0); offset < tt.wantEnd; offset++ {
+				want = append(want, offset)
+			}
+			if got := readAll(t, l, 0); !equalOffsets(got, want) {
+				t.Errorf("read batches %v, want %v", got, want)
+			}
+			if info := appendBatch(t, l, testBatch(2000)); info.FirstOffset != tt.wantEnd {
+				t.Errorf("appended at %d, want %d", info.FirstOffset, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestManagerDeleteTopic(t *testing.T) {
+	dir := t.TempDir()
+	m := openTestManager(t, dir, testConfig())
+	for partition := int32(0); partition < 2; partition++ {
+		l, err := m.GetOrCreate("doomed", partition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		appendBatch(t, l, testBatch(1000))
+	}
+	if _, err := m.GetOrCreate("kept", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.DeleteTopic("doomed"); err != nil {
+		t.Fatalf("DeleteTopic: %v", err)
+	}
+	if _, ok := m.Get("doomed", 0); ok {
+		t.Errorf("log doomed-0 still registered after DeleteTopic")
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if len(names) != 2 || names[0] != cleanShutdownFile || names[1] != "kept-0" {
+		t.Errorf("log dir holds %v, want only [%s kept-0]", names, cleanShutdownFile)
+	}
+}
+
+func TestManagerSegmentPolicy(t *testing.T) {
+	batchSize := int64(testBatch(0).Size())
+	m := openTestManager(t, t.TempDir(), testConfig())
+	defer m.Close()
+
+	// The policy applies to logs opened before it is set as well as after
+	before, err := m.GetOrCreate("small", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetSegmentPolicy(func(topic string) SegmentPolicy {
+		if topic == "small" {
+			return SegmentPolicy{SegmentBytes: batchSize}
+		}
+		return SegmentPolicy{}
+	})
+	after, err := m.GetOrCreate("small", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := m.GetOrCreate("other", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		log          *Log
+		wantSegments int
+	}{
+		{before, 3},
+		{after, 3},
+		{other, 1},
+	}
+	for _, tt := range tests {
+		for i := int64(0); i < 3; i++ {
+			appendBatch(t, tt.log, testBatch(1000+i))
+		}
+		if got := len(tt.log.segments); got != tt.wantSegments {
+			t.Errorf("%s-%d has %d segments, want %d", tt.log.topic, tt.log.partition, got, tt.wantSegments)
+		}
+	}
+}
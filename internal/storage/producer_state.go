@@ -0,0 +1,470 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+var (
+	// ErrOutOfOrderSequence is returned when a producer's batch does not
+	// continue the sequence of its previous batch
+	ErrOutOfOrderSequence = errors.New("out of order sequence number")
+
+	// ErrInvalidProducerEpoch is returned when a producer writes with an
+	// epoch older than one already seen, meaning it has been fenced
+	ErrInvalidProducerEpoch = errors.New("invalid producer epoch")
+)
+
+// snapshotFileSuffix is the extension of producer state snapshots
+const snapshotFileSuffix = ".snapshot"
+
+// snapshotsToRetain is how many of its latest producer snapshots a log
+// keeps. Recovery needs only the newest; an older one stands in if that
+// turns out to be corrupt.
+const snapshotsToRetain = 2
+
+// producerSnapshotVersion is the version of the snapshot file format
+const producerSnapshotVersion int16 = 1
+
+// Producer snapshot layout: a version and a CRC-32C over everything after
+// it, then an array of fixed-size producer entries
+const (
+	snapshotHeaderSize = 2 + 4 + 4 // version, crc, entry count
+	snapshotEntrySize  = 8 + 2 + 4 + 8 + 4 + 8 + 4 + 8
+)
+
+// snapshotCRCTable is the CRC-32C (Castagnoli) table snapshots are checked
+// with
+var snapshotCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// batchesToRetain is how many of its latest batches are kept per
+// producer to detect retries. Idempotent producers have at most five
+// requests in flight, so a retry is always among them.
+const batchesToRetain = 5
+
+// batchMetadata describes a batch a producer appended
+type batchMetadata struct {
+	firstSeq, lastSeq int32
+	lastOffset        int64
+	offsetDelta       int32
+	timestamp         int64
+}
+
+// firstOffset returns the offset of the batch's first record
+func (b batchMetadata) firstOffset() int64 {
+	return b.lastOffset - int64(b.offsetDelta)
+}
+
+// completedTxn is a transaction ended by a marker in a partition
+type completedTxn struct {
+	producerID int64
+	// firstOffset is the offset of the transaction's first record in the
+	// partition, and lastOffset that of its marker
+	firstOffset int64
+	lastOffset  int64
+	aborted     bool
+}
+
+// producerStateEntry is what a partition knows about one producer
+type producerStateEntry struct {
+	producerID int64
+	epoch      int16
+	// batches are the producer's latest batches, oldest first
+	batches          []batchMetadata
+	coordinatorEpoch int32
+	lastTimestamp    int64
+	// currentTxnFirstOffset is the first offset of the producer's open
+	// transaction, or -1
+	currentTxnFirstOffset int64
+}
+
+// lastSeq returns the last sequence number the producer wrote, or
+// record.NoSequence if none is known
+func (e *producerStateEntry) lastSeq() int32 {
+	if len(e.batches) == 0 {
+		return record.NoSequence
+	}
+	return e.batches[len(e.batches)-1].lastSeq
+}
+
+// findDuplicate returns the retained batch that b is a retry of
+func (e *producerStateEntry) findDuplicate(b *record.Batch) (batchMetadata, bool) {
+	if b.ProducerEpoch != e.epoch || b.BaseSequence == record.NoSequence {
+		return batchMetadata{}, false
+	}
+	for _, m := range e.batches {
+		if m.firstSeq == b.BaseSequence && m.lastSeq == lastSequence(b) {
+			return m, true
+		}
+	}
+	return batchMetadata{}, false
+}
+
+// addBatch records a batch the producer appended, forgetting everything
+// about older epochs once the epoch moves on. Batches without sequence
+// numbers, such as transaction markers, only move the epoch.
+func (e *producerStateEntry) addBatch(b *record.Batch) {
+	if b.ProducerEpoch != e.epoch {
+		e.epoch = b.ProducerEpoch
+		e.batches = nil
+	}
+	e.lastTimestamp = b.MaxTimestamp
+	if b.BaseSequence == record.NoSequence {
+		return
+	}
+	e.batches = append(e.batches, batchMetadata{
+		firstSeq:    b.BaseSequence,
+		lastSeq:     lastSequence(b),
+		lastOffset:  b.LastOffset(),
+		offsetDelta: b.LastOffsetDelta,
+		timestamp:   b.MaxTimestamp,
+	})
+	if len(e.batches) > batchesToRetain {
+		e.batches = e.batches[len(e.batches)-batchesToRetain:]
+	}
+}
+
+// updateTransaction follows the producer's open transaction through one
+// of its batches: a transactional batch opens one if none is open, and a
+// COMMIT or ABORT marker ends it. It returns the transaction a marker
+// completed; markers of transactions with no records in the partition
+// complete nothing.
+func (e *producerStateEntry) updateTransaction(b *record.Batch) (*completedTxn, error) {
+	if !b.IsTransactional() {
+		return nil, nil
+	}
+	if !b.IsControl() {
+		if e.currentTxnFirstOffset < 0 {
+			e.currentTxnFirstOffset = b.BaseOffset
+		}
+		return nil, nil
+	}
+
+	controlType, err := b.ControlType()
+	if err != nil {
+		return nil, err
+	}
+	if controlType != record.ControlCommit && controlType != record.ControlAbort {
+		return nil, nil
+	}
+	if e.currentTxnFirstOffset < 0 {
+		return nil, nil
+	}
+	txn := &completedTxn{
+		producerID:  e.producerID,
+		firstOffset: e.currentTxnFirstOffset,
+		lastOffset:  b.BaseOffset,
+		aborted:     controlType == record.ControlAbort,
+	}
+	e.currentTxnFirstOffset = -1
+	return txn, nil
+}
+
+// lastSequence returns the sequence number of a batch's last record.
+// Sequence numbers wrap around to 0 after math.MaxInt32.
+func lastSequence(b *record.Batch) int32 {
+	if b.BaseSequence > math.MaxInt32-b.LastOffsetDelta {
+		return b.LastOffsetDelta - (math.MaxInt32 - b.BaseSequence) - 1
+	}
+	return b.BaseSequence + b.LastOffsetDelta
+}
+
+// inSequence reports whether next directly follows last
+func inSequence(last, next int32) bool {
+	return next == last+1 || (next == 0 && last == math.MaxInt32)
+}
+
+// producerState tracks the producers that wrote to a partition, so that
+// their batches can be checked for gaps and retries, along with the
+// transactions they have open there. It is guarded by the owning Log's
+// lock.
+type producerState struct {
+	dir       string
+	producers map[int64]*producerStateEntry
+}
+
+// newProducerState creates an empty producer state for the log in dir
+func newProducerState(dir string) *producerState {
+	return &producerState{dir: dir, producers: make(map[int64]*producerStateEntry)}
+}
+
+// findDuplicate returns the batch that b is a retry of, if any
+func (ps *producerState) findDuplicate(b *record.Batch) (batchMetadata, bool) {
+	if b.ProducerID == record.NoProducerID {
+		return batchMetadata{}, false
+	}
+	entry, ok := ps.producers[b.ProducerID]
+	if !ok {
+		return batchMetadata{}, false
+	}
+	return entry.findDuplicate(b)
+}
+
+// prepare checks batches, whose offsets have been assigned, against the
+// producers' epochs and sequence numbers. It returns the updated entries
+// of the producers involved, to be applied once the batches are written,
+// and the transactions the batches' markers complete.
+func (ps *producerState) prepare(batches []*record.Batch) (map[int64]*producerStateEntry, []*completedTxn, error) {
+	updates := make(map[int64]*producerStateEntry)
+	var completed []*completedTxn
+	for _, b := range batches {
+		if b.ProducerID == record.NoProducerID {
+			continue
+		}
+		entry, ok := updates[b.ProducerID]
+		if !ok {
+			entry = &producerStateEntry{
+				producerID:            b.ProducerID,
+				epoch:                 record.NoProducerEpoch,
+				coordinatorEpoch:      -1,
+				currentTxnFirstOffset: -1,
+			}
+			if current, ok := ps.producers[b.ProducerID]; ok {
+				clone := *current
+				clone.batches = append([]batchMetadata(nil), current.batches...)
+				entry = &clone
+			}
+			updates[b.ProducerID] = entry
+		}
+		if err := checkSequence(entry, b); err != nil {
+			return nil, nil, err
+		}
+		entry.addBatch(b)
+		txn, err := entry.updateTransaction(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if txn != nil {
+			completed = append(completed, txn)
+		}
+	}
+	return updates, completed, nil
+}
+
+// checkSequence checks that a batch continues what its producer wrote
+// before: it must not use an older epoch, must start a new epoch at
+// sequence 0, and must otherwise follow the last sequence number. A
+// producer whose state is unknown, for example because retention removed
+// its batches, may continue at any sequence number. Batches the
+// coordinators write, such as transaction markers, carry no sequence
+// number and only have their epoch checked.
+func checkSequence(entry *producerStateEntry, b *record.Batch) error {
+	if entry.epoch != record.NoProducerEpoch && b.ProducerEpoch < entry.epoch {
+		return fmt.Errorf("%w: producer %d at offset %d has epoch %d, older than the last seen epoch %d",
+			ErrInvalidProducerEpoch, b.ProducerID, b.BaseOffset, b.ProducerEpoch, entry.epoch)
+	}
+	if b.BaseSequence == record.NoSequence {
+		return nil
+	}
+	if b.ProducerEpoch != entry.epoch {
+		if b.BaseSequence != 0 && entry.epoch != record.NoProducerEpoch {
+			return fmt.Errorf("%w for new epoch %d of producer %d at offset %d: %d (current epoch %d)",
+				ErrOutOfOrderSequence, b.ProducerEpoch, b.ProducerID, b.BaseOffset, b.BaseSequence, entry.epoch)
+		}
+		return nil
+	}
+	if last := entry.lastSeq(); !inSequence(last, b.BaseSequence) {
+		return fmt.Errorf("%w for producer %d at offset %d: %d (incoming seq. number), %d (current end sequence number)",
+			ErrOutOfOrderSequence, b.ProducerID, b.BaseOffset, b.BaseSequence, last)
+	}
+	return nil
+}
+
+// apply installs the entries returned by prepare
+func (ps *producerState) apply(updates map[int64]*producerStateEntry) {
+	for id, entry := range updates {
+		ps.producers[id] = entry
+	}
+}
+
+// replay updates the state with a batch read back from the log, without
+// checking it, and returns the transaction it completes, if any
+func (ps *producerState) replay(b *record.Batch) (*completedTxn, error) {
+	if b.ProducerID == record.NoProducerID {
+		return nil, nil
+	}
+	entry, ok := ps.producers[b.ProducerID]
+	if !ok {
+		entry = &producerStateEntry{
+			producerID:            b.ProducerID,
+			epoch:                 b.ProducerEpoch,
+			coordinatorEpoch:      -1,
+			currentTxnFirstOffset: -1,
+		}
+		ps.producers[b.ProducerID] = entry
+	}
+	entry.addBatch(b)
+	return entry.updateTransaction(b)
+}
+
+// lastStableOffset returns the first offset of the oldest transaction
+// still open, or highWatermark when none is
+func (ps *producerState) lastStableOffset(highWatermark int64) int64 {
+	lso := highWatermark
+	for _, e := range ps.producers {
+		if e.currentTxnFirstOffset >= 0 && e.currentTxnFirstOffset < lso {
+			lso = e.currentTxnFirstOffset
+		}
+	}
+	return lso
+}
+
+// clear forgets every producer
+func (ps *producerState) clear() {
+	ps.producers = make(map[int64]*producerStateEntry)
+}
+
+// snapshotPath returns the path of the snapshot taken at offset
+func (ps *producerState) snapshotPath(offset int64) string {
+	return segmentPath(ps.dir, offset, snapshotFileSuffix)
+}
+
+// snapshotOffsets returns the offsets of the snapshots on disk, sorted
+func (ps *producerState) snapshotOffsets() ([]int64, error) {
+	entries, err := os.ReadDir(ps.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+	var offsets []int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, snapshotFileSuffix) {
+			continue
+		}
+		offset, err := strconv.ParseInt(strings.TrimSuffix(name, snapshotFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
+
+// takeSnapshot writes the state, which must reflect every batch below
+// offset, to the snapshot file for offset. Like Kafka's, a snapshot keeps
+// only the last batch of each producer.
+func (ps *producerState) takeSnapshot(offset int64) error {
+	ids := make([]int64, 0, len(ps.producers))
+	for id := range ps.producers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	buf := make([]byte, snapshotHeaderSize, snapshotHeaderSize+len(ids)*snapshotEntrySize)
+	binary.BigEndian.PutUint16(buf, uint16(producerSnapshotVersion))
+	binary.BigEndian.PutUint32(buf[6:], uint32(len(ids)))
+	for _, id := range ids {
+		e := ps.producers[id]
+		last := batchMetadata{firstSeq: record.NoSequence, lastSeq: record.NoSequence, lastOffset: -1}
+		if len(e.batches) > 0 {
+			last = e.batches[len(e.batches)-1]
+		}
+		buf = binary.BigEndian.AppendUint64(buf, uint64(e.producerID))
+		buf = binary.BigEndian.AppendUint16(buf, uint16(e.epoch))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(last.lastSeq))
+		buf = binary.BigEndian.AppendUint64(buf, uint64(last.lastOffset))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(last.offsetDelta))
+		buf = binary.BigEndian.AppendUint64(buf, uint64(e.lastTimestamp))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(e.coordinatorEpoch))
+		buf = binary.BigEndian.AppendUint64(buf, uint64(e.currentTxnFirstOffset))
+	}
+	binary.BigEndian.PutUint32(buf[2:], crc32.Checksum(buf[6:], snapshotCRCTable))
+
+	f, err := os.OpenFile(ps.snapshotPath(offset), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create producer snapshot: %w", err)
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write producer snapshot: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync producer snapshot: %w", err)
+	}
+	return f.Close()
+}
+
+// readSnapshot replaces the state with the snapshot taken at offset
+func (ps *producerState) readSnapshot(offset int64) error {
+	data, err := os.ReadFile(ps.snapshotPath(offset))
+	if err != nil {
+		return err
+	}
+	if len(data) < snapshotHeaderSize {
+		return fmt.Errorf("snapshot of %d bytes is too short", len(data))
+	}
+	if version := int16(binary.BigEndian.Uint16(data)); version != producerSnapshotVersion {
+		return fmt.Errorf("unknown snapshot version %d", version)
+	}
+	if stored, computed := binary.BigEndian.Uint32(data[2:]), crc32.Checksum(data[6:], snapshotCRCTable); stored != computed {
+		return fmt.Errorf("crc mismatch (stored %08x, computed %08x)", stored, computed)
+	}
+	count := int(binary.BigEndian.Uint32(data[6:]))
+	if len(data) != snapshotHeaderSize+count*snapshotEntrySize {
+		return fmt.Errorf("snapshot of %d bytes cannot hold %d entries", len(data), count)
+	}
+
+	ps.clear()
+	for pos := snapshotHeaderSize; pos < len(data); pos += snapshotEntrySize {
+		e := data[pos:]
+		entry := &producerStateEntry{
+			producerID:            int64(binary.BigEndian.Uint64(e)),
+			epoch:                 int16(binary.BigEndian.Uint16(e[8:])),
+			lastTimestamp:         int64(binary.BigEndian.Uint64(e[26:])),
+			coordinatorEpoch:      int32(binary.BigEndian.Uint32(e[34:])),
+			currentTxnFirstOffset: int64(binary.BigEndian.Uint64(e[38:])),
+		}
+		last := batchMetadata{
+			lastSeq:     int32(binary.BigEndian.Uint32(e[10:])),
+			lastOffset:  int64(binary.BigEndian.Uint64(e[14:])),
+			offsetDelta: int32(binary.BigEndian.Uint32(e[22:])),
+			timestamp:   entry.lastTimestamp,
+		}
+		if last.lastOffset >= 0 {
+			last.firstSeq = max(0, last.lastSeq-last.offsetDelta)
+			entry.batches = []batchMetadata{last}
+		}
+		ps.producers[entry.producerID] = entry
+	}
+	return nil
+}
+
+// deleteSnapshots removes the snapshots taken at offsets for which keep
+// returns false
+func (ps *producerState) deleteSnapshots(keep func(offset int64) bool) error {
+	offsets, err := ps.snapshotOffsets()
+	if err != nil {
+		return err
+	}
+	for _, offset := range offsets {
+		if keep(offset) {
+			continue
+		}
+		if err := os.Remove(ps.snapshotPath(offset)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteOldSnapshots removes all but the latest snapshotsToRetain
+// snapshots
+func (ps *producerState) deleteOldSnapshots() error {
+	offsets, err := ps.snapshotOffsets()
+	if err != nil || len(offsets) <= snapshotsToRetain {
+		return err
+	}
+	oldest := offsets[len(offsets)-snapshotsToRetain]
+	return ps.deleteSnapshots(func(offset int64) bool { return offset >= oldest })
+}
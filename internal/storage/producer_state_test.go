@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+// producerBatch builds a batch of count records from an idempotent
+// producer, starting at sequence
+func producerBatch(producerID int64, epoch int16, sequence int32, count int) *record.Batch {
+	records := make([]record.Record, count)
+	for i := range records {
+		records[i].Value = []byte("value")
+	}
+	return record.EncodeBatch(record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		BaseTimestamp:        1000,
+		MaxTimestamp:         1000,
+		ProducerID:           producerID,
+		ProducerEpoch:        epoch,
+		BaseSequence:         sequence,
+	}, records)
+}
+
+func TestProducerSequence(t *testing.T) {
+	type appendStep struct {
+		epoch    int16
+		sequence int32
+		count    int
+		// wantErr is the error the append fails with, or wantFirst the
+		// offset it gets
+		wantErr   error
+		wantFirst int64
+	}
+	tests := []struct {
+		name  string
+		steps []appendStep
+	}{
+		{
+			name: "in sequence",
+			steps: []appendStep{
+				{epoch: 0, sequence: 0, count: 2, wantFirst: 0},
+				{epoch: 0, sequence: 2, count: 1, wantFirst: 2},
+			},
+		},
+		{
+			name: "unknown producer starts anywhere",
+			steps: []appendStep{
+				{epoch: 0, sequence: 7, count: 1, wantFirst: 0},
+				{epoch: 0, sequence: 8, count: 1, wantFirst: 1},
+			},
+		},
+		{
+			name: "duplicate gets the original offsets",
+			steps: []appendStep{
+				{epoch: 0, sequence: 0, count: 2, wantFirst: 0},
+				{epoch: 0, sequence: 2, count: 1, wantFirst: 2},
+				{epoch: 0, sequence: 0, count: 2, wantFirst: 0},
+				{epoch: 0, sequence: 2, count: 1, wantFirst: 2},
+				{epoch: 0, sequence: 3, count: 1, wantFirst: 3},
+			},
+		},
+		{
+			name: "gap",
+			steps: []appendStep{
+				{epoch: 0, sequence: 0, count: 1, wantFirst: 0},
+				{epoch: 0, sequence: 2, count: 1, wantErr: ErrOutOfOrderSequence},
+			},
+		},
+		{
+			name: "overlapping retry",
+			steps: []appendStep{
+				{epoch: 0, sequence: 0, count: 2, wantFirst: 0},
+				{epoch: 0, sequence: 1, count: 2, wantErr: ErrOutOfOrderSequence},
+			},
+		},
+		{
+			name: "older epoch is fenced",
+			steps: []appendStep{
+				{epoch: 1, sequence: 0, count: 1, wantFirst: 0},
+				{epoch: 0, sequence: 1, count: 1, wantErr: ErrInvalidProducerEpoch},
+			},
+		},
+		{
+			name: "new epoch starts at 0",
+			steps: []appendStep{
+				{epoch: 0, sequence: 0, count: 1, wantFirst: 0},
+				{epoch: 1, sequence: 5, count: 1, wantErr: ErrOutOfOrderSequence},
+				{epoch: 1, sequence: 0, count: 1, wantFirst: 1},
+				{epoch: 0, sequence: 1, count: 1, wantErr: ErrInvalidProducerEpoch},
+			},
+		},
+		{
+			name: "sequence wraps around",
+			steps: []appendStep{
+				{epoch: 0, sequence: math.MaxInt32 - 1, count: 2, wantFirst: 0},
+				{epoch: 0, sequence: 0, count: 1, wantFirst: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := openTestLog(t, t.TempDir(), testConfig())
+			defer l.Close()
+			for i, step := range tt.steps {
+				end := l.LogEndOffset()
+				info, err := l.Append(producerBatch(1, step.epoch, step.sequence, step.count).Data)
+				if step.wantErr != nil {
+					if !errors.Is(err, step.wantErr) {
+						t.Fatalf("step %d: Append error = %v, want %v", i, err, step.wantErr)
+					}
+					if got := l.LogEndOffset(); got != end {
+						t.Errorf("step %d: rejected batch moved the log end offset to %d", i, got)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("step %d: Append: %v", i, err)
+				}
+				if info.FirstOffset != step.wantFirst || info.LastOffset != step.wantFirst+int64(step.count)-1 {
+					t.Errorf("step %d: appended at [%d, %d], want [%d, %d]", i, info.FirstOffset, info.LastOffset, step.wantFirst, step.wantFirst+int64(step.count)-1)
+				}
+			}
+		})
+	}
+}
+
+func TestProducerDuplicateInMultiBatchAppend(t *testing.T) {
+	l := openTestLog(t, t.TempDir(), testConfig())
+	defer l.Close()
+	appendBatch(t, l, producerBatch(1, 0, 0, 2))
+	appendBatch(t, l, producerBatch(1, 0, 2, 1))
+	concat := func(batches ...*record.Batch) []byte {
+		var data []byte
+		for _, b := range batches {
+			data = append(data, b.Data...)
+		}
+		return data
+	}
+
+	// A retried batch sent with a new one is rejected, and the new one
+	// neither appended nor counted in the producer's sequence
+	_, err := l.Append(concat(producerBatch(1, 0, 2, 1), producerBatch(1, 0, 3, 1)))
+	if !errors.Is(err, ErrOutOfOrderSequence) {
+		t.Fatalf("Append of a duplicate and a new batch: err = %v, want %v", err, ErrOutOfOrderSequence)
+	}
+	if got := l.LogEndOffset(); got != 3 {
+		t.Errorf("log end offset = %d, want 3", got)
+	}
+
+	// Retried batches alone get the offsets they were appended at
+	info, err := l.Append(concat(producerBatch(1, 0, 0, 2), producerBatch(1, 0, 2, 1)))
+	if err != nil {
+		t.Fatalf("Append of duplicates: %v", err)
+	}
+	if info.FirstOffset != 0 || info.LastOffset != 2 {
+		t.Errorf("duplicates appended at [%d, %d], want [0, 2]", info.FirstOffset, info.LastOffset)
+	}
+	if info, err = l.Append(producerBatch(1, 0, 3, 1).Data); err != nil || info.FirstOffset != 3 {
+		t.Errorf("Append of the next batch: offset %d, err %v, want offset 3", info.FirstOffset, err)
+	}
+}
+
+func TestProducerSnapshotReload(t *testing.T) {
+	tests := []struct {
+		name string
+		// damage removes snapshots of the closed log in dir; its snapshots
+		// are at offset 2, where it rolled, and at its end offset 4
+		damage func(t *testing.T, dir string)
+	}{
+		{
+			name:   "from the snapshot at the end",
+			damage: func(t *testing.T, dir string) {},
+		},
+		{
+			name: "from an older snapshot and the batches after it",
+			damage: func(t *testing.T, dir string) {
+				os.Remove(segmentPath(dir, 4, snapshotFileSuffix))
+			},
+		},
+		{
+			name: "from the whole log without a snapshot",
+			damage: func(t *testing.T, dir string) {
+				paths, _ := filepath.Glob(filepath.Join(dir, "*"+snapshotFileSuffix))
+				for _, path := range paths {
+					os.Remove(path)
+				}
+			},
+		},
+		{
+			name: "past a corrupted snapshot",
+			damage: func(t *testing.T, dir string) {
+				flipByte(t, segmentPath(dir, 4, snapshotFileSuffix), snapshotHeaderSize)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := openTestLog(t, dir, testConfig())
+			appendBatch(t, l, producerBatch(1, 0, 0, 1))
+			appendBatch(t, l, txnBatch(2, 0, 1000, "a=1"))
+			rollLog(t, l)
+			appendBatch(t, l, producerBatch(1, 0, 1, 2))
+			if err := l.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			tt.damage(t, dir)
+			l = openTestLog(t, dir, testConfig())
+			defer l.Close()
+
+			// Producer 2's transaction is still open
+			if got := l.LastStableOffset(); got != 1 {
+				t.Errorf("last stable offset = %d, want 1", got)
+			}
+			// A retry of producer 1's last batch gets its offsets back
+			info := appendBatch(t, l, producerBatch(1, 0, 1, 2))
+			if info.FirstOffset != 2 || info.LastOffset != 3 || l.LogEndOffset() != 4 {
+				t.Errorf("retry appended at [%d, %d], log end %d, want [2, 3], log end 4", info.FirstOffset, info.LastOffset, l.LogEndOffset())
+			}
+			// and the producer continues from its last sequence number
+			if _, err := l.Append(producerBatch(1, 0, 4, 1).Data); !errors.Is(err, ErrOutOfOrderSequence) {
+				t.Errorf("append with a gap: error = %v, want %v", err, ErrOutOfOrderSequence)
+			}
+			if info := appendBatch(t, l, producerBatch(1, 0, 3, 1)); info.FirstOffset != 4 {
+				t.Errorf("next batch appended at %d, want 4", info.FirstOffset)
+			}
+		})
+	}
+}
+
+func TestProducerSnapshotCleanup(t *testing.T) {
+	dir := t.TempDir()
+	l := openTestLog(t, dir, testConfig())
+	defer l.Close()
+	snapshots := func() []int64 {
+		t.Helper()
+		offsets, err := l.producers.snapshotOffsets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return offsets
+	}
+
+	// Every roll snapshots the producer state, but only the latest
+	// snapshots are kept
+	for sequence := int32(0); sequence < 5; sequence++ {
+		appendBatch(t, l, producerBatch(1, 0, sequence, 1))
+		rollLog(t, l)
+	}
+	if got, want := snapshots(), []int64{4, 5}; !equalOffsets(got, want) {
+		t.Errorf("snapshots after rolling = %v, want %v", got, want)
+	}
+
+	// Retention deletes those below the new log start offset
+	if err := l.EnforceRetention(RetentionPolicy{Delete: true, RetentionMs: 0, RetentionBytes: -1}, retentionNow); err != nil {
+		t.Fatalf("EnforceRetention: %v", err)
+	}
+	if got := l.LogStartOffset(); got != 5 {
+		t.Fatalf("log start offset = %d, want 5", got)
+	}
+	if got, want := snapshots(), []int64{5}; !equalOffsets(got, want) {
+		t.Errorf("snapshots after retention = %v, want %v", got, want)
+	}
+	if info := appendBatch(t, l, producerBatch(1, 0, 5, 1)); info.FirstOffset != 5 {
+		t.Errorf("next batch appended at %d, want 5", info.FirstOffset)
+	}
+}
@@ -0,0 +1,148 @@
+package storage
+
+import "time"
+
+// RetentionPolicy says how much of a partition's log retention keeps
+type RetentionPolicy struct {
+	// Delete is unset for topics that are only compacted, such as
+	// __consumer_offsets, whose segments retention never deletes
+	Delete bool
+	// RetentionMs is how long a segment is kept after its newest record,
+	// or -1 for no limit (retention.ms)
+	RetentionMs int64
+	// RetentionBytes is the size the log is trimmed to, or -1 for no
+	// limit (retention.bytes)
+	RetentionBytes int64
+}
+
+// RetentionFunc returns the retention policy of a topic's logs
+type RetentionFunc func(topic string) RetentionPolicy
+
+// RunCleaner is the log cleaner: every interval it deletes the segments
+// that retention no longer keeps from every log, until shutdown is closed
+func (m *Manager) RunCleaner(interval time.Duration, retention RetentionFunc, shutdown <-chan struct{}) {
+	// A ticker needs a positive interval; without one the default applies
+	if interval <= 0 {
+		interval = time.Duration(DefaultConfig().RetentionCheckIntervalMs) * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-ticker.C:
+			m.EnforceRetention(retention, time.Now().UnixMilli())
+		}
+	}
+}
+
+// EnforceRetention deletes the segments that retention no longer keeps at
+// time now from every log. Failures are logged and leave the log for the
+// next run.
+func (m *Manager) EnforceRetention(retention RetentionFunc, now int64) {
+	for _, log := range m.Logs() {
+		policy := retention(log.topic)
+		if !policy.Delete {
+			continue
+		}
+		if err := m.enforceRetention(log, policy, now); err != nil {
+			m.logger.Error("Failed to enforce retention on %s-%d: %s", log.topic, log.partition, err.Error())
+		}
+	}
+}
+
+// enforceRetention enforces retention on one log. Holding the manager's
+// lock keeps the log from being deleted or closed meanwhile; a log that
+// already was is skipped.
+func (m *Manager) enforceRetention(log *Log, policy RetentionPolicy, now int64) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.logs[TopicPartition{log.topic, log.partition}] != log {
+		return nil
+	}
+	return log.EnforceRetention(policy, now)
+}
+
+// EnforceRetention deletes the oldest segments of the log: those whose
+// newest record is older than RetentionMs at time now, then as many more
+// as the log can lose without shrinking below RetentionBytes. The log
+// start offset moves up to the first segment kept.
+func (l *Log) EnforceRetention(policy RetentionPolicy, now int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// An empty active segment holds nothing to delete. Sealed segments may
+	// be empty too, once compaction dropped all their records.
+	deletable := len(l.segments)
+	if l.activeSegment().size == 0 {
+		deletable--
+	}
+	count := 0
+	if policy.RetentionMs >= 0 {
+		for ; count < deletable; count++ {
+			timestamp, err := l.segments[count].largestTimestamp()
+			if err != nil {
+				return err
+			}
+			if now-timestamp <= policy.RetentionMs {
+				break
+			}
+		}
+	}
+	if policy.RetentionBytes >= 0 {
+		excess := -policy.RetentionBytes
+		for _, seg := range l.segments[count:] {
+			excess += seg.size
+		}
+		for ; count < deletable && excess >= l.segments[count].size; count++ {
+			excess -= l.segments[count].size
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	return l.deleteOldestSegments(count)
+}
+
+// deleteOldestSegments deletes the first count segments. The log always
+// keeps an active segment, so deleting every segment first rolls a new one
+// at the log end offset. The lock must be held.
+func (l *Log) deleteOldestSegments(count int) error {
+	if count == len(l.segments) {
+		if err := l.roll(); err != nil {
+			return err
+		}
+	}
+	deleted := l.segments[:count]
+	l.segments = l.segments[count:]
+	start := l.segments[0].baseOffset
+	l.logger.Info("Deleting %d segments of %s-%d past retention, moving the log start offset to %d", count, l.topic, l.partition, start)
+
+	var firstErr error
+	for _, seg := range deleted {
+		if err := seg.remove(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := l.producers.deleteSnapshots(func(offset int64) bool { return offset >= start }); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// largestTimestamp returns the newest record timestamp in the segment, or,
+// if no record has one, when the segment was last written, which is how
+// retention measures the age of a segment
+func (s *segment) largestTimestamp() (int64, error) {
+	if s.maxTimestamp >= 0 {
+		return s.maxTimestamp, nil
+	}
+	info, err := s.log.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.ModTime().UnixMilli(), nil
+}
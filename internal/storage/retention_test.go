@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// retentionNow is when the retention tests enforce retention
+const retentionNow int64 = 100000
+
+func TestEnforceRetention(t *testing.T) {
+	batchSize := int64(testBatch(0).Size())
+	tests := []struct {
+		name string
+		// timestamps has the timestamp of the one batch of each segment, in
+		// order; the last segment stays active, and is left empty if its
+		// timestamp is 0
+		timestamps []int64
+		policy     RetentionPolicy
+		// wantStart is the log start offset after retention, and
+		// wantSegments the base offsets of the segments left
+		wantStart    int64
+		wantSegments []int64
+	}{
+		{
+			name:         "no limits",
+			timestamps:   []int64{1000, 2000, 3000},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: -1, RetentionBytes: -1},
+			wantStart:    0,
+			wantSegments: []int64{0, 1, 2},
+		},
+		{
+			name:         "by time",
+			timestamps:   []int64{1000, 2000, 96000, 99000},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: 5000, RetentionBytes: -1},
+			wantStart:    2,
+			wantSegments: []int64{2, 3},
+		},
+		{
+			// A segment is kept while its newest record is, even if an older
+			// segment after it has expired
+			name:         "by time stops at the first segment kept",
+			timestamps:   []int64{1000, 99000, 2000, 99500},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: 5000, RetentionBytes: -1},
+			wantStart:    1,
+			wantSegments: []int64{1, 2, 3},
+		},
+		{
+			name:         "by size",
+			timestamps:   []int64{99000, 99100, 99200, 99300},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: -1, RetentionBytes: 2 * batchSize},
+			wantStart:    2,
+			wantSegments: []int64{2, 3},
+		},
+		{
+			// Deleting another segment would shrink the log below
+			// retention.bytes
+			name:         "by size keeps a partial excess",
+			timestamps:   []int64{99000, 99100, 99200, 99300},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: -1, RetentionBytes: 2*batchSize + 1},
+			wantStart:    1,
+			wantSegments: []int64{1, 2, 3},
+		},
+		{
+			name:         "by time then size",
+			timestamps:   []int64{1000, 99000, 99100, 99200, 99300},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: 5000, RetentionBytes: 2 * batchSize},
+			wantStart:    3,
+			wantSegments: []int64{3, 4},
+		},
+		{
+			name:         "empty active segment kept",
+			timestamps:   []int64{1000, 2000, 0},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: 0, RetentionBytes: 0},
+			wantStart:    2,
+			wantSegments: []int64{2},
+		},
+		{
+			// Deleting the active segment rolls a new, empty one at the log
+			// end offset
+			name:         "active segment replaced",
+			timestamps:   []int64{1000, 2000},
+			policy:       RetentionPolicy{Delete: true, RetentionMs: 5000, RetentionBytes: -1},
+			wantStart:    2,
+			wantSegments: []int64{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := openTestLog(t, dir, testConfig())
+			for i, ts := range tt.timestamps {
+				if i > 0 {
+					rollLog(t, l)
+				}
+				if ts != 0 {
+					appendBatch(t, l, testBatch(ts))
+				}
+			}
+			end := l.LogEndOffset()
+
+			if err := l.EnforceRetention(tt.policy, retentionNow); err != nil {
+				t.Fatalf("EnforceRetention: %v", err)
+			}
+			check := func(l *Log) {
+				t.Helper()
+				if got := l.LogStartOffset(); got != tt.wantStart {
+					t.Errorf("log start offset = %d, want %d", got, tt.wantStart)
+				}
+				if got := l.LogEndOffset(); got != end {
+					t.Errorf("log end offset = %d, want %d", got, end)
+				}
+				if got := segmentBases(l); !equalOffsets(got, tt.wantSegments) {
+					t.Errorf("segments = %v, want %v", got, tt.wantSegments)
+				}
+				var want []int64
+				for offset := tt.wantStart; offset < end; offset++ {
+					want = append(want, offset)
+				}
+				if got := readAll(t, l, tt.wantStart); !equalOffsets(got, want) {
+					t.Errorf("batches = %v, want %v", got, want)
+				}
+				if tt.wantStart > 0 {
+					if _, err := l.Read(tt.wantStart-1, 1<<20, true); !errors.Is(err, ErrOffsetOutOfRange) {
+						t.Errorf("Read below the log start offset: err = %v, want %v", err, ErrOffsetOutOfRange)
+					}
+				}
+			}
+			check(l)
+
+			// The log start offset stays where retention moved it
+			if err := l.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			l = openTestLog(t, dir, testConfig())
+			defer l.Close()
+			check(l)
+		})
+	}
+}
+
+func TestEnforceRetentionDeletesCompactedSegments(t *testing.T) {
+	l := openTestLog(t, t.TempDir(), testConfig())
+	defer l.Close()
+
+	// Compaction leaves the first segment empty, with the log start offset
+	appendBatch(t, l, keyedBatch(oldTimestamp, "a=1"))
+	rollLog(t, l)
+	appendBatch(t, l, keyedBatch(retentionNow, "a=2"))
+	rollLog(t, l)
+	if err := l.Compact(CompactionPolicy{Compact: true}, retentionNow); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if got, want := segmentBases(l), []int64{0, 1, 2}; !equalOffsets(got, want) {
+		t.Fatalf("segments after compaction = %v, want %v", got, want)
+	}
+
+	// Empty, it is deleted by size, though it holds no bytes to trim
+	policy := RetentionPolicy{Delete: true, RetentionMs: -1, RetentionBytes: int64(keyedBatch(0, "a=2").Size())}
+	if err := l.EnforceRetention(policy, retentionNow); err != nil {
+		t.Fatalf("EnforceRetention: %v", err)
+	}
+	if got, want := segmentBases(l), []int64{1, 2}; !equalOffsets(got, want) {
+		t.Errorf("segments = %v, want %v", got, want)
+	}
+	if got := l.LogStartOffset(); got != 1 {
+		t.Errorf("log start offset = %d, want 1", got)
+	}
+}
+
+func TestManagerEnforceRetention(t *testing.T) {
+	m := openTestManager(t, t.TempDir(), testConfig())
+	defer m.Close()
+	for _, topic := range []string{"deleted", "compacted"} {
+		l, err := m.GetOrCreate(topic, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		appendBatch(t, l, testBatch(1000))
+		rollLog(t, l)
+		appendBatch(t, l, testBatch(99000))
+	}
+
+	// Logs of topics without cleanup.policy=delete are left alone
+	m.EnforceRetention(func(topic string) RetentionPolicy {
+		return RetentionPolicy{Delete: topic == "deleted", RetentionMs: 5000, RetentionBytes: -1}
+	}, retentionNow)
+
+	tests := []struct {
+		topic     string
+		wantStart int64
+	}{
+		{"deleted", 1},
+		{"compacted", 0},
+	}
+	for _, tt := range tests {
+		l, _ := m.Get(tt.topic, 0)
+		if got := l.LogStartOffset(); got != tt.wantStart {
+			t.Errorf("%s log start offset = %d, want %d", tt.topic, got, tt.wantStart)
+		}
+	}
+}
+
+func TestRunCleanerWithoutInterval(t *testing.T) {
+	m := openTestManager(t, t.TempDir(), testConfig())
+	defer m.Close()
+
+	// A non-positive interval falls back to the default instead of
+	// panicking in the ticker
+	shutdown := make(chan struct{})
+	close(shutdown)
+	for _, interval := range []int64{0, -1} {
+		m.RunCleaner(time.Duration(interval), func(string) RetentionPolicy { return RetentionPolicy{} }, shutdown)
+	}
+}
@@ -0,0 +1,446 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+// Segment file extensions
+const (
+	logFileSuffix       = ".log"
+	indexFileSuffix     = ".index"
+	timeIndexFileSuffix = ".timeindex"
+	txnIndexFileSuffix  = ".txnindex"
+)
+
+// segmentPath returns the path of a segment file, named after the
+// segment's zero-padded base offset like Kafka's
+func segmentPath(dir string, baseOffset int64, suffix string) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", baseOffset, suffix))
+}
+
+// segment is a .log file holding a contiguous range of record batches,
+// together with its offset, time and transaction indexes
+type segment struct {
+	dir        string
+	baseOffset int64
+	log        *os.File
+	index      *offsetIndex
+	timeIndex  *timeIndex
+	txnIndex   *transactionIndex
+
+	size                 int64
+	nextOffset           int64
+	maxTimestamp         int64
+	offsetOfMaxTimestamp int64
+
+	// rollingTimestamp is the max timestamp of the first batch, against
+	// which time-based rolling is measured; -1 while the segment is empty
+	rollingTimestamp int64
+
+	indexIntervalBytes       int
+	bytesSinceLastIndexEntry int
+}
+
+// createSegment creates an empty segment starting at baseOffset
+func createSegment(dir string, baseOffset int64, indexIntervalBytes int) (*segment, error) {
+	for _, suffix := range []string{logFileSuffix, indexFileSuffix, timeIndexFileSuffix, txnIndexFileSuffix} {
+		if err := os.Remove(segmentPath(dir, baseOffset, suffix)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	seg, _, err := openSegment(dir, baseOffset, indexIntervalBytes, false)
+	return seg, err
+}
+
+// openSegment opens the segment starting at baseOffset. When recover is
+// set, or the indexes do not match the log, every batch is re-read and
+// checked, the indexes are rebuilt and any invalid tail is truncated; the
+// number of bytes truncated is returned.
+func openSegment(dir string, baseOffset int64, indexIntervalBytes int, recover bool) (*segment, int64, error) {
+	log, err := os.OpenFile(segmentPath(dir, baseOffset, logFileSuffix), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open segment: %w", err)
+	}
+	info, err := log.Stat()
+	if err != nil {
+		log.Close()
+		return nil, 0, err
+	}
+
+	s := &segment{
+		dir:                  dir,
+		baseOffset:           baseOffset,
+		log:                  log,
+		size:                 info.Size(),
+		nextOffset:           baseOffset,
+		maxTimestamp:         -1,
+		offsetOfMaxTimestamp: -1,
+		rollingTimestamp:     -1,
+		indexIntervalBytes:   indexIntervalBytes,
+	}
+	if s.index, err = openOffsetIndex(segmentPath(dir, baseOffset, indexFileSuffix), baseOffset); err != nil {
+		s.close()
+		return nil, 0, err
+	}
+	if s.timeIndex, err = openTimeIndex(segmentPath(dir, baseOffset, timeIndexFileSuffix), baseOffset); err != nil {
+		s.close()
+		return nil, 0, err
+	}
+	if s.txnIndex, err = openTransactionIndex(segmentPath(dir, baseOffset, txnIndexFileSuffix)); err != nil {
+		s.close()
+		return nil, 0, err
+	}
+
+	// A segment that cannot be loaded as is gets recovered
+	truncated := int64(0)
+	if recover || !s.index.sane(s.size) || s.load() != nil || !s.timeIndex.sane(s.nextOffset) {
+		if truncated, err = s.recover(); err != nil {
+			s.close()
+			return nil, 0, err
+		}
+	}
+
+	// Aborted transactions whose markers are gone are forgotten; the
+	// producer state replay indexes those it finds missing again
+	if err := s.txnIndex.truncateTo(s.nextOffset); err != nil {
+		s.close()
+		return nil, 0, err
+	}
+	return s, truncated, nil
+}
+
+// load restores the segment state of a cleanly closed segment, reading
+// only the batch headers past the last index entry
+func (s *segment) load() error {
+	if s.size == 0 {
+		return nil
+	}
+
+	first, err := s.readHeader(0)
+	if err != nil {
+		return err
+	}
+	s.rollingTimestamp = first.MaxTimestamp
+
+	pos := int64(0)
+	if last, ok := s.index.last(); ok {
+		pos = last.position
+	}
+	if last, ok := s.timeIndex.last(); ok {
+		s.maxTimestamp, s.offsetOfMaxTimestamp = last.timestamp, last.offset
+	}
+	for pos < s.size {
+		b, err := s.readHeader(pos)
+		if err != nil {
+			return err
+		}
+		s.nextOffset = b.NextOffset()
+		if b.MaxTimestamp > s.maxTimestamp {
+			s.maxTimestamp, s.offsetOfMaxTimestamp = b.MaxTimestamp, b.LastOffset()
+		}
+		pos += int64(b.Size())
+	}
+	return nil
+}
+
+// recover re-reads every batch of the segment, verifying its CRC and
+// rebuilding the indexes, and truncates the log at the first invalid or
+// incomplete batch. It returns the number of bytes truncated.
+func (s *segment) recover() (int64, error) {
+	if err := s.index.reset(); err != nil {
+		return 0, err
+	}
+	if err := s.timeIndex.reset(); err != nil {
+		return 0, err
+	}
+	s.nextOffset = s.baseOffset
+	s.maxTimestamp, s.offsetOfMaxTimestamp, s.rollingTimestamp = -1, -1, -1
+	s.bytesSinceLastIndexEntry = 0
+
+	pos := int64(0)
+	for pos < s.size {
+		b, err := s.readBatch(pos)
+		if err == nil {
+			err = b.VerifyCRC()
+		}
+		if err == nil && b.BaseOffset < s.nextOffset {
+			err = fmt.Errorf("%w: offset %d is out of order", record.ErrCorruptBatch, b.BaseOffset)
+		}
+		if err != nil {
+			break
+		}
+		if err := s.indexBatch(b, pos); err != nil {
+			return 0, err
+		}
+		pos += int64(b.Size())
+	}
+
+	truncated := s.size - pos
+	if truncated > 0 {
+		if err := s.log.Truncate(pos); err != nil {
+			return 0, fmt.Errorf("failed to truncate segment: %w", err)
+		}
+		s.size = pos
+	}
+	return truncated, nil
+}
+
+// readHeader reads the header of the batch at pos
+func (s *segment) readHeader(pos int64) (*record.Batch, error) {
+	buf := make([]byte, record.HeaderSize)
+	if _, err := s.log.ReadAt(buf, pos); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, record.ErrShortBatch
+		}
+		return nil, err
+	}
+	return record.ParseHeader(buf)
+}
+
+// readBatch reads the complete batch at pos
+func (s *segment) readBatch(pos int64) (*record.Batch, error) {
+	header, err := s.readHeader(pos)
+	if err != nil {
+		return nil, err
+	}
+	if pos+int64(header.Size()) > s.size {
+		return nil, record.ErrShortBatch
+	}
+	buf := make([]byte, header.Size())
+	if _, err := s.log.ReadAt(buf, pos); err != nil {
+		return nil, err
+	}
+	return record.ParseBatch(buf)
+}
+
+// forEachBatch calls fn with every batch of the segment, read whole
+func (s *segment) forEachBatch(fn func(*record.Batch) error) error {
+	for pos := int64(0); pos < s.size; {
+		b, err := s.readBatch(pos)
+		if err != nil {
+			return err
+		}
+		if err := fn(b); err != nil {
+			return err
+		}
+		pos += int64(b.Size())
+	}
+	return nil
+}
+
+// append writes a batch, whose offsets have been assigned, to the end of
+// the segment
+func (s *segment) append(b *record.Batch) error {
+	if _, err := s.log.WriteAt(b.Data, s.size); err != nil {
+		return fmt.Errorf("failed to write segment: %w", err)
+	}
+	if err := s.indexBatch(b, s.size); err != nil {
+		return err
+	}
+	s.size += int64(b.Size())
+	return nil
+}
+
+// indexBatch updates the segment state for a batch written at pos, adding
+// index entries once every indexIntervalBytes
+func (s *segment) indexBatch(b *record.Batch, pos int64) error {
+	if s.rollingTimestamp < 0 {
+		s.rollingTimestamp = b.MaxTimestamp
+	}
+	if b.MaxTimestamp > s.maxTimestamp {
+		s.maxTimestamp, s.offsetOfMaxTimestamp = b.MaxTimestamp, b.LastOffset()
+	}
+	s.nextOffset = b.NextOffset()
+
+	if s.bytesSinceLastIndexEntry > s.indexIntervalBytes {
+		if err := s.index.append(b.LastOffset(), pos); err != nil {
+			return err
+		}
+		if err := s.timeIndex.maybeAppend(s.maxTimestamp, s.offsetOfMaxTimestamp); err != nil {
+			return err
+		}
+		s.bytesSinceLastIndexEntry = 0
+	}
+	s.bytesSinceLastIndexEntry += b.Size()
+	return nil
+}
+
+// read returns the whole batches starting with the one holding offset,
+// up to maxBytes in total and stopping before the batch holding maxOffset.
+// If minOneBatch is set the first batch is returned even when it is larger
+// than maxBytes. It returns nil if no batch in the segment holds offset or
+// a later one.
+func (s *segment) read(offset, maxOffset int64, maxBytes int, minOneBatch bool) ([]byte, error) {
+	start := s.index.lookup(offset)
+	for start < s.size {
+		b, err := s.readHeader(start)
+		if err != nil {
+			return nil, err
+		}
+		if b.LastOffset() >= offset {
+			break
+		}
+		start += int64(b.Size())
+	}
+	if start >= s.size {
+		return nil, nil
+	}
+
+	end := start
+	for end < s.size {
+		b, err := s.readHeader(end)
+		if err != nil {
+			return nil, err
+		}
+		size := int64(b.Size())
+		if b.LastOffset() >= maxOffset {
+			break
+		}
+		if end-start+size > int64(maxBytes) && !(end == start && minOneBatch) {
+			break
+		}
+		end += size
+	}
+	if end == start {
+		return nil, nil
+	}
+
+	buf := make([]byte, end-start)
+	if _, err := s.log.ReadAt(buf, start); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// scanHeaders calls fn with the header of every batch holding offset from
+// or a later one. Control batches are read whole, since what they mark is
+// in their record.
+func (s *segment) scanHeaders(from int64, fn func(*record.Batch) error) error {
+	for pos := s.index.lookup(from); pos < s.size; {
+		b, err := s.readHeader(pos)
+		if err != nil {
+			return err
+		}
+		if b.IsControl() {
+			if b, err = s.readBatch(pos); err != nil {
+				return err
+			}
+		}
+		if b.LastOffset() >= from {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+		pos += int64(b.Size())
+	}
+	return nil
+}
+
+// findOffsetByTimestamp returns the first record at or after startOffset
+// whose timestamp is at least timestamp. The time index narrows down where
+// the search starts; the batches from there on are scanned.
+func (s *segment) findOffsetByTimestamp(timestamp, startOffset int64) (TimestampOffset, bool, error) {
+	if s.maxTimestamp < timestamp {
+		return TimestampOffset{}, false, nil
+	}
+
+	pos := s.index.lookup(max(s.timeIndex.lookup(timestamp), startOffset))
+	for pos < s.size {
+		header, err := s.readHeader(pos)
+		if err != nil {
+			return TimestampOffset{}, false, err
+		}
+		if header.MaxTimestamp >= timestamp && header.LastOffset() >= startOffset {
+			b, err := s.readBatch(pos)
+			if err != nil {
+				return TimestampOffset{}, false, err
+			}
+			if found, ok := findRecordByTimestamp(b, timestamp, startOffset); ok {
+				return found, true, nil
+			}
+		}
+		pos += int64(header.Size())
+	}
+	return TimestampOffset{}, false, nil
+}
+
+// findRecordByTimestamp returns the first record of a batch at or after
+// startOffset whose timestamp is at least timestamp. Records stamped with
+// the log append time all carry the batch's max timestamp; those of batches
+// that cannot be decoded are represented by the batch as a whole.
+func findRecordByTimestamp(b *record.Batch, timestamp, startOffset int64) (TimestampOffset, bool) {
+	first := max(b.BaseOffset, startOffset)
+	if b.TimestampType() == record.LogAppendTime {
+		return TimestampOffset{Timestamp: b.MaxTimestamp, Offset: first}, true
+	}
+
+	records, err := b.Records()
+	if err != nil {
+		return TimestampOffset{Timestamp: b.MaxTimestamp, Offset: first}, true
+	}
+	for _, r := range records {
+		offset := b.BaseOffset + int64(r.OffsetDelta)
+		if ts := b.BaseTimestamp + r.TimestampDelta; ts >= timestamp && offset >= startOffset {
+			return TimestampOffset{Timestamp: ts, Offset: offset}, true
+		}
+	}
+	return TimestampOffset{}, false
+}
+
+// seal records the final time index entry once the segment stops being
+// the active one
+func (s *segment) seal() error {
+	if s.maxTimestamp < 0 {
+		return nil
+	}
+	return s.timeIndex.maybeAppend(s.maxTimestamp, s.offsetOfMaxTimestamp)
+}
+
+// flush syncs the segment files to disk
+func (s *segment) flush() error {
+	for _, f := range []*os.File{s.log, s.index.file, s.timeIndex.file, s.txnIndex.file} {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close closes the segment files
+func (s *segment) close() error {
+	files := []*os.File{s.log}
+	if s.index != nil {
+		files = append(files, s.index.file)
+	}
+	if s.timeIndex != nil {
+		files = append(files, s.timeIndex.file)
+	}
+	if s.txnIndex != nil {
+		files = append(files, s.txnIndex.file)
+	}
+
+	var firstErr error
+	for _, f := range files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// remove closes the segment and deletes its files
+func (s *segment) remove() error {
+	s.close()
+	for _, suffix := range []string{logFileSuffix, indexFileSuffix, timeIndexFileSuffix, txnIndexFileSuffix} {
+		if err := os.Remove(segmentPath(s.dir, s.baseOffset, suffix)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// txnIndexEntrySize is the size of a .txnindex entry, as in Kafka: version
+// (int16), producer ID, first offset, last offset and last stable offset
+// (int64 each)
+const txnIndexEntrySize = 2 + 8 + 8 + 8 + 8
+
+// txnIndexEntryVersion is the version of .txnindex entries
+const txnIndexEntryVersion int16 = 0
+
+// AbortedTransaction is a transaction that was aborted, as reported to
+// read_committed consumers so they can drop its records
+type AbortedTransaction struct {
+	ProducerID int64
+	// FirstOffset is the offset of the transaction's first record in the
+	// partition
+	FirstOffset int64
+}
+
+// abortedTxn is an entry of the transaction index
+type abortedTxn struct {
+	producerID  int64
+	firstOffset int64
+	// lastOffset is the offset of the ABORT marker
+	lastOffset int64
+	// lastStableOffset is the partition's last stable offset once the
+	// transaction was aborted
+	lastStableOffset int64
+}
+
+// transactionIndex is a segment's .txnindex: the transactions aborted by
+// markers in the segment, in marker order. Entries are kept in memory and
+// appended to the file as they are added.
+type transactionIndex struct {
+	file    *os.File
+	entries []abortedTxn
+}
+
+// openTransactionIndex opens (creating if needed) the transaction index at
+// path
+func openTransactionIndex(path string) (*transactionIndex, error) {
+	file, data, err := openIndexFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &transactionIndex{file: file}
+	for i := 0; i+txnIndexEntrySize <= len(data); i += txnIndexEntrySize {
+		if version := int16(binary.BigEndian.Uint16(data[i:])); version != txnIndexEntryVersion {
+			file.Close()
+			return nil, fmt.Errorf("unknown entry version %d in transaction index %s", version, path)
+		}
+		idx.entries = append(idx.entries, abortedTxn{
+			producerID:       int64(binary.BigEndian.Uint64(data[i+2:])),
+			firstOffset:      int64(binary.BigEndian.Uint64(data[i+10:])),
+			lastOffset:       int64(binary.BigEndian.Uint64(data[i+18:])),
+			lastStableOffset: int64(binary.BigEndian.Uint64(data[i+26:])),
+		})
+	}
+	return idx, nil
+}
+
+// append adds an entry. Entries must come in marker order; one for a
+// marker already indexed, as when replaying the log, is ignored.
+func (idx *transactionIndex) append(txn abortedTxn) error {
+	if n := len(idx.entries); n > 0 && txn.lastOffset <= idx.entries[n-1].lastOffset {
+		return nil
+	}
+
+	var buf [txnIndexEntrySize]byte
+	binary.BigEndian.PutUint16(buf[0:], uint16(txnIndexEntryVersion))
+	binary.BigEndian.PutUint64(buf[2:], uint64(txn.producerID))
+	binary.BigEndian.PutUint64(buf[10:], uint64(txn.firstOffset))
+	binary.BigEndian.PutUint64(buf[18:], uint64(txn.lastOffset))
+	binary.BigEndian.PutUint64(buf[26:], uint64(txn.lastStableOffset))
+	if _, err := idx.file.WriteAt(buf[:], int64(len(idx.entries))*txnIndexEntrySize); err != nil {
+		return fmt.Errorf("failed to write transaction index: %w", err)
+	}
+	idx.entries = append(idx.entries, txn)
+	return nil
+}
+
+// collect returns the aborted transactions with records in [from, to):
+// those aborted at or after from that started before to
+func (idx *transactionIndex) collect(from, to int64) []AbortedTransaction {
+	var aborted []AbortedTransaction
+	for _, e := range idx.entries {
+		if e.lastOffset >= from && e.firstOffset < to {
+			aborted = append(aborted, AbortedTransaction{ProducerID: e.producerID, FirstOffset: e.firstOffset})
+		}
+	}
+	return aborted
+}
+
+// truncateTo drops the entries of markers at or past offset
+func (idx *transactionIndex) truncateTo(offset int64) error {
+	n := sort.Search(len(idx.entries), func(i int) bool { return idx.entries[i].lastOffset >= offset })
+	if n == len(idx.entries) {
+		return nil
+	}
+	idx.entries = idx.entries[:n]
+	return idx.file.Truncate(int64(n) * txnIndexEntrySize)
+}
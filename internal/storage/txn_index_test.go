@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+)
+
+func TestTransactionIndexCollect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txnindex")
+	idx, err := openTransactionIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []abortedTxn{
+		{producerID: 1, firstOffset: 10, lastOffset: 15, lastStableOffset: 16},
+		{producerID: 2, firstOffset: 12, lastOffset: 20, lastStableOffset: 21},
+		// A marker indexed again, as when the log is replayed, is ignored
+		{producerID: 2, firstOffset: 12, lastOffset: 20, lastStableOffset: 21},
+		{producerID: 3, firstOffset: 30, lastOffset: 31, lastStableOffset: 32},
+	}
+	for _, e := range entries {
+		if err := idx.append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx.file.Close()
+
+	// Entries survive a reopen
+	if idx, err = openTransactionIndex(path); err != nil {
+		t.Fatal(err)
+	}
+	defer idx.file.Close()
+	if len(idx.entries) != 3 {
+		t.Fatalf("reopened index holds %d entries, want 3", len(idx.entries))
+	}
+
+	tests := []struct {
+		from, to int64
+		want     []int64
+	}{
+		{0, 10, nil},
+		{0, 11, []int64{1}},
+		{15, 16, []int64{1, 2}},
+		{16, 100, []int64{2, 3}},
+		{21, 30, nil},
+		{31, 32, []int64{3}},
+		{32, 100, nil},
+	}
+	for _, tt := range tests {
+		var got []int64
+		for _, a := range idx.collect(tt.from, tt.to) {
+			got = append(got, a.ProducerID)
+		}
+		if !equalOffsets(got, tt.want) {
+			t.Errorf("collect(%d, %d) = producers %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// Truncating drops the markers at or past the offset
+	if err := idx.truncateTo(20); err != nil {
+		t.Fatal(err)
+	}
+	if got := idx.collect(0, 100); len(got) != 1 || got[0] != (AbortedTransaction{ProducerID: 1, FirstOffset: 10}) {
+		t.Errorf("collect after truncating to 20 = %v, want producer 1 from 10", got)
+	}
+}
+
+// endTxnMarker builds the marker ending producerID's transaction
+func endTxnMarker(producerID int64, commit bool) *record.Batch {
+	return record.EncodeEndTxnMarker(producerID, 0, 0, commit, 1000)
+}
+
+func TestLastStableOffset(t *testing.T) {
+	l := openTestLog(t, t.TempDir(), testConfig())
+	defer l.Close()
+
+	steps := []struct {
+		batch *record.Batch
+		want  int64
+	}{
+		{testBatch(1000), 1},
+		// The first transaction holds the last stable offset at its start
+		{txnBatch(1, 0, 1000, "a=1"), 1},
+		{txnBatch(2, 0, 1000, "a=2"), 1},
+		{txnBatch(1, 1, 1000, "a=3"), 1},
+		// and once committed, it moves to the start of the next open one
+		{endTxnMarker(1, true), 2},
+		{txnBatch(1, 2, 1000, "a=4"), 2},
+		{testBatch(1000), 2},
+		{endTxnMarker(2, false), 5},
+		// Aborting the last open transaction moves it to the log end
+		{endTxnMarker(1, false), 9},
+		{testBatch(1000), 10},
+	}
+	for i, step := range steps {
+		appendBatch(t, l, step.batch)
+		if got := l.LastStableOffset(); got != step.want {
+			t.Errorf("step %d: last stable offset = %d, want %d", i, got, step.want)
+		}
+	}
+}
+
+func TestReadCommitted(t *testing.T) {
+	l := openTestLog(t, t.TempDir(), testConfig())
+	defer l.Close()
+
+	// Producer 1's transaction is aborted in the first segment, producer
+	// 3's in the last one, and producer 2 commits in between. Producer 1's
+	// next transaction is still open.
+	appendBatch(t, l, testBatch(1000))             // 0
+	appendBatch(t, l, txnBatch(1, 0, 1000, "a=1")) // 1
+	appendBatch(t, l, txnBatch(2, 0, 1000, "a=2")) // 2
+	appendBatch(t, l, endTxnMarker(1, false))      // 3
+	rollLog(t, l)
+	appendBatch(t, l, txnBatch(2, 1, 1000, "a=3")) // 4
+	appendBatch(t, l, endTxnMarker(2, true))       // 5
+	appendBatch(t, l, txnBatch(3, 0, 1000, "a=4")) // 6
+	rollLog(t, l)
+	appendBatch(t, l, endTxnMarker(3, false))      // 7
+	appendBatch(t, l, txnBatch(1, 1, 1000, "a=5")) // 8
+	appendBatch(t, l, testBatch(1000))             // 9
+
+	aborted1 := AbortedTransaction{ProducerID: 1, FirstOffset: 1}
+	aborted3 := AbortedTransaction{ProducerID: 3, FirstOffset: 6}
+	tests := []struct {
+		name     string
+		offset   int64
+		maxBytes int
+		// wantEnd is the offset past the last batch read
+		wantEnd     int64
+		wantAborted []AbortedTransaction
+	}{
+		{"before the aborted transaction", 0, 1, 1, nil},
+		{"start of the aborted transaction", 1, 1, 2, []AbortedTransaction{aborted1}},
+		{"whole first segment", 0, 1 << 20, 4, []AbortedTransaction{aborted1}},
+		{"the abort marker", 3, 1 << 20, 4, []AbortedTransaction{aborted1}},
+		{"aborted in a later segment", 4, 1 << 20, 7, []AbortedTransaction{aborted3}},
+		{"committed transaction only", 4, 1, 5, nil},
+		{"up to the last stable offset", 7, 1 << 20, 8, []AbortedTransaction{aborted3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, aborted, err := l.ReadCommitted(tt.offset, tt.maxBytes, true)
+			if err != nil {
+				t.Fatalf("ReadCommitted: %v", err)
+			}
+			end := tt.offset
+			for rest := data; len(rest) > 0; {
+				b, err := record.ParseHeader(rest)
+				if err != nil {
+					t.Fatal(err)
+				}
+				end, rest = b.NextOffset(), rest[b.Size():]
+			}
+			if end != tt.wantEnd {
+				t.Errorf("read up to offset %d, want %d", end, tt.wantEnd)
+			}
+			if len(aborted) != len(tt.wantAborted) {
+				t.Fatalf("aborted transactions = %v, want %v", aborted, tt.wantAborted)
+			}
+			for i := range aborted {
+				if aborted[i] != tt.wantAborted[i] {
+					t.Errorf("aborted transactions = %v, want %v", aborted, tt.wantAborted)
+					break
+				}
+			}
+		})
+	}
+
+	// Nothing at or past the last stable offset is read
+	data, aborted, err := l.ReadCommitted(8, 1<<20, true)
+	if err != nil || data != nil || aborted != nil {
+		t.Errorf("ReadCommitted at the last stable offset = %d bytes, %v, %v, want nothing", len(data), aborted, err)
+	}
+}
@@ -0,0 +1,502 @@
+package txn
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/group"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// coordinatorEpoch is the epoch markers are written with. A single broker
+// never hands transaction state partitions over, so it never changes.
+const coordinatorEpoch int32 = 0
+
+// replayReadBytes is how much of the state topic is read at a time while
+// replaying it
+const replayReadBytes = 1 << 20
+
+// Config holds the transaction coordinator settings
+type Config struct {
+	// StateTopicPartitions is the number of partitions of the state topic
+	// (transaction.state.log.num.partitions)
+	StateTopicPartitions int32
+
+	// MaxTimeout caps the transaction timeouts producers may ask for
+	// (transaction.max.timeout.ms)
+	MaxTimeout time.Duration
+}
+
+// DefaultConfig returns Kafka's default transaction coordinator settings
+func DefaultConfig() Config {
+	return Config{
+		StateTopicPartitions: 50,
+		MaxTimeout:           15 * time.Minute,
+	}
+}
+
+// Coordinator manages the transactions of every transactional ID, which on
+// a single broker are all coordinated here. Each state change is written
+// to the state topic before it takes effect, and ending a transaction
+// writes its COMMIT or ABORT markers to every partition it wrote to.
+type Coordinator struct {
+	config      Config
+	logs        *storage.Manager
+	groups      *group.Coordinator
+	producerIDs *ProducerIDManager
+	logger      *logger.Logger
+
+	// mu guards the transactions, and is held by timeout callbacks as well
+	// as requests
+	mu           sync.Mutex
+	transactions map[string]*transaction
+	closed       bool
+
+	// markerWritten is told about each partition a marker is written to
+	markerWritten func(storage.TopicPartition)
+}
+
+// New creates a coordinator with no transactions that hands out producer
+// IDs from producerIDs, writes its state to the state topic in logs and
+// completes transactional offset commits in groups. Use Load to start from
+// the state already there.
+func New(config Config, logs *storage.Manager, groups *group.Coordinator, producerIDs *ProducerIDManager, logger *logger.Logger) *Coordinator {
+	return &Coordinator{
+		config:       config,
+		logs:         logs,
+		groups:       groups,
+		producerIDs:  producerIDs,
+		logger:       logger,
+		transactions: make(map[string]*transaction),
+	}
+}
+
+// Load creates a coordinator and rebuilds its transactions by replaying
+// every partition of the state topic found in logs. Transactions that were
+// ended but not completed get their markers written again, and open ones
+// get their timeouts back.
+func Load(config Config, logs *storage.Manager, groups *group.Coordinator, producerIDs *ProducerIDManager, logger *logger.Logger) (*Coordinator, error) {
+	c := New(config, logs, groups, producerIDs, logger)
+	for p := int32(0); p < config.StateTopicPartitions; p++ {
+		log, ok := logs.Get(StateTopic, p)
+		if !ok {
+			continue
+		}
+		if err := c.replay(log); err != nil {
+			return nil, fmt.Errorf("failed to load %s-%d: %w", StateTopic, p, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.transactions {
+		switch t.state {
+		case PrepareCommit, PrepareAbort:
+			if err := c.completeTransaction(t); err != nil {
+				logger.Error("Failed to complete transaction of %s: %s", t.id, err.Error())
+			}
+		case Ongoing:
+			c.scheduleTimeout(t)
+		}
+	}
+	if len(c.transactions) > 0 {
+		logger.Info("Loaded %d transactional IDs from %s", len(c.transactions), StateTopic)
+	}
+	return c, nil
+}
+
+// replay applies every record of a partition of the state topic, in
+// order, so that the last state of each transactional ID wins
+func (c *Coordinator) replay(log *storage.Log) error {
+	for offset := log.LogStartOffset(); offset < log.LogEndOffset(); {
+		data, err := log.Read(offset, replayReadBytes, true)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			break
+		}
+		for len(data) > 0 {
+			batch, err := record.NextBatch(data)
+			if err != nil {
+				return err
+			}
+			data = data[batch.Size():]
+			offset = batch.NextOffset()
+			if batch.IsControl() {
+				continue
+			}
+
+			records, err := batch.Records()
+			if err != nil {
+				return err
+			}
+			for _, rec := range records {
+				id, t, err := parseStateRecord(rec)
+				if err != nil {
+					return fmt.Errorf("invalid record at offset %d: %w", batch.BaseOffset+int64(rec.OffsetDelta), err)
+				}
+				if t == nil {
+					delete(c.transactions, id)
+					continue
+				}
+				c.transactions[id] = t
+			}
+		}
+	}
+	return nil
+}
+
+// OnMarkerWritten registers fn to be called with each partition a marker
+// is written to, so that fetches waiting on it can read again. It must be
+// called before the coordinator serves requests.
+func (c *Coordinator) OnMarkerWritten(fn func(storage.TopicPartition)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.markerWritten = fn
+}
+
+// StateTopicPartitions returns the number of partitions the state topic
+// is created with
+func (c *Coordinator) StateTopicPartitions() int32 {
+	return c.config.StateTopicPartitions
+}
+
+// Close stops every transaction timeout. Requests arriving afterwards get
+// NOT_COORDINATOR.
+func (c *Coordinator) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, t := range c.transactions {
+		t.stopTimer()
+	}
+}
+
+// InitProducerID hands out a producer ID and epoch. An idempotent producer,
+// with no transactional ID, gets a new producer ID at epoch 0. A
+// transactional producer keeps the producer ID of its transactional ID
+// with the epoch bumped, which fences any older instance; a transaction
+// the older instance left open is aborted first. Once the epoch is
+// exhausted the ID moves on to a new producer ID. Producers from v3 pass
+// their current producer ID and epoch, which must still be current.
+func (c *Coordinator) InitProducerID(transactionalID *string, timeoutMs int32, producerID int64, epoch int16) (int64, int16, int16) {
+	if transactionalID == nil {
+		id, err := c.producerIDs.Generate()
+		if err != nil {
+			c.logger.Error("Failed to allocate a producer ID: %s", err.Error())
+			return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorCoordinatorNotAvailable
+		}
+		return id, 0, protocol.ErrorNone
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	switch {
+	case c.closed:
+		return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorNotCoordinator
+	case *transactionalID == "":
+		return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorInvalidRequest
+	case timeoutMs <= 0 || timeout > c.config.MaxTimeout:
+		return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorInvalidTransactionTimeout
+	}
+
+	t := c.transactions[*transactionalID]
+	if t == nil {
+		id, err := c.producerIDs.Generate()
+		if err != nil {
+			c.logger.Error("Failed to allocate a producer ID: %s", err.Error())
+			return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorCoordinatorNotAvailable
+		}
+		t = &transaction{
+			id:         *transactionalID,
+			producerID: id,
+			epoch:      record.NoProducerEpoch,
+			state:      Empty,
+			partitions: make(map[storage.TopicPartition]bool),
+		}
+	} else if producerID != record.NoProducerID && (producerID != t.producerID || epoch != t.epoch) {
+		return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorProducerFenced
+	}
+
+	switch t.state {
+	case Ongoing:
+		// The previous instance of the producer is fenced by aborting its
+		// transaction at a bumped epoch
+		if err := c.endTransaction(t, false, true); err != nil {
+			c.logger.Error("Failed to abort transaction of %s: %s", t.id, err.Error())
+			return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorConcurrentTransactions
+		}
+	case PrepareCommit, PrepareAbort:
+		if err := c.completeTransaction(t); err != nil {
+			c.logger.Error("Failed to complete transaction of %s: %s", t.id, err.Error())
+			return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorConcurrentTransactions
+		}
+	}
+
+	next := t.clone()
+	if t.epoch >= math.MaxInt16-1 {
+		id, err := c.producerIDs.Generate()
+		if err != nil {
+			c.logger.Error("Failed to allocate a producer ID: %s", err.Error())
+			return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorCoordinatorNotAvailable
+		}
+		next.producerID, next.epoch = id, 0
+	} else {
+		next.epoch++
+	}
+	next.timeout = timeout
+	next.state = Empty
+	next.partitions = make(map[storage.TopicPartition]bool)
+	next.startTimestamp = -1
+	if err := c.transition(t, next); err != nil {
+		c.logger.Error("Failed to write state of %s: %s", t.id, err.Error())
+		return record.NoProducerID, record.NoProducerEpoch, protocol.ErrorCoordinatorNotAvailable
+	}
+	c.logger.Info("Initialized transactional ID %s with producer ID %d and epoch %d", next.id, next.producerID, next.epoch)
+	return next.producerID, next.epoch, protocol.ErrorNone
+}
+
+// validate looks up the transaction of a request from a transactional
+// producer, checking that the producer is its current instance
+func (c *Coordinator) validate(transactionalID string, producerID int64, epoch int16) (*transaction, int16) {
+	t := c.transactions[transactionalID]
+	switch {
+	case c.closed:
+		return nil, protocol.ErrorNotCoordinator
+	case t == nil, t.producerID != producerID:
+		return nil, protocol.ErrorInvalidProducerIDMapping
+	case t.epoch != epoch:
+		return nil, protocol.ErrorProducerFenced
+	}
+	return t, protocol.ErrorNone
+}
+
+// AddPartitions adds partitions to the producer's transaction, starting
+// it if none is open
+func (c *Coordinator) AddPartitions(transactionalID string, producerID int64, epoch int16, partitions []storage.TopicPartition) int16 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, errorCode := c.validate(transactionalID, producerID, epoch)
+	if errorCode != protocol.ErrorNone {
+		return errorCode
+	}
+	switch t.state {
+	case PrepareCommit, PrepareAbort:
+		return protocol.ErrorConcurrentTransactions
+	case Ongoing:
+		added := true
+		for _, tp := range partitions {
+			added = added && t.partitions[tp]
+		}
+		if added {
+			return protocol.ErrorNone
+		}
+	}
+
+	next := t.clone()
+	if t.state != Ongoing {
+		next.state = Ongoing
+		next.startTimestamp = time.Now().UnixMilli()
+	}
+	for _, tp := range partitions {
+		next.partitions[tp] = true
+	}
+	if err := c.transition(t, next); err != nil {
+		c.logger.Error("Failed to write state of %s: %s", t.id, err.Error())
+		return protocol.ErrorCoordinatorNotAvailable
+	}
+	if t.timer == nil {
+		c.scheduleTimeout(t)
+	}
+	return protocol.ErrorNone
+}
+
+// VerifyPartitions checks, without changing anything, that partitions
+// were added to the producer's open transaction, as brokers do before
+// appending transactional records. It returns an error for the request as
+// a whole, or one for each partition.
+func (c *Coordinator) VerifyPartitions(transactionalID string, producerID int64, epoch int16, partitions []storage.TopicPartition) (map[storage.TopicPartition]int16, int16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, errorCode := c.validate(transactionalID, producerID, epoch)
+	if errorCode != protocol.ErrorNone {
+		return nil, errorCode
+	}
+	results := make(map[storage.TopicPartition]int16, len(partitions))
+	for _, tp := range partitions {
+		results[tp] = protocol.ErrorNone
+		if t.state != Ongoing || !t.partitions[tp] {
+			results[tp] = protocol.ErrorInvalidTxnState
+		}
+	}
+	return results, protocol.ErrorNone
+}
+
+// EndTransaction commits or aborts the producer's open transaction,
+// writing its markers before returning. Retrying a request whose
+// transaction already completed the same way succeeds, and retrying one
+// whose markers could not all be written tries them again.
+func (c *Coordinator) EndTransaction(transactionalID string, producerID int64, epoch int16, commit bool) int16 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, errorCode := c.validate(transactionalID, producerID, epoch)
+	if errorCode != protocol.ErrorNone {
+		return errorCode
+	}
+	wanted := map[bool]state{true: PrepareCommit, false: PrepareAbort}[commit]
+	completed := map[bool]state{true: CompleteCommit, false: CompleteAbort}[commit]
+	switch t.state {
+	case Ongoing:
+		if err := c.endTransaction(t, commit, false); err != nil {
+			c.logger.Error("Failed to end transaction of %s: %s", t.id, err.Error())
+			return protocol.ErrorCoordinatorNotAvailable
+		}
+		return protocol.ErrorNone
+	case wanted:
+		if err := c.completeTransaction(t); err != nil {
+			c.logger.Error("Failed to complete transaction of %s: %s", t.id, err.Error())
+			return protocol.ErrorCoordinatorNotAvailable
+		}
+		return protocol.ErrorNone
+	case completed:
+		return protocol.ErrorNone
+	}
+	return protocol.ErrorInvalidTxnState
+}
+
+// endTransaction moves an open transaction to PrepareCommit or
+// PrepareAbort and completes it. With bumpEpoch the epoch is bumped first,
+// so that markers fence the producer that opened it.
+func (c *Coordinator) endTransaction(t *transaction, commit, bumpEpoch bool) error {
+	t.stopTimer()
+	next := t.clone()
+	next.state = PrepareAbort
+	if commit {
+		next.state = PrepareCommit
+	}
+	if bumpEpoch && next.epoch < math.MaxInt16-1 {
+		next.epoch++
+	}
+	if err := c.transition(t, next); err != nil {
+		return err
+	}
+	return c.completeTransaction(t)
+}
+
+// completeTransaction writes the markers of an ended transaction to each
+// of its partitions and moves it to CompleteCommit or CompleteAbort. If a
+// marker cannot be written the transaction stays prepared, so that the
+// markers are written again when the producer retries.
+func (c *Coordinator) completeTransaction(t *transaction) error {
+	commit := t.state == PrepareCommit
+	for _, tp := range t.sortedPartitions() {
+		if err := c.WriteMarker(tp, t.producerID, t.epoch, coordinatorEpoch, commit); err != nil {
+			return err
+		}
+	}
+
+	next := t.clone()
+	next.state = CompleteAbort
+	if commit {
+		next.state = CompleteCommit
+	}
+	next.partitions = make(map[storage.TopicPartition]bool)
+	if err := c.transition(t, next); err != nil {
+		return err
+	}
+	c.logger.Debug("Completed transaction of %s as %s", t.id, t.state)
+	return nil
+}
+
+// WriteMarker appends the marker ending a producer's transaction to a
+// partition. For a partition of the offsets topic the group coordinator
+// then applies or drops the offsets the transaction committed. Partitions
+// that no longer exist are skipped.
+func (c *Coordinator) WriteMarker(tp storage.TopicPartition, producerID int64, epoch int16, coordinatorEpoch int32, commit bool) error {
+	log, ok := c.logs.Get(tp.Topic, tp.Partition)
+	if !ok {
+		c.logger.Info("Skipping transaction marker for %s-%d, which no longer exists", tp.Topic, tp.Partition)
+		return nil
+	}
+	marker := record.EncodeEndTxnMarker(producerID, epoch, coordinatorEpoch, commit, time.Now().UnixMilli())
+	if _, err := log.Append(marker.Data); err != nil {
+		return fmt.Errorf("failed to write marker to %s-%d: %w", tp.Topic, tp.Partition, err)
+	}
+	if tp.Topic == group.OffsetsTopic {
+		c.groups.CompleteTransaction(producerID, tp.Partition, commit)
+	}
+	if c.markerWritten != nil {
+		c.markerWritten(tp)
+	}
+	return nil
+}
+
+// transition writes next, the next state of t, to the state topic and
+// then applies it
+func (c *Coordinator) transition(t, next *transaction) error {
+	next.lastUpdateTimestamp = time.Now().UnixMilli()
+	partition := group.PartitionFor(next.id, c.config.StateTopicPartitions)
+	log, ok := c.logs.Get(StateTopic, partition)
+	if !ok {
+		return fmt.Errorf("no log for %s-%d", StateTopic, partition)
+	}
+	batch := record.EncodeBatch(record.Batch{
+		BaseTimestamp: next.lastUpdateTimestamp,
+		MaxTimestamp:  next.lastUpdateTimestamp,
+		ProducerID:    record.NoProducerID,
+		ProducerEpoch: record.NoProducerEpoch,
+		BaseSequence:  record.NoSequence,
+	}, []record.Record{stateRecord(next)})
+	if _, err := log.Append(batch.Data); err != nil {
+		return err
+	}
+
+	timer := t.timer
+	*t = *next
+	t.timer = timer
+	c.transactions[t.id] = t
+	return nil
+}
+
+// scheduleTimeout aborts an open transaction that is still open once its
+// timeout has passed since it started. The abort bumps the epoch, fencing
+// the producer: one that stalled that long must not finish it.
+func (c *Coordinator) scheduleTimeout(t *transaction) {
+	t.stopTimer()
+	start := t.startTimestamp
+	remaining := time.Until(time.UnixMilli(start).Add(t.timeout))
+	t.timer = time.AfterFunc(max(remaining, 0), func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.closed || c.transactions[t.id] != t || t.state != Ongoing || t.startTimestamp != start {
+			return
+		}
+		t.timer = nil
+		c.logger.Info("Aborting transaction of %s, which timed out after %s", t.id, t.timeout)
+		if err := c.endTransaction(t, false, true); err != nil {
+			c.logger.Error("Failed to abort transaction of %s: %s", t.id, err.Error())
+		}
+	})
+}
+
+// stopTimer cancels the transaction's timeout
+func (t *transaction) stopTimer() {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
@@ -0,0 +1,211 @@
+package txn
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+// newTestCoordinator returns a coordinator with a single state topic
+// partition, whose logs also hold partitions 0 and 1 of the topic
+// "events". It has no group coordinator.
+func newTestCoordinator(t *testing.T) (*Coordinator, *storage.Manager) {
+	t.Helper()
+	log := logger.New(logger.ERROR)
+	logs, err := storage.Open(t.TempDir(), storage.DefaultConfig(), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { logs.Close() })
+	for _, tp := range []storage.TopicPartition{{Topic: StateTopic, Partition: 0}, {Topic: "events", Partition: 0}, {Topic: "events", Partition: 1}} {
+		if _, err := logs.GetOrCreate(tp.Topic, tp.Partition); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := Config{StateTopicPartitions: 1, MaxTimeout: time.Hour}
+	c := New(config, logs, nil, NewProducerIDManager(metadata.NewImage(), 1), log)
+	t.Cleanup(c.Close)
+	return c, logs
+}
+
+// transactionalBatch builds a batch of one record written in the open
+// transaction of producerID at epoch
+func transactionalBatch(producerID int64, epoch int16, sequence int32) *record.Batch {
+	return record.EncodeBatch(record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		Attributes:           record.TransactionalAttribute,
+		BaseTimestamp:        1000,
+		MaxTimestamp:         1000,
+		ProducerID:           producerID,
+		ProducerEpoch:        epoch,
+		BaseSequence:         sequence,
+	}, []record.Record{{Value: []byte("value")}})
+}
+
+// plainBatch builds a batch of one record written outside transactions
+func plainBatch() *record.Batch {
+	return record.EncodeBatch(record.Batch{
+		PartitionLeaderEpoch: record.NoPartitionLeaderEpoch,
+		BaseTimestamp:        1000,
+		MaxTimestamp:         1000,
+		ProducerID:           record.NoProducerID,
+		ProducerEpoch:        record.NoProducerEpoch,
+		BaseSequence:         record.NoSequence,
+	}, []record.Record{{Value: []byte("value")}})
+}
+
+// readBatches returns every batch of log
+func readBatches(t *testing.T, log *storage.Log) []*record.Batch {
+	t.Helper()
+	var batches []*record.Batch
+	for offset := log.LogStartOffset(); offset < log.LogEndOffset(); {
+		data, err := log.Read(offset, 1<<20, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for len(data) > 0 {
+			b, err := record.NextBatch(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			batches = append(batches, b)
+			data, offset = data[b.Size():], b.NextOffset()
+		}
+	}
+	return batches
+}
+
+func TestEndTransactionMarkers(t *testing.T) {
+	transactionalID := "tx"
+	tests := []struct {
+		name string
+		// end ends the transaction opened by the producer at epoch
+		end         func(t *testing.T, c *Coordinator, producerID int64, epoch int16)
+		wantControl record.ControlType
+		// wantEpochBump is how much higher than the producer's the epoch of
+		// the markers is
+		wantEpochBump int16
+		wantState     state
+	}{
+		{
+			name: "commit",
+			end: func(t *testing.T, c *Coordinator, producerID int64, epoch int16) {
+				if errorCode := c.EndTransaction(transactionalID, producerID, epoch, true); errorCode != protocol.ErrorNone {
+					t.Fatalf("EndTransaction failed with error %d", errorCode)
+				}
+			},
+			wantControl: record.ControlCommit,
+			wantState:   CompleteCommit,
+		},
+		{
+			name: "abort",
+			end: func(t *testing.T, c *Coordinator, producerID int64, epoch int16) {
+				if errorCode := c.EndTransaction(transactionalID, producerID, epoch, false); errorCode != protocol.ErrorNone {
+					t.Fatalf("EndTransaction failed with error %d", errorCode)
+				}
+			},
+			wantControl: record.ControlAbort,
+			wantState:   CompleteAbort,
+		},
+		{
+			// A new instance of the producer aborts what the old one left
+			// open, with markers at a bumped epoch that fence it
+			name: "abort by a new producer instance",
+			end: func(t *testing.T, c *Coordinator, producerID int64, epoch int16) {
+				if _, _, errorCode := c.InitProducerID(&transactionalID, 60000, record.NoProducerID, record.NoProducerEpoch); errorCode != protocol.ErrorNone {
+					t.Fatalf("InitProducerID failed with error %d", errorCode)
+				}
+			},
+			wantControl:   record.ControlAbort,
+			wantEpochBump: 1,
+			wantState:     Empty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, logs := newTestCoordinator(t)
+			producerID, epoch, errorCode := c.InitProducerID(&transactionalID, 60000, record.NoProducerID, record.NoProducerEpoch)
+			if errorCode != protocol.ErrorNone {
+				t.Fatalf("InitProducerID failed with error %d", errorCode)
+			}
+			written := storage.TopicPartition{Topic: "events", Partition: 0}
+			unwritten := storage.TopicPartition{Topic: "events", Partition: 1}
+			if errorCode := c.AddPartitions(transactionalID, producerID, epoch, []storage.TopicPartition{written, unwritten}); errorCode != protocol.ErrorNone {
+				t.Fatalf("AddPartitions failed with error %d", errorCode)
+			}
+
+			// The transaction's record is followed by one written outside it
+			log, _ := logs.Get(written.Topic, written.Partition)
+			if _, err := log.Append(transactionalBatch(producerID, epoch, 0).Data); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := log.Append(plainBatch().Data); err != nil {
+				t.Fatal(err)
+			}
+			if got := log.LastStableOffset(); got != 0 {
+				t.Errorf("last stable offset with the transaction open = %d, want 0", got)
+			}
+
+			tt.end(t, c, producerID, epoch)
+
+			// Every partition of the transaction gets a marker at its end,
+			// the one it did not write to included
+			for _, tp := range []storage.TopicPartition{written, unwritten} {
+				partitionLog, _ := logs.Get(tp.Topic, tp.Partition)
+				batches := readBatches(t, partitionLog)
+				marker := batches[len(batches)-1]
+				if !marker.IsControl() || !marker.IsTransactional() {
+					t.Fatalf("%s ends with a batch that is not a transaction marker", tp)
+				}
+				if controlType, err := marker.ControlType(); err != nil || controlType != tt.wantControl {
+					t.Errorf("%s marker type = %d (%v), want %d", tp, controlType, err, tt.wantControl)
+				}
+				if marker.ProducerID != producerID || marker.ProducerEpoch != epoch+tt.wantEpochBump {
+					t.Errorf("%s marker of producer %d at epoch %d, want %d at epoch %d", tp, marker.ProducerID, marker.ProducerEpoch, producerID, epoch+tt.wantEpochBump)
+				}
+			}
+			if got := len(readBatches(t, log)); got != 3 {
+				t.Errorf("%s holds %d batches, want the record, the later record and the marker", written, got)
+			}
+			if got := log.LastStableOffset(); got != 3 {
+				t.Errorf("last stable offset after the marker = %d, want 3", got)
+			}
+
+			_, aborted, err := log.ReadCommitted(0, 1<<20, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantAborted := 0
+			if tt.wantControl == record.ControlAbort {
+				wantAborted = 1
+			}
+			if len(aborted) != wantAborted || (wantAborted == 1 && aborted[0] != storage.AbortedTransaction{ProducerID: producerID, FirstOffset: 0}) {
+				t.Errorf("aborted transactions = %v, want %d from offset 0", aborted, wantAborted)
+			}
+			if got := c.transactions[transactionalID].state; got != tt.wantState {
+				t.Errorf("transaction state = %s, want %s", got, tt.wantState)
+			}
+
+			// Once fenced by a marker, the old instance can no longer write
+			if tt.wantEpochBump > 0 {
+				if _, err := log.Append(transactionalBatch(producerID, epoch, 1).Data); !errors.Is(err, storage.ErrInvalidProducerEpoch) {
+					t.Errorf("append from the fenced instance: error = %v, want %v", err, storage.ErrInvalidProducerEpoch)
+				}
+				return
+			}
+			// Retrying EndTransaction writes no more markers
+			end := log.LogEndOffset()
+			tt.end(t, c, producerID, epoch)
+			if got := log.LogEndOffset(); got != end {
+				t.Errorf("retried EndTransaction moved the log end offset from %d to %d", end, got)
+			}
+		})
+	}
+}
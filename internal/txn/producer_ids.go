@@ -0,0 +1,76 @@
+// Package txn implements the broker side of idempotent and transactional
+// producers: the allocation of producer IDs, and the transaction
+// coordinator, which keeps the state of each transactional ID in the
+// __transaction_state topic and ends transactions with markers
+package txn
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/metadata"
+)
+
+// producerIDBlockSize is how many producer IDs are reserved at a time, as
+// in Kafka
+const producerIDBlockSize = 1000
+
+// ProducerIDManager hands out producer IDs. It reserves them a block at a
+// time by publishing a ProducerIdsRecord to the metadata log, so an ID is
+// never handed out twice, even across restarts; the unused rest of a block
+// is lost on shutdown.
+type ProducerIDManager struct {
+	image    *metadata.Image
+	brokerID int32
+
+	mu sync.Mutex
+	// next is the next ID to hand out and end the end of the current block
+	next, end int64
+}
+
+// NewProducerIDManager creates a manager reserving producer IDs for
+// broker brokerID in image
+func NewProducerIDManager(image *metadata.Image, brokerID int32) *ProducerIDManager {
+	return &ProducerIDManager{image: image, brokerID: brokerID}
+}
+
+// Generate returns a producer ID that has never been handed out
+func (m *ProducerIDManager) Generate() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.next >= m.end {
+		if err := m.reserveBlock(); err != nil {
+			return -1, err
+		}
+	}
+	id := m.next
+	m.next++
+	return id, nil
+}
+
+// reserveBlock reserves the next block of producer IDs
+func (m *ProducerIDManager) reserveBlock() error {
+	start := m.image.NextProducerID()
+	if start > math.MaxInt64-producerIDBlockSize {
+		return fmt.Errorf("producer IDs are exhausted")
+	}
+
+	epoch := int64(-1)
+	for _, b := range m.image.Brokers() {
+		if b.ID == m.brokerID {
+			epoch = b.Epoch
+		}
+	}
+	if err := m.image.Publish(&protocol.ProducerIdsRecord{
+		BrokerId:       m.brokerID,
+		BrokerEpoch:    epoch,
+		NextProducerId: start + producerIDBlockSize,
+	}); err != nil {
+		return fmt.Errorf("failed to reserve producer IDs: %w", err)
+	}
+	m.next, m.end = start, start+producerIDBlockSize
+	return nil
+}
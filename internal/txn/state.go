@@ -0,0 +1,194 @@
+package txn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/protocol"
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+)
+
+// StateTopic is the internal topic that transaction state is written to.
+// It is compacted, so the compactor keeps only the last state of each
+// transactional ID.
+const StateTopic = "__transaction_state"
+
+// Versions of the keys and values of the state topic's records
+const (
+	transactionLogKeyVersion   int16 = 0
+	transactionLogValueVersion int16 = 0
+)
+
+// state is the state of a transactional ID, with Kafka's numbering as
+// written to the state topic
+type state int8
+
+const (
+	// Empty transactional IDs have no transaction open
+	Empty state = iota
+	// Ongoing transactions have partitions added and are writing to them
+	Ongoing
+	// PrepareCommit and PrepareAbort transactions have been ended, and
+	// their markers are being written
+	PrepareCommit
+	PrepareAbort
+	// CompleteCommit and CompleteAbort transactions have their markers
+	// written; the ID may start its next transaction
+	CompleteCommit
+	CompleteAbort
+	// Dead transactional IDs have expired
+	Dead
+	// PrepareEpochFence is only held in memory by Kafka while fencing a
+	// producer; this coordinator fences with PrepareAbort and never writes it
+	PrepareEpochFence
+)
+
+// String returns the state's name as Kafka reports it
+func (s state) String() string {
+	switch s {
+	case Empty:
+		return "Empty"
+	case Ongoing:
+		return "Ongoing"
+	case PrepareCommit:
+		return "PrepareCommit"
+	case PrepareAbort:
+		return "PrepareAbort"
+	case CompleteCommit:
+		return "CompleteCommit"
+	case CompleteAbort:
+		return "CompleteAbort"
+	case Dead:
+		return "Dead"
+	case PrepareEpochFence:
+		return "PrepareEpochFence"
+	}
+	return fmt.Sprintf("state(%d)", int8(s))
+}
+
+// transaction is the state of a transactional ID: the producer ID and
+// epoch it uses and the transaction it has open, if any
+type transaction struct {
+	id         string
+	producerID int64
+	epoch      int16
+	timeout    time.Duration
+	state      state
+	// partitions are the partitions the open transaction has written to
+	partitions map[storage.TopicPartition]bool
+	// startTimestamp is when the open transaction added its first
+	// partition, and lastUpdateTimestamp when the state last changed
+	startTimestamp      int64
+	lastUpdateTimestamp int64
+
+	// timer aborts the open transaction once its timeout has passed
+	timer *time.Timer
+}
+
+// clone returns a copy of the transaction's state to prepare a transition
+// on, leaving the timer with the original
+func (t *transaction) clone() *transaction {
+	next := *t
+	next.partitions = make(map[storage.TopicPartition]bool, len(t.partitions))
+	for tp := range t.partitions {
+		next.partitions[tp] = true
+	}
+	next.timer = nil
+	return &next
+}
+
+// sortedPartitions returns the transaction's partitions ordered by topic
+// and partition
+func (t *transaction) sortedPartitions() []storage.TopicPartition {
+	partitions := make([]storage.TopicPartition, 0, len(t.partitions))
+	for tp := range t.partitions {
+		partitions = append(partitions, tp)
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].Topic != partitions[j].Topic {
+			return partitions[i].Topic < partitions[j].Topic
+		}
+		return partitions[i].Partition < partitions[j].Partition
+	})
+	return partitions
+}
+
+// stateRecord builds the state topic record holding a transaction's state
+func stateRecord(t *transaction) record.Record {
+	key := protocol.NewEncoder(0)
+	key.PutInt16(transactionLogKeyVersion)
+	(&protocol.TransactionLogKey{TransactionalId: t.id}).Encode(key, transactionLogKeyVersion)
+
+	value := &protocol.TransactionLogValue{}
+	value.Default()
+	value.ProducerId = t.producerID
+	value.ProducerEpoch = t.epoch
+	value.TransactionTimeoutMs = int32(t.timeout.Milliseconds())
+	value.TransactionStatus = int8(t.state)
+	value.TransactionPartitions = []protocol.TransactionLogValuePartitionsSchema{}
+	for _, tp := range t.sortedPartitions() {
+		n := len(value.TransactionPartitions)
+		if n == 0 || value.TransactionPartitions[n-1].Topic != tp.Topic {
+			value.TransactionPartitions = append(value.TransactionPartitions, protocol.TransactionLogValuePartitionsSchema{Topic: tp.Topic})
+			n++
+		}
+		value.TransactionPartitions[n-1].PartitionIds = append(value.TransactionPartitions[n-1].PartitionIds, tp.Partition)
+	}
+	value.TransactionLastUpdateTimestampMs = t.lastUpdateTimestamp
+	value.TransactionStartTimestampMs = t.startTimestamp
+
+	e := protocol.NewEncoder(0)
+	e.PutInt16(transactionLogValueVersion)
+	value.Encode(e, transactionLogValueVersion)
+	return record.Record{Key: key.Bytes(), Value: e.Bytes()}
+}
+
+// parseStateRecord decodes a record of the state topic. It returns the
+// transactional ID and its state, which is nil for a tombstone.
+func parseStateRecord(rec record.Record) (string, *transaction, error) {
+	if len(rec.Key) < 2 {
+		return "", nil, fmt.Errorf("key of %d bytes is too short", len(rec.Key))
+	}
+	if version := int16(binary.BigEndian.Uint16(rec.Key)); version != transactionLogKeyVersion {
+		return "", nil, fmt.Errorf("unknown transaction log key version %d", version)
+	}
+	key := &protocol.TransactionLogKey{}
+	if err := key.Decode(protocol.NewDecoder(rec.Key[2:]), transactionLogKeyVersion); err != nil {
+		return "", nil, err
+	}
+	if rec.Value == nil {
+		return key.TransactionalId, nil, nil
+	}
+
+	if len(rec.Value) < 2 {
+		return "", nil, fmt.Errorf("value of %d bytes is too short", len(rec.Value))
+	}
+	value := &protocol.TransactionLogValue{}
+	version := int16(binary.BigEndian.Uint16(rec.Value))
+	if version < value.MinVersion() || version > value.MaxVersion() {
+		return "", nil, fmt.Errorf("unknown transaction log value version %d", version)
+	}
+	if err := value.Decode(protocol.NewDecoder(rec.Value[2:]), version); err != nil {
+		return "", nil, err
+	}
+
+	t := &transaction{
+		id:                  key.TransactionalId,
+		producerID:          value.ProducerId,
+		epoch:               value.ProducerEpoch,
+		timeout:             time.Duration(value.TransactionTimeoutMs) * time.Millisecond,
+		state:               state(value.TransactionStatus),
+		partitions:          make(map[storage.TopicPartition]bool),
+		startTimestamp:      value.TransactionStartTimestampMs,
+		lastUpdateTimestamp: value.TransactionLastUpdateTimestampMs,
+	}
+	for _, topic := range value.TransactionPartitions {
+		for _, p := range topic.PartitionIds {
+			t.partitions[storage.TopicPartition{Topic: topic.Topic, Partition: p}] = true
+		}
+	}
+	return key.TransactionalId, t, nil
+}
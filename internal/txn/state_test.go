@@ -0,0 +1,101 @@
+package txn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/kafka-starter-go/internal/kafka/record"
+	"github.com/codecrafters-io/kafka-starter-go/internal/storage"
+	"github.com/codecrafters-io/kafka-starter-go/pkg/logger"
+)
+
+func TestStateTopicCompaction(t *testing.T) {
+	log := logger.New(logger.ERROR)
+	logs, err := storage.Open(t.TempDir(), storage.DefaultConfig(), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+	// Every state is written to a segment of its own, so that all but the
+	// last are sealed and compacted
+	logs.SetSegmentPolicy(func(string) storage.SegmentPolicy { return storage.SegmentPolicy{SegmentBytes: 1} })
+	stateLog, err := logs.GetOrCreate(StateTopic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := storage.TopicPartition{Topic: "events", Partition: 0}
+	states := []*transaction{
+		{id: "a", producerID: 1, epoch: 0, state: Empty},
+		{id: "b", producerID: 2, epoch: 0, state: Ongoing, partitions: map[storage.TopicPartition]bool{events: true}},
+		{id: "a", producerID: 1, epoch: 1, state: CompleteCommit},
+		{id: "b", producerID: 2, epoch: 0, state: CompleteAbort},
+		{id: "c", producerID: 3, epoch: 0, state: Empty},
+	}
+	for _, s := range states {
+		s.timeout = time.Minute
+		batch := record.EncodeBatch(record.Batch{
+			ProducerID:    record.NoProducerID,
+			ProducerEpoch: record.NoProducerEpoch,
+			BaseSequence:  record.NoSequence,
+		}, []record.Record{stateRecord(s)})
+		if _, err := stateLog.Append(batch.Data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logs.Compact(func(topic string) storage.CompactionPolicy {
+		return storage.CompactionPolicy{Compact: topic == StateTopic}
+	}, time.Now().UnixMilli())
+
+	// Only the last state of each transactional ID is left
+	var offsets []int64
+	for offset := stateLog.LogStartOffset(); offset < stateLog.LogEndOffset(); {
+		data, err := stateLog.Read(offset, 1<<20, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data == nil {
+			break
+		}
+		for len(data) > 0 {
+			b, err := record.NextBatch(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			offsets = append(offsets, b.BaseOffset)
+			data, offset = data[b.Size():], b.NextOffset()
+		}
+	}
+	if len(offsets) != 3 || offsets[0] != 2 || offsets[1] != 3 || offsets[2] != 4 {
+		t.Errorf("state records at offsets %v, want [2 3 4]", offsets)
+	}
+
+	// and it loads the same transactions as the whole log would
+	c, err := Load(Config{StateTopicPartitions: 1, MaxTimeout: time.Hour}, logs, nil, nil, log)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	defer c.Close()
+	want := map[string]struct {
+		epoch int16
+		state state
+	}{
+		"a": {1, CompleteCommit},
+		"b": {0, CompleteAbort},
+		"c": {0, Empty},
+	}
+	if len(c.transactions) != len(want) {
+		t.Errorf("loaded %d transactional IDs, want %d", len(c.transactions), len(want))
+	}
+	for id, w := range want {
+		got := c.transactions[id]
+		if got == nil {
+			t.Errorf("transactional ID %s not loaded", id)
+			continue
+		}
+		if got.epoch != w.epoch || got.state != w.state {
+			t.Errorf("%s loaded at epoch %d in %s, want epoch %d in %s", id, got.epoch, got.state, w.epoch, w.state)
+		}
+	}
+}